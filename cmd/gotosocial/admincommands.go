@@ -20,6 +20,7 @@ package main
 
 import (
 	"github.com/superseriousbusiness/gotosocial/internal/cliactions/admin/account"
+	"github.com/superseriousbusiness/gotosocial/internal/cliactions/admin/media"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/urfave/cli/v2"
 )
@@ -122,6 +123,32 @@ func adminCommands() []*cli.Command {
 						},
 					},
 				},
+				{
+					Name:  "media",
+					Usage: "admin commands related to media",
+					Subcommands: []*cli.Command{
+						{
+							Name:  "prune",
+							Usage: "remove remote media that hasn't been accessed for the configured number of remote cache days from storage",
+							Action: func(c *cli.Context) error {
+								return runAction(c, media.Prune)
+							},
+						},
+						{
+							Name:  "cleanup",
+							Usage: "remove media that has been unattached or orphaned for longer than the configured cleanup grace hours, and files in storage that don't belong to any media",
+							Flags: []cli.Flag{
+								&cli.BoolFlag{
+									Name:  config.DryRunFlag,
+									Usage: config.DryRunUsage,
+								},
+							},
+							Action: func(c *cli.Context) error {
+								return runAction(c, media.Cleanup)
+							},
+						},
+					},
+				},
 			},
 		},
 	}
@@ -27,6 +27,7 @@ func getCommands() []*cli.Command {
 	commandSets := [][]*cli.Command{
 		serverCommands(),
 		adminCommands(),
+		migrationCommands(),
 		testrigCommands(),
 	}
 	for _, cs := range commandSets {
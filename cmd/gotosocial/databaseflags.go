@@ -27,13 +27,13 @@ func databaseFlags(flagNames, envNames config.Flags, defaults config.Defaults) [
 	return []cli.Flag{
 		&cli.StringFlag{
 			Name:    flagNames.DbType,
-			Usage:   "Database type: eg., postgres",
+			Usage:   "Database type: eg., postgres or sqlite",
 			Value:   defaults.DbType,
 			EnvVars: []string{envNames.DbType},
 		},
 		&cli.StringFlag{
 			Name:    flagNames.DbAddress,
-			Usage:   "Database ipv4 address or hostname",
+			Usage:   "Database ipv4 address or hostname, or the path of the db file for sqlite",
 			Value:   defaults.DbAddress,
 			EnvVars: []string{envNames.DbAddress},
 		},
@@ -73,5 +73,11 @@ func databaseFlags(flagNames, envNames config.Flags, defaults config.Defaults) [
 			Value:   defaults.DBTlsCACert,
 			EnvVars: []string{envNames.DbTLSCACert},
 		},
+		&cli.IntFlag{
+			Name:    flagNames.DbQueryTimeout,
+			Usage:   "Maximum amount of time in seconds that a single database query may take before it's cancelled",
+			Value:   defaults.DbQueryTimeout,
+			EnvVars: []string{envNames.DbQueryTimeout},
+		},
 	}
 }
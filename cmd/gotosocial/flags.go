@@ -39,6 +39,8 @@ func getFlags() []cli.Flag {
 		statusesFlags(flagNames, envNames, defaults),
 		letsEncryptFlags(flagNames, envNames, defaults),
 		oidcFlags(flagNames, envNames, defaults),
+		metricsFlags(flagNames, envNames, defaults),
+		tracingFlags(flagNames, envNames, defaults),
 	}
 	for _, fs := range flagSets {
 		flags = append(flags, fs...)
@@ -37,6 +37,12 @@ func mediaFlags(flagNames, envNames config.Flags, defaults config.Defaults) []cl
 			Value:   defaults.MediaMaxVideoSize,
 			EnvVars: []string{envNames.MediaMaxVideoSize},
 		},
+		&cli.IntFlag{
+			Name:    flagNames.MediaMaxImageDimension,
+			Usage:   "Max width or height of stored images in pixels; larger images will be scaled down to fit",
+			Value:   defaults.MediaMaxImageDimension,
+			EnvVars: []string{envNames.MediaMaxImageDimension},
+		},
 		&cli.IntFlag{
 			Name:    flagNames.MediaMinDescriptionChars,
 			Usage:   "Min required chars for an image description",
@@ -49,5 +55,17 @@ func mediaFlags(flagNames, envNames config.Flags, defaults config.Defaults) []cl
 			Value:   defaults.MediaMaxDescriptionChars,
 			EnvVars: []string{envNames.MediaMaxDescriptionChars},
 		},
+		&cli.IntFlag{
+			Name:    flagNames.MediaRemoteCacheDays,
+			Usage:   "Number of days to keep remote media cached since it was last accessed; pruned media will be fetched again when needed",
+			Value:   defaults.MediaRemoteCacheDays,
+			EnvVars: []string{envNames.MediaRemoteCacheDays},
+		},
+		&cli.IntFlag{
+			Name:    flagNames.MediaCleanupGraceHours,
+			Usage:   "Number of hours that media can go unattached to anything before it's cleaned up",
+			Value:   defaults.MediaCleanupGraceHours,
+			EnvVars: []string{envNames.MediaCleanupGraceHours},
+		},
 	}
 }
@@ -0,0 +1,53 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+func metricsFlags(flagNames, envNames config.Flags, defaults config.Defaults) []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    flagNames.MetricsEnabled,
+			Usage:   "Serve prometheus metrics at /metrics.",
+			Value:   defaults.MetricsEnabled,
+			EnvVars: []string{envNames.MetricsEnabled},
+		},
+		&cli.BoolFlag{
+			Name:    flagNames.MetricsAuthEnabled,
+			Usage:   "Require http basic auth to access /metrics. If set to true, then the metrics auth username and password must also be set.",
+			Value:   defaults.MetricsAuthEnabled,
+			EnvVars: []string{envNames.MetricsAuthEnabled},
+		},
+		&cli.StringFlag{
+			Name:    flagNames.MetricsAuthUsername,
+			Usage:   "Username for http basic auth on /metrics.",
+			Value:   defaults.MetricsAuthUsername,
+			EnvVars: []string{envNames.MetricsAuthUsername},
+		},
+		&cli.StringFlag{
+			Name:    flagNames.MetricsAuthPassword,
+			Usage:   "Password for http basic auth on /metrics.",
+			Value:   defaults.MetricsAuthPassword,
+			EnvVars: []string{envNames.MetricsAuthPassword},
+		},
+	}
+}
@@ -55,5 +55,11 @@ func statusesFlags(flagNames, envNames config.Flags, defaults config.Defaults) [
 			Value:   defaults.StatusesMaxMediaFiles,
 			EnvVars: []string{envNames.StatusesMaxMediaFiles},
 		},
+		&cli.IntFlag{
+			Name:    flagNames.StatusesMaxPinned,
+			Usage:   "Maximum number of statuses an account may pin to their profile",
+			Value:   defaults.StatusesMaxPinned,
+			EnvVars: []string{envNames.StatusesMaxPinned},
+		},
 	}
 }
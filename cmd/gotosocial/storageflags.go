@@ -55,5 +55,53 @@ func storageFlags(flagNames, envNames config.Flags, defaults config.Defaults) []
 			Value:   defaults.StorageServeBasePath,
 			EnvVars: []string{envNames.StorageServeBasePath},
 		},
+		&cli.StringFlag{
+			Name:    flagNames.StorageS3Endpoint,
+			Usage:   "Endpoint of the S3-compatible object storage to use with the s3 storage backend, eg., s3.amazonaws.com or localhost:9000",
+			Value:   defaults.StorageS3Endpoint,
+			EnvVars: []string{envNames.StorageS3Endpoint},
+		},
+		&cli.StringFlag{
+			Name:    flagNames.StorageS3Region,
+			Usage:   "Region of the S3 bucket to use with the s3 storage backend (can be left empty for most self-hosted object storage)",
+			Value:   defaults.StorageS3Region,
+			EnvVars: []string{envNames.StorageS3Region},
+		},
+		&cli.StringFlag{
+			Name:    flagNames.StorageS3Bucket,
+			Usage:   "Name of an already-created S3 bucket to store media files in when using the s3 storage backend",
+			Value:   defaults.StorageS3Bucket,
+			EnvVars: []string{envNames.StorageS3Bucket},
+		},
+		&cli.StringFlag{
+			Name:    flagNames.StorageS3AccessKey,
+			Usage:   "Access key to use for the s3 storage backend",
+			Value:   defaults.StorageS3AccessKey,
+			EnvVars: []string{envNames.StorageS3AccessKey},
+		},
+		&cli.StringFlag{
+			Name:    flagNames.StorageS3SecretKey,
+			Usage:   "Secret key to use for the s3 storage backend",
+			Value:   defaults.StorageS3SecretKey,
+			EnvVars: []string{envNames.StorageS3SecretKey},
+		},
+		&cli.BoolFlag{
+			Name:    flagNames.StorageS3Insecure,
+			Usage:   "Connect to the S3 endpoint using plain http rather than https",
+			Value:   defaults.StorageS3Insecure,
+			EnvVars: []string{envNames.StorageS3Insecure},
+		},
+		&cli.BoolFlag{
+			Name:    flagNames.StorageS3PathStyle,
+			Usage:   "Use path-style S3 bucket urls (endpoint/bucket/key) rather than virtual-host-style urls (bucket.endpoint/key)",
+			Value:   defaults.StorageS3PathStyle,
+			EnvVars: []string{envNames.StorageS3PathStyle},
+		},
+		&cli.StringFlag{
+			Name:    flagNames.StorageS3ServeMode,
+			Usage:   "How to serve media stored in S3: proxy (through the gotosocial fileserver), presigned (presigned S3 urls) or public (public S3 urls)",
+			Value:   defaults.StorageS3ServeMode,
+			EnvVars: []string{envNames.StorageS3ServeMode},
+		},
 	}
 }
@@ -0,0 +1,59 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+func tracingFlags(flagNames, envNames config.Flags, defaults config.Defaults) []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    flagNames.TracingEnabled,
+			Usage:   "Enable OpenTelemetry tracing of http requests, processing, federation and database calls.",
+			Value:   defaults.TracingEnabled,
+			EnvVars: []string{envNames.TracingEnabled},
+		},
+		&cli.StringFlag{
+			Name:    flagNames.TracingExporter,
+			Usage:   "Where to export traces to: 'otlp' to send them to an OTLP collector over http, or 'file' to write them to a local file.",
+			Value:   defaults.TracingExporter,
+			EnvVars: []string{envNames.TracingExporter},
+		},
+		&cli.StringFlag{
+			Name:    flagNames.TracingEndpoint,
+			Usage:   "Host and port of the OTLP collector to send traces to, eg., 'localhost:4318'. Only used if the exporter is 'otlp'.",
+			Value:   defaults.TracingEndpoint,
+			EnvVars: []string{envNames.TracingEndpoint},
+		},
+		&cli.BoolFlag{
+			Name:    flagNames.TracingInsecure,
+			Usage:   "Send traces to the OTLP collector over plain http instead of https. Only used if the exporter is 'otlp'.",
+			Value:   defaults.TracingInsecure,
+			EnvVars: []string{envNames.TracingInsecure},
+		},
+		&cli.StringFlag{
+			Name:    flagNames.TracingFilePath,
+			Usage:   "Path of the file to write traces to. Only used if the exporter is 'file'.",
+			Value:   defaults.TracingFilePath,
+			EnvVars: []string{envNames.TracingFilePath},
+		},
+	}
+}
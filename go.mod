@@ -5,6 +5,44 @@ go 1.16
 require (
 	github.com/buckket/go-blurhash v1.1.0
 	github.com/coreos/go-oidc/v3 v3.0.0
+	github.com/gin-contrib/cors v1.3.1
+	github.com/gin-contrib/sessions v0.0.3
+	github.com/gin-gonic/gin v1.7.4
+	github.com/go-fed/activity v1.0.1-0.20210426194615-e0de0863dcc1
+	github.com/go-fed/httpsig v1.1.0
+	github.com/go-pg/pg/extra/pgdebug v0.2.0
+	github.com/go-pg/pg/v10 v10.9.3
+	github.com/google/uuid v1.2.0
+	github.com/gorilla/websocket v1.4.2
+	github.com/h2non/filetype v1.1.1
+	github.com/johannesboyne/gofakes3 v0.0.0-20210608054100-92d5d4af5fde
+	github.com/microcosm-cc/bluemonday v1.0.15
+	github.com/minio/minio-go/v7 v7.0.12
+	github.com/nfnt/resize v0.0.0-20180221191011-83c6a9932646
+	github.com/oklog/ulid v1.3.1
+	github.com/prometheus/client_golang v1.11.0
+	github.com/sirupsen/logrus v1.8.1
+	github.com/stretchr/testify v1.7.0
+	github.com/superseriousbusiness/exifremove v0.0.0-20210330092427-6acd27eac203
+	github.com/superseriousbusiness/oauth2/v4 v4.3.0-SSB
+	github.com/urfave/cli/v2 v2.3.0
+	github.com/wagslane/go-password-validator v0.3.0
+	go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin v0.24.0
+	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.24.0
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.0.0
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.0.0
+	go.opentelemetry.io/otel/sdk v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
+	golang.org/x/crypto v0.0.0-20210513164829-c07d793c2f9a
+	golang.org/x/image v0.0.0-20211028202545-6944b10bf410
+	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
+	golang.org/x/text v0.3.6
+	gopkg.in/yaml.v2 v2.4.0
+	modernc.org/sqlite v1.11.2
+)
+
+require (
 	github.com/cpuguy83/go-md2man/v2 v2.0.0 // indirect
 	github.com/dsoprea/go-exif v0.0.0-20210512055020-8213cfabc61b // indirect
 	github.com/dsoprea/go-exif/v2 v2.0.0-20210512055020-8213cfabc61b // indirect
@@ -14,47 +52,20 @@ require (
 	github.com/dsoprea/go-photoshop-info-format v0.0.0-20200610045659-121dd752914d // indirect
 	github.com/dsoprea/go-png-image-structure v0.0.0-20210512210324-29b889a6093d // indirect
 	github.com/dsoprea/go-utility v0.0.0-20200717064901-2fccff4aa15e // indirect
-	github.com/gin-contrib/cors v1.3.1
-	github.com/gin-contrib/sessions v0.0.3
-	github.com/gin-gonic/gin v1.7.2
 	github.com/go-errors/errors v1.4.0 // indirect
-	github.com/go-fed/activity v1.0.1-0.20210426194615-e0de0863dcc1
-	github.com/go-fed/httpsig v1.1.0
-	github.com/go-pg/pg/extra/pgdebug v0.2.0
-	github.com/go-pg/pg/v10 v10.9.3
 	github.com/go-playground/validator/v10 v10.6.1 // indirect
 	github.com/golang/geo v0.0.0-20210211234256-740aa86cb551 // indirect
 	github.com/golang/mock v1.5.0 // indirect
-	github.com/google/uuid v1.2.0
 	github.com/gorilla/sessions v1.2.1 // indirect
-	github.com/gorilla/websocket v1.4.2
-	github.com/h2non/filetype v1.1.1
-	github.com/json-iterator/go v1.1.11 // indirect
 	github.com/leodido/go-urn v1.2.1 // indirect
 	github.com/mattn/go-isatty v0.0.13 // indirect
-	github.com/microcosm-cc/bluemonday v1.0.15
-	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
-	github.com/modern-go/reflect2 v1.0.1 // indirect
-	github.com/nfnt/resize v0.0.0-20180221191011-83c6a9932646
-	github.com/oklog/ulid v1.3.1
 	github.com/onsi/gomega v1.13.0 // indirect
 	github.com/quasoft/memstore v0.0.0-20191010062613-2bce066d2b0b // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
-	github.com/sirupsen/logrus v1.8.1
-	github.com/stretchr/testify v1.7.0
-	github.com/superseriousbusiness/exifremove v0.0.0-20210330092427-6acd27eac203
-	github.com/superseriousbusiness/oauth2/v4 v4.3.0-SSB
 	github.com/tidwall/btree v0.5.0 // indirect
 	github.com/tidwall/buntdb v1.2.3 // indirect
 	github.com/ugorji/go v1.2.6 // indirect
-	github.com/urfave/cli/v2 v2.3.0
 	github.com/vmihailenco/msgpack/v5 v5.3.4 // indirect
-	github.com/wagslane/go-password-validator v0.3.0
-	golang.org/x/crypto v0.0.0-20210513164829-c07d793c2f9a
-	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
-	golang.org/x/sys v0.0.0-20210525143221-35b2ab0089ea // indirect
-	golang.org/x/text v0.3.6
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
-	gopkg.in/yaml.v2 v2.4.0
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
 )
@@ -69,6 +69,12 @@ const (
 	BlockPath = BasePathWithID + "/block"
 	// UnblockPath is for removing a block of an account
 	UnblockPath = BasePathWithID + "/unblock"
+	// MutePath is for creating or updating a mute of an account
+	MutePath = BasePathWithID + "/mute"
+	// UnmutePath is for removing a mute of an account
+	UnmutePath = BasePathWithID + "/unmute"
+	// GetListsPath is for showing the lists of the authed account that contain an account
+	GetListsPath = BasePathWithID + "/lists"
 )
 
 // Module implements the ClientAPIModule interface for account-related actions
@@ -116,6 +122,13 @@ func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodPost, BlockPath, m.AccountBlockPOSTHandler)
 	r.AttachHandler(http.MethodPost, UnblockPath, m.AccountUnblockPOSTHandler)
 
+	// mute or unmute account
+	r.AttachHandler(http.MethodPost, MutePath, m.AccountMutePOSTHandler)
+	r.AttachHandler(http.MethodPost, UnmutePath, m.AccountUnmutePOSTHandler)
+
+	// get lists containing account
+	r.AttachHandler(http.MethodGet, GetListsPath, m.AccountListsGETHandler)
+
 	return nil
 }
 
@@ -37,13 +37,43 @@ const (
 	DomainBlocksPath = BasePath + "/domain_blocks"
 	// DomainBlocksPathWithID is used for interacting with a single domain block.
 	DomainBlocksPathWithID = DomainBlocksPath + "/:" + IDKey
+	// DeliveriesPath is used for viewing outgoing deliveries that are waiting to be retried or that have failed.
+	DeliveriesPath = BasePath + "/deliveries"
+	// ReportsPath is used for viewing reports.
+	ReportsPath = BasePath + "/reports"
+	// ReportsPathWithID is used for viewing a single report.
+	ReportsPathWithID = ReportsPath + "/:" + IDKey
+	// ReportAssignPath is used for assigning a report to the requesting admin.
+	ReportAssignPath = ReportsPathWithID + "/assign_to_self"
+	// ReportUnassignPath is used for removing the admin assigned to a report.
+	ReportUnassignPath = ReportsPathWithID + "/unassign"
+	// ReportNotePath is used for setting the moderator notes on a report.
+	ReportNotePath = ReportsPathWithID + "/note"
+	// ReportResolvePath is used for resolving a report, optionally taking action against the reported account.
+	ReportResolvePath = ReportsPathWithID + "/resolve"
+	// ReportReopenPath is used for marking a resolved report as unresolved again.
+	ReportReopenPath = ReportsPathWithID + "/reopen"
 
 	// ExportQueryKey is for requesting a public export of some data.
 	ExportQueryKey = "export"
+	// DomainQueryKey is for filtering results by domain.
+	DomainQueryKey = "domain"
 	// ImportQueryKey is for submitting an import of some data.
 	ImportQueryKey = "import"
 	// IDKey specifies the ID of a single item being interacted with.
 	IDKey = "id"
+	// ResolvedKey is for choosing between resolved and unresolved reports.
+	ResolvedKey = "resolved"
+	// AccountIDKey is for filtering reports by the account that made them.
+	AccountIDKey = "account_id"
+	// TargetAccountIDKey is for filtering reports by the account that was reported.
+	TargetAccountIDKey = "target_account_id"
+	// MaxIDKey is for returning results older than the given ID.
+	MaxIDKey = "max_id"
+	// SinceIDKey is for returning results newer than the given ID.
+	SinceIDKey = "since_id"
+	// LimitKey is for specifying maximum number of results to return.
+	LimitKey = "limit"
 )
 
 // Module implements the ClientAPIModule interface for admin-related actions (reports, emojis, etc)
@@ -69,5 +99,13 @@ func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodGet, DomainBlocksPath, m.DomainBlocksGETHandler)
 	r.AttachHandler(http.MethodGet, DomainBlocksPathWithID, m.DomainBlockGETHandler)
 	r.AttachHandler(http.MethodDelete, DomainBlocksPathWithID, m.DomainBlockDELETEHandler)
+	r.AttachHandler(http.MethodGet, DeliveriesPath, m.DeliveriesGETHandler)
+	r.AttachHandler(http.MethodGet, ReportsPath, m.ReportsGETHandler)
+	r.AttachHandler(http.MethodGet, ReportsPathWithID, m.ReportGETHandler)
+	r.AttachHandler(http.MethodPost, ReportAssignPath, m.ReportAssignPOSTHandler)
+	r.AttachHandler(http.MethodPost, ReportUnassignPath, m.ReportUnassignPOSTHandler)
+	r.AttachHandler(http.MethodPost, ReportNotePath, m.ReportNotePOSTHandler)
+	r.AttachHandler(http.MethodPost, ReportResolvePath, m.ReportResolvePOSTHandler)
+	r.AttachHandler(http.MethodPost, ReportReopenPath, m.ReportReopenPOSTHandler)
 	return nil
 }
@@ -61,7 +61,7 @@ func (m *Module) BlocksGETHandler(c *gin.Context) {
 		limit = int(i)
 	}
 
-	resp, errWithCode := m.processor.BlocksGet(authed, maxID, sinceID, limit)
+	resp, errWithCode := m.processor.BlocksGet(c.Request.Context(), authed, maxID, sinceID, limit)
 	if errWithCode != nil {
 		l.Debugf("error from processor BlocksGet: %s", errWithCode)
 		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
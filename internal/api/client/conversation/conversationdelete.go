@@ -0,0 +1,34 @@
+package conversation
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ConversationDELETEHandler removes the conversation with the given ID from the authed account's conversations.
+func (m *Module) ConversationDELETEHandler(c *gin.Context) {
+	l := m.log.WithField("func", "ConversationDELETEHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	conversationID := c.Param(IDKey)
+	if conversationID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no conversation id specified"})
+		return
+	}
+
+	if errWithCode := m.processor.ConversationDelete(c.Request.Context(), authed, conversationID); errWithCode != nil {
+		l.Debugf("error from processor ConversationDelete: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
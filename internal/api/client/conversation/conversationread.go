@@ -0,0 +1,35 @@
+package conversation
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ConversationReadPOSTHandler marks the conversation with the given ID as read.
+func (m *Module) ConversationReadPOSTHandler(c *gin.Context) {
+	l := m.log.WithField("func", "ConversationReadPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	conversationID := c.Param(IDKey)
+	if conversationID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no conversation id specified"})
+		return
+	}
+
+	conversation, errWithCode := m.processor.ConversationRead(c.Request.Context(), authed, conversationID)
+	if errWithCode != nil {
+		l.Debugf("error from processor ConversationRead: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, conversation)
+}
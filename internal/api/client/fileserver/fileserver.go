@@ -19,6 +19,7 @@
 package fileserver
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -68,13 +69,13 @@ func (m *FileServer) Route(s router.Router) error {
 }
 
 // CreateTables populates necessary tables in the given DB
-func (m *FileServer) CreateTables(db db.DB) error {
+func (m *FileServer) CreateTables(ctx context.Context, db db.DB) error {
 	models := []interface{}{
 		&gtsmodel.MediaAttachment{},
 	}
 
 	for _, m := range models {
-		if err := db.CreateTable(m); err != nil {
+		if err := db.CreateTable(ctx, m); err != nil {
 			return fmt.Errorf("error creating table: %s", err)
 		}
 	}
@@ -19,7 +19,6 @@
 package fileserver
 
 import (
-	"bytes"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -78,7 +77,7 @@ func (m *FileServer) ServeFile(c *gin.Context) {
 		return
 	}
 
-	content, err := m.processor.FileGet(authed, &model.GetContentRequestForm{
+	content, err := m.processor.FileGet(c.Request.Context(), authed, &model.GetContentRequestForm{
 		AccountID: accountID,
 		MediaType: mediaType,
 		MediaSize: mediaSize,
@@ -89,6 +88,7 @@ func (m *FileServer) ServeFile(c *gin.Context) {
 		c.String(http.StatusNotFound, "404 page not found")
 		return
 	}
+	defer content.Content.Close()
 
 	// TODO: do proper content negotiation here -- if the requester only accepts text/html we should try to serve them *something*
 	// This is mostly needed because when sharing a link to a gts-hosted file on something like mastodon, the masto servers will
@@ -99,5 +99,9 @@ func (m *FileServer) ServeFile(c *gin.Context) {
 		return
 	}
 
-	c.DataFromReader(http.StatusOK, content.ContentLength, content.ContentType, bytes.NewReader(content.Content), nil)
+	// ServeContent takes care of Range requests, and of If-None-Match using the ETag we set here,
+	// and streams the content to the requester rather than loading all of it into memory
+	c.Header("Content-Type", content.ContentType)
+	c.Header("ETag", content.ETag)
+	http.ServeContent(c.Writer, c.Request, fileName, content.ContentUpdated, content.Content)
 }
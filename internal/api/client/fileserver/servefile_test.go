@@ -158,6 +158,78 @@ func (suite *ServeFileTestSuite) TestServeOriginalFileSuccessful() {
 	assert.Equal(suite.T(), b, fileInStorage)
 }
 
+func (suite *ServeFileTestSuite) serveOriginal(targetAttachment *gtsmodel.MediaAttachment, header http.Header) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, targetAttachment.URL, nil)
+	for k, v := range header {
+		ctx.Request.Header[k] = v
+	}
+
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   fileserver.AccountIDKey,
+			Value: targetAttachment.AccountID,
+		},
+		gin.Param{
+			Key:   fileserver.MediaTypeKey,
+			Value: string(media.Attachment),
+		},
+		gin.Param{
+			Key:   fileserver.MediaSizeKey,
+			Value: string(media.Original),
+		},
+		gin.Param{
+			Key:   fileserver.FileNameKey,
+			Value: fmt.Sprintf("%s.jpeg", targetAttachment.ID),
+		},
+	}
+
+	suite.fileServer.ServeFile(ctx)
+
+	// gin only writes the status code once something is written to the body, or when the router is
+	// done with the request, so for a bodiless response like a 304 we have to flush it ourselves
+	ctx.Writer.WriteHeaderNow()
+	return recorder
+}
+
+func (suite *ServeFileTestSuite) TestServeOriginalFileRange() {
+	targetAttachment, ok := suite.testAttachments["admin_account_status_1_attachment_1"]
+	suite.True(ok)
+
+	fileInStorage, err := suite.storage.RetrieveFileFrom(targetAttachment.File.Path)
+	suite.NoError(err)
+
+	recorder := suite.serveOriginal(targetAttachment, http.Header{"Range": []string{"bytes=10-19"}})
+	suite.EqualValues(http.StatusPartialContent, recorder.Code)
+	suite.Equal(fmt.Sprintf("bytes 10-19/%d", len(fileInStorage)), recorder.Header().Get("Content-Range"))
+	suite.Equal("10", recorder.Header().Get("Content-Length"))
+	suite.Equal("image/jpeg", recorder.Header().Get("Content-Type"))
+	suite.Equal(fileInStorage[10:20], recorder.Body.Bytes())
+}
+
+func (suite *ServeFileTestSuite) TestServeOriginalFileIfNoneMatch() {
+	targetAttachment, ok := suite.testAttachments["admin_account_status_1_attachment_1"]
+	suite.True(ok)
+
+	// the first request should get the whole file, with an etag
+	recorder := suite.serveOriginal(targetAttachment, nil)
+	suite.EqualValues(http.StatusOK, recorder.Code)
+	etag := recorder.Header().Get("ETag")
+	suite.NotEmpty(etag)
+	suite.Equal("bytes", recorder.Header().Get("Accept-Ranges"))
+
+	// a request with that etag should just be told nothing has changed
+	recorder = suite.serveOriginal(targetAttachment, http.Header{"If-None-Match": []string{etag}})
+	suite.EqualValues(http.StatusNotModified, recorder.Code)
+	suite.Empty(recorder.Body.Bytes())
+
+	// a request with some other etag should get the whole file again
+	recorder = suite.serveOriginal(targetAttachment, http.Header{"If-None-Match": []string{`"something-else"`}})
+	suite.EqualValues(http.StatusOK, recorder.Code)
+	suite.NotEmpty(recorder.Body.Bytes())
+}
+
 func TestServeFileTestSuite(t *testing.T) {
 	suite.Run(t, new(ServeFileTestSuite))
 }
@@ -4,9 +4,26 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
-// FiltersGETHandler returns a list of filters set by/for the authed account
+// FiltersGETHandler returns the keyword filters of the authed account.
 func (m *Module) FiltersGETHandler(c *gin.Context) {
-	c.JSON(http.StatusOK, []string{})
+	l := m.log.WithField("func", "FiltersGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	filters, errWithCode := m.processor.FiltersGet(c.Request.Context(), authed)
+	if errWithCode != nil {
+		l.Debugf("error from processor FiltersGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, filters)
 }
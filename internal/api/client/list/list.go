@@ -31,6 +31,18 @@ import (
 const (
 	// BasePath is the base path for serving the lists API
 	BasePath = "/api/v1/lists"
+	// IDKey is the key to use for retrieving the list ID in requests
+	IDKey = "id"
+	// BasePathWithID is the base path for this module with the ID key
+	BasePathWithID = BasePath + "/:" + IDKey
+	// AccountsPath is for viewing and changing the accounts in a list
+	AccountsPath = BasePathWithID + "/accounts"
+	// MaxIDKey is the url query for setting a max list entry ID to return
+	MaxIDKey = "max_id"
+	// SinceIDKey is the url query for returning results newer than the given ID
+	SinceIDKey = "since_id"
+	// LimitKey is for specifying maximum number of results to return.
+	LimitKey = "limit"
 )
 
 // Module implements the ClientAPIModule interface for everything related to lists
@@ -51,6 +63,18 @@ func New(config *config.Config, processor processing.Processor, log *logrus.Logg
 
 // Route attaches all routes from this module to the given router
 func (m *Module) Route(r router.Router) error {
+	// get or create lists
 	r.AttachHandler(http.MethodGet, BasePath, m.ListsGETHandler)
+	r.AttachHandler(http.MethodPost, BasePath, m.ListCreatePOSTHandler)
+
+	// get, update or delete a single list
+	r.AttachHandler(http.MethodGet, BasePathWithID, m.ListGETHandler)
+	r.AttachHandler(http.MethodPut, BasePathWithID, m.ListUpdatePUTHandler)
+	r.AttachHandler(http.MethodDelete, BasePathWithID, m.ListDELETEHandler)
+
+	// view or change the accounts in a list
+	r.AttachHandler(http.MethodGet, AccountsPath, m.ListAccountsGETHandler)
+	r.AttachHandler(http.MethodPost, AccountsPath, m.ListAccountsPOSTHandler)
+	r.AttachHandler(http.MethodDelete, AccountsPath, m.ListAccountsDELETEHandler)
 	return nil
 }
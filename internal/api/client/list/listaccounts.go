@@ -0,0 +1,128 @@
+package list
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ListAccountsGETHandler returns the accounts that are in the list with the given ID.
+//
+// Several different filters might be passed into this function in the query:
+//
+//	max_id -- the maximum ID of the list entry to show
+//	since_id -- Return results newer than id
+//	limit -- show only limit number of accounts, or all of them if limit is 0
+func (m *Module) ListAccountsGETHandler(c *gin.Context) {
+	l := m.log.WithField("func", "ListAccountsGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	listID := c.Param(IDKey)
+	if listID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no list id specified"})
+		return
+	}
+
+	maxID := c.Query(MaxIDKey)
+	sinceID := c.Query(SinceIDKey)
+
+	limit := 40
+	limitString := c.Query(LimitKey)
+	if limitString != "" {
+		i, err := strconv.ParseInt(limitString, 10, 64)
+		if err != nil {
+			l.Debugf("error parsing limit string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse limit query param"})
+			return
+		}
+		limit = int(i)
+	}
+
+	resp, errWithCode := m.processor.ListAccountsGet(c.Request.Context(), authed, listID, maxID, sinceID, limit)
+	if errWithCode != nil {
+		l.Debugf("error from processor ListAccountsGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	if resp.LinkHeader != "" {
+		c.Header("Link", resp.LinkHeader)
+	}
+	c.JSON(http.StatusOK, resp.Accounts)
+}
+
+// ListAccountsPOSTHandler adds the given accounts to the list with the given ID. The authed account must follow all of them.
+func (m *Module) ListAccountsPOSTHandler(c *gin.Context) {
+	l := m.log.WithField("func", "ListAccountsPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	listID := c.Param(IDKey)
+	if listID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no list id specified"})
+		return
+	}
+
+	form := &model.ListAccountsChangeRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("error parsing form %+v: %s", c.Request.Form, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("could not parse form: %s", err)})
+		return
+	}
+
+	if _, errWithCode := m.processor.ListAccountsAdd(c.Request.Context(), authed, listID, form); errWithCode != nil {
+		l.Debugf("error from processor ListAccountsAdd: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// ListAccountsDELETEHandler removes the given accounts from the list with the given ID.
+func (m *Module) ListAccountsDELETEHandler(c *gin.Context) {
+	l := m.log.WithField("func", "ListAccountsDELETEHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	listID := c.Param(IDKey)
+	if listID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no list id specified"})
+		return
+	}
+
+	form := &model.ListAccountsChangeRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("error parsing form %+v: %s", c.Request.Form, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("could not parse form: %s", err)})
+		return
+	}
+
+	if _, errWithCode := m.processor.ListAccountsRemove(c.Request.Context(), authed, listID, form); errWithCode != nil {
+		l.Debugf("error from processor ListAccountsRemove: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,125 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package list_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/list"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type ListAccountsTestSuite struct {
+	ListStandardTestSuite
+}
+
+func (suite *ListAccountsTestSuite) accountsRequest(method string, listID string, accountIDs ...string) *httptest.ResponseRecorder {
+	form := url.Values{"account_ids[]": accountIDs}
+
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauth.TokenToOauthToken(suite.testTokens["local_account_1"]))
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+	path := strings.Replace(list.AccountsPath, ":id", listID, 1)
+	if method == http.MethodPost {
+		ctx.Request = httptest.NewRequest(method, fmt.Sprintf("http://localhost:8080%s", path), strings.NewReader(form.Encode()))
+		ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		ctx.Request = httptest.NewRequest(method, fmt.Sprintf("http://localhost:8080%s?%s", path, form.Encode()), nil)
+	}
+	ctx.Params = gin.Params{gin.Param{Key: list.IDKey, Value: listID}}
+
+	switch method {
+	case http.MethodGet:
+		suite.listModule.ListAccountsGETHandler(ctx)
+	case http.MethodPost:
+		suite.listModule.ListAccountsPOSTHandler(ctx)
+	case http.MethodDelete:
+		suite.listModule.ListAccountsDELETEHandler(ctx)
+	}
+	return recorder
+}
+
+func (suite *ListAccountsTestSuite) listAccountIDs(listID string) []string {
+	recorder := suite.accountsRequest(http.MethodGet, listID)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	accounts := []*model.Account{}
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &accounts))
+
+	ids := []string{}
+	for _, a := range accounts {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func (suite *ListAccountsTestSuite) TestGetListAccounts() {
+	testList := suite.testLists["local_account_1_list_1"]
+	suite.Equal([]string{suite.testAccounts["local_account_2"].ID}, suite.listAccountIDs(testList.ID))
+}
+
+func (suite *ListAccountsTestSuite) TestAddAndRemoveFollowedAccount() {
+	testList := suite.testLists["local_account_1_list_1"]
+	adminAccount := suite.testAccounts["admin_account"]
+
+	// local_account_1 follows admin_account, so it can be added to the list
+	recorder := suite.accountsRequest(http.MethodPost, testList.ID, adminAccount.ID)
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Len(suite.listAccountIDs(testList.ID), 2)
+
+	// adding it again is a no-op
+	recorder = suite.accountsRequest(http.MethodPost, testList.ID, adminAccount.ID)
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Len(suite.listAccountIDs(testList.ID), 2)
+
+	recorder = suite.accountsRequest(http.MethodDelete, testList.ID, adminAccount.ID)
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Equal([]string{suite.testAccounts["local_account_2"].ID}, suite.listAccountIDs(testList.ID))
+}
+
+func (suite *ListAccountsTestSuite) TestAddNotFollowedAccount() {
+	testList := suite.testLists["local_account_1_list_1"]
+
+	// local_account_1 doesn't follow remote_account_1, so it can't be added to the list
+	recorder := suite.accountsRequest(http.MethodPost, testList.ID, suite.testAccounts["remote_account_1"].ID)
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+	suite.Len(suite.listAccountIDs(testList.ID), 1)
+}
+
+func (suite *ListAccountsTestSuite) TestListNotFound() {
+	// there is no list with this ID
+	recorder := suite.accountsRequest(http.MethodGet, "01FF4AFVJYVGP2R0H3WHAZMGPZ")
+	suite.Equal(http.StatusNotFound, recorder.Code)
+}
+
+func TestListAccountsTestSuite(t *testing.T) {
+	suite.Run(t, new(ListAccountsTestSuite))
+}
@@ -0,0 +1,109 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package list_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/list"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type ListCreateTestSuite struct {
+	ListStandardTestSuite
+}
+
+func (suite *ListCreateTestSuite) newContext(recorder *httptest.ResponseRecorder, method string, path string, form url.Values) *gin.Context {
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauth.TokenToOauthToken(suite.testTokens["local_account_1"]))
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+	ctx.Request = httptest.NewRequest(method, fmt.Sprintf("http://localhost:8080%s", path), strings.NewReader(form.Encode()))
+	ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return ctx
+}
+
+func (suite *ListCreateTestSuite) TestCreateList() {
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodPost, list.BasePath, url.Values{
+		"title":          []string{"some friends"},
+		"replies_policy": []string{"none"},
+	})
+
+	suite.listModule.ListCreatePOSTHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	apiList := &model.List{}
+	suite.NoError(json.Unmarshal(b, apiList))
+	suite.NotEmpty(apiList.ID)
+	suite.Equal("some friends", apiList.Title)
+	suite.Equal("none", apiList.RepliesPolicy)
+}
+
+func (suite *ListCreateTestSuite) TestCreateListBadRepliesPolicy() {
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodPost, list.BasePath, url.Values{
+		"title":          []string{"some friends"},
+		"replies_policy": []string{"everyone"},
+	})
+
+	suite.listModule.ListCreatePOSTHandler(ctx)
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *ListCreateTestSuite) TestGetListsAndList() {
+	testList := suite.testLists["local_account_1_list_1"]
+
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodGet, list.BasePath, nil)
+	suite.listModule.ListsGETHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	apiLists := []*model.List{}
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &apiLists))
+	suite.Len(apiLists, 1)
+	suite.Equal(testList.ID, apiLists[0].ID)
+	suite.Equal(testList.Title, apiLists[0].Title)
+
+	recorder = httptest.NewRecorder()
+	ctx = suite.newContext(recorder, http.MethodGet, strings.Replace(list.BasePathWithID, ":id", testList.ID, 1), nil)
+	ctx.Params = gin.Params{gin.Param{Key: list.IDKey, Value: testList.ID}}
+	suite.listModule.ListGETHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+}
+
+func TestListCreateTestSuite(t *testing.T) {
+	suite.Run(t, new(ListCreateTestSuite))
+}
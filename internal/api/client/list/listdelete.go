@@ -0,0 +1,35 @@
+package list
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ListDELETEHandler deletes the list with the given ID, along with all its entries.
+func (m *Module) ListDELETEHandler(c *gin.Context) {
+	l := m.log.WithField("func", "ListDELETEHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	listID := c.Param(IDKey)
+	if listID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no list id specified"})
+		return
+	}
+
+	list, errWithCode := m.processor.ListDelete(c.Request.Context(), authed, listID)
+	if errWithCode != nil {
+		l.Debugf("error from processor ListDelete: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
@@ -0,0 +1,35 @@
+package list
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ListGETHandler returns the list with the given ID, if it belongs to the authed account.
+func (m *Module) ListGETHandler(c *gin.Context) {
+	l := m.log.WithField("func", "ListGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	listID := c.Param(IDKey)
+	if listID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no list id specified"})
+		return
+	}
+
+	list, errWithCode := m.processor.ListGet(c.Request.Context(), authed, listID)
+	if errWithCode != nil {
+		l.Debugf("error from processor ListGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
@@ -19,6 +19,7 @@
 package media
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -65,13 +66,13 @@ func (m *Module) Route(s router.Router) error {
 }
 
 // CreateTables populates necessary tables in the given DB
-func (m *Module) CreateTables(db db.DB) error {
+func (m *Module) CreateTables(ctx context.Context, db db.DB) error {
 	models := []interface{}{
 		&gtsmodel.MediaAttachment{},
 	}
 
 	for _, m := range models {
-		if err := db.CreateTable(m); err != nil {
+		if err := db.CreateTable(ctx, m); err != nil {
 			return fmt.Errorf("error creating table: %s", err)
 		}
 	}
@@ -0,0 +1,35 @@
+package poll
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// PollGETHandler returns the poll with the given ID, if the status it's attached to is visible to the authed account.
+func (m *Module) PollGETHandler(c *gin.Context) {
+	l := m.log.WithField("func", "PollGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	pollID := c.Param(IDKey)
+	if pollID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no poll id specified"})
+		return
+	}
+
+	poll, errWithCode := m.processor.PollGet(c.Request.Context(), authed, pollID)
+	if errWithCode != nil {
+		l.Debugf("error from processor PollGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, poll)
+}
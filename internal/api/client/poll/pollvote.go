@@ -0,0 +1,44 @@
+package poll
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// PollVotePOSTHandler casts the authed account's vote in the poll with the given ID, using the given form.
+func (m *Module) PollVotePOSTHandler(c *gin.Context) {
+	l := m.log.WithField("func", "PollVotePOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	pollID := c.Param(IDKey)
+	if pollID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no poll id specified"})
+		return
+	}
+
+	form := &model.PollVoteRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("error parsing form %+v: %s", c.Request.Form, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("could not parse form: %s", err)})
+		return
+	}
+
+	poll, errWithCode := m.processor.PollVote(c.Request.Context(), authed, pollID, form.Choices)
+	if errWithCode != nil {
+		l.Debugf("error from processor PollVote: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, poll)
+}
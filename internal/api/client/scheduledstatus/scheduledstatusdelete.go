@@ -0,0 +1,34 @@
+package scheduledstatus
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ScheduledStatusDELETEHandler cancels the scheduled status with the given ID.
+func (m *Module) ScheduledStatusDELETEHandler(c *gin.Context) {
+	l := m.log.WithField("func", "ScheduledStatusDELETEHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	scheduledStatusID := c.Param(IDKey)
+	if scheduledStatusID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no scheduled status id specified"})
+		return
+	}
+
+	if errWithCode := m.processor.ScheduledStatusDelete(c.Request.Context(), authed, scheduledStatusID); errWithCode != nil {
+		l.Debugf("error from processor ScheduledStatusDelete: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
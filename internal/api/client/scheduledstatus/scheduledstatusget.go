@@ -0,0 +1,35 @@
+package scheduledstatus
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ScheduledStatusGETHandler returns the scheduled status with the given ID, if it belongs to the authed account.
+func (m *Module) ScheduledStatusGETHandler(c *gin.Context) {
+	l := m.log.WithField("func", "ScheduledStatusGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	scheduledStatusID := c.Param(IDKey)
+	if scheduledStatusID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no scheduled status id specified"})
+		return
+	}
+
+	scheduled, errWithCode := m.processor.ScheduledStatusGet(c.Request.Context(), authed, scheduledStatusID)
+	if errWithCode != nil {
+		l.Debugf("error from processor ScheduledStatusGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, scheduled)
+}
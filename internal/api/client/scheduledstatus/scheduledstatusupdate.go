@@ -0,0 +1,44 @@
+package scheduledstatus
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ScheduledStatusUpdatePUTHandler moves the scheduled status with the given ID to a different time.
+func (m *Module) ScheduledStatusUpdatePUTHandler(c *gin.Context) {
+	l := m.log.WithField("func", "ScheduledStatusUpdatePUTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	scheduledStatusID := c.Param(IDKey)
+	if scheduledStatusID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no scheduled status id specified"})
+		return
+	}
+
+	form := &model.ScheduledStatusUpdateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("error parsing form %+v: %s", c.Request.Form, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("could not parse form: %s", err)})
+		return
+	}
+
+	scheduled, errWithCode := m.processor.ScheduledStatusUpdate(c.Request.Context(), authed, scheduledStatusID, form)
+	if errWithCode != nil {
+		l.Debugf("error from processor ScheduledStatusUpdate: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, scheduled)
+}
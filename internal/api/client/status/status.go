@@ -101,6 +101,15 @@ func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodPost, UnreblogPath, m.StatusUnboostPOSTHandler)
 	r.AttachHandler(http.MethodGet, RebloggedPath, m.StatusBoostedByGETHandler)
 
+	r.AttachHandler(http.MethodPost, BookmarkPath, m.StatusBookmarkPOSTHandler)
+	r.AttachHandler(http.MethodPost, UnbookmarkPath, m.StatusUnbookmarkPOSTHandler)
+
+	r.AttachHandler(http.MethodPost, MutePath, m.StatusMutePOSTHandler)
+	r.AttachHandler(http.MethodPost, UnmutePath, m.StatusUnmutePOSTHandler)
+
+	r.AttachHandler(http.MethodPost, PinPath, m.StatusPinPOSTHandler)
+	r.AttachHandler(http.MethodPost, UnpinPath, m.StatusUnpinPOSTHandler)
+
 	r.AttachHandler(http.MethodGet, ContextPath, m.StatusContextGETHandler)
 
 	r.AttachHandler(http.MethodGet, BasePathWithID, m.muxHandler)
@@ -0,0 +1,130 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/status"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type StatusBookmarkTestSuite struct {
+	StatusStandardTestSuite
+}
+
+func (suite *StatusBookmarkTestSuite) SetupSuite() {
+	suite.testTokens = testrig.NewTestTokens()
+	suite.testClients = testrig.NewTestClients()
+	suite.testApplications = testrig.NewTestApplications()
+	suite.testUsers = testrig.NewTestUsers()
+	suite.testAccounts = testrig.NewTestAccounts()
+	suite.testAttachments = testrig.NewTestAttachments()
+	suite.testStatuses = testrig.NewTestStatuses()
+}
+
+func (suite *StatusBookmarkTestSuite) SetupTest() {
+	suite.config = testrig.NewTestConfig()
+	suite.db = testrig.NewTestDB()
+	suite.storage = testrig.NewTestStorage()
+	suite.log = testrig.NewTestLog()
+	suite.federator = testrig.NewTestFederator(suite.db, testrig.NewTestTransportController(testrig.NewMockHTTPClient(nil)), suite.storage)
+	suite.processor = testrig.NewTestProcessor(suite.db, suite.storage, suite.federator)
+	suite.statusModule = status.New(suite.config, suite.processor, suite.log).(*status.Module)
+	testrig.StandardDBSetup(suite.db)
+	testrig.StandardStorageSetup(suite.storage, "../../../../testrig/media")
+}
+
+func (suite *StatusBookmarkTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+	testrig.StandardStorageTeardown(suite.storage)
+}
+
+func (suite *StatusBookmarkTestSuite) newContext(recorder *httptest.ResponseRecorder, path string, targetStatusID string) *gin.Context {
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauth.TokenToOauthToken(suite.testTokens["local_account_1"]))
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+	ctx.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:8080%s", strings.Replace(path, ":id", targetStatusID, 1)), nil)
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   status.IDKey,
+			Value: targetStatusID,
+		},
+	}
+	return ctx
+}
+
+// bookmark a status and then remove the bookmark again
+func (suite *StatusBookmarkTestSuite) TestPostBookmarkAndUnbookmark() {
+	targetStatus := suite.testStatuses["admin_account_status_1"]
+
+	// bookmarking twice should be fine
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		suite.statusModule.StatusBookmarkPOSTHandler(suite.newContext(recorder, status.BookmarkPath, targetStatus.ID))
+		suite.Equal(http.StatusOK, recorder.Code)
+
+		statusReply := &model.Status{}
+		suite.NoError(json.Unmarshal(recorder.Body.Bytes(), statusReply))
+		suite.Equal(targetStatus.ID, statusReply.ID)
+		suite.True(statusReply.Bookmarked)
+	}
+
+	recorder := httptest.NewRecorder()
+	suite.statusModule.StatusUnbookmarkPOSTHandler(suite.newContext(recorder, status.UnbookmarkPath, targetStatus.ID))
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	statusReply := &model.Status{}
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), statusReply))
+	suite.False(statusReply.Bookmarked)
+}
+
+// statuses that aren't visible to the requester can't be bookmarked
+func (suite *StatusBookmarkTestSuite) TestPostBookmarkBlocked() {
+	targetStatus := suite.testStatuses["local_account_2_status_1"]
+
+	// local_account_2 blocks local_account_1, so local_account_1 can't see their statuses any more
+	suite.NoError(suite.db.Put(context.Background(), &gtsmodel.Block{
+		ID:              "01FGRY7WYN8B5QHS0NGDKHE5G9",
+		AccountID:       suite.testAccounts["local_account_2"].ID,
+		TargetAccountID: suite.testAccounts["local_account_1"].ID,
+		URI:             "http://localhost:8080/users/1happyturtle/blocks/01FGRY7WYN8B5QHS0NGDKHE5G9",
+	}))
+
+	recorder := httptest.NewRecorder()
+	suite.statusModule.StatusBookmarkPOSTHandler(suite.newContext(recorder, status.BookmarkPath, targetStatus.ID))
+	suite.Equal(http.StatusNotFound, recorder.Code)
+}
+
+func TestStatusBookmarkTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusBookmarkTestSuite))
+}
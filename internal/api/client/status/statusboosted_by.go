@@ -49,7 +49,7 @@ func (m *Module) StatusBoostedByGETHandler(c *gin.Context) {
 		return
 	}
 
-	mastoAccounts, err := m.processor.StatusBoostedBy(authed, targetStatusID)
+	mastoAccounts, err := m.processor.StatusBoostedBy(c.Request.Context(), authed, targetStatusID)
 	if err != nil {
 		l.Debugf("error processing status boosted by request: %s", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
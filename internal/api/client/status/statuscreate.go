@@ -30,6 +30,13 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
+const (
+	// pollMinExpiresIn is the shortest time in seconds that a poll can be open for
+	pollMinExpiresIn = 5 * 60
+	// pollMaxExpiresIn is the longest time in seconds that a poll can be open for
+	pollMaxExpiresIn = 31 * 24 * 60 * 60
+)
+
 // StatusCreatePOSTHandler deals with the creation of new statuses
 func (m *Module) StatusCreatePOSTHandler(c *gin.Context) {
 	l := m.log.WithField("func", "statusCreatePOSTHandler")
@@ -66,7 +73,19 @@ func (m *Module) StatusCreatePOSTHandler(c *gin.Context) {
 		return
 	}
 
-	mastoStatus, err := m.processor.StatusCreate(authed, form)
+	// if scheduled_at is set, store the status for later instead of posting it now
+	if form.ScheduledAt != "" {
+		scheduledStatus, errWithCode := m.processor.ScheduledStatusCreate(c.Request.Context(), authed, form)
+		if errWithCode != nil {
+			l.Debugf("error processing scheduled status create: %s", errWithCode.Error())
+			c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+			return
+		}
+		c.JSON(http.StatusOK, scheduledStatus)
+		return
+	}
+
+	mastoStatus, err := m.processor.StatusCreate(c.Request.Context(), authed, form)
 	if err != nil {
 		l.Debugf("error processing status create: %s", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
@@ -103,6 +122,9 @@ func validateCreateStatus(form *model.AdvancedStatusCreateForm, config *config.S
 		if form.Poll.Options == nil {
 			return errors.New("poll with no options")
 		}
+		if len(form.Poll.Options) < 2 {
+			return errors.New("poll must have at least 2 options")
+		}
 		if len(form.Poll.Options) > config.PollMaxOptions {
 			return fmt.Errorf("too many poll options provided, %d provided but limit is %d", len(form.Poll.Options), config.PollMaxOptions)
 		}
@@ -111,6 +133,9 @@ func validateCreateStatus(form *model.AdvancedStatusCreateForm, config *config.S
 				return fmt.Errorf("poll option too long, %d characters provided but limit is %d", len(p), config.PollOptionMaxChars)
 			}
 		}
+		if form.Poll.ExpiresIn < pollMinExpiresIn || form.Poll.ExpiresIn > pollMaxExpiresIn {
+			return fmt.Errorf("poll expires_in must be between %d and %d seconds", pollMinExpiresIn, pollMaxExpiresIn)
+		}
 	}
 
 	// validate spoiler text/cw
@@ -49,7 +49,7 @@ func (m *Module) StatusFavePOSTHandler(c *gin.Context) {
 		return
 	}
 
-	mastoStatus, err := m.processor.StatusFave(authed, targetStatusID)
+	mastoStatus, err := m.processor.StatusFave(c.Request.Context(), authed, targetStatusID)
 	if err != nil {
 		l.Debugf("error processing status fave: %s", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
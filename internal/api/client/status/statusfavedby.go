@@ -49,7 +49,7 @@ func (m *Module) StatusFavedByGETHandler(c *gin.Context) {
 		return
 	}
 
-	mastoAccounts, err := m.processor.StatusFavedBy(authed, targetStatusID)
+	mastoAccounts, err := m.processor.StatusFavedBy(c.Request.Context(), authed, targetStatusID)
 	if err != nil {
 		l.Debugf("error processing status faved by request: %s", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
@@ -49,7 +49,7 @@ func (m *Module) StatusUnfavePOSTHandler(c *gin.Context) {
 		return
 	}
 
-	mastoStatus, err := m.processor.StatusUnfave(authed, targetStatusID)
+	mastoStatus, err := m.processor.StatusUnfave(c.Request.Context(), authed, targetStatusID)
 	if err != nil {
 		l.Debugf("error processing status unfave: %s", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
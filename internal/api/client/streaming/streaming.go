@@ -35,6 +35,12 @@ const (
 	// StreamQueryKey is the query key for the type of stream being requested
 	StreamQueryKey = "stream"
 
+	// ListQueryKey is the query key for the ID of the list to stream, when the requested stream type is list.
+	ListQueryKey = "list"
+
+	// TagQueryKey is the query key for the name of the hashtag to stream, when the requested stream type is hashtag.
+	TagQueryKey = "tag"
+
 	// AccessTokenQueryKey is the query key for an oauth access token that should be passed in streaming requests.
 	AccessTokenQueryKey = "access_token"
 )
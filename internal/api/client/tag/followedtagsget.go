@@ -0,0 +1,29 @@
+package tag
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// FollowedTagsGETHandler returns the hashtags followed by the authed account.
+func (m *Module) FollowedTagsGETHandler(c *gin.Context) {
+	l := m.log.WithField("func", "FollowedTagsGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	tags, errWithCode := m.processor.FollowedTagsGet(c.Request.Context(), authed)
+	if errWithCode != nil {
+		l.Debugf("error from processor FollowedTagsGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
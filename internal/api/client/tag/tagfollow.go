@@ -0,0 +1,62 @@
+package tag
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// TagFollowPOSTHandler makes the authed account follow the hashtag with the given name.
+func (m *Module) TagFollowPOSTHandler(c *gin.Context) {
+	l := m.log.WithField("func", "TagFollowPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	tagName := c.Param(NameKey)
+	if tagName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no hashtag specified"})
+		return
+	}
+
+	tag, errWithCode := m.processor.TagFollow(c.Request.Context(), authed, tagName)
+	if errWithCode != nil {
+		l.Debugf("error from processor TagFollow: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// TagUnfollowPOSTHandler makes the authed account stop following the hashtag with the given name.
+func (m *Module) TagUnfollowPOSTHandler(c *gin.Context) {
+	l := m.log.WithField("func", "TagUnfollowPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	tagName := c.Param(NameKey)
+	if tagName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no hashtag specified"})
+		return
+	}
+
+	tag, errWithCode := m.processor.TagUnfollow(c.Request.Context(), authed, tagName)
+	if errWithCode != nil {
+		l.Debugf("error from processor TagUnfollow: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
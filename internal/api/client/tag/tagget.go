@@ -0,0 +1,35 @@
+package tag
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// TagGETHandler returns the hashtag with the given name, including whether the authed account follows it.
+func (m *Module) TagGETHandler(c *gin.Context) {
+	l := m.log.WithField("func", "TagGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	tagName := c.Param(NameKey)
+	if tagName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no hashtag specified"})
+		return
+	}
+
+	tag, errWithCode := m.processor.TagGet(c.Request.Context(), authed, tagName)
+	if errWithCode != nil {
+		l.Debugf("error from processor TagGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
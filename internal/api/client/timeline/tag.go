@@ -0,0 +1,109 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package timeline
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// TagTimelineGETHandler serves public statuses that use the hashtag with the given name.
+//
+// Several different filters might be passed into this function in the query:
+//
+//	max_id -- the maximum ID of the status to show
+//	since_id -- Return results newer than id
+//	min_id -- Return results immediately newer than id
+//	limit -- show only limit number of statuses
+//	local -- show only statuses created on this instance
+//	only_media -- show only statuses with media attachments
+//	any[] -- also show statuses using any of these hashtags
+//	all[] -- show only statuses that use all of these hashtags as well
+//	none[] -- don't show statuses that use any of these hashtags
+func (m *Module) TagTimelineGETHandler(c *gin.Context) {
+	l := m.log.WithField("func", "TagTimelineGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	tagName := c.Param(HashtagKey)
+	if tagName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no hashtag specified"})
+		return
+	}
+
+	maxID := c.Query(MaxIDKey)
+	sinceID := c.Query(SinceIDKey)
+	minID := c.Query(MinIDKey)
+
+	limit := 20
+	limitString := c.Query(LimitKey)
+	if limitString != "" {
+		i, err := strconv.ParseInt(limitString, 10, 64)
+		if err != nil {
+			l.Debugf("error parsing limit string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse limit query param"})
+			return
+		}
+		limit = int(i)
+	}
+
+	local := false
+	localString := c.Query(LocalKey)
+	if localString != "" {
+		i, err := strconv.ParseBool(localString)
+		if err != nil {
+			l.Debugf("error parsing local string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse local query param"})
+			return
+		}
+		local = i
+	}
+
+	onlyMedia := false
+	onlyMediaString := c.Query(OnlyMediaKey)
+	if onlyMediaString != "" {
+		i, err := strconv.ParseBool(onlyMediaString)
+		if err != nil {
+			l.Debugf("error parsing only media string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse only_media query param"})
+			return
+		}
+		onlyMedia = i
+	}
+
+	resp, errWithCode := m.processor.TagTimelineGet(c.Request.Context(), authed, tagName, c.QueryArray(AnyKey), c.QueryArray(AllKey), c.QueryArray(NoneKey), maxID, sinceID, minID, limit, local, onlyMedia)
+	if errWithCode != nil {
+		l.Debugf("error from processor TagTimelineGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	if resp.LinkHeader != "" {
+		c.Header("Link", resp.LinkHeader)
+	}
+	c.JSON(http.StatusOK, resp.Statuses)
+}
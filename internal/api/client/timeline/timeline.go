@@ -35,6 +35,14 @@ const (
 	HomeTimeline = BasePath + "/home"
 	// PublicTimeline is the path for the public (and public local) timeline
 	PublicTimeline = BasePath + "/public"
+	// ListIDKey is the key to use for retrieving the list ID in requests
+	ListIDKey = "list_id"
+	// ListTimeline is the path for a list timeline
+	ListTimeline = BasePath + "/list/:" + ListIDKey
+	// HashtagKey is the key to use for retrieving the hashtag name in requests
+	HashtagKey = "hashtag"
+	// TagTimeline is the path for a hashtag timeline
+	TagTimeline = BasePath + "/tag/:" + HashtagKey
 	// MaxIDKey is the url query for setting a max status ID to return
 	MaxIDKey = "max_id"
 	// SinceIDKey is the url query for returning results newer than the given ID
@@ -45,6 +53,14 @@ const (
 	LimitKey = "limit"
 	// LocalKey is for specifying whether only local statuses should be returned
 	LocalKey = "local"
+	// OnlyMediaKey is for specifying whether only statuses with media attachments should be returned
+	OnlyMediaKey = "only_media"
+	// AnyKey is for specifying additional hashtags, any of which may be used instead of the hashtag in the path
+	AnyKey = "any[]"
+	// AllKey is for specifying additional hashtags that must all be used as well
+	AllKey = "all[]"
+	// NoneKey is for specifying hashtags that must not be used
+	NoneKey = "none[]"
 )
 
 // Module implements the ClientAPIModule interface for everything relating to viewing timelines
@@ -67,5 +83,7 @@ func New(config *config.Config, processor processing.Processor, log *logrus.Logg
 func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodGet, HomeTimeline, m.HomeTimelineGETHandler)
 	r.AttachHandler(http.MethodGet, PublicTimeline, m.PublicTimelineGETHandler)
+	r.AttachHandler(http.MethodGet, ListTimeline, m.ListTimelineGETHandler)
+	r.AttachHandler(http.MethodGet, TagTimeline, m.TagTimelineGETHandler)
 	return nil
 }
@@ -145,3 +145,14 @@ type AccountFollowRequest struct {
 	// Notify when this account posts?
 	Notify *bool `form:"notify" json:"notify" xml:"notify"`
 }
+
+// AccountMuteRequest is for parsing requests at /api/v1/accounts/:id/mute
+type AccountMuteRequest struct {
+	// ID of the account to mute
+	// This should be a URL parameter not a form field
+	TargetAccountID string `form:"-"`
+	// Mute notifications from this account as well as its statuses? Defaults to true.
+	Notifications *bool `form:"notifications" json:"notifications" xml:"notifications"`
+	// How long the mute should last, in seconds. 0 means the mute never expires.
+	Duration int `form:"duration" json:"duration" xml:"duration"`
+}
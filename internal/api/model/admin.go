@@ -60,10 +60,16 @@ type AdminAccountInfo struct {
 type AdminReportInfo struct {
 	// The ID of the report in the database.
 	ID string `json:"id"`
-	// The action taken to resolve this report.
+	// The action taken to resolve this report, or an empty string if it hasn't been resolved yet.
 	ActionTaken string `json:"action_taken"`
+	// When the report was resolved. (ISO 8601 Datetime)
+	ActionTakenAt *string `json:"action_taken_at"`
 	// An optional reason for reporting.
 	Comment string `json:"comment"`
+	// Whether the report was forwarded to the instance of the reported account.
+	Forwarded bool `json:"forwarded"`
+	// Notes left on the report by moderators.
+	Note string `json:"note"`
 	// The time the report was filed. (ISO 8601 Datetime)
 	CreatedAt string `json:"created_at"`
 	// The time of last action on this report. (ISO 8601 Datetime)
@@ -74,8 +80,38 @@ type AdminReportInfo struct {
 	TargetAccount *Account `json:"target_account"`
 	// The account of the moderator assigned to this report.
 	AssignedAccount *Account `json:"assigned_account"`
-	// The action taken by the moderator who handled the report.
-	ActionTakenByAccount string `json:"action_taken_by_account"`
+	// The account of the moderator who resolved the report.
+	ActionTakenByAccount *Account `json:"action_taken_by_account"`
 	// Statuses attached to the report, for context.
 	Statuses []Status `json:"statuses"`
 }
+
+// AdminReportResolveRequest is the form submitted to resolve a report, via POST to /api/v1/admin/reports/:id/resolve.
+type AdminReportResolveRequest struct {
+	// The action to take against the reported account or statuses: none, silence, suspend or delete_status. Defaults to none.
+	Action string `form:"action" json:"action" xml:"action"`
+}
+
+// AdminReportNoteRequest is the form submitted to annotate a report, via POST to /api/v1/admin/reports/:id/note.
+type AdminReportNoteRequest struct {
+	// Moderator notes about the report, replacing any notes that were there before.
+	Note string `form:"note" json:"note" xml:"note"`
+}
+
+// AdminDeliveryInfo represents the *admin* view of outgoing deliveries to one domain that are waiting to be retried, or that have been given up on.
+type AdminDeliveryInfo struct {
+	// The domain the deliveries are addressed to.
+	Domain string `json:"domain"`
+	// The number of deliveries that will be retried.
+	Pending int `json:"pending"`
+	// The number of deliveries that have been given up on.
+	Failed int `json:"failed"`
+	// The error returned by the most recent failed delivery attempt.
+	LastError string `json:"last_error,omitempty"`
+	// When delivery to this domain was last attempted. (ISO 8601 Datetime)
+	LastAttemptAt string `json:"last_attempt_at,omitempty"`
+	// When the next pending delivery will be retried. (ISO 8601 Datetime)
+	NextAttemptAt string `json:"next_attempt_at,omitempty"`
+	// When the domain was marked as unreachable, if it has been. (ISO 8601 Datetime)
+	UnreachableSince string `json:"unreachable_since,omitempty"`
+}
@@ -18,14 +18,23 @@
 
 package model
 
+import (
+	"io"
+	"time"
+)
+
 // Content wraps everything needed to serve a blob of content (some kind of media) through the API.
 type Content struct {
 	// MIME content type
 	ContentType string
 	// ContentLength in bytes
 	ContentLength int64
-	// Actual content blob
-	Content []byte
+	// ContentUpdated is when the content was last modified. May be zero if unknown.
+	ContentUpdated time.Time
+	// ETag uniquely identifies this version of the content, for use in http caching.
+	ETag string
+	// Actual content, which should be closed by the caller when done with it
+	Content io.ReadSeekCloser
 }
 
 // GetContentRequestForm describes a piece of content desired by the caller of the fileserver API.
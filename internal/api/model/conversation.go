@@ -34,3 +34,10 @@ type Conversation struct {
 	// The last status in the conversation, to be used for optional display.
 	LastStatus *Status `json:"last_status"`
 }
+
+// ConversationsResponse wraps a slice of conversations, ready to be serialized, along with the Link
+// header for the previous and next queries, to be returned to the client.
+type ConversationsResponse struct {
+	Conversations []*Conversation
+	LinkHeader    string
+}
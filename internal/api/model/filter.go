@@ -29,7 +29,7 @@ type Filter struct {
 	// The ID of the filter in the database.
 	ID string `json:"id"`
 	// The text to be filtered.
-	Phrase string `json:"text"`
+	Phrase string `json:"phrase"`
 	// The contexts in which the filter should be applied.
 	// Array of String (Enumerable anyOf)
 	// 	home = home timeline and lists
@@ -44,3 +44,18 @@ type Filter struct {
 	// Should matching entities in home and notifications be dropped by the server?
 	Irreversible bool `json:"irreversible"`
 }
+
+// FilterCreateRequest is the form submitted as a POST to /api/v1/filters to create a new filter,
+// or as a PUT to /api/v1/filters/:id to update an existing one.
+type FilterCreateRequest struct {
+	// The text to be filtered.
+	Phrase string `form:"phrase" json:"phrase" xml:"phrase"`
+	// The contexts in which the filter should be applied: one or more of home, notifications, public or thread.
+	Context []string `form:"context[]" json:"context" xml:"context"`
+	// Should matching entities in home and notifications be dropped by the server?
+	Irreversible bool `form:"irreversible" json:"irreversible" xml:"irreversible"`
+	// Should the filter consider word boundaries?
+	WholeWord bool `form:"whole_word" json:"whole_word" xml:"whole_word"`
+	// Number of seconds from now that the filter should expire. If 0 or not set, the filter doesn't expire.
+	ExpiresIn int `form:"expires_in" json:"expires_in" xml:"expires_in"`
+}
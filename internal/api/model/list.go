@@ -29,3 +29,25 @@ type List struct {
 	//	none = Show replies to no one
 	RepliesPolicy string `json:"replies_policy"`
 }
+
+// ListCreateRequest is the form submitted as a POST to /api/v1/lists to create a new list,
+// or as a PUT to /api/v1/lists/:id to update an existing one.
+type ListCreateRequest struct {
+	// The title of the list.
+	Title string `form:"title" json:"title" xml:"title"`
+	// One of followed, list, or none. Defaults to followed.
+	RepliesPolicy string `form:"replies_policy" json:"replies_policy" xml:"replies_policy"`
+}
+
+// ListAccountsChangeRequest is the form submitted as a POST to /api/v1/lists/:id/accounts to add accounts to a list,
+// or as a DELETE to /api/v1/lists/:id/accounts to remove accounts from it.
+type ListAccountsChangeRequest struct {
+	// The IDs of the accounts to add to or remove from the list.
+	AccountIDs []string `form:"account_ids[]" json:"account_ids" xml:"account_ids"`
+}
+
+// ListAccountsResponse wraps a slice of list member accounts, and a link header for paging through them.
+type ListAccountsResponse struct {
+	Accounts   []*Account
+	LinkHeader string
+}
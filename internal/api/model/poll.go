@@ -54,11 +54,18 @@ type PollOptions struct {
 // It should be used at the path https://example.org/api/v1/statuses
 type PollRequest struct {
 	// Array of possible answers. If provided, media_ids cannot be used, and poll[expires_in] must be provided.
-	Options []string `form:"options"`
+	Options []string `form:"poll[options][]" json:"options" xml:"options"`
 	// Duration the poll should be open, in seconds. If provided, media_ids cannot be used, and poll[options] must be provided.
-	ExpiresIn int `form:"expires_in"`
+	ExpiresIn int `form:"poll[expires_in]" json:"expires_in" xml:"expires_in"`
 	// Allow multiple choices?
-	Multiple bool `form:"multiple"`
+	Multiple bool `form:"poll[multiple]" json:"multiple" xml:"multiple"`
 	// Hide vote counts until the poll ends?
-	HideTotals bool `form:"hide_totals"`
+	HideTotals bool `form:"poll[hide_totals]" json:"hide_totals" xml:"hide_totals"`
+}
+
+// PollVoteRequest represents a mastodon-api request to vote in a poll, as defined here: https://docs.joinmastodon.org/methods/statuses/polls/
+// It should be used at the path https://example.org/api/v1/polls/:id/votes
+type PollVoteRequest struct {
+	// Array of the indexes of the chosen options.
+	Choices []int `form:"choices[]" json:"choices" xml:"choices"`
 }
@@ -0,0 +1,51 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// Report represents a report of an account, as seen by the account that made the report. See https://docs.joinmastodon.org/entities/report/
+type Report struct {
+	// The ID of the report in the database.
+	ID string `json:"id"`
+	// Has a moderator taken action on this report yet?
+	ActionTaken bool `json:"action_taken"`
+	// When was action taken on this report? (ISO 8601 Datetime)
+	ActionTakenAt *string `json:"action_taken_at"`
+	// Why was the account reported?
+	Comment string `json:"comment"`
+	// Was the report forwarded to the instance of the reported account?
+	Forwarded bool `json:"forwarded"`
+	// When was the report created? (ISO 8601 Datetime)
+	CreatedAt string `json:"created_at"`
+	// IDs of statuses that were attached to the report.
+	StatusIDs []string `json:"status_ids"`
+	// The account that was reported.
+	TargetAccount *Account `json:"target_account"`
+}
+
+// ReportCreateRequest represents the form submitted to create a report, via POST to /api/v1/reports.
+type ReportCreateRequest struct {
+	// ID of the account to report. If not set, the author of the given statuses will be reported.
+	AccountID string `form:"account_id" json:"account_id" xml:"account_id"`
+	// IDs of statuses of the reported account to attach to the report.
+	StatusIDs []string `form:"status_ids[]" json:"status_ids" xml:"status_ids"`
+	// Why is the account being reported?
+	Comment string `form:"comment" json:"comment" xml:"comment"`
+	// If the account is remote, should the report be forwarded to its instance?
+	Forward bool `form:"forward" json:"forward" xml:"forward"`
+}
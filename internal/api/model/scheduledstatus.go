@@ -36,4 +36,21 @@ type StatusParams struct {
 	Visibility    string   `json:"visibility"`
 	ScheduledAt   string   `json:"scheduled_at,omitempty"`
 	ApplicationID string   `json:"application_id"`
+	// GTS extras
+	Poll     *PollRequest `json:"poll,omitempty"`
+	Language string       `json:"language,omitempty"`
+}
+
+// ScheduledStatusUpdateRequest represents a mastodon-api request to move a scheduled status to a different time.
+// It should be used at the path https://example.org/api/v1/scheduled_statuses/:id
+type ScheduledStatusUpdateRequest struct {
+	// ISO 8601 Datetime at which the status will be published. Must be at least 5 minutes in the future.
+	ScheduledAt string `form:"scheduled_at" json:"scheduled_at" xml:"scheduled_at"`
+}
+
+// ScheduledStatusesResponse wraps a slice of scheduled statuses, ready to be serialized, along with the Link
+// header for the previous and next queries, to be returned to the client.
+type ScheduledStatusesResponse struct {
+	ScheduledStatuses []*ScheduledStatus
+	LinkHeader        string
 }
@@ -24,4 +24,6 @@ type Tag struct {
 	Name string `json:"name"`
 	// A link to the hashtag on the instance.
 	URL string `json:"url"`
+	// Whether the requesting account follows this hashtag. Only set when a tag is looked up on its own.
+	Following *bool `json:"following,omitempty"`
 }
@@ -1,6 +1,7 @@
 package security
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 
@@ -33,7 +34,7 @@ func (m *Module) SignatureCheck(c *gin.Context) {
 			// we managed to parse the url!
 
 			// if the domain is blocked we want to bail as early as possible
-			blockedDomain, err := m.blockedDomain(requestingPublicKeyID.Host)
+			blockedDomain, err := m.blockedDomain(c.Request.Context(), requestingPublicKeyID.Host)
 			if err != nil {
 				l.Errorf("could not tell if domain %s was blocked or not: %s", requestingPublicKeyID.Host, err)
 				c.AbortWithStatus(http.StatusInternalServerError)
@@ -51,9 +52,9 @@ func (m *Module) SignatureCheck(c *gin.Context) {
 	}
 }
 
-func (m *Module) blockedDomain(host string) (bool, error) {
+func (m *Module) blockedDomain(ctx context.Context, host string) (bool, error) {
 	b := &gtsmodel.DomainBlock{}
-	err := m.db.GetWhere([]db.Where{{Key: "domain", Value: host, CaseInsensitive: true}}, b)
+	err := m.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: host, CaseInsensitive: true}}, b)
 	if err == nil {
 		// block exists
 		return true, nil
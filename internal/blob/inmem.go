@@ -1,7 +1,11 @@
 package blob
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"net/url"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
@@ -14,20 +18,23 @@ import (
 // will absolutely go through the roof.
 func NewInMem(c *config.Config, log *logrus.Logger) (Storage, error) {
 	return &inMemStorage{
-		stored: make(map[string][]byte),
-		log:    log,
+		stored:   make(map[string][]byte),
+		modTimes: make(map[string]time.Time),
+		log:      log,
 	}, nil
 }
 
 type inMemStorage struct {
-	stored map[string][]byte
-	log    *logrus.Logger
+	stored   map[string][]byte
+	modTimes map[string]time.Time
+	log      *logrus.Logger
 }
 
 func (s *inMemStorage) StoreFileAt(path string, data []byte) error {
 	l := s.log.WithField("func", "StoreFileAt")
 	l.Debugf("storing at path %s", path)
 	s.stored[path] = data
+	s.modTimes[path] = time.Now()
 	return nil
 }
 
@@ -41,6 +48,33 @@ func (s *inMemStorage) RetrieveFileFrom(path string) ([]byte, error) {
 	return d, nil
 }
 
+func (s *inMemStorage) StoreFileFrom(path string, r io.Reader, size int64) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("error reading data for path %s: %s", path, err)
+	}
+	return s.StoreFileAt(path, data)
+}
+
+func (s *inMemStorage) OpenFile(path string) (io.ReadSeekCloser, error) {
+	d, err := s.RetrieveFileFrom(path)
+	if err != nil {
+		return nil, err
+	}
+	return nopCloser{bytes.NewReader(d)}, nil
+}
+
+func (s *inMemStorage) StatFile(path string) (*FileInfo, error) {
+	d, ok := s.stored[path]
+	if !ok || len(d) == 0 {
+		return nil, fmt.Errorf("no data found at path %s", path)
+	}
+	return &FileInfo{
+		Size:    int64(len(d)),
+		ModTime: s.modTimes[path],
+	}, nil
+}
+
 func (s *inMemStorage) ListKeys() ([]string, error) {
 	keys := []string{}
 	for k := range s.stored {
@@ -51,5 +85,19 @@ func (s *inMemStorage) ListKeys() ([]string, error) {
 
 func (s *inMemStorage) RemoveFileAt(path string) error {
 	delete(s.stored, path)
+	delete(s.modTimes, path)
+	return nil
+}
+
+func (s *inMemStorage) ServeURL(path string) (*url.URL, error) {
+	return nil, nil
+}
+
+// nopCloser wraps an io.ReadSeeker that doesn't need closing.
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error {
 	return nil
 }
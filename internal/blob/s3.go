@@ -0,0 +1,210 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+)
+
+const (
+	// S3ServeModeProxy means media stored in s3 will be served through the gotosocial fileserver, just like local media.
+	S3ServeModeProxy = "proxy"
+	// S3ServeModePresigned means clients will be sent to presigned s3 urls to fetch media.
+	S3ServeModePresigned = "presigned"
+	// S3ServeModePublic means clients will be sent to the public s3 url of media; the bucket must allow public reads.
+	S3ServeModePublic = "public"
+
+	// presignedURLExpiry is how long presigned urls stay valid for. Clients tend to cache statuses
+	// and their attachments for a while, so this is deliberately generous.
+	presignedURLExpiry = 24 * time.Hour
+)
+
+// NewS3 returns an implementation of the Storage interface that uses
+// an S3-compatible object storage for storing and retrieving files, attachments, etc.
+//
+// Paths passed to the storage are expected to start with the configured BasePath, just like
+// with local storage; the BasePath is trimmed off to get the key of the object in the bucket.
+func NewS3(c *config.Config, log *logrus.Logger) (Storage, error) {
+	sc := c.StorageConfig
+	if sc.S3Endpoint == "" {
+		return nil, errors.New("no s3 endpoint set")
+	}
+	if sc.S3Bucket == "" {
+		return nil, errors.New("no s3 bucket set")
+	}
+
+	switch sc.S3ServeMode {
+	case "", S3ServeModeProxy, S3ServeModePresigned, S3ServeModePublic:
+	default:
+		return nil, fmt.Errorf("s3 serve mode %s not recognised", sc.S3ServeMode)
+	}
+
+	lookup := minio.BucketLookupDNS
+	if sc.S3PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(sc.S3Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(sc.S3AccessKey, sc.S3SecretKey, ""),
+		Secure:       !sc.S3Insecure,
+		Region:       sc.S3Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating s3 client: %s", err)
+	}
+
+	exists, err := client.BucketExists(context.Background(), sc.S3Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("error checking s3 bucket %s: %s", sc.S3Bucket, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("s3 bucket %s does not exist", sc.S3Bucket)
+	}
+
+	return &s3Storage{
+		config: c,
+		client: client,
+		log:    log,
+	}, nil
+}
+
+type s3Storage struct {
+	config *config.Config
+	client *minio.Client
+	log    *logrus.Logger
+}
+
+// key returns the object key for the given storage path.
+func (s *s3Storage) key(path string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(path, s.config.StorageConfig.BasePath), "/")
+}
+
+// path returns the storage path for the given object key.
+func (s *s3Storage) path(key string) string {
+	return strings.TrimSuffix(s.config.StorageConfig.BasePath, "/") + "/" + key
+}
+
+func (s *s3Storage) StoreFileAt(path string, data []byte) error {
+	l := s.log.WithField("func", "StoreFileAt")
+	l.Debugf("storing at path %s", path)
+	if _, err := s.client.PutObject(context.Background(), s.config.StorageConfig.S3Bucket, s.key(path), bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("error writing file at %s: %s", path, err)
+	}
+	return nil
+}
+
+func (s *s3Storage) RetrieveFileFrom(path string) ([]byte, error) {
+	l := s.log.WithField("func", "RetrieveFileFrom")
+	l.Debugf("retrieving from path %s", path)
+	o, err := s.client.GetObject(context.Background(), s.config.StorageConfig.S3Bucket, s.key(path), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error reading file at %s: %s", path, err)
+	}
+	defer o.Close()
+
+	b, err := io.ReadAll(o)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file at %s: %s", path, err)
+	}
+	return b, nil
+}
+
+func (s *s3Storage) StoreFileFrom(path string, r io.Reader, size int64) error {
+	l := s.log.WithField("func", "StoreFileFrom")
+	l.Debugf("storing at path %s", path)
+	if _, err := s.client.PutObject(context.Background(), s.config.StorageConfig.S3Bucket, s.key(path), r, size, minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("error writing file at %s: %s", path, err)
+	}
+	return nil
+}
+
+func (s *s3Storage) OpenFile(path string) (io.ReadSeekCloser, error) {
+	l := s.log.WithField("func", "OpenFile")
+	l.Debugf("opening path %s", path)
+	o, err := s.client.GetObject(context.Background(), s.config.StorageConfig.S3Bucket, s.key(path), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error opening file at %s: %s", path, err)
+	}
+
+	// GetObject doesn't actually make a request until the object is first read from,
+	// so stat the object now to make sure it exists before handing it back
+	if _, err := o.Stat(); err != nil {
+		o.Close()
+		return nil, fmt.Errorf("error opening file at %s: %s", path, err)
+	}
+	return o, nil
+}
+
+func (s *s3Storage) StatFile(path string) (*FileInfo, error) {
+	info, err := s.client.StatObject(context.Background(), s.config.StorageConfig.S3Bucket, s.key(path), minio.StatObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error statting file at %s: %s", path, err)
+	}
+	return &FileInfo{
+		Size:    info.Size,
+		ModTime: info.LastModified,
+	}, nil
+}
+
+func (s *s3Storage) ListKeys() ([]string, error) {
+	keys := []string{}
+	for o := range s.client.ListObjects(context.Background(), s.config.StorageConfig.S3Bucket, minio.ListObjectsOptions{Recursive: true}) {
+		if o.Err != nil {
+			return nil, o.Err
+		}
+		keys = append(keys, s.path(o.Key))
+	}
+	return keys, nil
+}
+
+func (s *s3Storage) RemoveFileAt(path string) error {
+	return s.client.RemoveObject(context.Background(), s.config.StorageConfig.S3Bucket, s.key(path), minio.RemoveObjectOptions{})
+}
+
+func (s *s3Storage) ServeURL(path string) (*url.URL, error) {
+	sc := s.config.StorageConfig
+	switch sc.S3ServeMode {
+	case S3ServeModePresigned:
+		return s.client.PresignedGetObject(context.Background(), sc.S3Bucket, s.key(path), presignedURLExpiry, url.Values{})
+	case S3ServeModePublic:
+		u := *s.client.EndpointURL()
+		if sc.S3PathStyle {
+			u.Path = "/" + sc.S3Bucket + "/" + s.key(path)
+		} else {
+			u.Host = sc.S3Bucket + "." + u.Host
+			u.Path = "/" + s.key(path)
+		}
+		return &u, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,161 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package blob_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/blob"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type S3TestSuite struct {
+	suite.Suite
+	server  *httptest.Server
+	config  *config.Config
+	storage blob.Storage
+}
+
+func (suite *S3TestSuite) SetupTest() {
+	// gofakes3 stands in for minio or any other s3-compatible object storage
+	backend := s3mem.New()
+	suite.NoError(backend.CreateBucket("gotosocial"))
+	faker := gofakes3.New(backend).Server()
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// gofakes3 treats an empty delimiter as a real one when listing objects,
+		// whereas s3 and minio ignore it, so strip it out before passing the request on
+		q := r.URL.Query()
+		if d, ok := q["delimiter"]; ok && len(d) == 1 && d[0] == "" {
+			q.Del("delimiter")
+			r.URL.RawQuery = q.Encode()
+		}
+		faker.ServeHTTP(w, r)
+	}))
+
+	serverURL, err := url.Parse(suite.server.URL)
+	suite.NoError(err)
+
+	suite.config = testrig.NewTestConfig()
+	suite.config.StorageConfig.Backend = "s3"
+	suite.config.StorageConfig.S3Endpoint = serverURL.Host
+	suite.config.StorageConfig.S3Region = "us-east-1"
+	suite.config.StorageConfig.S3Bucket = "gotosocial"
+	suite.config.StorageConfig.S3AccessKey = "access"
+	suite.config.StorageConfig.S3SecretKey = "secret"
+	suite.config.StorageConfig.S3Insecure = true
+	suite.config.StorageConfig.S3PathStyle = true
+	suite.config.StorageConfig.S3ServeMode = blob.S3ServeModeProxy
+
+	suite.storage, err = blob.New(suite.config, testrig.NewTestLog())
+	suite.NoError(err)
+}
+
+func (suite *S3TestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *S3TestSuite) TestStoreRetrieveRemove() {
+	path := suite.config.StorageConfig.BasePath + "/01F8MH17FWEB39HZJ76B6VXSKF/attachment/original/01F8MH6NEM8D7527KZAECTCR76.jpeg"
+	data := []byte("not really a jpeg")
+
+	suite.NoError(suite.storage.StoreFileAt(path, data))
+
+	retrieved, err := suite.storage.RetrieveFileFrom(path)
+	suite.NoError(err)
+	suite.Equal(data, retrieved)
+
+	keys, err := suite.storage.ListKeys()
+	suite.NoError(err)
+	suite.Equal([]string{path}, keys)
+
+	suite.NoError(suite.storage.RemoveFileAt(path))
+
+	keys, err = suite.storage.ListKeys()
+	suite.NoError(err)
+	suite.Empty(keys)
+
+	_, err = suite.storage.RetrieveFileFrom(path)
+	suite.Error(err)
+}
+
+func (suite *S3TestSuite) TestStreaming() {
+	path := suite.config.StorageConfig.BasePath + "/01F8MH17FWEB39HZJ76B6VXSKF/attachment/original/01F8MH7TDVANYKWVE8VVKFPJTJ.mp4"
+	data := []byte("not really a video, but long enough to seek around in")
+
+	suite.NoError(suite.storage.StoreFileFrom(path, bytes.NewReader(data), int64(len(data))))
+
+	info, err := suite.storage.StatFile(path)
+	suite.NoError(err)
+	suite.EqualValues(len(data), info.Size)
+	suite.False(info.ModTime.IsZero())
+
+	f, err := suite.storage.OpenFile(path)
+	suite.NoError(err)
+	defer f.Close()
+
+	_, err = f.Seek(13, io.SeekStart)
+	suite.NoError(err)
+	b := make([]byte, 5)
+	_, err = io.ReadFull(f, b)
+	suite.NoError(err)
+	suite.Equal("video", string(b))
+
+	_, err = suite.storage.OpenFile(path + ".nope")
+	suite.Error(err)
+}
+
+func (suite *S3TestSuite) TestServeURL() {
+	path := suite.config.StorageConfig.BasePath + "/01F8MH17FWEB39HZJ76B6VXSKF/attachment/small/01F8MH6NEM8D7527KZAECTCR76.jpeg"
+
+	// proxy mode should leave serving to the fileserver
+	u, err := suite.storage.ServeURL(path)
+	suite.NoError(err)
+	suite.Nil(u)
+
+	suite.config.StorageConfig.S3ServeMode = blob.S3ServeModePublic
+	u, err = suite.storage.ServeURL(path)
+	suite.NoError(err)
+	suite.Equal(suite.server.URL+"/gotosocial/01F8MH17FWEB39HZJ76B6VXSKF/attachment/small/01F8MH6NEM8D7527KZAECTCR76.jpeg", u.String())
+
+	suite.config.StorageConfig.S3ServeMode = blob.S3ServeModePresigned
+	u, err = suite.storage.ServeURL(path)
+	suite.NoError(err)
+	suite.True(strings.HasPrefix(u.String(), suite.server.URL+"/gotosocial/01F8MH17FWEB39HZJ76B6VXSKF/attachment/small/01F8MH6NEM8D7527KZAECTCR76.jpeg?"))
+	suite.NotEmpty(u.Query().Get("X-Amz-Signature"))
+}
+
+func (suite *S3TestSuite) TestNoSuchBucket() {
+	suite.config.StorageConfig.S3Bucket = "nope"
+	_, err := blob.New(suite.config, testrig.NewTestLog())
+	suite.EqualError(err, "s3 bucket nope does not exist")
+}
+
+func TestS3TestSuite(t *testing.T) {
+	suite.Run(t, new(S3TestSuite))
+}
@@ -18,12 +18,52 @@
 
 package blob
 
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+)
+
 // Storage is an interface for storing and retrieving blobs
 // such as images, videos, and any other attachments/documents
 // that shouldn't be stored in a database.
 type Storage interface {
 	StoreFileAt(path string, data []byte) error
 	RetrieveFileFrom(path string) ([]byte, error)
+	// StoreFileFrom stores everything read from r at path. If size is not known in advance, it should be -1.
+	StoreFileFrom(path string, r io.Reader, size int64) error
+	// OpenFile opens the file at path for reading. The caller should close the file when done with it.
+	OpenFile(path string) (io.ReadSeekCloser, error)
+	// StatFile returns information about the file at path, without reading the file itself.
+	StatFile(path string) (*FileInfo, error)
 	ListKeys() ([]string, error)
 	RemoveFileAt(path string) error
+	// ServeURL returns a url from which clients can fetch the file at path directly,
+	// or nil if the file should be served through the gotosocial fileserver as normal.
+	ServeURL(path string) (*url.URL, error)
+}
+
+// FileInfo describes a stored file.
+type FileInfo struct {
+	// Size of the file in bytes
+	Size int64
+	// When was the file last modified? May be zero if the storage backend doesn't know.
+	ModTime time.Time
+}
+
+// New returns an implementation of the Storage interface for the backend set in the given config.
+func New(c *config.Config, log *logrus.Logger) (Storage, error) {
+	switch strings.ToLower(c.StorageConfig.Backend) {
+	case "local":
+		return NewLocal(c, log)
+	case "s3":
+		return NewS3(c, log)
+	default:
+		return nil, fmt.Errorf("storage backend %s not recognised", c.StorageConfig.Backend)
+	}
 }
@@ -18,8 +18,42 @@
 
 package cache
 
+import "errors"
+
+// ErrNotFound is returned from Fetch when there's no entry for the given key, or the entry has expired.
+var ErrNotFound = errors.New("not found in cache")
+
 // Cache defines an in-memory cache that is safe to be wiped when the application is restarted
 type Cache interface {
+	// Store stores v under key k, replacing whatever was stored under k before.
 	Store(k string, v interface{}) error
+	// Fetch returns whatever is stored under key k, or ErrNotFound.
 	Fetch(k string) (interface{}, error)
+	// Evict removes whatever is stored under key k, if anything.
+	Evict(k string)
+	// Clear removes everything from the cache.
+	Clear()
+	// Stats returns usage statistics for the cache.
+	Stats() Stats
+}
+
+// Stats contains usage statistics for a cache.
+type Stats struct {
+	// Hits is the number of fetches that found an entry.
+	Hits uint64
+	// Misses is the number of fetches that didn't find an entry, or found an expired one.
+	Misses uint64
+	// Evictions is the number of entries removed to make room for new ones.
+	Evictions uint64
+	// Entries is the number of entries currently in the cache.
+	Entries int
+}
+
+// HitRate returns the fraction of fetches that found an entry, between 0 and 1.
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
 }
@@ -0,0 +1,141 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// NewLRU returns a Cache that holds at most size entries, evicting the least recently used entry
+// when it's full. Entries expire ttl after they were stored; a ttl of 0 means entries never expire.
+//
+// The returned cache is safe for concurrent use.
+func NewLRU(size int, ttl time.Duration) Cache {
+	if size < 1 {
+		size = 1
+	}
+	return &lru{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	// order holds the entries from most recently used at the front to least recently used at the back
+	order *list.List
+	stats Stats
+}
+
+type lruEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+func (c *lru) Store(k string, v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = time.Now().Add(c.ttl)
+	}
+
+	if el, ok := c.entries[k]; ok {
+		e := el.Value.(*lruEntry)
+		e.value = v
+		e.expires = expires
+		c.order.MoveToFront(el)
+		return nil
+	}
+
+	c.entries[k] = c.order.PushFront(&lruEntry{
+		key:     k,
+		value:   v,
+		expires: expires,
+	})
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+	return nil
+}
+
+func (c *lru) Fetch(k string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[k]
+	if !ok {
+		c.stats.Misses++
+		return nil, ErrNotFound
+	}
+
+	e := el.Value.(*lruEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.remove(el)
+		c.stats.Misses++
+		return nil, ErrNotFound
+	}
+
+	c.order.MoveToFront(el)
+	c.stats.Hits++
+	return e.value, nil
+}
+
+func (c *lru) Evict(k string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[k]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *lru) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element, c.size)
+	c.order.Init()
+}
+
+func (c *lru) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats
+	s.Entries = c.order.Len()
+	return s
+}
+
+// remove removes the given element; the caller should hold the lock.
+func (c *lru) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry).key)
+}
@@ -0,0 +1,114 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/cache"
+)
+
+type LRUTestSuite struct {
+	suite.Suite
+}
+
+func (suite *LRUTestSuite) TestStoreFetch() {
+	c := cache.NewLRU(10, 0)
+
+	_, err := c.Fetch("foo")
+	suite.Equal(cache.ErrNotFound, err)
+
+	suite.NoError(c.Store("foo", "bar"))
+	v, err := c.Fetch("foo")
+	suite.NoError(err)
+	suite.Equal("bar", v)
+
+	suite.NoError(c.Store("foo", "baz"))
+	v, err = c.Fetch("foo")
+	suite.NoError(err)
+	suite.Equal("baz", v)
+
+	stats := c.Stats()
+	suite.EqualValues(2, stats.Hits)
+	suite.EqualValues(1, stats.Misses)
+	suite.Equal(1, stats.Entries)
+	suite.InDelta(2.0/3.0, stats.HitRate(), 0.001)
+}
+
+func (suite *LRUTestSuite) TestEvictLeastRecentlyUsed() {
+	c := cache.NewLRU(2, 0)
+
+	suite.NoError(c.Store("a", 1))
+	suite.NoError(c.Store("b", 2))
+
+	// use a so that b is the least recently used
+	_, err := c.Fetch("a")
+	suite.NoError(err)
+
+	suite.NoError(c.Store("c", 3))
+
+	_, err = c.Fetch("b")
+	suite.Equal(cache.ErrNotFound, err)
+	_, err = c.Fetch("a")
+	suite.NoError(err)
+	_, err = c.Fetch("c")
+	suite.NoError(err)
+
+	stats := c.Stats()
+	suite.EqualValues(1, stats.Evictions)
+	suite.Equal(2, stats.Entries)
+}
+
+func (suite *LRUTestSuite) TestExpiry() {
+	c := cache.NewLRU(10, 10*time.Millisecond)
+
+	suite.NoError(c.Store("foo", "bar"))
+	_, err := c.Fetch("foo")
+	suite.NoError(err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = c.Fetch("foo")
+	suite.Equal(cache.ErrNotFound, err)
+	suite.Equal(0, c.Stats().Entries)
+}
+
+func (suite *LRUTestSuite) TestEvictAndClear() {
+	c := cache.NewLRU(10, 0)
+
+	suite.NoError(c.Store("a", 1))
+	suite.NoError(c.Store("b", 2))
+
+	c.Evict("a")
+	_, err := c.Fetch("a")
+	suite.Equal(cache.ErrNotFound, err)
+	_, err = c.Fetch("b")
+	suite.NoError(err)
+
+	c.Clear()
+	_, err = c.Fetch("b")
+	suite.Equal(cache.ErrNotFound, err)
+	suite.Equal(0, c.Stats().Entries)
+}
+
+func TestLRUTestSuite(t *testing.T) {
+	suite.Run(t, new(LRUTestSuite))
+}
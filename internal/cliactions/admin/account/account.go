@@ -28,14 +28,14 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/cliactions"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
-	"github.com/superseriousbusiness/gotosocial/internal/db/pg"
+	"github.com/superseriousbusiness/gotosocial/internal/db/dbconn"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
 // Create creates a new account in the database using the provided flags.
 var Create cliactions.GTSAction = func(ctx context.Context, c *config.Config, log *logrus.Logger) error {
-	dbConn, err := pg.NewPostgresService(ctx, c, log)
+	dbConn, err := dbconn.NewService(ctx, c, log)
 	if err != nil {
 		return fmt.Errorf("error creating dbservice: %s", err)
 	}
@@ -64,7 +64,7 @@ var Create cliactions.GTSAction = func(ctx context.Context, c *config.Config, lo
 		return err
 	}
 
-	_, err = dbConn.NewSignup(username, "", false, email, password, nil, "", "", false, false)
+	_, err = dbConn.NewSignup(ctx, username, "", false, email, password, nil, "", "", false, false)
 	if err != nil {
 		return err
 	}
@@ -74,7 +74,7 @@ var Create cliactions.GTSAction = func(ctx context.Context, c *config.Config, lo
 
 // Confirm sets a user to Approved, sets Email to the current UnconfirmedEmail value, and sets ConfirmedAt to now.
 var Confirm cliactions.GTSAction = func(ctx context.Context, c *config.Config, log *logrus.Logger) error {
-	dbConn, err := pg.NewPostgresService(ctx, c, log)
+	dbConn, err := dbconn.NewService(ctx, c, log)
 	if err != nil {
 		return fmt.Errorf("error creating dbservice: %s", err)
 	}
@@ -88,19 +88,19 @@ var Confirm cliactions.GTSAction = func(ctx context.Context, c *config.Config, l
 	}
 
 	a := &gtsmodel.Account{}
-	if err := dbConn.GetLocalAccountByUsername(username, a); err != nil {
+	if err := dbConn.GetLocalAccountByUsername(ctx, username, a); err != nil {
 		return err
 	}
 
 	u := &gtsmodel.User{}
-	if err := dbConn.GetWhere([]db.Where{{Key: "account_id", Value: a.ID}}, u); err != nil {
+	if err := dbConn.GetWhere(ctx, []db.Where{{Key: "account_id", Value: a.ID}}, u); err != nil {
 		return err
 	}
 
 	u.Approved = true
 	u.Email = u.UnconfirmedEmail
 	u.ConfirmedAt = time.Now()
-	if err := dbConn.UpdateByID(u.ID, u); err != nil {
+	if err := dbConn.UpdateByID(ctx, u.ID, u); err != nil {
 		return err
 	}
 
@@ -109,7 +109,7 @@ var Confirm cliactions.GTSAction = func(ctx context.Context, c *config.Config, l
 
 // Promote sets a user to admin.
 var Promote cliactions.GTSAction = func(ctx context.Context, c *config.Config, log *logrus.Logger) error {
-	dbConn, err := pg.NewPostgresService(ctx, c, log)
+	dbConn, err := dbconn.NewService(ctx, c, log)
 	if err != nil {
 		return fmt.Errorf("error creating dbservice: %s", err)
 	}
@@ -123,16 +123,16 @@ var Promote cliactions.GTSAction = func(ctx context.Context, c *config.Config, l
 	}
 
 	a := &gtsmodel.Account{}
-	if err := dbConn.GetLocalAccountByUsername(username, a); err != nil {
+	if err := dbConn.GetLocalAccountByUsername(ctx, username, a); err != nil {
 		return err
 	}
 
 	u := &gtsmodel.User{}
-	if err := dbConn.GetWhere([]db.Where{{Key: "account_id", Value: a.ID}}, u); err != nil {
+	if err := dbConn.GetWhere(ctx, []db.Where{{Key: "account_id", Value: a.ID}}, u); err != nil {
 		return err
 	}
 	u.Admin = true
-	if err := dbConn.UpdateByID(u.ID, u); err != nil {
+	if err := dbConn.UpdateByID(ctx, u.ID, u); err != nil {
 		return err
 	}
 
@@ -141,7 +141,7 @@ var Promote cliactions.GTSAction = func(ctx context.Context, c *config.Config, l
 
 // Demote sets admin on a user to false.
 var Demote cliactions.GTSAction = func(ctx context.Context, c *config.Config, log *logrus.Logger) error {
-	dbConn, err := pg.NewPostgresService(ctx, c, log)
+	dbConn, err := dbconn.NewService(ctx, c, log)
 	if err != nil {
 		return fmt.Errorf("error creating dbservice: %s", err)
 	}
@@ -155,16 +155,16 @@ var Demote cliactions.GTSAction = func(ctx context.Context, c *config.Config, lo
 	}
 
 	a := &gtsmodel.Account{}
-	if err := dbConn.GetLocalAccountByUsername(username, a); err != nil {
+	if err := dbConn.GetLocalAccountByUsername(ctx, username, a); err != nil {
 		return err
 	}
 
 	u := &gtsmodel.User{}
-	if err := dbConn.GetWhere([]db.Where{{Key: "account_id", Value: a.ID}}, u); err != nil {
+	if err := dbConn.GetWhere(ctx, []db.Where{{Key: "account_id", Value: a.ID}}, u); err != nil {
 		return err
 	}
 	u.Admin = false
-	if err := dbConn.UpdateByID(u.ID, u); err != nil {
+	if err := dbConn.UpdateByID(ctx, u.ID, u); err != nil {
 		return err
 	}
 
@@ -173,7 +173,7 @@ var Demote cliactions.GTSAction = func(ctx context.Context, c *config.Config, lo
 
 // Disable sets Disabled to true on a user.
 var Disable cliactions.GTSAction = func(ctx context.Context, c *config.Config, log *logrus.Logger) error {
-	dbConn, err := pg.NewPostgresService(ctx, c, log)
+	dbConn, err := dbconn.NewService(ctx, c, log)
 	if err != nil {
 		return fmt.Errorf("error creating dbservice: %s", err)
 	}
@@ -187,16 +187,16 @@ var Disable cliactions.GTSAction = func(ctx context.Context, c *config.Config, l
 	}
 
 	a := &gtsmodel.Account{}
-	if err := dbConn.GetLocalAccountByUsername(username, a); err != nil {
+	if err := dbConn.GetLocalAccountByUsername(ctx, username, a); err != nil {
 		return err
 	}
 
 	u := &gtsmodel.User{}
-	if err := dbConn.GetWhere([]db.Where{{Key: "account_id", Value: a.ID}}, u); err != nil {
+	if err := dbConn.GetWhere(ctx, []db.Where{{Key: "account_id", Value: a.ID}}, u); err != nil {
 		return err
 	}
 	u.Disabled = true
-	if err := dbConn.UpdateByID(u.ID, u); err != nil {
+	if err := dbConn.UpdateByID(ctx, u.ID, u); err != nil {
 		return err
 	}
 
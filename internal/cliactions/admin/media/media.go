@@ -0,0 +1,96 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/blob"
+	"github.com/superseriousbusiness/gotosocial/internal/cliactions"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db/dbconn"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+)
+
+// Prune removes remote media that hasn't been accessed for the configured number of days from storage.
+// Pruned media will be dereferenced again by the server when it's requested.
+var Prune cliactions.GTSAction = func(ctx context.Context, c *config.Config, log *logrus.Logger) error {
+	days := c.MediaConfig.RemoteCacheDays
+	if days <= 0 {
+		return errors.New("remote cache days must be greater than 0")
+	}
+
+	dbConn, err := dbconn.NewService(ctx, c, log)
+	if err != nil {
+		return fmt.Errorf("error creating dbservice: %s", err)
+	}
+
+	storageBackend, err := blob.New(c, log)
+	if err != nil {
+		return fmt.Errorf("error creating storage backend: %s", err)
+	}
+
+	mediaHandler := media.New(c, dbConn, storageBackend, log)
+	pruned, err := mediaHandler.PruneRemoteMedia(ctx, time.Now().Add(-time.Duration(days)*24*time.Hour))
+	if err != nil {
+		return err
+	}
+	log.Infof("pruned %d remote media attachments from storage", pruned)
+
+	return dbConn.Stop(ctx)
+}
+
+// Cleanup removes media that has been unattached or orphaned for longer than the configured grace period,
+// as well as files in storage that don't belong to any media. With the dry run flag set, it only reports
+// what would be removed.
+var Cleanup cliactions.GTSAction = func(ctx context.Context, c *config.Config, log *logrus.Logger) error {
+	hours := c.MediaConfig.CleanupGraceHours
+	if hours <= 0 {
+		return errors.New("cleanup grace hours must be greater than 0")
+	}
+	dryRun := c.MediaCLIFlags[config.DryRunFlag]
+
+	dbConn, err := dbconn.NewService(ctx, c, log)
+	if err != nil {
+		return fmt.Errorf("error creating dbservice: %s", err)
+	}
+
+	storageBackend, err := blob.New(c, log)
+	if err != nil {
+		return fmt.Errorf("error creating storage backend: %s", err)
+	}
+
+	mediaHandler := media.New(c, dbConn, storageBackend, log)
+	result, err := mediaHandler.CleanupMedia(ctx, time.Now().Add(-time.Duration(hours)*time.Hour), dryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "removed"
+	if dryRun {
+		verb = "would remove"
+	}
+	log.Infof("%s %d unattached attachments, %d orphaned attachments and %d orphaned files, totalling %d bytes", verb, result.Unattached, result.Orphaned, result.OrphanedFiles, result.Bytes)
+
+	return dbConn.Stop(ctx)
+}
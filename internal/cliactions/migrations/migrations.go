@@ -0,0 +1,87 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/cliactions"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/db/dbconn"
+	"github.com/superseriousbusiness/gotosocial/internal/db/migrations"
+)
+
+// Run runs all pending migrations against the database.
+var Run cliactions.GTSAction = func(ctx context.Context, c *config.Config, log *logrus.Logger) error {
+	dbConn, err := dbconn.NewService(ctx, c, log)
+	if err != nil {
+		return fmt.Errorf("error creating dbservice: %s", err)
+	}
+
+	ran, err := dbConn.Migrate(ctx, migrations.All())
+	if err != nil {
+		return fmt.Errorf("error running migrations: %s", err)
+	}
+
+	if len(ran) == 0 {
+		fmt.Println("database schema is up to date, no migrations were run")
+	}
+	for _, m := range ran {
+		fmt.Printf("ran migration %d: %s\n", m.Version, m.Name)
+	}
+
+	return dbConn.Stop(ctx)
+}
+
+// Status prints the current schema version of the database, and any migrations that still need to be run.
+var Status cliactions.GTSAction = func(ctx context.Context, c *config.Config, log *logrus.Logger) error {
+	dbConn, err := dbconn.NewService(ctx, c, log)
+	if err != nil {
+		return fmt.Errorf("error creating dbservice: %s", err)
+	}
+
+	done, err := dbConn.GetMigrationVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting migration versions: %s", err)
+	}
+
+	pending, err := db.PendingMigrations(migrations.All(), done)
+	if err != nil {
+		return err
+	}
+
+	if len(done) == 0 {
+		fmt.Println("schema version: none, no migrations have been run yet")
+	} else {
+		latest := done[len(done)-1]
+		fmt.Printf("schema version: %d (%s), run at %s\n", latest.Version, latest.Name, latest.RunAt.Format("2006-01-02 15:04:05 MST"))
+	}
+
+	if len(pending) == 0 {
+		fmt.Println("no pending migrations")
+	}
+	for _, m := range pending {
+		fmt.Printf("pending migration %d: %s\n", m.Version, m.Name)
+	}
+
+	return dbConn.Stop(ctx)
+}
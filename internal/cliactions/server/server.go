@@ -3,7 +3,6 @@ package server
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -15,6 +14,8 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/app"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/auth"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/blocks"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/bookmarks"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/conversation"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/emoji"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/favourites"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/fileserver"
@@ -23,10 +24,15 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/instance"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/list"
 	mediaModule "github.com/superseriousbusiness/gotosocial/internal/api/client/media"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/mutes"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/notification"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/poll"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/report"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/scheduledstatus"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/search"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/status"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/streaming"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/tag"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/timeline"
 	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/nodeinfo"
 	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/user"
@@ -35,92 +41,75 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/blob"
 	"github.com/superseriousbusiness/gotosocial/internal/cliactions"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
-	"github.com/superseriousbusiness/gotosocial/internal/db/pg"
+	"github.com/superseriousbusiness/gotosocial/internal/db/dbconn"
+	"github.com/superseriousbusiness/gotosocial/internal/db/migrations"
 	"github.com/superseriousbusiness/gotosocial/internal/federation"
 	"github.com/superseriousbusiness/gotosocial/internal/federation/federatingdb"
 	"github.com/superseriousbusiness/gotosocial/internal/gotosocial"
-	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/oidc"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
 	"github.com/superseriousbusiness/gotosocial/internal/router"
 	timelineprocessing "github.com/superseriousbusiness/gotosocial/internal/timeline"
+	"github.com/superseriousbusiness/gotosocial/internal/tracing"
 	"github.com/superseriousbusiness/gotosocial/internal/transport"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/web"
 )
 
-var models []interface{} = []interface{}{
-	&gtsmodel.Account{},
-	&gtsmodel.Application{},
-	&gtsmodel.Block{},
-	&gtsmodel.DomainBlock{},
-	&gtsmodel.EmailDomainBlock{},
-	&gtsmodel.Follow{},
-	&gtsmodel.FollowRequest{},
-	&gtsmodel.MediaAttachment{},
-	&gtsmodel.Mention{},
-	&gtsmodel.Status{},
-	&gtsmodel.StatusFave{},
-	&gtsmodel.StatusBookmark{},
-	&gtsmodel.StatusMute{},
-	&gtsmodel.Tag{},
-	&gtsmodel.User{},
-	&gtsmodel.Emoji{},
-	&gtsmodel.Instance{},
-	&gtsmodel.Notification{},
-	&gtsmodel.RouterSession{},
-	&oauth.Token{},
-	&oauth.Client{},
-}
-
 // Start creates and starts a gotosocial server
 var Start cliactions.GTSAction = func(ctx context.Context, c *config.Config, log *logrus.Logger) error {
-	dbService, err := pg.NewPostgresService(ctx, c, log)
+	shutdownTracing, err := tracing.Initialize(ctx, c)
+	if err != nil {
+		return fmt.Errorf("error initializing tracing: %s", err)
+	}
+
+	dbService, err := dbconn.NewService(ctx, c, log)
 	if err != nil {
 		return fmt.Errorf("error creating dbservice: %s", err)
 	}
 
-	for _, m := range models {
-		if err := dbService.CreateTable(m); err != nil {
-			return fmt.Errorf("table creation error: %s", err)
-		}
+	if _, err := dbService.Migrate(ctx, migrations.All()); err != nil {
+		return fmt.Errorf("error running migrations: %s", err)
 	}
 
-	if err := dbService.CreateInstanceAccount(); err != nil {
+	if err := dbService.CreateInstanceAccount(ctx); err != nil {
 		return fmt.Errorf("error creating instance account: %s", err)
 	}
 
-	if err := dbService.CreateInstanceInstance(); err != nil {
+	if err := dbService.CreateInstanceInstance(ctx); err != nil {
 		return fmt.Errorf("error creating instance instance: %s", err)
 	}
 
 	federatingDB := federatingdb.New(dbService, c, log)
 
-	router, err := router.New(c, dbService, log)
+	router, err := router.New(ctx, c, dbService, log)
 	if err != nil {
 		return fmt.Errorf("error creating router: %s", err)
 	}
 
-	storageBackend, err := blob.NewLocal(c, log)
+	storageBackend, err := blob.New(c, log)
 	if err != nil {
 		return fmt.Errorf("error creating storage backend: %s", err)
 	}
 
 	// build converters and util
-	typeConverter := typeutils.NewConverter(c, dbService)
+	typeConverter := typeutils.NewConverter(c, dbService, storageBackend)
 	timelineManager := timelineprocessing.NewManager(dbService, typeConverter, c, log)
 
 	// build backend handlers
 	mediaHandler := media.New(c, dbService, storageBackend, log)
 	oauthServer := oauth.New(dbService, log)
-	transportController := transport.NewController(c, &federation.Clock{}, http.DefaultClient, log)
+	transportController := transport.NewController(c, &federation.Clock{}, tracing.HTTPClient(c), log)
 	federator := federation.NewFederator(dbService, federatingDB, transportController, c, log, typeConverter, mediaHandler)
 	processor := processing.NewProcessor(c, typeConverter, federator, oauthServer, mediaHandler, storageBackend, timelineManager, dbService, log)
-	if err := processor.Start(); err != nil {
+	if err := processor.Start(ctx); err != nil {
 		return fmt.Errorf("error starting processor: %s", err)
 	}
+	if err := federator.Start(ctx); err != nil {
+		return fmt.Errorf("error starting federator: %s", err)
+	}
 
 	idp, err := oidc.NewIDP(c, log)
 	if err != nil {
@@ -143,6 +132,7 @@ var Start cliactions.GTSAction = func(ctx context.Context, c *config.Config, log
 	filtersModule := filter.New(c, processor, log)
 	emojiModule := emoji.New(c, processor, log)
 	listsModule := list.New(c, processor, log)
+	pollsModule := poll.New(c, processor, log)
 	mm := mediaModule.New(c, processor, log)
 	fileServerModule := fileserver.New(c, processor, log)
 	adminModule := admin.New(c, processor, log)
@@ -151,6 +141,12 @@ var Start cliactions.GTSAction = func(ctx context.Context, c *config.Config, log
 	streamingModule := streaming.New(c, processor, log)
 	favouritesModule := favourites.New(c, processor, log)
 	blocksModule := blocks.New(c, processor, log)
+	bookmarksModule := bookmarks.New(c, processor, log)
+	mutesModule := mutes.New(c, processor, log)
+	scheduledStatusModule := scheduledstatus.New(c, processor, log)
+	reportModule := report.New(c, processor, log)
+	conversationModule := conversation.New(c, processor, log)
+	tagModule := tag.New(c, processor, log)
 
 	apis := []api.ClientModule{
 		// modules with middleware go first
@@ -176,9 +172,16 @@ var Start cliactions.GTSAction = func(ctx context.Context, c *config.Config, log
 		filtersModule,
 		emojiModule,
 		listsModule,
+		pollsModule,
 		streamingModule,
 		favouritesModule,
 		blocksModule,
+		bookmarksModule,
+		mutesModule,
+		scheduledStatusModule,
+		reportModule,
+		conversationModule,
+		tagModule,
 	}
 
 	for _, m := range apis {
@@ -187,7 +190,7 @@ var Start cliactions.GTSAction = func(ctx context.Context, c *config.Config, log
 		}
 	}
 
-	gts, err := gotosocial.NewServer(dbService, router, federator, c)
+	gts, err := gotosocial.NewServer(dbService, router, federator, processor, c)
 	if err != nil {
 		return fmt.Errorf("error creating gotosocial service: %s", err)
 	}
@@ -207,6 +210,10 @@ var Start cliactions.GTSAction = func(ctx context.Context, c *config.Config, log
 		return fmt.Errorf("error closing gotosocial service: %s", err)
 	}
 
+	if err := shutdownTracing(ctx); err != nil {
+		return fmt.Errorf("error shutting down tracing: %s", err)
+	}
+
 	log.Info("done! exiting...")
 	return nil
 }
@@ -17,6 +17,8 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/app"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/auth"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/blocks"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/bookmarks"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/conversation"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/emoji"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/favourites"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/fileserver"
@@ -25,10 +27,15 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/instance"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/list"
 	mediaModule "github.com/superseriousbusiness/gotosocial/internal/api/client/media"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/mutes"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/notification"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/poll"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/report"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/scheduledstatus"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/search"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/status"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/streaming"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/tag"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/timeline"
 	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/nodeinfo"
 	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/user"
@@ -63,9 +70,12 @@ var Start cliactions.GTSAction = func(ctx context.Context, _ *config.Config, log
 	federator := testrig.NewTestFederator(dbService, transportController, storageBackend)
 
 	processor := testrig.NewTestProcessor(dbService, storageBackend, federator)
-	if err := processor.Start(); err != nil {
+	if err := processor.Start(ctx); err != nil {
 		return fmt.Errorf("error starting processor: %s", err)
 	}
+	if err := federator.Start(ctx); err != nil {
+		return fmt.Errorf("error starting federator: %s", err)
+	}
 
 	idp, err := oidc.NewIDP(c, log)
 	if err != nil {
@@ -88,6 +98,7 @@ var Start cliactions.GTSAction = func(ctx context.Context, _ *config.Config, log
 	filtersModule := filter.New(c, processor, log)
 	emojiModule := emoji.New(c, processor, log)
 	listsModule := list.New(c, processor, log)
+	pollsModule := poll.New(c, processor, log)
 	mm := mediaModule.New(c, processor, log)
 	fileServerModule := fileserver.New(c, processor, log)
 	adminModule := admin.New(c, processor, log)
@@ -96,6 +107,12 @@ var Start cliactions.GTSAction = func(ctx context.Context, _ *config.Config, log
 	streamingModule := streaming.New(c, processor, log)
 	favouritesModule := favourites.New(c, processor, log)
 	blocksModule := blocks.New(c, processor, log)
+	bookmarksModule := bookmarks.New(c, processor, log)
+	mutesModule := mutes.New(c, processor, log)
+	scheduledStatusModule := scheduledstatus.New(c, processor, log)
+	reportModule := report.New(c, processor, log)
+	conversationModule := conversation.New(c, processor, log)
+	tagModule := tag.New(c, processor, log)
 
 	apis := []api.ClientModule{
 		// modules with middleware go first
@@ -121,9 +138,16 @@ var Start cliactions.GTSAction = func(ctx context.Context, _ *config.Config, log
 		filtersModule,
 		emojiModule,
 		listsModule,
+		pollsModule,
 		streamingModule,
 		favouritesModule,
 		blocksModule,
+		bookmarksModule,
+		mutesModule,
+		scheduledStatusModule,
+		reportModule,
+		conversationModule,
+		tagModule,
 	}
 
 	for _, m := range apis {
@@ -132,7 +156,7 @@ var Start cliactions.GTSAction = func(ctx context.Context, _ *config.Config, log
 		}
 	}
 
-	gts, err := gotosocial.NewServer(dbService, router, federator, c)
+	gts, err := gotosocial.NewServer(dbService, router, federator, processor, c)
 	if err != nil {
 		return fmt.Errorf("error creating gotosocial service: %s", err)
 	}
@@ -36,6 +36,9 @@ const (
 
 	PasswordFlag  = "password"
 	PasswordUsage = "the password to set for this account"
+
+	DryRunFlag  = "dry-run"
+	DryRunUsage = "only report what would be removed, without removing anything"
 )
 
 // Config pulls together all the configuration needed to run gotosocial
@@ -58,11 +61,14 @@ type Config struct {
 	StatusesConfig    *StatusesConfig    `yaml:"statuses"`
 	LetsEncryptConfig *LetsEncryptConfig `yaml:"letsEncrypt"`
 	OIDCConfig        *OIDCConfig        `yaml:"oidc"`
+	MetricsConfig     *MetricsConfig     `yaml:"metrics"`
+	TracingConfig     *TracingConfig     `yaml:"tracing"`
 
 	/*
 		Not parsed from .yaml configuration file.
 	*/
 	AccountCLIFlags map[string]string
+	MediaCLIFlags   map[string]bool
 	SoftwareVersion string
 }
 
@@ -89,7 +95,10 @@ func Empty() *Config {
 		StatusesConfig:    &StatusesConfig{},
 		LetsEncryptConfig: &LetsEncryptConfig{},
 		OIDCConfig:        &OIDCConfig{},
+		MetricsConfig:     &MetricsConfig{},
+		TracingConfig:     &TracingConfig{},
 		AccountCLIFlags:   make(map[string]string),
+		MediaCLIFlags:     make(map[string]bool),
 	}
 }
 
@@ -183,6 +192,10 @@ func (c *Config) ParseCLIFlags(f KeyedFlags, version string) error {
 		c.DBConfig.TLSCACert = f.String(fn.DbTLSCACert)
 	}
 
+	if c.DBConfig.QueryTimeout == 0 || f.IsSet(fn.DbQueryTimeout) {
+		c.DBConfig.QueryTimeout = f.Int(fn.DbQueryTimeout)
+	}
+
 	// template flags
 	if c.TemplateConfig.BaseDir == "" || f.IsSet(fn.TemplateBaseDir) {
 		c.TemplateConfig.BaseDir = f.String(fn.TemplateBaseDir)
@@ -211,6 +224,10 @@ func (c *Config) ParseCLIFlags(f KeyedFlags, version string) error {
 		c.MediaConfig.MaxVideoSize = f.Int(fn.MediaMaxVideoSize)
 	}
 
+	if c.MediaConfig.MaxImageDimension == 0 || f.IsSet(fn.MediaMaxImageDimension) {
+		c.MediaConfig.MaxImageDimension = f.Int(fn.MediaMaxImageDimension)
+	}
+
 	if c.MediaConfig.MinDescriptionChars == 0 || f.IsSet(fn.MediaMinDescriptionChars) {
 		c.MediaConfig.MinDescriptionChars = f.Int(fn.MediaMinDescriptionChars)
 	}
@@ -219,6 +236,14 @@ func (c *Config) ParseCLIFlags(f KeyedFlags, version string) error {
 		c.MediaConfig.MaxDescriptionChars = f.Int(fn.MediaMaxDescriptionChars)
 	}
 
+	if c.MediaConfig.RemoteCacheDays == 0 || f.IsSet(fn.MediaRemoteCacheDays) {
+		c.MediaConfig.RemoteCacheDays = f.Int(fn.MediaRemoteCacheDays)
+	}
+
+	if c.MediaConfig.CleanupGraceHours == 0 || f.IsSet(fn.MediaCleanupGraceHours) {
+		c.MediaConfig.CleanupGraceHours = f.Int(fn.MediaCleanupGraceHours)
+	}
+
 	// storage flags
 	if c.StorageConfig.Backend == "" || f.IsSet(fn.StorageBackend) {
 		c.StorageConfig.Backend = f.String(fn.StorageBackend)
@@ -240,6 +265,38 @@ func (c *Config) ParseCLIFlags(f KeyedFlags, version string) error {
 		c.StorageConfig.ServeBasePath = f.String(fn.StorageServeBasePath)
 	}
 
+	if c.StorageConfig.S3Endpoint == "" || f.IsSet(fn.StorageS3Endpoint) {
+		c.StorageConfig.S3Endpoint = f.String(fn.StorageS3Endpoint)
+	}
+
+	if c.StorageConfig.S3Region == "" || f.IsSet(fn.StorageS3Region) {
+		c.StorageConfig.S3Region = f.String(fn.StorageS3Region)
+	}
+
+	if c.StorageConfig.S3Bucket == "" || f.IsSet(fn.StorageS3Bucket) {
+		c.StorageConfig.S3Bucket = f.String(fn.StorageS3Bucket)
+	}
+
+	if c.StorageConfig.S3AccessKey == "" || f.IsSet(fn.StorageS3AccessKey) {
+		c.StorageConfig.S3AccessKey = f.String(fn.StorageS3AccessKey)
+	}
+
+	if c.StorageConfig.S3SecretKey == "" || f.IsSet(fn.StorageS3SecretKey) {
+		c.StorageConfig.S3SecretKey = f.String(fn.StorageS3SecretKey)
+	}
+
+	if f.IsSet(fn.StorageS3Insecure) {
+		c.StorageConfig.S3Insecure = f.Bool(fn.StorageS3Insecure)
+	}
+
+	if f.IsSet(fn.StorageS3PathStyle) {
+		c.StorageConfig.S3PathStyle = f.Bool(fn.StorageS3PathStyle)
+	}
+
+	if c.StorageConfig.S3ServeMode == "" || f.IsSet(fn.StorageS3ServeMode) {
+		c.StorageConfig.S3ServeMode = f.String(fn.StorageS3ServeMode)
+	}
+
 	// statuses flags
 	if c.StatusesConfig.MaxChars == 0 || f.IsSet(fn.StatusesMaxChars) {
 		c.StatusesConfig.MaxChars = f.Int(fn.StatusesMaxChars)
@@ -256,6 +313,9 @@ func (c *Config) ParseCLIFlags(f KeyedFlags, version string) error {
 	if c.StatusesConfig.MaxMediaFiles == 0 || f.IsSet(fn.StatusesMaxMediaFiles) {
 		c.StatusesConfig.MaxMediaFiles = f.Int(fn.StatusesMaxMediaFiles)
 	}
+	if c.StatusesConfig.MaxPinned == 0 || f.IsSet(fn.StatusesMaxPinned) {
+		c.StatusesConfig.MaxPinned = f.Int(fn.StatusesMaxPinned)
+	}
 
 	// letsencrypt flags
 	if f.IsSet(fn.LetsEncryptEnabled) {
@@ -299,6 +359,44 @@ func (c *Config) ParseCLIFlags(f KeyedFlags, version string) error {
 		c.OIDCConfig.Scopes = f.StringSlice(fn.OIDCScopes)
 	}
 
+	// metrics flags
+	if f.IsSet(fn.MetricsEnabled) {
+		c.MetricsConfig.Enabled = f.Bool(fn.MetricsEnabled)
+	}
+
+	if f.IsSet(fn.MetricsAuthEnabled) {
+		c.MetricsConfig.AuthEnabled = f.Bool(fn.MetricsAuthEnabled)
+	}
+
+	if c.MetricsConfig.AuthUsername == "" || f.IsSet(fn.MetricsAuthUsername) {
+		c.MetricsConfig.AuthUsername = f.String(fn.MetricsAuthUsername)
+	}
+
+	if c.MetricsConfig.AuthPassword == "" || f.IsSet(fn.MetricsAuthPassword) {
+		c.MetricsConfig.AuthPassword = f.String(fn.MetricsAuthPassword)
+	}
+
+	// tracing flags
+	if f.IsSet(fn.TracingEnabled) {
+		c.TracingConfig.Enabled = f.Bool(fn.TracingEnabled)
+	}
+
+	if c.TracingConfig.Exporter == "" || f.IsSet(fn.TracingExporter) {
+		c.TracingConfig.Exporter = f.String(fn.TracingExporter)
+	}
+
+	if c.TracingConfig.Endpoint == "" || f.IsSet(fn.TracingEndpoint) {
+		c.TracingConfig.Endpoint = f.String(fn.TracingEndpoint)
+	}
+
+	if f.IsSet(fn.TracingInsecure) {
+		c.TracingConfig.Insecure = f.Bool(fn.TracingInsecure)
+	}
+
+	if c.TracingConfig.FilePath == "" || f.IsSet(fn.TracingFilePath) {
+		c.TracingConfig.FilePath = f.String(fn.TracingFilePath)
+	}
+
 	// command-specific flags
 
 	// admin account CLI flags
@@ -306,6 +404,9 @@ func (c *Config) ParseCLIFlags(f KeyedFlags, version string) error {
 	c.AccountCLIFlags[EmailFlag] = f.String(EmailFlag)
 	c.AccountCLIFlags[PasswordFlag] = f.String(PasswordFlag)
 
+	// admin media CLI flags
+	c.MediaCLIFlags[DryRunFlag] = f.Bool(DryRunFlag)
+
 	c.SoftwareVersion = version
 	return nil
 }
@@ -330,14 +431,15 @@ type Flags struct {
 	AccountDomain   string
 	Protocol        string
 
-	DbType      string
-	DbAddress   string
-	DbPort      string
-	DbUser      string
-	DbPassword  string
-	DbDatabase  string
-	DbTLSMode   string
-	DbTLSCACert string
+	DbType         string
+	DbAddress      string
+	DbPort         string
+	DbUser         string
+	DbPassword     string
+	DbDatabase     string
+	DbTLSMode      string
+	DbTLSCACert    string
+	DbQueryTimeout string
 
 	TemplateBaseDir string
 	AssetBaseDir    string
@@ -348,20 +450,32 @@ type Flags struct {
 
 	MediaMaxImageSize        string
 	MediaMaxVideoSize        string
+	MediaMaxImageDimension   string
 	MediaMinDescriptionChars string
 	MediaMaxDescriptionChars string
+	MediaRemoteCacheDays     string
+	MediaCleanupGraceHours   string
 
 	StorageBackend       string
 	StorageBasePath      string
 	StorageServeProtocol string
 	StorageServeHost     string
 	StorageServeBasePath string
+	StorageS3Endpoint    string
+	StorageS3Region      string
+	StorageS3Bucket      string
+	StorageS3AccessKey   string
+	StorageS3SecretKey   string
+	StorageS3Insecure    string
+	StorageS3PathStyle   string
+	StorageS3ServeMode   string
 
 	StatusesMaxChars           string
 	StatusesCWMaxChars         string
 	StatusesPollMaxOptions     string
 	StatusesPollOptionMaxChars string
 	StatusesMaxMediaFiles      string
+	StatusesMaxPinned          string
 
 	LetsEncryptEnabled      string
 	LetsEncryptCertDir      string
@@ -374,6 +488,17 @@ type Flags struct {
 	OIDCClientID         string
 	OIDCClientSecret     string
 	OIDCScopes           string
+
+	MetricsEnabled      string
+	MetricsAuthEnabled  string
+	MetricsAuthUsername string
+	MetricsAuthPassword string
+
+	TracingEnabled  string
+	TracingExporter string
+	TracingEndpoint string
+	TracingInsecure string
+	TracingFilePath string
 }
 
 // Defaults contains all the default values for a gotosocial config
@@ -386,14 +511,15 @@ type Defaults struct {
 	Protocol        string
 	SoftwareVersion string
 
-	DbType      string
-	DbAddress   string
-	DbPort      int
-	DbUser      string
-	DbPassword  string
-	DbDatabase  string
-	DBTlsMode   string
-	DBTlsCACert string
+	DbType         string
+	DbAddress      string
+	DbPort         int
+	DbUser         string
+	DbPassword     string
+	DbDatabase     string
+	DBTlsMode      string
+	DBTlsCACert    string
+	DbQueryTimeout int
 
 	TemplateBaseDir string
 	AssetBaseDir    string
@@ -404,20 +530,32 @@ type Defaults struct {
 
 	MediaMaxImageSize        int
 	MediaMaxVideoSize        int
+	MediaMaxImageDimension   int
 	MediaMinDescriptionChars int
 	MediaMaxDescriptionChars int
+	MediaRemoteCacheDays     int
+	MediaCleanupGraceHours   int
 
 	StorageBackend       string
 	StorageBasePath      string
 	StorageServeProtocol string
 	StorageServeHost     string
 	StorageServeBasePath string
+	StorageS3Endpoint    string
+	StorageS3Region      string
+	StorageS3Bucket      string
+	StorageS3AccessKey   string
+	StorageS3SecretKey   string
+	StorageS3Insecure    bool
+	StorageS3PathStyle   bool
+	StorageS3ServeMode   string
 
 	StatusesMaxChars           int
 	StatusesCWMaxChars         int
 	StatusesPollMaxOptions     int
 	StatusesPollOptionMaxChars int
 	StatusesMaxMediaFiles      int
+	StatusesMaxPinned          int
 
 	LetsEncryptEnabled      bool
 	LetsEncryptCertDir      string
@@ -430,6 +568,17 @@ type Defaults struct {
 	OIDCClientID         string
 	OIDCClientSecret     string
 	OIDCScopes           []string
+
+	MetricsEnabled      bool
+	MetricsAuthEnabled  bool
+	MetricsAuthUsername string
+	MetricsAuthPassword string
+
+	TracingEnabled  bool
+	TracingExporter string
+	TracingEndpoint string
+	TracingInsecure bool
+	TracingFilePath string
 }
 
 // GetFlagNames returns a struct containing the names of the various flags used for
@@ -443,14 +592,15 @@ func GetFlagNames() Flags {
 		AccountDomain:   "account-domain",
 		Protocol:        "protocol",
 
-		DbType:      "db-type",
-		DbAddress:   "db-address",
-		DbPort:      "db-port",
-		DbUser:      "db-user",
-		DbPassword:  "db-password",
-		DbDatabase:  "db-database",
-		DbTLSMode:   "db-tls-mode",
-		DbTLSCACert: "db-tls-ca-cert",
+		DbType:         "db-type",
+		DbAddress:      "db-address",
+		DbPort:         "db-port",
+		DbUser:         "db-user",
+		DbPassword:     "db-password",
+		DbDatabase:     "db-database",
+		DbTLSMode:      "db-tls-mode",
+		DbTLSCACert:    "db-tls-ca-cert",
+		DbQueryTimeout: "db-query-timeout",
 
 		TemplateBaseDir: "template-basedir",
 		AssetBaseDir:    "asset-basedir",
@@ -461,20 +611,32 @@ func GetFlagNames() Flags {
 
 		MediaMaxImageSize:        "media-max-image-size",
 		MediaMaxVideoSize:        "media-max-video-size",
+		MediaMaxImageDimension:   "media-max-image-dimension",
 		MediaMinDescriptionChars: "media-min-description-chars",
 		MediaMaxDescriptionChars: "media-max-description-chars",
+		MediaRemoteCacheDays:     "media-remote-cache-days",
+		MediaCleanupGraceHours:   "media-cleanup-grace-hours",
 
 		StorageBackend:       "storage-backend",
 		StorageBasePath:      "storage-base-path",
 		StorageServeProtocol: "storage-serve-protocol",
 		StorageServeHost:     "storage-serve-host",
 		StorageServeBasePath: "storage-serve-base-path",
+		StorageS3Endpoint:    "storage-s3-endpoint",
+		StorageS3Region:      "storage-s3-region",
+		StorageS3Bucket:      "storage-s3-bucket",
+		StorageS3AccessKey:   "storage-s3-access-key",
+		StorageS3SecretKey:   "storage-s3-secret-key",
+		StorageS3Insecure:    "storage-s3-insecure",
+		StorageS3PathStyle:   "storage-s3-path-style",
+		StorageS3ServeMode:   "storage-s3-serve-mode",
 
 		StatusesMaxChars:           "statuses-max-chars",
 		StatusesCWMaxChars:         "statuses-cw-max-chars",
 		StatusesPollMaxOptions:     "statuses-poll-max-options",
 		StatusesPollOptionMaxChars: "statuses-poll-option-max-chars",
 		StatusesMaxMediaFiles:      "statuses-max-media-files",
+		StatusesMaxPinned:          "statuses-max-pinned",
 
 		LetsEncryptEnabled:      "letsencrypt-enabled",
 		LetsEncryptCertDir:      "letsencrypt-cert-dir",
@@ -487,6 +649,17 @@ func GetFlagNames() Flags {
 		OIDCClientID:         "oidc-client-id",
 		OIDCClientSecret:     "oidc-client-secret",
 		OIDCScopes:           "oidc-scopes",
+
+		MetricsEnabled:      "metrics-enabled",
+		MetricsAuthEnabled:  "metrics-auth-enabled",
+		MetricsAuthUsername: "metrics-auth-username",
+		MetricsAuthPassword: "metrics-auth-password",
+
+		TracingEnabled:  "tracing-enabled",
+		TracingExporter: "tracing-exporter",
+		TracingEndpoint: "tracing-endpoint",
+		TracingInsecure: "tracing-insecure",
+		TracingFilePath: "tracing-file-path",
 	}
 }
 
@@ -501,14 +674,15 @@ func GetEnvNames() Flags {
 		AccountDomain:   "GTS_ACCOUNT_DOMAIN",
 		Protocol:        "GTS_PROTOCOL",
 
-		DbType:      "GTS_DB_TYPE",
-		DbAddress:   "GTS_DB_ADDRESS",
-		DbPort:      "GTS_DB_PORT",
-		DbUser:      "GTS_DB_USER",
-		DbPassword:  "GTS_DB_PASSWORD",
-		DbDatabase:  "GTS_DB_DATABASE",
-		DbTLSMode:   "GTS_DB_TLS_MODE",
-		DbTLSCACert: "GTS_DB_CA_CERT",
+		DbType:         "GTS_DB_TYPE",
+		DbAddress:      "GTS_DB_ADDRESS",
+		DbPort:         "GTS_DB_PORT",
+		DbUser:         "GTS_DB_USER",
+		DbPassword:     "GTS_DB_PASSWORD",
+		DbDatabase:     "GTS_DB_DATABASE",
+		DbTLSMode:      "GTS_DB_TLS_MODE",
+		DbTLSCACert:    "GTS_DB_CA_CERT",
+		DbQueryTimeout: "GTS_DB_QUERY_TIMEOUT",
 
 		TemplateBaseDir: "GTS_TEMPLATE_BASEDIR",
 		AssetBaseDir:    "GTS_ASSET_BASEDIR",
@@ -519,20 +693,32 @@ func GetEnvNames() Flags {
 
 		MediaMaxImageSize:        "GTS_MEDIA_MAX_IMAGE_SIZE",
 		MediaMaxVideoSize:        "GTS_MEDIA_MAX_VIDEO_SIZE",
+		MediaMaxImageDimension:   "GTS_MEDIA_MAX_IMAGE_DIMENSION",
 		MediaMinDescriptionChars: "GTS_MEDIA_MIN_DESCRIPTION_CHARS",
 		MediaMaxDescriptionChars: "GTS_MEDIA_MAX_DESCRIPTION_CHARS",
+		MediaRemoteCacheDays:     "GTS_MEDIA_REMOTE_CACHE_DAYS",
+		MediaCleanupGraceHours:   "GTS_MEDIA_CLEANUP_GRACE_HOURS",
 
 		StorageBackend:       "GTS_STORAGE_BACKEND",
 		StorageBasePath:      "GTS_STORAGE_BASE_PATH",
 		StorageServeProtocol: "GTS_STORAGE_SERVE_PROTOCOL",
 		StorageServeHost:     "GTS_STORAGE_SERVE_HOST",
 		StorageServeBasePath: "GTS_STORAGE_SERVE_BASE_PATH",
+		StorageS3Endpoint:    "GTS_STORAGE_S3_ENDPOINT",
+		StorageS3Region:      "GTS_STORAGE_S3_REGION",
+		StorageS3Bucket:      "GTS_STORAGE_S3_BUCKET",
+		StorageS3AccessKey:   "GTS_STORAGE_S3_ACCESS_KEY",
+		StorageS3SecretKey:   "GTS_STORAGE_S3_SECRET_KEY",
+		StorageS3Insecure:    "GTS_STORAGE_S3_INSECURE",
+		StorageS3PathStyle:   "GTS_STORAGE_S3_PATH_STYLE",
+		StorageS3ServeMode:   "GTS_STORAGE_S3_SERVE_MODE",
 
 		StatusesMaxChars:           "GTS_STATUSES_MAX_CHARS",
 		StatusesCWMaxChars:         "GTS_STATUSES_CW_MAX_CHARS",
 		StatusesPollMaxOptions:     "GTS_STATUSES_POLL_MAX_OPTIONS",
 		StatusesPollOptionMaxChars: "GTS_STATUSES_POLL_OPTION_MAX_CHARS",
 		StatusesMaxMediaFiles:      "GTS_STATUSES_MAX_MEDIA_FILES",
+		StatusesMaxPinned:          "GTS_STATUSES_MAX_PINNED",
 
 		LetsEncryptEnabled:      "GTS_LETSENCRYPT_ENABLED",
 		LetsEncryptCertDir:      "GTS_LETSENCRYPT_CERT_DIR",
@@ -545,5 +731,16 @@ func GetEnvNames() Flags {
 		OIDCClientID:         "GTS_OIDC_CLIENT_ID",
 		OIDCClientSecret:     "GTS_OIDC_CLIENT_SECRET",
 		OIDCScopes:           "GTS_OIDC_SCOPES",
+
+		MetricsEnabled:      "GTS_METRICS_ENABLED",
+		MetricsAuthEnabled:  "GTS_METRICS_AUTH_ENABLED",
+		MetricsAuthUsername: "GTS_METRICS_AUTH_USERNAME",
+		MetricsAuthPassword: "GTS_METRICS_AUTH_PASSWORD",
+
+		TracingEnabled:  "GTS_TRACING_ENABLED",
+		TracingExporter: "GTS_TRACING_EXPORTER",
+		TracingEndpoint: "GTS_TRACING_ENDPOINT",
+		TracingInsecure: "GTS_TRACING_INSECURE",
+		TracingFilePath: "GTS_TRACING_FILE_PATH",
 	}
 }
@@ -29,6 +29,7 @@ type DBConfig struct {
 	ApplicationName string    `yaml:"applicationName"`
 	TLSMode         DBTLSMode `yaml:"tlsMode"`
 	TLSCACert       string    `yaml:"tlsCACert"`
+	QueryTimeout    int       `yaml:"queryTimeout"`
 }
 
 // DBTLSMode describes a mode of connecting to a database with or without TLS.
@@ -19,6 +19,7 @@ func TestDefault() *Config {
 			Password:        defaults.DbPassword,
 			Database:        defaults.DbDatabase,
 			ApplicationName: defaults.ApplicationName,
+			QueryTimeout:    defaults.DbQueryTimeout,
 		},
 		TemplateConfig: &TemplateConfig{
 			BaseDir:      defaults.TemplateBaseDir,
@@ -32,8 +33,11 @@ func TestDefault() *Config {
 		MediaConfig: &MediaConfig{
 			MaxImageSize:        defaults.MediaMaxImageSize,
 			MaxVideoSize:        defaults.MediaMaxVideoSize,
+			MaxImageDimension:   defaults.MediaMaxImageDimension,
 			MinDescriptionChars: defaults.MediaMinDescriptionChars,
 			MaxDescriptionChars: defaults.MediaMaxDescriptionChars,
+			RemoteCacheDays:     defaults.MediaRemoteCacheDays,
+			CleanupGraceHours:   defaults.MediaCleanupGraceHours,
 		},
 		StorageConfig: &StorageConfig{
 			Backend:       defaults.StorageBackend,
@@ -41,6 +45,14 @@ func TestDefault() *Config {
 			ServeProtocol: defaults.StorageServeProtocol,
 			ServeHost:     defaults.StorageServeHost,
 			ServeBasePath: defaults.StorageServeBasePath,
+			S3Endpoint:    defaults.StorageS3Endpoint,
+			S3Region:      defaults.StorageS3Region,
+			S3Bucket:      defaults.StorageS3Bucket,
+			S3AccessKey:   defaults.StorageS3AccessKey,
+			S3SecretKey:   defaults.StorageS3SecretKey,
+			S3Insecure:    defaults.StorageS3Insecure,
+			S3PathStyle:   defaults.StorageS3PathStyle,
+			S3ServeMode:   defaults.StorageS3ServeMode,
 		},
 		StatusesConfig: &StatusesConfig{
 			MaxChars:           defaults.StatusesMaxChars,
@@ -48,6 +60,7 @@ func TestDefault() *Config {
 			PollMaxOptions:     defaults.StatusesPollMaxOptions,
 			PollOptionMaxChars: defaults.StatusesPollOptionMaxChars,
 			MaxMediaFiles:      defaults.StatusesMaxMediaFiles,
+			MaxPinned:          defaults.StatusesMaxPinned,
 		},
 		LetsEncryptConfig: &LetsEncryptConfig{
 			Enabled:      defaults.LetsEncryptEnabled,
@@ -63,6 +76,19 @@ func TestDefault() *Config {
 			ClientSecret:     defaults.OIDCClientSecret,
 			Scopes:           defaults.OIDCScopes,
 		},
+		MetricsConfig: &MetricsConfig{
+			Enabled:      defaults.MetricsEnabled,
+			AuthEnabled:  defaults.MetricsAuthEnabled,
+			AuthUsername: defaults.MetricsAuthUsername,
+			AuthPassword: defaults.MetricsAuthPassword,
+		},
+		TracingConfig: &TracingConfig{
+			Enabled:  defaults.TracingEnabled,
+			Exporter: defaults.TracingExporter,
+			Endpoint: defaults.TracingEndpoint,
+			Insecure: defaults.TracingInsecure,
+			FilePath: defaults.TracingFilePath,
+		},
 	}
 }
 
@@ -83,6 +109,7 @@ func Default() *Config {
 			Password:        defaults.DbPassword,
 			Database:        defaults.DbDatabase,
 			ApplicationName: defaults.ApplicationName,
+			QueryTimeout:    defaults.DbQueryTimeout,
 		},
 		TemplateConfig: &TemplateConfig{
 			BaseDir:      defaults.TemplateBaseDir,
@@ -96,8 +123,11 @@ func Default() *Config {
 		MediaConfig: &MediaConfig{
 			MaxImageSize:        defaults.MediaMaxImageSize,
 			MaxVideoSize:        defaults.MediaMaxVideoSize,
+			MaxImageDimension:   defaults.MediaMaxImageDimension,
 			MinDescriptionChars: defaults.MediaMinDescriptionChars,
 			MaxDescriptionChars: defaults.MediaMaxDescriptionChars,
+			RemoteCacheDays:     defaults.MediaRemoteCacheDays,
+			CleanupGraceHours:   defaults.MediaCleanupGraceHours,
 		},
 		StorageConfig: &StorageConfig{
 			Backend:       defaults.StorageBackend,
@@ -105,6 +135,14 @@ func Default() *Config {
 			ServeProtocol: defaults.StorageServeProtocol,
 			ServeHost:     defaults.StorageServeHost,
 			ServeBasePath: defaults.StorageServeBasePath,
+			S3Endpoint:    defaults.StorageS3Endpoint,
+			S3Region:      defaults.StorageS3Region,
+			S3Bucket:      defaults.StorageS3Bucket,
+			S3AccessKey:   defaults.StorageS3AccessKey,
+			S3SecretKey:   defaults.StorageS3SecretKey,
+			S3Insecure:    defaults.StorageS3Insecure,
+			S3PathStyle:   defaults.StorageS3PathStyle,
+			S3ServeMode:   defaults.StorageS3ServeMode,
 		},
 		StatusesConfig: &StatusesConfig{
 			MaxChars:           defaults.StatusesMaxChars,
@@ -112,6 +150,7 @@ func Default() *Config {
 			PollMaxOptions:     defaults.StatusesPollMaxOptions,
 			PollOptionMaxChars: defaults.StatusesPollOptionMaxChars,
 			MaxMediaFiles:      defaults.StatusesMaxMediaFiles,
+			MaxPinned:          defaults.StatusesMaxPinned,
 		},
 		LetsEncryptConfig: &LetsEncryptConfig{
 			Enabled:      defaults.LetsEncryptEnabled,
@@ -127,6 +166,19 @@ func Default() *Config {
 			ClientSecret:     defaults.OIDCClientSecret,
 			Scopes:           defaults.OIDCScopes,
 		},
+		MetricsConfig: &MetricsConfig{
+			Enabled:      defaults.MetricsEnabled,
+			AuthEnabled:  defaults.MetricsAuthEnabled,
+			AuthUsername: defaults.MetricsAuthUsername,
+			AuthPassword: defaults.MetricsAuthPassword,
+		},
+		TracingConfig: &TracingConfig{
+			Enabled:  defaults.TracingEnabled,
+			Exporter: defaults.TracingExporter,
+			Endpoint: defaults.TracingEndpoint,
+			Insecure: defaults.TracingInsecure,
+			FilePath: defaults.TracingFilePath,
+		},
 	}
 }
 
@@ -141,14 +193,15 @@ func GetDefaults() Defaults {
 		AccountDomain:   "",
 		Protocol:        "https",
 
-		DbType:      "postgres",
-		DbAddress:   "localhost",
-		DbPort:      5432,
-		DbUser:      "postgres",
-		DbPassword:  "postgres",
-		DbDatabase:  "postgres",
-		DBTlsMode:   "disable",
-		DBTlsCACert: "",
+		DbType:         "postgres",
+		DbAddress:      "localhost",
+		DbPort:         5432,
+		DbUser:         "postgres",
+		DbPassword:     "postgres",
+		DbDatabase:     "postgres",
+		DBTlsMode:      "disable",
+		DBTlsCACert:    "",
+		DbQueryTimeout: 30,
 
 		TemplateBaseDir: "./web/template/",
 		AssetBaseDir:    "./web/assets/",
@@ -159,20 +212,32 @@ func GetDefaults() Defaults {
 
 		MediaMaxImageSize:        2097152,  //2mb
 		MediaMaxVideoSize:        10485760, //10mb
+		MediaMaxImageDimension:   2560,
 		MediaMinDescriptionChars: 0,
 		MediaMaxDescriptionChars: 500,
+		MediaRemoteCacheDays:     30,
+		MediaCleanupGraceHours:   24,
 
 		StorageBackend:       "local",
 		StorageBasePath:      "/gotosocial/storage",
 		StorageServeProtocol: "https",
 		StorageServeHost:     "localhost",
 		StorageServeBasePath: "/fileserver",
+		StorageS3Endpoint:    "",
+		StorageS3Region:      "",
+		StorageS3Bucket:      "",
+		StorageS3AccessKey:   "",
+		StorageS3SecretKey:   "",
+		StorageS3Insecure:    false,
+		StorageS3PathStyle:   false,
+		StorageS3ServeMode:   "proxy",
 
 		StatusesMaxChars:           5000,
 		StatusesCWMaxChars:         100,
 		StatusesPollMaxOptions:     6,
 		StatusesPollOptionMaxChars: 50,
 		StatusesMaxMediaFiles:      6,
+		StatusesMaxPinned:          5,
 
 		LetsEncryptEnabled:      true,
 		LetsEncryptCertDir:      "/gotosocial/storage/certs",
@@ -185,6 +250,17 @@ func GetDefaults() Defaults {
 		OIDCClientID:         "",
 		OIDCClientSecret:     "",
 		OIDCScopes:           []string{oidc.ScopeOpenID, "profile", "email", "groups"},
+
+		MetricsEnabled:      false,
+		MetricsAuthEnabled:  false,
+		MetricsAuthUsername: "",
+		MetricsAuthPassword: "",
+
+		TracingEnabled:  false,
+		TracingExporter: "otlp",
+		TracingEndpoint: "localhost:4318",
+		TracingInsecure: false,
+		TracingFilePath: "./traces.json",
 	}
 }
 
@@ -198,12 +274,13 @@ func GetTestDefaults() Defaults {
 		AccountDomain:   "",
 		Protocol:        "http",
 
-		DbType:     "postgres",
-		DbAddress:  "localhost",
-		DbPort:     5432,
-		DbUser:     "postgres",
-		DbPassword: "postgres",
-		DbDatabase: "postgres",
+		DbType:         "postgres",
+		DbAddress:      "localhost",
+		DbPort:         5432,
+		DbUser:         "postgres",
+		DbPassword:     "postgres",
+		DbDatabase:     "postgres",
+		DbQueryTimeout: 30,
 
 		TemplateBaseDir: "./web/template/",
 		AssetBaseDir:    "./web/assets/",
@@ -214,20 +291,32 @@ func GetTestDefaults() Defaults {
 
 		MediaMaxImageSize:        1048576, //1mb
 		MediaMaxVideoSize:        5242880, //5mb
+		MediaMaxImageDimension:   2560,
 		MediaMinDescriptionChars: 0,
 		MediaMaxDescriptionChars: 500,
+		MediaRemoteCacheDays:     30,
+		MediaCleanupGraceHours:   24,
 
 		StorageBackend:       "local",
 		StorageBasePath:      "/gotosocial/storage",
 		StorageServeProtocol: "http",
 		StorageServeHost:     "localhost:8080",
 		StorageServeBasePath: "/fileserver",
+		StorageS3Endpoint:    "",
+		StorageS3Region:      "",
+		StorageS3Bucket:      "",
+		StorageS3AccessKey:   "",
+		StorageS3SecretKey:   "",
+		StorageS3Insecure:    false,
+		StorageS3PathStyle:   false,
+		StorageS3ServeMode:   "proxy",
 
 		StatusesMaxChars:           5000,
 		StatusesCWMaxChars:         100,
 		StatusesPollMaxOptions:     6,
 		StatusesPollOptionMaxChars: 50,
 		StatusesMaxMediaFiles:      6,
+		StatusesMaxPinned:          5,
 
 		LetsEncryptEnabled:      false,
 		LetsEncryptCertDir:      "",
@@ -240,5 +329,16 @@ func GetTestDefaults() Defaults {
 		OIDCClientID:         "",
 		OIDCClientSecret:     "",
 		OIDCScopes:           []string{oidc.ScopeOpenID, "profile", "email", "groups"},
+
+		MetricsEnabled:      false,
+		MetricsAuthEnabled:  false,
+		MetricsAuthUsername: "",
+		MetricsAuthPassword: "",
+
+		TracingEnabled:  false,
+		TracingExporter: "otlp",
+		TracingEndpoint: "localhost:4318",
+		TracingInsecure: false,
+		TracingFilePath: "./traces.json",
 	}
 }
@@ -24,8 +24,14 @@ type MediaConfig struct {
 	MaxImageSize int `yaml:"maxImageSize"`
 	// Max size of uploaded video in bytes
 	MaxVideoSize int `yaml:"maxVideoSize"`
+	// Max width or height of stored images in pixels; larger images will be scaled down to fit
+	MaxImageDimension int `yaml:"maxImageDimension"`
 	// Minimum amount of chars required in an image description
 	MinDescriptionChars int `yaml:"minDescriptionChars"`
 	// Max amount of chars allowed in an image description
 	MaxDescriptionChars int `yaml:"maxDescriptionChars"`
+	// Number of days to keep remote media cached in storage since it was last accessed
+	RemoteCacheDays int `yaml:"remoteCacheDays"`
+	// Number of hours that media can go unattached before it's cleaned up
+	CleanupGraceHours int `yaml:"cleanupGraceHours"`
 }
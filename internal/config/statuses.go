@@ -30,4 +30,6 @@ type StatusesConfig struct {
 	PollOptionMaxChars int `yaml:"poll_option_max_chars"`
 	// Maximum amount of media files allowed to be attached to one status
 	MaxMediaFiles int `yaml:"max_media_files"`
+	// Maximum amount of statuses an account may have pinned at once
+	MaxPinned int `yaml:"max_pinned"`
 }
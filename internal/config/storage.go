@@ -20,8 +20,7 @@ package config
 
 // StorageConfig contains configuration for storage and serving of media files and attachments
 type StorageConfig struct {
-	// Type of storage backend to use: currently only 'local' is supported.
-	// TODO: add S3 support here.
+	// Type of storage backend to use: 'local' or 's3'.
 	Backend string `yaml:"backend"`
 
 	// The base path for storing things. Should be an already-existing directory.
@@ -33,4 +32,22 @@ type StorageConfig struct {
 	ServeHost string `yaml:"serveHost"`
 	// Base path to use when *serving* media files from storage
 	ServeBasePath string `yaml:"serveBasePath"`
+
+	// Endpoint of the S3-compatible object storage to use, eg., s3.amazonaws.com or minio.example.org:9000. Only used for the 's3' backend.
+	S3Endpoint string `yaml:"s3Endpoint"`
+	// Region of the S3 bucket, eg., us-east-1. Can be left empty for most self-hosted object storage.
+	S3Region string `yaml:"s3Region"`
+	// Name of the S3 bucket to store things in. Should be an already-existing bucket.
+	S3Bucket string `yaml:"s3Bucket"`
+	// Access key to use for S3
+	S3AccessKey string `yaml:"s3AccessKey"`
+	// Secret key to use for S3
+	S3SecretKey string `yaml:"s3SecretKey"`
+	// Use plain http rather than https to connect to the S3 endpoint
+	S3Insecure bool `yaml:"s3Insecure"`
+	// Use path-style bucket urls (endpoint/bucket/key) rather than virtual-host-style urls (bucket.endpoint/key)
+	S3PathStyle bool `yaml:"s3PathStyle"`
+	// How to serve media stored in S3: 'proxy' to serve it through the gotosocial fileserver,
+	// 'presigned' to send clients to presigned urls, or 'public' to send clients to the public url of each object.
+	S3ServeMode string `yaml:"s3ServeMode"`
 }
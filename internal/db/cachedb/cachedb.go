@@ -0,0 +1,336 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package cachedb wraps a db.DB with an in-process cache of the things that get looked up over and over again,
+// like accounts, statuses and block and follow relationships, so that preparing a timeline doesn't hit the
+// database for every single account and status in it.
+package cachedb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/cache"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+const (
+	accountCacheSize      = 1000
+	statusCacheSize       = 5000
+	relationshipCacheSize = 10000
+	cacheTTL              = 5 * time.Minute
+
+	// statsInterval is how often the hit rates of the caches are logged
+	statsInterval = 10 * time.Minute
+)
+
+// Stater is implemented by a db.DB with caches, to report on how well the caches are doing.
+type Stater interface {
+	// CacheStats returns the stats of each cache, keyed by cache name.
+	CacheStats() map[string]cache.Stats
+}
+
+// New wraps the given db.DB with a caching layer.
+//
+// GetByID for accounts and statuses, as well as Blocked and Follows, will be served from the cache where possible.
+// Anything that changes accounts, statuses, blocks or follows through the returned db.DB invalidates the relevant
+// cache entries, so the underlying db.DB should not be written to directly once it's been wrapped.
+func New(conn db.DB, log *logrus.Logger) db.DB {
+	c := &cachingDB{
+		DB:            conn,
+		accounts:      cache.NewLRU(accountCacheSize, cacheTTL),
+		statuses:      cache.NewLRU(statusCacheSize, cacheTTL),
+		relationships: cache.NewLRU(relationshipCacheSize, cacheTTL),
+		log:           log,
+		stop:          make(chan struct{}),
+	}
+	go c.logStats()
+	return c
+}
+
+type cachingDB struct {
+	db.DB
+	accounts      cache.Cache
+	statuses      cache.Cache
+	relationships cache.Cache
+	log           *logrus.Logger
+	stop          chan struct{}
+	stopOnce      sync.Once
+}
+
+func (c *cachingDB) CacheStats() map[string]cache.Stats {
+	return map[string]cache.Stats{
+		"accounts":      c.accounts.Stats(),
+		"statuses":      c.statuses.Stats(),
+		"relationships": c.relationships.Stats(),
+	}
+}
+
+func (c *cachingDB) logStats() {
+	t := time.NewTicker(statsInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			stats := c.CacheStats()
+			names := make([]string, 0, len(stats))
+			for name := range stats {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				s := stats[name]
+				c.log.WithField("cache", name).Infof("hit rate %.2f (%d hits, %d misses, %d evictions, %d entries)", s.HitRate(), s.Hits, s.Misses, s.Evictions, s.Entries)
+			}
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// invalidate removes the cache entries for the model i with the given id. If id is empty,
+// the id of i is used; if that's empty too, every cached entry of the same type as i is removed.
+func (c *cachingDB) invalidate(id string, i interface{}) {
+	switch m := i.(type) {
+	case *gtsmodel.Account:
+		if id == "" {
+			id = m.ID
+		}
+		evictOrClear(c.accounts, id)
+	case *gtsmodel.Status:
+		if id == "" {
+			id = m.ID
+		}
+		evictOrClear(c.statuses, id)
+	case *gtsmodel.Block, *gtsmodel.Follow:
+		// relationships are keyed by the accounts involved rather than by id, so just start over
+		c.relationships.Clear()
+	}
+}
+
+// invalidateAll removes every cached entry of the same type as i.
+func (c *cachingDB) invalidateAll(i interface{}) {
+	switch i.(type) {
+	case *gtsmodel.Account:
+		c.accounts.Clear()
+	case *gtsmodel.Status:
+		c.statuses.Clear()
+	case *gtsmodel.Block, *gtsmodel.Follow:
+		c.relationships.Clear()
+	}
+}
+
+// copyAccount returns a copy of a that doesn't share any slices with it, so that callers can't change what's in the cache.
+// The keys are shared, since they're never changed in place.
+func copyAccount(a *gtsmodel.Account) gtsmodel.Account {
+	c := *a
+	if a.Fields != nil {
+		c.Fields = make([]gtsmodel.Field, len(a.Fields))
+		copy(c.Fields, a.Fields)
+	}
+	return c
+}
+
+// copyStatus returns a copy of s that doesn't share any slices or pointers with it, so that callers can't change what's in the cache.
+// The GTS fields aren't stored in the database, so they're left out of the copy rather than cached.
+func copyStatus(s *gtsmodel.Status) gtsmodel.Status {
+	c := *s
+	c.Attachments = copyStrings(s.Attachments)
+	c.Tags = copyStrings(s.Tags)
+	c.Mentions = copyStrings(s.Mentions)
+	c.Emojis = copyStrings(s.Emojis)
+	if s.VisibilityAdvanced != nil {
+		v := *s.VisibilityAdvanced
+		c.VisibilityAdvanced = &v
+	}
+	c.GTSAuthorAccount = nil
+	c.GTSMentions = nil
+	c.GTSTags = nil
+	c.GTSEmojis = nil
+	c.GTSMediaAttachments = nil
+	c.GTSReplyToStatus = nil
+	c.GTSReplyToAccount = nil
+	c.GTSBoostedStatus = nil
+	c.GTSBoostedAccount = nil
+	c.GTSPoll = nil
+	return c
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	c := make([]string, len(s))
+	copy(c, s)
+	return c
+}
+
+func evictOrClear(cc cache.Cache, id string) {
+	if id == "" {
+		cc.Clear()
+		return
+	}
+	cc.Evict(id)
+}
+
+/*
+	BASIC DB FUNCTIONALITY
+*/
+
+func (c *cachingDB) CreateTable(ctx context.Context, i interface{}) error {
+	c.invalidateAll(i)
+	return c.DB.CreateTable(ctx, i)
+}
+
+func (c *cachingDB) DropTable(ctx context.Context, i interface{}) error {
+	c.invalidateAll(i)
+	return c.DB.DropTable(ctx, i)
+}
+
+func (c *cachingDB) Stop(ctx context.Context) error {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+	return c.DB.Stop(ctx)
+}
+
+func (c *cachingDB) GetByID(ctx context.Context, id string, i interface{}) error {
+	switch m := i.(type) {
+	case *gtsmodel.Account:
+		if v, err := c.accounts.Fetch(id); err == nil {
+			a := v.(gtsmodel.Account)
+			*m = copyAccount(&a)
+			return nil
+		}
+		if err := c.DB.GetByID(ctx, id, m); err != nil {
+			return err
+		}
+		return c.accounts.Store(id, copyAccount(m))
+	case *gtsmodel.Status:
+		if v, err := c.statuses.Fetch(id); err == nil {
+			st := v.(gtsmodel.Status)
+			*m = copyStatus(&st)
+			return nil
+		}
+		if err := c.DB.GetByID(ctx, id, m); err != nil {
+			return err
+		}
+		return c.statuses.Store(id, copyStatus(m))
+	default:
+		return c.DB.GetByID(ctx, id, i)
+	}
+}
+
+func (c *cachingDB) Put(ctx context.Context, i interface{}) error {
+	err := c.DB.Put(ctx, i)
+	c.invalidate("", i)
+	return err
+}
+
+func (c *cachingDB) Upsert(ctx context.Context, i interface{}, conflictColumn string) error {
+	// the conflicting row might not have the same id as i, so we can't just evict i
+	err := c.DB.Upsert(ctx, i, conflictColumn)
+	c.invalidateAll(i)
+	return err
+}
+
+func (c *cachingDB) UpdateByID(ctx context.Context, id string, i interface{}) error {
+	err := c.DB.UpdateByID(ctx, id, i)
+	c.invalidate(id, i)
+	return err
+}
+
+func (c *cachingDB) UpdateOneByID(ctx context.Context, id string, key string, value interface{}, i interface{}) error {
+	err := c.DB.UpdateOneByID(ctx, id, key, value, i)
+	c.invalidate(id, i)
+	return err
+}
+
+func (c *cachingDB) UpdateWhere(ctx context.Context, where []db.Where, key string, value interface{}, i interface{}) error {
+	err := c.DB.UpdateWhere(ctx, where, key, value, i)
+	c.invalidateAll(i)
+	return err
+}
+
+func (c *cachingDB) DeleteByID(ctx context.Context, id string, i interface{}) error {
+	err := c.DB.DeleteByID(ctx, id, i)
+	c.invalidate(id, i)
+	return err
+}
+
+func (c *cachingDB) DeleteWhere(ctx context.Context, where []db.Where, i interface{}) error {
+	err := c.DB.DeleteWhere(ctx, where, i)
+	c.invalidateAll(i)
+	return err
+}
+
+/*
+	HANDY SHORTCUTS
+*/
+
+func (c *cachingDB) AcceptFollowRequest(ctx context.Context, originAccountID string, targetAccountID string) (*gtsmodel.Follow, error) {
+	follow, err := c.DB.AcceptFollowRequest(ctx, originAccountID, targetAccountID)
+	c.relationships.Clear()
+	return follow, err
+}
+
+func (c *cachingDB) SetHeaderOrAvatarForAccountID(ctx context.Context, mediaAttachment *gtsmodel.MediaAttachment, accountID string) error {
+	err := c.DB.SetHeaderOrAvatarForAccountID(ctx, mediaAttachment, accountID)
+	c.accounts.Evict(accountID)
+	return err
+}
+
+func (c *cachingDB) Blocked(ctx context.Context, account1 string, account2 string) (bool, error) {
+	// blocks work in both directions, so the order of the accounts doesn't matter
+	if account2 < account1 {
+		account1, account2 = account2, account1
+	}
+	key := fmt.Sprintf("block:%s:%s", account1, account2)
+
+	if v, err := c.relationships.Fetch(key); err == nil {
+		return v.(bool), nil
+	}
+
+	blocked, err := c.DB.Blocked(ctx, account1, account2)
+	if err != nil {
+		return false, err
+	}
+	return blocked, c.relationships.Store(key, blocked)
+}
+
+func (c *cachingDB) Follows(ctx context.Context, sourceAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (bool, error) {
+	if sourceAccount == nil || targetAccount == nil {
+		return c.DB.Follows(ctx, sourceAccount, targetAccount)
+	}
+	key := fmt.Sprintf("follow:%s:%s", sourceAccount.ID, targetAccount.ID)
+
+	if v, err := c.relationships.Fetch(key); err == nil {
+		return v.(bool), nil
+	}
+
+	follows, err := c.DB.Follows(ctx, sourceAccount, targetAccount)
+	if err != nil {
+		return false, err
+	}
+	return follows, c.relationships.Store(key, follows)
+}
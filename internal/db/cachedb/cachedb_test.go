@@ -0,0 +1,199 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cachedb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/db/cachedb"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type CacheDBTestSuite struct {
+	suite.Suite
+	db           db.DB
+	testAccounts map[string]*gtsmodel.Account
+	testStatuses map[string]*gtsmodel.Status
+}
+
+func (suite *CacheDBTestSuite) SetupTest() {
+	suite.db = testrig.NewTestDB()
+	testrig.StandardDBSetup(suite.db)
+	suite.testAccounts = testrig.NewTestAccounts()
+	suite.testStatuses = testrig.NewTestStatuses()
+}
+
+func (suite *CacheDBTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+func (suite *CacheDBTestSuite) stats(name string) (uint64, uint64) {
+	stater, ok := suite.db.(cachedb.Stater)
+	suite.True(ok)
+	s := stater.CacheStats()[name]
+	return s.Hits, s.Misses
+}
+
+func (suite *CacheDBTestSuite) TestGetAccountByIDCached() {
+	testAccount := suite.testAccounts["local_account_1"]
+	hits, misses := suite.stats("accounts")
+
+	a1 := &gtsmodel.Account{}
+	suite.NoError(suite.db.GetByID(context.Background(), testAccount.ID, a1))
+	a2 := &gtsmodel.Account{}
+	suite.NoError(suite.db.GetByID(context.Background(), testAccount.ID, a2))
+
+	suite.Equal(testAccount.Username, a1.Username)
+	suite.Equal(a1.Username, a2.Username)
+
+	newHits, newMisses := suite.stats("accounts")
+	suite.Equal(hits+1, newHits)
+	suite.Equal(misses+1, newMisses)
+
+	// changing the fetched account must not change the cached one
+	a2.Note = "i've been changed"
+	a3 := &gtsmodel.Account{}
+	suite.NoError(suite.db.GetByID(context.Background(), testAccount.ID, a3))
+	suite.Equal(a1.Note, a3.Note)
+}
+
+func (suite *CacheDBTestSuite) TestUpdateInvalidatesAccount() {
+	testAccount := suite.testAccounts["local_account_1"]
+
+	a := &gtsmodel.Account{}
+	suite.NoError(suite.db.GetByID(context.Background(), testAccount.ID, a))
+
+	a.Note = "a brand new note"
+	suite.NoError(suite.db.UpdateByID(context.Background(), a.ID, a))
+
+	updated := &gtsmodel.Account{}
+	suite.NoError(suite.db.GetByID(context.Background(), testAccount.ID, updated))
+	suite.Equal("a brand new note", updated.Note)
+
+	suite.NoError(suite.db.UpdateOneByID(context.Background(), a.ID, "note", "another note", &gtsmodel.Account{}))
+	suite.NoError(suite.db.GetByID(context.Background(), testAccount.ID, updated))
+	suite.Equal("another note", updated.Note)
+}
+
+func (suite *CacheDBTestSuite) TestDeleteInvalidatesStatus() {
+	testStatus := suite.testStatuses["local_account_1_status_1"]
+
+	s := &gtsmodel.Status{}
+	suite.NoError(suite.db.GetByID(context.Background(), testStatus.ID, s))
+
+	suite.NoError(suite.db.DeleteByID(context.Background(), testStatus.ID, &gtsmodel.Status{}))
+
+	err := suite.db.GetByID(context.Background(), testStatus.ID, &gtsmodel.Status{})
+	suite.IsType(db.ErrNoEntries{}, err)
+}
+
+func (suite *CacheDBTestSuite) TestBlockInvalidatesRelationship() {
+	account1 := suite.testAccounts["local_account_1"]
+	account2 := suite.testAccounts["local_account_2"]
+
+	blocked, err := suite.db.Blocked(context.Background(), account1.ID, account2.ID)
+	suite.NoError(err)
+	suite.False(blocked)
+
+	suite.NoError(suite.db.Put(context.Background(), &gtsmodel.Block{
+		ID:              "01FCW6CQEXCKFHKS1ZZ5HAPJ5Q",
+		AccountID:       account1.ID,
+		TargetAccountID: account2.ID,
+		URI:             "http://localhost:8080/users/the_mighty_zork/blocks/01FCW6CQEXCKFHKS1ZZ5HAPJ5Q",
+	}))
+
+	// blocks go both ways, so asking the other way around should also see the new block
+	blocked, err = suite.db.Blocked(context.Background(), account2.ID, account1.ID)
+	suite.NoError(err)
+	suite.True(blocked)
+}
+
+func (suite *CacheDBTestSuite) TestCancelledContext() {
+	testStatus := suite.testStatuses["local_account_1_status_1"]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// a cancelled query should fail, and the failure shouldn't stop the next lookup from working
+	suite.Error(suite.db.GetByID(ctx, testStatus.ID, &gtsmodel.Status{}))
+
+	s := &gtsmodel.Status{}
+	suite.NoError(suite.db.GetByID(context.Background(), testStatus.ID, s))
+	suite.Equal(testStatus.Content, s.Content)
+}
+
+func (suite *CacheDBTestSuite) TestCachedAccountFieldsNotShared() {
+	ctx := context.Background()
+	testAccount := suite.testAccounts["local_account_1"]
+
+	a := &gtsmodel.Account{}
+	suite.NoError(suite.db.GetByID(ctx, testAccount.ID, a))
+	a.Fields = []gtsmodel.Field{{Name: "pronouns", Value: "they/them"}}
+	suite.NoError(suite.db.UpdateByID(ctx, a.ID, a))
+
+	// the first get caches the account, and the second is served from the cache
+	for i := 0; i < 2; i++ {
+		fetched := &gtsmodel.Account{}
+		suite.NoError(suite.db.GetByID(ctx, testAccount.ID, fetched))
+		suite.Equal("they/them", fetched.Fields[0].Value)
+
+		// changing the fields of a fetched account in place must not change the cached one
+		fetched.Fields[0].Value = "changed"
+	}
+}
+
+func (suite *CacheDBTestSuite) TestCachedStatusSlicesNotShared() {
+	ctx := context.Background()
+	testStatus := suite.testStatuses["local_account_1_status_1"]
+
+	s := &gtsmodel.Status{}
+	suite.NoError(suite.db.GetByID(ctx, testStatus.ID, s))
+	s.Tags = []string{"01FCXSG3ZV2ZNY2PYY76TW1KR4"}
+	suite.NoError(suite.db.UpdateByID(ctx, s.ID, s))
+
+	for i := 0; i < 2; i++ {
+		fetched := &gtsmodel.Status{}
+		suite.NoError(suite.db.GetByID(ctx, testStatus.ID, fetched))
+		suite.Equal([]string{"01FCXSG3ZV2ZNY2PYY76TW1KR4"}, fetched.Tags)
+
+		fetched.Tags[0] = "changed"
+		fetched.GTSAuthorAccount = suite.testAccounts["local_account_2"]
+	}
+
+	// things that aren't in the db shouldn't be cached either
+	fetched := &gtsmodel.Status{}
+	suite.NoError(suite.db.GetByID(ctx, testStatus.ID, fetched))
+	suite.Nil(fetched.GTSAuthorAccount)
+}
+
+func (suite *CacheDBTestSuite) TestStopTwice() {
+	conn := testrig.NewTestDB()
+	suite.NotPanics(func() {
+		_ = conn.Stop(context.Background())
+		_ = conn.Stop(context.Background())
+	})
+}
+
+func TestCacheDBTestSuite(t *testing.T) {
+	suite.Run(t, new(CacheDBTestSuite))
+}
@@ -21,6 +21,7 @@ package db
 import (
 	"context"
 	"net"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
@@ -28,9 +29,11 @@ import (
 const (
 	// DBTypePostgres represents an underlying POSTGRES database type.
 	DBTypePostgres string = "POSTGRES"
+	// DBTypeSQLite represents an underlying SQLITE database type.
+	DBTypeSQLite string = "SQLITE"
 )
 
-// DB provides methods for interacting with an underlying database or other storage mechanism (for now, just postgres).
+// DB provides methods for interacting with an underlying database or other storage mechanism (postgres or sqlite).
 // Note that in all of the functions below, the passed interface should be a pointer or a slice, which will then be populated
 // by whatever is returned from the database.
 type DB interface {
@@ -40,11 +43,20 @@ type DB interface {
 
 	// CreateTable creates a table for the given interface.
 	// For implementations that don't use tables, this can just return nil.
-	CreateTable(i interface{}) error
+	CreateTable(ctx context.Context, i interface{}) error
 
 	// DropTable drops the table for the given interface.
 	// For implementations that don't use tables, this can just return nil.
-	DropTable(i interface{}) error
+	DropTable(ctx context.Context, i interface{}) error
+
+	// Migrate runs every one of the given migrations that hasn't been run against the database yet, in ascending order of version,
+	// and returns the migrations that were run. Each migration is run in its own transaction, so if a migration fails,
+	// the schema will be left at the version of the last migration that succeeded.
+	Migrate(ctx context.Context, migrations []Migration) ([]Migration, error)
+
+	// GetMigrationVersions returns the versions of all migrations that have been run against the database, in ascending order.
+	// If no migrations have been run yet, an empty slice will be returned.
+	GetMigrationVersions(ctx context.Context) ([]*MigrationVersion, error)
 
 	// Stop should stop and close the database connection cleanly, returning an error if this is not possible.
 	// If the database implementation doesn't need to be stopped, this can just return nil.
@@ -57,45 +69,45 @@ type DB interface {
 	// for other implementations (for example, in-memory) it might just be the key of a map.
 	// The given interface i will be set to the result of the query, whatever it is. Use a pointer or a slice.
 	// In case of no entries, a 'no entries' error will be returned
-	GetByID(id string, i interface{}) error
+	GetByID(ctx context.Context, id string, i interface{}) error
 
 	// GetWhere gets one entry where key = value. This is similar to GetByID but allows the caller to specify the
 	// name of the key to select from.
 	// The given interface i will be set to the result of the query, whatever it is. Use a pointer or a slice.
 	// In case of no entries, a 'no entries' error will be returned
-	GetWhere(where []Where, i interface{}) error
+	GetWhere(ctx context.Context, where []Where, i interface{}) error
 
 	// GetAll will try to get all entries of type i.
 	// The given interface i will be set to the result of the query, whatever it is. Use a pointer or a slice.
 	// In case of no entries, a 'no entries' error will be returned
-	GetAll(i interface{}) error
+	GetAll(ctx context.Context, i interface{}) error
 
 	// Put simply stores i. It is up to the implementation to figure out how to store it, and using what key.
 	// The given interface i will be set to the result of the query, whatever it is. Use a pointer or a slice.
-	Put(i interface{}) error
+	Put(ctx context.Context, i interface{}) error
 
 	// Upsert stores or updates i based on the given conflict column, as in https://www.postgresqltutorial.com/postgresql-upsert/
 	// It is up to the implementation to figure out how to store it, and using what key.
 	// The given interface i will be set to the result of the query, whatever it is. Use a pointer or a slice.
-	Upsert(i interface{}, conflictColumn string) error
+	Upsert(ctx context.Context, i interface{}, conflictColumn string) error
 
 	// UpdateByID updates i with id id.
 	// The given interface i will be set to the result of the query, whatever it is. Use a pointer or a slice.
-	UpdateByID(id string, i interface{}) error
+	UpdateByID(ctx context.Context, id string, i interface{}) error
 
 	// UpdateOneByID updates interface i with database the given database id. It will update one field of key key and value value.
-	UpdateOneByID(id string, key string, value interface{}, i interface{}) error
+	UpdateOneByID(ctx context.Context, id string, key string, value interface{}, i interface{}) error
 
 	// UpdateWhere updates column key of interface i with the given value, where the given parameters apply.
-	UpdateWhere(where []Where, key string, value interface{}, i interface{}) error
+	UpdateWhere(ctx context.Context, where []Where, key string, value interface{}, i interface{}) error
 
 	// DeleteByID removes i with id id.
 	// If i didn't exist anyway, then no error should be returned.
-	DeleteByID(id string, i interface{}) error
+	DeleteByID(ctx context.Context, id string, i interface{}) error
 
 	// DeleteWhere deletes i where key = value
 	// If i didn't exist anyway, then no error should be returned.
-	DeleteWhere(where []Where, i interface{}) error
+	DeleteWhere(ctx context.Context, where []Where, i interface{}) error
 
 	/*
 		HANDY SHORTCUTS
@@ -105,154 +117,163 @@ type DB interface {
 	// In other words, it should create the follow, and delete the existing follow request.
 	//
 	// It will return the newly created follow for further processing.
-	AcceptFollowRequest(originAccountID string, targetAccountID string) (*gtsmodel.Follow, error)
+	AcceptFollowRequest(ctx context.Context, originAccountID string, targetAccountID string) (*gtsmodel.Follow, error)
 
 	// CreateInstanceAccount creates an account in the database with the same username as the instance host value.
 	// Ie., if the instance is hosted at 'example.org' the instance user will have a username of 'example.org'.
 	// This is needed for things like serving files that belong to the instance and not an individual user/account.
-	CreateInstanceAccount() error
+	CreateInstanceAccount(ctx context.Context) error
 
 	// CreateInstanceInstance creates an instance in the database with the same domain as the instance host value.
 	// Ie., if the instance is hosted at 'example.org' the instance will have a domain of 'example.org'.
 	// This is needed for things like serving instance information through /api/v1/instance
-	CreateInstanceInstance() error
+	CreateInstanceInstance(ctx context.Context) error
 
 	// GetAccountByUserID is a shortcut for the common action of fetching an account corresponding to a user ID.
 	// The given account pointer will be set to the result of the query, whatever it is.
 	// In case of no entries, a 'no entries' error will be returned
-	GetAccountByUserID(userID string, account *gtsmodel.Account) error
+	GetAccountByUserID(ctx context.Context, userID string, account *gtsmodel.Account) error
 
 	// GetLocalAccountByUsername is a shortcut for the common action of fetching an account ON THIS INSTANCE
 	// according to its username, which should be unique.
 	// The given account pointer will be set to the result of the query, whatever it is.
 	// In case of no entries, a 'no entries' error will be returned
-	GetLocalAccountByUsername(username string, account *gtsmodel.Account) error
+	GetLocalAccountByUsername(ctx context.Context, username string, account *gtsmodel.Account) error
 
 	// GetFollowRequestsForAccountID is a shortcut for the common action of fetching a list of follow requests targeting the given account ID.
 	// The given slice 'followRequests' will be set to the result of the query, whatever it is.
 	// In case of no entries, a 'no entries' error will be returned
-	GetFollowRequestsForAccountID(accountID string, followRequests *[]gtsmodel.FollowRequest) error
+	GetFollowRequestsForAccountID(ctx context.Context, accountID string, followRequests *[]gtsmodel.FollowRequest) error
 
 	// GetFollowingByAccountID is a shortcut for the common action of fetching a list of accounts that accountID is following.
 	// The given slice 'following' will be set to the result of the query, whatever it is.
 	// In case of no entries, a 'no entries' error will be returned
-	GetFollowingByAccountID(accountID string, following *[]gtsmodel.Follow) error
+	GetFollowingByAccountID(ctx context.Context, accountID string, following *[]gtsmodel.Follow) error
 
 	// GetFollowersByAccountID is a shortcut for the common action of fetching a list of accounts that accountID is followed by.
 	// The given slice 'followers' will be set to the result of the query, whatever it is.
 	// In case of no entries, a 'no entries' error will be returned
 	//
 	// If localOnly is set to true, then only followers from *this instance* will be returned.
-	GetFollowersByAccountID(accountID string, followers *[]gtsmodel.Follow, localOnly bool) error
+	GetFollowersByAccountID(ctx context.Context, accountID string, followers *[]gtsmodel.Follow, localOnly bool) error
 
 	// GetFavesByAccountID is a shortcut for the common action of fetching a list of faves made by the given accountID.
 	// The given slice 'faves' will be set to the result of the query, whatever it is.
 	// In case of no entries, a 'no entries' error will be returned
-	GetFavesByAccountID(accountID string, faves *[]gtsmodel.StatusFave) error
+	GetFavesByAccountID(ctx context.Context, accountID string, faves *[]gtsmodel.StatusFave) error
 
 	// CountStatusesByAccountID is a shortcut for the common action of counting statuses produced by accountID.
-	CountStatusesByAccountID(accountID string) (int, error)
+	CountStatusesByAccountID(ctx context.Context, accountID string) (int, error)
 
 	// GetStatusesForAccount is a shortcut for getting the most recent statuses. accountID is optional, if not provided
 	// then all statuses will be returned. If limit is set to 0, the size of the returned slice will not be limited. This can
 	// be very memory intensive so you probably shouldn't do this!
 	// In case of no entries, a 'no entries' error will be returned
-	GetStatusesForAccount(accountID string, limit int, excludeReplies bool, maxID string, pinnedOnly bool, mediaOnly bool) ([]*gtsmodel.Status, error)
+	GetStatusesForAccount(ctx context.Context, accountID string, limit int, excludeReplies bool, maxID string, pinnedOnly bool, mediaOnly bool) ([]*gtsmodel.Status, error)
 
-	GetBlocksForAccount(accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Account, string, string, error)
+	GetBlocksForAccount(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Account, string, string, error)
+
+	// GetMutesForAccount returns the accounts muted by the given account, newest mute first, along with the IDs to use for paging.
+	// Mutes that have expired are not included.
+	// In case of no entries, a 'no entries' error will be returned
+	GetMutesForAccount(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Account, string, string, error)
+
+	// GetMute returns the mute of targetAccountID by accountID, as long as it hasn't expired.
+	// In case of no entries, a 'no entries' error will be returned
+	GetMute(ctx context.Context, accountID string, targetAccountID string) (*gtsmodel.Mute, error)
 
 	// GetLastStatusForAccountID simply gets the most recent status by the given account.
 	// The given slice 'status' pointer will be set to the result of the query, whatever it is.
 	// In case of no entries, a 'no entries' error will be returned
-	GetLastStatusForAccountID(accountID string, status *gtsmodel.Status) error
+	GetLastStatusForAccountID(ctx context.Context, accountID string, status *gtsmodel.Status) error
 
 	// IsUsernameAvailable checks whether a given username is available on our domain.
 	// Returns an error if the username is already taken, or something went wrong in the db.
-	IsUsernameAvailable(username string) error
+	IsUsernameAvailable(ctx context.Context, username string) error
 
 	// IsEmailAvailable checks whether a given email address for a new account is available to be used on our domain.
 	// Return an error if:
 	// A) the email is already associated with an account
 	// B) we block signups from this email domain
 	// C) something went wrong in the db
-	IsEmailAvailable(email string) error
+	IsEmailAvailable(ctx context.Context, email string) error
 
 	// NewSignup creates a new user in the database with the given parameters.
 	// By the time this function is called, it should be assumed that all the parameters have passed validation!
-	NewSignup(username string, reason string, requireApproval bool, email string, password string, signUpIP net.IP, locale string, appID string, emailVerified bool, admin bool) (*gtsmodel.User, error)
+	NewSignup(ctx context.Context, username string, reason string, requireApproval bool, email string, password string, signUpIP net.IP, locale string, appID string, emailVerified bool, admin bool) (*gtsmodel.User, error)
 
 	// SetHeaderOrAvatarForAccountID sets the header or avatar for the given accountID to the given media attachment.
-	SetHeaderOrAvatarForAccountID(mediaAttachment *gtsmodel.MediaAttachment, accountID string) error
+	SetHeaderOrAvatarForAccountID(ctx context.Context, mediaAttachment *gtsmodel.MediaAttachment, accountID string) error
 
 	// GetHeaderAvatarForAccountID gets the current avatar for the given account ID.
 	// The passed mediaAttachment pointer will be populated with the value of the avatar, if it exists.
-	GetAvatarForAccountID(avatar *gtsmodel.MediaAttachment, accountID string) error
+	GetAvatarForAccountID(ctx context.Context, avatar *gtsmodel.MediaAttachment, accountID string) error
 
 	// GetHeaderForAccountID gets the current header for the given account ID.
 	// The passed mediaAttachment pointer will be populated with the value of the header, if it exists.
-	GetHeaderForAccountID(header *gtsmodel.MediaAttachment, accountID string) error
+	GetHeaderForAccountID(ctx context.Context, header *gtsmodel.MediaAttachment, accountID string) error
 
 	// Blocked checks whether a block exists in eiher direction between two accounts.
 	// That is, it returns true if account1 blocks account2, OR if account2 blocks account1.
-	Blocked(account1 string, account2 string) (bool, error)
+	Blocked(ctx context.Context, account1 string, account2 string) (bool, error)
 
 	// GetRelationship retrieves the relationship of the targetAccount to the requestingAccount.
-	GetRelationship(requestingAccount string, targetAccount string) (*gtsmodel.Relationship, error)
+	GetRelationship(ctx context.Context, requestingAccount string, targetAccount string) (*gtsmodel.Relationship, error)
 
 	// Follows returns true if sourceAccount follows target account, or an error if something goes wrong while finding out.
-	Follows(sourceAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (bool, error)
+	Follows(ctx context.Context, sourceAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (bool, error)
 
 	// FollowRequested returns true if sourceAccount has requested to follow target account, or an error if something goes wrong while finding out.
-	FollowRequested(sourceAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (bool, error)
+	FollowRequested(ctx context.Context, sourceAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (bool, error)
 
 	// Mutuals returns true if account1 and account2 both follow each other, or an error if something goes wrong while finding out.
-	Mutuals(account1 *gtsmodel.Account, account2 *gtsmodel.Account) (bool, error)
+	Mutuals(ctx context.Context, account1 *gtsmodel.Account, account2 *gtsmodel.Account) (bool, error)
 
 	// GetReplyCountForStatus returns the amount of replies recorded for a status, or an error if something goes wrong
-	GetReplyCountForStatus(status *gtsmodel.Status) (int, error)
+	GetReplyCountForStatus(ctx context.Context, status *gtsmodel.Status) (int, error)
 
 	// GetReblogCountForStatus returns the amount of reblogs/boosts recorded for a status, or an error if something goes wrong
-	GetReblogCountForStatus(status *gtsmodel.Status) (int, error)
+	GetReblogCountForStatus(ctx context.Context, status *gtsmodel.Status) (int, error)
 
 	// GetFaveCountForStatus returns the amount of faves/likes recorded for a status, or an error if something goes wrong
-	GetFaveCountForStatus(status *gtsmodel.Status) (int, error)
+	GetFaveCountForStatus(ctx context.Context, status *gtsmodel.Status) (int, error)
 
 	// StatusParents get the parent statuses of a given status.
-	StatusParents(status *gtsmodel.Status) ([]*gtsmodel.Status, error)
+	StatusParents(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.Status, error)
 
 	// StatusChildren gets the child statuses of a given status.
-	StatusChildren(status *gtsmodel.Status) ([]*gtsmodel.Status, error)
+	StatusChildren(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.Status, error)
 
 	// StatusFavedBy checks if a given status has been faved by a given account ID
-	StatusFavedBy(status *gtsmodel.Status, accountID string) (bool, error)
+	StatusFavedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error)
 
 	// StatusRebloggedBy checks if a given status has been reblogged/boosted by a given account ID
-	StatusRebloggedBy(status *gtsmodel.Status, accountID string) (bool, error)
+	StatusRebloggedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error)
 
 	// StatusMutedBy checks if a given status has been muted by a given account ID
-	StatusMutedBy(status *gtsmodel.Status, accountID string) (bool, error)
+	StatusMutedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error)
 
 	// StatusBookmarkedBy checks if a given status has been bookmarked by a given account ID
-	StatusBookmarkedBy(status *gtsmodel.Status, accountID string) (bool, error)
+	StatusBookmarkedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error)
 
 	// WhoFavedStatus returns a slice of accounts who faved the given status.
 	// This slice will be unfiltered, not taking account of blocks and whatnot, so filter it before serving it back to a user.
-	WhoFavedStatus(status *gtsmodel.Status) ([]*gtsmodel.Account, error)
+	WhoFavedStatus(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.Account, error)
 
 	// WhoBoostedStatus returns a slice of accounts who boosted the given status.
 	// This slice will be unfiltered, not taking account of blocks and whatnot, so filter it before serving it back to a user.
-	WhoBoostedStatus(status *gtsmodel.Status) ([]*gtsmodel.Account, error)
+	WhoBoostedStatus(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.Account, error)
 
 	// GetHomeTimelineForAccount returns a slice of statuses from accounts that are followed by the given account id.
 	//
 	// Statuses should be returned in descending order of when they were created (newest first).
-	GetHomeTimelineForAccount(accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, error)
+	GetHomeTimelineForAccount(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, error)
 
 	// GetPublicTimelineForAccount fetches the account's PUBLIC timeline -- ie., posts and replies that are public.
 	// It will use the given filters and try to return as many statuses as possible up to the limit.
 	//
 	// Statuses should be returned in descending order of when they were created (newest first).
-	GetPublicTimelineForAccount(accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, error)
+	GetPublicTimelineForAccount(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, error)
 
 	// GetFavedTimelineForAccount fetches the account's FAVED timeline -- ie., posts and replies that the requesting account has faved.
 	// It will use the given filters and try to return as many statuses as possible up to the limit.
@@ -261,22 +282,101 @@ type DB interface {
 	// In other words, they'll be returned in descending order of when they were faved by the requesting user, not when they were created.
 	//
 	// Also note the extra return values, which correspond to the nextMaxID and prevMinID for building Link headers.
-	GetFavedTimelineForAccount(accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Status, string, string, error)
+	GetFavedTimelineForAccount(ctx context.Context, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Status, string, string, error)
+
+	// GetBookmarkedTimelineForAccount fetches the statuses that the given account has bookmarked.
+	//
+	// Like GetFavedTimelineForAccount, statuses are arranged by their BOOKMARK id, in descending order of when they were bookmarked,
+	// and the extra return values correspond to the nextMaxID and prevMinID for building Link headers.
+	GetBookmarkedTimelineForAccount(ctx context.Context, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Status, string, string, error)
+
+	// GetListTimeline returns a slice of statuses from accounts that are members of the given list, and are
+	// still followed by the owner of the list.
+	//
+	// Statuses should be returned in descending order of when they were created (newest first).
+	GetListTimeline(ctx context.Context, listID string, maxID string, sinceID string, minID string, limit int) ([]*gtsmodel.Status, error)
+
+	// GetTagTimeline returns a slice of public, top-level statuses that use at least one of the tags in anyTagIDs, every tag in allTagIDs,
+	// and none of the tags in noneTagIDs. If local is true, only statuses created on this instance are returned, and if mediaOnly
+	// is true, only statuses with media attachments are returned.
+	//
+	// Statuses should be returned in descending order of when they were created (newest first).
+	GetTagTimeline(ctx context.Context, anyTagIDs []string, allTagIDs []string, noneTagIDs []string, maxID string, sinceID string, minID string, limit int, local bool, mediaOnly bool) ([]*gtsmodel.Status, error)
+
+	// GetListEntries returns the entries of the given list, newest first, paged by the given entry IDs.
+	GetListEntries(ctx context.Context, listID string, maxID string, sinceID string, limit int) ([]*gtsmodel.ListEntry, error)
 
 	// GetNotificationsForAccount returns a list of notifications that pertain to the given accountID.
-	GetNotificationsForAccount(accountID string, limit int, maxID string, sinceID string) ([]*gtsmodel.Notification, error)
+	GetNotificationsForAccount(ctx context.Context, accountID string, limit int, maxID string, sinceID string) ([]*gtsmodel.Notification, error)
 
 	// GetUserCountForInstance returns the number of known accounts registered with the given domain.
-	GetUserCountForInstance(domain string) (int, error)
+	GetUserCountForInstance(ctx context.Context, domain string) (int, error)
 
 	// GetStatusCountForInstance returns the number of known statuses posted from the given domain.
-	GetStatusCountForInstance(domain string) (int, error)
+	GetStatusCountForInstance(ctx context.Context, domain string) (int, error)
 
 	// GetDomainCountForInstance returns the number of known instances known that the given domain federates with.
-	GetDomainCountForInstance(domain string) (int, error)
+	GetDomainCountForInstance(ctx context.Context, domain string) (int, error)
 
 	// GetAccountsForInstance returns a slice of accounts from the given instance, arranged by ID.
-	GetAccountsForInstance(domain string, maxID string, limit int) ([]*gtsmodel.Account, error)
+	GetAccountsForInstance(ctx context.Context, domain string, maxID string, limit int) ([]*gtsmodel.Account, error)
+
+	// GetDueQueuedMessages returns up to limit queued messages that are due to be processed, oldest first.
+	GetDueQueuedMessages(ctx context.Context, limit int) ([]*gtsmodel.QueuedMessage, error)
+
+	// CountQueuedMessages returns the number of messages in the queue, whether they're due or not.
+	CountQueuedMessages(ctx context.Context) (int, error)
+
+	// GetDueDeliveries returns up to limit deliveries that are due to be retried, oldest first.
+	// Deliveries that have been given up on are not included.
+	GetDueDeliveries(ctx context.Context, limit int) ([]*gtsmodel.Delivery, error)
+
+	// GetDeliveryStats returns a summary of the stored deliveries to each domain, ordered by domain.
+	// If domain is set, only the deliveries to that domain are summarized.
+	// In case of no entries, a 'no entries' error will be returned
+	GetDeliveryStats(ctx context.Context, domain string) ([]*DeliveryStats, error)
+
+	// DeleteFailedDeliveries deletes deliveries that were given up on before olderThan.
+	DeleteFailedDeliveries(ctx context.Context, olderThan time.Time) error
+
+	// GetExpiredPolls returns up to limit polls that have passed their expiry time but haven't been closed yet.
+	// Only polls with an ID higher than sinceID are returned, if it's set, lowest ID first, so that polls
+	// which can't be closed can be paged past.
+	GetExpiredPolls(ctx context.Context, sinceID string, limit int) ([]*gtsmodel.Poll, error)
+
+	// GetExpiredMutes returns up to limit mutes that have passed their expiry time, oldest first.
+	GetExpiredMutes(ctx context.Context, limit int) ([]*gtsmodel.Mute, error)
+
+	// GetCachedRemoteMedia returns up to limit media attachments from remote instances that are cached in storage,
+	// but haven't been accessed since olderThan. Only attachments with an ID higher than sinceID are returned, if it's set,
+	// lowest ID first, so that attachments which can't be dealt with can be paged past.
+	GetCachedRemoteMedia(ctx context.Context, olderThan time.Time, sinceID string, limit int) ([]*gtsmodel.MediaAttachment, error)
+
+	// GetMediaAttachments returns up to limit media attachments with an ID lower than maxID, or from the
+	// highest ID if maxID is empty, highest ID first. This can be used to page through all attachments.
+	GetMediaAttachments(ctx context.Context, maxID string, limit int) ([]*gtsmodel.MediaAttachment, error)
+
+	// GetScheduledStatusesForAccount returns the statuses that the given account has scheduled, newest first.
+	// In case of no entries, a 'no entries' error will be returned
+	GetScheduledStatusesForAccount(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.ScheduledStatus, error)
+
+	// GetDueScheduledStatuses returns up to limit scheduled statuses that are due to be published, earliest first.
+	// Scheduled statuses that have been given up on, or that are waiting to be retried, are not included.
+	GetDueScheduledStatuses(ctx context.Context, limit int) ([]*gtsmodel.ScheduledStatus, error)
+
+	// GetReports returns reports that are either resolved or unresolved, newest first.
+	// If accountID or targetAccountID are set, only reports by or about those accounts are returned.
+	// In case of no entries, a 'no entries' error will be returned
+	GetReports(ctx context.Context, resolved bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Report, error)
+
+	// GetConversationsForAccount returns the direct message conversations of the given account, the most recently active first.
+	// maxID and sinceID refer to the last status of the conversations.
+	// In case of no entries, a 'no entries' error will be returned
+	GetConversationsForAccount(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Conversation, error)
+
+	// GetConversationsForStatus returns all conversations that the given status is part of.
+	// In case of no entries, a 'no entries' error will be returned
+	GetConversationsForStatus(ctx context.Context, statusID string) ([]*gtsmodel.Conversation, error)
 
 	/*
 		USEFUL CONVERSION FUNCTIONS
@@ -289,7 +389,7 @@ type DB interface {
 	//
 	// Note: this func doesn't/shouldn't do any manipulation of the accounts in the DB, it's just for checking
 	// if they exist in the db and conveniently returning them if they do.
-	MentionStringsToMentions(targetAccounts []string, originAccountID string, statusID string) ([]*gtsmodel.Mention, error)
+	MentionStringsToMentions(ctx context.Context, targetAccounts []string, originAccountID string, statusID string) ([]*gtsmodel.Mention, error)
 
 	// TagStringsToTags takes a slice of deduplicated, lowercase tags in the form "somehashtag", which have been
 	// used in a status. It takes the id of the account that wrote the status, and the id of the status itself, and then
@@ -298,7 +398,7 @@ type DB interface {
 	//
 	// Note: this func doesn't/shouldn't do any manipulation of the tags in the DB, it's just for checking
 	// if they exist in the db already, and conveniently returning them, or creating new tag structs.
-	TagStringsToTags(tags []string, originAccountID string, statusID string) ([]*gtsmodel.Tag, error)
+	TagStringsToTags(ctx context.Context, tags []string, originAccountID string, statusID string) ([]*gtsmodel.Tag, error)
 
 	// EmojiStringsToEmojis takes a slice of deduplicated, lowercase emojis in the form ":emojiname:", which have been
 	// used in a status. It takes the id of the account that wrote the status, and the id of the status itself, and then
@@ -306,5 +406,5 @@ type DB interface {
 	//
 	// Note: this func doesn't/shouldn't do any manipulation of the emoji in the DB, it's just for checking
 	// if they exist in the db and conveniently returning them if they do.
-	EmojiStringsToEmojis(emojis []string, originAccountID string, statusID string) ([]*gtsmodel.Emoji, error)
+	EmojiStringsToEmojis(ctx context.Context, emojis []string, originAccountID string, statusID string) ([]*gtsmodel.Emoji, error)
 }
@@ -0,0 +1,52 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package dbconn picks and connects to the right db.DB implementation for the configured database type.
+package dbconn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/db/cachedb"
+	"github.com/superseriousbusiness/gotosocial/internal/db/pg"
+	"github.com/superseriousbusiness/gotosocial/internal/db/sqlite"
+)
+
+// NewService returns a new db.DB for the database type set in the given config, wrapped in a caching layer.
+func NewService(ctx context.Context, c *config.Config, log *logrus.Logger) (db.DB, error) {
+	var conn db.DB
+	var err error
+	switch strings.ToUpper(c.DBConfig.Type) {
+	case db.DBTypePostgres:
+		conn, err = pg.NewPostgresService(ctx, c, log)
+	case db.DBTypeSQLite:
+		conn, err = sqlite.NewSQLiteService(ctx, c, log)
+	default:
+		return nil, fmt.Errorf("database type %s not supported", c.DBConfig.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cachedb.New(conn, log), nil
+}
@@ -0,0 +1,39 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import "time"
+
+// DeliveryStats summarizes the stored deliveries to one domain.
+type DeliveryStats struct {
+	// Domain the deliveries are addressed to.
+	Domain string
+	// Number of deliveries that are waiting to be retried.
+	Pending int
+	// Number of deliveries that have been given up on.
+	Failed int
+	// When is the soonest that one of the pending deliveries will be retried?
+	NextAttemptAt time.Time
+	// When was a delivery to this domain last attempted?
+	LastAttemptAt time.Time
+	// Error returned by the last attempted delivery to this domain.
+	LastError string
+	// When was this domain marked as unreachable, if at all?
+	UnreachableSince time.Time
+}
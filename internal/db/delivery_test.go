@@ -0,0 +1,132 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type DeliveryTestSuite struct {
+	suite.Suite
+	db db.DB
+}
+
+func (suite *DeliveryTestSuite) SetupTest() {
+	suite.db = testrig.NewTestDB()
+	testrig.StandardDBSetup(suite.db)
+}
+
+func (suite *DeliveryTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+func (suite *DeliveryTestSuite) putDelivery(id string, domain string, attempts int, lastError string, updatedAt time.Time, nextAttemptAt time.Time, failedAt time.Time) {
+	suite.NoError(suite.db.Put(context.Background(), &gtsmodel.Delivery{
+		ID:            id,
+		CreatedAt:     updatedAt,
+		UpdatedAt:     updatedAt,
+		Domain:        domain,
+		AccountID:     "01F8MH1H7YV1Z7D2C8K2730QBF",
+		InboxURI:      "https://" + domain + "/users/someone/inbox",
+		Payload:       `{"type":"Create"}`,
+		Attempts:      attempts,
+		LastError:     lastError,
+		NextAttemptAt: nextAttemptAt,
+		FailedAt:      failedAt,
+	}))
+}
+
+func (suite *DeliveryTestSuite) TestGetDeliveryStats() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.Instance{
+		ID:               "01FJ2J0V5RFQ0H4GB9GZ4S0R8E",
+		Domain:           "b.example.org",
+		URI:              "https://b.example.org",
+		UnreachableSince: now.Add(-time.Hour),
+	}))
+
+	suite.putDelivery("01FJ2JBK3N8T6BQ1X9Z8R0M5FA", "b.example.org", 3, "older error", now.Add(-2*time.Hour), now.Add(time.Hour), time.Time{})
+	suite.putDelivery("01FJ2JBT4M7SB2Q0Q6K7C1N4DB", "b.example.org", 2, "newest error", now.Add(-time.Hour), now.Add(30*time.Minute), time.Time{})
+	suite.putDelivery("01FJ2JC1Z8XG2YB9V3P5D6W7EC", "b.example.org", 10, "given up", now.Add(-3*time.Hour), now.Add(-3*time.Hour), now.Add(-3*time.Hour))
+	suite.putDelivery("01FJ2JC8R4H6J2Q1T0V9S8A3FD", "a.example.org", 0, "instance unreachable", now, now.Add(15*time.Minute), time.Time{})
+
+	stats, err := suite.db.GetDeliveryStats(ctx, "")
+	suite.NoError(err)
+	suite.Len(stats, 2)
+
+	// ordered by domain
+	a := stats[0]
+	suite.Equal("a.example.org", a.Domain)
+	suite.Equal(1, a.Pending)
+	suite.Equal(0, a.Failed)
+	suite.WithinDuration(now.Add(15*time.Minute), a.NextAttemptAt, time.Second)
+	suite.True(a.LastAttemptAt.IsZero())
+	suite.Empty(a.LastError)
+	suite.True(a.UnreachableSince.IsZero())
+
+	b := stats[1]
+	suite.Equal("b.example.org", b.Domain)
+	suite.Equal(2, b.Pending)
+	suite.Equal(1, b.Failed)
+	suite.WithinDuration(now.Add(30*time.Minute), b.NextAttemptAt, time.Second)
+	suite.WithinDuration(now.Add(-time.Hour), b.LastAttemptAt, time.Second)
+	suite.Equal("newest error", b.LastError)
+	suite.WithinDuration(now.Add(-time.Hour), b.UnreachableSince, time.Second)
+
+	// filtered by domain
+	stats, err = suite.db.GetDeliveryStats(ctx, "B.example.org")
+	suite.NoError(err)
+	suite.Len(stats, 1)
+	suite.Equal("b.example.org", stats[0].Domain)
+
+	_, err = suite.db.GetDeliveryStats(ctx, "c.example.org")
+	suite.IsType(db.ErrNoEntries{}, err)
+}
+
+func (suite *DeliveryTestSuite) TestDeleteFailedDeliveries() {
+	ctx := context.Background()
+	now := time.Now()
+
+	suite.putDelivery("01FJ2JBK3N8T6BQ1X9Z8R0M5FA", "example.org", 10, "given up long ago", now.Add(-10*24*time.Hour), now.Add(-10*24*time.Hour), now.Add(-10*24*time.Hour))
+	suite.putDelivery("01FJ2JBT4M7SB2Q0Q6K7C1N4DB", "example.org", 10, "given up recently", now.Add(-time.Hour), now.Add(-time.Hour), now.Add(-time.Hour))
+	suite.putDelivery("01FJ2JC1Z8XG2YB9V3P5D6W7EC", "example.org", 1, "still trying", now.Add(-10*24*time.Hour), now.Add(time.Hour), time.Time{})
+
+	suite.NoError(suite.db.DeleteFailedDeliveries(ctx, now.Add(-7*24*time.Hour)))
+
+	deliveries := []*gtsmodel.Delivery{}
+	suite.NoError(suite.db.GetAll(ctx, &deliveries))
+	ids := []string{}
+	for _, d := range deliveries {
+		ids = append(ids, d.ID)
+	}
+	suite.ElementsMatch([]string{"01FJ2JBT4M7SB2Q0Q6K7C1N4DB", "01FJ2JC1Z8XG2YB9V3P5D6W7EC"}, ids)
+}
+
+func TestDeliveryTestSuite(t *testing.T) {
+	suite.Run(t, new(DeliveryTestSuite))
+}
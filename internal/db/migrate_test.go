@@ -0,0 +1,173 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+// MigrationThing is a model that only exists for the migrations below to create.
+type MigrationThing struct {
+	ID   string `pg:"type:CHAR(26),pk,notnull,unique"`
+	Name string
+}
+
+type MigrateTestSuite struct {
+	suite.Suite
+	db  db.DB
+	ran []int
+}
+
+func (suite *MigrateTestSuite) SetupTest() {
+	suite.db = testrig.NewTestDB()
+	suite.ran = []int{}
+}
+
+func (suite *MigrateTestSuite) TearDownTest() {
+	suite.NoError(suite.db.DropTable(context.Background(), &MigrationThing{}))
+	suite.NoError(suite.db.DropTable(context.Background(), &db.MigrationVersion{}))
+	suite.NoError(suite.db.Stop(context.Background()))
+}
+
+// migration returns a migration that records when it's run, and then does the given up func, if any.
+func (suite *MigrateTestSuite) migration(version int, up func(s db.Schema) error) db.Migration {
+	return db.Migration{
+		Version: version,
+		Name:    "test migration",
+		Up: func(s db.Schema) error {
+			suite.ran = append(suite.ran, version)
+			if up == nil {
+				return nil
+			}
+			return up(s)
+		},
+	}
+}
+
+func (suite *MigrateTestSuite) recordedVersions() []int {
+	done, err := suite.db.GetMigrationVersions(context.Background())
+	suite.NoError(err)
+	v := []int{}
+	for _, d := range done {
+		v = append(v, d.Version)
+	}
+	return v
+}
+
+func (suite *MigrateTestSuite) TestMigrateInOrder() {
+	ran, err := suite.db.Migrate(context.Background(), []db.Migration{
+		suite.migration(3, nil),
+		suite.migration(1, nil),
+		suite.migration(2, nil),
+	})
+	suite.NoError(err)
+	suite.Equal([]int{1, 2, 3}, versions(ran))
+	suite.Equal([]int{1, 2, 3}, suite.ran)
+	suite.Equal([]int{1, 2, 3}, suite.recordedVersions())
+}
+
+func (suite *MigrateTestSuite) TestMigrateSkipsApplied() {
+	ctx := context.Background()
+
+	_, err := suite.db.Migrate(ctx, []db.Migration{
+		suite.migration(1, nil),
+		suite.migration(2, nil),
+	})
+	suite.NoError(err)
+
+	ran, err := suite.db.Migrate(ctx, []db.Migration{
+		suite.migration(1, nil),
+		suite.migration(2, nil),
+		suite.migration(3, nil),
+	})
+	suite.NoError(err)
+	suite.Equal([]int{3}, versions(ran))
+	suite.Equal([]int{1, 2, 3}, suite.ran)
+
+	// running again with nothing new should be a no-op
+	ran, err = suite.db.Migrate(ctx, []db.Migration{
+		suite.migration(1, nil),
+		suite.migration(2, nil),
+		suite.migration(3, nil),
+	})
+	suite.NoError(err)
+	suite.Empty(ran)
+	suite.Equal([]int{1, 2, 3}, suite.ran)
+	suite.Equal([]int{1, 2, 3}, suite.recordedVersions())
+}
+
+func (suite *MigrateTestSuite) TestMigrateRollsBackFailed() {
+	ctx := context.Background()
+
+	failing := suite.migration(2, func(s db.Schema) error {
+		if err := s.CreateTable(&MigrationThing{}); err != nil {
+			return err
+		}
+		return errors.New("something went wrong")
+	})
+
+	ran, err := suite.db.Migrate(ctx, []db.Migration{
+		suite.migration(1, nil),
+		failing,
+		suite.migration(3, nil),
+	})
+	suite.EqualError(err, "error running migration 2 (test migration): something went wrong")
+	suite.Equal([]int{1}, versions(ran))
+
+	// the migration after the failed one shouldn't have run, and the failed one shouldn't be recorded
+	suite.Equal([]int{1, 2}, suite.ran)
+	suite.Equal([]int{1}, suite.recordedVersions())
+
+	// the table created by the failed migration should have been rolled back
+	suite.Error(suite.db.Put(ctx, &MigrationThing{ID: "01FJ0BVQGC8WRSQ4BFJ7ZJYBGK", Name: "thing"}))
+
+	// once the migration is fixed, it and the ones after it should run
+	ran, err = suite.db.Migrate(ctx, []db.Migration{
+		suite.migration(1, nil),
+		suite.migration(2, func(s db.Schema) error {
+			return s.CreateTable(&MigrationThing{})
+		}),
+		suite.migration(3, nil),
+	})
+	suite.NoError(err)
+	suite.Equal([]int{2, 3}, versions(ran))
+	suite.Equal([]int{1, 2, 3}, suite.recordedVersions())
+	suite.NoError(suite.db.Put(ctx, &MigrationThing{ID: "01FJ0BVQGC8WRSQ4BFJ7ZJYBGK", Name: "thing"}))
+}
+
+func (suite *MigrateTestSuite) TestMigrateRejectsDuplicates() {
+	ran, err := suite.db.Migrate(context.Background(), []db.Migration{
+		suite.migration(1, nil),
+		suite.migration(1, nil),
+	})
+	suite.EqualError(err, `migration "test migration" has duplicate version 1`)
+	suite.Empty(ran)
+	suite.Empty(suite.ran)
+	suite.Empty(suite.recordedVersions())
+}
+
+func TestMigrateTestSuite(t *testing.T) {
+	suite.Run(t, new(MigrateTestSuite))
+}
@@ -0,0 +1,109 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Migration is a single, versioned change to the database schema.
+//
+// Migrations are run in ascending order of Version, and each migration is only ever run once
+// against a given database: the version of every migration that has run is recorded in the database.
+type Migration struct {
+	// Version of this migration. Must be unique and greater than zero.
+	Version int
+	// Name is a short description of what this migration does, eg., "add pinned_at to statuses".
+	Name string
+	// Up applies this migration using the given Schema.
+	//
+	// Up is run in a transaction along with recording the version of the migration,
+	// so if it returns an error, none of the changes it made will be kept.
+	Up func(s Schema) error
+}
+
+// Schema is passed to migrations to let them change the database schema in a way that works for every database type.
+//
+// All of the functions below are scoped to the transaction that the migration runs in.
+type Schema interface {
+	// Type returns the type of the underlying database, eg., DBTypePostgres, so that migrations
+	// can do something specific to one type of database if they really need to.
+	Type() string
+
+	// CreateTable creates a table for the given interface, if it doesn't exist yet.
+	CreateTable(i interface{}) error
+
+	// DropTable drops the table for the given interface, if it exists.
+	DropTable(i interface{}) error
+
+	// AddColumn adds the given column to the table for the given interface, if it doesn't exist yet.
+	// The column type and constraints are derived from the annotations of the struct field corresponding to the column.
+	AddColumn(i interface{}, column string) error
+
+	// HasColumn returns whether the table for the given interface has the given column. The column doesn't have to correspond
+	// to a field of the interface, so that migrations can deal with columns that have since been removed from a model.
+	HasColumn(i interface{}, column string) (bool, error)
+
+	// CreateIndex creates an index with the given name on the given columns of the table for the given interface, if it doesn't exist yet.
+	CreateIndex(i interface{}, name string, columns ...string) error
+
+	// Exec executes the given raw query, using ? as placeholder for any args.
+	Exec(query string, args ...interface{}) error
+}
+
+// MigrationVersion records that the migration with the given version has been run against the database.
+type MigrationVersion struct {
+	// Version of the migration that was run.
+	Version int `pg:"type:BIGINT,pk,notnull"`
+	// Name of the migration that was run.
+	Name string
+	// When was this migration run?
+	RunAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+}
+
+// PendingMigrations returns the migrations out of the given migrations that don't have a version in done, sorted by ascending version.
+// An error will be returned if any of the given migrations have an invalid or duplicate version.
+func PendingMigrations(migrations []Migration, done []*MigrationVersion) ([]Migration, error) {
+	ran := make(map[int]bool, len(done))
+	for _, d := range done {
+		ran[d.Version] = true
+	}
+
+	seen := make(map[int]bool, len(migrations))
+	pending := []Migration{}
+	for _, m := range migrations {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("migration %q has invalid version %d", m.Name, m.Version)
+		}
+		if seen[m.Version] {
+			return nil, fmt.Errorf("migration %q has duplicate version %d", m.Name, m.Version)
+		}
+		seen[m.Version] = true
+		if !ran[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Version < pending[j].Version
+	})
+	return pending, nil
+}
@@ -0,0 +1,115 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+)
+
+type PendingMigrationsTestSuite struct {
+	suite.Suite
+}
+
+func noop(s db.Schema) error {
+	return nil
+}
+
+func versions(migrations []db.Migration) []int {
+	v := []int{}
+	for _, m := range migrations {
+		v = append(v, m.Version)
+	}
+	return v
+}
+
+func (suite *PendingMigrationsTestSuite) TestSortedByVersion() {
+	pending, err := db.PendingMigrations([]db.Migration{
+		{Version: 3, Name: "three", Up: noop},
+		{Version: 1, Name: "one", Up: noop},
+		{Version: 10, Name: "ten", Up: noop},
+		{Version: 2, Name: "two", Up: noop},
+	}, nil)
+	suite.NoError(err)
+	suite.Equal([]int{1, 2, 3, 10}, versions(pending))
+}
+
+func (suite *PendingMigrationsTestSuite) TestSkipsDone() {
+	pending, err := db.PendingMigrations([]db.Migration{
+		{Version: 1, Name: "one", Up: noop},
+		{Version: 2, Name: "two", Up: noop},
+		{Version: 3, Name: "three", Up: noop},
+	}, []*db.MigrationVersion{
+		{Version: 1, Name: "one"},
+		{Version: 3, Name: "three"},
+	})
+	suite.NoError(err)
+	suite.Equal([]int{2}, versions(pending))
+}
+
+func (suite *PendingMigrationsTestSuite) TestAllDone() {
+	pending, err := db.PendingMigrations([]db.Migration{
+		{Version: 1, Name: "one", Up: noop},
+	}, []*db.MigrationVersion{
+		{Version: 1, Name: "one"},
+		{Version: 2, Name: "a migration from a newer release"},
+	})
+	suite.NoError(err)
+	suite.Empty(pending)
+}
+
+func (suite *PendingMigrationsTestSuite) TestDuplicateVersion() {
+	pending, err := db.PendingMigrations([]db.Migration{
+		{Version: 1, Name: "one", Up: noop},
+		{Version: 2, Name: "two", Up: noop},
+		{Version: 1, Name: "also one", Up: noop},
+	}, nil)
+	suite.EqualError(err, `migration "also one" has duplicate version 1`)
+	suite.Nil(pending)
+}
+
+func (suite *PendingMigrationsTestSuite) TestDuplicateVersionAlreadyDone() {
+	// a duplicate should be caught even if the version it clashes with has already been run
+	_, err := db.PendingMigrations([]db.Migration{
+		{Version: 1, Name: "one", Up: noop},
+		{Version: 1, Name: "also one", Up: noop},
+	}, []*db.MigrationVersion{
+		{Version: 1, Name: "one"},
+	})
+	suite.EqualError(err, `migration "also one" has duplicate version 1`)
+}
+
+func (suite *PendingMigrationsTestSuite) TestInvalidVersion() {
+	_, err := db.PendingMigrations([]db.Migration{
+		{Version: 1, Name: "one", Up: noop},
+		{Version: 0, Name: "zero", Up: noop},
+	}, nil)
+	suite.EqualError(err, `migration "zero" has invalid version 0`)
+
+	_, err = db.PendingMigrations([]db.Migration{
+		{Version: -1, Name: "negative", Up: noop},
+	}, nil)
+	suite.EqualError(err, `migration "negative" has invalid version -1`)
+}
+
+func TestPendingMigrationsTestSuite(t *testing.T) {
+	suite.Run(t, new(PendingMigrationsTestSuite))
+}
@@ -0,0 +1,57 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// conversationStatuses creates the table that holds which statuses are part of which conversations, and moves
+// conversations over to it from the status_ids array column that they used to keep their statuses in.
+var conversationStatuses = db.Migration{
+	Version: 17,
+	Name:    "conversation_statuses",
+	Up: func(s db.Schema) error {
+		if err := s.CreateTable(&gtsmodel.ConversationStatus{}); err != nil {
+			return err
+		}
+		if err := s.CreateIndex(&gtsmodel.ConversationStatus{}, "conversation_statuses_status_id_idx", "status_id"); err != nil {
+			return err
+		}
+
+		hasStatusIDs, err := s.HasColumn(&gtsmodel.Conversation{}, "status_ids")
+		if err != nil || !hasStatusIDs {
+			return err
+		}
+
+		switch s.Type() {
+		case db.DBTypePostgres:
+			err = s.Exec("INSERT INTO conversation_statuses (conversation_id, status_id) SELECT id, unnest(status_ids) FROM conversations ON CONFLICT DO NOTHING")
+		case db.DBTypeSQLite:
+			// arrays are stored as json in sqlite
+			err = s.Exec("INSERT OR IGNORE INTO conversation_statuses (conversation_id, status_id) SELECT conversations.id, json_each.value FROM conversations, json_each(conversations.status_ids) WHERE conversations.status_ids IS NOT NULL")
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.Exec("ALTER TABLE conversations DROP COLUMN status_ids")
+	},
+}
@@ -0,0 +1,103 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/db/migrations"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type ConversationStatusesTestSuite struct {
+	suite.Suite
+	db db.DB
+}
+
+func (suite *ConversationStatusesTestSuite) SetupTest() {
+	suite.db = testrig.NewTestDB()
+}
+
+func (suite *ConversationStatusesTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+	suite.NoError(suite.db.DropTable(context.Background(), &db.MigrationVersion{}))
+}
+
+func (suite *ConversationStatusesTestSuite) TestUpgradePopulatedTable() {
+	ctx := context.Background()
+	all := migrations.All()
+	before := []db.Migration{}
+	for _, m := range all {
+		if m.Name == "conversation_statuses" {
+			break
+		}
+		before = append(before, m)
+	}
+
+	_, err := suite.db.Migrate(ctx, before[:len(before)-1])
+	suite.NoError(err)
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.Conversation{
+		ID:           "01FJ0C0SE0YHJ4BQ4X0D6EMX4S",
+		AccountID:    "01F8MH1H7YV1Z7D2C8K2730QBF",
+		ThreadURI:    "http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY",
+		LastStatusID: "01F8MH75CBF9JFX4ZAD54N0W0R",
+	}))
+
+	// set up conversations the way they were before the migration, with their statuses in an array column
+	_, err = suite.db.Migrate(ctx, append(before[:len(before)-1:len(before)-1], db.Migration{
+		Version: before[len(before)-1].Version,
+		Name:    "add conversation status ids",
+		Up: func(s db.Schema) error {
+			switch s.Type() {
+			case db.DBTypePostgres:
+				if err := s.Exec("ALTER TABLE conversations ADD COLUMN status_ids VARCHAR[]"); err != nil {
+					return err
+				}
+				return s.Exec("UPDATE conversations SET status_ids = ARRAY['01F8MHAMCHF6Y650WCRSCP4WMY', '01F8MH75CBF9JFX4ZAD54N0W0R']")
+			default:
+				if err := s.Exec("ALTER TABLE conversations ADD COLUMN status_ids VARCHAR"); err != nil {
+					return err
+				}
+				return s.Exec(`UPDATE conversations SET status_ids = '["01F8MHAMCHF6Y650WCRSCP4WMY","01F8MH75CBF9JFX4ZAD54N0W0R"]'`)
+			}
+		},
+	}))
+	suite.NoError(err)
+
+	ran, err := suite.db.Migrate(ctx, all)
+	suite.NoError(err)
+	suite.Equal("conversation_statuses", ran[0].Name)
+
+	conversationStatuses := []*gtsmodel.ConversationStatus{}
+	suite.NoError(suite.db.GetWhere(ctx, []db.Where{{Key: "conversation_id", Value: "01FJ0C0SE0YHJ4BQ4X0D6EMX4S"}}, &conversationStatuses))
+	suite.Len(conversationStatuses, 2)
+
+	conversations, err := suite.db.GetConversationsForStatus(ctx, "01F8MH75CBF9JFX4ZAD54N0W0R")
+	suite.NoError(err)
+	suite.Len(conversations, 1)
+	suite.Equal("01FJ0C0SE0YHJ4BQ4X0D6EMX4S", conversations[0].ID)
+}
+
+func TestConversationStatusesTestSuite(t *testing.T) {
+	suite.Run(t, new(ConversationStatusesTestSuite))
+}
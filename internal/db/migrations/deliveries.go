@@ -0,0 +1,43 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// deliveries creates the table that holds outgoing deliveries waiting to be retried,
+// and adds the columns used to track unreachable instances.
+var deliveries = db.Migration{
+	Version: 3,
+	Name:    "deliveries",
+	Up: func(s db.Schema) error {
+		if err := s.CreateTable(&gtsmodel.Delivery{}); err != nil {
+			return err
+		}
+		if err := s.CreateIndex(&gtsmodel.Delivery{}, "deliveries_next_attempt_at_idx", "next_attempt_at"); err != nil {
+			return err
+		}
+		if err := s.AddColumn(&gtsmodel.Instance{}, "delivery_failures"); err != nil {
+			return err
+		}
+		return s.AddColumn(&gtsmodel.Instance{}, "unreachable_since")
+	},
+}
@@ -0,0 +1,36 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// deliveryIndexes adds the indexes used to summarize deliveries per domain, and to prune deliveries that were given up on.
+var deliveryIndexes = db.Migration{
+	Version: 15,
+	Name:    "delivery_indexes",
+	Up: func(s db.Schema) error {
+		if err := s.CreateIndex(&gtsmodel.Delivery{}, "deliveries_domain_idx", "domain"); err != nil {
+			return err
+		}
+		return s.CreateIndex(&gtsmodel.Delivery{}, "deliveries_failed_at_idx", "failed_at")
+	},
+}
@@ -0,0 +1,64 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// initialSchema creates all the tables that existed before versioned migrations were introduced.
+// On databases created before then, all of these tables will already exist, so this does nothing.
+var initialSchema = db.Migration{
+	Version: 1,
+	Name:    "initial schema",
+	Up: func(s db.Schema) error {
+		models := []interface{}{
+			&gtsmodel.Account{},
+			&gtsmodel.Application{},
+			&gtsmodel.Block{},
+			&gtsmodel.DomainBlock{},
+			&gtsmodel.EmailDomainBlock{},
+			&gtsmodel.Follow{},
+			&gtsmodel.FollowRequest{},
+			&gtsmodel.MediaAttachment{},
+			&gtsmodel.Mention{},
+			&gtsmodel.Status{},
+			&gtsmodel.StatusFave{},
+			&gtsmodel.StatusBookmark{},
+			&gtsmodel.StatusMute{},
+			&gtsmodel.Tag{},
+			&gtsmodel.User{},
+			&gtsmodel.Emoji{},
+			&gtsmodel.Instance{},
+			&gtsmodel.Notification{},
+			&gtsmodel.RouterSession{},
+			&oauth.Token{},
+			&oauth.Client{},
+		}
+
+		for _, m := range models {
+			if err := s.CreateTable(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,45 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// lists creates the tables that hold lists, and the accounts that are members of them.
+var lists = db.Migration{
+	Version: 4,
+	Name:    "lists",
+	Up: func(s db.Schema) error {
+		if err := s.CreateTable(&gtsmodel.List{}); err != nil {
+			return err
+		}
+		if err := s.CreateIndex(&gtsmodel.List{}, "lists_account_id_idx", "account_id"); err != nil {
+			return err
+		}
+		if err := s.CreateTable(&gtsmodel.ListEntry{}); err != nil {
+			return err
+		}
+		if err := s.CreateIndex(&gtsmodel.ListEntry{}, "list_entries_account_id_idx", "account_id"); err != nil {
+			return err
+		}
+		return s.CreateIndex(&gtsmodel.ListEntry{}, "list_entries_follow_id_idx", "follow_id")
+	},
+}
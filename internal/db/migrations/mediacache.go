@@ -0,0 +1,39 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// mediaCache adds the columns used to track which remote media is cached in storage, and when it was last accessed.
+var mediaCache = db.Migration{
+	Version: 12,
+	Name:    "media_cache",
+	Up: func(s db.Schema) error {
+		if err := s.AddColumn(&gtsmodel.MediaAttachment{}, "cached"); err != nil {
+			return err
+		}
+		if err := s.AddColumn(&gtsmodel.MediaAttachment{}, "last_accessed_at"); err != nil {
+			return err
+		}
+		return s.CreateIndex(&gtsmodel.MediaAttachment{}, "media_attachments_last_accessed_at_idx", "last_accessed_at")
+	},
+}
@@ -0,0 +1,110 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/db/migrations"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type MediaCacheTestSuite struct {
+	suite.Suite
+	db db.DB
+}
+
+func (suite *MediaCacheTestSuite) SetupTest() {
+	suite.db = testrig.NewTestDB()
+}
+
+func (suite *MediaCacheTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+	suite.NoError(suite.db.DropTable(context.Background(), &db.MigrationVersion{}))
+}
+
+func (suite *MediaCacheTestSuite) TestUpgradePopulatedTable() {
+	ctx := context.Background()
+	all := migrations.All()
+	before := []db.Migration{}
+	for _, m := range all {
+		if m.Name == "media_cache" {
+			break
+		}
+		before = append(before, m)
+	}
+
+	// set up the schema as it was before the media cache migration...
+	_, err := suite.db.Migrate(ctx, before[:len(before)-1])
+	suite.NoError(err)
+
+	// ...with some attachments in it
+	attachments := testrig.NewTestAttachments()
+	for _, a := range attachments {
+		suite.NoError(suite.db.Put(ctx, a))
+	}
+
+	// the initial schema is created from the current model, so drop the new columns again
+	_, err = suite.db.Migrate(ctx, append(before[:len(before)-1:len(before)-1], db.Migration{
+		Version: before[len(before)-1].Version,
+		Name:    "drop media cache columns",
+		Up: func(s db.Schema) error {
+			if err := s.Exec("DROP INDEX IF EXISTS media_attachments_last_accessed_at_idx"); err != nil {
+				return err
+			}
+			if err := s.Exec("ALTER TABLE media_attachments DROP COLUMN cached"); err != nil {
+				return err
+			}
+			return s.Exec("ALTER TABLE media_attachments DROP COLUMN last_accessed_at")
+		},
+	}))
+	suite.NoError(err)
+
+	ran, err := suite.db.Migrate(ctx, all)
+	suite.NoError(err)
+	suite.Equal("media_cache", ran[0].Name)
+
+	// existing attachments should count as cached and recently accessed
+	for _, a := range attachments {
+		upgraded := &gtsmodel.MediaAttachment{}
+		suite.NoError(suite.db.GetByID(ctx, a.ID, upgraded))
+		suite.True(upgraded.Cached)
+		suite.False(upgraded.LastAccessedAt.IsZero())
+	}
+
+	// new attachments should still get their defaults
+	newAttachment := &gtsmodel.MediaAttachment{
+		ID:        "01FJ0BVQGC8WRSQ4BFJ7ZJYBGK",
+		AccountID: "01F8MH1H7YV1Z7D2C8K2730QBF",
+		Type:      gtsmodel.FileTypeImage,
+		Cached:    true,
+	}
+	suite.NoError(suite.db.Put(ctx, newAttachment))
+	fetched := &gtsmodel.MediaAttachment{}
+	suite.NoError(suite.db.GetByID(ctx, newAttachment.ID, fetched))
+	suite.False(fetched.LastAccessedAt.IsZero())
+}
+
+func TestMediaCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(MediaCacheTestSuite))
+}
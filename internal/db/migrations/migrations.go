@@ -0,0 +1,52 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package migrations contains the ordered list of schema migrations for the gotosocial database.
+//
+// To change the schema, add a new migration with the next version to the end of the list returned by All,
+// rather than changing an existing migration: existing migrations may already have been run against a database.
+//
+// Note that migrations create tables from the current gtsmodel structs, so on a fresh database the initial schema
+// will already contain columns that later migrations add. That's why every Schema function is a no-op if the
+// table, column or index in question already exists.
+package migrations
+
+import "github.com/superseriousbusiness/gotosocial/internal/db"
+
+// All returns all migrations, in ascending order of version.
+func All() []db.Migration {
+	return []db.Migration{
+		initialSchema,
+		queuedMessages,
+		deliveries,
+		lists,
+		filters,
+		polls,
+		mutes,
+		scheduledStatuses,
+		reports,
+		conversations,
+		followedTags,
+		mediaCache,
+		scheduledStatusFailures,
+		queuedMessageSteps,
+		deliveryIndexes,
+		scheduledStatusRetries,
+		conversationStatuses,
+	}
+}
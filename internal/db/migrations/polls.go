@@ -0,0 +1,42 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// polls creates the tables that hold polls attached to statuses, and the votes made in them.
+var polls = db.Migration{
+	Version: 6,
+	Name:    "polls",
+	Up: func(s db.Schema) error {
+		if err := s.CreateTable(&gtsmodel.Poll{}); err != nil {
+			return err
+		}
+		if err := s.CreateIndex(&gtsmodel.Poll{}, "polls_expires_at_idx", "expires_at"); err != nil {
+			return err
+		}
+		if err := s.CreateTable(&gtsmodel.PollVote{}); err != nil {
+			return err
+		}
+		return s.CreateIndex(&gtsmodel.PollVote{}, "poll_votes_account_id_idx", "account_id")
+	},
+}
@@ -0,0 +1,36 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// queuedMessages creates the table that the processor uses to persist messages waiting to be processed.
+var queuedMessages = db.Migration{
+	Version: 2,
+	Name:    "queued messages",
+	Up: func(s db.Schema) error {
+		if err := s.CreateTable(&gtsmodel.QueuedMessage{}); err != nil {
+			return err
+		}
+		return s.CreateIndex(&gtsmodel.QueuedMessage{}, "queued_messages_next_attempt_at_idx", "next_attempt_at")
+	},
+}
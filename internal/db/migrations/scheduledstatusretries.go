@@ -0,0 +1,37 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// scheduledStatusRetries adds the columns used to back off between attempts to publish a scheduled status,
+// and to recognise a scheduled status that was already published.
+var scheduledStatusRetries = db.Migration{
+	Version: 16,
+	Name:    "scheduled_status_retries",
+	Up: func(s db.Schema) error {
+		if err := s.AddColumn(&gtsmodel.ScheduledStatus{}, "next_attempt_at"); err != nil {
+			return err
+		}
+		return s.AddColumn(&gtsmodel.ScheduledStatus{}, "status_id")
+	},
+}
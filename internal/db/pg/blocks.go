@@ -19,15 +19,17 @@
 package pg
 
 import (
+	"context"
+
 	"github.com/go-pg/pg/v10"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (ps *postgresService) GetBlocksForAccount(accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Account, string, string, error) {
+func (ps *postgresService) GetBlocksForAccount(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Account, string, string, error) {
 	blocks := []*gtsmodel.Block{}
 
-	fq := ps.conn.Model(&blocks).
+	fq := ps.conn.ModelContext(ctx, &blocks).
 		Where("block.account_id = ?", accountID).
 		Relation("TargetAccount").
 		Order("block.id DESC")
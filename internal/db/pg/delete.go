@@ -19,14 +19,15 @@
 package pg
 
 import (
+	"context"
 	"errors"
 
 	"github.com/go-pg/pg/v10"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 )
 
-func (ps *postgresService) DeleteByID(id string, i interface{}) error {
-	if _, err := ps.conn.Model(i).Where("id = ?", id).Delete(); err != nil {
+func (ps *postgresService) DeleteByID(ctx context.Context, id string, i interface{}) error {
+	if _, err := ps.conn.ModelContext(ctx, i).Where("id = ?", id).Delete(); err != nil {
 		// if there are no rows *anyway* then that's fine
 		// just return err if there's an actual error
 		if err != pg.ErrNoRows {
@@ -36,12 +37,12 @@ func (ps *postgresService) DeleteByID(id string, i interface{}) error {
 	return nil
 }
 
-func (ps *postgresService) DeleteWhere(where []db.Where, i interface{}) error {
+func (ps *postgresService) DeleteWhere(ctx context.Context, where []db.Where, i interface{}) error {
 	if len(where) == 0 {
 		return errors.New("no queries provided")
 	}
 
-	q := ps.conn.Model(i)
+	q := ps.conn.ModelContext(ctx, i)
 	for _, w := range where {
 		q = q.Where("? = ?", pg.Safe(w.Key), w.Value)
 	}
@@ -0,0 +1,93 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (ps *postgresService) GetDueDeliveries(ctx context.Context, limit int) ([]*gtsmodel.Delivery, error) {
+	deliveries := []*gtsmodel.Delivery{}
+
+	q := ps.conn.ModelContext(ctx, &deliveries).
+		Where("next_attempt_at <= ?", time.Now()).
+		Where("failed_at IS NULL").
+		Order("next_attempt_at ASC", "id ASC")
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return deliveries, nil
+}
+
+func (ps *postgresService) GetDeliveryStats(ctx context.Context, domain string) ([]*db.DeliveryStats, error) {
+	stats := []*db.DeliveryStats{}
+
+	q := ps.conn.ModelContext(ctx, (*gtsmodel.Delivery)(nil)).
+		ColumnExpr("delivery.domain").
+		ColumnExpr("SUM(CASE WHEN delivery.failed_at IS NULL THEN 1 ELSE 0 END) AS pending").
+		ColumnExpr("SUM(CASE WHEN delivery.failed_at IS NULL THEN 0 ELSE 1 END) AS failed").
+		ColumnExpr("MIN(CASE WHEN delivery.failed_at IS NULL THEN delivery.next_attempt_at END) AS next_attempt_at").
+		ColumnExpr("MAX(CASE WHEN delivery.attempts > 0 THEN delivery.updated_at END) AS last_attempt_at").
+		ColumnExpr(deliveryLastErrorExpr).
+		ColumnExpr(deliveryUnreachableSinceExpr).
+		Group("delivery.domain").
+		Order("delivery.domain ASC")
+
+	if domain != "" {
+		q = q.Where("LOWER(delivery.domain) = LOWER(?)", domain)
+	}
+
+	if err := q.Select(&stats); err != nil {
+		return nil, err
+	}
+
+	if len(stats) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return stats, nil
+}
+
+func (ps *postgresService) DeleteFailedDeliveries(ctx context.Context, olderThan time.Time) error {
+	_, err := ps.conn.ModelContext(ctx, (*gtsmodel.Delivery)(nil)).
+		Where("failed_at < ?", olderThan).
+		Delete()
+	return err
+}
+
+const (
+	// deliveryLastErrorExpr selects the error of the most recently attempted delivery to each domain.
+	deliveryLastErrorExpr = "(SELECT latest.last_error FROM deliveries AS latest WHERE latest.domain = delivery.domain AND latest.attempts > 0 ORDER BY latest.updated_at DESC LIMIT 1) AS last_error"
+	// deliveryUnreachableSinceExpr selects when the instance of each domain was marked as unreachable.
+	deliveryUnreachableSinceExpr = "(SELECT instance.unreachable_since FROM instances AS instance WHERE LOWER(instance.domain) = LOWER(delivery.domain) LIMIT 1) AS unreachable_since"
+)
@@ -19,14 +19,15 @@
 package pg
 
 import (
+	"context"
 	"errors"
 
 	"github.com/go-pg/pg/v10"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 )
 
-func (ps *postgresService) GetByID(id string, i interface{}) error {
-	if err := ps.conn.Model(i).Where("id = ?", id).Select(); err != nil {
+func (ps *postgresService) GetByID(ctx context.Context, id string, i interface{}) error {
+	if err := ps.conn.ModelContext(ctx, i).Where("id = ?", id).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
@@ -36,12 +37,12 @@ func (ps *postgresService) GetByID(id string, i interface{}) error {
 	return nil
 }
 
-func (ps *postgresService) GetWhere(where []db.Where, i interface{}) error {
+func (ps *postgresService) GetWhere(ctx context.Context, where []db.Where, i interface{}) error {
 	if len(where) == 0 {
 		return errors.New("no queries provided")
 	}
 
-	q := ps.conn.Model(i)
+	q := ps.conn.ModelContext(ctx, i)
 	for _, w := range where {
 
 		if w.Value == nil {
@@ -64,8 +65,8 @@ func (ps *postgresService) GetWhere(where []db.Where, i interface{}) error {
 	return nil
 }
 
-func (ps *postgresService) GetAll(i interface{}) error {
-	if err := ps.conn.Model(i).Select(); err != nil {
+func (ps *postgresService) GetAll(ctx context.Context, i interface{}) error {
+	if err := ps.conn.ModelContext(ctx, i).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
@@ -19,13 +19,15 @@
 package pg
 
 import (
+	"context"
+
 	"github.com/go-pg/pg/v10"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (ps *postgresService) GetUserCountForInstance(domain string) (int, error) {
-	q := ps.conn.Model(&[]*gtsmodel.Account{})
+func (ps *postgresService) GetUserCountForInstance(ctx context.Context, domain string) (int, error) {
+	q := ps.conn.ModelContext(ctx, &[]*gtsmodel.Account{})
 
 	if domain == ps.config.Host {
 		// if the domain is *this* domain, just count where the domain field is null
@@ -40,8 +42,8 @@ func (ps *postgresService) GetUserCountForInstance(domain string) (int, error) {
 	return q.Count()
 }
 
-func (ps *postgresService) GetStatusCountForInstance(domain string) (int, error) {
-	q := ps.conn.Model(&[]*gtsmodel.Status{})
+func (ps *postgresService) GetStatusCountForInstance(ctx context.Context, domain string) (int, error) {
+	q := ps.conn.ModelContext(ctx, &[]*gtsmodel.Status{})
 
 	if domain == ps.config.Host {
 		// if the domain is *this* domain, just count where local is true
@@ -55,8 +57,8 @@ func (ps *postgresService) GetStatusCountForInstance(domain string) (int, error)
 	return q.Count()
 }
 
-func (ps *postgresService) GetDomainCountForInstance(domain string) (int, error) {
-	q := ps.conn.Model(&[]*gtsmodel.Instance{})
+func (ps *postgresService) GetDomainCountForInstance(ctx context.Context, domain string) (int, error) {
+	q := ps.conn.ModelContext(ctx, &[]*gtsmodel.Instance{})
 
 	if domain == ps.config.Host {
 		// if the domain is *this* domain, just count other instances it knows about
@@ -70,12 +72,12 @@ func (ps *postgresService) GetDomainCountForInstance(domain string) (int, error)
 	return q.Count()
 }
 
-func (ps *postgresService) GetAccountsForInstance(domain string, maxID string, limit int) ([]*gtsmodel.Account, error) {
+func (ps *postgresService) GetAccountsForInstance(ctx context.Context, domain string, maxID string, limit int) ([]*gtsmodel.Account, error) {
 	ps.log.Debug("GetAccountsForInstance")
 
 	accounts := []*gtsmodel.Account{}
 
-	q := ps.conn.Model(&accounts).Where("domain = ?", domain).Order("id DESC")
+	q := ps.conn.ModelContext(ctx, &accounts).Where("domain = ?", domain).Order("id DESC")
 
 	if maxID != "" {
 		q = q.Where("id < ?", maxID)
@@ -0,0 +1,80 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (ps *postgresService) GetCachedRemoteMedia(ctx context.Context, olderThan time.Time, sinceID string, limit int) ([]*gtsmodel.MediaAttachment, error) {
+	attachments := []*gtsmodel.MediaAttachment{}
+
+	q := ps.conn.ModelContext(ctx, &attachments).
+		Where("cached = ?", true).
+		Where("remote_url IS NOT NULL").
+		Where("remote_url != ''").
+		Where("last_accessed_at < ?", olderThan).
+		Order("id ASC")
+
+	if sinceID != "" {
+		q = q.Where("id > ?", sinceID)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(attachments) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return attachments, nil
+}
+
+func (ps *postgresService) GetMediaAttachments(ctx context.Context, maxID string, limit int) ([]*gtsmodel.MediaAttachment, error) {
+	attachments := []*gtsmodel.MediaAttachment{}
+
+	q := ps.conn.ModelContext(ctx, &attachments).Order("id DESC")
+
+	if maxID != "" {
+		q = q.Where("id < ?", maxID)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(attachments) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return attachments, nil
+}
@@ -0,0 +1,56 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pg
+
+import (
+	"context"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/metrics"
+)
+
+// queryMetricsHook is a go-pg query hook that records how long each query took.
+type queryMetricsHook struct{}
+
+func (h queryMetricsHook) BeforeQuery(ctx context.Context, evt *pg.QueryEvent) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h queryMetricsHook) AfterQuery(ctx context.Context, evt *pg.QueryEvent) error {
+	metrics.ObserveDBQuery(db.DBTypePostgres, queryOperation(evt), evt.StartTime)
+	return nil
+}
+
+// queryOperation returns the kind of query that evt is for, eg., select, insert, update.
+func queryOperation(evt *pg.QueryEvent) string {
+	switch evt.Query.(type) {
+	case *orm.SelectQuery:
+		return "select"
+	case *orm.InsertQuery:
+		return "insert"
+	case *orm.UpdateQuery:
+		return "update"
+	case *orm.DeleteQuery:
+		return "delete"
+	default:
+		return "other"
+	}
+}
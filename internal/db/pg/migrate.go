@@ -0,0 +1,158 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pg
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+)
+
+func (ps *postgresService) Migrate(ctx context.Context, migrations []db.Migration) ([]db.Migration, error) {
+	if err := ps.conn.ModelContext(ctx, &db.MigrationVersion{}).CreateTable(&orm.CreateTableOptions{
+		IfNotExists: true,
+	}); err != nil {
+		return nil, fmt.Errorf("error creating migration versions table: %s", err)
+	}
+
+	done, err := ps.GetMigrationVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := db.PendingMigrations(migrations, done)
+	if err != nil {
+		return nil, err
+	}
+
+	ran := []db.Migration{}
+	for _, m := range pending {
+		ps.log.Infof("running migration %d: %s", m.Version, m.Name)
+		if err := ps.conn.RunInTransaction(ctx, func(tx *pg.Tx) error {
+			if err := m.Up(&pgSchema{tx: tx}); err != nil {
+				return err
+			}
+			_, err := tx.Model(&db.MigrationVersion{
+				Version: m.Version,
+				Name:    m.Name,
+			}).Insert()
+			return err
+		}); err != nil {
+			return ran, fmt.Errorf("error running migration %d (%s): %s", m.Version, m.Name, err)
+		}
+		ran = append(ran, m)
+	}
+
+	return ran, nil
+}
+
+func (ps *postgresService) GetMigrationVersions(ctx context.Context) ([]*db.MigrationVersion, error) {
+	versions := []*db.MigrationVersion{}
+	if err := ps.conn.ModelContext(ctx, &versions).Order("version ASC").Select(); err != nil {
+		if pgErr, ok := err.(pg.Error); ok && pgErr.Field('C') == "42P01" {
+			// undefined table: no migrations have been run yet
+			return versions, nil
+		}
+		return nil, err
+	}
+	return versions, nil
+}
+
+// pgSchema implements db.Schema for a postgres transaction.
+type pgSchema struct {
+	tx *pg.Tx
+}
+
+func (s *pgSchema) Type() string {
+	return db.DBTypePostgres
+}
+
+func (s *pgSchema) CreateTable(i interface{}) error {
+	return s.tx.Model(i).CreateTable(&orm.CreateTableOptions{
+		IfNotExists: true,
+	})
+}
+
+func (s *pgSchema) DropTable(i interface{}) error {
+	return s.tx.Model(i).DropTable(&orm.DropTableOptions{
+		IfExists: true,
+	})
+}
+
+func (s *pgSchema) AddColumn(i interface{}, column string) error {
+	t := reflect.TypeOf(i)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("model %T was not a pointer to a struct", i)
+	}
+
+	table := orm.GetTable(t.Elem())
+	field, err := table.GetField(column)
+	if err != nil {
+		return err
+	}
+
+	def := fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", table.SQLName, field.Column, field.SQLType)
+	for _, opt := range strings.Split(field.Field.Tag.Get("pg"), ",") {
+		switch opt {
+		case "notnull":
+			def += " NOT NULL"
+		case "unique":
+			def += " UNIQUE"
+		}
+	}
+	if field.Default != "" {
+		def += " DEFAULT " + string(field.Default)
+	}
+
+	_, err = s.tx.Exec(def)
+	return err
+}
+
+func (s *pgSchema) HasColumn(i interface{}, column string) (bool, error) {
+	t := reflect.TypeOf(i)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return false, fmt.Errorf("model %T was not a pointer to a struct", i)
+	}
+
+	table := orm.GetTable(t.Elem())
+	var exists bool
+	_, err := s.tx.QueryOne(pg.Scan(&exists), "SELECT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?)", strings.Trim(string(table.SQLName), `"`), column)
+	return exists, err
+}
+
+func (s *pgSchema) CreateIndex(i interface{}, name string, columns ...string) error {
+	t := reflect.TypeOf(i)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("model %T was not a pointer to a struct", i)
+	}
+
+	table := orm.GetTable(t.Elem())
+	_, err := s.tx.Exec("CREATE INDEX IF NOT EXISTS ? ON ? (?)", pg.Ident(name), table.SQLName, pg.Safe(strings.Join(columns, ", ")))
+	return err
+}
+
+func (s *pgSchema) Exec(query string, args ...interface{}) error {
+	_, err := s.tx.Exec(query, args...)
+	return err
+}
@@ -66,6 +66,21 @@ func NewPostgresService(ctx context.Context, c *config.Config, log *logrus.Logge
 	pgCtx, cancel := context.WithCancel(ctx)
 	conn := pg.Connect(opts).WithContext(pgCtx)
 
+	// trace queries, if enabled; this hook comes first so that the span covers the query timeout too
+	if c.TracingConfig.Enabled {
+		conn.AddQueryHook(queryTracingHook{})
+	}
+
+	// make sure no single query can hang around forever
+	if c.DBConfig.QueryTimeout > 0 {
+		conn.AddQueryHook(queryTimeoutHook{
+			timeout: time.Duration(c.DBConfig.QueryTimeout) * time.Second,
+		})
+	}
+
+	// record how long queries take
+	conn.AddQueryHook(queryMetricsHook{})
+
 	// this will break the logfmt format we normally log in,
 	// since we can't choose where pg outputs to and it defaults to
 	// stdout. So use this option with care!
@@ -203,14 +218,14 @@ func derivePGOptions(c *config.Config) (*pg.Options, error) {
 	BASIC DB FUNCTIONALITY
 */
 
-func (ps *postgresService) CreateTable(i interface{}) error {
-	return ps.conn.Model(i).CreateTable(&orm.CreateTableOptions{
+func (ps *postgresService) CreateTable(ctx context.Context, i interface{}) error {
+	return ps.conn.ModelContext(ctx, i).CreateTable(&orm.CreateTableOptions{
 		IfNotExists: true,
 	})
 }
 
-func (ps *postgresService) DropTable(i interface{}) error {
-	return ps.conn.Model(i).DropTable(&orm.DropTableOptions{
+func (ps *postgresService) DropTable(ctx context.Context, i interface{}) error {
+	return ps.conn.ModelContext(ctx, i).DropTable(&orm.DropTableOptions{
 		IfExists: true,
 	})
 }
@@ -238,7 +253,7 @@ func (ps *postgresService) CreateSchema(ctx context.Context) error {
 	ps.log.Info("creating db schema")
 
 	for _, model := range models {
-		err := ps.conn.Model(model).CreateTable(&orm.CreateTableOptions{
+		err := ps.conn.ModelContext(ctx, model).CreateTable(&orm.CreateTableOptions{
 			IfNotExists: true,
 		})
 		if err != nil {
@@ -254,10 +269,10 @@ func (ps *postgresService) CreateSchema(ctx context.Context) error {
 	HANDY SHORTCUTS
 */
 
-func (ps *postgresService) AcceptFollowRequest(originAccountID string, targetAccountID string) (*gtsmodel.Follow, error) {
+func (ps *postgresService) AcceptFollowRequest(ctx context.Context, originAccountID string, targetAccountID string) (*gtsmodel.Follow, error) {
 	// make sure the original follow request exists
 	fr := &gtsmodel.FollowRequest{}
-	if err := ps.conn.Model(fr).Where("account_id = ?", originAccountID).Where("target_account_id = ?", targetAccountID).Select(); err != nil {
+	if err := ps.conn.ModelContext(ctx, fr).Where("account_id = ?", originAccountID).Where("target_account_id = ?", targetAccountID).Select(); err != nil {
 		if err == pg.ErrMultiRows {
 			return nil, db.ErrNoEntries{}
 		}
@@ -273,19 +288,19 @@ func (ps *postgresService) AcceptFollowRequest(originAccountID string, targetAcc
 	}
 
 	// if the follow already exists, just update the URI -- we don't need to do anything else
-	if _, err := ps.conn.Model(follow).OnConflict("ON CONSTRAINT follows_account_id_target_account_id_key DO UPDATE set uri = ?", follow.URI).Insert(); err != nil {
+	if _, err := ps.conn.ModelContext(ctx, follow).OnConflict("ON CONSTRAINT follows_account_id_target_account_id_key DO UPDATE set uri = ?", follow.URI).Insert(); err != nil {
 		return nil, err
 	}
 
 	// now remove the follow request
-	if _, err := ps.conn.Model(&gtsmodel.FollowRequest{}).Where("account_id = ?", originAccountID).Where("target_account_id = ?", targetAccountID).Delete(); err != nil {
+	if _, err := ps.conn.ModelContext(ctx, &gtsmodel.FollowRequest{}).Where("account_id = ?", originAccountID).Where("target_account_id = ?", targetAccountID).Delete(); err != nil {
 		return nil, err
 	}
 
 	return follow, nil
 }
 
-func (ps *postgresService) CreateInstanceAccount() error {
+func (ps *postgresService) CreateInstanceAccount(ctx context.Context) error {
 	username := ps.config.Host
 	key, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
@@ -315,7 +330,7 @@ func (ps *postgresService) CreateInstanceAccount() error {
 		FollowingURI:          newAccountURIs.FollowingURI,
 		FeaturedCollectionURI: newAccountURIs.CollectionURI,
 	}
-	inserted, err := ps.conn.Model(a).Where("username = ?", username).SelectOrInsert()
+	inserted, err := ps.conn.ModelContext(ctx, a).Where("username = ?", username).SelectOrInsert()
 	if err != nil {
 		return err
 	}
@@ -327,7 +342,7 @@ func (ps *postgresService) CreateInstanceAccount() error {
 	return nil
 }
 
-func (ps *postgresService) CreateInstanceInstance() error {
+func (ps *postgresService) CreateInstanceInstance(ctx context.Context) error {
 	iID, err := id.NewRandomULID()
 	if err != nil {
 		return err
@@ -339,7 +354,7 @@ func (ps *postgresService) CreateInstanceInstance() error {
 		Title:  ps.config.Host,
 		URI:    fmt.Sprintf("%s://%s", ps.config.Protocol, ps.config.Host),
 	}
-	inserted, err := ps.conn.Model(i).Where("domain = ?", ps.config.Host).SelectOrInsert()
+	inserted, err := ps.conn.ModelContext(ctx, i).Where("domain = ?", ps.config.Host).SelectOrInsert()
 	if err != nil {
 		return err
 	}
@@ -351,17 +366,17 @@ func (ps *postgresService) CreateInstanceInstance() error {
 	return nil
 }
 
-func (ps *postgresService) GetAccountByUserID(userID string, account *gtsmodel.Account) error {
+func (ps *postgresService) GetAccountByUserID(ctx context.Context, userID string, account *gtsmodel.Account) error {
 	user := &gtsmodel.User{
 		ID: userID,
 	}
-	if err := ps.conn.Model(user).Where("id = ?", userID).Select(); err != nil {
+	if err := ps.conn.ModelContext(ctx, user).Where("id = ?", userID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
 		return err
 	}
-	if err := ps.conn.Model(account).Where("id = ?", user.AccountID).Select(); err != nil {
+	if err := ps.conn.ModelContext(ctx, account).Where("id = ?", user.AccountID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
@@ -370,8 +385,8 @@ func (ps *postgresService) GetAccountByUserID(userID string, account *gtsmodel.A
 	return nil
 }
 
-func (ps *postgresService) GetLocalAccountByUsername(username string, account *gtsmodel.Account) error {
-	if err := ps.conn.Model(account).Where("username = ?", username).Where("? IS NULL", pg.Ident("domain")).Select(); err != nil {
+func (ps *postgresService) GetLocalAccountByUsername(ctx context.Context, username string, account *gtsmodel.Account) error {
+	if err := ps.conn.ModelContext(ctx, account).Where("username = ?", username).Where("? IS NULL", pg.Ident("domain")).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
@@ -380,8 +395,8 @@ func (ps *postgresService) GetLocalAccountByUsername(username string, account *g
 	return nil
 }
 
-func (ps *postgresService) GetFollowRequestsForAccountID(accountID string, followRequests *[]gtsmodel.FollowRequest) error {
-	if err := ps.conn.Model(followRequests).Where("target_account_id = ?", accountID).Select(); err != nil {
+func (ps *postgresService) GetFollowRequestsForAccountID(ctx context.Context, accountID string, followRequests *[]gtsmodel.FollowRequest) error {
+	if err := ps.conn.ModelContext(ctx, followRequests).Where("target_account_id = ?", accountID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return nil
 		}
@@ -390,8 +405,8 @@ func (ps *postgresService) GetFollowRequestsForAccountID(accountID string, follo
 	return nil
 }
 
-func (ps *postgresService) GetFollowingByAccountID(accountID string, following *[]gtsmodel.Follow) error {
-	if err := ps.conn.Model(following).Where("account_id = ?", accountID).Select(); err != nil {
+func (ps *postgresService) GetFollowingByAccountID(ctx context.Context, accountID string, following *[]gtsmodel.Follow) error {
+	if err := ps.conn.ModelContext(ctx, following).Where("account_id = ?", accountID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return nil
 		}
@@ -400,9 +415,9 @@ func (ps *postgresService) GetFollowingByAccountID(accountID string, following *
 	return nil
 }
 
-func (ps *postgresService) GetFollowersByAccountID(accountID string, followers *[]gtsmodel.Follow, localOnly bool) error {
+func (ps *postgresService) GetFollowersByAccountID(ctx context.Context, accountID string, followers *[]gtsmodel.Follow, localOnly bool) error {
 
-	q := ps.conn.Model(followers)
+	q := ps.conn.ModelContext(ctx, followers)
 
 	if localOnly {
 		// for local accounts let's get where domain is null OR where domain is an empty string, just to be safe
@@ -430,8 +445,8 @@ func (ps *postgresService) GetFollowersByAccountID(accountID string, followers *
 	return nil
 }
 
-func (ps *postgresService) GetFavesByAccountID(accountID string, faves *[]gtsmodel.StatusFave) error {
-	if err := ps.conn.Model(faves).Where("account_id = ?", accountID).Select(); err != nil {
+func (ps *postgresService) GetFavesByAccountID(ctx context.Context, accountID string, faves *[]gtsmodel.StatusFave) error {
+	if err := ps.conn.ModelContext(ctx, faves).Where("account_id = ?", accountID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return nil
 		}
@@ -440,8 +455,8 @@ func (ps *postgresService) GetFavesByAccountID(accountID string, faves *[]gtsmod
 	return nil
 }
 
-func (ps *postgresService) CountStatusesByAccountID(accountID string) (int, error) {
-	count, err := ps.conn.Model(&gtsmodel.Status{}).Where("account_id = ?", accountID).Count()
+func (ps *postgresService) CountStatusesByAccountID(ctx context.Context, accountID string) (int, error) {
+	count, err := ps.conn.ModelContext(ctx, &gtsmodel.Status{}).Where("account_id = ?", accountID).Count()
 	if err != nil {
 		if err == pg.ErrNoRows {
 			return 0, nil
@@ -451,11 +466,11 @@ func (ps *postgresService) CountStatusesByAccountID(accountID string) (int, erro
 	return count, nil
 }
 
-func (ps *postgresService) GetStatusesForAccount(accountID string, limit int, excludeReplies bool, maxID string, pinnedOnly bool, mediaOnly bool) ([]*gtsmodel.Status, error) {
+func (ps *postgresService) GetStatusesForAccount(ctx context.Context, accountID string, limit int, excludeReplies bool, maxID string, pinnedOnly bool, mediaOnly bool) ([]*gtsmodel.Status, error) {
 	ps.log.Debugf("getting statuses for account %s", accountID)
 	statuses := []*gtsmodel.Status{}
 
-	q := ps.conn.Model(&statuses).Order("id DESC")
+	q := ps.conn.ModelContext(ctx, &statuses).Order("id DESC")
 	if accountID != "" {
 		q = q.Where("account_id = ?", accountID)
 	}
@@ -497,8 +512,8 @@ func (ps *postgresService) GetStatusesForAccount(accountID string, limit int, ex
 	return statuses, nil
 }
 
-func (ps *postgresService) GetLastStatusForAccountID(accountID string, status *gtsmodel.Status) error {
-	if err := ps.conn.Model(status).Order("created_at DESC").Limit(1).Where("account_id = ?", accountID).Select(); err != nil {
+func (ps *postgresService) GetLastStatusForAccountID(ctx context.Context, accountID string, status *gtsmodel.Status) error {
+	if err := ps.conn.ModelContext(ctx, status).Order("created_at DESC").Limit(1).Where("account_id = ?", accountID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
@@ -508,11 +523,11 @@ func (ps *postgresService) GetLastStatusForAccountID(accountID string, status *g
 
 }
 
-func (ps *postgresService) IsUsernameAvailable(username string) error {
+func (ps *postgresService) IsUsernameAvailable(ctx context.Context, username string) error {
 	// if no error we fail because it means we found something
 	// if error but it's not pg.ErrNoRows then we fail
 	// if err is pg.ErrNoRows we're good, we found nothing so continue
-	if err := ps.conn.Model(&gtsmodel.Account{}).Where("username = ?", username).Where("domain = ?", nil).Select(); err == nil {
+	if err := ps.conn.ModelContext(ctx, &gtsmodel.Account{}).Where("username = ?", username).Where("domain = ?", nil).Select(); err == nil {
 		return fmt.Errorf("username %s already in use", username)
 	} else if err != pg.ErrNoRows {
 		return fmt.Errorf("db error: %s", err)
@@ -520,7 +535,7 @@ func (ps *postgresService) IsUsernameAvailable(username string) error {
 	return nil
 }
 
-func (ps *postgresService) IsEmailAvailable(email string) error {
+func (ps *postgresService) IsEmailAvailable(ctx context.Context, email string) error {
 	// parse the domain from the email
 	m, err := mail.ParseAddress(email)
 	if err != nil {
@@ -529,7 +544,7 @@ func (ps *postgresService) IsEmailAvailable(email string) error {
 	domain := strings.Split(m.Address, "@")[1] // domain will always be the second part after @
 
 	// check if the email domain is blocked
-	if err := ps.conn.Model(&gtsmodel.EmailDomainBlock{}).Where("domain = ?", domain).Select(); err == nil {
+	if err := ps.conn.ModelContext(ctx, &gtsmodel.EmailDomainBlock{}).Where("domain = ?", domain).Select(); err == nil {
 		// fail because we found something
 		return fmt.Errorf("email domain %s is blocked", domain)
 	} else if err != pg.ErrNoRows {
@@ -538,7 +553,7 @@ func (ps *postgresService) IsEmailAvailable(email string) error {
 	}
 
 	// check if this email is associated with a user already
-	if err := ps.conn.Model(&gtsmodel.User{}).Where("email = ?", email).WhereOr("unconfirmed_email = ?", email).Select(); err == nil {
+	if err := ps.conn.ModelContext(ctx, &gtsmodel.User{}).Where("email = ?", email).WhereOr("unconfirmed_email = ?", email).Select(); err == nil {
 		// fail because we found something
 		return fmt.Errorf("email %s already in use", email)
 	} else if err != pg.ErrNoRows {
@@ -548,7 +563,7 @@ func (ps *postgresService) IsEmailAvailable(email string) error {
 	return nil
 }
 
-func (ps *postgresService) NewSignup(username string, reason string, requireApproval bool, email string, password string, signUpIP net.IP, locale string, appID string, emailVerified bool, admin bool) (*gtsmodel.User, error) {
+func (ps *postgresService) NewSignup(ctx context.Context, username string, reason string, requireApproval bool, email string, password string, signUpIP net.IP, locale string, appID string, emailVerified bool, admin bool) (*gtsmodel.User, error) {
 	key, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
 		ps.log.Errorf("error creating new rsa key: %s", err)
@@ -557,7 +572,7 @@ func (ps *postgresService) NewSignup(username string, reason string, requireAppr
 
 	// if something went wrong while creating a user, we might already have an account, so check here first...
 	a := &gtsmodel.Account{}
-	err = ps.conn.Model(a).Where("username = ?", username).Where("? IS NULL", pg.Ident("domain")).Select()
+	err = ps.conn.ModelContext(ctx, a).Where("username = ?", username).Where("? IS NULL", pg.Ident("domain")).Select()
 	if err != nil {
 		// there's been an actual error
 		if err != pg.ErrNoRows {
@@ -588,7 +603,7 @@ func (ps *postgresService) NewSignup(username string, reason string, requireAppr
 			FollowingURI:          newAccountURIs.FollowingURI,
 			FeaturedCollectionURI: newAccountURIs.CollectionURI,
 		}
-		if _, err = ps.conn.Model(a).Insert(); err != nil {
+		if _, err = ps.conn.ModelContext(ctx, a).Insert(); err != nil {
 			return nil, err
 		}
 	}
@@ -624,14 +639,14 @@ func (ps *postgresService) NewSignup(username string, reason string, requireAppr
 		u.Moderator = true
 	}
 
-	if _, err = ps.conn.Model(u).Insert(); err != nil {
+	if _, err = ps.conn.ModelContext(ctx, u).Insert(); err != nil {
 		return nil, err
 	}
 
 	return u, nil
 }
 
-func (ps *postgresService) SetHeaderOrAvatarForAccountID(mediaAttachment *gtsmodel.MediaAttachment, accountID string) error {
+func (ps *postgresService) SetHeaderOrAvatarForAccountID(ctx context.Context, mediaAttachment *gtsmodel.MediaAttachment, accountID string) error {
 	if mediaAttachment.Avatar && mediaAttachment.Header {
 		return errors.New("one media attachment cannot be both header and avatar")
 	}
@@ -646,19 +661,19 @@ func (ps *postgresService) SetHeaderOrAvatarForAccountID(mediaAttachment *gtsmod
 	}
 
 	// TODO: there are probably more side effects here that need to be handled
-	if _, err := ps.conn.Model(mediaAttachment).OnConflict("(id) DO UPDATE").Insert(); err != nil {
+	if _, err := ps.conn.ModelContext(ctx, mediaAttachment).OnConflict("(id) DO UPDATE").Insert(); err != nil {
 		return err
 	}
 
-	if _, err := ps.conn.Model(&gtsmodel.Account{}).Set(fmt.Sprintf("%s_media_attachment_id = ?", headerOrAVI), mediaAttachment.ID).Where("id = ?", accountID).Update(); err != nil {
+	if _, err := ps.conn.ModelContext(ctx, &gtsmodel.Account{}).Set(fmt.Sprintf("%s_media_attachment_id = ?", headerOrAVI), mediaAttachment.ID).Where("id = ?", accountID).Update(); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (ps *postgresService) GetHeaderForAccountID(header *gtsmodel.MediaAttachment, accountID string) error {
+func (ps *postgresService) GetHeaderForAccountID(ctx context.Context, header *gtsmodel.MediaAttachment, accountID string) error {
 	acct := &gtsmodel.Account{}
-	if err := ps.conn.Model(acct).Where("id = ?", accountID).Select(); err != nil {
+	if err := ps.conn.ModelContext(ctx, acct).Where("id = ?", accountID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
@@ -669,7 +684,7 @@ func (ps *postgresService) GetHeaderForAccountID(header *gtsmodel.MediaAttachmen
 		return db.ErrNoEntries{}
 	}
 
-	if err := ps.conn.Model(header).Where("id = ?", acct.HeaderMediaAttachmentID).Select(); err != nil {
+	if err := ps.conn.ModelContext(ctx, header).Where("id = ?", acct.HeaderMediaAttachmentID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
@@ -678,9 +693,9 @@ func (ps *postgresService) GetHeaderForAccountID(header *gtsmodel.MediaAttachmen
 	return nil
 }
 
-func (ps *postgresService) GetAvatarForAccountID(avatar *gtsmodel.MediaAttachment, accountID string) error {
+func (ps *postgresService) GetAvatarForAccountID(ctx context.Context, avatar *gtsmodel.MediaAttachment, accountID string) error {
 	acct := &gtsmodel.Account{}
-	if err := ps.conn.Model(acct).Where("id = ?", accountID).Select(); err != nil {
+	if err := ps.conn.ModelContext(ctx, acct).Where("id = ?", accountID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
@@ -691,7 +706,7 @@ func (ps *postgresService) GetAvatarForAccountID(avatar *gtsmodel.MediaAttachmen
 		return db.ErrNoEntries{}
 	}
 
-	if err := ps.conn.Model(avatar).Where("id = ?", acct.AvatarMediaAttachmentID).Select(); err != nil {
+	if err := ps.conn.ModelContext(ctx, avatar).Where("id = ?", acct.AvatarMediaAttachmentID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
@@ -700,10 +715,10 @@ func (ps *postgresService) GetAvatarForAccountID(avatar *gtsmodel.MediaAttachmen
 	return nil
 }
 
-func (ps *postgresService) Blocked(account1 string, account2 string) (bool, error) {
+func (ps *postgresService) Blocked(ctx context.Context, account1 string, account2 string) (bool, error) {
 	// TODO: check domain blocks as well
 	var blocked bool
-	if err := ps.conn.Model(&gtsmodel.Block{}).
+	if err := ps.conn.ModelContext(ctx, &gtsmodel.Block{}).
 		Where("account_id = ?", account1).Where("target_account_id = ?", account2).
 		WhereOr("target_account_id = ?", account1).Where("account_id = ?", account2).
 		Select(); err != nil {
@@ -717,14 +732,14 @@ func (ps *postgresService) Blocked(account1 string, account2 string) (bool, erro
 	return blocked, nil
 }
 
-func (ps *postgresService) GetRelationship(requestingAccount string, targetAccount string) (*gtsmodel.Relationship, error) {
+func (ps *postgresService) GetRelationship(ctx context.Context, requestingAccount string, targetAccount string) (*gtsmodel.Relationship, error) {
 	r := &gtsmodel.Relationship{
 		ID: targetAccount,
 	}
 
 	// check if the requesting account follows the target account
 	follow := &gtsmodel.Follow{}
-	if err := ps.conn.Model(follow).Where("account_id = ?", requestingAccount).Where("target_account_id = ?", targetAccount).Select(); err != nil {
+	if err := ps.conn.ModelContext(ctx, follow).Where("account_id = ?", requestingAccount).Where("target_account_id = ?", targetAccount).Select(); err != nil {
 		if err != pg.ErrNoRows {
 			// a proper error
 			return nil, fmt.Errorf("getrelationship: error checking follow existence: %s", err)
@@ -741,59 +756,70 @@ func (ps *postgresService) GetRelationship(requestingAccount string, targetAccou
 	}
 
 	// check if the target account follows the requesting account
-	followedBy, err := ps.conn.Model(&gtsmodel.Follow{}).Where("account_id = ?", targetAccount).Where("target_account_id = ?", requestingAccount).Exists()
+	followedBy, err := ps.conn.ModelContext(ctx, &gtsmodel.Follow{}).Where("account_id = ?", targetAccount).Where("target_account_id = ?", requestingAccount).Exists()
 	if err != nil {
 		return nil, fmt.Errorf("getrelationship: error checking followed_by existence: %s", err)
 	}
 	r.FollowedBy = followedBy
 
 	// check if the requesting account blocks the target account
-	blocking, err := ps.conn.Model(&gtsmodel.Block{}).Where("account_id = ?", requestingAccount).Where("target_account_id = ?", targetAccount).Exists()
+	blocking, err := ps.conn.ModelContext(ctx, &gtsmodel.Block{}).Where("account_id = ?", requestingAccount).Where("target_account_id = ?", targetAccount).Exists()
 	if err != nil {
 		return nil, fmt.Errorf("getrelationship: error checking blocking existence: %s", err)
 	}
 	r.Blocking = blocking
 
 	// check if the target account blocks the requesting account
-	blockedBy, err := ps.conn.Model(&gtsmodel.Block{}).Where("account_id = ?", targetAccount).Where("target_account_id = ?", requestingAccount).Exists()
+	blockedBy, err := ps.conn.ModelContext(ctx, &gtsmodel.Block{}).Where("account_id = ?", targetAccount).Where("target_account_id = ?", requestingAccount).Exists()
 	if err != nil {
 		return nil, fmt.Errorf("getrelationship: error checking blocked existence: %s", err)
 	}
 	r.BlockedBy = blockedBy
 
 	// check if there's a pending following request from requesting account to target account
-	requested, err := ps.conn.Model(&gtsmodel.FollowRequest{}).Where("account_id = ?", requestingAccount).Where("target_account_id = ?", targetAccount).Exists()
+	requested, err := ps.conn.ModelContext(ctx, &gtsmodel.FollowRequest{}).Where("account_id = ?", requestingAccount).Where("target_account_id = ?", targetAccount).Exists()
 	if err != nil {
 		return nil, fmt.Errorf("getrelationship: error checking blocked existence: %s", err)
 	}
 	r.Requested = requested
 
+	// check if the requesting account mutes the target account
+	mute, err := ps.GetMute(ctx, requestingAccount, targetAccount)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, fmt.Errorf("getrelationship: error checking mute existence: %s", err)
+		}
+	} else {
+		r.Muting = true
+		r.MutingNotifications = mute.Notifications
+	}
+
 	return r, nil
 }
 
-func (ps *postgresService) Follows(sourceAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (bool, error) {
+func (ps *postgresService) Follows(ctx context.Context, sourceAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (bool, error) {
 	if sourceAccount == nil || targetAccount == nil {
 		return false, nil
 	}
 
-	return ps.conn.Model(&gtsmodel.Follow{}).Where("account_id = ?", sourceAccount.ID).Where("target_account_id = ?", targetAccount.ID).Exists()
+	return ps.conn.ModelContext(ctx, &gtsmodel.Follow{}).Where("account_id = ?", sourceAccount.ID).Where("target_account_id = ?", targetAccount.ID).Exists()
 }
 
-func (ps *postgresService) FollowRequested(sourceAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (bool, error) {
+func (ps *postgresService) FollowRequested(ctx context.Context, sourceAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (bool, error) {
 	if sourceAccount == nil || targetAccount == nil {
 		return false, nil
 	}
 
-	return ps.conn.Model(&gtsmodel.FollowRequest{}).Where("account_id = ?", sourceAccount.ID).Where("target_account_id = ?", targetAccount.ID).Exists()
+	return ps.conn.ModelContext(ctx, &gtsmodel.FollowRequest{}).Where("account_id = ?", sourceAccount.ID).Where("target_account_id = ?", targetAccount.ID).Exists()
 }
 
-func (ps *postgresService) Mutuals(account1 *gtsmodel.Account, account2 *gtsmodel.Account) (bool, error) {
+func (ps *postgresService) Mutuals(ctx context.Context, account1 *gtsmodel.Account, account2 *gtsmodel.Account) (bool, error) {
 	if account1 == nil || account2 == nil {
 		return false, nil
 	}
 
 	// make sure account 1 follows account 2
-	f1, err := ps.conn.Model(&gtsmodel.Follow{}).Where("account_id = ?", account1.ID).Where("target_account_id = ?", account2.ID).Exists()
+	f1, err := ps.conn.ModelContext(ctx, &gtsmodel.Follow{}).Where("account_id = ?", account1.ID).Where("target_account_id = ?", account2.ID).Exists()
 	if err != nil {
 		if err == pg.ErrNoRows {
 			return false, nil
@@ -802,7 +828,7 @@ func (ps *postgresService) Mutuals(account1 *gtsmodel.Account, account2 *gtsmode
 	}
 
 	// make sure account 2 follows account 1
-	f2, err := ps.conn.Model(&gtsmodel.Follow{}).Where("account_id = ?", account2.ID).Where("target_account_id = ?", account1.ID).Exists()
+	f2, err := ps.conn.ModelContext(ctx, &gtsmodel.Follow{}).Where("account_id = ?", account2.ID).Where("target_account_id = ?", account1.ID).Exists()
 	if err != nil {
 		if err == pg.ErrNoRows {
 			return false, nil
@@ -813,39 +839,39 @@ func (ps *postgresService) Mutuals(account1 *gtsmodel.Account, account2 *gtsmode
 	return f1 && f2, nil
 }
 
-func (ps *postgresService) GetReplyCountForStatus(status *gtsmodel.Status) (int, error) {
-	return ps.conn.Model(&gtsmodel.Status{}).Where("in_reply_to_id = ?", status.ID).Count()
+func (ps *postgresService) GetReplyCountForStatus(ctx context.Context, status *gtsmodel.Status) (int, error) {
+	return ps.conn.ModelContext(ctx, &gtsmodel.Status{}).Where("in_reply_to_id = ?", status.ID).Count()
 }
 
-func (ps *postgresService) GetReblogCountForStatus(status *gtsmodel.Status) (int, error) {
-	return ps.conn.Model(&gtsmodel.Status{}).Where("boost_of_id = ?", status.ID).Count()
+func (ps *postgresService) GetReblogCountForStatus(ctx context.Context, status *gtsmodel.Status) (int, error) {
+	return ps.conn.ModelContext(ctx, &gtsmodel.Status{}).Where("boost_of_id = ?", status.ID).Count()
 }
 
-func (ps *postgresService) GetFaveCountForStatus(status *gtsmodel.Status) (int, error) {
-	return ps.conn.Model(&gtsmodel.StatusFave{}).Where("status_id = ?", status.ID).Count()
+func (ps *postgresService) GetFaveCountForStatus(ctx context.Context, status *gtsmodel.Status) (int, error) {
+	return ps.conn.ModelContext(ctx, &gtsmodel.StatusFave{}).Where("status_id = ?", status.ID).Count()
 }
 
-func (ps *postgresService) StatusFavedBy(status *gtsmodel.Status, accountID string) (bool, error) {
-	return ps.conn.Model(&gtsmodel.StatusFave{}).Where("status_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
+func (ps *postgresService) StatusFavedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error) {
+	return ps.conn.ModelContext(ctx, &gtsmodel.StatusFave{}).Where("status_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
 }
 
-func (ps *postgresService) StatusRebloggedBy(status *gtsmodel.Status, accountID string) (bool, error) {
-	return ps.conn.Model(&gtsmodel.Status{}).Where("boost_of_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
+func (ps *postgresService) StatusRebloggedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error) {
+	return ps.conn.ModelContext(ctx, &gtsmodel.Status{}).Where("boost_of_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
 }
 
-func (ps *postgresService) StatusMutedBy(status *gtsmodel.Status, accountID string) (bool, error) {
-	return ps.conn.Model(&gtsmodel.StatusMute{}).Where("status_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
+func (ps *postgresService) StatusMutedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error) {
+	return ps.conn.ModelContext(ctx, &gtsmodel.StatusMute{}).Where("status_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
 }
 
-func (ps *postgresService) StatusBookmarkedBy(status *gtsmodel.Status, accountID string) (bool, error) {
-	return ps.conn.Model(&gtsmodel.StatusBookmark{}).Where("status_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
+func (ps *postgresService) StatusBookmarkedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error) {
+	return ps.conn.ModelContext(ctx, &gtsmodel.StatusBookmark{}).Where("status_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
 }
 
-func (ps *postgresService) WhoFavedStatus(status *gtsmodel.Status) ([]*gtsmodel.Account, error) {
+func (ps *postgresService) WhoFavedStatus(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.Account, error) {
 	accounts := []*gtsmodel.Account{}
 
 	faves := []*gtsmodel.StatusFave{}
-	if err := ps.conn.Model(&faves).Where("status_id = ?", status.ID).Select(); err != nil {
+	if err := ps.conn.ModelContext(ctx, &faves).Where("status_id = ?", status.ID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return accounts, nil // no rows just means nobody has faved this status, so that's fine
 		}
@@ -854,7 +880,7 @@ func (ps *postgresService) WhoFavedStatus(status *gtsmodel.Status) ([]*gtsmodel.
 
 	for _, f := range faves {
 		acc := &gtsmodel.Account{}
-		if err := ps.conn.Model(acc).Where("id = ?", f.AccountID).Select(); err != nil {
+		if err := ps.conn.ModelContext(ctx, acc).Where("id = ?", f.AccountID).Select(); err != nil {
 			if err == pg.ErrNoRows {
 				continue // the account doesn't exist for some reason??? but this isn't the place to worry about that so just skip it
 			}
@@ -865,11 +891,11 @@ func (ps *postgresService) WhoFavedStatus(status *gtsmodel.Status) ([]*gtsmodel.
 	return accounts, nil
 }
 
-func (ps *postgresService) WhoBoostedStatus(status *gtsmodel.Status) ([]*gtsmodel.Account, error) {
+func (ps *postgresService) WhoBoostedStatus(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.Account, error) {
 	accounts := []*gtsmodel.Account{}
 
 	boosts := []*gtsmodel.Status{}
-	if err := ps.conn.Model(&boosts).Where("boost_of_id = ?", status.ID).Select(); err != nil {
+	if err := ps.conn.ModelContext(ctx, &boosts).Where("boost_of_id = ?", status.ID).Select(); err != nil {
 		if err == pg.ErrNoRows {
 			return accounts, nil // no rows just means nobody has boosted this status, so that's fine
 		}
@@ -878,7 +904,7 @@ func (ps *postgresService) WhoBoostedStatus(status *gtsmodel.Status) ([]*gtsmode
 
 	for _, f := range boosts {
 		acc := &gtsmodel.Account{}
-		if err := ps.conn.Model(acc).Where("id = ?", f.AccountID).Select(); err != nil {
+		if err := ps.conn.ModelContext(ctx, acc).Where("id = ?", f.AccountID).Select(); err != nil {
 			if err == pg.ErrNoRows {
 				continue // the account doesn't exist for some reason??? but this isn't the place to worry about that so just skip it
 			}
@@ -889,10 +915,10 @@ func (ps *postgresService) WhoBoostedStatus(status *gtsmodel.Status) ([]*gtsmode
 	return accounts, nil
 }
 
-func (ps *postgresService) GetNotificationsForAccount(accountID string, limit int, maxID string, sinceID string) ([]*gtsmodel.Notification, error) {
+func (ps *postgresService) GetNotificationsForAccount(ctx context.Context, accountID string, limit int, maxID string, sinceID string) ([]*gtsmodel.Notification, error) {
 	notifications := []*gtsmodel.Notification{}
 
-	q := ps.conn.Model(&notifications).Where("target_account_id = ?", accountID)
+	q := ps.conn.ModelContext(ctx, &notifications).Where("target_account_id = ?", accountID)
 
 	if maxID != "" {
 		q = q.Where("id < ?", maxID)
@@ -923,9 +949,9 @@ func (ps *postgresService) GetNotificationsForAccount(accountID string, limit in
 
 // TODO: move these to the type converter, it's bananas that they're here and not there
 
-func (ps *postgresService) MentionStringsToMentions(targetAccounts []string, originAccountID string, statusID string) ([]*gtsmodel.Mention, error) {
+func (ps *postgresService) MentionStringsToMentions(ctx context.Context, targetAccounts []string, originAccountID string, statusID string) ([]*gtsmodel.Mention, error) {
 	ogAccount := &gtsmodel.Account{}
-	if err := ps.conn.Model(ogAccount).Where("id = ?", originAccountID).Select(); err != nil {
+	if err := ps.conn.ModelContext(ctx, ogAccount).Where("id = ?", originAccountID).Select(); err != nil {
 		return nil, err
 	}
 
@@ -970,10 +996,10 @@ func (ps *postgresService) MentionStringsToMentions(targetAccounts []string, ori
 		// match username + account, case insensitive
 		if local {
 			// local user -- should have a null domain
-			err = ps.conn.Model(mentionedAccount).Where("LOWER(?) = LOWER(?)", pg.Ident("username"), username).Where("? IS NULL", pg.Ident("domain")).Select()
+			err = ps.conn.ModelContext(ctx, mentionedAccount).Where("LOWER(?) = LOWER(?)", pg.Ident("username"), username).Where("? IS NULL", pg.Ident("domain")).Select()
 		} else {
 			// remote user -- should have domain defined
-			err = ps.conn.Model(mentionedAccount).Where("LOWER(?) = LOWER(?)", pg.Ident("username"), username).Where("LOWER(?) = LOWER(?)", pg.Ident("domain"), domain).Select()
+			err = ps.conn.ModelContext(ctx, mentionedAccount).Where("LOWER(?) = LOWER(?)", pg.Ident("username"), username).Where("LOWER(?) = LOWER(?)", pg.Ident("domain"), domain).Select()
 		}
 
 		if err != nil {
@@ -1001,13 +1027,13 @@ func (ps *postgresService) MentionStringsToMentions(targetAccounts []string, ori
 	return menchies, nil
 }
 
-func (ps *postgresService) TagStringsToTags(tags []string, originAccountID string, statusID string) ([]*gtsmodel.Tag, error) {
+func (ps *postgresService) TagStringsToTags(ctx context.Context, tags []string, originAccountID string, statusID string) ([]*gtsmodel.Tag, error) {
 	newTags := []*gtsmodel.Tag{}
 	for _, t := range tags {
 		tag := &gtsmodel.Tag{}
 		// we can use selectorinsert here to create the new tag if it doesn't exist already
 		// inserted will be true if this is a new tag we just created
-		if err := ps.conn.Model(tag).Where("LOWER(?) = LOWER(?)", pg.Ident("name"), t).Select(); err != nil {
+		if err := ps.conn.ModelContext(ctx, tag).Where("LOWER(?) = LOWER(?)", pg.Ident("name"), t).Select(); err != nil {
 			if err == pg.ErrNoRows {
 				// tag doesn't exist yet so populate it
 				newID, err := id.NewRandomULID()
@@ -1037,11 +1063,11 @@ func (ps *postgresService) TagStringsToTags(tags []string, originAccountID strin
 	return newTags, nil
 }
 
-func (ps *postgresService) EmojiStringsToEmojis(emojis []string, originAccountID string, statusID string) ([]*gtsmodel.Emoji, error) {
+func (ps *postgresService) EmojiStringsToEmojis(ctx context.Context, emojis []string, originAccountID string, statusID string) ([]*gtsmodel.Emoji, error) {
 	newEmojis := []*gtsmodel.Emoji{}
 	for _, e := range emojis {
 		emoji := &gtsmodel.Emoji{}
-		err := ps.conn.Model(emoji).Where("shortcode = ?", e).Where("visible_in_picker = true").Where("disabled = false").Select()
+		err := ps.conn.ModelContext(ctx, emoji).Where("shortcode = ?", e).Where("visible_in_picker = true").Where("disabled = false").Select()
 		if err != nil {
 			if err == pg.ErrNoRows {
 				// no result found for this username/domain so just don't include it as an emoji and carry on about our business
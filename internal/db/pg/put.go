@@ -19,13 +19,14 @@
 package pg
 
 import (
+	"context"
 	"strings"
 
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 )
 
-func (ps *postgresService) Put(i interface{}) error {
-	_, err := ps.conn.Model(i).Insert(i)
+func (ps *postgresService) Put(ctx context.Context, i interface{}) error {
+	_, err := ps.conn.ModelContext(ctx, i).Insert(i)
 	if err != nil && strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
 		return db.ErrAlreadyExists{}
 	}
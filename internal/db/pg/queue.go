@@ -0,0 +1,53 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (ps *postgresService) GetDueQueuedMessages(ctx context.Context, limit int) ([]*gtsmodel.QueuedMessage, error) {
+	messages := []*gtsmodel.QueuedMessage{}
+
+	q := ps.conn.ModelContext(ctx, &messages).
+		Where("next_attempt_at <= ?", time.Now()).
+		Order("next_attempt_at ASC", "id ASC")
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return messages, nil
+}
+
+func (ps *postgresService) CountQueuedMessages(ctx context.Context) (int, error) {
+	return ps.conn.ModelContext(ctx, &gtsmodel.QueuedMessage{}).Count()
+}
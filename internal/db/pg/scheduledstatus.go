@@ -0,0 +1,85 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (ps *postgresService) GetScheduledStatusesForAccount(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.ScheduledStatus, error) {
+	scheduled := []*gtsmodel.ScheduledStatus{}
+
+	q := ps.conn.ModelContext(ctx, &scheduled).
+		Where("account_id = ?", accountID).
+		Order("id DESC")
+
+	if maxID != "" {
+		q = q.Where("id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("id > ?", sinceID)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, db.ErrNoEntries{}
+		}
+		return nil, err
+	}
+
+	if len(scheduled) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return scheduled, nil
+}
+
+func (ps *postgresService) GetDueScheduledStatuses(ctx context.Context, limit int) ([]*gtsmodel.ScheduledStatus, error) {
+	scheduled := []*gtsmodel.ScheduledStatus{}
+
+	q := ps.conn.ModelContext(ctx, &scheduled).
+		Where("scheduled_at <= ?", time.Now()).
+		Where("failed_at IS NULL").
+		Where("next_attempt_at IS NULL OR next_attempt_at <= ?", time.Now()).
+		Order("scheduled_at ASC", "id ASC")
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(scheduled) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return scheduled, nil
+}
@@ -19,6 +19,7 @@
 package pg
 
 import (
+	"context"
 	"sort"
 
 	"github.com/go-pg/pg/v10"
@@ -26,10 +27,10 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (ps *postgresService) GetHomeTimelineForAccount(accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, error) {
+func (ps *postgresService) GetHomeTimelineForAccount(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, error) {
 	statuses := []*gtsmodel.Status{}
 
-	q := ps.conn.Model(&statuses)
+	q := ps.conn.ModelContext(ctx, &statuses)
 
 	q = q.ColumnExpr("status.*").
 		Join("LEFT JOIN follows AS f ON f.target_account_id = status.account_id").
@@ -71,10 +72,10 @@ func (ps *postgresService) GetHomeTimelineForAccount(accountID string, maxID str
 	return statuses, nil
 }
 
-func (ps *postgresService) GetPublicTimelineForAccount(accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, error) {
+func (ps *postgresService) GetPublicTimelineForAccount(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, error) {
 	statuses := []*gtsmodel.Status{}
 
-	q := ps.conn.Model(&statuses).
+	q := ps.conn.ModelContext(ctx, &statuses).
 		Where("visibility = ?", gtsmodel.VisibilityPublic).
 		Where("? IS NULL", pg.Ident("in_reply_to_id")).
 		Where("? IS NULL", pg.Ident("in_reply_to_uri")).
@@ -118,11 +119,11 @@ func (ps *postgresService) GetPublicTimelineForAccount(accountID string, maxID s
 
 // TODO optimize this query and the logic here, because it's slow as balls -- it takes like a literal second to return with a limit of 20!
 // It might be worth serving it through a timeline instead of raw DB queries, like we do for Home feeds.
-func (ps *postgresService) GetFavedTimelineForAccount(accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Status, string, string, error) {
+func (ps *postgresService) GetFavedTimelineForAccount(ctx context.Context, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Status, string, string, error) {
 
 	faves := []*gtsmodel.StatusFave{}
 
-	fq := ps.conn.Model(&faves).
+	fq := ps.conn.ModelContext(ctx, &faves).
 		Where("account_id = ?", accountID).
 		Order("id DESC")
 
@@ -160,7 +161,7 @@ func (ps *postgresService) GetFavedTimelineForAccount(accountID string, maxID st
 	}
 
 	statuses := []*gtsmodel.Status{}
-	err = ps.conn.Model(&statuses).Where("id IN (?)", pg.In(in)).Select()
+	err = ps.conn.ModelContext(ctx, &statuses).Where("id IN (?)", pg.In(in)).Select()
 	if err != nil {
 		if err == pg.ErrNoRows {
 			return nil, "", "", db.ErrNoEntries{}
@@ -183,3 +184,66 @@ func (ps *postgresService) GetFavedTimelineForAccount(accountID string, maxID st
 	prevMinID := faves[0].ID
 	return statuses, nextMaxID, prevMinID, nil
 }
+
+func (ps *postgresService) GetBookmarkedTimelineForAccount(ctx context.Context, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Status, string, string, error) {
+	bookmarks := []*gtsmodel.StatusBookmark{}
+
+	bq := ps.conn.ModelContext(ctx, &bookmarks).
+		Where("account_id = ?", accountID).
+		Order("id DESC")
+
+	if maxID != "" {
+		bq = bq.Where("id < ?", maxID)
+	}
+
+	if minID != "" {
+		bq = bq.Where("id > ?", minID)
+	}
+
+	if limit > 0 {
+		bq = bq.Limit(limit)
+	}
+
+	err := bq.Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, "", "", db.ErrNoEntries{}
+		}
+		return nil, "", "", err
+	}
+
+	if len(bookmarks) == 0 {
+		return nil, "", "", db.ErrNoEntries{}
+	}
+
+	// map[statusID]bookmarkID -- we need this to sort statuses by bookmark ID rather than their own ID
+	statusesBookmarksMap := map[string]string{}
+
+	in := []string{}
+	for _, b := range bookmarks {
+		statusesBookmarksMap[b.StatusID] = b.ID
+		in = append(in, b.StatusID)
+	}
+
+	statuses := []*gtsmodel.Status{}
+	err = ps.conn.ModelContext(ctx, &statuses).Where("id IN (?)", pg.In(in)).Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, "", "", db.ErrNoEntries{}
+		}
+		return nil, "", "", err
+	}
+
+	if len(statuses) == 0 {
+		return nil, "", "", db.ErrNoEntries{}
+	}
+
+	// arrange statuses by bookmark ID, newest bookmark first
+	sort.Slice(statuses, func(i int, j int) bool {
+		return statusesBookmarksMap[statuses[i].ID] > statusesBookmarksMap[statuses[j].ID]
+	})
+
+	nextMaxID := bookmarks[len(bookmarks)-1].ID
+	prevMinID := bookmarks[0].ID
+	return statuses, nextMaxID, prevMinID, nil
+}
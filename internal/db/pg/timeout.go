@@ -0,0 +1,53 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+// queryTimeoutHook is a go-pg query hook that cancels any query which takes longer than timeout.
+//
+// The timeout applies on top of whatever context the query was given, so queries belonging to a
+// request will still be cancelled early if the request itself goes away.
+type queryTimeoutHook struct {
+	timeout time.Duration
+}
+
+// cancelKey is used to stash the cancel func of a query's context in its query event.
+type cancelKey struct{}
+
+func (h queryTimeoutHook) BeforeQuery(ctx context.Context, evt *pg.QueryEvent) (context.Context, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	if evt.Stash == nil {
+		evt.Stash = make(map[interface{}]interface{})
+	}
+	evt.Stash[cancelKey{}] = cancel
+	return ctx, nil
+}
+
+func (h queryTimeoutHook) AfterQuery(ctx context.Context, evt *pg.QueryEvent) error {
+	if cancel, ok := evt.Stash[cancelKey{}].(context.CancelFunc); ok {
+		cancel()
+	}
+	return nil
+}
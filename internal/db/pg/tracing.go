@@ -0,0 +1,59 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pg
+
+import (
+	"context"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/superseriousbusiness/gotosocial/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryTracingHook is a go-pg query hook that records a span for each query.
+type queryTracingHook struct{}
+
+// spanKey is used to stash the span of a query in its query event.
+type spanKey struct{}
+
+func (h queryTracingHook) BeforeQuery(ctx context.Context, evt *pg.QueryEvent) (context.Context, error) {
+	operation := queryOperation(evt)
+	ctx, span := tracing.StartSpan(ctx, "db."+operation,
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationKey.String(operation),
+	)
+	if evt.Stash == nil {
+		evt.Stash = make(map[interface{}]interface{})
+	}
+	evt.Stash[spanKey{}] = span
+	return ctx, nil
+}
+
+func (h queryTracingHook) AfterQuery(ctx context.Context, evt *pg.QueryEvent) error {
+	if span, ok := evt.Stash[spanKey{}].(trace.Span); ok {
+		err := evt.Err
+		if err == pg.ErrNoRows {
+			// not finding anything isn't a failure as far as the trace is concerned
+			err = nil
+		}
+		tracing.EndSpan(span, err)
+	}
+	return nil
+}
@@ -19,14 +19,15 @@
 package pg
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/go-pg/pg/v10"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 )
 
-func (ps *postgresService) Upsert(i interface{}, conflictColumn string) error {
-	if _, err := ps.conn.Model(i).OnConflict(fmt.Sprintf("(%s) DO UPDATE", conflictColumn)).Insert(); err != nil {
+func (ps *postgresService) Upsert(ctx context.Context, i interface{}, conflictColumn string) error {
+	if _, err := ps.conn.ModelContext(ctx, i).OnConflict(fmt.Sprintf("(%s) DO UPDATE", conflictColumn)).Insert(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
@@ -35,8 +36,8 @@ func (ps *postgresService) Upsert(i interface{}, conflictColumn string) error {
 	return nil
 }
 
-func (ps *postgresService) UpdateByID(id string, i interface{}) error {
-	if _, err := ps.conn.Model(i).Where("id = ?", id).OnConflict("(id) DO UPDATE").Insert(); err != nil {
+func (ps *postgresService) UpdateByID(ctx context.Context, id string, i interface{}) error {
+	if _, err := ps.conn.ModelContext(ctx, i).Where("id = ?", id).OnConflict("(id) DO UPDATE").Insert(); err != nil {
 		if err == pg.ErrNoRows {
 			return db.ErrNoEntries{}
 		}
@@ -45,13 +46,13 @@ func (ps *postgresService) UpdateByID(id string, i interface{}) error {
 	return nil
 }
 
-func (ps *postgresService) UpdateOneByID(id string, key string, value interface{}, i interface{}) error {
-	_, err := ps.conn.Model(i).Set("? = ?", pg.Safe(key), value).Where("id = ?", id).Update()
+func (ps *postgresService) UpdateOneByID(ctx context.Context, id string, key string, value interface{}, i interface{}) error {
+	_, err := ps.conn.ModelContext(ctx, i).Set("? = ?", pg.Safe(key), value).Where("id = ?", id).Update()
 	return err
 }
 
-func (ps *postgresService) UpdateWhere(where []db.Where, key string, value interface{}, i interface{}) error {
-	q := ps.conn.Model(i)
+func (ps *postgresService) UpdateWhere(ctx context.Context, where []db.Where, key string, value interface{}, i interface{}) error {
+	q := ps.conn.ModelContext(ctx, i)
 
 	for _, w := range where {
 		if w.Value == nil {
@@ -0,0 +1,103 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// withTimeout returns a copy of ctx that's cancelled after the given timeout, or just when ctx is, if the timeout isn't positive.
+//
+// The returned context is safe to hand to the sqlite driver: see guard.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	var cancel context.CancelFunc
+	if timeout <= 0 {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	guarded, release := guard(ctx)
+	return guarded, func() {
+		release()
+		cancel()
+	}
+}
+
+// guard works around a quirk of the sqlite driver: when the context of a statement is done, the driver interrupts
+// the whole connection from another goroutine. If the context is cancelled just as a statement finishes, that
+// interrupt can land on whatever statement runs next on the same connection, even though it belongs to another query.
+//
+// The returned context is only ever done if ctx is done before release is called, so cancelling ctx after
+// the statement is finished and released can't interrupt anything else.
+func guard(ctx context.Context) (context.Context, func()) {
+	g := &guardedContext{
+		Context: ctx,
+		done:    make(chan struct{}),
+	}
+
+	if ctx.Err() != nil {
+		close(g.done)
+		return g, func() {}
+	}
+
+	var mu sync.Mutex
+	released := false
+	stop := make(chan struct{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			if !released {
+				close(g.done)
+			}
+			mu.Unlock()
+		case <-stop:
+		}
+	}()
+
+	return g, func() {
+		mu.Lock()
+		released = true
+		mu.Unlock()
+		close(stop)
+	}
+}
+
+// guardedContext is a context whose done channel is controlled by guard.
+type guardedContext struct {
+	context.Context
+	done chan struct{}
+}
+
+func (g *guardedContext) Done() <-chan struct{} {
+	return g.done
+}
+
+func (g *guardedContext) Err() error {
+	select {
+	case <-g.done:
+		return g.Context.Err()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (ss *sqliteService) GetConversationsForAccount(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Conversation, error) {
+	conversations := []*gtsmodel.Conversation{}
+
+	q := ss.newQuery(ctx, &conversations).
+		Where("account_id = ?", accountID).
+		Order("last_status_id DESC")
+
+	if maxID != "" {
+		q = q.Where("last_status_id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("last_status_id > ?", sinceID)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(conversations) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return conversations, nil
+}
+
+func (ss *sqliteService) GetConversationsForStatus(ctx context.Context, statusID string) ([]*gtsmodel.Conversation, error) {
+	conversations := []*gtsmodel.Conversation{}
+
+	if err := ss.newQuery(ctx, &conversations).
+		Join("INNER JOIN conversation_statuses AS cs ON cs.conversation_id = conversation.id").
+		Where("cs.status_id = ?", statusID).
+		Select(); err != nil {
+		return nil, err
+	}
+
+	if len(conversations) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return conversations, nil
+}
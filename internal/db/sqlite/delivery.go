@@ -0,0 +1,124 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (ss *sqliteService) GetDueDeliveries(ctx context.Context, limit int) ([]*gtsmodel.Delivery, error) {
+	deliveries := []*gtsmodel.Delivery{}
+
+	q := ss.newQuery(ctx, &deliveries).
+		Where("next_attempt_at <= ?", time.Now()).
+		Where("failed_at IS NULL").
+		Order("next_attempt_at ASC").
+		Order("id ASC")
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return deliveries, nil
+}
+
+// deliveryStatsStatement selects a db.DeliveryStats for each domain that deliveries are addressed to,
+// with its columns in the same order as the fields of db.DeliveryStats.
+const deliveryStatsStatement = `SELECT "delivery"."domain",
+	SUM(CASE WHEN "delivery"."failed_at" IS NULL THEN 1 ELSE 0 END),
+	SUM(CASE WHEN "delivery"."failed_at" IS NULL THEN 0 ELSE 1 END),
+	MIN(CASE WHEN "delivery"."failed_at" IS NULL THEN "delivery"."next_attempt_at" END),
+	MAX(CASE WHEN "delivery"."attempts" > 0 THEN "delivery"."updated_at" END),
+	(SELECT "latest"."last_error" FROM "deliveries" AS "latest" WHERE "latest"."domain" = "delivery"."domain" AND "latest"."attempts" > 0 ORDER BY "latest"."updated_at" DESC LIMIT 1),
+	(SELECT "instance"."unreachable_since" FROM "instances" AS "instance" WHERE LOWER("instance"."domain") = LOWER("delivery"."domain") LIMIT 1)
+FROM "deliveries" AS "delivery"`
+
+func (ss *sqliteService) GetDeliveryStats(ctx context.Context, domain string) ([]*db.DeliveryStats, error) {
+	tbl, err := tableFor(&db.DeliveryStats{})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, done := startQuery(ctx, ss.timeout, "select", "deliveries")
+	defer done()
+
+	statement := deliveryStatsStatement
+	args := []interface{}{}
+	if domain != "" {
+		statement = statement + ` WHERE LOWER("delivery"."domain") = LOWER(?)`
+		args = append(args, domain)
+	}
+	statement = statement + ` GROUP BY "delivery"."domain" ORDER BY "delivery"."domain" ASC`
+
+	rows, err := ss.conn.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []*db.DeliveryStats{}
+	for rows.Next() {
+		values := make([]interface{}, len(tbl.columns))
+		pointers := make([]interface{}, len(values))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		s := &db.DeliveryStats{}
+		target := reflect.ValueOf(s).Elem()
+		for i, col := range tbl.columns {
+			if err := col.decode(target.FieldByIndex(col.index), values[i]); err != nil {
+				return nil, err
+			}
+		}
+		stats = append(stats, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(stats) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return stats, nil
+}
+
+func (ss *sqliteService) DeleteFailedDeliveries(ctx context.Context, olderThan time.Time) error {
+	_, err := ss.newQuery(ctx, &gtsmodel.Delivery{}).
+		Where("failed_at < ?", olderThan).
+		Delete()
+	return err
+}
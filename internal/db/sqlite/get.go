@@ -0,0 +1,77 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+)
+
+func (ss *sqliteService) GetByID(ctx context.Context, id string, i interface{}) error {
+	if err := ss.newQuery(ctx, i).Where("id = ?", id).Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrNoEntries{}
+		}
+		return err
+	}
+	return nil
+}
+
+func (ss *sqliteService) GetWhere(ctx context.Context, where []db.Where, i interface{}) error {
+	if len(where) == 0 {
+		return errors.New("no queries provided")
+	}
+
+	q := ss.newQuery(ctx, i)
+	for _, w := range where {
+		q = whereToQuery(q, w)
+	}
+
+	if err := q.Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrNoEntries{}
+		}
+		return err
+	}
+	return nil
+}
+
+func (ss *sqliteService) GetAll(ctx context.Context, i interface{}) error {
+	if err := ss.newQuery(ctx, i).Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrNoEntries{}
+		}
+		return err
+	}
+	return nil
+}
+
+// whereToQuery adds the given where parameter to the query.
+func whereToQuery(q *query, w db.Where) *query {
+	if w.Value == nil {
+		return q.Where(w.Key + " IS NULL")
+	}
+	if w.CaseInsensitive {
+		return q.Where("LOWER("+w.Key+") = LOWER(?)", w.Value)
+	}
+	return q.Where(w.Key+" = ?", w.Value)
+}
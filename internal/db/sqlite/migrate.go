@@ -0,0 +1,198 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+)
+
+func (ss *sqliteService) Migrate(ctx context.Context, migrations []db.Migration) ([]db.Migration, error) {
+	if err := ss.CreateTable(ctx, &db.MigrationVersion{}); err != nil {
+		return nil, fmt.Errorf("error creating migration versions table: %s", err)
+	}
+
+	done, err := ss.GetMigrationVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := db.PendingMigrations(migrations, done)
+	if err != nil {
+		return nil, err
+	}
+
+	ran := []db.Migration{}
+	for _, m := range pending {
+		ss.log.Infof("running migration %d: %s", m.Version, m.Name)
+		if err := ss.runMigration(ctx, m); err != nil {
+			return ran, fmt.Errorf("error running migration %d (%s): %s", m.Version, m.Name, err)
+		}
+		ran = append(ran, m)
+	}
+
+	return ran, nil
+}
+
+// runMigration runs the given migration and records its version in a single transaction.
+func (ss *sqliteService) runMigration(ctx context.Context, m db.Migration) error {
+	tx, err := ss.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Up(&sqliteSchema{ctx: ctx, tx: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := insert(ctx, tx, &db.MigrationVersion{
+		Version: m.Version,
+		Name:    m.Name,
+	}, "", ""); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (ss *sqliteService) GetMigrationVersions(ctx context.Context) ([]*db.MigrationVersion, error) {
+	versions := []*db.MigrationVersion{}
+
+	var exists bool
+	if err := ss.conn.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'migration_versions')").Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		// no migrations have been run yet
+		return versions, nil
+	}
+
+	if err := ss.newQuery(ctx, &versions).Order("migration_version.version ASC").Select(); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// sqliteSchema implements db.Schema for a sqlite transaction.
+type sqliteSchema struct {
+	ctx context.Context
+	tx  *sql.Tx
+}
+
+func (s *sqliteSchema) Type() string {
+	return db.DBTypeSQLite
+}
+
+func (s *sqliteSchema) CreateTable(i interface{}) error {
+	tbl, err := tableFor(i)
+	if err != nil {
+		return err
+	}
+	_, err = s.tx.ExecContext(s.ctx, tbl.createStatement())
+	return err
+}
+
+func (s *sqliteSchema) DropTable(i interface{}) error {
+	tbl, err := tableFor(i)
+	if err != nil {
+		return err
+	}
+	_, err = s.tx.ExecContext(s.ctx, fmt.Sprintf("DROP TABLE IF EXISTS %q", tbl.name))
+	return err
+}
+
+func (s *sqliteSchema) AddColumn(i interface{}, column string) error {
+	tbl, err := tableFor(i)
+	if err != nil {
+		return err
+	}
+
+	col := tbl.column(column)
+	if col == nil {
+		return fmt.Errorf("table %s has no column %s", tbl.name, column)
+	}
+
+	// sqlite has no ADD COLUMN IF NOT EXISTS, so check the existing columns first
+	var exists bool
+	if err := s.tx.QueryRow("SELECT EXISTS (SELECT 1 FROM pragma_table_info(?) WHERE name = ?)", tbl.name, col.name).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	// sqlite won't add a column with a non-constant default like CURRENT_TIMESTAMP to a table that already has rows,
+	// so add it without constraints and backfill the default instead; we always set defaults on insert anyway
+	if d := sqlDefault(col.defValue); !constantDefault(d) {
+		if _, err := s.tx.ExecContext(s.ctx, fmt.Sprintf("ALTER TABLE %q ADD COLUMN %q %s", tbl.name, col.name, col.sqlType)); err != nil {
+			return err
+		}
+		if _, err := s.tx.ExecContext(s.ctx, fmt.Sprintf("UPDATE %q SET %q = %s", tbl.name, col.name, d)); err != nil {
+			return err
+		}
+	} else if _, err := s.tx.ExecContext(s.ctx, fmt.Sprintf("ALTER TABLE %q ADD COLUMN %s", tbl.name, col.definition())); err != nil {
+		return err
+	}
+
+	// sqlite can't add a column with a unique constraint, so use a unique index instead
+	if col.unique {
+		_, err = s.tx.ExecContext(s.ctx, fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %q ON %q (%q)", tbl.name+"_"+col.name+"_key", tbl.name, col.name))
+	}
+	return err
+}
+
+func (s *sqliteSchema) HasColumn(i interface{}, column string) (bool, error) {
+	tbl, err := tableFor(i)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = s.tx.QueryRowContext(s.ctx, "SELECT EXISTS (SELECT 1 FROM pragma_table_info(?) WHERE name = ?)", tbl.name, column).Scan(&exists)
+	return exists, err
+}
+
+func (s *sqliteSchema) CreateIndex(i interface{}, name string, columns ...string) error {
+	tbl, err := tableFor(i)
+	if err != nil {
+		return err
+	}
+	_, err = s.tx.ExecContext(s.ctx, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %q ON %q (%s)", name, tbl.name, quoteAll(columns)))
+	return err
+}
+
+func (s *sqliteSchema) Exec(query string, args ...interface{}) error {
+	_, err := s.tx.ExecContext(s.ctx, query, encodeArgs(args)...)
+	return err
+}
+
+// constantDefault returns whether the given sqlite default is constant, and so can be used when adding a column to an existing table.
+func constantDefault(d string) bool {
+	switch strings.ToUpper(d) {
+	case "CURRENT_TIMESTAMP", "CURRENT_DATE", "CURRENT_TIME":
+		return false
+	}
+	return !strings.HasPrefix(d, "(")
+}
@@ -0,0 +1,601 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+	The gtsmodel structs are annotated for the go-pg ORM. Rather than duplicating all of those annotations,
+	the sqlite service reads the same `pg` struct tags and maps them onto sqlite tables, following the
+	conventions that go-pg uses: snake_case column names, pluralized table names, zero values stored as NULL,
+	and nested structs, maps and arrays stored as JSON.
+*/
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	ipType     = reflect.TypeOf(net.IP{})
+	bytesType  = reflect.TypeOf([]byte{})
+	tableCache = sync.Map{}
+)
+
+// table describes how a go struct is stored in sqlite.
+type table struct {
+	name    string
+	alias   string
+	columns []*column
+	// names of the primary key columns
+	pks []string
+	// unique constraints spanning more than one column, keyed by their group name
+	uniqueGroups map[string][]string
+}
+
+// column describes how a single field of a go struct is stored in sqlite.
+type column struct {
+	name     string
+	index    []int
+	sqlType  string
+	notNull  bool
+	unique   bool
+	useZero  bool
+	json     bool
+	defValue string
+}
+
+// precomputer is implemented by *rsa.PrivateKey, which should be precomputed after being decoded from json.
+type precomputer interface {
+	Precompute()
+}
+
+// tableFor returns the table for the given model, which should be a pointer to a struct,
+// or a pointer to a slice of structs or struct pointers.
+func tableFor(model interface{}) (*table, error) {
+	t := reflect.TypeOf(model)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("model %T was not a pointer", model)
+	}
+	t = t.Elem()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("model %T was not a struct or slice of structs", model)
+	}
+
+	if cached, ok := tableCache.Load(t); ok {
+		return cached.(*table), nil
+	}
+
+	tbl := &table{
+		name:         pluralize(underscore(t.Name())),
+		alias:        underscore(t.Name()),
+		uniqueGroups: map[string][]string{},
+	}
+	tbl.addColumns(t, nil)
+
+	tableCache.Store(t, tbl)
+	return tbl, nil
+}
+
+func (tbl *table) addColumns(t reflect.Type, index []int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		tag := f.Tag.Get("pg")
+		name, opts := parseTag(tag)
+
+		if f.Name == "tableName" {
+			if name != "" {
+				tbl.name = name
+			}
+			if alias, ok := opts["alias"]; ok {
+				tbl.alias = alias
+			}
+			continue
+		}
+
+		if f.PkgPath != "" || tag == "-" {
+			// unexported or explicitly skipped
+			continue
+		}
+
+		if _, ok := opts["rel"]; ok {
+			// relations are not columns, they're selected separately
+			continue
+		}
+
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Type != timeType {
+			tbl.addColumns(f.Type, fieldIndex)
+			continue
+		}
+
+		col := &column{
+			name:  name,
+			index: fieldIndex,
+		}
+		if col.name == "" {
+			col.name = underscore(f.Name)
+		}
+
+		_, col.notNull = opts["notnull"]
+		_, col.useZero = opts["use_zero"]
+		col.defValue = opts["default"]
+		col.json = isJSON(f.Type)
+		col.sqlType = sqlTypeFor(f.Type, opts["type"])
+
+		if _, ok := opts["pk"]; ok {
+			tbl.pks = append(tbl.pks, col.name)
+		}
+
+		if group, ok := opts["unique"]; ok {
+			if group == "" {
+				col.unique = true
+			} else {
+				tbl.uniqueGroups[group] = append(tbl.uniqueGroups[group], col.name)
+			}
+		}
+
+		tbl.columns = append(tbl.columns, col)
+	}
+}
+
+// createStatement returns a CREATE TABLE statement for this table.
+func (tbl *table) createStatement() string {
+	defs := []string{}
+	for _, col := range tbl.columns {
+		def := col.definition()
+		if col.unique {
+			def += " UNIQUE"
+		}
+		defs = append(defs, def)
+	}
+
+	if len(tbl.pks) != 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", quoteAll(tbl.pks)))
+	}
+
+	for _, cols := range tbl.uniqueGroups {
+		defs = append(defs, fmt.Sprintf("UNIQUE (%s)", quoteAll(cols)))
+	}
+
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %q (%s)", tbl.name, strings.Join(defs, ", "))
+}
+
+// column returns the column with the given name, or nil if this table has no such column.
+func (tbl *table) column(name string) *column {
+	for _, col := range tbl.columns {
+		if col.name == name {
+			return col
+		}
+	}
+	return nil
+}
+
+// selectColumns returns the columns of this table qualified with the table alias, suitable for a SELECT.
+func (tbl *table) selectColumns() string {
+	cols := make([]string, 0, len(tbl.columns))
+	for _, col := range tbl.columns {
+		cols = append(cols, fmt.Sprintf("%q.%q", tbl.alias, col.name))
+	}
+	return strings.Join(cols, ", ")
+}
+
+// values returns the column names and encoded values of the given struct value, for use in an INSERT.
+//
+// Zero values of columns with a default will be set to that default on the struct as well, just like
+// go-pg returns defaulted columns into the model after an insert.
+func (tbl *table) values(v reflect.Value) ([]string, []interface{}, error) {
+	names := make([]string, 0, len(tbl.columns))
+	values := make([]interface{}, 0, len(tbl.columns))
+	for _, col := range tbl.columns {
+		f := v.FieldByIndex(col.index)
+		if f.IsZero() && col.defValue != "" && !col.useZero {
+			if err := setDefault(f, col.defValue); err != nil {
+				return nil, nil, fmt.Errorf("error setting default for column %s: %s", col.name, err)
+			}
+		}
+
+		value, err := col.encode(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error encoding column %s: %s", col.name, err)
+		}
+		names = append(names, col.name)
+		values = append(values, value)
+	}
+	return names, values, nil
+}
+
+// definition returns the definition of this column for use in a CREATE TABLE or ALTER TABLE statement, without any unique constraint.
+func (col *column) definition() string {
+	def := fmt.Sprintf("%q %s", col.name, col.sqlType)
+	if col.notNull {
+		def += " NOT NULL"
+	}
+	if d := sqlDefault(col.defValue); d != "" {
+		def += " DEFAULT " + d
+	}
+	return def
+}
+
+// encode returns the value that should be stored in sqlite for the given field.
+func (col *column) encode(f reflect.Value) (interface{}, error) {
+	if f.IsZero() && !col.useZero {
+		switch f.Kind() {
+		case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+			// store the zero value rather than null so that these can be compared to without special cases
+		default:
+			return nil, nil
+		}
+	}
+
+	switch {
+	case f.Type() == timeType:
+		return f.Interface().(time.Time).UTC(), nil
+	case f.Type() == ipType:
+		return f.Interface().(net.IP).String(), nil
+	case f.Type() == bytesType:
+		return f.Bytes(), nil
+	case col.json:
+		b, err := json.Marshal(f.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		return f.String(), nil
+	case reflect.Bool:
+		return f.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return f.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(f.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return f.Float(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", f.Type())
+}
+
+// decode sets the given field from the given value retrieved from sqlite.
+func (col *column) decode(f reflect.Value, value interface{}) error {
+	if value == nil {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+
+	switch {
+	case f.Type() == timeType:
+		t, err := toTime(value)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	case f.Type() == ipType:
+		f.Set(reflect.ValueOf(net.ParseIP(toString(value))))
+		return nil
+	case f.Type() == bytesType:
+		f.SetBytes([]byte(toString(value)))
+		return nil
+	case col.json:
+		ptr := reflect.New(f.Type())
+		if err := json.Unmarshal([]byte(toString(value)), ptr.Interface()); err != nil {
+			return err
+		}
+		if p, ok := ptr.Elem().Interface().(precomputer); ok && !ptr.Elem().IsNil() {
+			p.Precompute()
+		}
+		f.Set(ptr.Elem())
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(toString(value))
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+			f.SetBool(v)
+		case int64:
+			f.SetBool(v != 0)
+		default:
+			b, err := strconv.ParseBool(toString(value))
+			if err != nil {
+				return err
+			}
+			f.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v := value.(type) {
+		case int64:
+			f.SetInt(v)
+		case float64:
+			f.SetInt(int64(v))
+		default:
+			return fmt.Errorf("cannot decode %T into %s", value, f.Type())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch v := value.(type) {
+		case int64:
+			f.SetUint(uint64(v))
+		case float64:
+			f.SetUint(uint64(v))
+		default:
+			return fmt.Errorf("cannot decode %T into %s", value, f.Type())
+		}
+	case reflect.Float32, reflect.Float64:
+		switch v := value.(type) {
+		case int64:
+			f.SetFloat(float64(v))
+		case float64:
+			f.SetFloat(v)
+		default:
+			return fmt.Errorf("cannot decode %T into %s", value, f.Type())
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+	return nil
+}
+
+/*
+	HELPERS
+*/
+
+// parseTag parses a go-pg struct tag into its name and options.
+func parseTag(tag string) (string, map[string]string) {
+	var name string
+	opts := map[string]string{}
+	for i, part := range splitTag(tag) {
+		if i == 0 && !strings.Contains(part, ":") {
+			name = part
+			continue
+		}
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) == 2 {
+			opts[kv[0]] = kv[1]
+		} else if kv[0] != "" {
+			opts[kv[0]] = ""
+		}
+	}
+	return name, opts
+}
+
+// splitTag splits a tag on commas, ignoring commas inside parentheses, eg., in type:NUMERIC(10,2).
+func splitTag(tag string) []string {
+	parts := []string{}
+	depth := 0
+	start := 0
+	for i, c := range tag {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, tag[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, tag[start:])
+}
+
+// underscore converts a go name like InReplyToURI to a snake case name like in_reply_to_uri, the same way go-pg does.
+func underscore(s string) string {
+	r := make([]byte, 0, len(s)+5)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isUpper(c) {
+			if i > 0 && i+1 < len(s) && (isLower(s[i-1]) || isLower(s[i+1])) {
+				r = append(r, '_', c+32)
+			} else {
+				r = append(r, c+32)
+			}
+		} else {
+			r = append(r, c)
+		}
+	}
+	return string(r)
+}
+
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+func isLower(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+// pluralize returns the plural form of the given snake case noun, which is good enough for our model names.
+func pluralize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsAny(s[len(s)-2:len(s)-1], "aeiou"):
+		return s[:len(s)-1] + "ies"
+	}
+	return s + "s"
+}
+
+// isJSON returns true if fields of the given type should be stored as json.
+func isJSON(t reflect.Type) bool {
+	if t == timeType || t == ipType || t == bytesType {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Ptr, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// sqlTypeFor returns the sqlite column type for the given go type.
+func sqlTypeFor(t reflect.Type, explicit string) string {
+	if explicit != "" {
+		return strings.ToUpper(explicit)
+	}
+
+	switch {
+	case t == timeType:
+		return "TIMESTAMP"
+	case t == bytesType:
+		return "BLOB"
+	case t == ipType:
+		return "TEXT"
+	case isJSON(t):
+		return "TEXT"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "BOOLEAN"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "REAL"
+	}
+	return "TEXT"
+}
+
+// sqlDefault converts a go-pg default into an sqlite default.
+func sqlDefault(def string) string {
+	switch strings.ToLower(def) {
+	case "":
+		return ""
+	case "now()":
+		return "CURRENT_TIMESTAMP"
+	case "true":
+		return "1"
+	case "false":
+		return "0"
+	}
+	return def
+}
+
+// setDefault sets the given field to the go value of the given go-pg default.
+func setDefault(f reflect.Value, def string) error {
+	switch strings.ToLower(def) {
+	case "null":
+		return nil
+	case "now()":
+		if f.Type() != timeType {
+			return fmt.Errorf("now() default on non-time type %s", f.Type())
+		}
+		f.Set(reflect.ValueOf(time.Now()))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(strings.Trim(def, "'"))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(def)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(def, 10, 64)
+		if err != nil {
+			return err
+		}
+		f.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(def, 10, 64)
+		if err != nil {
+			return err
+		}
+		f.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		fl, err := strconv.ParseFloat(def, 64)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(fl)
+	default:
+		return fmt.Errorf("unsupported default %s for type %s", def, f.Type())
+	}
+	return nil
+}
+
+// timeFormats are the formats that timestamps might be stored in, depending on whether they were written by us or by sqlite.
+var timeFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	time.RFC3339Nano,
+	// how the driver stores a time.Time, which is what comes back when a timestamp column is selected through an expression
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+}
+
+func toTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case int64:
+		return time.Unix(v, 0).UTC(), nil
+	}
+
+	s := toString(value)
+	for _, format := range timeFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("could not parse %s as a timestamp", s)
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return fmt.Sprint(value)
+}
+
+func quoteAll(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, n := range names {
+		quoted = append(quoted, strconv.Quote(n))
+	}
+	return strings.Join(quoted, ", ")
+}
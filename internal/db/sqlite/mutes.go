@@ -0,0 +1,114 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (ss *sqliteService) GetMutesForAccount(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Account, string, string, error) {
+	mutes := []*gtsmodel.Mute{}
+
+	fq := ss.newQuery(ctx, &mutes).
+		Where("mute.account_id = ?", accountID).
+		Where("mute.expires_at IS NULL OR mute.expires_at > ?", time.Now()).
+		Order("mute.id DESC")
+
+	if maxID != "" {
+		fq = fq.Where("mute.id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		fq = fq.Where("mute.id > ?", sinceID)
+	}
+
+	if limit > 0 {
+		fq = fq.Limit(limit)
+	}
+
+	if err := fq.Select(); err != nil {
+		return nil, "", "", err
+	}
+
+	if len(mutes) == 0 {
+		return nil, "", "", db.ErrNoEntries{}
+	}
+
+	accounts := []*gtsmodel.Account{}
+	for _, m := range mutes {
+		targetAccount := &gtsmodel.Account{}
+		if err := ss.newQuery(ctx, targetAccount).Where("id = ?", m.TargetAccountID).Select(); err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return nil, "", "", err
+		}
+		accounts = append(accounts, targetAccount)
+	}
+
+	nextMaxID := mutes[len(mutes)-1].ID
+	prevMinID := mutes[0].ID
+	return accounts, nextMaxID, prevMinID, nil
+}
+
+func (ss *sqliteService) GetMute(ctx context.Context, accountID string, targetAccountID string) (*gtsmodel.Mute, error) {
+	mute := &gtsmodel.Mute{}
+
+	err := ss.newQuery(ctx, mute).
+		Where("account_id = ?", accountID).
+		Where("target_account_id = ?", targetAccountID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Select()
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, db.ErrNoEntries{}
+		}
+		return nil, err
+	}
+
+	return mute, nil
+}
+
+func (ss *sqliteService) GetExpiredMutes(ctx context.Context, limit int) ([]*gtsmodel.Mute, error) {
+	mutes := []*gtsmodel.Mute{}
+
+	q := ss.newQuery(ctx, &mutes).
+		Where("expires_at <= ?", time.Now()).
+		Order("expires_at ASC").
+		Order("id ASC")
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(mutes) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return mutes, nil
+}
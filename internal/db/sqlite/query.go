@@ -0,0 +1,383 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/metrics"
+	"github.com/superseriousbusiness/gotosocial/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// query is a small query builder in the spirit of the go-pg query builder, so that the sqlite service
+// can be read side by side with the postgres service.
+//
+// Conditions given to Where and WhereOr are joined in the order they were added, just like in go-pg.
+type query struct {
+	ctx     context.Context
+	timeout time.Duration
+	conn    *sql.DB
+	model   interface{}
+	table   *table
+	err     error
+	joins   []string
+	where   []string
+	args    []interface{}
+	sets    []string
+	setArg  []interface{}
+	order   []string
+	limit   int
+}
+
+// newQuery returns a new query on the table of the given model, which will be run with the given context.
+func (ss *sqliteService) newQuery(ctx context.Context, model interface{}) *query {
+	tbl, err := tableFor(model)
+	return &query{
+		ctx:     ctx,
+		timeout: ss.timeout,
+		conn:    ss.conn,
+		model:   model,
+		table:   tbl,
+		err:     err,
+	}
+}
+
+// Join adds a join to the query, eg., "JOIN accounts AS a ON a.id = status.account_id".
+func (q *query) Join(join string) *query {
+	q.joins = append(q.joins, join)
+	return q
+}
+
+// Where adds a condition to the query, joined to any previous conditions with AND.
+func (q *query) Where(condition string, args ...interface{}) *query {
+	return q.addWhere("AND", condition, args)
+}
+
+// WhereOr adds a condition to the query, joined to any previous conditions with OR.
+func (q *query) WhereOr(condition string, args ...interface{}) *query {
+	return q.addWhere("OR", condition, args)
+}
+
+// WhereIn adds a condition that the given column is one of the given values.
+func (q *query) WhereIn(column string, values []string) *query {
+	if len(values) == 0 {
+		return q.Where("0")
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	args := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return q.Where(fmt.Sprintf("%s IN (%s)", column, placeholders), args...)
+}
+
+func (q *query) addWhere(sep string, condition string, args []interface{}) *query {
+	condition = "(" + condition + ")"
+	if len(q.where) != 0 {
+		condition = sep + " " + condition
+	}
+	q.where = append(q.where, condition)
+	q.args = append(q.args, encodeArgs(args)...)
+	return q
+}
+
+// Set adds a column assignment to be used in an Update, eg., Set("uri = ?", uri).
+func (q *query) Set(set string, args ...interface{}) *query {
+	q.sets = append(q.sets, set)
+	q.setArg = append(q.setArg, encodeArgs(args)...)
+	return q
+}
+
+// Order adds an ordering to the query, eg., "status.id DESC".
+func (q *query) Order(order string) *query {
+	q.order = append(q.order, order)
+	return q
+}
+
+// Limit limits the amount of rows returned by the query.
+func (q *query) Limit(limit int) *query {
+	q.limit = limit
+	return q
+}
+
+func (q *query) from() string {
+	s := fmt.Sprintf(" FROM %q AS %q", q.table.name, q.table.alias)
+	for _, j := range q.joins {
+		s += " " + j
+	}
+	return s
+}
+
+func (q *query) whereClause() string {
+	if len(q.where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.where, " ")
+}
+
+func (q *query) tail() string {
+	var s string
+	if len(q.order) != 0 {
+		s += " ORDER BY " + strings.Join(q.order, ", ")
+	}
+	if q.limit > 0 {
+		s += fmt.Sprintf(" LIMIT %d", q.limit)
+	}
+	return s
+}
+
+// Select selects the query into the model.
+//
+// If the model is a pointer to a struct, sql.ErrNoRows will be returned if nothing was found.
+// If the model is a pointer to a slice, the slice will just be empty if nothing was found.
+func (q *query) Select() error {
+	if q.err != nil {
+		return q.err
+	}
+
+	ctx, done := startQuery(q.ctx, q.timeout, "select", q.table.name)
+	defer done()
+
+	modelValue := reflect.ValueOf(q.model).Elem()
+	single := modelValue.Kind() == reflect.Struct
+	if single {
+		q.limit = 1
+	}
+
+	statement := "SELECT " + q.table.selectColumns() + q.from() + q.whereClause() + q.tail()
+	rows, err := q.conn.QueryContext(ctx, statement, q.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	found := 0
+	for rows.Next() {
+		values := make([]interface{}, len(q.table.columns))
+		pointers := make([]interface{}, len(values))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+
+		var target reflect.Value
+		if single {
+			target = modelValue
+		} else {
+			target = reflect.New(sliceElemStruct(modelValue.Type())).Elem()
+		}
+
+		for i, col := range q.table.columns {
+			if err := col.decode(target.FieldByIndex(col.index), values[i]); err != nil {
+				return fmt.Errorf("error decoding column %s: %s", col.name, err)
+			}
+		}
+
+		if !single {
+			if modelValue.Type().Elem().Kind() == reflect.Ptr {
+				target = target.Addr()
+			}
+			modelValue.Set(reflect.Append(modelValue, target))
+		}
+		found++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if single && found == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Count returns the number of rows matching the query.
+func (q *query) Count() (int, error) {
+	if q.err != nil {
+		return 0, q.err
+	}
+
+	ctx, done := startQuery(q.ctx, q.timeout, "count", q.table.name)
+	defer done()
+
+	var count int
+	statement := "SELECT COUNT(*)" + q.from() + q.whereClause()
+	if err := q.conn.QueryRowContext(ctx, statement, q.args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Exists returns true if at least one row matches the query.
+func (q *query) Exists() (bool, error) {
+	if q.err != nil {
+		return false, q.err
+	}
+
+	ctx, done := startQuery(q.ctx, q.timeout, "exists", q.table.name)
+	defer done()
+
+	var exists bool
+	statement := "SELECT EXISTS (SELECT 1" + q.from() + q.whereClause() + ")"
+	if err := q.conn.QueryRowContext(ctx, statement, q.args...).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// Update updates the rows matching the query with the assignments given to Set.
+func (q *query) Update() (int64, error) {
+	if q.err != nil {
+		return 0, q.err
+	}
+
+	ctx, done := startQuery(q.ctx, q.timeout, "update", q.table.name)
+	defer done()
+	if len(q.sets) == 0 {
+		return 0, errors.New("no columns set for update")
+	}
+
+	statement := fmt.Sprintf("UPDATE %q AS %q SET %s", q.table.name, q.table.alias, strings.Join(q.sets, ", ")) + q.whereClause()
+	res, err := q.conn.ExecContext(ctx, statement, append(append([]interface{}{}, q.setArg...), q.args...)...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Delete deletes the rows matching the query.
+func (q *query) Delete() (int64, error) {
+	if q.err != nil {
+		return 0, q.err
+	}
+
+	ctx, done := startQuery(q.ctx, q.timeout, "delete", q.table.name)
+	defer done()
+
+	statement := fmt.Sprintf("DELETE FROM %q AS %q", q.table.name, q.table.alias) + q.whereClause()
+	res, err := q.conn.ExecContext(ctx, statement, q.args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// insert inserts the given model, which must be a pointer to a struct.
+//
+// If conflictColumns is set, then existing rows conflicting on those columns will be updated instead,
+// with every column taking the new value unless updateSet is given, in which case only those assignments are made.
+func (ss *sqliteService) insert(ctx context.Context, model interface{}, conflictColumns string, updateSet string, updateArgs ...interface{}) error {
+	var tableName string
+	if tbl, err := tableFor(model); err == nil {
+		tableName = tbl.name
+	}
+
+	ctx, done := startQuery(ctx, ss.timeout, "insert", tableName)
+	defer done()
+	return insert(ctx, ss.conn, model, conflictColumns, updateSet, updateArgs...)
+}
+
+// startQuery prepares ctx for running a query with the given operation on the given table, applying the timeout
+// and starting a span for the query. The returned function must be called once the query is done, to release
+// the context, end the span, and record how long the query took.
+func startQuery(ctx context.Context, timeout time.Duration, operation string, tableName string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracing.StartSpan(ctx, "db."+operation,
+		semconv.DBSystemSqlite,
+		semconv.DBOperationKey.String(operation),
+		semconv.DBSQLTableKey.String(tableName),
+	)
+	ctx, cancel := withTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		span.End()
+		metrics.ObserveDBQuery(db.DBTypeSQLite, operation, start)
+	}
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insert(ctx context.Context, conn execer, model interface{}, conflictColumns string, updateSet string, updateArgs ...interface{}) error {
+	tbl, err := tableFor(model)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(model).Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot insert %T, expected a pointer to a struct", model)
+	}
+
+	names, values, err := tbl.values(v)
+	if err != nil {
+		return err
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")
+	statement := fmt.Sprintf("INSERT INTO %q (%s) VALUES (%s)", tbl.name, quoteAll(names), placeholders)
+
+	if conflictColumns != "" {
+		if updateSet == "" {
+			sets := make([]string, 0, len(names))
+			for _, n := range names {
+				sets = append(sets, fmt.Sprintf("%q = excluded.%q", n, n))
+			}
+			updateSet = strings.Join(sets, ", ")
+		}
+		statement += fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", conflictColumns, updateSet)
+		values = append(values, encodeArgs(updateArgs)...)
+	}
+
+	_, err = conn.ExecContext(ctx, statement, values...)
+	return err
+}
+
+// sliceElemStruct returns the struct type of a slice of structs or struct pointers.
+func sliceElemStruct(t reflect.Type) reflect.Type {
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem
+}
+
+// encodeArgs makes sure that query arguments are stored in the same way as the column values they're compared to.
+func encodeArgs(args []interface{}) []interface{} {
+	encoded := make([]interface{}, 0, len(args))
+	for _, a := range args {
+		if t, ok := a.(time.Time); ok {
+			a = t.UTC()
+		}
+		encoded = append(encoded, a)
+	}
+	return encoded
+}
@@ -0,0 +1,69 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (ss *sqliteService) GetReports(ctx context.Context, resolved bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Report, error) {
+	reports := []*gtsmodel.Report{}
+
+	q := ss.newQuery(ctx, &reports).
+		Order("id DESC")
+
+	if resolved {
+		q = q.Where("action_taken_at IS NOT NULL")
+	} else {
+		q = q.Where("action_taken_at IS NULL")
+	}
+
+	if accountID != "" {
+		q = q.Where("account_id = ?", accountID)
+	}
+
+	if targetAccountID != "" {
+		q = q.Where("target_account_id = ?", targetAccountID)
+	}
+
+	if maxID != "" {
+		q = q.Where("id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("id > ?", sinceID)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(reports) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,903 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"golang.org/x/crypto/bcrypt"
+
+	// register the sqlite driver with database/sql
+	_ "modernc.org/sqlite"
+)
+
+// inMemoryAddress can be used as the db address to run sqlite entirely in memory, which is handy for testing.
+const inMemoryAddress = ":memory:"
+
+// sqliteService satisfies the DB interface
+type sqliteService struct {
+	config  *config.Config
+	conn    *sql.DB
+	log     *logrus.Logger
+	timeout time.Duration
+}
+
+// NewSQLiteService returns a sqliteService derived from the provided config, which implements the go-fed DB interface.
+// Under the hood, it uses https://gitlab.com/cznic/sqlite, which is a cgo-free port of sqlite, so no external database is needed.
+//
+// The db address in the config is used as the path of the sqlite database file, or :memory: for an in-memory database.
+func NewSQLiteService(ctx context.Context, c *config.Config, log *logrus.Logger) (db.DB, error) {
+	if strings.ToUpper(c.DBConfig.Type) != db.DBTypeSQLite {
+		return nil, fmt.Errorf("could not create sqlite service: expected db type of %s but got %s", db.DBTypeSQLite, c.DBConfig.Type)
+	}
+
+	address := c.DBConfig.Address
+	if address == "" {
+		return nil, errors.New("could not create sqlite service: no address set")
+	}
+
+	conn, err := sql.Open("sqlite", address)
+	if err != nil {
+		return nil, fmt.Errorf("could not open sqlite db: %s", err)
+	}
+
+	if address == inMemoryAddress {
+		// every new connection to :memory: would get its own, empty database, so make sure we only ever use one
+		conn.SetMaxOpenConns(1)
+		conn.SetConnMaxLifetime(0)
+		conn.SetConnMaxIdleTime(0)
+	}
+
+	// actually *begin* the connection so that we can tell if the db is there and usable
+	if err := conn.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("db connection error: %s", err)
+	}
+
+	pragmas := []string{
+		// wait for locks instead of failing immediately when the db is busy
+		"PRAGMA busy_timeout = 5000",
+		// allow readers to proceed while writing
+		"PRAGMA journal_mode = WAL",
+	}
+	for _, p := range pragmas {
+		if _, err := conn.ExecContext(ctx, p); err != nil {
+			return nil, fmt.Errorf("db connection error setting %s: %s", p, err)
+		}
+	}
+
+	// print out discovered sqlite version
+	var version string
+	if err := conn.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err != nil {
+		return nil, fmt.Errorf("db connection error: %s", err)
+	}
+	log.Infof("connected to sqlite version: %s", version)
+
+	ss := &sqliteService{
+		config:  c,
+		conn:    conn,
+		log:     log,
+		timeout: time.Duration(c.DBConfig.QueryTimeout) * time.Second,
+	}
+
+	// we can confidently return this useable sqlite service now
+	return ss, nil
+}
+
+/*
+	BASIC DB FUNCTIONALITY
+*/
+
+func (ss *sqliteService) CreateTable(ctx context.Context, i interface{}) error {
+	tbl, err := tableFor(i)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := withTimeout(ctx, ss.timeout)
+	defer cancel()
+	_, err = ss.conn.ExecContext(ctx, tbl.createStatement())
+	return err
+}
+
+func (ss *sqliteService) DropTable(ctx context.Context, i interface{}) error {
+	tbl, err := tableFor(i)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := withTimeout(ctx, ss.timeout)
+	defer cancel()
+	_, err = ss.conn.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %q", tbl.name))
+	return err
+}
+
+func (ss *sqliteService) Stop(ctx context.Context) error {
+	ss.log.Info("closing db connection")
+	return ss.conn.Close()
+}
+
+func (ss *sqliteService) IsHealthy(ctx context.Context) error {
+	return ss.conn.PingContext(ctx)
+}
+
+/*
+	HANDY SHORTCUTS
+*/
+
+func (ss *sqliteService) AcceptFollowRequest(ctx context.Context, originAccountID string, targetAccountID string) (*gtsmodel.Follow, error) {
+	// make sure the original follow request exists
+	fr := &gtsmodel.FollowRequest{}
+	if err := ss.newQuery(ctx, fr).Where("account_id = ?", originAccountID).Where("target_account_id = ?", targetAccountID).Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, db.ErrNoEntries{}
+		}
+		return nil, err
+	}
+
+	// create a new follow to 'replace' the request with
+	follow := &gtsmodel.Follow{
+		ID:              fr.ID,
+		AccountID:       originAccountID,
+		TargetAccountID: targetAccountID,
+		URI:             fr.URI,
+	}
+
+	// if the follow already exists, just update the URI -- we don't need to do anything else
+	if err := ss.insert(ctx, follow, "account_id, target_account_id", "uri = ?", follow.URI); err != nil {
+		return nil, err
+	}
+
+	// now remove the follow request
+	if _, err := ss.newQuery(ctx, &gtsmodel.FollowRequest{}).Where("account_id = ?", originAccountID).Where("target_account_id = ?", targetAccountID).Delete(); err != nil {
+		return nil, err
+	}
+
+	return follow, nil
+}
+
+func (ss *sqliteService) CreateInstanceAccount(ctx context.Context) error {
+	username := ss.config.Host
+
+	a := &gtsmodel.Account{}
+	err := ss.newQuery(ctx, a).Where("username = ?", username).Select()
+	if err == nil {
+		ss.log.Infof("instance account %s already exists with id %s", username, a.ID)
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		ss.log.Errorf("error creating new rsa key: %s", err)
+		return err
+	}
+
+	aID, err := id.NewRandomULID()
+	if err != nil {
+		return err
+	}
+
+	newAccountURIs := util.GenerateURIsForAccount(username, ss.config.Protocol, ss.config.Host)
+	a = &gtsmodel.Account{
+		ID:                    aID,
+		Username:              ss.config.Host,
+		DisplayName:           username,
+		URL:                   newAccountURIs.UserURL,
+		PrivateKey:            key,
+		PublicKey:             &key.PublicKey,
+		PublicKeyURI:          newAccountURIs.PublicKeyURI,
+		ActorType:             gtsmodel.ActivityStreamsPerson,
+		URI:                   newAccountURIs.UserURI,
+		InboxURI:              newAccountURIs.InboxURI,
+		OutboxURI:             newAccountURIs.OutboxURI,
+		FollowersURI:          newAccountURIs.FollowersURI,
+		FollowingURI:          newAccountURIs.FollowingURI,
+		FeaturedCollectionURI: newAccountURIs.CollectionURI,
+	}
+	if err := ss.insert(ctx, a, "", ""); err != nil {
+		return err
+	}
+
+	ss.log.Infof("created instance account %s with id %s", username, a.ID)
+	return nil
+}
+
+func (ss *sqliteService) CreateInstanceInstance(ctx context.Context) error {
+	i := &gtsmodel.Instance{}
+	err := ss.newQuery(ctx, i).Where("domain = ?", ss.config.Host).Select()
+	if err == nil {
+		ss.log.Infof("instance instance %s already exists with id %s", ss.config.Host, i.ID)
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	iID, err := id.NewRandomULID()
+	if err != nil {
+		return err
+	}
+
+	i = &gtsmodel.Instance{
+		ID:     iID,
+		Domain: ss.config.Host,
+		Title:  ss.config.Host,
+		URI:    fmt.Sprintf("%s://%s", ss.config.Protocol, ss.config.Host),
+	}
+	if err := ss.insert(ctx, i, "", ""); err != nil {
+		return err
+	}
+
+	ss.log.Infof("created instance instance %s with id %s", ss.config.Host, i.ID)
+	return nil
+}
+
+func (ss *sqliteService) GetAccountByUserID(ctx context.Context, userID string, account *gtsmodel.Account) error {
+	user := &gtsmodel.User{}
+	if err := ss.newQuery(ctx, user).Where("id = ?", userID).Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrNoEntries{}
+		}
+		return err
+	}
+	if err := ss.newQuery(ctx, account).Where("id = ?", user.AccountID).Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrNoEntries{}
+		}
+		return err
+	}
+	return nil
+}
+
+func (ss *sqliteService) GetLocalAccountByUsername(ctx context.Context, username string, account *gtsmodel.Account) error {
+	if err := ss.newQuery(ctx, account).Where("username = ?", username).Where("domain IS NULL").Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrNoEntries{}
+		}
+		return err
+	}
+	return nil
+}
+
+func (ss *sqliteService) GetFollowRequestsForAccountID(ctx context.Context, accountID string, followRequests *[]gtsmodel.FollowRequest) error {
+	return ss.newQuery(ctx, followRequests).Where("target_account_id = ?", accountID).Select()
+}
+
+func (ss *sqliteService) GetFollowingByAccountID(ctx context.Context, accountID string, following *[]gtsmodel.Follow) error {
+	return ss.newQuery(ctx, following).Where("account_id = ?", accountID).Select()
+}
+
+func (ss *sqliteService) GetFollowersByAccountID(ctx context.Context, accountID string, followers *[]gtsmodel.Follow, localOnly bool) error {
+	q := ss.newQuery(ctx, followers)
+
+	if localOnly {
+		// for local accounts let's get where domain is null OR where domain is an empty string, just to be safe
+		q = q.Join("JOIN accounts AS a ON follow.account_id = a.id").
+			Where("follow.target_account_id = ?", accountID).
+			Where("a.domain IS NULL OR a.domain = ''")
+	} else {
+		q = q.Where("target_account_id = ?", accountID)
+	}
+
+	return q.Select()
+}
+
+func (ss *sqliteService) GetFavesByAccountID(ctx context.Context, accountID string, faves *[]gtsmodel.StatusFave) error {
+	return ss.newQuery(ctx, faves).Where("account_id = ?", accountID).Select()
+}
+
+func (ss *sqliteService) CountStatusesByAccountID(ctx context.Context, accountID string) (int, error) {
+	return ss.newQuery(ctx, &gtsmodel.Status{}).Where("account_id = ?", accountID).Count()
+}
+
+func (ss *sqliteService) GetStatusesForAccount(ctx context.Context, accountID string, limit int, excludeReplies bool, maxID string, pinnedOnly bool, mediaOnly bool) ([]*gtsmodel.Status, error) {
+	ss.log.Debugf("getting statuses for account %s", accountID)
+	statuses := []*gtsmodel.Status{}
+
+	q := ss.newQuery(ctx, &statuses).Order("id DESC")
+	if accountID != "" {
+		q = q.Where("account_id = ?", accountID)
+	}
+
+	if limit != 0 {
+		q = q.Limit(limit)
+	}
+
+	if excludeReplies {
+		q = q.Where("in_reply_to_id IS NULL")
+	}
+
+	if pinnedOnly {
+		q = q.Where("pinned = ?", true)
+	}
+
+	if mediaOnly {
+		q = q.Where("attachments IS NOT NULL AND attachments != '[]'")
+	}
+
+	if maxID != "" {
+		q = q.Where("id < ?", maxID)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(statuses) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	ss.log.Debugf("returning statuses for account %s", accountID)
+	return statuses, nil
+}
+
+func (ss *sqliteService) GetLastStatusForAccountID(ctx context.Context, accountID string, status *gtsmodel.Status) error {
+	if err := ss.newQuery(ctx, status).Order("created_at DESC").Where("account_id = ?", accountID).Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrNoEntries{}
+		}
+		return err
+	}
+	return nil
+}
+
+func (ss *sqliteService) IsUsernameAvailable(ctx context.Context, username string) error {
+	exists, err := ss.newQuery(ctx, &gtsmodel.Account{}).Where("username = ?", username).Where("domain IS NULL").Exists()
+	if err != nil {
+		return fmt.Errorf("db error: %s", err)
+	}
+	if exists {
+		return fmt.Errorf("username %s already in use", username)
+	}
+	return nil
+}
+
+func (ss *sqliteService) IsEmailAvailable(ctx context.Context, email string) error {
+	// parse the domain from the email
+	m, err := mail.ParseAddress(email)
+	if err != nil {
+		return fmt.Errorf("error parsing email address %s: %s", email, err)
+	}
+	domain := strings.Split(m.Address, "@")[1] // domain will always be the second part after @
+
+	// check if the email domain is blocked
+	blocked, err := ss.newQuery(ctx, &gtsmodel.EmailDomainBlock{}).Where("domain = ?", domain).Exists()
+	if err != nil {
+		return fmt.Errorf("db error: %s", err)
+	}
+	if blocked {
+		return fmt.Errorf("email domain %s is blocked", domain)
+	}
+
+	// check if this email is associated with a user already
+	inUse, err := ss.newQuery(ctx, &gtsmodel.User{}).Where("email = ?", email).WhereOr("unconfirmed_email = ?", email).Exists()
+	if err != nil {
+		return fmt.Errorf("db error: %s", err)
+	}
+	if inUse {
+		return fmt.Errorf("email %s already in use", email)
+	}
+	return nil
+}
+
+func (ss *sqliteService) NewSignup(ctx context.Context, username string, reason string, requireApproval bool, email string, password string, signUpIP net.IP, locale string, appID string, emailVerified bool, admin bool) (*gtsmodel.User, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		ss.log.Errorf("error creating new rsa key: %s", err)
+		return nil, err
+	}
+
+	// if something went wrong while creating a user, we might already have an account, so check here first...
+	a := &gtsmodel.Account{}
+	err = ss.newQuery(ctx, a).Where("username = ?", username).Where("domain IS NULL").Select()
+	if err != nil {
+		// there's been an actual error
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("db error checking existence of account: %s", err)
+		}
+
+		// we just don't have an account yet create one
+		newAccountURIs := util.GenerateURIsForAccount(username, ss.config.Protocol, ss.config.Host)
+		newAccountID, err := id.NewRandomULID()
+		if err != nil {
+			return nil, err
+		}
+
+		a = &gtsmodel.Account{
+			ID:                    newAccountID,
+			Username:              username,
+			DisplayName:           username,
+			Reason:                reason,
+			URL:                   newAccountURIs.UserURL,
+			PrivateKey:            key,
+			PublicKey:             &key.PublicKey,
+			PublicKeyURI:          newAccountURIs.PublicKeyURI,
+			ActorType:             gtsmodel.ActivityStreamsPerson,
+			URI:                   newAccountURIs.UserURI,
+			InboxURI:              newAccountURIs.InboxURI,
+			OutboxURI:             newAccountURIs.OutboxURI,
+			FollowersURI:          newAccountURIs.FollowersURI,
+			FollowingURI:          newAccountURIs.FollowingURI,
+			FeaturedCollectionURI: newAccountURIs.CollectionURI,
+		}
+		if err = ss.insert(ctx, a, "", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	pw, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing password: %s", err)
+	}
+
+	newUserID, err := id.NewRandomULID()
+	if err != nil {
+		return nil, err
+	}
+
+	u := &gtsmodel.User{
+		ID:                     newUserID,
+		AccountID:              a.ID,
+		EncryptedPassword:      string(pw),
+		SignUpIP:               signUpIP.To4(),
+		Locale:                 locale,
+		UnconfirmedEmail:       email,
+		CreatedByApplicationID: appID,
+		Approved:               !requireApproval, // if we don't require moderator approval, just pre-approve the user
+	}
+
+	if emailVerified {
+		u.ConfirmedAt = time.Now()
+		u.Email = email
+	}
+
+	if admin {
+		u.Admin = true
+		u.Moderator = true
+	}
+
+	if err = ss.insert(ctx, u, "", ""); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (ss *sqliteService) SetHeaderOrAvatarForAccountID(ctx context.Context, mediaAttachment *gtsmodel.MediaAttachment, accountID string) error {
+	if mediaAttachment.Avatar && mediaAttachment.Header {
+		return errors.New("one media attachment cannot be both header and avatar")
+	}
+
+	var headerOrAVI string
+	if mediaAttachment.Avatar {
+		headerOrAVI = "avatar"
+	} else if mediaAttachment.Header {
+		headerOrAVI = "header"
+	} else {
+		return errors.New("given media attachment was neither a header nor an avatar")
+	}
+
+	// TODO: there are probably more side effects here that need to be handled
+	if err := ss.insert(ctx, mediaAttachment, "id", ""); err != nil {
+		return err
+	}
+
+	if _, err := ss.newQuery(ctx, &gtsmodel.Account{}).Set(fmt.Sprintf("%s_media_attachment_id = ?", headerOrAVI), mediaAttachment.ID).Where("id = ?", accountID).Update(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ss *sqliteService) GetHeaderForAccountID(ctx context.Context, header *gtsmodel.MediaAttachment, accountID string) error {
+	acct := &gtsmodel.Account{}
+	if err := ss.newQuery(ctx, acct).Where("id = ?", accountID).Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrNoEntries{}
+		}
+		return err
+	}
+
+	if acct.HeaderMediaAttachmentID == "" {
+		return db.ErrNoEntries{}
+	}
+
+	if err := ss.newQuery(ctx, header).Where("id = ?", acct.HeaderMediaAttachmentID).Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrNoEntries{}
+		}
+		return err
+	}
+	return nil
+}
+
+func (ss *sqliteService) GetAvatarForAccountID(ctx context.Context, avatar *gtsmodel.MediaAttachment, accountID string) error {
+	acct := &gtsmodel.Account{}
+	if err := ss.newQuery(ctx, acct).Where("id = ?", accountID).Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrNoEntries{}
+		}
+		return err
+	}
+
+	if acct.AvatarMediaAttachmentID == "" {
+		return db.ErrNoEntries{}
+	}
+
+	if err := ss.newQuery(ctx, avatar).Where("id = ?", acct.AvatarMediaAttachmentID).Select(); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrNoEntries{}
+		}
+		return err
+	}
+	return nil
+}
+
+func (ss *sqliteService) Blocked(ctx context.Context, account1 string, account2 string) (bool, error) {
+	// TODO: check domain blocks as well
+	return ss.newQuery(ctx, &gtsmodel.Block{}).
+		Where("account_id = ? AND target_account_id = ?", account1, account2).
+		WhereOr("account_id = ? AND target_account_id = ?", account2, account1).
+		Exists()
+}
+
+func (ss *sqliteService) GetRelationship(ctx context.Context, requestingAccount string, targetAccount string) (*gtsmodel.Relationship, error) {
+	r := &gtsmodel.Relationship{
+		ID: targetAccount,
+	}
+
+	// check if the requesting account follows the target account
+	follow := &gtsmodel.Follow{}
+	if err := ss.newQuery(ctx, follow).Where("account_id = ?", requestingAccount).Where("target_account_id = ?", targetAccount).Select(); err != nil {
+		if err != sql.ErrNoRows {
+			// a proper error
+			return nil, fmt.Errorf("getrelationship: error checking follow existence: %s", err)
+		}
+		// no follow exists so these are all false
+		r.Following = false
+		r.ShowingReblogs = false
+		r.Notifying = false
+	} else {
+		// follow exists so we can fill these fields out...
+		r.Following = true
+		r.ShowingReblogs = follow.ShowReblogs
+		r.Notifying = follow.Notify
+	}
+
+	// check if the target account follows the requesting account
+	followedBy, err := ss.newQuery(ctx, &gtsmodel.Follow{}).Where("account_id = ?", targetAccount).Where("target_account_id = ?", requestingAccount).Exists()
+	if err != nil {
+		return nil, fmt.Errorf("getrelationship: error checking followed_by existence: %s", err)
+	}
+	r.FollowedBy = followedBy
+
+	// check if the requesting account blocks the target account
+	blocking, err := ss.newQuery(ctx, &gtsmodel.Block{}).Where("account_id = ?", requestingAccount).Where("target_account_id = ?", targetAccount).Exists()
+	if err != nil {
+		return nil, fmt.Errorf("getrelationship: error checking blocking existence: %s", err)
+	}
+	r.Blocking = blocking
+
+	// check if the target account blocks the requesting account
+	blockedBy, err := ss.newQuery(ctx, &gtsmodel.Block{}).Where("account_id = ?", targetAccount).Where("target_account_id = ?", requestingAccount).Exists()
+	if err != nil {
+		return nil, fmt.Errorf("getrelationship: error checking blocked existence: %s", err)
+	}
+	r.BlockedBy = blockedBy
+
+	// check if there's a pending following request from requesting account to target account
+	requested, err := ss.newQuery(ctx, &gtsmodel.FollowRequest{}).Where("account_id = ?", requestingAccount).Where("target_account_id = ?", targetAccount).Exists()
+	if err != nil {
+		return nil, fmt.Errorf("getrelationship: error checking blocked existence: %s", err)
+	}
+	r.Requested = requested
+
+	// check if the requesting account mutes the target account
+	mute, err := ss.GetMute(ctx, requestingAccount, targetAccount)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, fmt.Errorf("getrelationship: error checking mute existence: %s", err)
+		}
+	} else {
+		r.Muting = true
+		r.MutingNotifications = mute.Notifications
+	}
+
+	return r, nil
+}
+
+func (ss *sqliteService) Follows(ctx context.Context, sourceAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (bool, error) {
+	if sourceAccount == nil || targetAccount == nil {
+		return false, nil
+	}
+
+	return ss.newQuery(ctx, &gtsmodel.Follow{}).Where("account_id = ?", sourceAccount.ID).Where("target_account_id = ?", targetAccount.ID).Exists()
+}
+
+func (ss *sqliteService) FollowRequested(ctx context.Context, sourceAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (bool, error) {
+	if sourceAccount == nil || targetAccount == nil {
+		return false, nil
+	}
+
+	return ss.newQuery(ctx, &gtsmodel.FollowRequest{}).Where("account_id = ?", sourceAccount.ID).Where("target_account_id = ?", targetAccount.ID).Exists()
+}
+
+func (ss *sqliteService) Mutuals(ctx context.Context, account1 *gtsmodel.Account, account2 *gtsmodel.Account) (bool, error) {
+	if account1 == nil || account2 == nil {
+		return false, nil
+	}
+
+	// make sure account 1 follows account 2
+	f1, err := ss.newQuery(ctx, &gtsmodel.Follow{}).Where("account_id = ?", account1.ID).Where("target_account_id = ?", account2.ID).Exists()
+	if err != nil {
+		return false, err
+	}
+
+	// make sure account 2 follows account 1
+	f2, err := ss.newQuery(ctx, &gtsmodel.Follow{}).Where("account_id = ?", account2.ID).Where("target_account_id = ?", account1.ID).Exists()
+	if err != nil {
+		return false, err
+	}
+
+	return f1 && f2, nil
+}
+
+func (ss *sqliteService) GetReplyCountForStatus(ctx context.Context, status *gtsmodel.Status) (int, error) {
+	return ss.newQuery(ctx, &gtsmodel.Status{}).Where("in_reply_to_id = ?", status.ID).Count()
+}
+
+func (ss *sqliteService) GetReblogCountForStatus(ctx context.Context, status *gtsmodel.Status) (int, error) {
+	return ss.newQuery(ctx, &gtsmodel.Status{}).Where("boost_of_id = ?", status.ID).Count()
+}
+
+func (ss *sqliteService) GetFaveCountForStatus(ctx context.Context, status *gtsmodel.Status) (int, error) {
+	return ss.newQuery(ctx, &gtsmodel.StatusFave{}).Where("status_id = ?", status.ID).Count()
+}
+
+func (ss *sqliteService) StatusFavedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error) {
+	return ss.newQuery(ctx, &gtsmodel.StatusFave{}).Where("status_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
+}
+
+func (ss *sqliteService) StatusRebloggedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error) {
+	return ss.newQuery(ctx, &gtsmodel.Status{}).Where("boost_of_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
+}
+
+func (ss *sqliteService) StatusMutedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error) {
+	return ss.newQuery(ctx, &gtsmodel.StatusMute{}).Where("status_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
+}
+
+func (ss *sqliteService) StatusBookmarkedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error) {
+	return ss.newQuery(ctx, &gtsmodel.StatusBookmark{}).Where("status_id = ?", status.ID).Where("account_id = ?", accountID).Exists()
+}
+
+func (ss *sqliteService) WhoFavedStatus(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.Account, error) {
+	accounts := []*gtsmodel.Account{}
+
+	faves := []*gtsmodel.StatusFave{}
+	if err := ss.newQuery(ctx, &faves).Where("status_id = ?", status.ID).Select(); err != nil {
+		return nil, err // an actual error has occurred
+	}
+
+	for _, f := range faves {
+		acc := &gtsmodel.Account{}
+		if err := ss.newQuery(ctx, acc).Where("id = ?", f.AccountID).Select(); err != nil {
+			if err == sql.ErrNoRows {
+				continue // the account doesn't exist for some reason??? but this isn't the place to worry about that so just skip it
+			}
+			return nil, err // an actual error has occurred
+		}
+		accounts = append(accounts, acc)
+	}
+	return accounts, nil
+}
+
+func (ss *sqliteService) WhoBoostedStatus(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.Account, error) {
+	accounts := []*gtsmodel.Account{}
+
+	boosts := []*gtsmodel.Status{}
+	if err := ss.newQuery(ctx, &boosts).Where("boost_of_id = ?", status.ID).Select(); err != nil {
+		return nil, err // an actual error has occurred
+	}
+
+	for _, f := range boosts {
+		acc := &gtsmodel.Account{}
+		if err := ss.newQuery(ctx, acc).Where("id = ?", f.AccountID).Select(); err != nil {
+			if err == sql.ErrNoRows {
+				continue // the account doesn't exist for some reason??? but this isn't the place to worry about that so just skip it
+			}
+			return nil, err // an actual error has occurred
+		}
+		accounts = append(accounts, acc)
+	}
+	return accounts, nil
+}
+
+func (ss *sqliteService) GetNotificationsForAccount(ctx context.Context, accountID string, limit int, maxID string, sinceID string) ([]*gtsmodel.Notification, error) {
+	notifications := []*gtsmodel.Notification{}
+
+	q := ss.newQuery(ctx, &notifications).Where("target_account_id = ?", accountID)
+
+	if maxID != "" {
+		q = q.Where("id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("id > ?", sinceID)
+	}
+
+	if limit != 0 {
+		q = q.Limit(limit)
+	}
+
+	q = q.Order("created_at DESC")
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+/*
+	CONVERSION FUNCTIONS
+*/
+
+func (ss *sqliteService) MentionStringsToMentions(ctx context.Context, targetAccounts []string, originAccountID string, statusID string) ([]*gtsmodel.Mention, error) {
+	ogAccount := &gtsmodel.Account{}
+	if err := ss.newQuery(ctx, ogAccount).Where("id = ?", originAccountID).Select(); err != nil {
+		return nil, err
+	}
+
+	menchies := []*gtsmodel.Mention{}
+	for _, a := range targetAccounts {
+		// A mentioned account looks like "@test@example.org" or just "@test" for a local account
+		// -- we can guarantee this from the regex that targetAccounts should have been derived from.
+		// But we still need to do a bit of fiddling to get what we need here -- the username and domain (if given).
+
+		// 1.  trim off the first @
+		t := strings.TrimPrefix(a, "@")
+
+		// 2. split the username and domain
+		s := strings.Split(t, "@")
+
+		// 3. if it's length 1 it's a local account, length 2 means remote, anything else means something is wrong
+		var local bool
+		switch len(s) {
+		case 1:
+			local = true
+		case 2:
+			local = false
+		default:
+			return nil, fmt.Errorf("mentioned account format '%s' was not valid", a)
+		}
+
+		var username, domain string
+		username = s[0]
+		if !local {
+			domain = s[1]
+		}
+
+		// 4. check we now have a proper username and domain
+		if username == "" || (!local && domain == "") {
+			return nil, fmt.Errorf("username or domain for '%s' was nil", a)
+		}
+
+		// okay we're good now, we can start pulling accounts out of the database
+		mentionedAccount := &gtsmodel.Account{}
+		var err error
+
+		// match username + account, case insensitive
+		if local {
+			// local user -- should have a null domain
+			err = ss.newQuery(ctx, mentionedAccount).Where("LOWER(username) = LOWER(?)", username).Where("domain IS NULL").Select()
+		} else {
+			// remote user -- should have domain defined
+			err = ss.newQuery(ctx, mentionedAccount).Where("LOWER(username) = LOWER(?)", username).Where("LOWER(domain) = LOWER(?)", domain).Select()
+		}
+
+		if err != nil {
+			if err == sql.ErrNoRows {
+				// no result found for this username/domain so just don't include it as a mencho and carry on about our business
+				ss.log.Debugf("no account found with username '%s' and domain '%s', skipping it", username, domain)
+				continue
+			}
+			// a serious error has happened so bail
+			return nil, fmt.Errorf("error getting account with username '%s' and domain '%s': %s", username, domain, err)
+		}
+
+		// id, createdAt and updatedAt will be populated by the db, so we have everything we need!
+		menchies = append(menchies, &gtsmodel.Mention{
+			StatusID:            statusID,
+			OriginAccountID:     ogAccount.ID,
+			OriginAccountURI:    ogAccount.URI,
+			TargetAccountID:     mentionedAccount.ID,
+			NameString:          a,
+			MentionedAccountURI: mentionedAccount.URI,
+			MentionedAccountURL: mentionedAccount.URL,
+			GTSAccount:          mentionedAccount,
+		})
+	}
+	return menchies, nil
+}
+
+func (ss *sqliteService) TagStringsToTags(ctx context.Context, tags []string, originAccountID string, statusID string) ([]*gtsmodel.Tag, error) {
+	newTags := []*gtsmodel.Tag{}
+	for _, t := range tags {
+		tag := &gtsmodel.Tag{}
+		if err := ss.newQuery(ctx, tag).Where("LOWER(name) = LOWER(?)", t).Select(); err != nil {
+			if err == sql.ErrNoRows {
+				// tag doesn't exist yet so populate it
+				newID, err := id.NewRandomULID()
+				if err != nil {
+					return nil, err
+				}
+				tag.ID = newID
+				tag.URL = fmt.Sprintf("%s://%s/tags/%s", ss.config.Protocol, ss.config.Host, t)
+				tag.Name = t
+				tag.FirstSeenFromAccountID = originAccountID
+				tag.CreatedAt = time.Now()
+				tag.UpdatedAt = time.Now()
+				tag.Useable = true
+				tag.Listable = true
+			} else {
+				return nil, fmt.Errorf("error getting tag with name %s: %s", t, err)
+			}
+		}
+
+		// bail already if the tag isn't useable
+		if !tag.Useable {
+			continue
+		}
+		tag.LastStatusAt = time.Now()
+		newTags = append(newTags, tag)
+	}
+	return newTags, nil
+}
+
+func (ss *sqliteService) EmojiStringsToEmojis(ctx context.Context, emojis []string, originAccountID string, statusID string) ([]*gtsmodel.Emoji, error) {
+	newEmojis := []*gtsmodel.Emoji{}
+	for _, e := range emojis {
+		emoji := &gtsmodel.Emoji{}
+		err := ss.newQuery(ctx, emoji).Where("shortcode = ?", e).Where("visible_in_picker = ?", true).Where("disabled = ?", false).Select()
+		if err != nil {
+			if err == sql.ErrNoRows {
+				// no result found for this username/domain so just don't include it as an emoji and carry on about our business
+				ss.log.Debugf("no emoji found with shortcode %s, skipping it", e)
+				continue
+			}
+			// a serious error has happened so bail
+			return nil, fmt.Errorf("error getting emoji with shortcode %s: %s", e, err)
+		}
+		newEmojis = append(newEmojis, emoji)
+	}
+	return newEmojis, nil
+}
@@ -0,0 +1,94 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (ss *sqliteService) GetTagTimeline(ctx context.Context, anyTagIDs []string, allTagIDs []string, noneTagIDs []string, maxID string, sinceID string, minID string, limit int, local bool, mediaOnly bool) ([]*gtsmodel.Status, error) {
+	statuses := []*gtsmodel.Status{}
+
+	if len(anyTagIDs) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	q := ss.newQuery(ctx, &statuses).
+		Where("visibility = ?", gtsmodel.VisibilityPublic).
+		Where("in_reply_to_id IS NULL").
+		Where("in_reply_to_uri IS NULL").
+		Where("boost_of_id IS NULL").
+		Order("status.id DESC")
+
+	// arrays are stored as json, so look for the quoted tag ids in there
+	anyConditions := []string{}
+	anyArgs := []interface{}{}
+	for _, tagID := range anyTagIDs {
+		anyConditions = append(anyConditions, "tags LIKE ?")
+		anyArgs = append(anyArgs, fmt.Sprintf("%%%q%%", tagID))
+	}
+	q = q.Where("("+strings.Join(anyConditions, " OR ")+")", anyArgs...)
+
+	for _, tagID := range allTagIDs {
+		q = q.Where("tags LIKE ?", fmt.Sprintf("%%%q%%", tagID))
+	}
+
+	for _, tagID := range noneTagIDs {
+		q = q.Where("tags NOT LIKE ?", fmt.Sprintf("%%%q%%", tagID))
+	}
+
+	if maxID != "" {
+		q = q.Where("status.id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("status.id > ?", sinceID)
+	}
+
+	if minID != "" {
+		q = q.Where("status.id > ?", minID)
+	}
+
+	if local {
+		q = q.Where("status.local = ?", local)
+	}
+
+	if mediaOnly {
+		q = q.Where("attachments IS NOT NULL AND attachments != '[]'")
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(statuses) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return statuses, nil
+}
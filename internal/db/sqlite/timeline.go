@@ -0,0 +1,219 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"sort"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (ss *sqliteService) GetHomeTimelineForAccount(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, error) {
+	statuses := []*gtsmodel.Status{}
+
+	q := ss.newQuery(ctx, &statuses).
+		Join("LEFT JOIN follows AS f ON f.target_account_id = status.account_id").
+		Where("f.account_id = ?", accountID).
+		Order("status.id DESC")
+
+	if maxID != "" {
+		q = q.Where("status.id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("status.id > ?", sinceID)
+	}
+
+	if minID != "" {
+		q = q.Where("status.id > ?", minID)
+	}
+
+	if local {
+		q = q.Where("status.local = ?", local)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(statuses) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return statuses, nil
+}
+
+func (ss *sqliteService) GetPublicTimelineForAccount(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, error) {
+	statuses := []*gtsmodel.Status{}
+
+	q := ss.newQuery(ctx, &statuses).
+		Where("visibility = ?", gtsmodel.VisibilityPublic).
+		Where("in_reply_to_id IS NULL").
+		Where("in_reply_to_uri IS NULL").
+		Where("boost_of_id IS NULL").
+		Order("status.id DESC")
+
+	if maxID != "" {
+		q = q.Where("status.id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("status.id > ?", sinceID)
+	}
+
+	if minID != "" {
+		q = q.Where("status.id > ?", minID)
+	}
+
+	if local {
+		q = q.Where("status.local = ?", local)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Select(); err != nil {
+		return nil, err
+	}
+
+	if len(statuses) == 0 {
+		return nil, db.ErrNoEntries{}
+	}
+
+	return statuses, nil
+}
+
+func (ss *sqliteService) GetFavedTimelineForAccount(ctx context.Context, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Status, string, string, error) {
+	faves := []*gtsmodel.StatusFave{}
+
+	fq := ss.newQuery(ctx, &faves).
+		Where("account_id = ?", accountID).
+		Order("id DESC")
+
+	if maxID != "" {
+		fq = fq.Where("id < ?", maxID)
+	}
+
+	if minID != "" {
+		fq = fq.Where("id > ?", minID)
+	}
+
+	if limit > 0 {
+		fq = fq.Limit(limit)
+	}
+
+	if err := fq.Select(); err != nil {
+		return nil, "", "", err
+	}
+
+	if len(faves) == 0 {
+		return nil, "", "", db.ErrNoEntries{}
+	}
+
+	// map[statusID]faveID -- we need this to sort statuses by fave ID rather than their own ID
+	statusesFavesMap := map[string]string{}
+
+	in := []string{}
+	for _, f := range faves {
+		statusesFavesMap[f.StatusID] = f.ID
+		in = append(in, f.StatusID)
+	}
+
+	statuses := []*gtsmodel.Status{}
+	if err := ss.newQuery(ctx, &statuses).WhereIn("id", in).Select(); err != nil {
+		return nil, "", "", err
+	}
+
+	if len(statuses) == 0 {
+		return nil, "", "", db.ErrNoEntries{}
+	}
+
+	// arrange statuses by fave ID
+	sort.Slice(statuses, func(i int, j int) bool {
+		statusI := statuses[i]
+		statusJ := statuses[j]
+		return statusesFavesMap[statusI.ID] < statusesFavesMap[statusJ.ID]
+	})
+
+	nextMaxID := faves[len(faves)-1].ID
+	prevMinID := faves[0].ID
+	return statuses, nextMaxID, prevMinID, nil
+}
+
+func (ss *sqliteService) GetBookmarkedTimelineForAccount(ctx context.Context, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Status, string, string, error) {
+	bookmarks := []*gtsmodel.StatusBookmark{}
+
+	bq := ss.newQuery(ctx, &bookmarks).
+		Where("account_id = ?", accountID).
+		Order("id DESC")
+
+	if maxID != "" {
+		bq = bq.Where("id < ?", maxID)
+	}
+
+	if minID != "" {
+		bq = bq.Where("id > ?", minID)
+	}
+
+	if limit > 0 {
+		bq = bq.Limit(limit)
+	}
+
+	if err := bq.Select(); err != nil {
+		return nil, "", "", err
+	}
+
+	if len(bookmarks) == 0 {
+		return nil, "", "", db.ErrNoEntries{}
+	}
+
+	// map[statusID]bookmarkID -- we need this to sort statuses by bookmark ID rather than their own ID
+	statusesBookmarksMap := map[string]string{}
+
+	in := []string{}
+	for _, b := range bookmarks {
+		statusesBookmarksMap[b.StatusID] = b.ID
+		in = append(in, b.StatusID)
+	}
+
+	statuses := []*gtsmodel.Status{}
+	if err := ss.newQuery(ctx, &statuses).WhereIn("id", in).Select(); err != nil {
+		return nil, "", "", err
+	}
+
+	if len(statuses) == 0 {
+		return nil, "", "", db.ErrNoEntries{}
+	}
+
+	// arrange statuses by bookmark ID, newest bookmark first
+	sort.Slice(statuses, func(i int, j int) bool {
+		return statusesBookmarksMap[statuses[i].ID] > statusesBookmarksMap[statuses[j].ID]
+	})
+
+	nextMaxID := bookmarks[len(bookmarks)-1].ID
+	prevMinID := bookmarks[0].ID
+	return statuses, nextMaxID, prevMinID, nil
+}
@@ -0,0 +1,49 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sqlite
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+)
+
+func (ss *sqliteService) Upsert(ctx context.Context, i interface{}, conflictColumn string) error {
+	return ss.insert(ctx, i, conflictColumn, "")
+}
+
+func (ss *sqliteService) UpdateByID(ctx context.Context, id string, i interface{}) error {
+	return ss.insert(ctx, i, "id", "")
+}
+
+func (ss *sqliteService) UpdateOneByID(ctx context.Context, id string, key string, value interface{}, i interface{}) error {
+	_, err := ss.newQuery(ctx, i).Set(key+" = ?", value).Where("id = ?", id).Update()
+	return err
+}
+
+func (ss *sqliteService) UpdateWhere(ctx context.Context, where []db.Where, key string, value interface{}, i interface{}) error {
+	q := ss.newQuery(ctx, i)
+
+	for _, w := range where {
+		q = whereToQuery(q, w)
+	}
+
+	_, err := q.Set(key+" = ?", value).Update()
+	return err
+}
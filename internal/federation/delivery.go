@@ -0,0 +1,404 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/metrics"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+)
+
+const (
+	// deliveryMaxAttempts is the number of times a delivery will be attempted before it's given up on.
+	deliveryMaxAttempts = 10
+	// deliveryRetryBackoff is how long to wait before the first retry of a failed delivery; it doubles with every further attempt,
+	// so a delivery is retried for about 17 hours before it's given up on.
+	deliveryRetryBackoff = 1 * time.Minute
+	// deliveryUnreachableAfter is the number of failed deliveries in a row after which an instance is considered unreachable.
+	deliveryUnreachableAfter = 5
+	// deliveryPollInterval is how often the db is checked for deliveries that are due to be retried.
+	deliveryPollInterval = 1 * time.Minute
+	// deliveryBatchSize is the maximum number of deliveries that will be retried in one go.
+	deliveryBatchSize = 100
+	// deliveryProbeInterval is how long deliveries to an unreachable instance are held back for
+	// before being looked at again, so that they don't crowd out deliveries that are due to other instances.
+	deliveryProbeInterval = 15 * time.Minute
+	// deliveryMaxAge is how long a delivery is kept around for before it's given up on, even if it's never been attempted
+	// because the instance it's addressed to has been unreachable all this time.
+	deliveryMaxAge = 7 * 24 * time.Hour
+	// deliveryFailedRetention is how long a delivery that has been given up on is kept around for, so that admins can
+	// see what didn't go through, before it's deleted.
+	deliveryFailedRetention = 7 * 24 * time.Hour
+)
+
+// deliveryTransport wraps a transport so that deliveries which fail are stored and retried later, instead of being dropped.
+//
+// Deliveries to instances which are currently unreachable aren't attempted at all, but are stored straight away,
+// to be sent once the instance has recovered.
+type deliveryTransport struct {
+	transport.Transport
+	f         *federator
+	accountID string
+}
+
+// Deliver delivers b to the given inbox. If delivery fails, it's stored for retrying, and only an error storing
+// the delivery will be returned: the caller shouldn't retry the delivery itself.
+func (t *deliveryTransport) Deliver(ctx context.Context, b []byte, to *url.URL) error {
+	return t.f.deliver(ctx, t.Transport, t.accountID, b, to)
+}
+
+// BatchDeliver delivers b to each of the given inboxes concurrently, in the same way as Deliver.
+func (t *deliveryTransport) BatchDeliver(ctx context.Context, b []byte, recipients []*url.URL) error {
+	wg := &sync.WaitGroup{}
+	errs := make(chan error, len(recipients))
+	for _, to := range recipients {
+		wg.Add(1)
+		go func(to *url.URL) {
+			defer wg.Done()
+			if err := t.Deliver(ctx, b, to); err != nil {
+				errs <- err
+			}
+		}(to)
+	}
+	wg.Wait()
+	close(errs)
+
+	errStrs := []string{}
+	for err := range errs {
+		errStrs = append(errStrs, err.Error())
+	}
+	if len(errStrs) > 0 {
+		return fmt.Errorf("batch deliver had at least one failure: %s", strings.Join(errStrs, "; "))
+	}
+	return nil
+}
+
+// deliver delivers b to the given inbox using the given transport, storing the delivery for retrying if it fails.
+func (f *federator) deliver(ctx context.Context, t transport.Transport, accountID string, b []byte, to *url.URL) error {
+	l := f.log.WithFields(logrus.Fields{
+		"func": "deliver",
+		"to":   to.String(),
+	})
+
+	if !f.instanceReachable(ctx, to.Host) {
+		l.Debugf("instance %s is unreachable, holding back delivery", to.Host)
+		return f.storeDelivery(ctx, accountID, b, to, 0, "instance unreachable", time.Now().Add(deliveryProbeInterval))
+	}
+
+	if err := t.Deliver(ctx, b, to); err != nil {
+		l.Debugf("delivery failed, will retry: %s", err)
+		metrics.DeliveryFailed(to.Host)
+		f.deliveryFailed(ctx, to.Host)
+		return f.storeDelivery(ctx, accountID, b, to, 1, err.Error(), time.Now().Add(deliveryBackoff(1)))
+	}
+
+	metrics.DeliverySucceeded(to.Host)
+	f.deliverySucceeded(ctx, to)
+	return nil
+}
+
+// storeDelivery stores a delivery of b to the given inbox that has been attempted the given number of times, to be retried at nextAttemptAt.
+func (f *federator) storeDelivery(ctx context.Context, accountID string, b []byte, to *url.URL, attempts int, lastError string, nextAttemptAt time.Time) error {
+	deliveryID, err := id.NewULID()
+	if err != nil {
+		return err
+	}
+
+	delivery := &gtsmodel.Delivery{
+		ID:            deliveryID,
+		Domain:        to.Host,
+		AccountID:     accountID,
+		InboxURI:      to.String(),
+		Payload:       string(b),
+		Attempts:      attempts,
+		LastError:     lastError,
+		NextAttemptAt: nextAttemptAt,
+	}
+
+	if err := f.db.Put(ctx, delivery); err != nil {
+		return fmt.Errorf("error storing delivery to %s: %s", to.String(), err)
+	}
+	return nil
+}
+
+// deliveryBackoff returns how long to wait before retrying a delivery that has failed the given number of times.
+func deliveryBackoff(attempts int) time.Duration {
+	if attempts <= 0 {
+		return 0
+	}
+	return deliveryRetryBackoff << (attempts - 1)
+}
+
+// instanceReachable returns false if the instance with the given domain has been marked as unreachable.
+// Instances that we don't have an entry for are assumed to be reachable.
+func (f *federator) instanceReachable(ctx context.Context, domain string) bool {
+	instance := &gtsmodel.Instance{}
+	if err := f.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: domain, CaseInsensitive: true}}, instance); err != nil {
+		return true
+	}
+	return instance.UnreachableSince.IsZero()
+}
+
+// deliveryFailed records a failed delivery against the instance with the given domain,
+// marking it as unreachable if too many deliveries to it have failed in a row.
+//
+// Failures can only be recorded against an instance that we have an entry for. Entries are made for instances
+// when we've dereferenced them, which happens when they first talk to us, or when we first deliver to them successfully.
+// Deliveries to other instances are still stored and retried as normal, they're just never held back.
+func (f *federator) deliveryFailed(ctx context.Context, domain string) {
+	f.deliverySync.Lock()
+	defer f.deliverySync.Unlock()
+
+	instance := &gtsmodel.Instance{}
+	if err := f.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: domain, CaseInsensitive: true}}, instance); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			f.log.Errorf("deliveryFailed: db error getting instance %s: %s", domain, err)
+			return
+		}
+		f.log.Debugf("deliveryFailed: no entry for instance %s, not recording failure", domain)
+		return
+	}
+
+	instance.DeliveryFailures = instance.DeliveryFailures + 1
+	if instance.DeliveryFailures >= deliveryUnreachableAfter && instance.UnreachableSince.IsZero() {
+		f.log.Infof("deliveryFailed: %d deliveries to %s have failed in a row, marking it as unreachable", instance.DeliveryFailures, domain)
+		instance.UnreachableSince = time.Now()
+	}
+	instance.UpdatedAt = time.Now()
+
+	if err := f.db.UpdateByID(ctx, instance.ID, instance); err != nil {
+		f.log.Errorf("deliveryFailed: db error updating instance %s: %s", domain, err)
+	}
+}
+
+// deliverySucceeded clears any failed deliveries recorded against the instance of the given inbox,
+// marking it as reachable again if necessary.
+//
+// If we don't have an entry for the instance yet, then now that we know it's reachable, it's dereferenced
+// and put in the db, so that any deliveries to it that fail from now on can be recorded against it.
+func (f *federator) deliverySucceeded(ctx context.Context, inbox *url.URL) {
+	domain := inbox.Host
+
+	f.deliverySync.Lock()
+	instance := &gtsmodel.Instance{}
+	if err := f.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: domain, CaseInsensitive: true}}, instance); err != nil {
+		// don't hold the lock while dereferencing
+		f.deliverySync.Unlock()
+		if _, ok := err.(db.ErrNoEntries); ok {
+			f.addDeliveryInstance(ctx, inbox)
+		}
+		return
+	}
+	defer f.deliverySync.Unlock()
+
+	if instance.DeliveryFailures == 0 && instance.UnreachableSince.IsZero() {
+		// nothing to do
+		return
+	}
+
+	if !instance.UnreachableSince.IsZero() {
+		f.log.Infof("deliverySucceeded: %s is reachable again", domain)
+	}
+	instance.DeliveryFailures = 0
+	instance.UnreachableSince = time.Time{}
+	instance.UpdatedAt = time.Now()
+
+	if err := f.db.UpdateByID(ctx, instance.ID, instance); err != nil {
+		f.log.Errorf("deliverySucceeded: db error updating instance %s: %s", domain, err)
+	}
+}
+
+// addDeliveryInstance dereferences the instance of the given inbox, and puts it in the db.
+func (f *federator) addDeliveryInstance(ctx context.Context, inbox *url.URL) {
+	instance, err := f.DereferenceRemoteInstance(ctx, "", &url.URL{
+		Scheme: inbox.Scheme,
+		Host:   inbox.Host,
+	})
+	if err != nil {
+		f.log.Debugf("addDeliveryInstance: couldn't dereference instance %s: %s", inbox.Host, err)
+		return
+	}
+
+	if err := f.db.Put(ctx, instance); err != nil {
+		if _, ok := err.(db.ErrAlreadyExists); !ok {
+			f.log.Errorf("addDeliveryInstance: db error putting instance %s: %s", inbox.Host, err)
+		}
+	}
+}
+
+// retryDeliveries retries deliveries that are due, once every deliveryPollInterval, until the federator is stopped.
+func (f *federator) retryDeliveries(ctx context.Context) {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(deliveryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.pruneFailedDeliveries(ctx)
+			f.retryDueDeliveries(ctx)
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+// pruneFailedDeliveries deletes deliveries that were given up on more than deliveryFailedRetention ago.
+func (f *federator) pruneFailedDeliveries(ctx context.Context) {
+	if err := f.db.DeleteFailedDeliveries(ctx, time.Now().Add(-deliveryFailedRetention)); err != nil {
+		f.log.Errorf("pruneFailedDeliveries: db error deleting failed deliveries: %s", err)
+	}
+}
+
+// retryDueDeliveries retries one batch of deliveries that are due.
+//
+// Only one delivery per unreachable instance is retried in each batch: if it goes through, the instance
+// is marked as reachable again, and the rest of its deliveries will go out as normal. If it doesn't, the rest
+// are held back for deliveryProbeInterval, so that the next batch can get on with deliveries to other instances.
+func (f *federator) retryDueDeliveries(ctx context.Context) {
+	deliveries, err := f.db.GetDueDeliveries(ctx, deliveryBatchSize)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			f.log.Errorf("retryDueDeliveries: db error getting due deliveries: %s", err)
+		}
+		return
+	}
+
+	probed := map[string]bool{}
+	transports := map[string]transport.Transport{}
+	for _, delivery := range deliveries {
+		select {
+		case <-f.stop:
+			return
+		default:
+		}
+
+		if time.Since(delivery.CreatedAt) > deliveryMaxAge {
+			f.giveUpDelivery(ctx, delivery)
+			continue
+		}
+
+		if !f.instanceReachable(ctx, delivery.Domain) {
+			if probed[delivery.Domain] {
+				f.holdDelivery(ctx, delivery)
+				continue
+			}
+			probed[delivery.Domain] = true
+		}
+
+		t, ok := transports[delivery.AccountID]
+		if !ok {
+			account := &gtsmodel.Account{}
+			if err := f.db.GetByID(ctx, delivery.AccountID, account); err != nil {
+				if _, ok := err.(db.ErrNoEntries); ok {
+					// the sending account is gone, so there's no way to sign this delivery anymore
+					if err := f.db.DeleteByID(ctx, delivery.ID, delivery); err != nil {
+						f.log.Errorf("retryDueDeliveries: db error deleting delivery %s: %s", delivery.ID, err)
+					}
+					continue
+				}
+				f.log.Errorf("retryDueDeliveries: db error getting account %s: %s", delivery.AccountID, err)
+				continue
+			}
+
+			t, err = f.transportController.NewTransport(account.PublicKeyURI, account.PrivateKey)
+			if err != nil {
+				f.log.Errorf("retryDueDeliveries: error creating transport for account %s: %s", delivery.AccountID, err)
+				continue
+			}
+			transports[delivery.AccountID] = t
+		}
+
+		f.retryDelivery(ctx, t, delivery)
+	}
+}
+
+// retryDelivery makes another attempt at the given delivery, removing it if it succeeds,
+// or scheduling the next attempt if it fails.
+func (f *federator) retryDelivery(ctx context.Context, t transport.Transport, delivery *gtsmodel.Delivery) {
+	l := f.log.WithFields(logrus.Fields{
+		"func": "retryDelivery",
+		"to":   delivery.InboxURI,
+	})
+
+	to, err := url.Parse(delivery.InboxURI)
+	if err == nil {
+		err = t.Deliver(ctx, []byte(delivery.Payload), to)
+	}
+
+	if err == nil {
+		metrics.DeliverySucceeded(delivery.Domain)
+		f.deliverySucceeded(ctx, to)
+		if err := f.db.DeleteByID(ctx, delivery.ID, delivery); err != nil {
+			l.Errorf("db error deleting delivery %s: %s", delivery.ID, err)
+		}
+		return
+	}
+
+	metrics.DeliveryFailed(delivery.Domain)
+	f.deliveryFailed(ctx, delivery.Domain)
+
+	now := time.Now()
+	delivery.Attempts = delivery.Attempts + 1
+	delivery.LastError = err.Error()
+	delivery.UpdatedAt = now
+	if delivery.Attempts >= deliveryMaxAttempts {
+		l.Infof("giving up on delivery after %d attempts: %s", delivery.Attempts, err)
+		delivery.FailedAt = now
+	} else {
+		l.Debugf("delivery failed on attempt %d, will retry: %s", delivery.Attempts, err)
+		delivery.NextAttemptAt = now.Add(deliveryBackoff(delivery.Attempts))
+	}
+
+	if err := f.db.UpdateByID(ctx, delivery.ID, delivery); err != nil {
+		l.Errorf("db error updating delivery %s: %s", delivery.ID, err)
+	}
+}
+
+// holdDelivery puts off the next attempt at the given delivery, which is addressed to an unreachable instance, by deliveryProbeInterval.
+func (f *federator) holdDelivery(ctx context.Context, delivery *gtsmodel.Delivery) {
+	now := time.Now()
+	delivery.NextAttemptAt = now.Add(deliveryProbeInterval)
+	delivery.UpdatedAt = now
+	if err := f.db.UpdateByID(ctx, delivery.ID, delivery); err != nil {
+		f.log.Errorf("holdDelivery: db error updating delivery %s: %s", delivery.ID, err)
+	}
+}
+
+// giveUpDelivery marks the given delivery as failed, so that it won't be attempted again.
+func (f *federator) giveUpDelivery(ctx context.Context, delivery *gtsmodel.Delivery) {
+	f.log.Infof("giveUpDelivery: giving up on delivery to %s after %s", delivery.InboxURI, deliveryMaxAge)
+	now := time.Now()
+	delivery.FailedAt = now
+	delivery.UpdatedAt = now
+	if err := f.db.UpdateByID(ctx, delivery.ID, delivery); err != nil {
+		f.log.Errorf("giveUpDelivery: db error updating delivery %s: %s", delivery.ID, err)
+	}
+}
@@ -0,0 +1,200 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federation_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/superseriousbusiness/gotosocial/internal/blob"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/federation"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type DeliveryTestSuite struct {
+	suite.Suite
+	config        *config.Config
+	db            db.DB
+	log           *logrus.Logger
+	storage       blob.Storage
+	typeConverter typeutils.TypeConverter
+}
+
+func (suite *DeliveryTestSuite) SetupSuite() {
+	suite.config = testrig.NewTestConfig()
+	suite.db = testrig.NewTestDB()
+	suite.log = testrig.NewTestLog()
+	suite.storage = testrig.NewTestStorage()
+	suite.typeConverter = testrig.NewTestTypeConverter(suite.db)
+}
+
+func (suite *DeliveryTestSuite) SetupTest() {
+	testrig.StandardDBSetup(suite.db)
+}
+
+func (suite *DeliveryTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+// make sure failed deliveries are stored for retrying, and that deliveries to an instance that keeps failing are held back
+func (suite *DeliveryTestSuite) TestFailedDeliveriesMarkInstanceUnreachable() {
+	ctx := context.Background()
+
+	// every delivery to the remote instance fails
+	attempts := 0
+	tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		attempts = attempts + 1
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	}))
+	federator := federation.NewFederator(suite.db, testrig.NewTestFederatingDB(suite.db), tc, suite.config, suite.log, suite.typeConverter, testrig.NewTestMediaHandler(suite.db, suite.storage))
+
+	outbox, err := url.Parse("http://localhost:8080/users/the_mighty_zork/outbox")
+	suite.NoError(err)
+	inbox, err := url.Parse("https://dead.example.org/users/someone/inbox")
+	suite.NoError(err)
+
+	t, err := federator.NewTransport(ctx, outbox, "")
+	suite.NoError(err)
+
+	// we've heard from this instance before
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.Instance{
+		ID:     "01FJ2J0V5RFQ0H4GB9GZ4S0R8E",
+		Domain: "dead.example.org",
+		URI:    "https://dead.example.org",
+		Title:  "dead",
+	}))
+
+	// the first five deliveries are attempted, and fail; failures are stored rather than returned
+	for i := 0; i < 5; i++ {
+		suite.NoError(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox))
+	}
+	suite.Equal(5, attempts)
+
+	instance := &gtsmodel.Instance{}
+	err = suite.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: "dead.example.org"}}, instance)
+	suite.NoError(err)
+	suite.Equal(5, instance.DeliveryFailures)
+	suite.False(instance.UnreachableSince.IsZero())
+
+	// the instance is unreachable now, so this delivery shouldn't even be attempted
+	suite.NoError(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox))
+	suite.Equal(5, attempts)
+
+	// but all six deliveries should be waiting to be retried
+	deliveries := []*gtsmodel.Delivery{}
+	err = suite.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: "dead.example.org"}}, &deliveries)
+	suite.NoError(err)
+	suite.Len(deliveries, 6)
+	held := 0
+	for _, d := range deliveries {
+		suite.Equal(inbox.String(), d.InboxURI)
+		suite.Equal(testrig.NewTestAccounts()["local_account_1"].ID, d.AccountID)
+		suite.True(d.FailedAt.IsZero())
+
+		// the held back delivery shouldn't be due until the instance has had time to recover,
+		// so that it doesn't get in the way of deliveries to other instances
+		if d.Attempts == 0 {
+			held = held + 1
+			suite.True(d.NextAttemptAt.After(time.Now().Add(10 * time.Minute)))
+		}
+	}
+	suite.Equal(1, held)
+}
+
+// make sure failed deliveries to an instance we don't know about yet are still stored, without making up an entry for the instance
+func (suite *DeliveryTestSuite) TestFailedDeliveryToUnknownInstance() {
+	ctx := context.Background()
+
+	tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	}))
+	federator := federation.NewFederator(suite.db, testrig.NewTestFederatingDB(suite.db), tc, suite.config, suite.log, suite.typeConverter, testrig.NewTestMediaHandler(suite.db, suite.storage))
+
+	outbox, err := url.Parse("http://localhost:8080/users/the_mighty_zork/outbox")
+	suite.NoError(err)
+	inbox, err := url.Parse("https://unknown.example.org/users/someone/inbox")
+	suite.NoError(err)
+
+	t, err := federator.NewTransport(ctx, outbox, "")
+	suite.NoError(err)
+	suite.NoError(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox))
+
+	err = suite.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: "unknown.example.org"}}, &gtsmodel.Instance{})
+	suite.IsType(db.ErrNoEntries{}, err)
+
+	deliveries := []*gtsmodel.Delivery{}
+	suite.NoError(suite.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: "unknown.example.org"}}, &deliveries))
+	suite.Len(deliveries, 1)
+}
+
+// make sure that an instance we don't know about yet is dereferenced once a delivery to it goes through
+func (suite *DeliveryTestSuite) TestSuccessfulDeliveryDereferencesInstance() {
+	ctx := context.Background()
+
+	tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet && req.URL.Path == "/api/v1/instance" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"uri":"alive.example.org","title":"alive and well","version":"3.4.1"}`))),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusAccepted,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	}))
+	federator := federation.NewFederator(suite.db, testrig.NewTestFederatingDB(suite.db), tc, suite.config, suite.log, suite.typeConverter, testrig.NewTestMediaHandler(suite.db, suite.storage))
+
+	outbox, err := url.Parse("http://localhost:8080/users/the_mighty_zork/outbox")
+	suite.NoError(err)
+	inbox, err := url.Parse("https://alive.example.org/users/someone/inbox")
+	suite.NoError(err)
+
+	t, err := federator.NewTransport(ctx, outbox, "")
+	suite.NoError(err)
+	suite.NoError(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox))
+
+	instance := &gtsmodel.Instance{}
+	suite.NoError(suite.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: "alive.example.org"}}, instance))
+	suite.Equal("https://alive.example.org", instance.URI)
+	suite.Equal("alive and well", instance.Title)
+	suite.Equal("3.4.1", instance.Version)
+}
+
+func TestDeliveryTestSuite(t *testing.T) {
+	suite.Run(t, new(DeliveryTestSuite))
+}
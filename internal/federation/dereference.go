@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/go-fed/activity/streams"
 	"github.com/go-fed/activity/streams/vocab"
@@ -13,24 +14,29 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/tracing"
 	"github.com/superseriousbusiness/gotosocial/internal/transport"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func (f *federator) DereferenceRemoteAccount(username string, remoteAccountID *url.URL) (typeutils.Accountable, error) {
+func (f *federator) DereferenceRemoteAccount(ctx context.Context, username string, remoteAccountID *url.URL) (typeutils.Accountable, error) {
+	ctx, span := tracing.StartSpan(ctx, "federation.DereferenceRemoteAccount", attribute.String("uri", remoteAccountID.String()))
+	defer span.End()
+
 	f.startHandshake(username, remoteAccountID)
 	defer f.stopHandshake(username, remoteAccountID)
 
-	if blocked, err := f.blockedDomain(remoteAccountID.Host); blocked || err != nil {
+	if blocked, err := f.blockedDomain(ctx, remoteAccountID.Host); blocked || err != nil {
 		return nil, fmt.Errorf("DereferenceRemoteAccount: domain %s is blocked", remoteAccountID.Host)
 	}
 
-	transport, err := f.GetTransportForUser(username)
+	transport, err := f.GetTransportForUser(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("transport err: %s", err)
 	}
 
-	b, err := transport.Dereference(context.Background(), remoteAccountID)
+	b, err := transport.Dereference(ctx, remoteAccountID)
 	if err != nil {
 		return nil, fmt.Errorf("error deferencing %s: %s", remoteAccountID.String(), err)
 	}
@@ -40,7 +46,7 @@ func (f *federator) DereferenceRemoteAccount(username string, remoteAccountID *u
 		return nil, fmt.Errorf("error unmarshalling bytes into json: %s", err)
 	}
 
-	t, err := streams.ToType(context.Background(), m)
+	t, err := streams.ToType(ctx, m)
 	if err != nil {
 		return nil, fmt.Errorf("error resolving json into ap vocab type: %s", err)
 	}
@@ -69,17 +75,20 @@ func (f *federator) DereferenceRemoteAccount(username string, remoteAccountID *u
 	return nil, fmt.Errorf("type name %s not supported", t.GetTypeName())
 }
 
-func (f *federator) DereferenceRemoteStatus(username string, remoteStatusID *url.URL) (typeutils.Statusable, error) {
-	if blocked, err := f.blockedDomain(remoteStatusID.Host); blocked || err != nil {
+func (f *federator) DereferenceRemoteStatus(ctx context.Context, username string, remoteStatusID *url.URL) (typeutils.Statusable, error) {
+	ctx, span := tracing.StartSpan(ctx, "federation.DereferenceRemoteStatus", attribute.String("uri", remoteStatusID.String()))
+	defer span.End()
+
+	if blocked, err := f.blockedDomain(ctx, remoteStatusID.Host); blocked || err != nil {
 		return nil, fmt.Errorf("DereferenceRemoteStatus: domain %s is blocked", remoteStatusID.Host)
 	}
 
-	transport, err := f.GetTransportForUser(username)
+	transport, err := f.GetTransportForUser(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("transport err: %s", err)
 	}
 
-	b, err := transport.Dereference(context.Background(), remoteStatusID)
+	b, err := transport.Dereference(ctx, remoteStatusID)
 	if err != nil {
 		return nil, fmt.Errorf("error deferencing %s: %s", remoteStatusID.String(), err)
 	}
@@ -89,7 +98,7 @@ func (f *federator) DereferenceRemoteStatus(username string, remoteStatusID *url
 		return nil, fmt.Errorf("error unmarshalling bytes into json: %s", err)
 	}
 
-	t, err := streams.ToType(context.Background(), m)
+	t, err := streams.ToType(ctx, m)
 	if err != nil {
 		return nil, fmt.Errorf("error resolving json into ap vocab type: %s", err)
 	}
@@ -150,22 +159,31 @@ func (f *federator) DereferenceRemoteStatus(username string, remoteStatusID *url
 			return nil, errors.New("error resolving type as ActivityStreamsProfile")
 		}
 		return p, nil
+	case gtsmodel.ActivityStreamsQuestion:
+		p, ok := t.(vocab.ActivityStreamsQuestion)
+		if !ok {
+			return nil, errors.New("error resolving type as ActivityStreamsQuestion")
+		}
+		return p, nil
 	}
 
 	return nil, fmt.Errorf("type name %s not supported", t.GetTypeName())
 }
 
-func (f *federator) DereferenceRemoteInstance(username string, remoteInstanceURI *url.URL) (*gtsmodel.Instance, error) {
-	if blocked, err := f.blockedDomain(remoteInstanceURI.Host); blocked || err != nil {
+func (f *federator) DereferenceRemoteInstance(ctx context.Context, username string, remoteInstanceURI *url.URL) (*gtsmodel.Instance, error) {
+	ctx, span := tracing.StartSpan(ctx, "federation.DereferenceRemoteInstance", attribute.String("uri", remoteInstanceURI.String()))
+	defer span.End()
+
+	if blocked, err := f.blockedDomain(ctx, remoteInstanceURI.Host); blocked || err != nil {
 		return nil, fmt.Errorf("DereferenceRemoteInstance: domain %s is blocked", remoteInstanceURI.Host)
 	}
 
-	transport, err := f.GetTransportForUser(username)
+	transport, err := f.GetTransportForUser(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("transport err: %s", err)
 	}
 
-	return transport.DereferenceInstance(context.Background(), remoteInstanceURI)
+	return transport.DereferenceInstance(ctx, remoteInstanceURI)
 }
 
 // dereferenceStatusFields fetches all the information we temporarily pinned to an incoming
@@ -191,7 +209,10 @@ func (f *federator) DereferenceRemoteInstance(username string, remoteInstanceURI
 // This function will deference all of the above, insert them in the database as necessary,
 // and attach them to the status. The status itself will not be added to the database yet,
 // that's up the caller to do.
-func (f *federator) DereferenceStatusFields(status *gtsmodel.Status, requestingUsername string) error {
+func (f *federator) DereferenceStatusFields(ctx context.Context, status *gtsmodel.Status, requestingUsername string) error {
+	ctx, span := tracing.StartSpan(ctx, "federation.DereferenceStatusFields", attribute.String("uri", status.URI))
+	defer span.End()
+
 	l := f.log.WithFields(logrus.Fields{
 		"func":   "dereferenceStatusFields",
 		"status": fmt.Sprintf("%+v", status),
@@ -202,11 +223,11 @@ func (f *federator) DereferenceStatusFields(status *gtsmodel.Status, requestingU
 	if err != nil {
 		return fmt.Errorf("DereferenceStatusFields: couldn't parse status URI %s: %s", status.URI, err)
 	}
-	if blocked, err := f.blockedDomain(statusURI.Host); blocked || err != nil {
+	if blocked, err := f.blockedDomain(ctx, statusURI.Host); blocked || err != nil {
 		return fmt.Errorf("DereferenceStatusFields: domain %s is blocked", statusURI.Host)
 	}
 
-	t, err := f.GetTransportForUser(requestingUsername)
+	t, err := f.GetTransportForUser(ctx, requestingUsername)
 	if err != nil {
 		return fmt.Errorf("error creating transport: %s", err)
 	}
@@ -235,7 +256,7 @@ func (f *federator) DereferenceStatusFields(status *gtsmodel.Status, requestingU
 
 		// it might have been processed elsewhere so check first if it's already in the database or not
 		maybeAttachment := &gtsmodel.MediaAttachment{}
-		err := f.db.GetWhere([]db.Where{{Key: "remote_url", Value: a.RemoteURL}}, maybeAttachment)
+		err := f.db.GetWhere(ctx, []db.Where{{Key: "remote_url", Value: a.RemoteURL}}, maybeAttachment)
 		if err == nil {
 			// we already have it in the db, dereferenced, no need to do it again
 			l.Debugf("attachment already exists with id %s", maybeAttachment.ID)
@@ -256,7 +277,7 @@ func (f *federator) DereferenceStatusFields(status *gtsmodel.Status, requestingU
 		l.Debugf("dereferenced attachment: %+v", deferencedAttachment)
 		deferencedAttachment.StatusID = status.ID
 		deferencedAttachment.Description = a.Description
-		if err := f.db.Put(deferencedAttachment); err != nil {
+		if err := f.db.Put(ctx, deferencedAttachment); err != nil {
 			return fmt.Errorf("error inserting dereferenced attachment with remote url %s: %s", a.RemoteURL, err)
 		}
 		attachmentIDs = append(attachmentIDs, deferencedAttachment.ID)
@@ -264,6 +285,32 @@ func (f *federator) DereferenceStatusFields(status *gtsmodel.Status, requestingU
 	status.Attachments = attachmentIDs
 
 	// 2. Hashtags
+	// At this point, tags should have their name set on them, without the leading #.
+	//
+	// We want to use our own tag for each name, creating it if we haven't seen the tag before,
+	// so that remote statuses show up in the tag timelines of this instance.
+	tagNames := []string{}
+	seenTagNames := map[string]bool{}
+	for _, t := range status.GTSTags {
+		if seenTagNames[strings.ToLower(t.Name)] {
+			continue
+		}
+		seenTagNames[strings.ToLower(t.Name)] = true
+		tagNames = append(tagNames, t.Name)
+	}
+	tags, err := f.db.TagStringsToTags(ctx, tagNames, status.AccountID, status.ID)
+	if err != nil {
+		return fmt.Errorf("error getting tags for status: %s", err)
+	}
+	tagIDs := []string{}
+	for _, t := range tags {
+		if err := f.db.Upsert(ctx, t, "name"); err != nil {
+			return fmt.Errorf("error putting tag %s in db: %s", t.Name, err)
+		}
+		tagIDs = append(tagIDs, t.ID)
+	}
+	status.GTSTags = tags
+	status.Tags = tagIDs
 
 	// 3. Emojis
 
@@ -292,21 +339,21 @@ func (f *federator) DereferenceStatusFields(status *gtsmodel.Status, requestingU
 		m.OriginAccountURI = status.GTSAuthorAccount.URI
 
 		targetAccount := &gtsmodel.Account{}
-		if err := f.db.GetWhere([]db.Where{{Key: "uri", Value: uri.String()}}, targetAccount); err != nil {
+		if err := f.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: uri.String()}}, targetAccount); err != nil {
 			// proper error
 			if _, ok := err.(db.ErrNoEntries); !ok {
 				return fmt.Errorf("db error checking for account with uri %s", uri.String())
 			}
 
 			// we just don't have it yet, so we should go get it....
-			accountable, err := f.DereferenceRemoteAccount(requestingUsername, uri)
+			accountable, err := f.DereferenceRemoteAccount(ctx, requestingUsername, uri)
 			if err != nil {
 				// we can't dereference it so just skip it
 				l.Debugf("error dereferencing remote account with uri %s: %s", uri.String(), err)
 				continue
 			}
 
-			targetAccount, err = f.typeConverter.ASRepresentationToAccount(accountable, false)
+			targetAccount, err = f.typeConverter.ASRepresentationToAccount(ctx, accountable, false)
 			if err != nil {
 				l.Debugf("error converting remote account with uri %s into gts model: %s", uri.String(), err)
 				continue
@@ -318,24 +365,52 @@ func (f *federator) DereferenceStatusFields(status *gtsmodel.Status, requestingU
 			}
 			targetAccount.ID = targetAccountID
 
-			if err := f.db.Put(targetAccount); err != nil {
+			if err := f.db.Put(ctx, targetAccount); err != nil {
 				return fmt.Errorf("db error inserting account with uri %s", uri.String())
 			}
 		}
 
 		// by this point, we know the targetAccount exists in our database with an ID :)
 		m.TargetAccountID = targetAccount.ID
-		if err := f.db.Put(m); err != nil {
+		if err := f.db.Put(ctx, m); err != nil {
 			return fmt.Errorf("error creating mention: %s", err)
 		}
 		mentions = append(mentions, m.ID)
 	}
 	status.Mentions = mentions
 
+	// 5. Poll
+	// At this point, the poll should have its options and tallies set on it, but no ids.
+	//
+	// The status might have been dereferenced before, in which case we already have its poll.
+	if status.GTSPoll != nil {
+		maybePoll := &gtsmodel.Poll{}
+		err := f.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: status.ID}}, maybePoll)
+		if err == nil {
+			status.GTSPoll = maybePoll
+		} else if _, ok := err.(db.ErrNoEntries); ok {
+			pollID, err := id.NewULID()
+			if err != nil {
+				return err
+			}
+			status.GTSPoll.ID = pollID
+			status.GTSPoll.StatusID = status.ID
+			status.GTSPoll.AccountID = status.AccountID
+			if err := f.db.Put(ctx, status.GTSPoll); err != nil {
+				return fmt.Errorf("error creating poll: %s", err)
+			}
+		} else {
+			return fmt.Errorf("error checking db for existence of poll for status %s: %s", status.ID, err)
+		}
+	}
+
 	return nil
 }
 
-func (f *federator) DereferenceAccountFields(account *gtsmodel.Account, requestingUsername string, refresh bool) error {
+func (f *federator) DereferenceAccountFields(ctx context.Context, account *gtsmodel.Account, requestingUsername string, refresh bool) error {
+	ctx, span := tracing.StartSpan(ctx, "federation.DereferenceAccountFields", attribute.String("uri", account.URI))
+	defer span.End()
+
 	l := f.log.WithFields(logrus.Fields{
 		"func":               "dereferenceAccountFields",
 		"requestingUsername": requestingUsername,
@@ -345,29 +420,40 @@ func (f *federator) DereferenceAccountFields(account *gtsmodel.Account, requesti
 	if err != nil {
 		return fmt.Errorf("DereferenceAccountFields: couldn't parse account URI %s: %s", account.URI, err)
 	}
-	if blocked, err := f.blockedDomain(accountURI.Host); blocked || err != nil {
+	if blocked, err := f.blockedDomain(ctx, accountURI.Host); blocked || err != nil {
 		return fmt.Errorf("DereferenceAccountFields: domain %s is blocked", accountURI.Host)
 	}
 
-	t, err := f.GetTransportForUser(requestingUsername)
+	t, err := f.GetTransportForUser(ctx, requestingUsername)
 	if err != nil {
 		return fmt.Errorf("error getting transport for user: %s", err)
 	}
 
 	// fetch the header and avatar
-	if err := f.fetchHeaderAndAviForAccount(account, t, refresh); err != nil {
+	if err := f.fetchHeaderAndAviForAccount(ctx, account, t, refresh); err != nil {
 		// if this doesn't work, just skip it -- we can do it later
 		l.Debugf("error fetching header/avi for account: %s", err)
 	}
 
-	if err := f.db.UpdateByID(account.ID, account); err != nil {
+	// pins of remote accounts are only known to us through their featured collection, so refresh that along with the rest
+	if refresh && account.Domain != "" {
+		if err := f.DereferenceFeaturedCollection(ctx, account, requestingUsername); err != nil {
+			// if this doesn't work, just skip it -- we can do it later
+			l.Debugf("error dereferencing featured collection for account: %s", err)
+		}
+	}
+
+	if err := f.db.UpdateByID(ctx, account.ID, account); err != nil {
 		return fmt.Errorf("error updating account in database: %s", err)
 	}
 
 	return nil
 }
 
-func (f *federator) DereferenceAnnounce(announce *gtsmodel.Status, requestingUsername string) error {
+func (f *federator) DereferenceAnnounce(ctx context.Context, announce *gtsmodel.Status, requestingUsername string) error {
+	ctx, span := tracing.StartSpan(ctx, "federation.DereferenceAnnounce", attribute.String("uri", announce.URI))
+	defer span.End()
+
 	if announce.GTSBoostedStatus == nil || announce.GTSBoostedStatus.URI == "" {
 		// we can't do anything unfortunately
 		return errors.New("DereferenceAnnounce: no URI to dereference")
@@ -377,13 +463,13 @@ func (f *federator) DereferenceAnnounce(announce *gtsmodel.Status, requestingUse
 	if err != nil {
 		return fmt.Errorf("DereferenceAnnounce: couldn't parse boosted status URI %s: %s", announce.GTSBoostedStatus.URI, err)
 	}
-	if blocked, err := f.blockedDomain(boostedStatusURI.Host); blocked || err != nil {
+	if blocked, err := f.blockedDomain(ctx, boostedStatusURI.Host); blocked || err != nil {
 		return fmt.Errorf("DereferenceAnnounce: domain %s is blocked", boostedStatusURI.Host)
 	}
 
 	// check if we already have the boosted status in the database
 	boostedStatus := &gtsmodel.Status{}
-	err = f.db.GetWhere([]db.Where{{Key: "uri", Value: announce.GTSBoostedStatus.URI}}, boostedStatus)
+	err = f.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: announce.GTSBoostedStatus.URI}}, boostedStatus)
 	if err == nil {
 		// nice, we already have it so we don't actually need to dereference it from remote
 		announce.Content = boostedStatus.Content
@@ -401,7 +487,7 @@ func (f *federator) DereferenceAnnounce(announce *gtsmodel.Status, requestingUse
 	}
 
 	// we don't have it so we need to dereference it
-	statusable, err := f.DereferenceRemoteStatus(requestingUsername, boostedStatusURI)
+	statusable, err := f.DereferenceRemoteStatus(ctx, requestingUsername, boostedStatusURI)
 	if err != nil {
 		return fmt.Errorf("dereferenceAnnounce: error dereferencing remote status with id %s: %s", announce.GTSBoostedStatus.URI, err)
 	}
@@ -414,17 +500,17 @@ func (f *federator) DereferenceAnnounce(announce *gtsmodel.Status, requestingUse
 			continue
 		}
 
-		if err := f.db.GetWhere([]db.Where{{Key: "uri", Value: accountURI.String()}}, &gtsmodel.Account{}); err == nil {
+		if err := f.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: accountURI.String()}}, &gtsmodel.Account{}); err == nil {
 			// we already have it, fine
 			continue
 		}
 
 		// we don't have the boosted status author account yet so dereference it
-		accountable, err := f.DereferenceRemoteAccount(requestingUsername, accountURI)
+		accountable, err := f.DereferenceRemoteAccount(ctx, requestingUsername, accountURI)
 		if err != nil {
 			return fmt.Errorf("dereferenceAnnounce: error dereferencing remote account with id %s: %s", accountURI.String(), err)
 		}
-		account, err := f.typeConverter.ASRepresentationToAccount(accountable, false)
+		account, err := f.typeConverter.ASRepresentationToAccount(ctx, accountable, false)
 		if err != nil {
 			return fmt.Errorf("dereferenceAnnounce: error converting dereferenced account with id %s into account : %s", accountURI.String(), err)
 		}
@@ -435,17 +521,17 @@ func (f *federator) DereferenceAnnounce(announce *gtsmodel.Status, requestingUse
 		}
 		account.ID = accountID
 
-		if err := f.db.Put(account); err != nil {
+		if err := f.db.Put(ctx, account); err != nil {
 			return fmt.Errorf("dereferenceAnnounce: error putting dereferenced account with id %s into database : %s", accountURI.String(), err)
 		}
 
-		if err := f.DereferenceAccountFields(account, requestingUsername, false); err != nil {
+		if err := f.DereferenceAccountFields(ctx, account, requestingUsername, false); err != nil {
 			return fmt.Errorf("dereferenceAnnounce: error dereferencing fields on account with id %s : %s", accountURI.String(), err)
 		}
 	}
 
 	// now convert the statusable into something we can understand
-	boostedStatus, err = f.typeConverter.ASStatusToStatus(statusable)
+	boostedStatus, err = f.typeConverter.ASStatusToStatus(ctx, statusable)
 	if err != nil {
 		return fmt.Errorf("dereferenceAnnounce: error converting dereferenced statusable with id %s into status : %s", announce.GTSBoostedStatus.URI, err)
 	}
@@ -456,17 +542,17 @@ func (f *federator) DereferenceAnnounce(announce *gtsmodel.Status, requestingUse
 	}
 	boostedStatus.ID = boostedStatusID
 
-	if err := f.db.Put(boostedStatus); err != nil {
+	if err := f.db.Put(ctx, boostedStatus); err != nil {
 		return fmt.Errorf("dereferenceAnnounce: error putting dereferenced status with id %s into the db: %s", announce.GTSBoostedStatus.URI, err)
 	}
 
 	// now dereference additional fields straight away (we're already async here so we have time)
-	if err := f.DereferenceStatusFields(boostedStatus, requestingUsername); err != nil {
+	if err := f.DereferenceStatusFields(ctx, boostedStatus, requestingUsername); err != nil {
 		return fmt.Errorf("dereferenceAnnounce: error dereferencing status fields for status with id %s: %s", announce.GTSBoostedStatus.URI, err)
 	}
 
 	// update with the newly dereferenced fields
-	if err := f.db.UpdateByID(boostedStatus.ID, boostedStatus); err != nil {
+	if err := f.db.UpdateByID(ctx, boostedStatus.ID, boostedStatus); err != nil {
 		return fmt.Errorf("dereferenceAnnounce: error updating dereferenced status in the db: %s", err)
 	}
 
@@ -492,17 +578,17 @@ func (f *federator) DereferenceAnnounce(announce *gtsmodel.Status, requestingUse
 //
 // SIDE EFFECTS: remote header and avatar will be stored in local storage, and the database will be updated
 // to reflect the creation of these new attachments.
-func (f *federator) fetchHeaderAndAviForAccount(targetAccount *gtsmodel.Account, t transport.Transport, refresh bool) error {
+func (f *federator) fetchHeaderAndAviForAccount(ctx context.Context, targetAccount *gtsmodel.Account, t transport.Transport, refresh bool) error {
 	accountURI, err := url.Parse(targetAccount.URI)
 	if err != nil {
 		return fmt.Errorf("fetchHeaderAndAviForAccount: couldn't parse account URI %s: %s", targetAccount.URI, err)
 	}
-	if blocked, err := f.blockedDomain(accountURI.Host); blocked || err != nil {
+	if blocked, err := f.blockedDomain(ctx, accountURI.Host); blocked || err != nil {
 		return fmt.Errorf("fetchHeaderAndAviForAccount: domain %s is blocked", accountURI.Host)
 	}
 
 	if targetAccount.AvatarRemoteURL != "" && (targetAccount.AvatarMediaAttachmentID == "" || refresh) {
-		a, err := f.mediaHandler.ProcessRemoteHeaderOrAvatar(t, &gtsmodel.MediaAttachment{
+		a, err := f.mediaHandler.ProcessRemoteHeaderOrAvatar(ctx, t, &gtsmodel.MediaAttachment{
 			RemoteURL: targetAccount.AvatarRemoteURL,
 			Avatar:    true,
 		}, targetAccount.ID)
@@ -513,7 +599,7 @@ func (f *federator) fetchHeaderAndAviForAccount(targetAccount *gtsmodel.Account,
 	}
 
 	if targetAccount.HeaderRemoteURL != "" && (targetAccount.HeaderMediaAttachmentID == "" || refresh) {
-		a, err := f.mediaHandler.ProcessRemoteHeaderOrAvatar(t, &gtsmodel.MediaAttachment{
+		a, err := f.mediaHandler.ProcessRemoteHeaderOrAvatar(ctx, t, &gtsmodel.MediaAttachment{
 			RemoteURL: targetAccount.HeaderRemoteURL,
 			Header:    true,
 		}, targetAccount.ID)
@@ -524,3 +610,184 @@ func (f *federator) fetchHeaderAndAviForAccount(targetAccount *gtsmodel.Account,
 	}
 	return nil
 }
+
+func (f *federator) DereferenceFeaturedCollection(ctx context.Context, account *gtsmodel.Account, requestingUsername string) error {
+	ctx, span := tracing.StartSpan(ctx, "federation.DereferenceFeaturedCollection", attribute.String("uri", account.FeaturedCollectionURI))
+	defer span.End()
+
+	if account.FeaturedCollectionURI == "" {
+		// nothing to do
+		return nil
+	}
+
+	featuredURI, err := url.Parse(account.FeaturedCollectionURI)
+	if err != nil {
+		return fmt.Errorf("DereferenceFeaturedCollection: couldn't parse featured URI %s: %s", account.FeaturedCollectionURI, err)
+	}
+	blocked, err := f.blockedDomain(ctx, featuredURI.Host)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return fmt.Errorf("DereferenceFeaturedCollection: domain %s is blocked", featuredURI.Host)
+	}
+
+	transport, err := f.GetTransportForUser(ctx, requestingUsername)
+	if err != nil {
+		return fmt.Errorf("transport err: %s", err)
+	}
+
+	b, err := transport.Dereference(ctx, featuredURI)
+	if err != nil {
+		return fmt.Errorf("error deferencing %s: %s", featuredURI.String(), err)
+	}
+
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("error unmarshalling bytes into json: %s", err)
+	}
+
+	t, err := streams.ToType(ctx, m)
+	if err != nil {
+		return fmt.Errorf("error resolving json into ap vocab type: %s", err)
+	}
+
+	// the items of the collection can either be IRIs or embedded statuses
+	items := []featuredItem{}
+	switch t.GetTypeName() {
+	case gtsmodel.ActivityStreamsOrderedCollection:
+		c, ok := t.(vocab.ActivityStreamsOrderedCollection)
+		if !ok {
+			return errors.New("error resolving type as ActivityStreamsOrderedCollection")
+		}
+		if prop := c.GetActivityStreamsOrderedItems(); prop != nil {
+			for iter := prop.Begin(); iter != prop.End(); iter = iter.Next() {
+				items = append(items, featuredItem{iri: iter.GetIRI(), t: iter.GetType()})
+			}
+		}
+	case gtsmodel.ActivityStreamsCollection:
+		c, ok := t.(vocab.ActivityStreamsCollection)
+		if !ok {
+			return errors.New("error resolving type as ActivityStreamsCollection")
+		}
+		if prop := c.GetActivityStreamsItems(); prop != nil {
+			for iter := prop.Begin(); iter != prop.End(); iter = iter.Next() {
+				items = append(items, featuredItem{iri: iter.GetIRI(), t: iter.GetType()})
+			}
+		}
+	default:
+		return fmt.Errorf("type name %s not supported as featured collection", t.GetTypeName())
+	}
+
+	pinnedIDs := make(map[string]bool, len(items))
+	for _, item := range items {
+		status, err := f.featuredItemToStatus(ctx, account, item, requestingUsername)
+		if err != nil {
+			f.log.WithField("func", "DereferenceFeaturedCollection").Debugf("skipping featured item: %s", err)
+			continue
+		}
+		pinnedIDs[status.ID] = true
+
+		if !status.Pinned {
+			if err := f.db.UpdateOneByID(ctx, status.ID, "pinned", true, &gtsmodel.Status{}); err != nil {
+				return fmt.Errorf("DereferenceFeaturedCollection: error pinning status %s: %s", status.ID, err)
+			}
+		}
+	}
+
+	// anything that was pinned before but isn't in the collection anymore has been unpinned
+	previouslyPinned, err := f.db.GetStatusesForAccount(ctx, account.ID, 0, false, "", true, false)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return fmt.Errorf("DereferenceFeaturedCollection: error getting pinned statuses for account %s: %s", account.ID, err)
+		}
+	}
+
+	for _, s := range previouslyPinned {
+		if pinnedIDs[s.ID] {
+			continue
+		}
+		if err := f.db.UpdateOneByID(ctx, s.ID, "pinned", false, &gtsmodel.Status{}); err != nil {
+			return fmt.Errorf("DereferenceFeaturedCollection: error unpinning status %s: %s", s.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// featuredItem is one entry of a featured collection, which will have either iri or t set.
+type featuredItem struct {
+	iri *url.URL
+	t   vocab.Type
+}
+
+// featuredItemToStatus gets the status referred to by the given featured item out of the database,
+// dereferencing and storing it first if we don't have it yet. Statuses that don't belong to account are rejected.
+func (f *federator) featuredItemToStatus(ctx context.Context, account *gtsmodel.Account, item featuredItem, requestingUsername string) (*gtsmodel.Status, error) {
+	statusURI := item.iri
+	var statusable typeutils.Statusable
+	if statusURI == nil {
+		if item.t == nil {
+			return nil, errors.New("item had neither an iri nor a type")
+		}
+		s, ok := item.t.(typeutils.Statusable)
+		if !ok {
+			return nil, fmt.Errorf("type name %s not supported as a featured status", item.t.GetTypeName())
+		}
+		idProp := s.GetJSONLDId()
+		if idProp == nil || !idProp.IsIRI() {
+			return nil, errors.New("embedded status had no id")
+		}
+		statusURI = idProp.GetIRI()
+		statusable = s
+	}
+
+	status := &gtsmodel.Status{}
+	err := f.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: statusURI.String()}}, status)
+	if err == nil {
+		if status.AccountID != account.ID {
+			return nil, fmt.Errorf("status %s does not belong to account %s", status.URI, account.URI)
+		}
+		return status, nil
+	}
+	if _, ok := err.(db.ErrNoEntries); !ok {
+		return nil, fmt.Errorf("error checking db for status %s: %s", statusURI.String(), err)
+	}
+
+	// we don't have it so we need to dereference it
+	if statusable == nil {
+		statusable, err = f.DereferenceRemoteStatus(ctx, requestingUsername, statusURI)
+		if err != nil {
+			return nil, fmt.Errorf("error dereferencing remote status with id %s: %s", statusURI.String(), err)
+		}
+	}
+
+	status, err = f.typeConverter.ASStatusToStatus(ctx, statusable)
+	if err != nil {
+		return nil, fmt.Errorf("error converting dereferenced statusable with id %s into status: %s", statusURI.String(), err)
+	}
+
+	if status.AccountID != account.ID {
+		return nil, fmt.Errorf("status %s does not belong to account %s", status.URI, account.URI)
+	}
+
+	statusID, err := id.NewULIDFromTime(status.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	status.ID = statusID
+
+	if err := f.db.Put(ctx, status); err != nil {
+		return nil, fmt.Errorf("error putting dereferenced status with id %s into the db: %s", status.URI, err)
+	}
+
+	if err := f.DereferenceStatusFields(ctx, status, requestingUsername); err != nil {
+		return nil, fmt.Errorf("error dereferencing status fields for status with id %s: %s", status.URI, err)
+	}
+
+	if err := f.db.UpdateByID(ctx, status.ID, status); err != nil {
+		return nil, fmt.Errorf("error updating dereferenced status with id %s in the db: %s", status.URI, err)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,143 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federation_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type DereferenceTestSuite struct {
+	suite.Suite
+	db       db.DB
+	accounts map[string]*gtsmodel.Account
+	tags     map[string]*gtsmodel.Tag
+}
+
+func (suite *DereferenceTestSuite) SetupTest() {
+	suite.db = testrig.NewTestDB()
+	suite.accounts = testrig.NewTestAccounts()
+	suite.tags = testrig.NewTestTags()
+	testrig.StandardDBSetup(suite.db)
+}
+
+func (suite *DereferenceTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+// hashtags on incoming statuses should be swapped for our own tags, so that remote statuses end up in tag timelines
+func (suite *DereferenceTestSuite) TestDereferenceStatusTags() {
+	ctx := context.Background()
+	federator := testrig.NewTestFederator(suite.db, testrig.NewTestTransportController(testrig.NewMockHTTPClient(nil)), testrig.NewTestStorage())
+	author := suite.accounts["remote_account_1"]
+
+	status := &gtsmodel.Status{
+		ID:               "01FFMG8WBN4QQ3N9W6CNDMNBVW",
+		URI:              "http://fossbros-anonymous.io/users/foss_satan/statuses/01FFMG8WBN4QQ3N9W6CNDMNBVW",
+		CreatedAt:        time.Now(),
+		AccountID:        author.ID,
+		GTSAuthorAccount: author,
+		Visibility:       gtsmodel.VisibilityPublic,
+		GTSTags: []*gtsmodel.Tag{
+			{Name: "Welcome", URL: "http://fossbros-anonymous.io/tags/Welcome"},
+			{Name: "gardening", URL: "http://fossbros-anonymous.io/tags/gardening"},
+			{Name: "GARDENING", URL: "http://fossbros-anonymous.io/tags/GARDENING"},
+		},
+	}
+
+	suite.NoError(federator.DereferenceStatusFields(ctx, status, "the_mighty_zork"))
+
+	// the existing tag is reused, and the new one is only created once
+	suite.Len(status.Tags, 2)
+	suite.Equal(suite.tags["welcome"].ID, status.Tags[0])
+
+	gardening := &gtsmodel.Tag{}
+	suite.NoError(suite.db.GetByID(ctx, status.Tags[1], gardening))
+	suite.Equal("gardening", gardening.Name)
+	suite.Equal("http://localhost:8080/tags/gardening", gardening.URL)
+	suite.Equal(author.ID, gardening.FirstSeenFromAccountID)
+}
+
+// the pins of a remote account should be brought up to date when its fields are refreshed, but not otherwise
+func (suite *DereferenceTestSuite) TestDereferenceAccountFieldsRefreshesPins() {
+	ctx := context.Background()
+	account := suite.accounts["remote_account_1"]
+
+	status := &gtsmodel.Status{
+		ID:         "01FFMG8WBN4QQ3N9W6CNDMNBVW",
+		URI:        "http://fossbros-anonymous.io/users/foss_satan/statuses/01FFMG8WBN4QQ3N9W6CNDMNBVW",
+		CreatedAt:  time.Now(),
+		AccountID:  account.ID,
+		Visibility: gtsmodel.VisibilityPublic,
+	}
+	suite.NoError(suite.db.Put(ctx, status))
+
+	featured := fmt.Sprintf(`{"@context":"https://www.w3.org/ns/activitystreams","id":"%s","type":"OrderedCollection","totalItems":1,"orderedItems":["%s"]}`, account.FeaturedCollectionURI, status.URI)
+	httpClient := testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() != account.FeaturedCollectionURI {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(featured))),
+		}, nil
+	})
+	federator := testrig.NewTestFederator(suite.db, testrig.NewTestTransportController(httpClient), testrig.NewTestStorage())
+
+	suite.NoError(federator.DereferenceAccountFields(ctx, account, "the_mighty_zork", false))
+	got := &gtsmodel.Status{}
+	suite.NoError(suite.db.GetByID(ctx, status.ID, got))
+	suite.False(got.Pinned)
+
+	suite.NoError(federator.DereferenceAccountFields(ctx, account, "the_mighty_zork", true))
+	suite.NoError(suite.db.GetByID(ctx, status.ID, got))
+	suite.True(got.Pinned)
+}
+
+func (suite *DereferenceTestSuite) TestDereferenceFeaturedCollectionBlocked() {
+	ctx := context.Background()
+	federator := testrig.NewTestFederator(suite.db, testrig.NewTestTransportController(testrig.NewMockHTTPClient(nil)), testrig.NewTestStorage())
+
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.DomainBlock{
+		ID:                 "01FFMGB7R0FYHJ3MH8RTJ9JT70",
+		Domain:             "fossbros-anonymous.io",
+		CreatedByAccountID: suite.accounts["admin_account"].ID,
+	}))
+
+	err := federator.DereferenceFeaturedCollection(ctx, suite.accounts["remote_account_1"], "the_mighty_zork")
+	suite.EqualError(err, "DereferenceFeaturedCollection: domain fossbros-anonymous.io is blocked")
+}
+
+func TestDereferenceTestSuite(t *testing.T) {
+	suite.Run(t, new(DereferenceTestSuite))
+}
@@ -51,7 +51,7 @@ func (f *federatingDB) Announce(ctx context.Context, announce vocab.ActivityStre
 		return nil
 	}
 
-	boost, isNew, err := f.typeConverter.ASAnnounceToStatus(announce)
+	boost, isNew, err := f.typeConverter.ASAnnounceToStatus(ctx, announce)
 	if err != nil {
 		return fmt.Errorf("Announce: error converting announce to boost: %s", err)
 	}
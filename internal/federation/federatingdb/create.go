@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-fed/activity/streams"
 	"github.com/go-fed/activity/streams/vocab"
@@ -30,6 +31,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
@@ -98,33 +100,21 @@ func (f *federatingDB) Create(ctx context.Context, asType vocab.Type) error {
 			case gtsmodel.ActivityStreamsNote:
 				// CREATE A NOTE
 				note := objectIter.GetActivityStreamsNote()
-				status, err := f.typeConverter.ASStatusToStatus(note)
-				if err != nil {
-					return fmt.Errorf("error converting note to status: %s", err)
+				if isPollVote(note) {
+					// it's not really a status, but a vote in a poll
+					if err := f.createPollVote(ctx, note, targetAcct, fromFederatorChan); err != nil {
+						return err
+					}
+					continue
 				}
-
-				// id the status based on the time it was created
-				statusID, err := id.NewULIDFromTime(status.CreatedAt)
-				if err != nil {
+				if err := f.createStatus(ctx, note, targetAcct, fromFederatorChan); err != nil {
 					return err
 				}
-				status.ID = statusID
-
-				if err := f.db.Put(status); err != nil {
-					if _, ok := err.(db.ErrAlreadyExists); ok {
-						// the status already exists in the database, which means we've already handled everything else,
-						// so we can just return nil here and be done with it.
-						return nil
-					}
-					// an actual error has happened
-					return fmt.Errorf("database error inserting status: %s", err)
-				}
-
-				fromFederatorChan <- gtsmodel.FromFederator{
-					APObjectType:     gtsmodel.ActivityStreamsNote,
-					APActivityType:   gtsmodel.ActivityStreamsCreate,
-					GTSModel:         status,
-					ReceivingAccount: targetAcct,
+			case gtsmodel.ActivityStreamsQuestion:
+				// CREATE A QUESTION aka a status with a poll
+				question := objectIter.GetActivityStreamsQuestion()
+				if err := f.createStatus(ctx, question, targetAcct, fromFederatorChan); err != nil {
+					return err
 				}
 			}
 		}
@@ -135,7 +125,7 @@ func (f *federatingDB) Create(ctx context.Context, asType vocab.Type) error {
 			return errors.New("could not convert type to follow")
 		}
 
-		followRequest, err := f.typeConverter.ASFollowToFollowRequest(follow)
+		followRequest, err := f.typeConverter.ASFollowToFollowRequest(ctx, follow)
 		if err != nil {
 			return fmt.Errorf("could not convert Follow to follow request: %s", err)
 		}
@@ -146,7 +136,7 @@ func (f *federatingDB) Create(ctx context.Context, asType vocab.Type) error {
 		}
 		followRequest.ID = newID
 
-		if err := f.db.Put(followRequest); err != nil {
+		if err := f.db.Put(ctx, followRequest); err != nil {
 			return fmt.Errorf("database error inserting follow request: %s", err)
 		}
 
@@ -163,7 +153,7 @@ func (f *federatingDB) Create(ctx context.Context, asType vocab.Type) error {
 			return errors.New("could not convert type to like")
 		}
 
-		fave, err := f.typeConverter.ASLikeToFave(like)
+		fave, err := f.typeConverter.ASLikeToFave(ctx, like)
 		if err != nil {
 			return fmt.Errorf("could not convert Like to fave: %s", err)
 		}
@@ -174,7 +164,7 @@ func (f *federatingDB) Create(ctx context.Context, asType vocab.Type) error {
 		}
 		fave.ID = newID
 
-		if err := f.db.Put(fave); err != nil {
+		if err := f.db.Put(ctx, fave); err != nil {
 			return fmt.Errorf("database error inserting fave: %s", err)
 		}
 
@@ -191,7 +181,7 @@ func (f *federatingDB) Create(ctx context.Context, asType vocab.Type) error {
 			return errors.New("could not convert type to block")
 		}
 
-		block, err := f.typeConverter.ASBlockToBlock(blockable)
+		block, err := f.typeConverter.ASBlockToBlock(ctx, blockable)
 		if err != nil {
 			return fmt.Errorf("could not convert Block to gts model block")
 		}
@@ -202,7 +192,7 @@ func (f *federatingDB) Create(ctx context.Context, asType vocab.Type) error {
 		}
 		block.ID = newID
 
-		if err := f.db.Put(block); err != nil {
+		if err := f.db.Put(ctx, block); err != nil {
 			return fmt.Errorf("database error inserting block: %s", err)
 		}
 
@@ -215,3 +205,99 @@ func (f *federatingDB) Create(ctx context.Context, asType vocab.Type) error {
 	}
 	return nil
 }
+
+// createStatus puts the given statusable in the database as a new status, and passes it on to the processor.
+func (f *federatingDB) createStatus(ctx context.Context, statusable typeutils.Statusable, targetAcct *gtsmodel.Account, fromFederatorChan chan gtsmodel.FromFederator) error {
+	status, err := f.typeConverter.ASStatusToStatus(ctx, statusable)
+	if err != nil {
+		return fmt.Errorf("error converting note to status: %s", err)
+	}
+
+	// id the status based on the time it was created
+	statusID, err := id.NewULIDFromTime(status.CreatedAt)
+	if err != nil {
+		return err
+	}
+	status.ID = statusID
+
+	if err := f.db.Put(ctx, status); err != nil {
+		if _, ok := err.(db.ErrAlreadyExists); ok {
+			// the status already exists in the database, which means we've already handled everything else,
+			// so we can just return nil here and be done with it.
+			return nil
+		}
+		// an actual error has happened
+		return fmt.Errorf("database error inserting status: %s", err)
+	}
+
+	fromFederatorChan <- gtsmodel.FromFederator{
+		APObjectType:     gtsmodel.ActivityStreamsNote,
+		APActivityType:   gtsmodel.ActivityStreamsCreate,
+		GTSModel:         status,
+		ReceivingAccount: targetAcct,
+	}
+	return nil
+}
+
+// isPollVote returns true if the given note looks like a vote in a poll rather than a status:
+// votes have the title of the chosen option as their name, and no content.
+func isPollVote(note vocab.ActivityStreamsNote) bool {
+	nameProp := note.GetActivityStreamsName()
+	if nameProp == nil || nameProp.Len() == 0 {
+		return false
+	}
+	if inReplyToProp := note.GetActivityStreamsInReplyTo(); inReplyToProp == nil || inReplyToProp.Len() == 0 {
+		return false
+	}
+	contentProp := note.GetActivityStreamsContent()
+	return contentProp == nil || contentProp.Len() == 0
+}
+
+// createPollVote puts a vote made by a remote account in a local poll in the database, and passes it on to the processor.
+//
+// Votes in polls that have closed, or that would be a second vote by the same account in a single choice poll, are ignored.
+func (f *federatingDB) createPollVote(ctx context.Context, note vocab.ActivityStreamsNote, targetAcct *gtsmodel.Account, fromFederatorChan chan gtsmodel.FromFederator) error {
+	l := f.log.WithField("func", "createPollVote")
+
+	vote, poll, err := f.typeConverter.ASVoteToPollVote(ctx, note)
+	if err != nil {
+		// this isn't a vote we can do anything with, so there's no point in the sender trying again
+		l.Debugf("ignoring note that couldn't be converted to a poll vote: %s", err)
+		return nil
+	}
+
+	if poll.Expired(time.Now()) {
+		l.Debugf("ignoring vote %s in poll %s because the poll has closed", vote.URI, poll.ID)
+		return nil
+	}
+
+	if !poll.Multiple {
+		if err := f.db.GetWhere(ctx, []db.Where{{Key: "poll_id", Value: poll.ID}, {Key: "account_id", Value: vote.AccountID}}, &gtsmodel.PollVote{}); err == nil {
+			l.Debugf("ignoring vote %s in poll %s because account %s already voted", vote.URI, poll.ID, vote.AccountID)
+			return nil
+		} else if _, ok := err.(db.ErrNoEntries); !ok {
+			return fmt.Errorf("database error checking for existing votes: %s", err)
+		}
+	}
+
+	newID, err := id.NewULID()
+	if err != nil {
+		return err
+	}
+	vote.ID = newID
+
+	if err := f.db.Put(ctx, vote); err != nil {
+		if _, ok := err.(db.ErrAlreadyExists); ok {
+			return nil
+		}
+		return fmt.Errorf("database error inserting poll vote: %s", err)
+	}
+
+	fromFederatorChan <- gtsmodel.FromFederator{
+		APObjectType:     gtsmodel.ActivityStreamsNote,
+		APActivityType:   gtsmodel.ActivityStreamsCreate,
+		GTSModel:         vote,
+		ReceivingAccount: targetAcct,
+	}
+	return nil
+}
@@ -36,6 +36,7 @@ type DB interface {
 	Undo(ctx context.Context, undo vocab.ActivityStreamsUndo) error
 	Accept(ctx context.Context, accept vocab.ActivityStreamsAccept) error
 	Announce(ctx context.Context, announce vocab.ActivityStreamsAnnounce) error
+	Flag(ctx context.Context, flag vocab.ActivityStreamsFlag) error
 }
 
 // FederatingDB uses the underlying DB interface to implement the go-fed pub.Database interface.
@@ -55,6 +56,6 @@ func New(db db.DB, config *config.Config, log *logrus.Logger) DB {
 		db:            db,
 		config:        config,
 		log:           log,
-		typeConverter: typeutils.NewConverter(config, db),
+		typeConverter: typeutils.NewConverter(config, db, nil),
 	}
 }
@@ -53,10 +53,10 @@ func (f *federatingDB) Delete(ctx context.Context, id *url.URL) error {
 	where := []db.Where{{Key: "uri", Value: id.String()}}
 
 	s := &gtsmodel.Status{}
-	if err := f.db.GetWhere(where, s); err == nil {
+	if err := f.db.GetWhere(ctx, where, s); err == nil {
 		// it's a status
 		l.Debugf("uri is for status with id: %s", s.ID)
-		if err := f.db.DeleteByID(s.ID, &gtsmodel.Status{}); err != nil {
+		if err := f.db.DeleteByID(ctx, s.ID, &gtsmodel.Status{}); err != nil {
 			return fmt.Errorf("Delete: err deleting status: %s", err)
 		}
 		fromFederatorChan <- gtsmodel.FromFederator{
@@ -68,10 +68,10 @@ func (f *federatingDB) Delete(ctx context.Context, id *url.URL) error {
 	}
 
 	a := &gtsmodel.Account{}
-	if err := f.db.GetWhere(where, a); err == nil {
+	if err := f.db.GetWhere(ctx, where, a); err == nil {
 		// it's an account
 		l.Debugf("uri is for an account with id: %s", s.ID)
-		if err := f.db.DeleteByID(a.ID, &gtsmodel.Account{}); err != nil {
+		if err := f.db.DeleteByID(ctx, a.ID, &gtsmodel.Account{}); err != nil {
 			return fmt.Errorf("Delete: err deleting account: %s", err)
 		}
 		fromFederatorChan <- gtsmodel.FromFederator{
@@ -0,0 +1,61 @@
+package federatingdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-fed/activity/streams"
+	"github.com/go-fed/activity/streams/vocab"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+// Flag stores a report of a local account that was made on a remote instance, so that it shows up in the moderation queue.
+func (f *federatingDB) Flag(ctx context.Context, flag vocab.ActivityStreamsFlag) error {
+	l := f.log.WithFields(
+		logrus.Fields{
+			"func": "Flag",
+		},
+	)
+	m, err := streams.Serialize(flag)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	l.Debugf("received FLAG %s", string(b))
+
+	report, err := f.typeConverter.ASFlagToReport(ctx, flag)
+	if err != nil {
+		return fmt.Errorf("Flag: error converting flag to report: %s", err)
+	}
+
+	// the same flag might be delivered more than once, eg., to the inboxes of several accounts, but we only want one report
+	if err := f.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: report.URI}}, &gtsmodel.Report{}); err == nil {
+		l.Debugf("report with uri %s already exists", report.URI)
+		return nil
+	} else if _, ok := err.(db.ErrNoEntries); !ok {
+		return fmt.Errorf("Flag: database error checking for existing report: %s", err)
+	}
+
+	newID, err := id.NewULID()
+	if err != nil {
+		return err
+	}
+	report.ID = newID
+	report.CreatedAt = time.Now()
+	report.UpdatedAt = time.Now()
+
+	if err := f.db.Put(ctx, report); err != nil {
+		return fmt.Errorf("Flag: database error inserting report: %s", err)
+	}
+
+	return nil
+}
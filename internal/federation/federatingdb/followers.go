@@ -31,11 +31,11 @@ func (f *federatingDB) Followers(c context.Context, actorIRI *url.URL) (follower
 	acct := &gtsmodel.Account{}
 
 	if util.IsUserPath(actorIRI) {
-		if err := f.db.GetWhere([]db.Where{{Key: "uri", Value: actorIRI.String()}}, acct); err != nil {
+		if err := f.db.GetWhere(c, []db.Where{{Key: "uri", Value: actorIRI.String()}}, acct); err != nil {
 			return nil, fmt.Errorf("db error getting account with uri %s: %s", actorIRI.String(), err)
 		}
 	} else if util.IsFollowersPath(actorIRI) {
-		if err := f.db.GetWhere([]db.Where{{Key: "followers_uri", Value: actorIRI.String()}}, acct); err != nil {
+		if err := f.db.GetWhere(c, []db.Where{{Key: "followers_uri", Value: actorIRI.String()}}, acct); err != nil {
 			return nil, fmt.Errorf("db error getting account with followers uri %s: %s", actorIRI.String(), err)
 		}
 	} else {
@@ -43,7 +43,7 @@ func (f *federatingDB) Followers(c context.Context, actorIRI *url.URL) (follower
 	}
 
 	acctFollowers := []gtsmodel.Follow{}
-	if err := f.db.GetFollowersByAccountID(acct.ID, &acctFollowers, false); err != nil {
+	if err := f.db.GetFollowersByAccountID(c, acct.ID, &acctFollowers, false); err != nil {
 		return nil, fmt.Errorf("db error getting followers for account id %s: %s", acct.ID, err)
 	}
 
@@ -51,7 +51,7 @@ func (f *federatingDB) Followers(c context.Context, actorIRI *url.URL) (follower
 	items := streams.NewActivityStreamsItemsProperty()
 	for _, follow := range acctFollowers {
 		gtsFollower := &gtsmodel.Account{}
-		if err := f.db.GetByID(follow.AccountID, gtsFollower); err != nil {
+		if err := f.db.GetByID(c, follow.AccountID, gtsFollower); err != nil {
 			return nil, fmt.Errorf("db error getting account id %s: %s", follow.AccountID, err)
 		}
 		uri, err := url.Parse(gtsFollower.URI)
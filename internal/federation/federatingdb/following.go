@@ -30,11 +30,11 @@ func (f *federatingDB) Following(c context.Context, actorIRI *url.URL) (followin
 
 	acct := &gtsmodel.Account{}
 	if util.IsUserPath(actorIRI) {
-		if err := f.db.GetWhere([]db.Where{{Key: "uri", Value: actorIRI.String()}}, acct); err != nil {
+		if err := f.db.GetWhere(c, []db.Where{{Key: "uri", Value: actorIRI.String()}}, acct); err != nil {
 			return nil, fmt.Errorf("db error getting account with uri %s: %s", actorIRI.String(), err)
 		}
 	} else if util.IsFollowingPath(actorIRI) {
-		if err := f.db.GetWhere([]db.Where{{Key: "following_uri", Value: actorIRI.String()}}, acct); err != nil {
+		if err := f.db.GetWhere(c, []db.Where{{Key: "following_uri", Value: actorIRI.String()}}, acct); err != nil {
 			return nil, fmt.Errorf("db error getting account with following uri %s: %s", actorIRI.String(), err)
 		}
 	} else {
@@ -42,7 +42,7 @@ func (f *federatingDB) Following(c context.Context, actorIRI *url.URL) (followin
 	}
 
 	acctFollowing := []gtsmodel.Follow{}
-	if err := f.db.GetFollowingByAccountID(acct.ID, &acctFollowing); err != nil {
+	if err := f.db.GetFollowingByAccountID(c, acct.ID, &acctFollowing); err != nil {
 		return nil, fmt.Errorf("db error getting following for account id %s: %s", acct.ID, err)
 	}
 
@@ -50,7 +50,7 @@ func (f *federatingDB) Following(c context.Context, actorIRI *url.URL) (followin
 	items := streams.NewActivityStreamsItemsProperty()
 	for _, follow := range acctFollowing {
 		gtsFollowing := &gtsmodel.Account{}
-		if err := f.db.GetByID(follow.AccountID, gtsFollowing); err != nil {
+		if err := f.db.GetByID(c, follow.AccountID, gtsFollowing); err != nil {
 			return nil, fmt.Errorf("db error getting account id %s: %s", follow.AccountID, err)
 		}
 		uri, err := url.Parse(gtsFollowing.URI)
@@ -44,16 +44,16 @@ func (f *federatingDB) Get(c context.Context, id *url.URL) (value vocab.Type, er
 
 	if util.IsUserPath(id) {
 		acct := &gtsmodel.Account{}
-		if err := f.db.GetWhere([]db.Where{{Key: "uri", Value: id.String()}}, acct); err != nil {
+		if err := f.db.GetWhere(c, []db.Where{{Key: "uri", Value: id.String()}}, acct); err != nil {
 			return nil, err
 		}
 		l.Debug("is user path! returning account")
-		return f.typeConverter.AccountToAS(acct)
+		return f.typeConverter.AccountToAS(c, acct)
 	}
 
 	if util.IsFollowersPath(id) {
 		acct := &gtsmodel.Account{}
-		if err := f.db.GetWhere([]db.Where{{Key: "followers_uri", Value: id.String()}}, acct); err != nil {
+		if err := f.db.GetWhere(c, []db.Where{{Key: "followers_uri", Value: id.String()}}, acct); err != nil {
 			return nil, err
 		}
 
@@ -67,7 +67,7 @@ func (f *federatingDB) Get(c context.Context, id *url.URL) (value vocab.Type, er
 
 	if util.IsFollowingPath(id) {
 		acct := &gtsmodel.Account{}
-		if err := f.db.GetWhere([]db.Where{{Key: "following_uri", Value: id.String()}}, acct); err != nil {
+		if err := f.db.GetWhere(c, []db.Where{{Key: "following_uri", Value: id.String()}}, acct); err != nil {
 			return nil, err
 		}
 
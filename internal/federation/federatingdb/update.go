@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-fed/activity/streams"
 	"github.com/go-fed/activity/streams/vocab"
 	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
@@ -69,6 +71,15 @@ func (f *federatingDB) Update(ctx context.Context, asType vocab.Type) error {
 	}
 
 	typeName := asType.GetTypeName()
+	if typeName == gtsmodel.ActivityStreamsQuestion {
+		// it's an UPDATE to a poll, probably because its tallies have changed
+		question, ok := asType.(vocab.ActivityStreamsQuestion)
+		if !ok {
+			return errors.New("could not convert type to question")
+		}
+		return f.updatePoll(ctx, question, requestingAcct)
+	}
+
 	if typeName == gtsmodel.ActivityStreamsApplication ||
 		typeName == gtsmodel.ActivityStreamsGroup ||
 		typeName == gtsmodel.ActivityStreamsOrganization ||
@@ -115,7 +126,7 @@ func (f *federatingDB) Update(ctx context.Context, asType vocab.Type) error {
 			accountable = i
 		}
 
-		updatedAcct, err := f.typeConverter.ASRepresentationToAccount(accountable, true)
+		updatedAcct, err := f.typeConverter.ASRepresentationToAccount(ctx, accountable, true)
 		if err != nil {
 			return fmt.Errorf("error converting to account: %s", err)
 		}
@@ -131,7 +142,7 @@ func (f *federatingDB) Update(ctx context.Context, asType vocab.Type) error {
 		}
 
 		updatedAcct.ID = requestingAcct.ID // set this here so the db will update properly instead of trying to PUT this and getting constraint issues
-		if err := f.db.UpdateByID(requestingAcct.ID, updatedAcct); err != nil {
+		if err := f.db.UpdateByID(ctx, requestingAcct.ID, updatedAcct); err != nil {
 			return fmt.Errorf("database error inserting updated account: %s", err)
 		}
 
@@ -146,3 +157,55 @@ func (f *federatingDB) Update(ctx context.Context, asType vocab.Type) error {
 
 	return nil
 }
+
+// updatePoll updates the tallies of a remote poll that we already know about with those in the given question.
+func (f *federatingDB) updatePoll(ctx context.Context, question vocab.ActivityStreamsQuestion, requestingAcct *gtsmodel.Account) error {
+	idProp := question.GetJSONLDId()
+	if idProp == nil || !idProp.IsIRI() {
+		return errors.New("no id property set on question, or was not an iri")
+	}
+
+	status := &gtsmodel.Status{}
+	if err := f.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: idProp.GetIRI().String()}}, status); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			// we don't know this status so there's nothing to update
+			return nil
+		}
+		return fmt.Errorf("database error getting status: %s", err)
+	}
+
+	if status.Local {
+		// no need to update local polls
+		return nil
+	}
+
+	if requestingAcct == nil || requestingAcct.ID != status.AccountID {
+		return fmt.Errorf("update for poll %s was not requested by its owner", status.URI)
+	}
+
+	poll := &gtsmodel.Poll{}
+	if err := f.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: status.ID}}, poll); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil
+		}
+		return fmt.Errorf("database error getting poll: %s", err)
+	}
+
+	updatedPoll, err := f.typeConverter.ASPollToPoll(ctx, question)
+	if err != nil {
+		return fmt.Errorf("error converting question to poll: %s", err)
+	}
+
+	if len(updatedPoll.Options) != len(poll.Options) {
+		return fmt.Errorf("update for poll %s changed the number of options", status.URI)
+	}
+
+	poll.Votes = updatedPoll.Votes
+	poll.VotersCount = updatedPoll.VotersCount
+	poll.UpdatedAt = time.Now()
+	if err := f.db.UpdateByID(ctx, poll.ID, poll); err != nil {
+		return fmt.Errorf("database error updating poll: %s", err)
+	}
+
+	return nil
+}
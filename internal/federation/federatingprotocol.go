@@ -32,6 +32,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/metrics"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
@@ -102,82 +103,95 @@ func (f *federator) AuthenticatePostInbox(ctx context.Context, w http.ResponseWr
 	l.Trace("received request to authenticate")
 
 	if !util.IsInboxPath(r.URL) {
+		metrics.InboxPostRejected("bad_inbox_path")
 		return nil, false, fmt.Errorf("path %s was not an inbox path", r.URL.String())
 	}
 
 	username, err := util.ParseInboxPath(r.URL)
 	if err != nil {
+		metrics.InboxPostRejected("bad_inbox_path")
 		return nil, false, fmt.Errorf("could not parse path %s: %s", r.URL.String(), err)
 	}
 
 	if username == "" {
+		metrics.InboxPostRejected("bad_inbox_path")
 		return nil, false, errors.New("username was empty")
 	}
 
 	requestedAccount := &gtsmodel.Account{}
-	if err := f.db.GetLocalAccountByUsername(username, requestedAccount); err != nil {
+	if err := f.db.GetLocalAccountByUsername(ctx, username, requestedAccount); err != nil {
+		metrics.InboxPostRejected("unknown_account")
 		return nil, false, fmt.Errorf("could not fetch requested account with username %s: %s", username, err)
 	}
 
 	publicKeyOwnerURI, authenticated, err := f.AuthenticateFederatedRequest(ctx, requestedAccount.Username)
 	if err != nil {
 		l.Debugf("request not authenticated: %s", err)
+		metrics.InboxPostRejected("auth_error")
 		return ctx, false, err
 	}
 
 	if !authenticated {
 		w.WriteHeader(http.StatusForbidden)
+		metrics.InboxPostRejected("not_authenticated")
 		return ctx, false, nil
 	}
 
 	// authentication has passed, so add an instance entry for this instance if it hasn't been done already
 	i := &gtsmodel.Instance{}
-	if err := f.db.GetWhere([]db.Where{{Key: "domain", Value: publicKeyOwnerURI.Host, CaseInsensitive: true}}, i); err != nil {
+	if err := f.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: publicKeyOwnerURI.Host, CaseInsensitive: true}}, i); err != nil {
 		if _, ok := err.(db.ErrNoEntries); !ok {
 			// there's been an actual error
+			metrics.InboxPostRejected("instance_error")
 			return ctx, false, fmt.Errorf("error getting requesting account with public key id %s: %s", publicKeyOwnerURI.String(), err)
 		}
 
 		// we don't have an entry for this instance yet so dereference it
-		i, err = f.DereferenceRemoteInstance(username, &url.URL{
+		i, err = f.DereferenceRemoteInstance(ctx, username, &url.URL{
 			Scheme: publicKeyOwnerURI.Scheme,
 			Host:   publicKeyOwnerURI.Host,
 		})
 		if err != nil {
+			metrics.InboxPostRejected("instance_error")
 			return nil, false, fmt.Errorf("could not dereference new remote instance %s during AuthenticatePostInbox: %s", publicKeyOwnerURI.Host, err)
 		}
 
 		// and put it in the db
-		if err := f.db.Put(i); err != nil {
+		if err := f.db.Put(ctx, i); err != nil {
+			metrics.InboxPostRejected("instance_error")
 			return nil, false, fmt.Errorf("error inserting newly dereferenced instance %s: %s", publicKeyOwnerURI.Host, err)
 		}
 	}
 
 	requestingAccount := &gtsmodel.Account{}
-	if err := f.db.GetWhere([]db.Where{{Key: "uri", Value: publicKeyOwnerURI.String()}}, requestingAccount); err != nil {
+	if err := f.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: publicKeyOwnerURI.String()}}, requestingAccount); err != nil {
 		// there's been a proper error so return it
 		if _, ok := err.(db.ErrNoEntries); !ok {
+			metrics.InboxPostRejected("account_error")
 			return ctx, false, fmt.Errorf("error getting requesting account with public key id %s: %s", publicKeyOwnerURI.String(), err)
 		}
 
 		// we don't know this account (yet) so let's dereference it right now
-		person, err := f.DereferenceRemoteAccount(requestedAccount.Username, publicKeyOwnerURI)
+		person, err := f.DereferenceRemoteAccount(ctx, requestedAccount.Username, publicKeyOwnerURI)
 		if err != nil {
+			metrics.InboxPostRejected("account_error")
 			return ctx, false, fmt.Errorf("error dereferencing account with public key id %s: %s", publicKeyOwnerURI.String(), err)
 		}
 
-		a, err := f.typeConverter.ASRepresentationToAccount(person, false)
+		a, err := f.typeConverter.ASRepresentationToAccount(ctx, person, false)
 		if err != nil {
+			metrics.InboxPostRejected("account_error")
 			return ctx, false, fmt.Errorf("error converting person with public key id %s to account: %s", publicKeyOwnerURI.String(), err)
 		}
 
 		aID, err := id.NewRandomULID()
 		if err != nil {
+			metrics.InboxPostRejected("account_error")
 			return ctx, false, err
 		}
 		a.ID = aID
 
-		if err := f.db.Put(a); err != nil {
+		if err := f.db.Put(ctx, a); err != nil {
 			l.Errorf("error inserting dereferenced remote account: %s", err)
 		}
 
@@ -200,6 +214,8 @@ func (f *federator) AuthenticatePostInbox(ctx context.Context, w http.ResponseWr
 		}
 	}
 
+	metrics.InboxPostAccepted()
+
 	withRequester := context.WithValue(ctx, util.APRequestingAccount, requestingAccount)
 	withRequested := context.WithValue(withRequester, util.APAccount, requestedAccount)
 	return withRequested, true, nil
@@ -235,7 +251,7 @@ func (f *federator) Blocked(ctx context.Context, actorIRIs []*url.URL) (bool, er
 	}
 
 	for _, uri := range actorIRIs {
-		blockedDomain, err := f.blockedDomain(uri.Host)
+		blockedDomain, err := f.blockedDomain(ctx, uri.Host)
 		if err != nil {
 			return false, fmt.Errorf("error checking domain block: %s", err)
 		}
@@ -244,7 +260,7 @@ func (f *federator) Blocked(ctx context.Context, actorIRIs []*url.URL) (bool, er
 		}
 
 		requestingAccount := &gtsmodel.Account{}
-		if err := f.db.GetWhere([]db.Where{{Key: "uri", Value: uri.String()}}, requestingAccount); err != nil {
+		if err := f.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: uri.String()}}, requestingAccount); err != nil {
 			_, ok := err.(db.ErrNoEntries)
 			if ok {
 				// we don't have an entry for this account so it's not blocked
@@ -255,7 +271,7 @@ func (f *federator) Blocked(ctx context.Context, actorIRIs []*url.URL) (bool, er
 		}
 
 		// check if requested account blocks requesting account
-		if err := f.db.GetWhere([]db.Where{
+		if err := f.db.GetWhere(ctx, []db.Where{
 			{Key: "account_id", Value: requestedAccount.ID},
 			{Key: "target_account_id", Value: requestingAccount.ID},
 		}, &gtsmodel.Block{}); err == nil {
@@ -307,6 +323,10 @@ func (f *federator) FederatingCallbacks(ctx context.Context) (wrapped pub.Federa
 		func(ctx context.Context, announce vocab.ActivityStreamsAnnounce) error {
 			return f.FederatingDB().Announce(ctx, announce)
 		},
+		// store incoming reports so that they show up in the moderation queue
+		func(ctx context.Context, flag vocab.ActivityStreamsFlag) error {
+			return f.FederatingDB().Flag(ctx, flag)
+		},
 	}
 
 	return
@@ -36,6 +36,10 @@ import (
 
 // Federator wraps various interfaces and functions to manage activitypub federation from gotosocial
 type Federator interface {
+	// Start starts the Federator, retrying failed deliveries in the background.
+	Start(ctx context.Context) error
+	// Stop stops the Federator, waiting for any deliveries that are being retried to finish before returning.
+	Stop() error
 	// FederatingActor returns the underlying pub.FederatingActor, which can be used to send activities, and serve actors at inboxes/outboxes.
 	FederatingActor() pub.FederatingActor
 	// FederatingDB returns the underlying FederatingDB interface.
@@ -51,27 +55,30 @@ type Federator interface {
 	AuthenticateFederatedRequest(ctx context.Context, username string) (*url.URL, bool, error)
 	// FingerRemoteAccount performs a webfinger lookup for a remote account, using the .well-known path. It will return the ActivityPub URI for that
 	// account, or an error if it doesn't exist or can't be retrieved.
-	FingerRemoteAccount(requestingUsername string, targetUsername string, targetDomain string) (*url.URL, error)
+	FingerRemoteAccount(ctx context.Context, requestingUsername string, targetUsername string, targetDomain string) (*url.URL, error)
 	// DereferenceRemoteAccount can be used to get the representation of a remote account, based on the account ID (which is a URI).
 	// The given username will be used to create a transport for making outgoing requests. See the implementation for more detailed comments.
-	DereferenceRemoteAccount(username string, remoteAccountID *url.URL) (typeutils.Accountable, error)
+	DereferenceRemoteAccount(ctx context.Context, username string, remoteAccountID *url.URL) (typeutils.Accountable, error)
 	// DereferenceRemoteStatus can be used to get the representation of a remote status, based on its ID (which is a URI).
 	// The given username will be used to create a transport for making outgoing requests. See the implementation for more detailed comments.
-	DereferenceRemoteStatus(username string, remoteStatusID *url.URL) (typeutils.Statusable, error)
+	DereferenceRemoteStatus(ctx context.Context, username string, remoteStatusID *url.URL) (typeutils.Statusable, error)
 	// DereferenceRemoteInstance takes the URL of a remote instance, and a username (optional) to spin up a transport with. It then
 	// does its damnedest to get some kind of information back about the instance, trying /api/v1/instance, then /.well-known/nodeinfo
-	DereferenceRemoteInstance(username string, remoteInstanceURI *url.URL) (*gtsmodel.Instance, error)
+	DereferenceRemoteInstance(ctx context.Context, username string, remoteInstanceURI *url.URL) (*gtsmodel.Instance, error)
 	// DereferenceStatusFields does further dereferencing on a status.
-	DereferenceStatusFields(status *gtsmodel.Status, requestingUsername string) error
-	// DereferenceAccountFields does further dereferencing on an account.
-	DereferenceAccountFields(account *gtsmodel.Account, requestingUsername string, refresh bool) error
+	DereferenceStatusFields(ctx context.Context, status *gtsmodel.Status, requestingUsername string) error
+	// DereferenceAccountFields does further dereferencing on an account. If refresh is true, the featured collection of a remote account is refreshed as well.
+	DereferenceAccountFields(ctx context.Context, account *gtsmodel.Account, requestingUsername string, refresh bool) error
 	// DereferenceAnnounce does further dereferencing on an announce.
-	DereferenceAnnounce(announce *gtsmodel.Status, requestingUsername string) error
+	DereferenceAnnounce(ctx context.Context, announce *gtsmodel.Status, requestingUsername string) error
+	// DereferenceFeaturedCollection fetches the featured collection of a remote account, and makes sure that the statuses in it,
+	// and only those statuses, are marked as pinned in the database.
+	DereferenceFeaturedCollection(ctx context.Context, account *gtsmodel.Account, requestingUsername string) error
 	// GetTransportForUser returns a new transport initialized with the key credentials belonging to the given username.
 	// This can be used for making signed http requests.
 	//
 	// If username is an empty string, our instance user's credentials will be used instead.
-	GetTransportForUser(username string) (transport.Transport, error)
+	GetTransportForUser(ctx context.Context, username string) (transport.Transport, error)
 	// Handshaking returns true if the given username is currently in the process of dereferencing the remoteAccountID.
 	Handshaking(username string, remoteAccountID *url.URL) bool
 	pub.CommonBehavior
@@ -90,6 +97,9 @@ type federator struct {
 	log                 *logrus.Logger
 	handshakes          map[string][]*url.URL
 	handshakeSync       *sync.Mutex // mutex to lock/unlock when checking or updating the handshakes map
+	deliverySync        *sync.Mutex // mutex to lock/unlock when recording delivery failures against an instance
+	stop                chan interface{}
+	wg                  sync.WaitGroup
 }
 
 // NewFederator returns a new federator
@@ -106,12 +116,26 @@ func NewFederator(db db.DB, federatingDB federatingdb.DB, transportController tr
 		mediaHandler:        mediaHandler,
 		log:                 log,
 		handshakeSync:       &sync.Mutex{},
+		deliverySync:        &sync.Mutex{},
+		stop:                make(chan interface{}),
 	}
 	actor := newFederatingActor(f, f, federatingDB, clock)
 	f.actor = actor
 	return f
 }
 
+func (f *federator) Start(ctx context.Context) error {
+	f.wg.Add(1)
+	go f.retryDeliveries(ctx)
+	return nil
+}
+
+func (f *federator) Stop() error {
+	close(f.stop)
+	f.wg.Wait()
+	return nil
+}
+
 func (f *federator) FederatingActor() pub.FederatingActor {
 	return f.actor
 }
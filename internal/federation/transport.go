@@ -54,14 +54,24 @@ func (f *federator) NewTransport(ctx context.Context, actorBoxIRI *url.URL, gofe
 	}
 
 	account := &gtsmodel.Account{}
-	if err := f.db.GetLocalAccountByUsername(username, account); err != nil {
+	if err := f.db.GetLocalAccountByUsername(ctx, username, account); err != nil {
 		return nil, fmt.Errorf("error getting account with username %s from the db: %s", username, err)
 	}
 
-	return f.transportController.NewTransport(account.PublicKeyURI, account.PrivateKey)
+	t, err := f.transportController.NewTransport(account.PublicKeyURI, account.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// wrap the transport so that failed deliveries are retried later on
+	return &deliveryTransport{
+		Transport: t,
+		f:         f,
+		accountID: account.ID,
+	}, nil
 }
 
-func (f *federator) GetTransportForUser(username string) (transport.Transport, error) {
+func (f *federator) GetTransportForUser(ctx context.Context, username string) (transport.Transport, error) {
 	// We need an account to use to create a transport for dereferecing something.
 	// If a username has been given, we can fetch the account with that username and use it.
 	// Otherwise, we can take the instance account and use those credentials to make the request.
@@ -72,7 +82,7 @@ func (f *federator) GetTransportForUser(username string) (transport.Transport, e
 	} else {
 		u = username
 	}
-	if err := f.db.GetLocalAccountByUsername(u, ourAccount); err != nil {
+	if err := f.db.GetLocalAccountByUsername(ctx, u, ourAccount); err != nil {
 		return nil, fmt.Errorf("error getting account %s from db: %s", username, err)
 	}
 
@@ -1,13 +1,15 @@
 package federation
 
 import (
+	"context"
+
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (f *federator) blockedDomain(host string) (bool, error) {
+func (f *federator) blockedDomain(ctx context.Context, host string) (bool, error) {
 	b := &gtsmodel.DomainBlock{}
-	err := f.db.GetWhere([]db.Where{{Key: "domain", Value: host, CaseInsensitive: true}}, b)
+	err := f.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: host, CaseInsensitive: true}}, b)
 	if err == nil {
 		// block exists
 		return true, nil
@@ -24,6 +24,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/federation"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
 	"github.com/superseriousbusiness/gotosocial/internal/router"
 )
 
@@ -33,20 +34,21 @@ type Server interface {
 	// Start starts up the gotosocial server. If something goes wrong
 	// while starting the server, then an error will be returned.
 	Start(context.Context) error
-	// Stop closes down the gotosocial server, first closing the router
-	// then the database. If something goes wrong while stopping, an
-	// error will be returned.
+	// Stop closes down the gotosocial server, first closing the router,
+	// then the processor, then the federator, then the database. If something goes wrong while
+	// stopping, an error will be returned.
 	Stop(context.Context) error
 }
 
 // NewServer returns a new gotosocial server, initialized with the given configuration.
 // An error will be returned the caller if something goes wrong during initialization
 // eg., no db or storage connection, port for router already in use, etc.
-func NewServer(db db.DB, apiRouter router.Router, federator federation.Federator, config *config.Config) (Server, error) {
+func NewServer(db db.DB, apiRouter router.Router, federator federation.Federator, processor processing.Processor, config *config.Config) (Server, error) {
 	return &gotosocial{
 		db:        db,
 		apiRouter: apiRouter,
 		federator: federator,
+		processor: processor,
 		config:    config,
 	}, nil
 }
@@ -56,6 +58,7 @@ type gotosocial struct {
 	db        db.DB
 	apiRouter router.Router
 	federator federation.Federator
+	processor processing.Processor
 	config    *config.Config
 }
 
@@ -66,13 +69,19 @@ func (gts *gotosocial) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop closes down the gotosocial server, first closing the router
-// then the database. If something goes wrong while stopping, an
-// error will be returned.
+// Stop closes down the gotosocial server, first closing the router,
+// then the processor, then the federator, then the database. If something goes wrong while
+// stopping, an error will be returned.
 func (gts *gotosocial) Stop(ctx context.Context) error {
 	if err := gts.apiRouter.Stop(ctx); err != nil {
 		return err
 	}
+	if err := gts.processor.Stop(); err != nil {
+		return err
+	}
+	if err := gts.federator.Stop(); err != nil {
+		return err
+	}
 	if err := gts.db.Stop(ctx); err != nil {
 		return err
 	}
@@ -115,10 +115,11 @@ type Account struct {
 		CRYPTO FIELDS
 	*/
 
-	// Privatekey for validating activitypub requests, will only be defined for local accounts
-	PrivateKey *rsa.PrivateKey
+	// Privatekey for validating activitypub requests, will only be defined for local accounts.
+	// Keys are left out when accounts are encoded as part of a queued message; they're always fetched from the db when needed.
+	PrivateKey *rsa.PrivateKey `json:"-"`
 	// Publickey for encoding activitypub requests, will be defined for both local and remote accounts
-	PublicKey *rsa.PublicKey
+	PublicKey *rsa.PublicKey `json:"-"`
 	// Web-reachable location of this account's public key
 	PublicKeyURI string
 
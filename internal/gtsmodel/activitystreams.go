@@ -58,6 +58,13 @@ const (
 	ActivityStreamsService = "Service"
 )
 
+const (
+	// ActivityStreamsCollection https://www.w3.org/TR/activitystreams-vocabulary/#dfn-collection
+	ActivityStreamsCollection = "Collection"
+	// ActivityStreamsOrderedCollection https://www.w3.org/TR/activitystreams-vocabulary/#dfn-orderedcollection
+	ActivityStreamsOrderedCollection = "OrderedCollection"
+)
+
 const (
 	// ActivityStreamsAccept https://www.w3.org/TR/activitystreams-vocabulary/#dfn-accept
 	ActivityStreamsAccept = "Accept"
@@ -0,0 +1,52 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Conversation is a thread of direct statuses between a set of accounts, as seen by one local account taking part in it.
+//
+// Each local participant has its own conversation, so that they can each keep track of what they've read, or remove it.
+// The statuses in a conversation are stored separately, as ConversationStatus entries.
+type Conversation struct {
+	// id of this conversation in the database
+	ID string `pg:"type:CHAR(26),pk,notnull,unique"`
+	// When was this conversation created
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// When was this conversation last updated, eg., by a new status
+	UpdatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Which local account does this conversation belong to?
+	AccountID string `pg:"type:CHAR(26),notnull"`
+	// ActivityPub URI of the first status of the thread, or of the earliest status of it that we know about
+	ThreadURI string `pg:",notnull"`
+	// Database IDs of the other accounts taking part in the conversation, sorted
+	ParticipantAccountIDs []string `pg:"participant_account_ids,array"`
+	// Database ID of the most recent status in this conversation
+	LastStatusID string `pg:"type:CHAR(26),notnull"`
+	// Has the owning account read the most recent status in this conversation?
+	Read bool `pg:",default:false"`
+}
+
+// ConversationStatus refers to one status being part of a conversation.
+type ConversationStatus struct {
+	// Which conversation is the status part of?
+	ConversationID string `pg:"type:CHAR(26),pk,notnull"`
+	// Which status is part of the conversation?
+	StatusID string `pg:"type:CHAR(26),pk,notnull"`
+}
@@ -0,0 +1,48 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Delivery is an outgoing federated activity that couldn't be delivered to a remote inbox the first time around,
+// and which is waiting to be retried.
+type Delivery struct {
+	// id of this delivery in the database
+	ID string `pg:"type:CHAR(26),pk,notnull,unique"`
+	// When was this delivery first attempted?
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// When was this delivery last updated?
+	UpdatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Domain of the inbox this delivery is addressed to
+	Domain string `pg:",notnull"`
+	// ID of the local account sending this delivery; its keys are used to sign the request
+	AccountID string `pg:"type:CHAR(26),notnull"`
+	// URI of the inbox this delivery is addressed to
+	InboxURI string `pg:",notnull"`
+	// Serialized activity to deliver
+	Payload string `pg:",notnull"`
+	// How many times have we tried and failed to deliver this?
+	Attempts int `pg:",notnull,default:0"`
+	// Error returned by the last failed attempt
+	LastError string
+	// When should this delivery next be attempted?
+	NextAttemptAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// When did we give up on this delivery, if at all?
+	FailedAt time.Time `pg:"type:timestamp"`
+}
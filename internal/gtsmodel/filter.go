@@ -0,0 +1,82 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Filter refers to a phrase that an account doesn't want to see in statuses, in the given contexts.
+type Filter struct {
+	// id of this filter in the database
+	ID string `pg:"type:CHAR(26),pk,notnull,unique"`
+	// When was this filter created
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// When was this filter last updated
+	UpdatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Who owns this filter?
+	AccountID string `pg:"type:CHAR(26),notnull"`
+	// The phrase to filter statuses on
+	Phrase string `pg:",notnull"`
+	// Should the filter be applied to the home timeline and lists?
+	ContextHome bool
+	// Should the filter be applied to notifications?
+	ContextNotifications bool
+	// Should the filter be applied to public timelines?
+	ContextPublic bool
+	// Should the filter be applied to the context of a status?
+	ContextThread bool
+	// Should the phrase only match whole words?
+	WholeWord bool
+	// Should matching statuses be dropped for good, rather than hidden by the client?
+	Irreversible bool
+	// When does this filter stop applying? A zero value means it never expires.
+	ExpiresAt time.Time `pg:"type:timestamp"`
+}
+
+// FilterContext is one of the places where a filter can be applied.
+type FilterContext string
+
+const (
+	// FilterContextHome means the home timeline and lists.
+	FilterContextHome FilterContext = "home"
+	// FilterContextNotifications means notifications.
+	FilterContextNotifications FilterContext = "notifications"
+	// FilterContextPublic means the public and local timelines.
+	FilterContextPublic FilterContext = "public"
+	// FilterContextThread means the ancestors and descendants of a status.
+	FilterContextThread FilterContext = "thread"
+)
+
+// AppliesTo returns true if the filter applies in the given context at the given time.
+func (f *Filter) AppliesTo(context FilterContext, now time.Time) bool {
+	if !f.ExpiresAt.IsZero() && !now.Before(f.ExpiresAt) {
+		return false
+	}
+
+	switch context {
+	case FilterContextHome:
+		return f.ContextHome
+	case FilterContextNotifications:
+		return f.ContextNotifications
+	case FilterContextPublic:
+		return f.ContextPublic
+	case FilterContextThread:
+		return f.ContextThread
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// FollowedTag refers to an account following a hashtag.
+//
+// Public statuses that use a followed hashtag are put in the home timeline of the following account,
+// even if the account doesn't follow the author of the status.
+type FollowedTag struct {
+	// id of this followed tag in the database
+	ID string `pg:"type:CHAR(26),pk,notnull"`
+	// When was this tag followed
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// When was this followed tag updated
+	UpdatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Which account follows the tag?
+	AccountID string `pg:"type:CHAR(26),unique:followedtagaccounttag,notnull"`
+	// Which tag is followed?
+	TagID string `pg:"type:CHAR(26),unique:followedtagaccounttag,notnull"`
+}
@@ -36,4 +36,8 @@ type Instance struct {
 	Reputation int64 `pg:",notnull,default:0"`
 	// Version of the software used on this instance
 	Version string
+	// How many deliveries to this instance have failed in a row?
+	DeliveryFailures int `pg:",notnull,default:0"`
+	// When did this instance become unreachable, if at all? Deliveries to unreachable instances are held back until it recovers.
+	UnreachableSince time.Time `pg:"type:timestamp"`
 }
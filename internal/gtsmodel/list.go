@@ -0,0 +1,64 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// List refers to a list of follows that an account has set up, so that statuses from those follows can be seen in a timeline of their own.
+type List struct {
+	// id of this list in the database
+	ID string `pg:"type:CHAR(26),pk,notnull,unique"`
+	// When was this list created
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// When was this list last updated
+	UpdatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Title of this list, as set by the account that owns it
+	Title string `pg:",notnull"`
+	// Who owns this list?
+	AccountID string `pg:"type:CHAR(26),notnull"`
+	// Which replies should be shown in the timeline of this list?
+	RepliesPolicy ListRepliesPolicy `pg:",notnull,default:'followed'"`
+}
+
+// ListEntry refers to one account being a member of a list. An account can only be added to a list
+// if the owner of the list follows it, so the id of that follow is stored here too.
+type ListEntry struct {
+	// id of this list entry in the database
+	ID string `pg:"type:CHAR(26),pk,notnull,unique"`
+	// When was this entry created
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Which list does this entry belong to?
+	ListID string `pg:"type:CHAR(26),unique:listaccount,notnull"`
+	// Which account is in the list?
+	AccountID string `pg:"type:CHAR(26),unique:listaccount,notnull"`
+	// The follow from the list owner to AccountID that this entry depends on
+	FollowID string `pg:"type:CHAR(26),notnull"`
+}
+
+// ListRepliesPolicy describes which replies should be shown in the timeline of a list.
+type ListRepliesPolicy string
+
+const (
+	// ListRepliesPolicyFollowed means replies to any account followed by the list owner should be shown.
+	ListRepliesPolicyFollowed ListRepliesPolicy = "followed"
+	// ListRepliesPolicyList means only replies to members of the list should be shown.
+	ListRepliesPolicyList ListRepliesPolicy = "list"
+	// ListRepliesPolicyNone means no replies should be shown, except for replies of list members to themselves.
+	ListRepliesPolicyNone ListRepliesPolicy = "none"
+)
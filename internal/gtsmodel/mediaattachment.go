@@ -59,6 +59,10 @@ type MediaAttachment struct {
 	Avatar bool
 	// Is this attachment being used as a header?
 	Header bool
+	// Is the file for this attachment currently in storage? Remote media may be pruned from storage, and fetched again when it's needed.
+	Cached bool `pg:",notnull,default:true,use_zero"`
+	// When was the file for this attachment last served or dereferenced
+	LastAccessedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
 }
 
 // File refers to the metadata for the whole file
@@ -140,6 +144,12 @@ type Original struct {
 	Height int
 	Size   int
 	Aspect float64
+	// Duration in seconds, for video and audio
+	Duration *float32
+	// Framerate in frames per second, for video
+	Framerate *float32
+	// Bitrate in bits per second, for video and audio
+	Bitrate *uint64
 }
 
 // Focus describes the 'center' of the image for display purposes.
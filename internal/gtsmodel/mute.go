@@ -0,0 +1,50 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Mute refers to the muting of one account by another.
+//
+// A muted account's statuses are hidden from the muting account's timelines, and if Notifications
+// is set, notifications caused by the muted account are hidden too. Unlike a block, the muted
+// account is never told about the mute, so it isn't federated.
+type Mute struct {
+	// id of this mute in the database
+	ID string `pg:"type:CHAR(26),pk,notnull"`
+	// When was this mute created
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// When was this mute updated
+	UpdatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Who created this mute?
+	AccountID string   `pg:"type:CHAR(26),unique:mutesrctarget,notnull"`
+	Account   *Account `pg:"rel:has-one"`
+	// Who is targeted by this mute?
+	TargetAccountID string   `pg:"type:CHAR(26),unique:mutesrctarget,notnull"`
+	TargetAccount   *Account `pg:"rel:has-one"`
+	// Should notifications from the target account be muted as well?
+	Notifications bool
+	// When does this mute stop applying? A zero value means it never expires.
+	ExpiresAt time.Time `pg:"type:timestamp"`
+}
+
+// Expired returns true if the mute has an expiry time and it has passed at the given time.
+func (m *Mute) Expired(now time.Time) bool {
+	return !m.ExpiresAt.IsZero() && !now.Before(m.ExpiresAt)
+}
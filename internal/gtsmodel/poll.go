@@ -17,3 +17,59 @@
 */
 
 package gtsmodel
+
+import "time"
+
+// Poll represents a set of options attached to a status, which accounts can vote on until the poll expires.
+// A status with a poll has ActivityStreamsType Question.
+type Poll struct {
+	// id of this poll in the database
+	ID string `pg:"type:CHAR(26),pk,notnull,unique"`
+	// When was this poll created
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// When were the tallies of this poll last updated
+	UpdatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Which status is this poll attached to?
+	StatusID string `pg:"type:CHAR(26),notnull,unique"`
+	// Which account owns the status this poll is attached to?
+	AccountID string `pg:"type:CHAR(26),notnull"`
+	// The titles of the options in this poll, in order
+	Options []string `pg:",array"`
+	// The number of votes for each option, in the same order as Options
+	Votes []int `pg:",array"`
+	// Can voters choose more than one option?
+	Multiple bool
+	// Should the tallies be hidden until the poll has closed?
+	HideTotals bool
+	// How many accounts have voted in this poll? For single choice polls this is the sum of Votes.
+	VotersCount int
+	// When does this poll stop accepting votes? A zero value means it never expires.
+	ExpiresAt time.Time `pg:"type:timestamp"`
+	// When was this poll closed? A zero value means it's still open.
+	ClosedAt time.Time `pg:"type:timestamp"`
+}
+
+// Expired returns true if the poll has stopped accepting votes at the given time.
+func (p *Poll) Expired(now time.Time) bool {
+	if !p.ClosedAt.IsZero() {
+		return true
+	}
+	return !p.ExpiresAt.IsZero() && !now.Before(p.ExpiresAt)
+}
+
+// PollVote represents one choice made by an account in a poll. Voting for several
+// options in a multiple choice poll gives one PollVote per option.
+type PollVote struct {
+	// id of this vote in the database
+	ID string `pg:"type:CHAR(26),pk,notnull,unique"`
+	// When was this vote made
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Which poll is this vote in?
+	PollID string `pg:"type:CHAR(26),unique:pollaccountchoice,notnull"`
+	// Who voted?
+	AccountID string `pg:"type:CHAR(26),unique:pollaccountchoice,notnull"`
+	// The index of the chosen option in the options of the poll
+	Choice int `pg:",unique:pollaccountchoice,use_zero"`
+	// ActivityPub URI of the Note that carried this vote
+	URI string `pg:",unique"`
+}
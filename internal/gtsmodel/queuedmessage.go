@@ -0,0 +1,56 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// QueuedMessage is a FromClientAPI or FromFederator message that's waiting to be processed by the processor.
+//
+// Messages are stored in the database so that their side effects (deliveries, notifications, timeline inserts etc)
+// survive a restart, and so that side effects which fail can be retried later on.
+type QueuedMessage struct {
+	// id of this message in the database
+	ID string `pg:"type:CHAR(26),pk,notnull,unique"`
+	// When was this message queued?
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Where did this message come from?
+	Origin QueueOrigin `pg:",notnull"`
+	// Type of the GTSModel carried by this message, eg., "Status" or "Follow". Empty if there's no model.
+	ModelType string
+	// JSON encoding of the FromClientAPI or FromFederator message.
+	Payload string `pg:",notnull"`
+	// How many times have we tried and failed to process this message?
+	Attempts int `pg:",notnull,default:0"`
+	// Error returned by the last failed attempt.
+	LastError string
+	// When should this message next be picked up for processing?
+	NextAttemptAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Names of the side effects of this message that have already succeeded, so that they aren't repeated when the message is retried.
+	CompletedSteps []string `pg:",array"`
+}
+
+// QueueOrigin describes where a queued message came from.
+type QueueOrigin string
+
+const (
+	// QueueOriginClientAPI means that the message is a FromClientAPI message.
+	QueueOriginClientAPI QueueOrigin = "client_api"
+	// QueueOriginFederator means that the message is a FromFederator message.
+	QueueOriginFederator QueueOrigin = "federator"
+)
@@ -0,0 +1,69 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Report represents one account reporting another account, and optionally some of its statuses, to the moderators of an instance.
+//
+// Reports can be created by local accounts, or come in from remote instances as a Flag activity.
+type Report struct {
+	// id of this report in the database
+	ID string `pg:"type:CHAR(26),pk,notnull,unique"`
+	// When was this report created
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// When was this report last updated, eg., assigned or resolved
+	UpdatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// ActivityPub URI of this report, ie., the ID of the Flag activity it was sent or received as
+	URI string `pg:",unique"`
+	// Which account created this report? For remote reports this will usually be the instance account of the remote instance.
+	AccountID string `pg:"type:CHAR(26),notnull"`
+	// Which account is being reported?
+	TargetAccountID string `pg:"type:CHAR(26),notnull"`
+	// Database IDs of statuses of the target account that are attached to this report
+	StatusIDs []string `pg:",array"`
+	// Why was this report made?
+	Comment string
+	// Has this report been forwarded to the instance of the target account?
+	Forwarded bool `pg:",default:false"`
+	// Which moderator is handling this report?
+	AssignedAccountID string `pg:"type:CHAR(26)"`
+	// Notes left on this report by moderators; these are never shown to the reporting account
+	Note string
+	// When was this report resolved? Zero if it hasn't been resolved yet.
+	ActionTakenAt time.Time `pg:"type:timestamp"`
+	// Which moderator resolved this report?
+	ActionTakenByAccountID string `pg:"type:CHAR(26)"`
+	// What action was taken to resolve this report?
+	ActionTaken ReportAction
+}
+
+// ReportAction is an action that a moderator took when resolving a report.
+type ReportAction string
+
+const (
+	// ReportActionNone means the report was resolved without doing anything else
+	ReportActionNone ReportAction = "none"
+	// ReportActionSilence means the target account was silenced, so its statuses stay off the public timeline
+	ReportActionSilence ReportAction = "silence"
+	// ReportActionSuspend means the target account was suspended and its content removed
+	ReportActionSuspend ReportAction = "suspend"
+	// ReportActionDeleteStatus means the statuses attached to the report were deleted
+	ReportActionDeleteStatus ReportAction = "delete_status"
+)
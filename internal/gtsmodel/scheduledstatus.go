@@ -0,0 +1,55 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// ScheduledStatus is a status that an account has asked to be published at a later time.
+// Once it's due, it's published like any other new status and then removed from the database.
+// If publishing fails, it's tried again after a while, until it's given up on and left for the account to deal with.
+type ScheduledStatus struct {
+	// id of this scheduled status in the database
+	ID string `pg:"type:CHAR(26),pk,notnull,unique"`
+	// When was this scheduled status created
+	CreatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// When was this scheduled status last updated, eg., rescheduled
+	UpdatedAt time.Time `pg:"type:timestamp,notnull,default:now()"`
+	// Which account will the status be published by?
+	AccountID string `pg:"type:CHAR(26),notnull"`
+	// Which application was used to schedule the status? It will be published with this application too.
+	ApplicationID string `pg:"type:CHAR(26)"`
+	// When should the status be published?
+	ScheduledAt time.Time `pg:"type:timestamp,notnull"`
+	// JSON encoding of the status create form that the status will be published with.
+	Params string `pg:",notnull"`
+	// Database IDs of the media attachments that will be attached to the status. These attachments
+	// have their ScheduledStatusID set to the ID of this scheduled status until it's published.
+	MediaAttachments []string `pg:",array"`
+	// How many times have we tried and failed to publish this?
+	Attempts int `pg:",notnull,default:0"`
+	// Error returned by the last failed attempt to publish this
+	LastError string
+	// When did we give up on publishing this, if at all?
+	FailedAt time.Time `pg:"type:timestamp"`
+	// When should publishing this be tried again after a failed attempt?
+	NextAttemptAt time.Time `pg:"type:timestamp"`
+	// ID that the status will be published with. It's stored before each attempt to publish this, so that if the
+	// status was published but this scheduled status couldn't be removed afterwards, it won't be published twice.
+	StatusID string `pg:"type:CHAR(26)"`
+}
@@ -103,6 +103,8 @@ type Status struct {
 	GTSBoostedStatus *Status `pg:"-"`
 	// Account of the boosted status
 	GTSBoostedAccount *Account `pg:"-"`
+	// Poll attached to this status
+	GTSPoll *Poll `pg:"-"`
 }
 
 // Visibility represents the visibility granularity of a status.
@@ -15,8 +15,12 @@ type StreamsForAccount struct {
 type Stream struct {
 	// ID of this stream, generated during creation.
 	ID string
-	// Type of this stream: user/public/etc
+	// Type of this stream: user/public/list/etc
 	Type string
+	// ID of the list this stream is for, only set when Type is list
+	List string
+	// Name of the hashtag this stream is for, only set when Type is hashtag
+	Tag string
 	// Channel of messages for the client to read from
 	Messages chan *Message
 	// Channel to close when the client drops away
@@ -27,6 +31,15 @@ type Stream struct {
 	sync.Mutex
 }
 
+// StreamTypeList is the type of a stream that streams the timeline of one list.
+const StreamTypeList = "list"
+
+// StreamTypeHashtag is the type of a stream that streams public statuses using one hashtag.
+const StreamTypeHashtag = "hashtag"
+
+// StreamTypeDirect is the type of a stream that streams updates to the direct message conversations of an account.
+const StreamTypeDirect = "direct"
+
 // Message represents one streamed message.
 type Message struct {
 	// All the stream types this message should be delivered to.
@@ -0,0 +1,183 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// mp3Bitrates are the layer III bitrates in kbps, indexed by the bitrate bits of a frame header,
+// for MPEG 1 and for MPEG 2 and 2.5 respectively.
+var mp3Bitrates = [2][16]int{
+	{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+}
+
+// mp3SampleRates are the sample rates in Hz, indexed by the version bits and
+// then the sample rate bits of a frame header.
+var mp3SampleRates = [4][3]int{
+	{11025, 12000, 8000},  // MPEG 2.5
+	{0, 0, 0},             // reserved
+	{22050, 24000, 16000}, // MPEG 2
+	{44100, 48000, 32000}, // MPEG 1
+}
+
+// mp3Frame describes an mp3 frame header.
+type mp3Frame struct {
+	length     int
+	samples    int
+	sampleRate int
+}
+
+// readMP3Frame reads the mp3 frame header at the start of b.
+func readMP3Frame(b []byte) (mp3Frame, error) {
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return mp3Frame{}, errors.New("no frame sync")
+	}
+
+	version := (b[1] >> 3) & 0x03
+	layer := (b[1] >> 1) & 0x03
+	bitrateIndex := b[2] >> 4
+	sampleRateIndex := (b[2] >> 2) & 0x03
+	padding := int((b[2] >> 1) & 0x01)
+
+	if version == 1 || layer != 1 || sampleRateIndex == 3 {
+		// reserved version, or not layer III, or reserved sample rate
+		return mp3Frame{}, errors.New("not an mpeg layer III frame")
+	}
+
+	table := 1
+	samples := 576
+	if version == 3 {
+		table = 0
+		samples = 1152
+	}
+	bitrate := mp3Bitrates[table][bitrateIndex] * 1000
+	if bitrate == 0 {
+		return mp3Frame{}, errors.New("free or invalid bitrate")
+	}
+	sampleRate := mp3SampleRates[version][sampleRateIndex]
+
+	return mp3Frame{
+		length:     samples/8*bitrate/sampleRate + padding,
+		samples:    samples,
+		sampleRate: sampleRate,
+	}, nil
+}
+
+// decodeMP3 reads the duration and bitrate of an mp3 file by walking through its frames,
+// and returns just the frames, without any ID3 or other tags before or after them.
+func decodeMP3(b []byte) ([]byte, *audioVisualMeta, error) {
+	start := 0
+	if len(b) >= 10 && bytes.Equal(b[0:3], []byte("ID3")) {
+		// tag size is a 28 bit 'syncsafe' integer, excluding the 10 byte header and optional footer
+		size := int(b[6])<<21 | int(b[7])<<14 | int(b[8])<<7 | int(b[9])
+		start = 10 + size
+		if b[5]&0x10 != 0 {
+			start += 10
+		}
+	}
+
+	var samples float64
+	var sampleRate int
+	end := start
+	for end < len(b) {
+		frame, err := readMP3Frame(b[end:])
+		if err != nil || end+frame.length > len(b) {
+			// anything after the last frame is tags or junk
+			break
+		}
+		samples += float64(frame.samples)
+		sampleRate = frame.sampleRate
+		end += frame.length
+	}
+
+	if end == start {
+		return nil, nil, errors.New("no mp3 frames found")
+	}
+
+	duration := samples / float64(sampleRate)
+	clean := make([]byte, end-start)
+	copy(clean, b[start:end])
+
+	return clean, &audioVisualMeta{
+		hasAudio: true,
+		duration: duration,
+	}, nil
+}
+
+// decodeWav reads the duration of a wav file, and returns a copy of it with only the format and data chunks kept,
+// so without any INFO or other metadata chunks.
+func decodeWav(b []byte) ([]byte, *audioVisualMeta, error) {
+	if len(b) < 12 || !bytes.Equal(b[0:4], []byte("RIFF")) || !bytes.Equal(b[8:12], []byte("WAVE")) {
+		return nil, nil, errors.New("not a wav file")
+	}
+
+	var format []byte
+	var data []byte
+	for offset := 12; offset+8 <= len(b); {
+		chunkID := string(b[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(b[offset+4 : offset+8]))
+		if size > len(b)-offset-8 {
+			return nil, nil, fmt.Errorf("chunk %s at offset %d overflows the file", chunkID, offset)
+		}
+		chunk := b[offset : offset+8+size]
+
+		switch chunkID {
+		case "fmt ":
+			format = chunk
+		case "data":
+			data = chunk
+		}
+
+		// chunks are padded to an even length
+		offset += 8 + size + size%2
+	}
+
+	if format == nil || data == nil || len(format) < 24 {
+		return nil, nil, errors.New("wav file is missing fmt or data chunk")
+	}
+
+	// bytes per second is at offset 8 in the format chunk data
+	byteRate := binary.LittleEndian.Uint32(format[16:20])
+	if byteRate == 0 {
+		return nil, nil, errors.New("wav file has a byte rate of 0")
+	}
+
+	clean := &bytes.Buffer{}
+	clean.WriteString("RIFF")
+	riffSize := make([]byte, 4)
+	binary.LittleEndian.PutUint32(riffSize, uint32(4+len(format)+len(format)%2+len(data)))
+	clean.Write(riffSize)
+	clean.WriteString("WAVE")
+	clean.Write(format)
+	if len(format)%2 != 0 {
+		clean.WriteByte(0)
+	}
+	clean.Write(data)
+
+	return clean.Bytes(), &audioVisualMeta{
+		hasAudio: true,
+		duration: float64(len(data)-8) / float64(byteRate),
+		bitrate:  uint64(byteRate) * 8,
+	}, nil
+}
@@ -0,0 +1,219 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+)
+
+// LastAccessedInterval is how often the last accessed time of an attachment is updated when it's served,
+// so that serving popular media doesn't mean writing to the db for every request.
+const LastAccessedInterval = 1 * time.Hour
+
+// pruneBatch is the number of attachments that PruneRemoteMedia fetches from the db at once.
+const pruneBatch = 100
+
+// PruneRemoteMedia removes the files of cached remote media that hasn't been accessed since olderThan from storage,
+// and marks the attachments as no longer cached. It returns the number of attachments that were pruned.
+func (mh *mediaHandler) PruneRemoteMedia(ctx context.Context, olderThan time.Time) (int, error) {
+	pruned := 0
+	sinceID := ""
+	for {
+		attachments, err := mh.db.GetCachedRemoteMedia(ctx, olderThan, sinceID, pruneBatch)
+		if err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				return pruned, nil
+			}
+			return pruned, fmt.Errorf("error getting cached remote media from the db: %s", err)
+		}
+
+		for _, a := range attachments {
+			// one attachment that can't be pruned shouldn't stop the rest from being pruned, so just page past it
+			if err := mh.uncache(ctx, a); err != nil {
+				mh.log.Errorf("PruneRemoteMedia: %s", err)
+				continue
+			}
+			pruned++
+		}
+		sinceID = attachments[len(attachments)-1].ID
+
+		if len(attachments) < pruneBatch {
+			return pruned, nil
+		}
+	}
+}
+
+// uncache removes the files of the given attachment from storage, and marks it as no longer cached.
+func (mh *mediaHandler) uncache(ctx context.Context, attachment *gtsmodel.MediaAttachment) error {
+	for _, path := range []string{attachment.File.Path, attachment.Thumbnail.Path} {
+		if path == "" {
+			continue
+		}
+		if err := mh.storage.RemoveFileAt(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing file at path %s for attachment %s: %s", path, attachment.ID, err)
+		}
+	}
+
+	attachment.Cached = false
+	if err := mh.db.UpdateByID(ctx, attachment.ID, attachment); err != nil {
+		return fmt.Errorf("error updating attachment %s: %s", attachment.ID, err)
+	}
+	return nil
+}
+
+// RecacheRemoteMedia dereferences the given remote attachment again using the given transport, and stores it at the paths
+// the attachment already has, so that its ID and URLs stay the same. The updated attachment is stored in the database.
+func (mh *mediaHandler) RecacheRemoteMedia(ctx context.Context, t transport.Transport, attachment *gtsmodel.MediaAttachment) error {
+	mh.recacheLocks.Lock(attachment.ID)
+	defer mh.recacheLocks.Unlock(attachment.ID)
+
+	// another request may have recached the attachment while we were waiting,
+	// in which case we just need to catch up with it
+	current := &gtsmodel.MediaAttachment{}
+	if err := mh.db.GetByID(ctx, attachment.ID, current); err != nil {
+		return fmt.Errorf("error getting attachment %s: %s", attachment.ID, err)
+	}
+	if current.Cached {
+		*attachment = *current
+		return nil
+	}
+
+	return mh.recache(ctx, t, attachment)
+}
+
+// recache does the work of RecacheRemoteMedia, and should only be called while holding the lock for the attachment.
+func (mh *mediaHandler) recache(ctx context.Context, t transport.Transport, attachment *gtsmodel.MediaAttachment) error {
+	if attachment.RemoteURL == "" {
+		return errors.New("no remote URL on media attachment to dereference")
+	}
+	remoteIRI, err := url.Parse(attachment.RemoteURL)
+	if err != nil {
+		return fmt.Errorf("error parsing attachment url %s: %s", attachment.RemoteURL, err)
+	}
+
+	// the content type we have is the one we stored the file as, which isn't necessarily what the remote serves
+	attachmentBytes, err := t.DereferenceMedia(ctx, remoteIRI, "*/*")
+	if err != nil {
+		return fmt.Errorf("dereferencing remote media with url %s: %s", remoteIRI.String(), err)
+	}
+
+	// run the bytes through the usual processing, which stores the results under a new id...
+	var fresh *gtsmodel.MediaAttachment
+	if attachment.Header || attachment.Avatar {
+		fresh, err = mh.reprocessHeaderOrAvatar(attachmentBytes, attachment)
+	} else {
+		fresh, err = mh.ProcessAttachment(attachmentBytes, attachment.AccountID, attachment.RemoteURL)
+	}
+	if err != nil {
+		return fmt.Errorf("error processing remote media with url %s: %s", remoteIRI.String(), err)
+	}
+
+	// ...so move the files to where the existing attachment expects them
+	if fresh.Type != attachment.Type {
+		mh.removeFiles(fresh)
+		return fmt.Errorf("remote media with url %s changed type from %s to %s", remoteIRI.String(), attachment.Type, fresh.Type)
+	}
+	if err := mh.moveFile(fresh.File.Path, attachment.File.Path); err != nil {
+		mh.removeFiles(fresh)
+		return err
+	}
+	if err := mh.moveFile(fresh.Thumbnail.Path, attachment.Thumbnail.Path); err != nil {
+		mh.removeFiles(fresh)
+		return err
+	}
+
+	attachment.FileMeta = fresh.FileMeta
+	attachment.Blurhash = fresh.Blurhash
+	attachment.File.ContentType = fresh.File.ContentType
+	attachment.File.FileSize = fresh.File.FileSize
+	attachment.File.UpdatedAt = fresh.File.UpdatedAt
+	attachment.Thumbnail.ContentType = fresh.Thumbnail.ContentType
+	attachment.Thumbnail.FileSize = fresh.Thumbnail.FileSize
+	attachment.Thumbnail.UpdatedAt = fresh.Thumbnail.UpdatedAt
+	attachment.Cached = true
+	attachment.LastAccessedAt = time.Now()
+	attachment.UpdatedAt = time.Now()
+
+	if err := mh.db.UpdateByID(ctx, attachment.ID, attachment); err != nil {
+		return fmt.Errorf("error updating attachment %s: %s", attachment.ID, err)
+	}
+	return nil
+}
+
+// reprocessHeaderOrAvatar processes the given bytes as the header or avatar that the given attachment is used as,
+// without changing the header or avatar of the account.
+func (mh *mediaHandler) reprocessHeaderOrAvatar(attachmentBytes []byte, attachment *gtsmodel.MediaAttachment) (*gtsmodel.MediaAttachment, error) {
+	mediaType := Avatar
+	if attachment.Header {
+		mediaType = Header
+	}
+
+	contentType, err := parseContentType(attachmentBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !SupportedImageType(contentType) {
+		return nil, fmt.Errorf("%s is not an accepted image type", contentType)
+	}
+
+	return mh.processHeaderOrAvi(attachmentBytes, contentType, mediaType, attachment.AccountID, attachment.RemoteURL)
+}
+
+// moveFile moves the file at path from to path to in storage, streaming it across rather than reading it all into memory.
+func (mh *mediaHandler) moveFile(from string, to string) error {
+	info, err := mh.storage.StatFile(from)
+	if err != nil {
+		return fmt.Errorf("error getting info of file at path %s: %s", from, err)
+	}
+
+	f, err := mh.storage.OpenFile(from)
+	if err != nil {
+		return fmt.Errorf("error opening file at path %s: %s", from, err)
+	}
+	if err := mh.storage.StoreFileFrom(to, f, info.Size); err != nil {
+		f.Close()
+		return fmt.Errorf("error storing file at path %s: %s", to, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error closing file at path %s: %s", from, err)
+	}
+
+	if err := mh.storage.RemoveFileAt(from); err != nil {
+		return fmt.Errorf("error removing file at path %s: %s", from, err)
+	}
+	return nil
+}
+
+// removeFiles removes any files of the given attachment that are still in storage, logging rather than returning errors.
+func (mh *mediaHandler) removeFiles(attachment *gtsmodel.MediaAttachment) {
+	for _, path := range []string{attachment.File.Path, attachment.Thumbnail.Path} {
+		if err := mh.storage.RemoveFileAt(path); err != nil && !os.IsNotExist(err) {
+			mh.log.Errorf("removeFiles: error removing file at path %s: %s", path, err)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// cleanupBatch is the number of attachments that CleanupMedia fetches from the db at once.
+const cleanupBatch = 100
+
+// CleanupResult reports what CleanupMedia removed, or would have removed in case of a dry run.
+type CleanupResult struct {
+	// Unattached is the number of attachments that weren't attached to a status, scheduled status, or account.
+	Unattached int
+	// Orphaned is the number of attachments whose account, status or scheduled status no longer exists.
+	Orphaned int
+	// OrphanedFiles is the number of files in storage that didn't belong to any attachment or emoji.
+	OrphanedFiles int
+	// Bytes is the total size of the files that were removed from storage.
+	Bytes int64
+}
+
+// mediaCleanup holds the state of one run of CleanupMedia, so that accounts and statuses shared by
+// many attachments only have to be looked up once.
+type mediaCleanup struct {
+	mh        *mediaHandler
+	olderThan time.Time
+	dryRun    bool
+
+	// accounts by ID, nil if the account doesn't exist
+	accounts map[string]*gtsmodel.Account
+	// whether statuses and scheduled statuses exist, by ID
+	statuses          map[string]bool
+	scheduledStatuses map[string]bool
+	// cleaned storage paths of all attachments and emojis that are in the db
+	knownPaths map[string]bool
+
+	result *CleanupResult
+}
+
+// CleanupMedia removes attachments that aren't attached to anything, attachments whose account, status or scheduled status
+// no longer exists, and files in storage that don't belong to any attachment or emoji. Only media that hasn't been updated
+// since olderThan is removed. If dryRun is true, nothing is removed, but the result reports what would have been.
+func (mh *mediaHandler) CleanupMedia(ctx context.Context, olderThan time.Time, dryRun bool) (*CleanupResult, error) {
+	c := &mediaCleanup{
+		mh:                mh,
+		olderThan:         olderThan,
+		dryRun:            dryRun,
+		accounts:          make(map[string]*gtsmodel.Account),
+		statuses:          make(map[string]bool),
+		scheduledStatuses: make(map[string]bool),
+		knownPaths:        make(map[string]bool),
+		result:            &CleanupResult{},
+	}
+
+	if err := c.cleanupAttachments(ctx); err != nil {
+		return c.result, err
+	}
+
+	// files can only be recognized as orphaned once we know the paths of everything in the db
+	emojis := []*gtsmodel.Emoji{}
+	if err := mh.db.GetAll(ctx, &emojis); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return c.result, fmt.Errorf("error getting emojis from the db: %s", err)
+		}
+	}
+	for _, e := range emojis {
+		c.know(e.ImagePath)
+		c.know(e.ImageStaticPath)
+	}
+
+	if err := c.cleanupFiles(); err != nil {
+		return c.result, err
+	}
+
+	return c.result, nil
+}
+
+// cleanupAttachments pages through all attachments in the db, removing the ones that are unattached or orphaned.
+func (c *mediaCleanup) cleanupAttachments(ctx context.Context) error {
+	maxID := ""
+	for {
+		attachments, err := c.mh.db.GetMediaAttachments(ctx, maxID, cleanupBatch)
+		if err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				return nil
+			}
+			return fmt.Errorf("error getting attachments from the db: %s", err)
+		}
+
+		for _, a := range attachments {
+			// even if the attachment is removed, its files shouldn't be counted again as orphaned files
+			c.know(a.File.Path)
+			c.know(a.Thumbnail.Path)
+
+			if !a.UpdatedAt.Before(c.olderThan) {
+				// attachments are put in the db before the statuses they belong to, so give everything some time to settle
+				continue
+			}
+
+			orphaned, unattached, err := c.check(ctx, a)
+			if err != nil {
+				return err
+			}
+			if !orphaned && !unattached {
+				continue
+			}
+
+			if err := c.removeAttachment(ctx, a); err != nil {
+				return err
+			}
+			if orphaned {
+				c.result.Orphaned++
+			} else {
+				c.result.Unattached++
+			}
+		}
+
+		if len(attachments) < cleanupBatch {
+			return nil
+		}
+		maxID = attachments[len(attachments)-1].ID
+	}
+}
+
+// check returns whether the given attachment is orphaned, meaning that its account, status or scheduled status no longer exists,
+// or unattached, meaning that it's local media that isn't used by any status, or a header or avatar that's no longer in use.
+func (c *mediaCleanup) check(ctx context.Context, a *gtsmodel.MediaAttachment) (orphaned bool, unattached bool, err error) {
+	account, err := c.account(ctx, a.AccountID)
+	if err != nil {
+		return false, false, err
+	}
+	if account == nil {
+		return true, false, nil
+	}
+
+	if a.StatusID != "" {
+		exists, err := c.exists(ctx, c.statuses, a.StatusID, &gtsmodel.Status{})
+		return !exists, false, err
+	}
+
+	if a.ScheduledStatusID != "" {
+		// media of a scheduled status becomes attached to the status once it's published, so it's not unattached in the meantime
+		exists, err := c.exists(ctx, c.scheduledStatuses, a.ScheduledStatusID, &gtsmodel.ScheduledStatus{})
+		return !exists, false, err
+	}
+
+	switch {
+	case a.Avatar:
+		return false, account.AvatarMediaAttachmentID != a.ID, nil
+	case a.Header:
+		return false, account.HeaderMediaAttachmentID != a.ID, nil
+	}
+
+	// remote media is always put in the db along with the status it's attached to
+	return false, a.RemoteURL == "", nil
+}
+
+// account returns the account with the given ID, or nil if it doesn't exist.
+func (c *mediaCleanup) account(ctx context.Context, id string) (*gtsmodel.Account, error) {
+	if account, ok := c.accounts[id]; ok {
+		return account, nil
+	}
+
+	account := &gtsmodel.Account{}
+	if err := c.mh.db.GetByID(ctx, id, account); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, fmt.Errorf("error getting account %s from the db: %s", id, err)
+		}
+		account = nil
+	}
+
+	c.accounts[id] = account
+	return account, nil
+}
+
+// exists returns whether i with the given ID exists in the db, using and updating the given cache.
+func (c *mediaCleanup) exists(ctx context.Context, cache map[string]bool, id string, i interface{}) (bool, error) {
+	if exists, ok := cache[id]; ok {
+		return exists, nil
+	}
+
+	exists := true
+	if err := c.mh.db.GetByID(ctx, id, i); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return false, fmt.Errorf("error getting %T %s from the db: %s", i, id, err)
+		}
+		exists = false
+	}
+
+	cache[id] = exists
+	return exists, nil
+}
+
+// removeAttachment removes the files of the given attachment from storage, and then the attachment itself from the db.
+func (c *mediaCleanup) removeAttachment(ctx context.Context, a *gtsmodel.MediaAttachment) error {
+	for _, p := range []string{a.File.Path, a.Thumbnail.Path} {
+		if p == "" {
+			continue
+		}
+		if err := c.removeFile(p); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing file at path %s for attachment %s: %s", p, a.ID, err)
+		}
+	}
+
+	if c.dryRun {
+		return nil
+	}
+	if err := c.mh.db.DeleteByID(ctx, a.ID, &gtsmodel.MediaAttachment{}); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return fmt.Errorf("error removing attachment %s from the db: %s", a.ID, err)
+		}
+	}
+	return nil
+}
+
+// cleanupFiles removes files from storage that look like media, but don't belong to any attachment or emoji in the db.
+func (c *mediaCleanup) cleanupFiles() error {
+	keys, err := c.mh.storage.ListKeys()
+	if err != nil {
+		return fmt.Errorf("error listing files in storage: %s", err)
+	}
+
+	for _, key := range keys {
+		if c.knownPaths[path.Clean(key)] || !c.isMediaPath(key) {
+			continue
+		}
+
+		info, err := c.mh.storage.StatFile(key)
+		if err != nil {
+			return fmt.Errorf("error getting info of file at path %s: %s", key, err)
+		}
+		if info.ModTime.IsZero() || !info.ModTime.Before(c.olderThan) {
+			// the file might belong to media that's being processed right now and isn't in the db yet
+			continue
+		}
+
+		if err := c.removeFile(key); err != nil {
+			return fmt.Errorf("error removing file at path %s: %s", key, err)
+		}
+		c.result.OrphanedFiles++
+	}
+	return nil
+}
+
+// removeFile removes the file at the given path from storage, counting its size towards the result.
+func (c *mediaCleanup) removeFile(p string) error {
+	// the size is only used for reporting, so a failed stat shouldn't stop the file from being removed
+	var size int64
+	if info, err := c.mh.storage.StatFile(p); err == nil {
+		size = info.Size
+	}
+
+	if !c.dryRun {
+		if err := c.mh.storage.RemoveFileAt(p); err != nil {
+			return err
+		}
+	}
+	c.result.Bytes += size
+	return nil
+}
+
+// isMediaPath returns whether the given storage path is laid out like the path of an attachment or emoji,
+// ie., [BASE_PATH]/[ACCOUNT_ID]/[MEDIA_TYPE]/[MEDIA_SIZE]/[FILE_NAME], so that other files are never removed.
+func (c *mediaCleanup) isMediaPath(p string) bool {
+	base := strings.TrimSuffix(path.Clean(c.mh.config.StorageConfig.BasePath), "/") + "/"
+	p = path.Clean(p)
+	if !strings.HasPrefix(p, base) {
+		return false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(p, base), "/")
+	if len(parts) != 4 || parts[0] == "" || parts[3] == "" {
+		return false
+	}
+	if _, err := ParseMediaType(parts[1]); err != nil {
+		return false
+	}
+	if _, err := ParseMediaSize(parts[2]); err != nil {
+		return false
+	}
+	return true
+}
+
+// know records the given storage path as belonging to something in the db.
+func (c *mediaCleanup) know(p string) {
+	if p != "" {
+		c.knownPaths[path.Clean(p)] = true
+	}
+}
@@ -0,0 +1,190 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/blob"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type CleanupTestSuite struct {
+	suite.Suite
+	db              db.DB
+	storage         blob.Storage
+	mediaHandler    media.Handler
+	testAccounts    map[string]*gtsmodel.Account
+	testAttachments map[string]*gtsmodel.MediaAttachment
+}
+
+func (suite *CleanupTestSuite) SetupTest() {
+	suite.db = testrig.NewTestDB()
+	testrig.StandardDBSetup(suite.db)
+	suite.storage = testrig.NewTestStorage()
+	testrig.StandardStorageSetup(suite.storage, "../../testrig/media")
+	suite.mediaHandler = testrig.NewTestMediaHandler(suite.db, suite.storage)
+	suite.testAccounts = testrig.NewTestAccounts()
+	suite.testAttachments = testrig.NewTestAttachments()
+}
+
+func (suite *CleanupTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+	testrig.StandardStorageTeardown(suite.storage)
+}
+
+// attachment stores a copy of the given test attachment's files under a new id, and puts it in the db after letting modify change it.
+func (suite *CleanupTestSuite) attachment(id string, from string, modify func(a *gtsmodel.MediaAttachment)) *gtsmodel.MediaAttachment {
+	a := *suite.testAttachments[from]
+	a.ID = id
+	a.File.Path = "/gotosocial/storage/" + a.AccountID + "/attachment/original/" + id + ".jpeg"
+	a.Thumbnail.Path = "/gotosocial/storage/" + a.AccountID + "/attachment/small/" + id + ".jpeg"
+	modify(&a)
+
+	b, err := suite.storage.RetrieveFileFrom(suite.testAttachments[from].File.Path)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.storage.StoreFileAt(a.File.Path, b))
+	suite.Require().NoError(suite.storage.StoreFileAt(a.Thumbnail.Path, b))
+	suite.Require().NoError(suite.db.Put(context.Background(), &a))
+	return &a
+}
+
+func (suite *CleanupTestSuite) exists(a *gtsmodel.MediaAttachment) bool {
+	err := suite.db.GetByID(context.Background(), a.ID, &gtsmodel.MediaAttachment{})
+	if _, ok := err.(db.ErrNoEntries); ok {
+		_, fileErr := suite.storage.RetrieveFileFrom(a.File.Path)
+		suite.Error(fileErr)
+		_, thumbnailErr := suite.storage.RetrieveFileFrom(a.Thumbnail.Path)
+		suite.Error(thumbnailErr)
+		return false
+	}
+	suite.NoError(err)
+	return true
+}
+
+func (suite *CleanupTestSuite) TestCleanupMedia() {
+	ctx := context.Background()
+	old := time.Now().Add(-48 * time.Hour)
+
+	unattached := suite.attachment("01FGKBK4S6HHYJ8K5QW9Y1Z2AB", "local_account_1_unattached_1", func(a *gtsmodel.MediaAttachment) {
+		a.UpdatedAt = old
+	})
+	recentlyUploaded := suite.attachment("01FGKBK4S6HHYJ8K5QW9Y1Z2AC", "local_account_1_unattached_1", func(a *gtsmodel.MediaAttachment) {
+		a.UpdatedAt = time.Now()
+	})
+	statusGone := suite.attachment("01FGKBK4S6HHYJ8K5QW9Y1Z2AD", "admin_account_status_1_attachment_1", func(a *gtsmodel.MediaAttachment) {
+		a.StatusID = "01FGKBN1AV0E6S0TTRN8QMZ1XR"
+		a.UpdatedAt = old
+	})
+	oldAvatar := suite.attachment("01FGKBK4S6HHYJ8K5QW9Y1Z2AE", "local_account_1_avatar", func(a *gtsmodel.MediaAttachment) {
+		a.UpdatedAt = old
+	})
+
+	// media of a scheduled status isn't attached to a status yet, but it will be once the status is published
+	scheduledStatus := &gtsmodel.ScheduledStatus{
+		ID:               "01FGKBQ3WYB8C4G0KX6Z2RWTNE",
+		AccountID:        suite.testAccounts["local_account_1"].ID,
+		ScheduledAt:      time.Now().Add(time.Hour),
+		Params:           "{}",
+		MediaAttachments: []string{"01FGKBK4S6HHYJ8K5QW9Y1Z2AF"},
+	}
+	suite.Require().NoError(suite.db.Put(ctx, scheduledStatus))
+	scheduled := suite.attachment("01FGKBK4S6HHYJ8K5QW9Y1Z2AF", "local_account_1_unattached_1", func(a *gtsmodel.MediaAttachment) {
+		a.ScheduledStatusID = scheduledStatus.ID
+		a.UpdatedAt = old
+	})
+	scheduledGone := suite.attachment("01FGKBK4S6HHYJ8K5QW9Y1Z2AG", "local_account_1_unattached_1", func(a *gtsmodel.MediaAttachment) {
+		a.ScheduledStatusID = "01FGKBT0M4VSEAXJ8T6GFZ9P1C"
+		a.UpdatedAt = old
+	})
+
+	// a file left behind by processing that failed, and a file that has nothing to do with media
+	orphanedFile := "/gotosocial/storage/" + suite.testAccounts["local_account_1"].ID + "/attachment/original/01FGKBWXJ3DZ3P0A7K8YQ2N5HS.jpeg"
+	suite.Require().NoError(suite.storage.StoreFileAt(orphanedFile, []byte("some bytes")))
+	otherFile := "/gotosocial/storage/README.txt"
+	suite.Require().NoError(suite.storage.StoreFileAt(otherFile, []byte("don't touch me")))
+
+	// files are only removed once they're older than the grace period, and the ones we just stored aren't
+	result, err := suite.mediaHandler.CleanupMedia(ctx, time.Now().Add(-24*time.Hour), false)
+	suite.NoError(err)
+	suite.Equal(2, result.Unattached)
+	suite.Equal(2, result.Orphaned)
+	suite.Zero(result.OrphanedFiles)
+	suite.NotZero(result.Bytes)
+
+	suite.False(suite.exists(unattached))
+	suite.False(suite.exists(oldAvatar))
+	suite.False(suite.exists(statusGone))
+	suite.False(suite.exists(scheduledGone))
+	suite.True(suite.exists(recentlyUploaded))
+	suite.True(suite.exists(scheduled))
+	for _, a := range suite.testAttachments {
+		suite.True(suite.exists(a))
+	}
+
+	// with a grace period that's passed for everything, the orphaned file goes too
+	result, err = suite.mediaHandler.CleanupMedia(ctx, time.Now().Add(time.Minute), false)
+	suite.NoError(err)
+	suite.Equal(1, result.OrphanedFiles)
+
+	_, err = suite.storage.RetrieveFileFrom(orphanedFile)
+	suite.Error(err)
+	_, err = suite.storage.RetrieveFileFrom(otherFile)
+	suite.NoError(err)
+
+	// the recently uploaded attachment and the unattached test attachment have been unattached for long enough by now too
+	suite.Equal(2, result.Unattached)
+	suite.Zero(result.Orphaned)
+	suite.False(suite.exists(recentlyUploaded))
+	suite.False(suite.exists(suite.testAttachments["local_account_1_unattached_1"]))
+	suite.True(suite.exists(suite.testAttachments["local_account_1_avatar"]))
+	suite.True(suite.exists(scheduled))
+}
+
+func (suite *CleanupTestSuite) TestCleanupMediaDryRun() {
+	ctx := context.Background()
+	unattached := suite.attachment("01FGKBK4S6HHYJ8K5QW9Y1Z2AB", "local_account_1_unattached_1", func(a *gtsmodel.MediaAttachment) {
+		a.UpdatedAt = time.Now().Add(-48 * time.Hour)
+	})
+	orphanedFile := "/gotosocial/storage/" + suite.testAccounts["local_account_1"].ID + "/attachment/original/01FGKBWXJ3DZ3P0A7K8YQ2N5HS.jpeg"
+	suite.Require().NoError(suite.storage.StoreFileAt(orphanedFile, []byte("some bytes")))
+
+	result, err := suite.mediaHandler.CleanupMedia(ctx, time.Now().Add(time.Minute), true)
+	suite.NoError(err)
+	suite.Equal(1, result.OrphanedFiles)
+	suite.NotZero(result.Bytes)
+	suite.NotZero(result.Unattached)
+
+	// nothing should actually have been removed
+	suite.True(suite.exists(unattached))
+	_, err = suite.storage.RetrieveFileFrom(unattached.File.Path)
+	suite.NoError(err)
+	_, err = suite.storage.RetrieveFileFrom(orphanedFile)
+	suite.NoError(err)
+}
+
+func TestCleanupTestSuite(t *testing.T) {
+	suite.Run(t, new(CleanupTestSuite))
+}
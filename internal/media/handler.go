@@ -67,7 +67,7 @@ type Handler interface {
 	// ProcessHeaderOrAvatar takes a new header image for an account, checks it out, removes exif data from it,
 	// puts it in whatever storage backend we're using, sets the relevant fields in the database for the new image,
 	// and then returns information to the caller about the new header.
-	ProcessHeaderOrAvatar(attachment []byte, accountID string, mediaType Type, remoteURL string) (*gtsmodel.MediaAttachment, error)
+	ProcessHeaderOrAvatar(ctx context.Context, attachment []byte, accountID string, mediaType Type, remoteURL string) (*gtsmodel.MediaAttachment, error)
 
 	// ProcessLocalAttachment takes a new attachment and the requesting account, checks it out, removes exif data from it,
 	// puts it in whatever storage backend we're using, sets the relevant fields in the database for the new media,
@@ -78,7 +78,7 @@ type Handler interface {
 	// ProcessLocalEmoji takes a new emoji and a shortcode, cleans it up, puts it in storage, and creates a new
 	// *gts.Emoji for it, then returns it to the caller. It's the caller's responsibility to put the returned struct
 	// in the database.
-	ProcessLocalEmoji(emojiBytes []byte, shortcode string) (*gtsmodel.Emoji, error)
+	ProcessLocalEmoji(ctx context.Context, emojiBytes []byte, shortcode string) (*gtsmodel.Emoji, error)
 
 	// ProcessRemoteAttachment takes a transport, a bare-bones current attachment, and an accountID that the attachment belongs to.
 	// It then dereferences the attachment (ie., fetches the attachment bytes from the remote server), ensuring that the bytes are
@@ -87,23 +87,38 @@ type Handler interface {
 	// in the database.
 	ProcessRemoteAttachment(t transport.Transport, currentAttachment *gtsmodel.MediaAttachment, accountID string) (*gtsmodel.MediaAttachment, error)
 
-	ProcessRemoteHeaderOrAvatar(t transport.Transport, currentAttachment *gtsmodel.MediaAttachment, accountID string) (*gtsmodel.MediaAttachment, error)
+	ProcessRemoteHeaderOrAvatar(ctx context.Context, t transport.Transport, currentAttachment *gtsmodel.MediaAttachment, accountID string) (*gtsmodel.MediaAttachment, error)
+
+	// PruneRemoteMedia removes the files of cached remote media that hasn't been accessed since olderThan from storage,
+	// and marks the attachments as no longer cached. It returns the number of attachments that were pruned.
+	PruneRemoteMedia(ctx context.Context, olderThan time.Time) (int, error)
+
+	// RecacheRemoteMedia dereferences the given remote attachment again using the given transport, and stores it at the paths
+	// the attachment already has, so that its ID and URLs stay the same. The updated attachment is stored in the database.
+	RecacheRemoteMedia(ctx context.Context, t transport.Transport, attachment *gtsmodel.MediaAttachment) error
+
+	// CleanupMedia removes attachments that aren't attached to anything, attachments whose account, status or scheduled status
+	// no longer exists, and files in storage that don't belong to any attachment or emoji. Only media that hasn't been updated
+	// since olderThan is removed. If dryRun is true, nothing is removed, but the result reports what would have been.
+	CleanupMedia(ctx context.Context, olderThan time.Time, dryRun bool) (*CleanupResult, error)
 }
 
 type mediaHandler struct {
-	config  *config.Config
-	db      db.DB
-	storage blob.Storage
-	log     *logrus.Logger
+	config       *config.Config
+	db           db.DB
+	storage      blob.Storage
+	log          *logrus.Logger
+	recacheLocks *keyedMutex // mutexes by attachment ID, so that the same attachment isn't recached by several requests at once
 }
 
 // New returns a new handler with the given config, db, storage, and logger
 func New(config *config.Config, database db.DB, storage blob.Storage, log *logrus.Logger) Handler {
 	return &mediaHandler{
-		config:  config,
-		db:      database,
-		storage: storage,
-		log:     log,
+		config:       config,
+		db:           database,
+		storage:      storage,
+		log:          log,
+		recacheLocks: newKeyedMutex(),
 	}
 }
 
@@ -114,7 +129,7 @@ func New(config *config.Config, database db.DB, storage blob.Storage, log *logru
 // ProcessHeaderOrAvatar takes a new header image for an account, checks it out, removes exif data from it,
 // puts it in whatever storage backend we're using, sets the relevant fields in the database for the new image,
 // and then returns information to the caller about the new header.
-func (mh *mediaHandler) ProcessHeaderOrAvatar(attachment []byte, accountID string, mediaType Type, remoteURL string) (*gtsmodel.MediaAttachment, error) {
+func (mh *mediaHandler) ProcessHeaderOrAvatar(ctx context.Context, attachment []byte, accountID string, mediaType Type, remoteURL string) (*gtsmodel.MediaAttachment, error) {
 	l := mh.log.WithField("func", "SetHeaderForAccountID")
 
 	if mediaType != Header && mediaType != Avatar {
@@ -142,7 +157,7 @@ func (mh *mediaHandler) ProcessHeaderOrAvatar(attachment []byte, accountID strin
 	}
 
 	// set it in the database
-	if err := mh.db.SetHeaderOrAvatarForAccountID(ma, accountID); err != nil {
+	if err := mh.db.SetHeaderOrAvatarForAccountID(ctx, ma, accountID); err != nil {
 		return nil, fmt.Errorf("error putting %s in database: %s", mediaType, err)
 	}
 
@@ -159,14 +174,28 @@ func (mh *mediaHandler) ProcessAttachment(attachment []byte, accountID string, r
 	}
 	mainType := strings.Split(contentType, "/")[0]
 	switch mainType {
-	// case MIMEVideo:
-	// 	if !SupportedVideoType(contentType) {
-	// 		return nil, fmt.Errorf("video type %s not supported", contentType)
-	// 	}
-	// 	if len(attachment) == 0 {
-	// 		return nil, errors.New("video was of size 0")
-	// 	}
-	// 	return mh.processVideoAttachment(attachment, accountID, contentType, remoteURL)
+	case MIMEVideo:
+		if !SupportedVideoType(contentType) {
+			return nil, fmt.Errorf("video type %s not supported", contentType)
+		}
+		if len(attachment) == 0 {
+			return nil, errors.New("video was of size 0")
+		}
+		if len(attachment) > mh.config.MediaConfig.MaxVideoSize {
+			return nil, fmt.Errorf("video size %d bytes exceeded max video size of %d bytes", len(attachment), mh.config.MediaConfig.MaxVideoSize)
+		}
+		return mh.processVideoAttachment(attachment, accountID, contentType, remoteURL)
+	case MIMEAudio:
+		if !SupportedAudioType(contentType) {
+			return nil, fmt.Errorf("audio type %s not supported", contentType)
+		}
+		if len(attachment) == 0 {
+			return nil, errors.New("audio was of size 0")
+		}
+		if len(attachment) > mh.config.MediaConfig.MaxVideoSize {
+			return nil, fmt.Errorf("audio size %d bytes exceeded max video size of %d bytes", len(attachment), mh.config.MediaConfig.MaxVideoSize)
+		}
+		return mh.processAudioAttachment(attachment, accountID, contentType, remoteURL)
 	case MIMEImage:
 		if !SupportedImageType(contentType) {
 			return nil, fmt.Errorf("image type %s not supported", contentType)
@@ -184,7 +213,7 @@ func (mh *mediaHandler) ProcessAttachment(attachment []byte, accountID string, r
 // ProcessLocalEmoji takes a new emoji and a shortcode, cleans it up, puts it in storage, and creates a new
 // *gts.Emoji for it, then returns it to the caller. It's the caller's responsibility to put the returned struct
 // in the database.
-func (mh *mediaHandler) ProcessLocalEmoji(emojiBytes []byte, shortcode string) (*gtsmodel.Emoji, error) {
+func (mh *mediaHandler) ProcessLocalEmoji(ctx context.Context, emojiBytes []byte, shortcode string) (*gtsmodel.Emoji, error) {
 	var clean []byte
 	var err error
 	var original *imageAndMeta
@@ -232,7 +261,7 @@ func (mh *mediaHandler) ProcessLocalEmoji(emojiBytes []byte, shortcode string) (
 	// (ie., fileserver/ACCOUNT_ID/etc etc) we need to fetch the INSTANCE ACCOUNT from the database. That is, the account that's created
 	// with the same username as the instance hostname, which doesn't belong to any particular user.
 	instanceAccount := &gtsmodel.Account{}
-	if err := mh.db.GetLocalAccountByUsername(mh.config.Host, instanceAccount); err != nil {
+	if err := mh.db.GetLocalAccountByUsername(ctx, mh.config.Host, instanceAccount); err != nil {
 		return nil, fmt.Errorf("error fetching instance account: %s", err)
 	}
 
@@ -320,7 +349,7 @@ func (mh *mediaHandler) ProcessRemoteAttachment(t transport.Transport, currentAt
 	return mh.ProcessAttachment(attachmentBytes, accountID, currentAttachment.RemoteURL)
 }
 
-func (mh *mediaHandler) ProcessRemoteHeaderOrAvatar(t transport.Transport, currentAttachment *gtsmodel.MediaAttachment, accountID string) (*gtsmodel.MediaAttachment, error) {
+func (mh *mediaHandler) ProcessRemoteHeaderOrAvatar(ctx context.Context, t transport.Transport, currentAttachment *gtsmodel.MediaAttachment, accountID string) (*gtsmodel.MediaAttachment, error) {
 
 	if !currentAttachment.Header && !currentAttachment.Avatar {
 		return nil, errors.New("provided attachment was set to neither header nor avatar")
@@ -357,5 +386,5 @@ func (mh *mediaHandler) ProcessRemoteHeaderOrAvatar(t transport.Transport, curre
 		return nil, fmt.Errorf("dereferencing remote media with url %s: %s", remoteIRI.String(), err)
 	}
 
-	return mh.ProcessHeaderOrAvatar(attachmentBytes, accountID, headerOrAvi, currentAttachment.RemoteURL)
+	return mh.ProcessHeaderOrAvatar(ctx, attachmentBytes, accountID, headerOrAvi, currentAttachment.RemoteURL)
 }
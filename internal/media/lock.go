@@ -0,0 +1,70 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import "sync"
+
+// keyedMutex hands out one mutex per key, and forgets about the mutex for a key again once nobody
+// is holding or waiting for it, so that it doesn't grow with every key that has ever been locked.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*refMutex
+}
+
+// refMutex is a mutex along with the number of callers holding or waiting for it.
+type refMutex struct {
+	sync.Mutex
+	refs int
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{
+		locks: make(map[string]*refMutex),
+	}
+}
+
+// Lock locks the mutex for the given key, blocking until it's available.
+func (k *keyedMutex) Lock(key string) {
+	k.mu.Lock()
+	l, ok := k.locks[key]
+	if !ok {
+		l = &refMutex{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	l.Lock()
+}
+
+// Unlock unlocks the mutex for the given key, which must have been locked with Lock.
+func (k *keyedMutex) Unlock(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	l, ok := k.locks[key]
+	if !ok {
+		panic("unlock of unlocked key " + key)
+	}
+	l.refs--
+	if l.refs == 0 {
+		delete(k.locks, key)
+	}
+	l.Unlock()
+}
@@ -0,0 +1,71 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyedMutexTestSuite struct {
+	suite.Suite
+}
+
+func (suite *KeyedMutexTestSuite) TestLockSameKey() {
+	k := newKeyedMutex()
+	counter := 0
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			k.Lock("01F8MH4YNAPMWE6ND06X4TPHTF")
+			defer k.Unlock("01F8MH4YNAPMWE6ND06X4TPHTF")
+			// not atomic, so this only adds up if the lock holds
+			c := counter
+			counter = c + 1
+		}()
+	}
+	wg.Wait()
+
+	suite.Equal(50, counter)
+	// nobody is holding or waiting for the lock anymore, so it should be gone
+	suite.Empty(k.locks)
+}
+
+func (suite *KeyedMutexTestSuite) TestLockDifferentKeys() {
+	k := newKeyedMutex()
+
+	// holding the lock for one key doesn't block another
+	k.Lock("01F8MH4YNAPMWE6ND06X4TPHTF")
+	k.Lock("01F8MH6NEM2ZHAS49ZJCR1DKZS")
+	suite.Len(k.locks, 2)
+
+	k.Unlock("01F8MH6NEM2ZHAS49ZJCR1DKZS")
+	suite.Len(k.locks, 1)
+	k.Unlock("01F8MH4YNAPMWE6ND06X4TPHTF")
+	suite.Empty(k.locks)
+}
+
+func TestKeyedMutexTestSuite(t *testing.T) {
+	suite.Run(t, new(KeyedMutexTestSuite))
+}
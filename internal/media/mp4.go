@@ -0,0 +1,298 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// mp4Box is a box (atom) in an ISO base media file, such as an mp4, mov, or m4a file.
+type mp4Box struct {
+	boxType string
+	// start is the offset of the box header in the file
+	start int
+	// payload is the offset of the box contents in the file
+	payload int
+	// end is the offset of the first byte after the box
+	end int
+}
+
+// mp4MetadataBoxes are the box types that contain descriptive metadata like titles, locations,
+// cover art, and the software or device used to create the file.
+var mp4MetadataBoxes = map[string]bool{
+	"udta": true,
+	"meta": true,
+	"uuid": true,
+}
+
+// readMP4Boxes reads the boxes in b between start and end.
+func readMP4Boxes(b []byte, start int, end int) ([]mp4Box, error) {
+	boxes := []mp4Box{}
+	for offset := start; offset < end; {
+		if end-offset < 8 {
+			return nil, fmt.Errorf("truncated box header at offset %d", offset)
+		}
+
+		size := int(binary.BigEndian.Uint32(b[offset : offset+4]))
+		box := mp4Box{
+			boxType: string(b[offset+4 : offset+8]),
+			start:   offset,
+			payload: offset + 8,
+		}
+
+		switch size {
+		case 0:
+			// box extends to the end of the file
+			size = end - offset
+		case 1:
+			// 64 bit size follows the box type
+			if end-offset < 16 {
+				return nil, fmt.Errorf("truncated box header at offset %d", offset)
+			}
+			largeSize := binary.BigEndian.Uint64(b[offset+8 : offset+16])
+			if largeSize > uint64(end-offset) {
+				return nil, fmt.Errorf("box %s at offset %d overflows its parent", box.boxType, offset)
+			}
+			size = int(largeSize)
+			box.payload = offset + 16
+		}
+
+		if size < box.payload-offset || size > end-offset {
+			return nil, fmt.Errorf("box %s at offset %d has invalid size %d", box.boxType, offset, size)
+		}
+
+		box.end = offset + size
+		boxes = append(boxes, box)
+		offset = box.end
+	}
+	return boxes, nil
+}
+
+// findMP4Box returns the first box of the given type, or nil if there is none.
+func findMP4Box(boxes []mp4Box, boxType string) *mp4Box {
+	for i := range boxes {
+		if boxes[i].boxType == boxType {
+			return &boxes[i]
+		}
+	}
+	return nil
+}
+
+// mp4Duration reads the timescale and duration from an mvhd or mdhd box payload,
+// and returns the duration in seconds.
+func mp4Duration(payload []byte) (float64, error) {
+	if len(payload) < 4 {
+		return 0, errors.New("header box too short")
+	}
+
+	var timescale uint32
+	var duration uint64
+	if payload[0] == 1 {
+		// version 1: 64 bit creation/modification times and duration
+		if len(payload) < 32 {
+			return 0, errors.New("header box too short")
+		}
+		timescale = binary.BigEndian.Uint32(payload[20:24])
+		duration = binary.BigEndian.Uint64(payload[24:32])
+	} else {
+		if len(payload) < 20 {
+			return 0, errors.New("header box too short")
+		}
+		timescale = binary.BigEndian.Uint32(payload[12:16])
+		duration = uint64(binary.BigEndian.Uint32(payload[16:20]))
+	}
+
+	if timescale == 0 {
+		return 0, errors.New("header box has a timescale of 0")
+	}
+	return float64(duration) / float64(timescale), nil
+}
+
+// mp4ClearTimes zeroes the creation and modification times in an mvhd, tkhd, or mdhd box payload.
+func mp4ClearTimes(payload []byte) {
+	if len(payload) < 4 {
+		return
+	}
+	timesLen := 8
+	if payload[0] == 1 {
+		timesLen = 16
+	}
+	if len(payload) < 4+timesLen {
+		return
+	}
+	for i := 4; i < 4+timesLen; i++ {
+		payload[i] = 0
+	}
+}
+
+// decodeMP4 reads the duration, dimensions, and frame rate of an mp4 (or mov or m4a) file,
+// and returns a copy of the file with descriptive metadata and creation times wiped.
+//
+// Metadata boxes are overwritten with free boxes of the same size rather than removed,
+// so that the chunk offsets pointing into the media data stay valid.
+func decodeMP4(b []byte) ([]byte, *audioVisualMeta, error) {
+	clean := make([]byte, len(b))
+	copy(clean, b)
+
+	boxes, err := readMP4Boxes(clean, 0, len(clean))
+	if err != nil {
+		return nil, nil, err
+	}
+	wipeMP4Metadata(clean, boxes)
+
+	moov := findMP4Box(boxes, "moov")
+	if moov == nil {
+		return nil, nil, errors.New("no moov box found")
+	}
+	moovBoxes, err := readMP4Boxes(clean, moov.payload, moov.end)
+	if err != nil {
+		return nil, nil, err
+	}
+	wipeMP4Metadata(clean, moovBoxes)
+
+	mvhd := findMP4Box(moovBoxes, "mvhd")
+	if mvhd == nil {
+		return nil, nil, errors.New("no mvhd box found")
+	}
+	duration, err := mp4Duration(clean[mvhd.payload:mvhd.end])
+	if err != nil {
+		return nil, nil, err
+	}
+	mp4ClearTimes(clean[mvhd.payload:mvhd.end])
+
+	meta := &audioVisualMeta{
+		duration: duration,
+	}
+
+	for _, trak := range moovBoxes {
+		if trak.boxType != "trak" {
+			continue
+		}
+		if err := decodeMP4Track(clean, trak, meta); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !meta.hasVideo && !meta.hasAudio {
+		return nil, nil, errors.New("no audio or video tracks found")
+	}
+
+	return clean, meta, nil
+}
+
+// decodeMP4Track reads the given track into meta, wiping its metadata as it goes.
+func decodeMP4Track(b []byte, trak mp4Box, meta *audioVisualMeta) error {
+	trakBoxes, err := readMP4Boxes(b, trak.payload, trak.end)
+	if err != nil {
+		return err
+	}
+	wipeMP4Metadata(b, trakBoxes)
+
+	tkhd := findMP4Box(trakBoxes, "tkhd")
+	mdia := findMP4Box(trakBoxes, "mdia")
+	if tkhd == nil || mdia == nil {
+		return errors.New("track is missing tkhd or mdia box")
+	}
+	mp4ClearTimes(b[tkhd.payload:tkhd.end])
+
+	mdiaBoxes, err := readMP4Boxes(b, mdia.payload, mdia.end)
+	if err != nil {
+		return err
+	}
+	hdlr := findMP4Box(mdiaBoxes, "hdlr")
+	mdhd := findMP4Box(mdiaBoxes, "mdhd")
+	if hdlr == nil || mdhd == nil || hdlr.end-hdlr.payload < 12 {
+		return errors.New("track is missing hdlr or mdhd box")
+	}
+	mp4ClearTimes(b[mdhd.payload:mdhd.end])
+
+	switch string(b[hdlr.payload+8 : hdlr.payload+12]) {
+	case "soun":
+		meta.hasAudio = true
+	case "vide":
+		if meta.hasVideo {
+			// we only care about the first video track
+			return nil
+		}
+		meta.hasVideo = true
+
+		// width and height are 16.16 fixed point numbers at the end of the track header
+		tkhdPayload := b[tkhd.payload:tkhd.end]
+		if len(tkhdPayload) < 8 {
+			return errors.New("track header too short")
+		}
+		meta.width = int(binary.BigEndian.Uint32(tkhdPayload[len(tkhdPayload)-8:]) >> 16)
+		meta.height = int(binary.BigEndian.Uint32(tkhdPayload[len(tkhdPayload)-4:]) >> 16)
+
+		// frame rate is the number of samples in the track over the track duration
+		trackDuration, err := mp4Duration(b[mdhd.payload:mdhd.end])
+		if err != nil {
+			return err
+		}
+		frames, err := mp4SampleCount(b, mdiaBoxes)
+		if err != nil {
+			return err
+		}
+		if trackDuration > 0 {
+			meta.framerate = float64(frames) / trackDuration
+		}
+	}
+
+	return nil
+}
+
+// mp4SampleCount returns the number of samples in the sample size box of a track.
+func mp4SampleCount(b []byte, mdiaBoxes []mp4Box) (uint32, error) {
+	minf := findMP4Box(mdiaBoxes, "minf")
+	if minf == nil {
+		return 0, errors.New("track is missing minf box")
+	}
+	minfBoxes, err := readMP4Boxes(b, minf.payload, minf.end)
+	if err != nil {
+		return 0, err
+	}
+	stbl := findMP4Box(minfBoxes, "stbl")
+	if stbl == nil {
+		return 0, errors.New("track is missing stbl box")
+	}
+	stblBoxes, err := readMP4Boxes(b, stbl.payload, stbl.end)
+	if err != nil {
+		return 0, err
+	}
+	stsz := findMP4Box(stblBoxes, "stsz")
+	if stsz == nil || stsz.end-stsz.payload < 12 {
+		return 0, errors.New("track is missing stsz box")
+	}
+	return binary.BigEndian.Uint32(b[stsz.payload+8 : stsz.payload+12]), nil
+}
+
+// wipeMP4Metadata turns any metadata boxes in boxes into zeroed free boxes.
+func wipeMP4Metadata(b []byte, boxes []mp4Box) {
+	for _, box := range boxes {
+		if !mp4MetadataBoxes[box.boxType] {
+			continue
+		}
+		copy(b[box.start+4:box.start+8], "free")
+		for i := box.payload; i < box.end; i++ {
+			b[i] = 0
+		}
+	}
+}
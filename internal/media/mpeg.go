@@ -0,0 +1,106 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"errors"
+)
+
+// MPEG start codes that we care about.
+const (
+	mpegPackHeader     = 0xBA
+	mpegSequenceHeader = 0xB3
+)
+
+// mpegFramerates are the frame rates that an mpeg sequence header's frame_rate_code refers to.
+var mpegFramerates = map[byte]float64{
+	1: 24000.0 / 1001.0,
+	2: 24,
+	3: 25,
+	4: 30000.0 / 1001.0,
+	5: 30,
+	6: 50,
+	7: 60000.0 / 1001.0,
+	8: 60,
+}
+
+// decodeMPEG parses an mpeg-1 or mpeg-2 program stream, returning it along with a description of its contents.
+//
+// Program streams don't carry any metadata worth stripping, so the file is returned as is. The dimensions and frame rate
+// come from the first sequence header, and the duration from the clock references of the first and last pack headers.
+func decodeMPEG(b []byte) ([]byte, *audioVisualMeta, error) {
+	meta := &audioVisualMeta{}
+
+	var firstSCR, lastSCR uint64
+	var packs int
+	for i := 0; i+4 <= len(b); i++ {
+		if b[i] != 0 || b[i+1] != 0 || b[i+2] != 1 {
+			continue
+		}
+
+		switch b[i+3] {
+		case mpegPackHeader:
+			scr, ok := mpegSCR(b[i+4:])
+			if !ok {
+				continue
+			}
+			if packs == 0 {
+				firstSCR = scr
+			}
+			lastSCR = scr
+			packs++
+		case mpegSequenceHeader:
+			if meta.hasVideo || i+12 > len(b) {
+				continue
+			}
+			meta.hasVideo = true
+			meta.width = int(b[i+4])<<4 | int(b[i+5])>>4
+			meta.height = int(b[i+5]&0x0F)<<8 | int(b[i+6])
+			meta.framerate = mpegFramerates[b[i+7]&0x0F]
+		}
+	}
+
+	if !meta.hasVideo {
+		return nil, nil, errors.New("no sequence header found")
+	}
+
+	if packs > 1 && lastSCR > firstSCR {
+		// the system clock reference counts in 90kHz ticks
+		meta.duration = float64(lastSCR-firstSCR) / 90000
+	}
+
+	return b, meta, nil
+}
+
+// mpegSCR parses the system clock reference from the given pack header, which should start just after the start code.
+func mpegSCR(b []byte) (uint64, bool) {
+	if len(b) < 5 {
+		return 0, false
+	}
+
+	switch {
+	case b[0]&0xC0 == 0x40:
+		// mpeg-2
+		return uint64(b[0]&0x38)<<27 | uint64(b[0]&0x03)<<28 | uint64(b[1])<<20 | uint64(b[2]&0xF8)<<12 | uint64(b[2]&0x03)<<13 | uint64(b[3])<<5 | uint64(b[4])>>3, true
+	case b[0]&0xF0 == 0x20:
+		// mpeg-1
+		return uint64(b[0]&0x0E)<<29 | uint64(b[1])<<22 | uint64(b[2]&0xFE)<<14 | uint64(b[3])<<7 | uint64(b[4])>>1, true
+	}
+	return 0, false
+}
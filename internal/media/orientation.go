@@ -0,0 +1,150 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+// exifOrientationTag is the exif tag that says which way up the camera was when a photo was taken.
+const exifOrientationTag = 0x0112
+
+// imageOrientation returns the exif orientation of the given jpeg or webp image, from 1 to 8,
+// or 1 (the right way up) if the image has no orientation set.
+func imageOrientation(b []byte, contentType string) int {
+	var tiff []byte
+	switch contentType {
+	case MIMEJpeg:
+		tiff = jpegExif(b)
+	case MIMEWebp:
+		tiff = webpExif(b)
+	}
+
+	if orientation := tiffOrientation(tiff); orientation >= 1 && orientation <= 8 {
+		return orientation
+	}
+	return 1
+}
+
+// jpegExif returns the exif data from the APP1 segment of the given jpeg, if it has one.
+func jpegExif(b []byte) []byte {
+	if len(b) < 2 || b[0] != 0xFF || b[1] != 0xD8 {
+		return nil
+	}
+
+	for offset := 2; offset+4 <= len(b); {
+		if b[offset] != 0xFF {
+			return nil
+		}
+		marker := b[offset+1]
+		if marker == 0xDA || marker == 0xD9 {
+			// start of scan or end of image; there won't be any more metadata
+			return nil
+		}
+
+		length := int(binary.BigEndian.Uint16(b[offset+2 : offset+4]))
+		if length < 2 || offset+2+length > len(b) {
+			return nil
+		}
+		segment := b[offset+4 : offset+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+		offset += 2 + length
+	}
+	return nil
+}
+
+// tiffOrientation reads the orientation tag from the first IFD of the given tiff formatted exif data,
+// returning 0 if it can't be found.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		// each entry is a 2 byte tag, 2 byte type, 4 byte count, and 4 byte value
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:entry+2]) == exifOrientationTag {
+			return int(order.Uint16(tiff[entry+8 : entry+10]))
+		}
+	}
+	return 0
+}
+
+// orientImage transforms the given image according to its exif orientation, so that it's the right way up.
+func orientImage(i image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return i
+	}
+
+	bounds := i.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+
+	// orientations 5 to 8 involve a quarter turn, so width and height swap around
+	out := image.NewNRGBA(image.Rect(0, 0, width, height))
+	if orientation >= 5 {
+		out = image.NewNRGBA(image.Rect(0, 0, height, width))
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var outX, outY int
+			switch orientation {
+			case 2: // flipped horizontally
+				outX, outY = width-1-x, y
+			case 3: // upside down
+				outX, outY = width-1-x, height-1-y
+			case 4: // flipped vertically
+				outX, outY = x, height-1-y
+			case 5: // flipped along the top left to bottom right diagonal
+				outX, outY = y, x
+			case 6: // needs a quarter turn clockwise
+				outX, outY = height-1-y, x
+			case 7: // flipped along the top right to bottom left diagonal
+				outX, outY = height-1-y, width-1-x
+			case 8: // needs a quarter turn anticlockwise
+				outX, outY = y, width-1-x
+			}
+			out.Set(outX, outY, i.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (mh *mediaHandler) processAudioAttachment(data []byte, accountID string, contentType string, remoteURL string) (*gtsmodel.MediaAttachment, error) {
+	var clean []byte
+	var meta *audioVisualMeta
+	var err error
+
+	switch contentType {
+	case MIMEMp3:
+		clean, meta, err = decodeMP3(data)
+	case MIMEWav:
+		clean, meta, err = decodeWav(data)
+	case MIMEM4a:
+		clean, meta, err = decodeMP4(data)
+	default:
+		return nil, errors.New("media type unrecognized")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing audio: %s", err)
+	}
+
+	// audio doesn't have any frames to take a thumbnail from, so just use a placeholder
+	meta.hasVideo = false
+	return mh.storeAudioVisualAttachment(clean, meta, placeholderFrame(256, 256), accountID, contentType, remoteURL)
+}
@@ -21,7 +21,6 @@ package media
 import (
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
@@ -41,39 +40,18 @@ func (mh *mediaHandler) processHeaderOrAvi(imageBytes []byte, contentType string
 		return nil, errors.New("header or avatar not selected")
 	}
 
-	var clean []byte
-	var err error
-
-	var original *imageAndMeta
-	switch contentType {
-	case MIMEJpeg:
-		if clean, err = purgeExif(imageBytes); err != nil {
-			return nil, fmt.Errorf("error cleaning exif data: %s", err)
-		}
-		original, err = deriveImage(clean, contentType)
-	case MIMEPng:
-		if clean, err = purgeExif(imageBytes); err != nil {
-			return nil, fmt.Errorf("error cleaning exif data: %s", err)
-		}
-		original, err = deriveImage(clean, contentType)
-	case MIMEGif:
-		clean = imageBytes
-		original, err = deriveGif(clean, contentType)
-	default:
-		return nil, errors.New("media type unrecognized")
-	}
-
+	original, err := deriveOriginalImage(imageBytes, contentType, mh.config.MediaConfig.MaxImageDimension)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing image: %s", err)
 	}
 
-	small, err := deriveThumbnail(clean, contentType, 256, 256)
+	small, err := deriveThumbnail(original.image, original.contentType, 256, 256)
 	if err != nil {
 		return nil, fmt.Errorf("error deriving thumbnail: %s", err)
 	}
 
 	// now put it in storage, take a new id for the name of the file so we don't store any unnecessary info about it
-	extension := strings.Split(contentType, "/")[1]
+	extension := extensionForContentType(original.contentType)
 	newMediaID, err := id.NewRandomULID()
 	if err != nil {
 		return nil, err
@@ -124,20 +102,22 @@ func (mh *mediaHandler) processHeaderOrAvi(imageBytes []byte, contentType string
 		Processing:        2,
 		File: gtsmodel.File{
 			Path:        originalPath,
-			ContentType: contentType,
+			ContentType: original.contentType,
 			FileSize:    len(original.image),
 			UpdatedAt:   time.Now(),
 		},
 		Thumbnail: gtsmodel.Thumbnail{
 			Path:        smallPath,
-			ContentType: contentType,
+			ContentType: original.contentType,
 			FileSize:    len(small.image),
 			UpdatedAt:   time.Now(),
 			URL:         smallURL,
 			RemoteURL:   "",
 		},
-		Avatar: isAvatar,
-		Header: isHeader,
+		Avatar:         isAvatar,
+		Header:         isHeader,
+		Cached:         true,
+		LastAccessedAt: time.Now(),
 	}
 
 	return ma, nil
@@ -19,9 +19,7 @@
 package media
 
 import (
-	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
@@ -29,37 +27,19 @@ import (
 )
 
 func (mh *mediaHandler) processImageAttachment(data []byte, accountID string, contentType string, remoteURL string) (*gtsmodel.MediaAttachment, error) {
-	var clean []byte
-	var err error
-	var original *imageAndMeta
-	var small *imageAndMeta
-
-	switch contentType {
-	case MIMEJpeg, MIMEPng:
-		if clean, err = purgeExif(data); err != nil {
-			return nil, fmt.Errorf("error cleaning exif data: %s", err)
-		}
-		original, err = deriveImage(clean, contentType)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing image: %s", err)
-		}
-	case MIMEGif:
-		clean = data
-		original, err = deriveGif(clean, contentType)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing gif: %s", err)
-		}
-	default:
-		return nil, errors.New("media type unrecognized")
+	original, err := deriveOriginalImage(data, contentType, mh.config.MediaConfig.MaxImageDimension)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing image: %s", err)
 	}
 
-	small, err = deriveThumbnail(clean, contentType, 256, 256)
+	// derive the thumbnail from the cleaned up original, so that it's the right way up
+	small, err := deriveThumbnail(original.image, original.contentType, 256, 256)
 	if err != nil {
 		return nil, fmt.Errorf("error deriving thumbnail: %s", err)
 	}
 
 	// now put it in storage, take a new id for the name of the file so we don't store any unnecessary info about it
-	extension := strings.Split(contentType, "/")[1]
+	extension := extensionForContentType(original.contentType)
 	newMediaID, err := id.NewRandomULID()
 	if err != nil {
 		return nil, err
@@ -110,7 +90,7 @@ func (mh *mediaHandler) processImageAttachment(data []byte, accountID string, co
 		Processing:        2,
 		File: gtsmodel.File{
 			Path:        originalPath,
-			ContentType: contentType,
+			ContentType: original.contentType,
 			FileSize:    len(original.image),
 			UpdatedAt:   time.Now(),
 		},
@@ -122,8 +102,10 @@ func (mh *mediaHandler) processImageAttachment(data []byte, accountID string, co
 			URL:         smallURL,
 			RemoteURL:   "",
 		},
-		Avatar: false,
-		Header: false,
+		Avatar:         false,
+		Header:         false,
+		Cached:         true,
+		LastAccessedAt: time.Now(),
 	}
 
 	return ma, nil
@@ -0,0 +1,207 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/blob"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type ProcessImageTestSuite struct {
+	suite.Suite
+	storage      blob.Storage
+	mediaHandler media.Handler
+}
+
+func (suite *ProcessImageTestSuite) SetupTest() {
+	suite.storage = testrig.NewTestStorage()
+	suite.mediaHandler = testrig.NewTestMediaHandler(nil, suite.storage)
+}
+
+func (suite *ProcessImageTestSuite) process(data []byte) (*gtsmodel.MediaAttachment, []byte) {
+	attachment, err := suite.mediaHandler.ProcessAttachment(data, "01F8MH1H7YV1Z7D2C8K2730QBF", "")
+	suite.Require().NoError(err)
+	suite.Equal(gtsmodel.FileTypeImage, attachment.Type)
+
+	stored, err := suite.storage.RetrieveFileFrom(attachment.File.Path)
+	suite.NoError(err)
+	suite.Equal(len(stored), attachment.File.FileSize)
+
+	thumbnail, err := suite.storage.RetrieveFileFrom(attachment.Thumbnail.Path)
+	suite.NoError(err)
+	_, err = jpeg.Decode(bytes.NewReader(thumbnail))
+	suite.NoError(err)
+
+	return attachment, stored
+}
+
+// riffChunk returns a RIFF chunk with the given id wrapping payload.
+func riffChunk(id string, payload []byte) []byte {
+	c := make([]byte, 8, 8+len(payload)+1)
+	copy(c, id)
+	binary.LittleEndian.PutUint32(c[4:], uint32(len(payload)))
+	c = append(c, payload...)
+	if len(payload)%2 != 0 {
+		c = append(c, 0)
+	}
+	return c
+}
+
+func (suite *ProcessImageTestSuite) TestProcessJPEGWithOrientation() {
+	// a landscape photo that's red on the left and blue on the right...
+	i := image.NewRGBA(image.Rect(0, 0, 80, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 80; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 40 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			i.Set(x, y, c)
+		}
+	}
+	encoded := &bytes.Buffer{}
+	suite.NoError(jpeg.Encode(encoded, i, nil))
+
+	// ...that the camera says needs a quarter turn clockwise
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1, 0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, 6, 0, 0, 0, 0, 0, 0, 0, 0}
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(app1)+2))
+	data := append([]byte{0xFF, 0xD8}, segment...)
+	data = append(data, app1...)
+	data = append(data, encoded.Bytes()[2:]...)
+
+	attachment, stored := suite.process(data)
+	suite.Equal(media.MIMEJpeg, attachment.File.ContentType)
+	suite.Equal(40, attachment.FileMeta.Original.Width)
+	suite.Equal(80, attachment.FileMeta.Original.Height)
+
+	// it should now be portrait, red on top and blue on the bottom, with no exif left
+	suite.NotContains(string(stored), "Exif")
+	rotated, err := jpeg.Decode(bytes.NewReader(stored))
+	suite.NoError(err)
+	suite.Equal(image.Rect(0, 0, 40, 80), rotated.Bounds())
+	r, _, b, _ := rotated.At(20, 10).RGBA()
+	suite.True(r > 0xF000 && b < 0x1000)
+	r, _, b, _ = rotated.At(20, 70).RGBA()
+	suite.True(b > 0xF000 && r < 0x1000)
+}
+
+func (suite *ProcessImageTestSuite) TestProcessLargePNG() {
+	i := image.NewNRGBA(image.Rect(0, 0, 3000, 1000))
+	encoded := &bytes.Buffer{}
+	suite.NoError(png.Encode(encoded, i))
+
+	// the image gets scaled down to fit in the max dimension, and stays a png
+	attachment, stored := suite.process(encoded.Bytes())
+	suite.Equal(media.MIMEPng, attachment.File.ContentType)
+	suite.True(strings.HasSuffix(attachment.File.Path, ".png"))
+	suite.Equal(2560, attachment.FileMeta.Original.Width)
+	suite.Equal(853, attachment.FileMeta.Original.Height)
+
+	config, err := png.DecodeConfig(bytes.NewReader(stored))
+	suite.NoError(err)
+	suite.Equal(2560, config.Width)
+	suite.Equal(853, config.Height)
+}
+
+func (suite *ProcessImageTestSuite) TestProcessWebp() {
+	data, err := ioutil.ReadFile("./test/test-webp.webp")
+	suite.NoError(err)
+
+	// an opaque webp gets re-encoded as a jpeg
+	attachment, stored := suite.process(data)
+	suite.Equal(media.MIMEJpeg, attachment.File.ContentType)
+	suite.True(strings.HasSuffix(attachment.File.Path, ".jpeg"))
+	suite.Equal(150, attachment.FileMeta.Original.Width)
+	suite.Equal(103, attachment.FileMeta.Original.Height)
+	suite.NotEmpty(attachment.Blurhash)
+
+	_, err = jpeg.Decode(bytes.NewReader(stored))
+	suite.NoError(err)
+}
+
+func (suite *ProcessImageTestSuite) TestProcessWebpWithAlpha() {
+	data, err := ioutil.ReadFile("./test/test-webp-alpha.webp")
+	suite.NoError(err)
+
+	// a webp with transparency gets re-encoded as a png to keep it
+	attachment, stored := suite.process(data)
+	suite.Equal(media.MIMEPng, attachment.File.ContentType)
+	suite.Equal(400, attachment.FileMeta.Original.Width)
+	suite.Equal(301, attachment.FileMeta.Original.Height)
+
+	_, err = png.Decode(bytes.NewReader(stored))
+	suite.NoError(err)
+}
+
+func (suite *ProcessImageTestSuite) TestProcessAnimatedWebp() {
+	still, err := ioutil.ReadFile("./test/test-webp-alpha.webp")
+	suite.NoError(err)
+
+	// make an animated webp with two frames, both the alpha and image data from the still, plus some exif
+	frameData := still[12+18:]
+	frameHeader := make([]byte, 16)
+	frameHeader[6], frameHeader[7] = 0x8F, 0x01  // width - 1 = 399
+	frameHeader[9], frameHeader[10] = 0x2C, 0x01 // height - 1 = 300
+	frameHeader[12] = 100                        // 100ms
+	frame := riffChunk("ANMF", append(frameHeader, frameData...))
+
+	vp8x := make([]byte, 10)
+	vp8x[0] = 0x02 | 0x08 | 0x10 // animation, exif, alpha
+	copy(vp8x[4:], frameHeader[6:12])
+
+	body := []byte("WEBP")
+	body = append(body, riffChunk("VP8X", vp8x)...)
+	body = append(body, riffChunk("ANIM", make([]byte, 6))...)
+	body = append(body, frame...)
+	body = append(body, frame...)
+	body = append(body, riffChunk("EXIF", []byte("MM\x00\x2a my secret location"))...)
+	data := riffChunk("RIFF", body)
+
+	// it's stored as an animated webp still, just without the exif
+	attachment, stored := suite.process(data)
+	suite.Equal(media.MIMEWebp, attachment.File.ContentType)
+	suite.Equal(400, attachment.FileMeta.Original.Width)
+	suite.Equal(301, attachment.FileMeta.Original.Height)
+	suite.NotEmpty(attachment.Blurhash)
+	suite.Equal(256, attachment.FileMeta.Small.Width)
+
+	suite.NotContains(string(stored), "secret")
+	suite.Equal(2, strings.Count(string(stored), "ANMF"))
+	suite.Equal(byte(0x02|0x10), stored[20])
+	suite.Len(stored, len(data)-len(riffChunk("EXIF", []byte("MM\x00\x2a my secret location"))))
+}
+
+func TestProcessImageTestSuite(t *testing.T) {
+	suite.Run(t, new(ProcessImageTestSuite))
+}
@@ -18,6 +18,228 @@
 
 package media
 
-// func (mh *mediaHandler) processVideoAttachment(data []byte, accountID string, contentType string, remoteURL string) (*gtsmodel.MediaAttachment, error) {
-// 	return nil, nil
-// }
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/buckket/go-blurhash"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+// frameTimeout is how long we give ffmpeg to extract a frame from a video before giving up.
+const frameTimeout = 30 * time.Second
+
+// audioVisualMeta describes the contents of a video or audio file.
+type audioVisualMeta struct {
+	hasVideo bool
+	hasAudio bool
+	width    int
+	height   int
+	// duration in seconds
+	duration float64
+	// framerate in frames per second
+	framerate float64
+	// bitrate in bits per second
+	bitrate uint64
+}
+
+func (mh *mediaHandler) processVideoAttachment(data []byte, accountID string, contentType string, remoteURL string) (*gtsmodel.MediaAttachment, error) {
+	var clean []byte
+	var meta *audioVisualMeta
+	var err error
+
+	switch contentType {
+	case MIMEMp4, MIMEQuicktime:
+		clean, meta, err = decodeMP4(data)
+	case MIMEWebm:
+		clean, meta, err = decodeWebm(data)
+	case MIMEMpeg:
+		clean, meta, err = decodeMPEG(data)
+	default:
+		return nil, errors.New("media type unrecognized")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing video: %s", err)
+	}
+
+	// a video container with no video track in it is just audio
+	if !meta.hasVideo {
+		return mh.storeAudioVisualAttachment(clean, meta, placeholderFrame(256, 256), accountID, contentType, remoteURL)
+	}
+
+	if meta.width <= 0 || meta.height <= 0 {
+		return nil, errors.New("video has no dimensions")
+	}
+
+	frame, err := extractVideoFrame(clean)
+	if err != nil {
+		mh.log.Debugf("processVideoAttachment: couldn't extract video frame, using placeholder: %s", err)
+		frame = placeholderFrame(meta.width, meta.height)
+	}
+
+	return mh.storeAudioVisualAttachment(clean, meta, frame, accountID, contentType, remoteURL)
+}
+
+// storeAudioVisualAttachment stores the given cleaned video or audio file, along with a thumbnail derived
+// from the given frame, and returns a media attachment for them.
+func (mh *mediaHandler) storeAudioVisualAttachment(clean []byte, meta *audioVisualMeta, frame image.Image, accountID string, contentType string, remoteURL string) (*gtsmodel.MediaAttachment, error) {
+	small, err := deriveThumbnailFromImage(frame, 256, 256)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving thumbnail: %s", err)
+	}
+
+	bh, err := blurhash.Encode(4, 3, frame)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving blurhash: %s", err)
+	}
+
+	if meta.bitrate == 0 && meta.duration > 0 {
+		meta.bitrate = uint64(float64(len(clean)*8) / meta.duration)
+	}
+
+	// now put it in storage, take a new id for the name of the file so we don't store any unnecessary info about it
+	extension := extensionForContentType(contentType)
+	newMediaID, err := id.NewRandomULID()
+	if err != nil {
+		return nil, err
+	}
+
+	URLbase := fmt.Sprintf("%s://%s%s", mh.config.StorageConfig.ServeProtocol, mh.config.StorageConfig.ServeHost, mh.config.StorageConfig.ServeBasePath)
+	originalURL := fmt.Sprintf("%s/%s/attachment/original/%s.%s", URLbase, accountID, newMediaID, extension)
+	smallURL := fmt.Sprintf("%s/%s/attachment/small/%s.jpeg", URLbase, accountID, newMediaID) // all thumbnails/smalls are encoded as jpeg
+
+	// we store the original...
+	originalPath := fmt.Sprintf("%s/%s/%s/%s/%s.%s", mh.config.StorageConfig.BasePath, accountID, Attachment, Original, newMediaID, extension)
+	if err := mh.storage.StoreFileAt(originalPath, clean); err != nil {
+		return nil, fmt.Errorf("storage error: %s", err)
+	}
+
+	// and a thumbnail...
+	smallPath := fmt.Sprintf("%s/%s/%s/%s/%s.jpeg", mh.config.StorageConfig.BasePath, accountID, Attachment, Small, newMediaID) // all thumbnails/smalls are encoded as jpeg
+	if err := mh.storage.StoreFileAt(smallPath, small.image); err != nil {
+		return nil, fmt.Errorf("storage error: %s", err)
+	}
+
+	duration := float32(meta.duration)
+	original := gtsmodel.Original{
+		Duration: &duration,
+		Bitrate:  &meta.bitrate,
+	}
+
+	fileType := gtsmodel.FileTypeAudio
+	if meta.hasVideo {
+		fileType = gtsmodel.FileTypeVideo
+		framerate := float32(meta.framerate)
+		original.Width = meta.width
+		original.Height = meta.height
+		original.Size = meta.width * meta.height
+		original.Aspect = float64(meta.width) / float64(meta.height)
+		original.Framerate = &framerate
+	}
+
+	ma := &gtsmodel.MediaAttachment{
+		ID:        newMediaID,
+		StatusID:  "",
+		URL:       originalURL,
+		RemoteURL: remoteURL,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Type:      fileType,
+		FileMeta: gtsmodel.FileMeta{
+			Original: original,
+			Small: gtsmodel.Small{
+				Width:  small.width,
+				Height: small.height,
+				Size:   small.size,
+				Aspect: small.aspect,
+			},
+		},
+		AccountID:         accountID,
+		Description:       "",
+		ScheduledStatusID: "",
+		Blurhash:          bh,
+		Processing:        2,
+		File: gtsmodel.File{
+			Path:        originalPath,
+			ContentType: contentType,
+			FileSize:    len(clean),
+			UpdatedAt:   time.Now(),
+		},
+		Thumbnail: gtsmodel.Thumbnail{
+			Path:        smallPath,
+			ContentType: MIMEJpeg, // all thumbnails/smalls are encoded as jpeg
+			FileSize:    len(small.image),
+			UpdatedAt:   time.Now(),
+			URL:         smallURL,
+			RemoteURL:   "",
+		},
+		Avatar:         false,
+		Header:         false,
+		Cached:         true,
+		LastAccessedAt: time.Now(),
+	}
+
+	return ma, nil
+}
+
+// extractVideoFrame uses ffmpeg to decode the first frame of the given video, if ffmpeg is installed.
+//
+// Go doesn't have any native video decoders, so this is the only way we can get a proper frame;
+// if it doesn't work out, callers should fall back to a placeholder frame.
+func extractVideoFrame(video []byte) (image.Image, error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg not available: %s", err)
+	}
+
+	// mp4 files can't always be read from a pipe, since the moov box may be at the end of the file
+	f, err := ioutil.TempFile("", "gotosocial-video-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp file: %s", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(video); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("error writing temp file: %s", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("error closing temp file: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), frameTimeout)
+	defer cancel()
+
+	out := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, ffmpeg, "-hide_banner", "-loglevel", "error", "-i", f.Name(), "-frames:v", "1", "-f", "image2pipe", "-vcodec", "png", "-")
+	cmd.Stdout = out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error running ffmpeg: %s", err)
+	}
+
+	return png.Decode(out)
+}
+
+// placeholderFrame returns a plain grey frame with the given dimensions, for use as the basis
+// of a thumbnail when we can't get one out of a video or audio file.
+func placeholderFrame(width int, height int) image.Image {
+	// the frame only gets scaled down for the thumbnail, so there's no need for it to be huge
+	for width > 1024 || height > 1024 {
+		width = (width + 1) / 2
+		height = (height + 1) / 2
+	}
+
+	frame := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range frame.Pix {
+		frame.Pix[i] = 0x40
+	}
+	return frame
+}
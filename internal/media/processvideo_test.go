@@ -0,0 +1,223 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/blob"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type ProcessVideoTestSuite struct {
+	suite.Suite
+	storage      blob.Storage
+	mediaHandler media.Handler
+}
+
+func (suite *ProcessVideoTestSuite) SetupTest() {
+	suite.storage = testrig.NewTestStorage()
+	suite.mediaHandler = testrig.NewTestMediaHandler(nil, suite.storage)
+}
+
+func (suite *ProcessVideoTestSuite) process(data []byte) (*gtsmodel.MediaAttachment, []byte) {
+	attachment, err := suite.mediaHandler.ProcessAttachment(data, "01F8MH1H7YV1Z7D2C8K2730QBF", "")
+	suite.Require().NoError(err)
+
+	stored, err := suite.storage.RetrieveFileFrom(attachment.File.Path)
+	suite.NoError(err)
+	suite.Equal(len(stored), attachment.File.FileSize)
+
+	thumbnail, err := suite.storage.RetrieveFileFrom(attachment.Thumbnail.Path)
+	suite.NoError(err)
+	suite.NotEmpty(thumbnail)
+	suite.Equal(media.MIMEJpeg, attachment.Thumbnail.ContentType)
+	suite.NotEmpty(attachment.Blurhash)
+
+	return attachment, stored
+}
+
+// mp4Box returns an mp4 box of the given type wrapping payload.
+func mp4Box(boxType string, payload []byte) []byte {
+	box := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint32(box, uint32(8+len(payload)))
+	copy(box[4:], boxType)
+	return append(box, payload...)
+}
+
+func (suite *ProcessVideoTestSuite) TestProcessMP4() {
+	original, err := ioutil.ReadFile("./test/test-mp4-original.mp4")
+	suite.NoError(err)
+
+	// tack a title on the end of the file
+	data := append(original, mp4Box("udta", mp4Box("\xa9nam", []byte("my secret holiday video")))...)
+
+	attachment, stored := suite.process(data)
+	suite.Equal(gtsmodel.FileTypeVideo, attachment.Type)
+	suite.Equal(media.MIMEMp4, attachment.File.ContentType)
+	suite.Equal(190, attachment.FileMeta.Original.Width)
+	suite.Equal(240, attachment.FileMeta.Original.Height)
+	suite.InDelta(0.792, attachment.FileMeta.Original.Aspect, 0.001)
+	suite.InDelta(4.967, *attachment.FileMeta.Original.Duration, 0.001)
+	suite.InDelta(30, *attachment.FileMeta.Original.Framerate, 0.01)
+	suite.InDelta(float64(len(data)*8)/4.9666667, *attachment.FileMeta.Original.Bitrate, 1)
+	suite.Equal(190, attachment.FileMeta.Small.Width)
+
+	// the title should be gone, and the creation time zeroed, but the file otherwise left alone
+	suite.Len(stored, len(data))
+	suite.NotContains(string(stored), "secret")
+	suite.Equal(make([]byte, 8), stored[44:52])
+	suite.Equal(data[3086:len(original)], stored[3086:len(original)])
+}
+
+func (suite *ProcessVideoTestSuite) TestProcessWebm() {
+	data, err := ioutil.ReadFile("./test/test-webm-original.webm")
+	suite.NoError(err)
+
+	// replace the 172 byte void element at offset 106 with a tags element of the same length
+	tags := []byte{0x12, 0x54, 0xC3, 0x67, 0x01, 0, 0, 0, 0, 0, 0, 160}
+	tags = append(tags, []byte("my secret holiday video")...)
+	tags = append(tags, make([]byte, 172-len(tags))...)
+	copy(data[106:], tags)
+
+	attachment, stored := suite.process(data)
+	suite.Equal(gtsmodel.FileTypeVideo, attachment.Type)
+	suite.Equal(media.MIMEWebm, attachment.File.ContentType)
+	suite.Equal(400, attachment.FileMeta.Original.Width)
+	suite.Equal(250, attachment.FileMeta.Original.Height)
+	suite.InDelta(8.2, *attachment.FileMeta.Original.Duration, 0.001)
+	suite.InDelta(5, *attachment.FileMeta.Original.Framerate, 0.01)
+
+	suite.Len(stored, len(data))
+	suite.NotContains(string(stored), "secret")
+	suite.Equal(byte(0xEC), stored[106])
+	suite.Equal(data[359:], stored[359:])
+}
+
+func (suite *ProcessVideoTestSuite) TestProcessMP3() {
+	data := &bytes.Buffer{}
+
+	// an id3v2 tag...
+	data.Write([]byte{'I', 'D', '3', 3, 0, 0, 0, 0, 0, 23})
+	data.WriteString("my secret holiday audio")
+
+	// ...100 frames of 128kbps 44.1kHz audio...
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x64})
+	for i := 0; i < 100; i++ {
+		data.Write(frame)
+	}
+
+	// ...and an id3v1 tag
+	id3v1 := make([]byte, 128)
+	copy(id3v1, "TAGmy secret holiday audio")
+	data.Write(id3v1)
+
+	attachment, stored := suite.process(data.Bytes())
+	suite.Equal(gtsmodel.FileTypeAudio, attachment.Type)
+	suite.Equal(media.MIMEMp3, attachment.File.ContentType)
+	suite.Contains(attachment.File.Path, ".mp3")
+	suite.InDelta(2.612, *attachment.FileMeta.Original.Duration, 0.001)
+	suite.Nil(attachment.FileMeta.Original.Framerate)
+	suite.Zero(attachment.FileMeta.Original.Width)
+
+	suite.Equal(bytes.Repeat(frame, 100), stored)
+}
+
+func (suite *ProcessVideoTestSuite) TestProcessWav() {
+	chunk := func(id string, payload []byte) []byte {
+		c := make([]byte, 8, 8+len(payload))
+		copy(c, id)
+		binary.LittleEndian.PutUint32(c[4:], uint32(len(payload)))
+		return append(c, payload...)
+	}
+
+	// mono 16 bit pcm at 8kHz
+	format := make([]byte, 16)
+	binary.LittleEndian.PutUint16(format[0:], 1)
+	binary.LittleEndian.PutUint16(format[2:], 1)
+	binary.LittleEndian.PutUint32(format[4:], 8000)
+	binary.LittleEndian.PutUint32(format[8:], 16000)
+	binary.LittleEndian.PutUint16(format[12:], 2)
+	binary.LittleEndian.PutUint16(format[14:], 16)
+
+	body := []byte("WAVE")
+	body = append(body, chunk("fmt ", format)...)
+	body = append(body, chunk("LIST", []byte("INFOINAM\x17\x00\x00\x00my secret holiday audio\x00"))...)
+	body = append(body, chunk("data", make([]byte, 32000))...)
+	data := chunk("RIFF", body)
+
+	attachment, stored := suite.process(data)
+	suite.Equal(gtsmodel.FileTypeAudio, attachment.Type)
+	suite.Equal(media.MIMEWav, attachment.File.ContentType)
+	suite.InDelta(2, *attachment.FileMeta.Original.Duration, 0.001)
+	suite.EqualValues(128000, *attachment.FileMeta.Original.Bitrate)
+
+	suite.NotContains(string(stored), "secret")
+	suite.Equal(uint32(len(stored)-8), binary.LittleEndian.Uint32(stored[4:8]))
+	suite.Len(stored, 12+24+8+32000)
+}
+
+func mpegPackHeader(scr uint64) []byte {
+	return []byte{
+		0x00, 0x00, 0x01, 0xBA,
+		0x44 | byte(scr>>27)&0x38 | byte(scr>>28)&0x03,
+		byte(scr >> 20),
+		0x04 | byte(scr>>12)&0xF8 | byte(scr>>13)&0x03,
+		byte(scr >> 5),
+		0x04 | byte(scr<<3),
+		0x01, 0x89, 0xC3, 0xF8,
+	}
+}
+
+func (suite *ProcessVideoTestSuite) TestProcessMPEG() {
+	// 320x240 at 25fps, two seconds between the first and last packs
+	data := mpegPackHeader(0)
+	data = append(data, 0x00, 0x00, 0x01, 0xB3, 0x14, 0x00, 0xF0, 0x13, 0xFF, 0xFF, 0xE0, 0x18)
+	data = append(data, make([]byte, 4096)...)
+	data = append(data, mpegPackHeader(2*90000)...)
+
+	attachment, stored := suite.process(data)
+	suite.Equal(gtsmodel.FileTypeVideo, attachment.Type)
+	suite.Equal(media.MIMEMpeg, attachment.File.ContentType)
+	suite.Equal(320, attachment.FileMeta.Original.Width)
+	suite.Equal(240, attachment.FileMeta.Original.Height)
+	suite.InDelta(2, *attachment.FileMeta.Original.Duration, 0.001)
+	suite.InDelta(25, *attachment.FileMeta.Original.Framerate, 0.01)
+	suite.Equal(data, stored)
+}
+
+func (suite *ProcessVideoTestSuite) TestProcessVideoTooBig() {
+	data, err := ioutil.ReadFile("./test/test-webm-original.webm")
+	suite.NoError(err)
+	data = append(data, make([]byte, 5*1024*1024)...)
+
+	_, err = suite.mediaHandler.ProcessAttachment(data, "01F8MH1H7YV1Z7D2C8K2730QBF", "")
+	suite.EqualError(err, "video size 5608003 bytes exceeded max video size of 5242880 bytes")
+}
+
+func TestProcessVideoTestSuite(t *testing.T) {
+	suite.Run(t, new(ProcessVideoTestSuite))
+}
@@ -26,6 +26,7 @@ import (
 	"image/gif"
 	"image/jpeg"
 	"image/png"
+	"strings"
 
 	"github.com/buckket/go-blurhash"
 	"github.com/h2non/filetype"
@@ -42,6 +43,8 @@ const (
 	MIMEGif = "image/gif"
 	// MIMEPng is the png image mime type
 	MIMEPng = "image/png"
+	// MIMEWebp is the webp image mime type
+	MIMEWebp = "image/webp"
 
 	// MIMEVideo is the mime type for video
 	MIMEVideo = "video"
@@ -51,8 +54,26 @@ const (
 	MIMEMpeg = "video/mpeg"
 	// MIMEWebm is the webm video mime type
 	MIMEWebm = "video/webm"
+	// MIMEQuicktime is the quicktime (mov) video mime type
+	MIMEQuicktime = "video/quicktime"
+
+	// MIMEAudio is the mime type for audio
+	MIMEAudio = "audio"
+	// MIMEMp3 is the mp3 audio mime type
+	MIMEMp3 = "audio/mpeg"
+	// MIMEWav is the wav audio mime type
+	MIMEWav = "audio/x-wav"
+	// MIMEM4a is the m4a (mp4 audio) mime type
+	MIMEM4a = "audio/m4a"
 )
 
+// contentTypeExtensions are file extensions for content types where the extension isn't just the subtype
+var contentTypeExtensions = map[string]string{
+	MIMEQuicktime: "mov",
+	MIMEMp3:       "mp3",
+	MIMEWav:       "wav",
+}
+
 // parseContentType parses the MIME content type from a file, returning it as a string in the form (eg., "image/jpeg").
 // Returns an error if the content type is not something we can process.
 func parseContentType(content []byte) (string, error) {
@@ -76,11 +97,15 @@ func parseContentType(content []byte) (string, error) {
 
 // SupportedImageType checks mime type of an image against a slice of accepted types,
 // and returns True if the mime type is accepted.
+//
+// AVIF and HEIC aren't accepted yet, since there's no pure Go decoder for either of them;
+// they can be added here once there is, or once we decode them with an external tool like ffmpeg.
 func SupportedImageType(mimeType string) bool {
 	acceptedImageTypes := []string{
 		MIMEJpeg,
 		MIMEGif,
 		MIMEPng,
+		MIMEWebp,
 	}
 	for _, accepted := range acceptedImageTypes {
 		if mimeType == accepted {
@@ -97,6 +122,7 @@ func SupportedVideoType(mimeType string) bool {
 		MIMEMp4,
 		MIMEMpeg,
 		MIMEWebm,
+		MIMEQuicktime,
 	}
 	for _, accepted := range acceptedVideoTypes {
 		if mimeType == accepted {
@@ -106,6 +132,30 @@ func SupportedVideoType(mimeType string) bool {
 	return false
 }
 
+// SupportedAudioType checks mime type of an audio file against a slice of accepted types,
+// and returns True if the mime type is accepted.
+func SupportedAudioType(mimeType string) bool {
+	acceptedAudioTypes := []string{
+		MIMEMp3,
+		MIMEWav,
+		MIMEM4a,
+	}
+	for _, accepted := range acceptedAudioTypes {
+		if mimeType == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+// extensionForContentType returns the file extension to store a file of the given content type with (eg., "jpeg" or "mp3").
+func extensionForContentType(contentType string) string {
+	if extension, ok := contentTypeExtensions[contentType]; ok {
+		return extension
+	}
+	return strings.Split(contentType, "/")[1]
+}
+
 // supportedEmojiType checks that the content type is image/png -- the only type supported for emoji.
 func supportedEmojiType(mimeType string) bool {
 	acceptedEmojiTypes := []string{
@@ -167,16 +217,47 @@ func deriveGif(b []byte, extension string) (*imageAndMeta, error) {
 	}
 
 	return &imageAndMeta{
-		image:    out.Bytes(),
-		width:    width,
-		height:   height,
-		size:     size,
-		aspect:   aspect,
-		blurhash: bh,
+		image:       out.Bytes(),
+		contentType: MIMEGif,
+		width:       width,
+		height:      height,
+		size:        size,
+		aspect:      aspect,
+		blurhash:    bh,
 	}, nil
 }
 
-func deriveImage(b []byte, contentType string) (*imageAndMeta, error) {
+// deriveOriginalImage cleans up the given image for storage as an original: metadata is removed, and still images
+// are turned the right way up, scaled down to fit within maxDimension x maxDimension if they're bigger than that
+// (unless maxDimension is 0), and re-encoded. Animated gifs and webps keep their animation, so they're left at their
+// original size.
+func deriveOriginalImage(b []byte, contentType string, maxDimension int) (*imageAndMeta, error) {
+	switch contentType {
+	case MIMEJpeg, MIMEPng:
+		// read the orientation before the exif data gets purged, since we need it to turn the image the right way up
+		orientation := imageOrientation(b, contentType)
+		clean, err := purgeExif(b)
+		if err != nil {
+			return nil, fmt.Errorf("error cleaning exif data: %s", err)
+		}
+		return deriveImage(clean, contentType, orientation, maxDimension)
+	case MIMEWebp:
+		if webpAnimated(b) {
+			return deriveAnimatedWebp(b)
+		}
+		return deriveImage(b, contentType, imageOrientation(b, contentType), maxDimension)
+	case MIMEGif:
+		return deriveGif(b, contentType)
+	}
+	return nil, fmt.Errorf("content type %s not recognised", contentType)
+}
+
+// deriveImage decodes the given jpeg, png, or still webp, applies the given exif orientation to it, scales it down
+// to fit within maxDimension x maxDimension if it's bigger than that (unless maxDimension is 0), and re-encodes it.
+//
+// Jpegs and pngs are re-encoded in their own format. Webps are re-encoded as png if they have any transparency,
+// or jpeg if they don't, since there's no webp encoder available to us.
+func deriveImage(b []byte, contentType string, orientation int, maxDimension int) (*imageAndMeta, error) {
 	var i image.Image
 	var err error
 
@@ -191,10 +272,25 @@ func deriveImage(b []byte, contentType string) (*imageAndMeta, error) {
 		if err != nil {
 			return nil, err
 		}
+	case MIMEWebp:
+		i, err = decodeWebp(b)
+		if err != nil {
+			return nil, err
+		}
+		contentType = MIMEJpeg
+		if opaque, ok := i.(interface{ Opaque() bool }); !ok || !opaque.Opaque() {
+			contentType = MIMEPng
+		}
 	default:
 		return nil, fmt.Errorf("content type %s not recognised", contentType)
 	}
 
+	if maxDimension > 0 {
+		// this leaves the image alone if it already fits
+		i = resize.Thumbnail(uint(maxDimension), uint(maxDimension), i, resize.Lanczos3)
+	}
+	i = orientImage(i, orientation)
+
 	width := i.Bounds().Size().X
 	height := i.Bounds().Size().Y
 	size := width * height
@@ -206,24 +302,32 @@ func deriveImage(b []byte, contentType string) (*imageAndMeta, error) {
 	}
 
 	out := &bytes.Buffer{}
-	if err := jpeg.Encode(out, i, &jpeg.Options{
-		Quality: 100,
-	}); err != nil {
+	switch contentType {
+	case MIMEPng:
+		err = png.Encode(out, i)
+	default:
+		err = jpeg.Encode(out, i, &jpeg.Options{
+			Quality: 100,
+		})
+	}
+	if err != nil {
 		return nil, err
 	}
 
 	return &imageAndMeta{
-		image:    out.Bytes(),
-		width:    width,
-		height:   height,
-		size:     size,
-		aspect:   aspect,
-		blurhash: bh,
+		image:       out.Bytes(),
+		contentType: contentType,
+		width:       width,
+		height:      height,
+		size:        size,
+		aspect:      aspect,
+		blurhash:    bh,
 	}, nil
 }
 
 // deriveThumbnail returns a byte slice and metadata for a thumbnail of width x and height y,
-// of a given jpeg, png, or gif, or an error if something goes wrong.
+// of a given jpeg, png, gif, or webp, or an error if something goes wrong. Thumbnails of animated
+// gifs and webps are taken from the first frame.
 //
 // Note that the aspect ratio of the image will be retained,
 // so it will not necessarily be a square, even if x and y are set as the same value.
@@ -247,10 +351,21 @@ func deriveThumbnail(b []byte, contentType string, x uint, y uint) (*imageAndMet
 		if err != nil {
 			return nil, err
 		}
+	case MIMEWebp:
+		i, err = decodeWebp(b)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("content type %s not recognised", contentType)
 	}
 
+	return deriveThumbnailFromImage(i, x, y)
+}
+
+// deriveThumbnailFromImage returns a byte slice and metadata for a jpeg thumbnail of width x and height y
+// of the given image, which might be a frame of a video, or an error if something goes wrong.
+func deriveThumbnailFromImage(i image.Image, x uint, y uint) (*imageAndMeta, error) {
 	thumb := resize.Thumbnail(x, y, i, resize.NearestNeighbor)
 	width := thumb.Bounds().Size().X
 	height := thumb.Bounds().Size().Y
@@ -302,12 +417,14 @@ func deriveStaticEmoji(b []byte, contentType string) (*imageAndMeta, error) {
 }
 
 type imageAndMeta struct {
-	image    []byte
-	width    int
-	height   int
-	size     int
-	aspect   float64
-	blurhash string
+	image []byte
+	// contentType of image, which may differ from that of the file it was derived from
+	contentType string
+	width       int
+	height      int
+	size        int
+	aspect      float64
+	blurhash    string
 }
 
 // ParseMediaType converts s to a recognized MediaType, or returns an error if unrecognized
@@ -100,7 +100,7 @@ func (suite *MediaUtilTestSuite) TestDeriveImageFromJPEG() {
 	assert.Nil(suite.T(), err)
 
 	// clean it up and validate the clean version
-	imageAndMeta, err := deriveImage(b, "image/jpeg")
+	imageAndMeta, err := deriveImage(b, "image/jpeg", 1, 0)
 	assert.Nil(suite.T(), err)
 
 	assert.Equal(suite.T(), 1920, imageAndMeta.width)
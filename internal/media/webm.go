@@ -0,0 +1,388 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// EBML element IDs used in webm (matroska) files.
+const (
+	ebmlIDHeader          = 0x1A45DFA3
+	ebmlIDSegment         = 0x18538067
+	ebmlIDInfo            = 0x1549A966
+	ebmlIDTimecodeScale   = 0x2AD7B1
+	ebmlIDDuration        = 0x4489
+	ebmlIDTitle           = 0x7BA9
+	ebmlIDDateUTC         = 0x4461
+	ebmlIDSegmentUID      = 0x73A4
+	ebmlIDTracks          = 0x1654AE6B
+	ebmlIDTrackEntry      = 0xAE
+	ebmlIDTrackNumber     = 0xD7
+	ebmlIDTrackType       = 0x83
+	ebmlIDDefaultDuration = 0x23E383
+	ebmlIDVideo           = 0xE0
+	ebmlIDPixelWidth      = 0xB0
+	ebmlIDPixelHeight     = 0xBA
+	ebmlIDTags            = 0x1254C367
+	ebmlIDAttachments     = 0x1941A469
+	ebmlIDCluster         = 0x1F43B675
+	ebmlIDTimecode        = 0xE7
+	ebmlIDSimpleBlock     = 0xA3
+	ebmlIDBlockGroup      = 0xA0
+	ebmlIDBlock           = 0xA1
+	ebmlIDVoid            = 0xEC
+
+	webmTrackTypeVideo = 1
+	webmTrackTypeAudio = 2
+)
+
+// webmMetadataElements are the elements that contain descriptive metadata like titles,
+// creation dates, and attached cover art.
+var webmMetadataElements = map[uint64]bool{
+	ebmlIDTitle:       true,
+	ebmlIDDateUTC:     true,
+	ebmlIDSegmentUID:  true,
+	ebmlIDTags:        true,
+	ebmlIDAttachments: true,
+}
+
+// ebmlElement is an element in an EBML document, such as a webm file.
+type ebmlElement struct {
+	id uint64
+	// start is the offset of the element ID in the file
+	start int
+	// payload is the offset of the element data in the file
+	payload int
+	// end is the offset of the first byte after the element, or -1 if the element has an unknown size
+	end int
+}
+
+// readEBMLVint reads a variable length integer from the start of b, returning the value and its length.
+// If keepMarker is true, the length marker bit is kept in the value, as it is for element IDs.
+func readEBMLVint(b []byte, keepMarker bool) (uint64, int, error) {
+	if len(b) == 0 || b[0] == 0 {
+		return 0, 0, errors.New("invalid variable length integer")
+	}
+
+	length := 1
+	marker := byte(0x80)
+	for b[0]&marker == 0 {
+		marker >>= 1
+		length++
+	}
+	if len(b) < length {
+		return 0, 0, errors.New("truncated variable length integer")
+	}
+
+	value := uint64(b[0])
+	if !keepMarker {
+		value = uint64(b[0] & (marker - 1))
+	}
+	for i := 1; i < length; i++ {
+		value = value<<8 | uint64(b[i])
+	}
+	return value, length, nil
+}
+
+// readEBMLElement reads the header of the element starting at offset.
+func readEBMLElement(b []byte, offset int, end int) (ebmlElement, error) {
+	id, idLength, err := readEBMLVint(b[offset:end], true)
+	if err != nil {
+		return ebmlElement{}, fmt.Errorf("error reading element id at offset %d: %s", offset, err)
+	}
+	size, sizeLength, err := readEBMLVint(b[offset+idLength:end], false)
+	if err != nil {
+		return ebmlElement{}, fmt.Errorf("error reading element size at offset %d: %s", offset, err)
+	}
+
+	e := ebmlElement{
+		id:      id,
+		start:   offset,
+		payload: offset + idLength + sizeLength,
+		end:     -1,
+	}
+	if size == 1<<(7*uint(sizeLength))-1 {
+		// all ones means the size is unknown, which is allowed for segments and clusters
+		return e, nil
+	}
+	if size > uint64(end-e.payload) {
+		return ebmlElement{}, fmt.Errorf("element %x at offset %d overflows its parent", id, offset)
+	}
+	e.end = e.payload + int(size)
+	return e, nil
+}
+
+// readEBMLElements reads the elements in b between start and end.
+func readEBMLElements(b []byte, start int, end int) ([]ebmlElement, error) {
+	elements := []ebmlElement{}
+	for offset := start; offset < end; {
+		e, err := readEBMLElement(b, offset, end)
+		if err != nil {
+			return nil, err
+		}
+		if e.end == -1 {
+			return nil, fmt.Errorf("element %x at offset %d has an unknown size", e.id, offset)
+		}
+		elements = append(elements, e)
+		offset = e.end
+	}
+	return elements, nil
+}
+
+// ebmlUint reads an unsigned integer element.
+func ebmlUint(b []byte, e ebmlElement) uint64 {
+	var value uint64
+	for _, c := range b[e.payload:e.end] {
+		value = value<<8 | uint64(c)
+	}
+	return value
+}
+
+// ebmlFloat reads a 4 or 8 byte float element.
+func ebmlFloat(b []byte, e ebmlElement) (float64, error) {
+	switch e.end - e.payload {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b[e.payload:e.end]))), nil
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(b[e.payload:e.end])), nil
+	}
+	return 0, fmt.Errorf("float element %x has invalid size %d", e.id, e.end-e.payload)
+}
+
+// voidEBMLElement overwrites the given element with a zeroed void element of the same length,
+// so that the offsets to later elements stay valid.
+func voidEBMLElement(b []byte, e ebmlElement) {
+	total := e.end - e.start
+	for i := e.start; i < e.end; i++ {
+		b[i] = 0
+	}
+	b[e.start] = ebmlIDVoid
+
+	// pick the shortest size length that can hold the remaining length
+	for sizeLength := 1; sizeLength <= 8; sizeLength++ {
+		size := uint64(total - 1 - sizeLength)
+		if total-1-sizeLength < 0 || size >= 1<<(7*uint(sizeLength))-1 {
+			continue
+		}
+		for i := sizeLength - 1; i >= 0; i-- {
+			b[e.start+1+i] = byte(size)
+			size >>= 8
+		}
+		b[e.start+1] |= 0x80 >> uint(sizeLength-1)
+		return
+	}
+}
+
+// webmTrack describes a track in a webm file.
+type webmTrack struct {
+	number          uint64
+	trackType       uint64
+	defaultDuration uint64
+	width           int
+	height          int
+}
+
+// decodeWebm reads the duration, dimensions, and frame rate of a webm file,
+// and returns a copy of the file with descriptive metadata wiped.
+//
+// Metadata elements are overwritten with void elements of the same size rather than removed,
+// so that the seek and cue positions in the file stay valid.
+func decodeWebm(b []byte) ([]byte, *audioVisualMeta, error) {
+	clean := make([]byte, len(b))
+	copy(clean, b)
+
+	header, err := readEBMLElement(clean, 0, len(clean))
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.id != ebmlIDHeader || header.end == -1 {
+		return nil, nil, errors.New("no EBML header found")
+	}
+	segment, err := readEBMLElement(clean, header.end, len(clean))
+	if err != nil {
+		return nil, nil, err
+	}
+	if segment.id != ebmlIDSegment {
+		return nil, nil, errors.New("no segment found")
+	}
+	segmentEnd := segment.end
+	if segmentEnd == -1 {
+		segmentEnd = len(clean)
+	}
+
+	timecodeScale := uint64(1000000)
+	var duration float64
+	tracks := []*webmTrack{}
+
+	// these are used to work out the duration and frame rate from the blocks themselves,
+	// if they're not given in the headers, which is often the case for recorded webm files
+	var clusterTimecode uint64
+	var lastTimecode uint64
+	blocks := map[uint64]int{}
+
+	for offset := segment.payload; offset < segmentEnd; {
+		e, err := readEBMLElement(clean, offset, segmentEnd)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if e.end == -1 {
+			// clusters can have an unknown size; just read through their children as if they were at this level
+			offset = e.payload
+			continue
+		}
+		offset = e.end
+
+		if webmMetadataElements[e.id] {
+			voidEBMLElement(clean, e)
+			continue
+		}
+
+		switch e.id {
+		case ebmlIDInfo:
+			children, err := readEBMLElements(clean, e.payload, e.end)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, c := range children {
+				switch c.id {
+				case ebmlIDTimecodeScale:
+					timecodeScale = ebmlUint(clean, c)
+				case ebmlIDDuration:
+					if duration, err = ebmlFloat(clean, c); err != nil {
+						return nil, nil, err
+					}
+				default:
+					if webmMetadataElements[c.id] {
+						voidEBMLElement(clean, c)
+					}
+				}
+			}
+		case ebmlIDTracks:
+			if tracks, err = decodeWebmTracks(clean, e); err != nil {
+				return nil, nil, err
+			}
+		case ebmlIDCluster:
+			// read through the cluster's children at this level
+			offset = e.payload
+		case ebmlIDTimecode:
+			clusterTimecode = ebmlUint(clean, e)
+		case ebmlIDBlockGroup:
+			// read through the block group's children at this level
+			offset = e.payload
+		case ebmlIDSimpleBlock, ebmlIDBlock:
+			trackNumber, length, err := readEBMLVint(clean[e.payload:e.end], false)
+			if err != nil || e.end-e.payload < length+2 {
+				return nil, nil, fmt.Errorf("invalid block at offset %d", e.start)
+			}
+			relative := int16(binary.BigEndian.Uint16(clean[e.payload+length : e.payload+length+2]))
+			if timecode := int64(clusterTimecode) + int64(relative); timecode > int64(lastTimecode) {
+				lastTimecode = uint64(timecode)
+			}
+			blocks[trackNumber]++
+		}
+	}
+
+	if timecodeScale == 0 {
+		return nil, nil, errors.New("timecode scale was 0")
+	}
+
+	// duration is given in timecode units, as is the timecode of each block
+	meta := &audioVisualMeta{}
+	if duration > 0 {
+		meta.duration = duration * float64(timecodeScale) / 1e9
+	} else {
+		meta.duration = float64(lastTimecode) * float64(timecodeScale) / 1e9
+	}
+
+	for _, track := range tracks {
+		switch track.trackType {
+		case webmTrackTypeAudio:
+			meta.hasAudio = true
+		case webmTrackTypeVideo:
+			if meta.hasVideo {
+				continue
+			}
+			meta.hasVideo = true
+			meta.width = track.width
+			meta.height = track.height
+			if track.defaultDuration > 0 {
+				// default duration is the nanoseconds per frame
+				meta.framerate = 1e9 / float64(track.defaultDuration)
+			} else if meta.duration > 0 {
+				meta.framerate = float64(blocks[track.number]) / meta.duration
+			}
+		}
+	}
+
+	if !meta.hasVideo && !meta.hasAudio {
+		return nil, nil, errors.New("no audio or video tracks found")
+	}
+
+	return clean, meta, nil
+}
+
+// decodeWebmTracks reads the track entries in a tracks element.
+func decodeWebmTracks(b []byte, e ebmlElement) ([]*webmTrack, error) {
+	entries, err := readEBMLElements(b, e.payload, e.end)
+	if err != nil {
+		return nil, err
+	}
+	tracks := []*webmTrack{}
+	for _, entry := range entries {
+		if entry.id != ebmlIDTrackEntry {
+			continue
+		}
+		fields, err := readEBMLElements(b, entry.payload, entry.end)
+		if err != nil {
+			return nil, err
+		}
+
+		track := &webmTrack{}
+		for _, f := range fields {
+			switch f.id {
+			case ebmlIDTrackNumber:
+				track.number = ebmlUint(b, f)
+			case ebmlIDTrackType:
+				track.trackType = ebmlUint(b, f)
+			case ebmlIDDefaultDuration:
+				track.defaultDuration = ebmlUint(b, f)
+			case ebmlIDVideo:
+				dimensions, err := readEBMLElements(b, f.payload, f.end)
+				if err != nil {
+					return nil, err
+				}
+				for _, d := range dimensions {
+					switch d.id {
+					case ebmlIDPixelWidth:
+						track.width = int(ebmlUint(b, d))
+					case ebmlIDPixelHeight:
+						track.height = int(ebmlUint(b, d))
+					}
+				}
+			}
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks, nil
+}
@@ -0,0 +1,243 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/buckket/go-blurhash"
+	"golang.org/x/image/webp"
+)
+
+// flags in the VP8X chunk of an extended webp file
+const (
+	webpFlagAnimation = 1 << 1
+	webpFlagXMP       = 1 << 2
+	webpFlagEXIF      = 1 << 3
+	webpFlagAlpha     = 1 << 4
+)
+
+// riffChunk is a chunk in a RIFF file, such as a webp file.
+type riffChunk struct {
+	id      string
+	payload []byte
+}
+
+// readWebpChunks reads the chunks of a webp file.
+func readWebpChunks(b []byte) ([]riffChunk, error) {
+	if len(b) < 12 || !bytes.Equal(b[0:4], []byte("RIFF")) || !bytes.Equal(b[8:12], []byte("WEBP")) {
+		return nil, errors.New("not a webp file")
+	}
+
+	chunks := []riffChunk{}
+	for offset := 12; offset+8 <= len(b); {
+		id := string(b[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(b[offset+4 : offset+8]))
+		if size > len(b)-offset-8 {
+			return nil, fmt.Errorf("chunk %s at offset %d overflows the file", id, offset)
+		}
+		chunks = append(chunks, riffChunk{
+			id:      id,
+			payload: b[offset+8 : offset+8+size],
+		})
+
+		// chunks are padded to an even length
+		offset += 8 + size + size%2
+	}
+	return chunks, nil
+}
+
+// writeWebpChunks writes the given chunks into a new webp file.
+func writeWebpChunks(chunks []riffChunk) []byte {
+	body := &bytes.Buffer{}
+	body.WriteString("WEBP")
+	for _, c := range chunks {
+		body.WriteString(c.id)
+		size := make([]byte, 4)
+		binary.LittleEndian.PutUint32(size, uint32(len(c.payload)))
+		body.Write(size)
+		body.Write(c.payload)
+		if len(c.payload)%2 != 0 {
+			body.WriteByte(0)
+		}
+	}
+
+	out := &bytes.Buffer{}
+	out.WriteString("RIFF")
+	size := make([]byte, 4)
+	binary.LittleEndian.PutUint32(size, uint32(body.Len()))
+	out.Write(size)
+	out.Write(body.Bytes())
+	return out.Bytes()
+}
+
+// webpVP8X returns the payload of the VP8X chunk of an extended webp file, or nil if it's a simple webp file.
+func webpVP8X(chunks []riffChunk) []byte {
+	for _, c := range chunks {
+		if c.id == "VP8X" && len(c.payload) >= 10 {
+			return c.payload
+		}
+	}
+	return nil
+}
+
+// webpAnimated returns true if the given webp file is animated.
+func webpAnimated(b []byte) bool {
+	chunks, err := readWebpChunks(b)
+	if err != nil {
+		return false
+	}
+	vp8x := webpVP8X(chunks)
+	return vp8x != nil && vp8x[0]&webpFlagAnimation != 0
+}
+
+// webpExif returns the exif data of the given webp file, if it has any.
+func webpExif(b []byte) []byte {
+	chunks, err := readWebpChunks(b)
+	if err != nil {
+		return nil
+	}
+	for _, c := range chunks {
+		if c.id == "EXIF" {
+			// some encoders include the jpeg exif header, which shouldn't be there
+			return bytes.TrimPrefix(c.payload, []byte("Exif\x00\x00"))
+		}
+	}
+	return nil
+}
+
+// purgeWebpMetadata returns a copy of the given webp file without its EXIF and XMP chunks.
+func purgeWebpMetadata(b []byte) ([]byte, error) {
+	chunks, err := readWebpChunks(b)
+	if err != nil {
+		return nil, err
+	}
+
+	clean := []riffChunk{}
+	for _, c := range chunks {
+		switch c.id {
+		case "EXIF", "XMP ":
+			continue
+		case "VP8X":
+			payload := make([]byte, len(c.payload))
+			copy(payload, c.payload)
+			payload[0] &^= webpFlagEXIF | webpFlagXMP
+			c.payload = payload
+		}
+		clean = append(clean, c)
+	}
+	return writeWebpChunks(clean), nil
+}
+
+// decodeWebp decodes the given webp file. For animated webp files, only the first frame is decoded.
+func decodeWebp(b []byte) (image.Image, error) {
+	if !webpAnimated(b) {
+		return webp.Decode(bytes.NewReader(b))
+	}
+
+	chunks, err := readWebpChunks(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// the golang webp decoder doesn't know about animation, so build a still webp file out of the first frame
+	for _, c := range chunks {
+		if c.id != "ANMF" {
+			continue
+		}
+		if len(c.payload) < 16 {
+			return nil, errors.New("animation frame too short")
+		}
+
+		// the frame header is 16 bytes: x, y, width - 1, height - 1, and duration as 24 bit ints, then flags
+		frameHeader := c.payload[0:16]
+		frameData, err := readWebpChunks(append([]byte("RIFF\x00\x00\x00\x00WEBP"), c.payload[16:]...))
+		if err != nil {
+			return nil, err
+		}
+
+		still := []riffChunk{}
+		for _, f := range frameData {
+			if f.id == "ALPH" {
+				// alpha data can only go in an extended webp file, with the dimensions in the VP8X chunk
+				vp8x := make([]byte, 10)
+				vp8x[0] = webpFlagAlpha
+				copy(vp8x[4:10], frameHeader[6:12])
+				still = append(still, riffChunk{id: "VP8X", payload: vp8x})
+				break
+			}
+		}
+		for _, f := range frameData {
+			switch f.id {
+			case "ALPH", "VP8 ", "VP8L":
+				still = append(still, f)
+			}
+		}
+
+		return webp.Decode(bytes.NewReader(writeWebpChunks(still)))
+	}
+
+	return nil, errors.New("animated webp has no frames")
+}
+
+// deriveAnimatedWebp returns the given animated webp, without any metadata, along with its dimensions
+// and a blurhash of its first frame.
+func deriveAnimatedWebp(b []byte) (*imageAndMeta, error) {
+	clean, err := purgeWebpMetadata(b)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks, err := readWebpChunks(clean)
+	if err != nil {
+		return nil, err
+	}
+	vp8x := webpVP8X(chunks)
+	if vp8x == nil {
+		return nil, errors.New("animated webp has no VP8X chunk")
+	}
+
+	// canvas width and height are stored minus one, as 24 bit ints
+	width := (int(vp8x[4]) | int(vp8x[5])<<8 | int(vp8x[6])<<16) + 1
+	height := (int(vp8x[7]) | int(vp8x[8])<<8 | int(vp8x[9])<<16) + 1
+
+	frame, err := decodeWebp(clean)
+	if err != nil {
+		return nil, err
+	}
+
+	bh, err := blurhash.Encode(4, 3, frame)
+	if err != nil {
+		return nil, err
+	}
+
+	return &imageAndMeta{
+		image:       clean,
+		contentType: MIMEWebp,
+		width:       width,
+		height:      height,
+		size:        width * height,
+		aspect:      float64(width) / float64(height),
+		blurhash:    bh,
+	}, nil
+}
@@ -0,0 +1,124 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package metrics collects prometheus metrics about what gotosocial is doing, for serving at the /metrics endpoint.
+//
+// Metrics are recorded into the package Registry rather than the prometheus default registry, so that only
+// gotosocial's own metrics, plus the standard go runtime and process metrics, are exposed.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "gotosocial"
+
+// Registry is the registry that all gotosocial metrics are recorded in.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of http requests handled, by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to handle http requests, by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	inboxPosts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "federation",
+		Name:      "inbox_posts_total",
+		Help:      "Number of POSTs to inboxes, by result and, for rejected POSTs, the reason they were rejected.",
+	}, []string{"result", "reason"})
+
+	deliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "federation",
+		Name:      "deliveries_total",
+		Help:      "Number of attempted deliveries of outgoing activities, by remote domain and result.",
+	}, []string{"domain", "result"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Time taken to run database queries, by database type and operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"type", "operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpRequestDuration,
+		inboxPosts,
+		deliveries,
+		dbQueryDuration,
+		sources,
+	)
+}
+
+// ObserveHTTPRequest records a handled http request. Route should be the route pattern that handled the request,
+// eg., "/users/:username/inbox", rather than the actual path, so that the number of different routes stays small.
+func ObserveHTTPRequest(route string, method string, status int, duration time.Duration) {
+	httpRequests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+	httpRequestDuration.WithLabelValues(route, method).Observe(duration.Seconds())
+}
+
+// InboxPostAccepted records an inbox POST that passed authentication.
+func InboxPostAccepted() {
+	inboxPosts.WithLabelValues("accepted", "").Inc()
+}
+
+// InboxPostRejected records an inbox POST that was rejected for the given reason.
+func InboxPostRejected(reason string) {
+	inboxPosts.WithLabelValues("rejected", reason).Inc()
+}
+
+// DeliverySucceeded records a successful delivery to an inbox on the given domain.
+func DeliverySucceeded(domain string) {
+	deliveries.WithLabelValues(domain, "success").Inc()
+}
+
+// DeliveryFailed records a failed delivery to an inbox on the given domain.
+func DeliveryFailed(domain string) {
+	deliveries.WithLabelValues(domain, "failure").Inc()
+}
+
+// ObserveDBQuery records a database query of the given operation, eg., "select" or "insert", which was started at the given time.
+//
+// It's intended to be deferred at the start of the function that runs the query:
+//
+//	defer metrics.ObserveDBQuery(db.DBTypeSQLite, "select", time.Now())
+func ObserveDBQuery(dbType string, operation string, start time.Time) {
+	dbQueryDuration.WithLabelValues(dbType, operation).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,92 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TimelineSizer reports the total number of posts held in memory by timelines.
+type TimelineSizer interface {
+	// GetTotalIndexedLength returns the number of posts indexed across all timelines.
+	GetTotalIndexedLength() int
+	// GetTotalPreparedLength returns the number of posts prepared across all timelines.
+	GetTotalPreparedLength() int
+}
+
+// QueueDepther reports the number of messages waiting to be processed.
+type QueueDepther interface {
+	// QueueDepth returns the number of messages currently in the queue.
+	QueueDepth() (int, error)
+}
+
+// sources is a collector for metrics that are read from other parts of gotosocial when they're scraped, rather than being recorded as they happen.
+var sources = &sourceCollector{
+	timelineIndexed:  prometheus.NewDesc(prometheus.BuildFQName(namespace, "timeline", "indexed_posts"), "Number of posts indexed across all timelines.", nil, nil),
+	timelinePrepared: prometheus.NewDesc(prometheus.BuildFQName(namespace, "timeline", "prepared_posts"), "Number of posts prepared across all timelines.", nil, nil),
+	queueDepth:       prometheus.NewDesc(prometheus.BuildFQName(namespace, "processor", "queue_depth"), "Number of messages waiting in the processor queue.", nil, nil),
+}
+
+// SetTimelineSizer sets where the timeline size metrics are read from.
+func SetTimelineSizer(t TimelineSizer) {
+	sources.mu.Lock()
+	defer sources.mu.Unlock()
+	sources.timelines = t
+}
+
+// SetQueueDepther sets where the processor queue depth metric is read from.
+func SetQueueDepther(q QueueDepther) {
+	sources.mu.Lock()
+	defer sources.mu.Unlock()
+	sources.queue = q
+}
+
+type sourceCollector struct {
+	timelineIndexed  *prometheus.Desc
+	timelinePrepared *prometheus.Desc
+	queueDepth       *prometheus.Desc
+
+	mu        sync.RWMutex
+	timelines TimelineSizer
+	queue     QueueDepther
+}
+
+func (s *sourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.timelineIndexed
+	ch <- s.timelinePrepared
+	ch <- s.queueDepth
+}
+
+func (s *sourceCollector) Collect(ch chan<- prometheus.Metric) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.timelines != nil {
+		ch <- prometheus.MustNewConstMetric(s.timelineIndexed, prometheus.GaugeValue, float64(s.timelines.GetTotalIndexedLength()))
+		ch <- prometheus.MustNewConstMetric(s.timelinePrepared, prometheus.GaugeValue, float64(s.timelines.GetTotalPreparedLength()))
+	}
+
+	if s.queue != nil {
+		if depth, err := s.queue.QueueDepth(); err == nil {
+			ch <- prometheus.MustNewConstMetric(s.queueDepth, prometheus.GaugeValue, float64(depth))
+		}
+	}
+}
@@ -75,7 +75,7 @@ func (suite *PgClientStoreTestSuite) SetupTest() {
 	}
 
 	for _, m := range models {
-		if err := suite.db.CreateTable(m); err != nil {
+		if err := suite.db.CreateTable(context.Background(), m); err != nil {
 			logrus.Panicf("db connection error: %s", err)
 		}
 	}
@@ -87,7 +87,7 @@ func (suite *PgClientStoreTestSuite) TearDownTest() {
 		&oauth.Client{},
 	}
 	for _, m := range models {
-		if err := suite.db.DropTable(m); err != nil {
+		if err := suite.db.DropTable(context.Background(), m); err != nil {
 			logrus.Panicf("error dropping table: %s", err)
 		}
 	}
@@ -19,6 +19,7 @@
 package account
 
 import (
+	"context"
 	"fmt"
 
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
@@ -29,10 +30,10 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
-func (p *processor) BlockCreate(requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+func (p *processor) BlockCreate(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
 	// make sure the target account actually exists in our db
 	targetAcct := &gtsmodel.Account{}
-	if err := p.db.GetByID(targetAccountID, targetAcct); err != nil {
+	if err := p.db.GetByID(ctx, targetAccountID, targetAcct); err != nil {
 		if _, ok := err.(db.ErrNoEntries); ok {
 			return nil, gtserror.NewErrorNotFound(fmt.Errorf("BlockCreate: account %s not found in the db: %s", targetAccountID, err))
 		}
@@ -40,12 +41,12 @@ func (p *processor) BlockCreate(requestingAccount *gtsmodel.Account, targetAccou
 
 	// if requestingAccount already blocks target account, we don't need to do anything
 	block := &gtsmodel.Block{}
-	if err := p.db.GetWhere([]db.Where{
+	if err := p.db.GetWhere(ctx, []db.Where{
 		{Key: "account_id", Value: requestingAccount.ID},
 		{Key: "target_account_id", Value: targetAccountID},
 	}, block); err == nil {
 		// block already exists, just return relationship
-		return p.RelationshipGet(requestingAccount, targetAccountID)
+		return p.RelationshipGet(ctx, requestingAccount, targetAccountID)
 	}
 
 	// make the block
@@ -61,18 +62,18 @@ func (p *processor) BlockCreate(requestingAccount *gtsmodel.Account, targetAccou
 	block.URI = util.GenerateURIForBlock(requestingAccount.Username, p.config.Protocol, p.config.Host, newBlockID)
 
 	// whack it in the database
-	if err := p.db.Put(block); err != nil {
+	if err := p.db.Put(ctx, block); err != nil {
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("BlockCreate: error creating block in db: %s", err))
 	}
 
 	// clear any follows or follow requests from the blocked account to the target account -- this is a simple delete
-	if err := p.db.DeleteWhere([]db.Where{
+	if err := p.db.DeleteWhere(ctx, []db.Where{
 		{Key: "account_id", Value: targetAccountID},
 		{Key: "target_account_id", Value: requestingAccount.ID},
 	}, &gtsmodel.Follow{}); err != nil {
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("BlockCreate: error removing follow in db: %s", err))
 	}
-	if err := p.db.DeleteWhere([]db.Where{
+	if err := p.db.DeleteWhere(ctx, []db.Where{
 		{Key: "account_id", Value: targetAccountID},
 		{Key: "target_account_id", Value: requestingAccount.ID},
 	}, &gtsmodel.FollowRequest{}); err != nil {
@@ -86,12 +87,12 @@ func (p *processor) BlockCreate(requestingAccount *gtsmodel.Account, targetAccou
 	var frChanged bool
 	var frURI string
 	fr := &gtsmodel.FollowRequest{}
-	if err := p.db.GetWhere([]db.Where{
+	if err := p.db.GetWhere(ctx, []db.Where{
 		{Key: "account_id", Value: requestingAccount.ID},
 		{Key: "target_account_id", Value: targetAccountID},
 	}, fr); err == nil {
 		frURI = fr.URI
-		if err := p.db.DeleteByID(fr.ID, fr); err != nil {
+		if err := p.db.DeleteByID(ctx, fr.ID, fr); err != nil {
 			return nil, gtserror.NewErrorInternalError(fmt.Errorf("BlockCreate: error removing follow request from db: %s", err))
 		}
 		frChanged = true
@@ -101,14 +102,18 @@ func (p *processor) BlockCreate(requestingAccount *gtsmodel.Account, targetAccou
 	var fChanged bool
 	var fURI string
 	f := &gtsmodel.Follow{}
-	if err := p.db.GetWhere([]db.Where{
+	if err := p.db.GetWhere(ctx, []db.Where{
 		{Key: "account_id", Value: requestingAccount.ID},
 		{Key: "target_account_id", Value: targetAccountID},
 	}, f); err == nil {
 		fURI = f.URI
-		if err := p.db.DeleteByID(f.ID, f); err != nil {
+		if err := p.db.DeleteByID(ctx, f.ID, f); err != nil {
 			return nil, gtserror.NewErrorInternalError(fmt.Errorf("BlockCreate: error removing follow from db: %s", err))
 		}
+		// the target account can't be in any of our lists anymore now that we don't follow it
+		if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "follow_id", Value: f.ID}}, &[]*gtsmodel.ListEntry{}); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("BlockCreate: error removing list entries from db: %s", err))
+		}
 		fChanged = true
 	}
 
@@ -151,5 +156,5 @@ func (p *processor) BlockCreate(requestingAccount *gtsmodel.Account, targetAccou
 		TargetAccount:  targetAcct,
 	}
 
-	return p.RelationshipGet(requestingAccount, targetAccountID)
+	return p.RelationshipGet(ctx, requestingAccount, targetAccountID)
 }
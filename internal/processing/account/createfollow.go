@@ -19,6 +19,7 @@
 package account
 
 import (
+	"context"
 	"fmt"
 
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
@@ -29,9 +30,9 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
-func (p *processor) FollowCreate(requestingAccount *gtsmodel.Account, form *apimodel.AccountFollowRequest) (*apimodel.Relationship, gtserror.WithCode) {
+func (p *processor) FollowCreate(ctx context.Context, requestingAccount *gtsmodel.Account, form *apimodel.AccountFollowRequest) (*apimodel.Relationship, gtserror.WithCode) {
 	// if there's a block between the accounts we shouldn't create the request ofc
-	blocked, err := p.db.Blocked(requestingAccount.ID, form.TargetAccountID)
+	blocked, err := p.db.Blocked(ctx, requestingAccount.ID, form.TargetAccountID)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
@@ -41,30 +42,30 @@ func (p *processor) FollowCreate(requestingAccount *gtsmodel.Account, form *apim
 
 	// make sure the target account actually exists in our db
 	targetAcct := &gtsmodel.Account{}
-	if err := p.db.GetByID(form.TargetAccountID, targetAcct); err != nil {
+	if err := p.db.GetByID(ctx, form.TargetAccountID, targetAcct); err != nil {
 		if _, ok := err.(db.ErrNoEntries); ok {
 			return nil, gtserror.NewErrorNotFound(fmt.Errorf("accountfollowcreate: account %s not found in the db: %s", form.TargetAccountID, err))
 		}
 	}
 
 	// check if a follow exists already
-	follows, err := p.db.Follows(requestingAccount, targetAcct)
+	follows, err := p.db.Follows(ctx, requestingAccount, targetAcct)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("accountfollowcreate: error checking follow in db: %s", err))
 	}
 	if follows {
 		// already follows so just return the relationship
-		return p.RelationshipGet(requestingAccount, form.TargetAccountID)
+		return p.RelationshipGet(ctx, requestingAccount, form.TargetAccountID)
 	}
 
 	// check if a follow exists already
-	followRequested, err := p.db.FollowRequested(requestingAccount, targetAcct)
+	followRequested, err := p.db.FollowRequested(ctx, requestingAccount, targetAcct)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("accountfollowcreate: error checking follow request in db: %s", err))
 	}
 	if followRequested {
 		// already follow requested so just return the relationship
-		return p.RelationshipGet(requestingAccount, form.TargetAccountID)
+		return p.RelationshipGet(ctx, requestingAccount, form.TargetAccountID)
 	}
 
 	// make the follow request
@@ -89,17 +90,17 @@ func (p *processor) FollowCreate(requestingAccount *gtsmodel.Account, form *apim
 	}
 
 	// whack it in the database
-	if err := p.db.Put(fr); err != nil {
+	if err := p.db.Put(ctx, fr); err != nil {
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("accountfollowcreate: error creating follow request in db: %s", err))
 	}
 
 	// if it's a local account that's not locked we can just straight up accept the follow request
 	if !targetAcct.Locked && targetAcct.Domain == "" {
-		if _, err := p.db.AcceptFollowRequest(requestingAccount.ID, form.TargetAccountID); err != nil {
+		if _, err := p.db.AcceptFollowRequest(ctx, requestingAccount.ID, form.TargetAccountID); err != nil {
 			return nil, gtserror.NewErrorInternalError(fmt.Errorf("accountfollowcreate: error accepting folow request for local unlocked account: %s", err))
 		}
 		// return the new relationship
-		return p.RelationshipGet(requestingAccount, form.TargetAccountID)
+		return p.RelationshipGet(ctx, requestingAccount, form.TargetAccountID)
 	}
 
 	// otherwise we leave the follow request as it is and we handle the rest of the process asynchronously
@@ -112,5 +113,5 @@ func (p *processor) FollowCreate(requestingAccount *gtsmodel.Account, form *apim
 	}
 
 	// return whatever relationship results from this
-	return p.RelationshipGet(requestingAccount, form.TargetAccountID)
+	return p.RelationshipGet(ctx, requestingAccount, form.TargetAccountID)
 }
@@ -0,0 +1,99 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+func (p *processor) MuteCreate(ctx context.Context, requestingAccount *gtsmodel.Account, form *apimodel.AccountMuteRequest) (*apimodel.Relationship, gtserror.WithCode) {
+	if form.TargetAccountID == requestingAccount.ID {
+		err := errors.New("MuteCreate: account can't mute itself")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if form.Duration < 0 {
+		err := errors.New("MuteCreate: duration can't be negative")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// make sure the target account actually exists in our db
+	targetAcct := &gtsmodel.Account{}
+	if err := p.db.GetByID(ctx, form.TargetAccountID, targetAcct); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("MuteCreate: account %s not found in the db: %s", form.TargetAccountID, err))
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("MuteCreate: error getting account %s from the db: %s", form.TargetAccountID, err))
+	}
+
+	// mastodon mutes notifications too unless told otherwise
+	notifications := true
+	if form.Notifications != nil {
+		notifications = *form.Notifications
+	}
+
+	var expiresAt time.Time
+	if form.Duration > 0 {
+		expiresAt = time.Now().Add(time.Duration(form.Duration) * time.Second)
+	}
+
+	// if requestingAccount already mutes target account, muting again just updates the mute
+	mute := &gtsmodel.Mute{}
+	if err := p.db.GetWhere(ctx, []db.Where{
+		{Key: "account_id", Value: requestingAccount.ID},
+		{Key: "target_account_id", Value: form.TargetAccountID},
+	}, mute); err == nil {
+		mute.Notifications = notifications
+		mute.ExpiresAt = expiresAt
+		mute.UpdatedAt = time.Now()
+		if err := p.db.UpdateByID(ctx, mute.ID, mute); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("MuteCreate: error updating mute in db: %s", err))
+		}
+		return p.RelationshipGet(ctx, requestingAccount, form.TargetAccountID)
+	} else if _, ok := err.(db.ErrNoEntries); !ok {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("MuteCreate: error checking for existing mute in db: %s", err))
+	}
+
+	// make the mute
+	newMuteID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	mute.ID = newMuteID
+	mute.AccountID = requestingAccount.ID
+	mute.TargetAccountID = form.TargetAccountID
+	mute.Notifications = notifications
+	mute.ExpiresAt = expiresAt
+
+	// whack it in the database
+	if err := p.db.Put(ctx, mute); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("MuteCreate: error creating mute in db: %s", err))
+	}
+
+	return p.RelationshipGet(ctx, requestingAccount, form.TargetAccountID)
+}
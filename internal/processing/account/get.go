@@ -19,6 +19,7 @@
 package account
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -27,9 +28,9 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (p *processor) Get(requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Account, error) {
+func (p *processor) Get(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Account, error) {
 	targetAccount := &gtsmodel.Account{}
-	if err := p.db.GetByID(targetAccountID, targetAccount); err != nil {
+	if err := p.db.GetByID(ctx, targetAccountID, targetAccount); err != nil {
 		if _, ok := err.(db.ErrNoEntries); ok {
 			return nil, errors.New("account not found")
 		}
@@ -41,14 +42,14 @@ func (p *processor) Get(requestingAccount *gtsmodel.Account, targetAccountID str
 	if requestingAccount != nil {
 		requestingUsername = requestingAccount.Username
 	}
-	if err := p.federator.DereferenceAccountFields(targetAccount, requestingUsername, false); err != nil {
+	if err := p.federator.DereferenceAccountFields(ctx, targetAccount, requestingUsername, false); err != nil {
 		p.log.WithField("func", "AccountGet").Debugf("dereferencing account: %s", err)
 	}
 
 	var blocked bool
 	var err error
 	if requestingAccount != nil {
-		blocked, err = p.db.Blocked(requestingAccount.ID, targetAccountID)
+		blocked, err = p.db.Blocked(ctx, requestingAccount.ID, targetAccountID)
 		if err != nil {
 			return nil, fmt.Errorf("error checking account block: %s", err)
 		}
@@ -58,9 +59,9 @@ func (p *processor) Get(requestingAccount *gtsmodel.Account, targetAccountID str
 	if blocked {
 		mastoAccount, err = p.tc.AccountToMastoBlocked(targetAccount)
 	} else if requestingAccount != nil && targetAccount.ID == requestingAccount.ID {
-		mastoAccount, err = p.tc.AccountToMastoSensitive(targetAccount)
+		mastoAccount, err = p.tc.AccountToMastoSensitive(ctx, targetAccount)
 	} else {
-		mastoAccount, err = p.tc.AccountToMastoPublic(targetAccount)
+		mastoAccount, err = p.tc.AccountToMastoPublic(ctx, targetAccount)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("error converting account: %s", err)
@@ -19,6 +19,7 @@
 package account
 
 import (
+	"context"
 	"fmt"
 
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
@@ -27,8 +28,8 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (p *processor) FollowersGet(requestingAccount *gtsmodel.Account, targetAccountID string) ([]apimodel.Account, gtserror.WithCode) {
-	blocked, err := p.db.Blocked(requestingAccount.ID, targetAccountID)
+func (p *processor) FollowersGet(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) ([]apimodel.Account, gtserror.WithCode) {
+	blocked, err := p.db.Blocked(ctx, requestingAccount.ID, targetAccountID)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
@@ -39,7 +40,7 @@ func (p *processor) FollowersGet(requestingAccount *gtsmodel.Account, targetAcco
 
 	followers := []gtsmodel.Follow{}
 	accounts := []apimodel.Account{}
-	if err := p.db.GetFollowersByAccountID(targetAccountID, &followers, false); err != nil {
+	if err := p.db.GetFollowersByAccountID(ctx, targetAccountID, &followers, false); err != nil {
 		if _, ok := err.(db.ErrNoEntries); ok {
 			return accounts, nil
 		}
@@ -47,7 +48,7 @@ func (p *processor) FollowersGet(requestingAccount *gtsmodel.Account, targetAcco
 	}
 
 	for _, f := range followers {
-		blocked, err := p.db.Blocked(requestingAccount.ID, f.AccountID)
+		blocked, err := p.db.Blocked(ctx, requestingAccount.ID, f.AccountID)
 		if err != nil {
 			return nil, gtserror.NewErrorInternalError(err)
 		}
@@ -56,7 +57,7 @@ func (p *processor) FollowersGet(requestingAccount *gtsmodel.Account, targetAcco
 		}
 
 		a := &gtsmodel.Account{}
-		if err := p.db.GetByID(f.AccountID, a); err != nil {
+		if err := p.db.GetByID(ctx, f.AccountID, a); err != nil {
 			if _, ok := err.(db.ErrNoEntries); ok {
 				continue
 			}
@@ -64,12 +65,12 @@ func (p *processor) FollowersGet(requestingAccount *gtsmodel.Account, targetAcco
 		}
 
 		// derefence account fields in case we haven't done it already
-		if err := p.federator.DereferenceAccountFields(a, requestingAccount.Username, false); err != nil {
+		if err := p.federator.DereferenceAccountFields(ctx, a, requestingAccount.Username, false); err != nil {
 			// don't bail if we can't fetch them, we'll try another time
 			p.log.WithField("func", "AccountFollowersGet").Debugf("error dereferencing account fields: %s", err)
 		}
 
-		account, err := p.tc.AccountToMastoPublic(a)
+		account, err := p.tc.AccountToMastoPublic(ctx, a)
 		if err != nil {
 			return nil, gtserror.NewErrorInternalError(err)
 		}
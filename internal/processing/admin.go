@@ -19,31 +19,65 @@
 package processing
 
 import (
+	"context"
+
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
-func (p *processor) AdminEmojiCreate(authed *oauth.Auth, form *apimodel.EmojiCreateRequest) (*apimodel.Emoji, error) {
-	return p.adminProcessor.EmojiCreate(authed.Account, authed.User, form)
+func (p *processor) AdminEmojiCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.EmojiCreateRequest) (*apimodel.Emoji, error) {
+	return p.adminProcessor.EmojiCreate(ctx, authed.Account, authed.User, form)
+}
+
+func (p *processor) AdminDomainBlockCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) (*apimodel.DomainBlock, gtserror.WithCode) {
+	return p.adminProcessor.DomainBlockCreate(ctx, authed.Account, form.Domain, form.Obfuscate, form.PublicComment, form.PrivateComment, "")
+}
+
+func (p *processor) AdminDomainBlocksImport(ctx context.Context, authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) ([]*apimodel.DomainBlock, gtserror.WithCode) {
+	return p.adminProcessor.DomainBlocksImport(ctx, authed.Account, form.Domains)
+}
+
+func (p *processor) AdminDomainBlocksGet(ctx context.Context, authed *oauth.Auth, export bool) ([]*apimodel.DomainBlock, gtserror.WithCode) {
+	return p.adminProcessor.DomainBlocksGet(ctx, authed.Account, export)
+}
+
+func (p *processor) AdminDomainBlockGet(ctx context.Context, authed *oauth.Auth, id string, export bool) (*apimodel.DomainBlock, gtserror.WithCode) {
+	return p.adminProcessor.DomainBlockGet(ctx, authed.Account, id, export)
+}
+
+func (p *processor) AdminDomainBlockDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.DomainBlock, gtserror.WithCode) {
+	return p.adminProcessor.DomainBlockDelete(ctx, authed.Account, id)
+}
+
+func (p *processor) AdminDeliveriesGet(ctx context.Context, authed *oauth.Auth, domain string) ([]*apimodel.AdminDeliveryInfo, gtserror.WithCode) {
+	return p.adminProcessor.DeliveriesGet(ctx, authed.Account, domain)
+}
+
+func (p *processor) AdminReportsGet(ctx context.Context, authed *oauth.Auth, resolved bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportsGet(ctx, authed.Account, resolved, accountID, targetAccountID, maxID, sinceID, limit)
+}
+
+func (p *processor) AdminReportGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportGet(ctx, authed.Account, id)
 }
 
-func (p *processor) AdminDomainBlockCreate(authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) (*apimodel.DomainBlock, gtserror.WithCode) {
-	return p.adminProcessor.DomainBlockCreate(authed.Account, form.Domain, form.Obfuscate, form.PublicComment, form.PrivateComment, "")
+func (p *processor) AdminReportAssign(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportAssign(ctx, authed.Account, id)
 }
 
-func (p *processor) AdminDomainBlocksImport(authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) ([]*apimodel.DomainBlock, gtserror.WithCode) {
-	return p.adminProcessor.DomainBlocksImport(authed.Account, form.Domains)
+func (p *processor) AdminReportUnassign(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportUnassign(ctx, authed.Account, id)
 }
 
-func (p *processor) AdminDomainBlocksGet(authed *oauth.Auth, export bool) ([]*apimodel.DomainBlock, gtserror.WithCode) {
-	return p.adminProcessor.DomainBlocksGet(authed.Account, export)
+func (p *processor) AdminReportNote(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminReportNoteRequest) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportNote(ctx, authed.Account, id, form.Note)
 }
 
-func (p *processor) AdminDomainBlockGet(authed *oauth.Auth, id string, export bool) (*apimodel.DomainBlock, gtserror.WithCode) {
-	return p.adminProcessor.DomainBlockGet(authed.Account, id, export)
+func (p *processor) AdminReportResolve(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminReportResolveRequest) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportResolve(ctx, authed.Account, id, form.Action)
 }
 
-func (p *processor) AdminDomainBlockDelete(authed *oauth.Auth, id string) (*apimodel.DomainBlock, gtserror.WithCode) {
-	return p.adminProcessor.DomainBlockDelete(authed.Account, id)
+func (p *processor) AdminReportReopen(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportReopen(ctx, authed.Account, id)
 }
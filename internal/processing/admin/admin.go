@@ -19,6 +19,7 @@
 package admin
 
 import (
+	"context"
 	"mime/multipart"
 
 	"github.com/sirupsen/logrus"
@@ -33,12 +34,20 @@ import (
 
 // Processor wraps a bunch of functions for processing admin actions.
 type Processor interface {
-	DomainBlockCreate(account *gtsmodel.Account, domain string, obfuscate bool, publicComment string, privateComment string, subscriptionID string) (*apimodel.DomainBlock, gtserror.WithCode)
-	DomainBlocksImport(account *gtsmodel.Account, domains *multipart.FileHeader) ([]*apimodel.DomainBlock, gtserror.WithCode)
-	DomainBlocksGet(account *gtsmodel.Account, export bool) ([]*apimodel.DomainBlock, gtserror.WithCode)
-	DomainBlockGet(account *gtsmodel.Account, id string, export bool) (*apimodel.DomainBlock, gtserror.WithCode)
-	DomainBlockDelete(account *gtsmodel.Account, id string) (*apimodel.DomainBlock, gtserror.WithCode)
-	EmojiCreate(account *gtsmodel.Account, user *gtsmodel.User, form *apimodel.EmojiCreateRequest) (*apimodel.Emoji, error)
+	DomainBlockCreate(ctx context.Context, account *gtsmodel.Account, domain string, obfuscate bool, publicComment string, privateComment string, subscriptionID string) (*apimodel.DomainBlock, gtserror.WithCode)
+	DomainBlocksImport(ctx context.Context, account *gtsmodel.Account, domains *multipart.FileHeader) ([]*apimodel.DomainBlock, gtserror.WithCode)
+	DomainBlocksGet(ctx context.Context, account *gtsmodel.Account, export bool) ([]*apimodel.DomainBlock, gtserror.WithCode)
+	DomainBlockGet(ctx context.Context, account *gtsmodel.Account, id string, export bool) (*apimodel.DomainBlock, gtserror.WithCode)
+	DomainBlockDelete(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.DomainBlock, gtserror.WithCode)
+	DeliveriesGet(ctx context.Context, account *gtsmodel.Account, domain string) ([]*apimodel.AdminDeliveryInfo, gtserror.WithCode)
+	EmojiCreate(ctx context.Context, account *gtsmodel.Account, user *gtsmodel.User, form *apimodel.EmojiCreateRequest) (*apimodel.Emoji, error)
+	ReportsGet(ctx context.Context, account *gtsmodel.Account, resolved bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*apimodel.AdminReportInfo, gtserror.WithCode)
+	ReportGet(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	ReportAssign(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	ReportUnassign(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	ReportNote(ctx context.Context, account *gtsmodel.Account, id string, note string) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	ReportResolve(ctx context.Context, account *gtsmodel.Account, id string, action string) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	ReportReopen(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
 }
 
 type processor struct {
@@ -0,0 +1,61 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (p *processor) DeliveriesGet(ctx context.Context, account *gtsmodel.Account, domain string) ([]*apimodel.AdminDeliveryInfo, gtserror.WithCode) {
+	stats, err := p.db.GetDeliveryStats(ctx, domain)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			// something has gone really wrong
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	mastoInfos := []*apimodel.AdminDeliveryInfo{}
+	for _, s := range stats {
+		info := &apimodel.AdminDeliveryInfo{
+			Domain:    s.Domain,
+			Pending:   s.Pending,
+			Failed:    s.Failed,
+			LastError: s.LastError,
+		}
+		if !s.NextAttemptAt.IsZero() {
+			info.NextAttemptAt = s.NextAttemptAt.Format(time.RFC3339)
+		}
+		if !s.LastAttemptAt.IsZero() {
+			info.LastAttemptAt = s.LastAttemptAt.Format(time.RFC3339)
+		}
+		if !s.UnreachableSince.IsZero() {
+			info.UnreachableSince = s.UnreachableSince.Format(time.RFC3339)
+		}
+		mastoInfos = append(mastoInfos, info)
+	}
+
+	return mastoInfos, nil
+}
@@ -0,0 +1,211 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (p *processor) ReportsGet(ctx context.Context, account *gtsmodel.Account, resolved bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*apimodel.AdminReportInfo, gtserror.WithCode) {
+	mastoReports := []*apimodel.AdminReportInfo{}
+
+	reports, err := p.db.GetReports(ctx, resolved, accountID, targetAccountID, maxID, sinceID, limit)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			// there are just no entries
+			return mastoReports, nil
+		}
+		// there's an actual error
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	for _, r := range reports {
+		mastoReport, err := p.tc.ReportToAdminMasto(ctx, r, account)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		mastoReports = append(mastoReports, mastoReport)
+	}
+
+	return mastoReports, nil
+}
+
+func (p *processor) ReportGet(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	report, errWithCode := p.getReport(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.reportToAdminMasto(ctx, report, account)
+}
+
+func (p *processor) ReportAssign(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.updateReport(ctx, account, id, func(report *gtsmodel.Report) gtserror.WithCode {
+		report.AssignedAccountID = account.ID
+		return nil
+	})
+}
+
+func (p *processor) ReportUnassign(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.updateReport(ctx, account, id, func(report *gtsmodel.Report) gtserror.WithCode {
+		report.AssignedAccountID = ""
+		return nil
+	})
+}
+
+func (p *processor) ReportNote(ctx context.Context, account *gtsmodel.Account, id string, note string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.updateReport(ctx, account, id, func(report *gtsmodel.Report) gtserror.WithCode {
+		report.Note = note
+		return nil
+	})
+}
+
+func (p *processor) ReportResolve(ctx context.Context, account *gtsmodel.Account, id string, action string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	reportAction := gtsmodel.ReportAction(action)
+	switch reportAction {
+	case "":
+		reportAction = gtsmodel.ReportActionNone
+	case gtsmodel.ReportActionNone, gtsmodel.ReportActionSilence, gtsmodel.ReportActionSuspend, gtsmodel.ReportActionDeleteStatus:
+	default:
+		err := fmt.Errorf("action %s not recognized, must be one of none, silence, suspend or delete_status", action)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	return p.updateReport(ctx, account, id, func(report *gtsmodel.Report) gtserror.WithCode {
+		if !report.ActionTakenAt.IsZero() {
+			err := fmt.Errorf("report %s has already been resolved", report.ID)
+			return gtserror.NewErrorBadRequest(err, err.Error())
+		}
+
+		if errWithCode := p.takeReportAction(ctx, account, report, reportAction); errWithCode != nil {
+			return errWithCode
+		}
+
+		report.ActionTaken = reportAction
+		report.ActionTakenAt = time.Now()
+		report.ActionTakenByAccountID = account.ID
+		return nil
+	})
+}
+
+func (p *processor) ReportReopen(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.updateReport(ctx, account, id, func(report *gtsmodel.Report) gtserror.WithCode {
+		report.ActionTaken = ""
+		report.ActionTakenAt = time.Time{}
+		report.ActionTakenByAccountID = ""
+		return nil
+	})
+}
+
+// takeReportAction carries out the given action against the account or statuses that were reported.
+func (p *processor) takeReportAction(ctx context.Context, account *gtsmodel.Account, report *gtsmodel.Report, action gtsmodel.ReportAction) gtserror.WithCode {
+	targetAccount := &gtsmodel.Account{}
+	if err := p.db.GetByID(ctx, report.TargetAccountID, targetAccount); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error getting reported account %s: %s", report.TargetAccountID, err))
+	}
+
+	switch action {
+	case gtsmodel.ReportActionSilence:
+		if err := p.db.UpdateOneByID(ctx, targetAccount.ID, "silenced_at", time.Now(), &gtsmodel.Account{}); err != nil {
+			return gtserror.NewErrorInternalError(fmt.Errorf("error silencing account %s: %s", targetAccount.ID, err))
+		}
+	case gtsmodel.ReportActionSuspend:
+		// the account is deleted asynchronously through the normal account deletion process
+		p.fromClientAPI <- gtsmodel.FromClientAPI{
+			APObjectType:   gtsmodel.ActivityStreamsPerson,
+			APActivityType: gtsmodel.ActivityStreamsDelete,
+			GTSModel:       report,
+			OriginAccount:  account,
+			TargetAccount:  targetAccount,
+		}
+	case gtsmodel.ReportActionDeleteStatus:
+		for _, statusID := range report.StatusIDs {
+			status := &gtsmodel.Status{}
+			if err := p.db.GetByID(ctx, statusID, status); err != nil {
+				if _, ok := err.(db.ErrNoEntries); ok {
+					// status is already gone
+					continue
+				}
+				return gtserror.NewErrorInternalError(fmt.Errorf("error getting reported status %s: %s", statusID, err))
+			}
+
+			if err := p.db.DeleteByID(ctx, status.ID, &gtsmodel.Status{}); err != nil {
+				return gtserror.NewErrorInternalError(fmt.Errorf("error deleting reported status %s: %s", statusID, err))
+			}
+
+			// the rest of the status is cleaned up asynchronously, in the same way as if its author had deleted it
+			p.fromClientAPI <- gtsmodel.FromClientAPI{
+				APObjectType:   gtsmodel.ActivityStreamsNote,
+				APActivityType: gtsmodel.ActivityStreamsDelete,
+				GTSModel:       status,
+				OriginAccount:  targetAccount,
+				TargetAccount:  targetAccount,
+			}
+		}
+	}
+
+	return nil
+}
+
+// updateReport gets the report with the given id, applies the given update function to it, and stores the result.
+func (p *processor) updateReport(ctx context.Context, account *gtsmodel.Account, id string, update func(report *gtsmodel.Report) gtserror.WithCode) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	report, errWithCode := p.getReport(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if errWithCode := update(report); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	report.UpdatedAt = time.Now()
+	if err := p.db.UpdateByID(ctx, report.ID, report); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating report %s: %s", report.ID, err))
+	}
+
+	return p.reportToAdminMasto(ctx, report, account)
+}
+
+func (p *processor) getReport(ctx context.Context, id string) (*gtsmodel.Report, gtserror.WithCode) {
+	report := &gtsmodel.Report{}
+	if err := p.db.GetByID(ctx, id, report); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			// something has gone really wrong
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		// there are no entries for this ID
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no entry for ID %s", id))
+	}
+	return report, nil
+}
+
+func (p *processor) reportToAdminMasto(ctx context.Context, report *gtsmodel.Report, account *gtsmodel.Account) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	mastoReport, err := p.tc.ReportToAdminMasto(ctx, report, account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	return mastoReport, nil
+}
@@ -19,6 +19,7 @@
 package processing
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 
@@ -28,8 +29,8 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
-func (p *processor) BlocksGet(authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.BlocksResponse, gtserror.WithCode) {
-	accounts, nextMaxID, prevMinID, err := p.db.GetBlocksForAccount(authed.Account.ID, maxID, sinceID, limit)
+func (p *processor) BlocksGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.BlocksResponse, gtserror.WithCode) {
+	accounts, nextMaxID, prevMinID, err := p.db.GetBlocksForAccount(ctx, authed.Account.ID, maxID, sinceID, limit)
 	if err != nil {
 		if _, ok := err.(db.ErrNoEntries); ok {
 			// there are just no entries
@@ -0,0 +1,78 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type BookmarkTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *BookmarkTestSuite) TestBookmarkedTimeline() {
+	ctx := context.Background()
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+
+	resp, errWithCode := suite.processor.BookmarkedTimelineGet(ctx, authed, "", "", 20)
+	suite.Nil(errWithCode)
+	suite.Empty(resp.Statuses)
+	suite.Empty(resp.LinkHeader)
+
+	// bookmark two statuses, the most recently bookmarked one should come first
+	for _, statusName := range []string{"admin_account_status_1", "local_account_2_status_1"} {
+		_, errWithCode := suite.processor.StatusBookmark(ctx, authed, suite.testStatuses[statusName].ID)
+		suite.Nil(errWithCode)
+		// make sure the bookmark IDs don't end up in the same millisecond
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	resp, errWithCode = suite.processor.BookmarkedTimelineGet(ctx, authed, "", "", 20)
+	suite.Nil(errWithCode)
+	suite.Equal([]string{suite.testStatuses["local_account_2_status_1"].ID, suite.testStatuses["admin_account_status_1"].ID}, statusIDs(resp.Statuses))
+	suite.NotEmpty(resp.LinkHeader)
+
+	// paging should work
+	resp, errWithCode = suite.processor.BookmarkedTimelineGet(ctx, authed, "", "", 1)
+	suite.Nil(errWithCode)
+	suite.Equal([]string{suite.testStatuses["local_account_2_status_1"].ID}, statusIDs(resp.Statuses))
+	suite.Contains(resp.LinkHeader, "api/v1/bookmarks")
+
+	// local_account_2 blocks local_account_1, so their bookmarked status should disappear
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.Block{
+		ID:              "01FGRY7WYN8B5QHS0NGDKHE5G9",
+		AccountID:       suite.testAccounts["local_account_2"].ID,
+		TargetAccountID: suite.testAccounts["local_account_1"].ID,
+		URI:             "http://localhost:8080/users/1happyturtle/blocks/01FGRY7WYN8B5QHS0NGDKHE5G9",
+	}))
+
+	resp, errWithCode = suite.processor.BookmarkedTimelineGet(ctx, authed, "", "", 20)
+	suite.Nil(errWithCode)
+	suite.Equal([]string{suite.testStatuses["admin_account_status_1"].ID}, statusIDs(resp.Statuses))
+}
+
+func TestBookmarkTestSuite(t *testing.T) {
+	suite.Run(t, new(BookmarkTestSuite))
+}
@@ -0,0 +1,350 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+func (p *processor) ConversationsGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.ConversationsResponse, gtserror.WithCode) {
+	resp := &apimodel.ConversationsResponse{
+		Conversations: []*apimodel.Conversation{},
+	}
+
+	conversations, err := p.db.GetConversationsForAccount(ctx, authed.Account.ID, maxID, sinceID, limit)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			// there are just no entries
+			return resp, nil
+		}
+		// there's an actual error
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	for _, c := range conversations {
+		apiConversation, err := p.tc.ConversationToMasto(ctx, c, authed.Account)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("ConversationsGet: error converting conversation %s to api representation: %s", c.ID, err))
+		}
+		resp.Conversations = append(resp.Conversations, apiConversation)
+	}
+
+	// prepare the next and previous links; conversations are paged by their last status
+	nextLink := &url.URL{
+		Scheme:   p.config.Protocol,
+		Host:     p.config.Host,
+		Path:     "/api/v1/conversations",
+		RawQuery: fmt.Sprintf("limit=%d&max_id=%s", limit, conversations[len(conversations)-1].LastStatusID),
+	}
+	next := fmt.Sprintf("<%s>; rel=\"next\"", nextLink.String())
+
+	prevLink := &url.URL{
+		Scheme:   p.config.Protocol,
+		Host:     p.config.Host,
+		Path:     "/api/v1/conversations",
+		RawQuery: fmt.Sprintf("limit=%d&since_id=%s", limit, conversations[0].LastStatusID),
+	}
+	prev := fmt.Sprintf("<%s>; rel=\"prev\"", prevLink.String())
+	resp.LinkHeader = fmt.Sprintf("%s, %s", next, prev)
+
+	return resp, nil
+}
+
+func (p *processor) ConversationRead(ctx context.Context, authed *oauth.Auth, conversationID string) (*apimodel.Conversation, gtserror.WithCode) {
+	conversation, errWithCode := p.getOwnConversation(ctx, authed, conversationID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	conversation.Read = true
+	conversation.UpdatedAt = time.Now()
+	if err := p.db.UpdateByID(ctx, conversation.ID, conversation); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ConversationRead: error updating conversation in db: %s", err))
+	}
+
+	apiConversation, err := p.tc.ConversationToMasto(ctx, conversation, authed.Account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ConversationRead: error converting conversation to api representation: %s", err))
+	}
+
+	return apiConversation, nil
+}
+
+func (p *processor) ConversationDelete(ctx context.Context, authed *oauth.Auth, conversationID string) gtserror.WithCode {
+	conversation, errWithCode := p.getOwnConversation(ctx, authed, conversationID)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	// this only removes the conversation for the requesting account; the statuses in it are left alone
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "conversation_id", Value: conversation.ID}}, &gtsmodel.ConversationStatus{}); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("ConversationDelete: error deleting conversation statuses from db: %s", err))
+	}
+	if err := p.db.DeleteByID(ctx, conversation.ID, &gtsmodel.Conversation{}); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("ConversationDelete: error deleting conversation from db: %s", err))
+	}
+
+	return nil
+}
+
+// getOwnConversation gets the conversation with the given ID, making sure that it belongs to the authed account.
+func (p *processor) getOwnConversation(ctx context.Context, authed *oauth.Auth, conversationID string) (*gtsmodel.Conversation, gtserror.WithCode) {
+	conversation := &gtsmodel.Conversation{}
+	if err := p.db.GetByID(ctx, conversationID, conversation); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("conversation %s not found", conversationID))
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting conversation %s: %s", conversationID, err))
+	}
+
+	if conversation.AccountID != authed.Account.ID {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("conversation %s does not belong to account %s", conversationID, authed.Account.ID))
+	}
+
+	return conversation, nil
+}
+
+// updateConversations adds the given status to the conversations of every local account taking part in it,
+// if it's a direct status, and streams the updated conversations to those accounts.
+//
+// A conversation is made up of the statuses of one thread that were sent between the same set of accounts.
+func (p *processor) updateConversations(ctx context.Context, status *gtsmodel.Status) error {
+	if status.Visibility != gtsmodel.VisibilityDirect {
+		return nil
+	}
+
+	if status.GTSAuthorAccount == nil {
+		a := &gtsmodel.Account{}
+		if err := p.db.GetByID(ctx, status.AccountID, a); err != nil {
+			return fmt.Errorf("updateConversations: error getting status author account: %s", err)
+		}
+		status.GTSAuthorAccount = a
+	}
+
+	// everyone who was mentioned takes part in the conversation, along with the author
+	participants := map[string]*gtsmodel.Account{
+		status.AccountID: status.GTSAuthorAccount,
+	}
+	for _, mID := range status.Mentions {
+		m := &gtsmodel.Mention{}
+		if err := p.db.GetByID(ctx, mID, m); err != nil {
+			return fmt.Errorf("updateConversations: error getting mention %s: %s", mID, err)
+		}
+		if _, ok := participants[m.TargetAccountID]; ok {
+			continue
+		}
+		a := &gtsmodel.Account{}
+		if err := p.db.GetByID(ctx, m.TargetAccountID, a); err != nil {
+			return fmt.Errorf("updateConversations: error getting mentioned account %s: %s", m.TargetAccountID, err)
+		}
+		participants[a.ID] = a
+	}
+
+	threadURI, err := p.threadURI(ctx, status)
+	if err != nil {
+		return fmt.Errorf("updateConversations: %s", err)
+	}
+
+	for ownerID, owner := range participants {
+		if owner.Domain != "" {
+			// remote accounts don't have conversations here
+			continue
+		}
+
+		others := []string{}
+		for aID := range participants {
+			if aID != ownerID {
+				others = append(others, aID)
+			}
+		}
+		sort.Strings(others)
+
+		conversation, err := p.getConversation(ctx, ownerID, threadURI, others)
+		if err != nil {
+			return fmt.Errorf("updateConversations: %s", err)
+		}
+
+		isNew := conversation == nil
+		if isNew {
+			conversationID, err := id.NewULID()
+			if err != nil {
+				return err
+			}
+			conversation = &gtsmodel.Conversation{
+				ID:                    conversationID,
+				CreatedAt:             time.Now(),
+				UpdatedAt:             time.Now(),
+				AccountID:             ownerID,
+				ThreadURI:             threadURI,
+				ParticipantAccountIDs: others,
+				LastStatusID:          status.ID,
+				// there's nothing new to read for whoever wrote the status
+				Read: ownerID == status.AccountID,
+			}
+			if err := p.db.Put(ctx, conversation); err != nil {
+				return fmt.Errorf("updateConversations: error putting conversation in db: %s", err)
+			}
+		}
+
+		if err := p.db.Put(ctx, &gtsmodel.ConversationStatus{
+			ConversationID: conversation.ID,
+			StatusID:       status.ID,
+		}); err != nil {
+			if _, ok := err.(db.ErrAlreadyExists); ok {
+				// the status has been added to this conversation already
+				continue
+			}
+			return fmt.Errorf("updateConversations: error putting conversation status in db: %s", err)
+		}
+
+		if !isNew {
+			if status.ID > conversation.LastStatusID {
+				conversation.LastStatusID = status.ID
+			}
+			conversation.Read = ownerID == status.AccountID
+			conversation.UpdatedAt = time.Now()
+			if err := p.db.UpdateByID(ctx, conversation.ID, conversation); err != nil {
+				return fmt.Errorf("updateConversations: error updating conversation in db: %s", err)
+			}
+		}
+
+		apiConversation, err := p.tc.ConversationToMasto(ctx, conversation, owner)
+		if err != nil {
+			return fmt.Errorf("updateConversations: error converting conversation to api representation: %s", err)
+		}
+
+		if err := p.streamingProcessor.StreamConversationToAccount(ctx, apiConversation, owner); err != nil {
+			return fmt.Errorf("updateConversations: error streaming conversation: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// getConversation returns the conversation that the given account has in the given thread with exactly the given
+// other participants, or nil if there isn't one yet.
+func (p *processor) getConversation(ctx context.Context, accountID string, threadURI string, participantAccountIDs []string) (*gtsmodel.Conversation, error) {
+	conversations := []*gtsmodel.Conversation{}
+	if err := p.db.GetWhere(ctx, []db.Where{
+		{Key: "account_id", Value: accountID},
+		{Key: "thread_uri", Value: threadURI},
+	}, &conversations); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting conversations of account %s: %s", accountID, err)
+	}
+
+conversationsLoop:
+	for _, c := range conversations {
+		if len(c.ParticipantAccountIDs) != len(participantAccountIDs) {
+			continue
+		}
+		for i := range c.ParticipantAccountIDs {
+			if c.ParticipantAccountIDs[i] != participantAccountIDs[i] {
+				continue conversationsLoop
+			}
+		}
+		return c, nil
+	}
+
+	return nil, nil
+}
+
+// threadURI returns the URI of the first status in the thread that the given status is part of.
+// If the start of the thread isn't in the database, the URI of the earliest status that is known about is used instead.
+func (p *processor) threadURI(ctx context.Context, status *gtsmodel.Status) (string, error) {
+	parents, err := p.db.StatusParents(ctx, status)
+	if err != nil {
+		return "", fmt.Errorf("error getting parents of status %s: %s", status.ID, err)
+	}
+
+	root := status
+	if len(parents) != 0 {
+		root = parents[len(parents)-1]
+	}
+
+	if root.InReplyToURI != "" {
+		return root.InReplyToURI, nil
+	}
+	return root.URI, nil
+}
+
+// deleteStatusFromConversations removes the given status from any conversations that it's part of,
+// removing conversations altogether when there's nothing left in them.
+func (p *processor) deleteStatusFromConversations(ctx context.Context, status *gtsmodel.Status) error {
+	if status.Visibility != gtsmodel.VisibilityDirect {
+		// only direct statuses are ever part of conversations
+		return nil
+	}
+
+	conversations, err := p.db.GetConversationsForStatus(ctx, status.ID)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil
+		}
+		return fmt.Errorf("deleteStatusFromConversations: error getting conversations for status %s: %s", status.ID, err)
+	}
+
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "status_id", Value: status.ID}}, &gtsmodel.ConversationStatus{}); err != nil {
+		return fmt.Errorf("deleteStatusFromConversations: error deleting conversation statuses for status %s: %s", status.ID, err)
+	}
+
+	for _, c := range conversations {
+		remaining := []*gtsmodel.ConversationStatus{}
+		if err := p.db.GetWhere(ctx, []db.Where{{Key: "conversation_id", Value: c.ID}}, &remaining); err != nil {
+			if _, ok := err.(db.ErrNoEntries); !ok {
+				return fmt.Errorf("deleteStatusFromConversations: error getting statuses of conversation %s: %s", c.ID, err)
+			}
+		}
+
+		if len(remaining) == 0 {
+			if err := p.db.DeleteByID(ctx, c.ID, &gtsmodel.Conversation{}); err != nil {
+				return fmt.Errorf("deleteStatusFromConversations: error deleting conversation %s: %s", c.ID, err)
+			}
+			continue
+		}
+
+		lastStatusID := ""
+		for _, cs := range remaining {
+			if cs.StatusID > lastStatusID {
+				lastStatusID = cs.StatusID
+			}
+		}
+
+		c.LastStatusID = lastStatusID
+		c.UpdatedAt = time.Now()
+		if err := p.db.UpdateByID(ctx, c.ID, c); err != nil {
+			return fmt.Errorf("deleteStatusFromConversations: error updating conversation %s: %s", c.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type ConversationTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *ConversationTestSuite) authed(accountName string) *oauth.Auth {
+	return &oauth.Auth{
+		Account:     suite.testAccounts[accountName],
+		Application: suite.testApplications["application_1"],
+	}
+}
+
+// directMessage posts a direct status as the given account, and waits for it to show up in the conversations of the author.
+func (suite *ConversationTestSuite) directMessage(accountName string, text string, inReplyToID string) *apimodel.Status {
+	ctx := context.Background()
+	federated := false
+	status, err := suite.processor.StatusCreate(ctx, suite.authed(accountName), &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status:      text,
+			InReplyToID: inReplyToID,
+			Visibility:  apimodel.VisibilityDirect,
+		},
+		AdvancedVisibilityFlagsForm: apimodel.AdvancedVisibilityFlagsForm{
+			Federated: &federated,
+		},
+	})
+	suite.NoError(err)
+
+	suite.Eventually(func() bool {
+		resp, errWithCode := suite.processor.ConversationsGet(ctx, suite.authed(accountName), "", "", 20)
+		if errWithCode != nil {
+			return false
+		}
+		for _, c := range resp.Conversations {
+			if c.LastStatus != nil && c.LastStatus.ID == status.ID {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return status
+}
+
+func (suite *ConversationTestSuite) conversations(accountName string) []*apimodel.Conversation {
+	resp, errWithCode := suite.processor.ConversationsGet(context.Background(), suite.authed(accountName), "", "", 20)
+	suite.Nil(errWithCode)
+	return resp.Conversations
+}
+
+func (suite *ConversationTestSuite) TestDirectMessageConversation() {
+	ctx := context.Background()
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+
+	// the recipient has a direct stream open, so the conversation should be streamed to them
+	stream, errWithCode := suite.processor.OpenStreamForAccount(ctx, suite.testAccounts["local_account_2"], gtsmodel.StreamTypeDirect, "", "")
+	suite.Nil(errWithCode)
+
+	first := suite.directMessage("local_account_1", "hey @1happyturtle, how's it going?", "")
+
+	select {
+	case msg := <-stream.Messages:
+		suite.Equal("conversation", msg.Event)
+		suite.Contains(msg.Payload, first.ID)
+	case <-time.After(5 * time.Second):
+		suite.FailNow("timed out waiting for streamed conversation")
+	}
+
+	// the author has read their own message, the recipient hasn't
+	authorConversations := suite.conversations("local_account_1")
+	suite.Len(authorConversations, 1)
+	suite.False(authorConversations[0].Unread)
+	suite.Len(authorConversations[0].Accounts, 1)
+	suite.Equal(suite.testAccounts["local_account_2"].ID, authorConversations[0].Accounts[0].ID)
+
+	recipientConversations := suite.conversations("local_account_2")
+	suite.Len(recipientConversations, 1)
+	suite.True(recipientConversations[0].Unread)
+	suite.Equal(suite.testAccounts["local_account_1"].ID, recipientConversations[0].Accounts[0].ID)
+
+	// a reply in the same thread joins the same conversation, and is unread for the original author
+	reply := suite.directMessage("local_account_2", "@the_mighty_zork pretty good thanks", first.ID)
+	authorConversations = suite.conversations("local_account_1")
+	suite.Len(authorConversations, 1)
+	suite.True(authorConversations[0].Unread)
+	suite.Equal(reply.ID, authorConversations[0].LastStatus.ID)
+
+	recipientConversations = suite.conversations("local_account_2")
+	suite.Len(recipientConversations, 1)
+	suite.False(recipientConversations[0].Unread)
+
+	read, errWithCode := suite.processor.ConversationRead(ctx, suite.authed("local_account_1"), authorConversations[0].ID)
+	suite.Nil(errWithCode)
+	suite.False(read.Unread)
+
+	// deleting the reply takes it out of the conversations again
+	_, err := suite.processor.StatusDelete(ctx, suite.authed("local_account_2"), reply.ID)
+	suite.NoError(err)
+	suite.Eventually(func() bool {
+		conversations := suite.conversations("local_account_1")
+		return len(conversations) == 1 && conversations[0].LastStatus.ID == first.ID
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// deleting the first message as well leaves nothing of the conversation
+	_, err = suite.processor.StatusDelete(ctx, suite.authed("local_account_1"), first.ID)
+	suite.NoError(err)
+	suite.Eventually(func() bool {
+		return len(suite.conversations("local_account_2")) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (suite *ConversationTestSuite) TestConversationDelete() {
+	ctx := context.Background()
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+
+	suite.directMessage("local_account_1", "psst @1happyturtle", "")
+	conversations := suite.conversations("local_account_1")
+	suite.Len(conversations, 1)
+
+	// other accounts can't touch the conversation
+	_, errWithCode := suite.processor.ConversationRead(ctx, suite.authed("local_account_2"), conversations[0].ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+	errWithCode = suite.processor.ConversationDelete(ctx, suite.authed("local_account_2"), conversations[0].ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+
+	// deleting the conversation only removes it for the requesting account
+	suite.Nil(suite.processor.ConversationDelete(ctx, suite.authed("local_account_1"), conversations[0].ID))
+	suite.Empty(suite.conversations("local_account_1"))
+	suite.Len(suite.conversations("local_account_2"), 1)
+}
+
+func TestConversationTestSuite(t *testing.T) {
+	suite.Run(t, new(ConversationTestSuite))
+}
@@ -39,12 +39,12 @@ import (
 // username to perform the validation. It will *also* dereference the originator of the request and return it as a gtsmodel account
 // for further processing. NOTE that this function will have the side effect of putting the dereferenced account into the database,
 // and passing it into the processor through a channel for further asynchronous processing.
-func (p *processor) dereferenceFediRequest(username string, requestingAccountURI *url.URL) (*gtsmodel.Account, error) {
+func (p *processor) dereferenceFediRequest(ctx context.Context, username string, requestingAccountURI *url.URL) (*gtsmodel.Account, error) {
 	// OK now we can do the dereferencing part
 	// we might already have an entry for this account so check that first
 	requestingAccount := &gtsmodel.Account{}
 
-	err := p.db.GetWhere([]db.Where{{Key: "uri", Value: requestingAccountURI.String()}}, requestingAccount)
+	err := p.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: requestingAccountURI.String()}}, requestingAccount)
 	if err == nil {
 		// we do have it yay, return it
 		return requestingAccount, nil
@@ -59,13 +59,13 @@ func (p *processor) dereferenceFediRequest(username string, requestingAccountURI
 	// what we do now should depend on our chosen federation method
 	// for now though, we'll just dereference it
 	// TODO: slow-fed
-	requestingPerson, err := p.federator.DereferenceRemoteAccount(username, requestingAccountURI)
+	requestingPerson, err := p.federator.DereferenceRemoteAccount(ctx, username, requestingAccountURI)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't dereference %s: %s", requestingAccountURI.String(), err)
 	}
 
 	// convert it to our internal account representation
-	requestingAccount, err = p.tc.ASRepresentationToAccount(requestingPerson, false)
+	requestingAccount, err = p.tc.ASRepresentationToAccount(ctx, requestingPerson, false)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't convert dereferenced uri %s to gtsmodel account: %s", requestingAccountURI.String(), err)
 	}
@@ -76,7 +76,7 @@ func (p *processor) dereferenceFediRequest(username string, requestingAccountURI
 	}
 	requestingAccount.ID = requestingAccountID
 
-	if err := p.db.Put(requestingAccount); err != nil {
+	if err := p.db.Put(ctx, requestingAccount); err != nil {
 		return nil, fmt.Errorf("database error inserting account with uri %s: %s", requestingAccountURI.String(), err)
 	}
 
@@ -93,7 +93,7 @@ func (p *processor) dereferenceFediRequest(username string, requestingAccountURI
 func (p *processor) GetFediUser(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
 	// get the account the request is referring to
 	requestedAccount := &gtsmodel.Account{}
-	if err := p.db.GetLocalAccountByUsername(requestedUsername, requestedAccount); err != nil {
+	if err := p.db.GetLocalAccountByUsername(ctx, requestedUsername, requestedAccount); err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("database error getting account with username %s: %s", requestedUsername, err))
 	}
 
@@ -114,12 +114,12 @@ func (p *processor) GetFediUser(ctx context.Context, requestedUsername string, r
 
 		// if we're already handshaking/dereferencing a remote account, we can skip the dereferencing part
 		if !p.federator.Handshaking(requestedUsername, requestingAccountURI) {
-			requestingAccount, err := p.dereferenceFediRequest(requestedUsername, requestingAccountURI)
+			requestingAccount, err := p.dereferenceFediRequest(ctx, requestedUsername, requestingAccountURI)
 			if err != nil {
 				return nil, gtserror.NewErrorNotAuthorized(err)
 			}
 
-			blocked, err := p.db.Blocked(requestedAccount.ID, requestingAccount.ID)
+			blocked, err := p.db.Blocked(ctx, requestedAccount.ID, requestingAccount.ID)
 			if err != nil {
 				return nil, gtserror.NewErrorInternalError(err)
 			}
@@ -129,7 +129,7 @@ func (p *processor) GetFediUser(ctx context.Context, requestedUsername string, r
 			}
 		}
 
-		requestedPerson, err = p.tc.AccountToAS(requestedAccount)
+		requestedPerson, err = p.tc.AccountToAS(ctx, requestedAccount)
 		if err != nil {
 			return nil, gtserror.NewErrorInternalError(err)
 		}
@@ -148,7 +148,7 @@ func (p *processor) GetFediUser(ctx context.Context, requestedUsername string, r
 func (p *processor) GetFediFollowers(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
 	// get the account the request is referring to
 	requestedAccount := &gtsmodel.Account{}
-	if err := p.db.GetLocalAccountByUsername(requestedUsername, requestedAccount); err != nil {
+	if err := p.db.GetLocalAccountByUsername(ctx, requestedUsername, requestedAccount); err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("database error getting account with username %s: %s", requestedUsername, err))
 	}
 
@@ -158,12 +158,12 @@ func (p *processor) GetFediFollowers(ctx context.Context, requestedUsername stri
 		return nil, gtserror.NewErrorNotAuthorized(errors.New("not authorized"), "not authorized")
 	}
 
-	requestingAccount, err := p.dereferenceFediRequest(requestedUsername, requestingAccountURI)
+	requestingAccount, err := p.dereferenceFediRequest(ctx, requestedUsername, requestingAccountURI)
 	if err != nil {
 		return nil, gtserror.NewErrorNotAuthorized(err)
 	}
 
-	blocked, err := p.db.Blocked(requestedAccount.ID, requestingAccount.ID)
+	blocked, err := p.db.Blocked(ctx, requestedAccount.ID, requestingAccount.ID)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
@@ -177,7 +177,7 @@ func (p *processor) GetFediFollowers(ctx context.Context, requestedUsername stri
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error parsing url %s: %s", requestedAccount.URI, err))
 	}
 
-	requestedFollowers, err := p.federator.FederatingDB().Followers(context.Background(), requestedAccountURI)
+	requestedFollowers, err := p.federator.FederatingDB().Followers(ctx, requestedAccountURI)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching followers for uri %s: %s", requestedAccountURI.String(), err))
 	}
@@ -193,7 +193,7 @@ func (p *processor) GetFediFollowers(ctx context.Context, requestedUsername stri
 func (p *processor) GetFediFollowing(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
 	// get the account the request is referring to
 	requestedAccount := &gtsmodel.Account{}
-	if err := p.db.GetLocalAccountByUsername(requestedUsername, requestedAccount); err != nil {
+	if err := p.db.GetLocalAccountByUsername(ctx, requestedUsername, requestedAccount); err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("database error getting account with username %s: %s", requestedUsername, err))
 	}
 
@@ -203,12 +203,12 @@ func (p *processor) GetFediFollowing(ctx context.Context, requestedUsername stri
 		return nil, gtserror.NewErrorNotAuthorized(errors.New("not authorized"), "not authorized")
 	}
 
-	requestingAccount, err := p.dereferenceFediRequest(requestedUsername, requestingAccountURI)
+	requestingAccount, err := p.dereferenceFediRequest(ctx, requestedUsername, requestingAccountURI)
 	if err != nil {
 		return nil, gtserror.NewErrorNotAuthorized(err)
 	}
 
-	blocked, err := p.db.Blocked(requestedAccount.ID, requestingAccount.ID)
+	blocked, err := p.db.Blocked(ctx, requestedAccount.ID, requestingAccount.ID)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
@@ -222,7 +222,7 @@ func (p *processor) GetFediFollowing(ctx context.Context, requestedUsername stri
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error parsing url %s: %s", requestedAccount.URI, err))
 	}
 
-	requestedFollowing, err := p.federator.FederatingDB().Following(context.Background(), requestedAccountURI)
+	requestedFollowing, err := p.federator.FederatingDB().Following(ctx, requestedAccountURI)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching following for uri %s: %s", requestedAccountURI.String(), err))
 	}
@@ -235,10 +235,68 @@ func (p *processor) GetFediFollowing(ctx context.Context, requestedUsername stri
 	return data, nil
 }
 
+func (p *processor) GetFediFeatured(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
+	// get the account the request is referring to
+	requestedAccount := &gtsmodel.Account{}
+	if err := p.db.GetLocalAccountByUsername(ctx, requestedUsername, requestedAccount); err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("database error getting account with username %s: %s", requestedUsername, err))
+	}
+
+	// authenticate the request
+	requestingAccountURI, authenticated, err := p.federator.AuthenticateFederatedRequest(ctx, requestedUsername)
+	if err != nil || !authenticated {
+		return nil, gtserror.NewErrorNotAuthorized(errors.New("not authorized"), "not authorized")
+	}
+
+	requestingAccount, err := p.dereferenceFediRequest(ctx, requestedUsername, requestingAccountURI)
+	if err != nil {
+		return nil, gtserror.NewErrorNotAuthorized(err)
+	}
+
+	blocked, err := p.db.Blocked(ctx, requestedAccount.ID, requestingAccount.ID)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if blocked {
+		return nil, gtserror.NewErrorNotAuthorized(fmt.Errorf("block exists between accounts %s and %s", requestedAccount.ID, requestingAccount.ID))
+	}
+
+	pinned, err := p.db.GetStatusesForAccount(ctx, requestedAccount.ID, 0, false, "", true, false)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching pinned statuses for account %s: %s", requestedAccount.ID, err))
+		}
+	}
+
+	visiblePinned := []*gtsmodel.Status{}
+	for _, s := range pinned {
+		visible, err := p.filter.StatusVisible(ctx, s, requestingAccount)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		if visible {
+			visiblePinned = append(visiblePinned, s)
+		}
+	}
+
+	featured, err := p.tc.StatusesToASFeaturedCollection(ctx, requestedAccount, visiblePinned)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	data, err := streams.Serialize(featured)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return data, nil
+}
+
 func (p *processor) GetFediStatus(ctx context.Context, requestedUsername string, requestedStatusID string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
 	// get the account the request is referring to
 	requestedAccount := &gtsmodel.Account{}
-	if err := p.db.GetLocalAccountByUsername(requestedUsername, requestedAccount); err != nil {
+	if err := p.db.GetLocalAccountByUsername(ctx, requestedUsername, requestedAccount); err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("database error getting account with username %s: %s", requestedUsername, err))
 	}
 
@@ -248,14 +306,14 @@ func (p *processor) GetFediStatus(ctx context.Context, requestedUsername string,
 		return nil, gtserror.NewErrorNotAuthorized(errors.New("not authorized"), "not authorized")
 	}
 
-	requestingAccount, err := p.dereferenceFediRequest(requestedUsername, requestingAccountURI)
+	requestingAccount, err := p.dereferenceFediRequest(ctx, requestedUsername, requestingAccountURI)
 	if err != nil {
 		return nil, gtserror.NewErrorNotAuthorized(err)
 	}
 
 	// authorize the request:
 	// 1. check if a block exists between the requester and the requestee
-	blocked, err := p.db.Blocked(requestedAccount.ID, requestingAccount.ID)
+	blocked, err := p.db.Blocked(ctx, requestedAccount.ID, requestingAccount.ID)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
@@ -266,14 +324,14 @@ func (p *processor) GetFediStatus(ctx context.Context, requestedUsername string,
 
 	// get the status out of the database here
 	s := &gtsmodel.Status{}
-	if err := p.db.GetWhere([]db.Where{
+	if err := p.db.GetWhere(ctx, []db.Where{
 		{Key: "id", Value: requestedStatusID},
 		{Key: "account_id", Value: requestedAccount.ID},
 	}, s); err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("database error getting status with id %s and account id %s: %s", requestedStatusID, requestedAccount.ID, err))
 	}
 
-	visible, err := p.filter.StatusVisible(s, requestingAccount)
+	visible, err := p.filter.StatusVisible(ctx, s, requestingAccount)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
@@ -282,7 +340,7 @@ func (p *processor) GetFediStatus(ctx context.Context, requestedUsername string,
 	}
 
 	// requester is authorized to view the status, so convert it to AP representation and serialize it
-	asStatus, err := p.tc.StatusToAS(s)
+	asStatus, err := p.statusToAS(ctx, s)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
@@ -298,7 +356,7 @@ func (p *processor) GetFediStatus(ctx context.Context, requestedUsername string,
 func (p *processor) GetWebfingerAccount(ctx context.Context, requestedUsername string, requestURL *url.URL) (*apimodel.WellKnownResponse, gtserror.WithCode) {
 	// get the account the request is referring to
 	requestedAccount := &gtsmodel.Account{}
-	if err := p.db.GetLocalAccountByUsername(requestedUsername, requestedAccount); err != nil {
+	if err := p.db.GetLocalAccountByUsername(ctx, requestedUsername, requestedAccount); err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("database error getting account with username %s: %s", requestedUsername, err))
 	}
 
@@ -324,7 +382,7 @@ func (p *processor) GetWebfingerAccount(ctx context.Context, requestedUsername s
 	}, nil
 }
 
-func (p *processor) GetNodeInfoRel(request *http.Request) (*apimodel.WellKnownResponse, gtserror.WithCode) {
+func (p *processor) GetNodeInfoRel(ctx context.Context, request *http.Request) (*apimodel.WellKnownResponse, gtserror.WithCode) {
 	return &apimodel.WellKnownResponse{
 		Links: []apimodel.Link{
 			{
@@ -335,7 +393,7 @@ func (p *processor) GetNodeInfoRel(request *http.Request) (*apimodel.WellKnownRe
 	}, nil
 }
 
-func (p *processor) GetNodeInfo(request *http.Request) (*apimodel.Nodeinfo, gtserror.WithCode) {
+func (p *processor) GetNodeInfo(ctx context.Context, request *http.Request) (*apimodel.Nodeinfo, gtserror.WithCode) {
 	return &apimodel.Nodeinfo{
 		Version: "2.0",
 		Software: apimodel.NodeInfoSoftware{
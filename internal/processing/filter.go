@@ -0,0 +1,200 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+func (p *processor) FiltersGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Filter, gtserror.WithCode) {
+	filters := []*gtsmodel.Filter{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: authed.Account.ID}}, &filters); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("FiltersGet: error getting filters: %s", err))
+		}
+	}
+
+	apiFilters := []*apimodel.Filter{}
+	for _, filter := range filters {
+		apiFilter, errWithCode := p.filterToMasto(filter)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		apiFilters = append(apiFilters, apiFilter)
+	}
+
+	return apiFilters, nil
+}
+
+func (p *processor) FilterGet(ctx context.Context, authed *oauth.Auth, filterID string) (*apimodel.Filter, gtserror.WithCode) {
+	filter, errWithCode := p.getOwnFilter(ctx, authed, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.filterToMasto(filter)
+}
+
+func (p *processor) FilterCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.FilterCreateRequest) (*apimodel.Filter, gtserror.WithCode) {
+	filterID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	filter := &gtsmodel.Filter{
+		ID:        filterID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		AccountID: authed.Account.ID,
+	}
+
+	if err := applyFilterForm(filter, form); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if err := p.db.Put(ctx, filter); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("FilterCreate: error putting filter in db: %s", err))
+	}
+
+	p.resetTimelinesForFilters(authed.Account.ID)
+
+	return p.filterToMasto(filter)
+}
+
+func (p *processor) FilterUpdate(ctx context.Context, authed *oauth.Auth, filterID string, form *apimodel.FilterCreateRequest) (*apimodel.Filter, gtserror.WithCode) {
+	filter, errWithCode := p.getOwnFilter(ctx, authed, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	filter.UpdatedAt = time.Now()
+	if err := applyFilterForm(filter, form); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if err := p.db.UpdateByID(ctx, filter.ID, filter); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("FilterUpdate: error updating filter in db: %s", err))
+	}
+
+	p.resetTimelinesForFilters(authed.Account.ID)
+
+	return p.filterToMasto(filter)
+}
+
+func (p *processor) FilterDelete(ctx context.Context, authed *oauth.Auth, filterID string) (*apimodel.Filter, gtserror.WithCode) {
+	filter, errWithCode := p.getOwnFilter(ctx, authed, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := p.db.DeleteByID(ctx, filter.ID, &gtsmodel.Filter{}); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("FilterDelete: error deleting filter from db: %s", err))
+	}
+
+	p.resetTimelinesForFilters(authed.Account.ID)
+
+	return p.filterToMasto(filter)
+}
+
+// getOwnFilter gets the filter with the given ID, making sure that it belongs to the authed account.
+func (p *processor) getOwnFilter(ctx context.Context, authed *oauth.Auth, filterID string) (*gtsmodel.Filter, gtserror.WithCode) {
+	filter := &gtsmodel.Filter{}
+	if err := p.db.GetByID(ctx, filterID, filter); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("filter %s not found", filterID))
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting filter %s: %s", filterID, err))
+	}
+
+	if filter.AccountID != authed.Account.ID {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("filter %s does not belong to account %s", filterID, authed.Account.ID))
+	}
+
+	return filter, nil
+}
+
+// resetTimelinesForFilters makes sure that the changed filters of the given account
+// are applied to statuses that have already been prepared for its timelines.
+func (p *processor) resetTimelinesForFilters(accountID string) {
+	if err := p.timelineManager.ResetTimelines(accountID); err != nil {
+		p.log.Errorf("error resetting timelines of account %s after its filters changed: %s", accountID, err)
+	}
+}
+
+func (p *processor) filterToMasto(filter *gtsmodel.Filter) (*apimodel.Filter, gtserror.WithCode) {
+	apiFilter, err := p.tc.FilterToMasto(filter)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting filter to api representation: %s", err))
+	}
+	return apiFilter, nil
+}
+
+// applyFilterForm validates the given form and sets its values on the given filter.
+func applyFilterForm(filter *gtsmodel.Filter, form *apimodel.FilterCreateRequest) error {
+	if form.Phrase == "" {
+		return errors.New("filter phrase must not be empty")
+	}
+
+	if len(form.Context) == 0 {
+		return errors.New("filter must have at least one context")
+	}
+
+	if form.ExpiresIn < 0 {
+		return errors.New("filter expires_in must not be negative")
+	}
+
+	filter.ContextHome = false
+	filter.ContextNotifications = false
+	filter.ContextPublic = false
+	filter.ContextThread = false
+	for _, c := range form.Context {
+		switch gtsmodel.FilterContext(c) {
+		case gtsmodel.FilterContextHome:
+			filter.ContextHome = true
+		case gtsmodel.FilterContextNotifications:
+			filter.ContextNotifications = true
+		case gtsmodel.FilterContextPublic:
+			filter.ContextPublic = true
+		case gtsmodel.FilterContextThread:
+			filter.ContextThread = true
+		default:
+			return fmt.Errorf("filter context %s not recognised, must be one of %s, %s, %s or %s", c, gtsmodel.FilterContextHome, gtsmodel.FilterContextNotifications, gtsmodel.FilterContextPublic, gtsmodel.FilterContextThread)
+		}
+	}
+
+	filter.Phrase = form.Phrase
+	filter.WholeWord = form.WholeWord
+	filter.Irreversible = form.Irreversible
+	filter.ExpiresAt = time.Time{}
+	if form.ExpiresIn > 0 {
+		filter.ExpiresAt = time.Now().Add(time.Duration(form.ExpiresIn) * time.Second)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type FilterTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func statusIDs(statuses []*apimodel.Status) []string {
+	ids := []string{}
+	for _, s := range statuses {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func (suite *FilterTestSuite) TestPublicTimelineFiltered() {
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+
+	resp, errWithCode := suite.processor.PublicTimelineGet(context.Background(), authed, "", "", "", 20, false)
+	suite.Nil(errWithCode)
+
+	// local_account_1 filters out turtles, but other statuses by the same account should still be there
+	ids := statusIDs(resp.Statuses)
+	suite.NotContains(ids, suite.testStatuses["local_account_2_status_1"].ID)
+	suite.Contains(ids, suite.testStatuses["local_account_2_status_2"].ID)
+}
+
+func (suite *FilterTestSuite) TestHomeTimelineFilteredUntilFilterDeleted() {
+	ctx := context.Background()
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+	filteredStatusID := suite.testStatuses["local_account_2_status_1"].ID
+
+	// starting the processor indexes the home timelines
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+
+	resp, errWithCode := suite.processor.HomeTimelineGet(ctx, authed, "", "", "", 20, false)
+	suite.Nil(errWithCode)
+	suite.NotEmpty(resp.Statuses)
+	suite.NotContains(statusIDs(resp.Statuses), filteredStatusID)
+
+	_, errWithCode = suite.processor.FilterDelete(ctx, authed, suite.testFilters["local_account_1_filter_1"].ID)
+	suite.Nil(errWithCode)
+
+	resp, errWithCode = suite.processor.HomeTimelineGet(ctx, authed, "", "", "", 20, false)
+	suite.Nil(errWithCode)
+	suite.Contains(statusIDs(resp.Statuses), filteredStatusID)
+}
+
+func (suite *FilterTestSuite) TestWholeWord() {
+	ctx := context.Background()
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+
+	// "turtle" is only part of the word "turtles" so it shouldn't match when whole_word is set...
+	_, errWithCode := suite.processor.FilterUpdate(ctx, authed, suite.testFilters["local_account_1_filter_1"].ID, &apimodel.FilterCreateRequest{
+		Phrase:    "TURTLE",
+		Context:   []string{"public"},
+		WholeWord: true,
+	})
+	suite.Nil(errWithCode)
+
+	resp, errWithCode := suite.processor.PublicTimelineGet(ctx, authed, "", "", "", 20, false)
+	suite.Nil(errWithCode)
+	suite.Contains(statusIDs(resp.Statuses), suite.testStatuses["local_account_2_status_1"].ID)
+
+	// ...but it should match when it isn't
+	_, errWithCode = suite.processor.FilterUpdate(ctx, authed, suite.testFilters["local_account_1_filter_1"].ID, &apimodel.FilterCreateRequest{
+		Phrase:  "TURTLE",
+		Context: []string{"public"},
+	})
+	suite.Nil(errWithCode)
+
+	resp, errWithCode = suite.processor.PublicTimelineGet(ctx, authed, "", "", "", 20, false)
+	suite.Nil(errWithCode)
+	suite.NotContains(statusIDs(resp.Statuses), suite.testStatuses["local_account_2_status_1"].ID)
+}
+
+func (suite *FilterTestSuite) TestCreateFilterBadContext() {
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+
+	_, errWithCode := suite.processor.FilterCreate(context.Background(), authed, &apimodel.FilterCreateRequest{
+		Phrase:  "dogs",
+		Context: []string{"everywhere"},
+	})
+	suite.NotNil(errWithCode)
+	suite.Equal(400, errWithCode.Code())
+}
+
+func TestFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(FilterTestSuite))
+}
@@ -20,37 +20,64 @@ package processing
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 
 	"github.com/go-fed/activity/streams"
+	"github.com/go-fed/activity/streams/vocab"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error {
+func (p *processor) processFromClientAPI(ctx context.Context, clientMsg gtsmodel.FromClientAPI) error {
 	switch clientMsg.APActivityType {
 	case gtsmodel.ActivityStreamsCreate:
 		// CREATE
 		switch clientMsg.APObjectType {
 		case gtsmodel.ActivityStreamsNote:
 			// CREATE NOTE
+			if vote, ok := clientMsg.GTSModel.(*gtsmodel.PollVote); ok {
+				// CREATE POLL VOTE
+				return p.step(ctx, "federate", func() error {
+					return p.federatePollVote(ctx, vote, clientMsg.OriginAccount, clientMsg.TargetAccount)
+				})
+			}
+
 			status, ok := clientMsg.GTSModel.(*gtsmodel.Status)
 			if !ok {
 				return errors.New("note was not parseable as *gtsmodel.Status")
 			}
 
-			if err := p.timelineStatus(status); err != nil {
+			if err := p.step(ctx, "inherit thread mutes", func() error {
+				return p.inheritThreadMutes(ctx, status)
+			}); err != nil {
+				return err
+			}
+
+			if err := p.step(ctx, "timeline", func() error {
+				return p.timelineStatus(ctx, status)
+			}); err != nil {
 				return err
 			}
 
-			if err := p.notifyStatus(status); err != nil {
+			if err := p.step(ctx, "notify", func() error {
+				return p.notifyStatus(ctx, status)
+			}); err != nil {
+				return err
+			}
+
+			if err := p.step(ctx, "conversations", func() error {
+				return p.updateConversations(ctx, status)
+			}); err != nil {
 				return err
 			}
 
 			if status.VisibilityAdvanced != nil && status.VisibilityAdvanced.Federated {
-				return p.federateStatus(status)
+				return p.step(ctx, "federate", func() error {
+					return p.federateStatus(ctx, status)
+				})
 			}
 		case gtsmodel.ActivityStreamsFollow:
 			// CREATE FOLLOW REQUEST
@@ -59,11 +86,15 @@ func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error
 				return errors.New("followrequest was not parseable as *gtsmodel.FollowRequest")
 			}
 
-			if err := p.notifyFollowRequest(followRequest, clientMsg.TargetAccount); err != nil {
+			if err := p.step(ctx, "notify", func() error {
+				return p.notifyFollowRequest(ctx, followRequest, clientMsg.TargetAccount)
+			}); err != nil {
 				return err
 			}
 
-			return p.federateFollow(followRequest, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			return p.step(ctx, "federate", func() error {
+				return p.federateFollow(ctx, followRequest, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			})
 		case gtsmodel.ActivityStreamsLike:
 			// CREATE LIKE/FAVE
 			fave, ok := clientMsg.GTSModel.(*gtsmodel.StatusFave)
@@ -71,11 +102,15 @@ func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error
 				return errors.New("fave was not parseable as *gtsmodel.StatusFave")
 			}
 
-			if err := p.notifyFave(fave, clientMsg.TargetAccount); err != nil {
+			if err := p.step(ctx, "notify", func() error {
+				return p.notifyFave(ctx, fave, clientMsg.TargetAccount)
+			}); err != nil {
 				return err
 			}
 
-			return p.federateFave(fave, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			return p.step(ctx, "federate", func() error {
+				return p.federateFave(ctx, fave, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			})
 		case gtsmodel.ActivityStreamsAnnounce:
 			// CREATE BOOST/ANNOUNCE
 			boostWrapperStatus, ok := clientMsg.GTSModel.(*gtsmodel.Status)
@@ -83,15 +118,21 @@ func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error
 				return errors.New("boost was not parseable as *gtsmodel.Status")
 			}
 
-			if err := p.timelineStatus(boostWrapperStatus); err != nil {
+			if err := p.step(ctx, "timeline", func() error {
+				return p.timelineStatus(ctx, boostWrapperStatus)
+			}); err != nil {
 				return err
 			}
 
-			if err := p.notifyAnnounce(boostWrapperStatus); err != nil {
+			if err := p.step(ctx, "notify", func() error {
+				return p.notifyAnnounce(ctx, boostWrapperStatus)
+			}); err != nil {
 				return err
 			}
 
-			return p.federateAnnounce(boostWrapperStatus, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			return p.step(ctx, "federate", func() error {
+				return p.federateAnnounce(ctx, boostWrapperStatus, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			})
 		case gtsmodel.ActivityStreamsBlock:
 			// CREATE BLOCK
 			block, ok := clientMsg.GTSModel.(*gtsmodel.Block)
@@ -100,17 +141,29 @@ func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error
 			}
 
 			// remove any of the blocking account's statuses from the blocked account's timeline, and vice versa
-			if err := p.timelineManager.WipeStatusesFromAccountID(block.AccountID, block.TargetAccountID); err != nil {
+			if err := p.timelineManager.WipeStatusesFromAccountID(ctx, block.AccountID, block.TargetAccountID); err != nil {
 				return err
 			}
-			if err := p.timelineManager.WipeStatusesFromAccountID(block.TargetAccountID, block.AccountID); err != nil {
+			if err := p.timelineManager.WipeStatusesFromAccountID(ctx, block.TargetAccountID, block.AccountID); err != nil {
 				return err
 			}
 
 			// TODO: same with notifications
 			// TODO: same with bookmarks
 
-			return p.federateBlock(block)
+			return p.step(ctx, "federate", func() error {
+				return p.federateBlock(ctx, block)
+			})
+		case gtsmodel.ActivityStreamsFlag:
+			// CREATE FLAG/REPORT
+			report, ok := clientMsg.GTSModel.(*gtsmodel.Report)
+			if !ok {
+				return errors.New("flag was not parseable as *gtsmodel.Report")
+			}
+
+			return p.step(ctx, "federate", func() error {
+				return p.federateReport(ctx, report, clientMsg.TargetAccount)
+			})
 		}
 	case gtsmodel.ActivityStreamsUpdate:
 		// UPDATE
@@ -122,7 +175,47 @@ func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error
 				return errors.New("account was not parseable as *gtsmodel.Account")
 			}
 
-			return p.federateAccountUpdate(account, clientMsg.OriginAccount)
+			return p.step(ctx, "federate", func() error {
+				return p.federateAccountUpdate(ctx, account, clientMsg.OriginAccount)
+			})
+		case gtsmodel.ActivityStreamsQuestion:
+			// UPDATE POLL
+			status, ok := clientMsg.GTSModel.(*gtsmodel.Status)
+			if !ok {
+				return errors.New("question was not parseable as *gtsmodel.Status")
+			}
+
+			return p.step(ctx, "federate", func() error {
+				return p.federatePollUpdate(ctx, status)
+			})
+		}
+	case gtsmodel.ActivityStreamsAdd:
+		// ADD
+		switch clientMsg.APObjectType {
+		case gtsmodel.ActivityStreamsNote:
+			// ADD NOTE TO FEATURED (PIN)
+			status, ok := clientMsg.GTSModel.(*gtsmodel.Status)
+			if !ok {
+				return errors.New("note was not parseable as *gtsmodel.Status")
+			}
+
+			return p.step(ctx, "federate", func() error {
+				return p.federateStatusPin(ctx, status, clientMsg.OriginAccount)
+			})
+		}
+	case gtsmodel.ActivityStreamsRemove:
+		// REMOVE
+		switch clientMsg.APObjectType {
+		case gtsmodel.ActivityStreamsNote:
+			// REMOVE NOTE FROM FEATURED (UNPIN)
+			status, ok := clientMsg.GTSModel.(*gtsmodel.Status)
+			if !ok {
+				return errors.New("note was not parseable as *gtsmodel.Status")
+			}
+
+			return p.step(ctx, "federate", func() error {
+				return p.federateStatusUnpin(ctx, status, clientMsg.OriginAccount)
+			})
 		}
 	case gtsmodel.ActivityStreamsAccept:
 		// ACCEPT
@@ -134,11 +227,15 @@ func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error
 				return errors.New("accept was not parseable as *gtsmodel.Follow")
 			}
 
-			if err := p.notifyFollow(follow, clientMsg.TargetAccount); err != nil {
+			if err := p.step(ctx, "notify", func() error {
+				return p.notifyFollow(ctx, follow, clientMsg.TargetAccount)
+			}); err != nil {
 				return err
 			}
 
-			return p.federateAcceptFollowRequest(follow, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			return p.step(ctx, "federate", func() error {
+				return p.federateAcceptFollowRequest(ctx, follow, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			})
 		}
 	case gtsmodel.ActivityStreamsUndo:
 		// UNDO
@@ -149,21 +246,27 @@ func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error
 			if !ok {
 				return errors.New("undo was not parseable as *gtsmodel.Follow")
 			}
-			return p.federateUnfollow(follow, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			return p.step(ctx, "federate", func() error {
+				return p.federateUnfollow(ctx, follow, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			})
 		case gtsmodel.ActivityStreamsBlock:
 			// UNDO BLOCK
 			block, ok := clientMsg.GTSModel.(*gtsmodel.Block)
 			if !ok {
 				return errors.New("undo was not parseable as *gtsmodel.Block")
 			}
-			return p.federateUnblock(block)
+			return p.step(ctx, "federate", func() error {
+				return p.federateUnblock(ctx, block)
+			})
 		case gtsmodel.ActivityStreamsLike:
 			// UNDO LIKE/FAVE
 			fave, ok := clientMsg.GTSModel.(*gtsmodel.StatusFave)
 			if !ok {
 				return errors.New("undo was not parseable as *gtsmodel.StatusFave")
 			}
-			return p.federateUnfave(fave, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			return p.step(ctx, "federate", func() error {
+				return p.federateUnfave(ctx, fave, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			})
 		case gtsmodel.ActivityStreamsAnnounce:
 			// UNDO ANNOUNCE/BOOST
 			boost, ok := clientMsg.GTSModel.(*gtsmodel.Status)
@@ -171,11 +274,15 @@ func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error
 				return errors.New("undo was not parseable as *gtsmodel.Status")
 			}
 
-			if err := p.deleteStatusFromTimelines(boost); err != nil {
+			if err := p.step(ctx, "delete from timelines", func() error {
+				return p.deleteStatusFromTimelines(ctx, boost)
+			}); err != nil {
 				return err
 			}
 
-			return p.federateUnannounce(boost, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			return p.step(ctx, "federate", func() error {
+				return p.federateUnannounce(ctx, boost, clientMsg.OriginAccount, clientMsg.TargetAccount)
+			})
 		}
 	case gtsmodel.ActivityStreamsDelete:
 		// DELETE
@@ -193,29 +300,59 @@ func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error
 
 			// delete all attachments for this status
 			for _, a := range statusToDelete.Attachments {
-				if err := p.mediaProcessor.Delete(a); err != nil {
+				if err := p.step(ctx, "delete attachment "+a, func() error {
+					return p.mediaProcessor.Delete(ctx, a)
+				}); err != nil {
 					return err
 				}
 			}
 
 			// delete all mentions for this status
 			for _, m := range statusToDelete.Mentions {
-				if err := p.db.DeleteByID(m, &gtsmodel.Mention{}); err != nil {
+				if err := p.db.DeleteByID(ctx, m, &gtsmodel.Mention{}); err != nil {
 					return err
 				}
 			}
 
 			// delete all notifications for this status
-			if err := p.db.DeleteWhere([]db.Where{{Key: "status_id", Value: statusToDelete.ID}}, &[]*gtsmodel.Notification{}); err != nil {
+			if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "status_id", Value: statusToDelete.ID}}, &[]*gtsmodel.Notification{}); err != nil {
+				return err
+			}
+
+			// take this status out of any direct message conversations
+			if err := p.step(ctx, "delete from conversations", func() error {
+				return p.deleteStatusFromConversations(ctx, statusToDelete)
+			}); err != nil {
+				return err
+			}
+
+			// delete all bookmarks of this status
+			if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "status_id", Value: statusToDelete.ID}}, &[]*gtsmodel.StatusBookmark{}); err != nil {
+				return err
+			}
+
+			// delete all mutes of this status
+			if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "status_id", Value: statusToDelete.ID}}, &[]*gtsmodel.StatusMute{}); err != nil {
+				return err
+			}
+
+			// delete any poll attached to this status
+			if err := p.step(ctx, "delete poll", func() error {
+				return p.deletePoll(ctx, statusToDelete)
+			}); err != nil {
 				return err
 			}
 
 			// delete this status from any and all timelines
-			if err := p.deleteStatusFromTimelines(statusToDelete); err != nil {
+			if err := p.step(ctx, "delete from timelines", func() error {
+				return p.deleteStatusFromTimelines(ctx, statusToDelete)
+			}); err != nil {
 				return err
 			}
 
-			return p.federateStatusDelete(statusToDelete)
+			return p.step(ctx, "federate", func() error {
+				return p.federateStatusDelete(ctx, statusToDelete)
+			})
 		case gtsmodel.ActivityStreamsProfile, gtsmodel.ActivityStreamsPerson:
 			// DELETE ACCOUNT/PROFILE
 
@@ -228,7 +365,14 @@ func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error
 				// origin is whichever account caused this message
 				origin = clientMsg.OriginAccount.ID
 			}
-			return p.accountProcessor.Delete(clientMsg.TargetAccount, origin)
+
+			// keys aren't serialized onto the queue, so work from a fresh copy of the
+			// account rather than writing a keyless one back to the database
+			account := &gtsmodel.Account{}
+			if err := p.db.GetByID(ctx, clientMsg.TargetAccount.ID, account); err != nil {
+				return err
+			}
+			return p.accountProcessor.Delete(ctx, account, origin)
 		}
 	}
 	return nil
@@ -236,10 +380,10 @@ func (p *processor) processFromClientAPI(clientMsg gtsmodel.FromClientAPI) error
 
 // TODO: move all the below functions into federation.Federator
 
-func (p *processor) federateStatus(status *gtsmodel.Status) error {
+func (p *processor) federateStatus(ctx context.Context, status *gtsmodel.Status) error {
 	if status.GTSAuthorAccount == nil {
 		a := &gtsmodel.Account{}
-		if err := p.db.GetByID(status.AccountID, a); err != nil {
+		if err := p.db.GetByID(ctx, status.AccountID, a); err != nil {
 			return fmt.Errorf("federateStatus: error fetching status author account: %s", err)
 		}
 		status.GTSAuthorAccount = a
@@ -250,24 +394,41 @@ func (p *processor) federateStatus(status *gtsmodel.Status) error {
 		return nil
 	}
 
-	asStatus, err := p.tc.StatusToAS(status)
+	asStatus, err := p.statusToAS(ctx, status)
 	if err != nil {
 		return fmt.Errorf("federateStatus: error converting status to as format: %s", err)
 	}
 
+	// questions are activities in their own right, so the federating actor
+	// won't wrap them in a create for us like it does for notes
+	if question, ok := asStatus.(vocab.ActivityStreamsQuestion); ok {
+		asStatus, err = p.tc.WrapQuestionInCreate(question, status.GTSAuthorAccount)
+		if err != nil {
+			return fmt.Errorf("federateStatus: error wrapping question in create: %s", err)
+		}
+	}
+
 	outboxIRI, err := url.Parse(status.GTSAuthorAccount.OutboxURI)
 	if err != nil {
 		return fmt.Errorf("federateStatus: error parsing outboxURI %s: %s", status.GTSAuthorAccount.OutboxURI, err)
 	}
 
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, asStatus)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, asStatus)
 	return err
 }
 
-func (p *processor) federateStatusDelete(status *gtsmodel.Status) error {
+// statusToAS converts the given status into a Question if it has a poll, or a Note otherwise.
+func (p *processor) statusToAS(ctx context.Context, status *gtsmodel.Status) (vocab.Type, error) {
+	if status.ActivityStreamsType == gtsmodel.ActivityStreamsQuestion {
+		return p.tc.PollStatusToAS(ctx, status)
+	}
+	return p.tc.StatusToAS(ctx, status)
+}
+
+func (p *processor) federateStatusDelete(ctx context.Context, status *gtsmodel.Status) error {
 	if status.GTSAuthorAccount == nil {
 		a := &gtsmodel.Account{}
-		if err := p.db.GetByID(status.AccountID, a); err != nil {
+		if err := p.db.GetByID(ctx, status.AccountID, a); err != nil {
 			return fmt.Errorf("federateStatus: error fetching status author account: %s", err)
 		}
 		status.GTSAuthorAccount = a
@@ -278,7 +439,7 @@ func (p *processor) federateStatusDelete(status *gtsmodel.Status) error {
 		return nil
 	}
 
-	asStatus, err := p.tc.StatusToAS(status)
+	asStatus, err := p.tc.StatusToAS(ctx, status)
 	if err != nil {
 		return fmt.Errorf("federateStatusDelete: error converting status to as format: %s", err)
 	}
@@ -310,11 +471,11 @@ func (p *processor) federateStatusDelete(status *gtsmodel.Status) error {
 	delete.SetActivityStreamsTo(asStatus.GetActivityStreamsTo())
 	delete.SetActivityStreamsCc(asStatus.GetActivityStreamsCc())
 
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, delete)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, delete)
 	return err
 }
 
-func (p *processor) federateFollow(followRequest *gtsmodel.FollowRequest, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+func (p *processor) federateFollow(ctx context.Context, followRequest *gtsmodel.FollowRequest, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
 	// if both accounts are local there's nothing to do here
 	if originAccount.Domain == "" && targetAccount.Domain == "" {
 		return nil
@@ -332,11 +493,11 @@ func (p *processor) federateFollow(followRequest *gtsmodel.FollowRequest, origin
 		return fmt.Errorf("federateFollow: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
 	}
 
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, asFollow)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, asFollow)
 	return err
 }
 
-func (p *processor) federateUnfollow(follow *gtsmodel.Follow, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+func (p *processor) federateUnfollow(ctx context.Context, follow *gtsmodel.Follow, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
 	// if both accounts are local there's nothing to do here
 	if originAccount.Domain == "" && targetAccount.Domain == "" {
 		return nil
@@ -373,18 +534,18 @@ func (p *processor) federateUnfollow(follow *gtsmodel.Follow, originAccount *gts
 	}
 
 	// send off the Undo
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, undo)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, undo)
 	return err
 }
 
-func (p *processor) federateUnfave(fave *gtsmodel.StatusFave, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+func (p *processor) federateUnfave(ctx context.Context, fave *gtsmodel.StatusFave, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
 	// if both accounts are local there's nothing to do here
 	if originAccount.Domain == "" && targetAccount.Domain == "" {
 		return nil
 	}
 
 	// create the AS fave
-	asFave, err := p.tc.FaveToAS(fave)
+	asFave, err := p.tc.FaveToAS(ctx, fave)
 	if err != nil {
 		return fmt.Errorf("federateFave: error converting fave to as format: %s", err)
 	}
@@ -412,17 +573,17 @@ func (p *processor) federateUnfave(fave *gtsmodel.StatusFave, originAccount *gts
 	if err != nil {
 		return fmt.Errorf("federateFave: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
 	}
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, undo)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, undo)
 	return err
 }
 
-func (p *processor) federateUnannounce(boost *gtsmodel.Status, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+func (p *processor) federateUnannounce(ctx context.Context, boost *gtsmodel.Status, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
 	if originAccount.Domain != "" {
 		// nothing to do here
 		return nil
 	}
 
-	asAnnounce, err := p.tc.BoostToAS(boost, originAccount, targetAccount)
+	asAnnounce, err := p.tc.BoostToAS(ctx, boost, originAccount, targetAccount)
 	if err != nil {
 		return fmt.Errorf("federateUnannounce: error converting status to announce: %s", err)
 	}
@@ -447,11 +608,11 @@ func (p *processor) federateUnannounce(boost *gtsmodel.Status, originAccount *gt
 		return fmt.Errorf("federateUnannounce: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
 	}
 
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, undo)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, undo)
 	return err
 }
 
-func (p *processor) federateAcceptFollowRequest(follow *gtsmodel.Follow, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+func (p *processor) federateAcceptFollowRequest(ctx context.Context, follow *gtsmodel.Follow, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
 	// if both accounts are local there's nothing to do here
 	if originAccount.Domain == "" && targetAccount.Domain == "" {
 		return nil
@@ -497,18 +658,18 @@ func (p *processor) federateAcceptFollowRequest(follow *gtsmodel.Follow, originA
 	}
 
 	// send off the accept using the accepter's outbox
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, accept)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, accept)
 	return err
 }
 
-func (p *processor) federateFave(fave *gtsmodel.StatusFave, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+func (p *processor) federateFave(ctx context.Context, fave *gtsmodel.StatusFave, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
 	// if both accounts are local there's nothing to do here
 	if originAccount.Domain == "" && targetAccount.Domain == "" {
 		return nil
 	}
 
 	// create the AS fave
-	asFave, err := p.tc.FaveToAS(fave)
+	asFave, err := p.tc.FaveToAS(ctx, fave)
 	if err != nil {
 		return fmt.Errorf("federateFave: error converting fave to as format: %s", err)
 	}
@@ -517,12 +678,12 @@ func (p *processor) federateFave(fave *gtsmodel.StatusFave, originAccount *gtsmo
 	if err != nil {
 		return fmt.Errorf("federateFave: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
 	}
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, asFave)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, asFave)
 	return err
 }
 
-func (p *processor) federateAnnounce(boostWrapperStatus *gtsmodel.Status, boostingAccount *gtsmodel.Account, boostedAccount *gtsmodel.Account) error {
-	announce, err := p.tc.BoostToAS(boostWrapperStatus, boostingAccount, boostedAccount)
+func (p *processor) federateAnnounce(ctx context.Context, boostWrapperStatus *gtsmodel.Status, boostingAccount *gtsmodel.Account, boostedAccount *gtsmodel.Account) error {
+	announce, err := p.tc.BoostToAS(ctx, boostWrapperStatus, boostingAccount, boostedAccount)
 	if err != nil {
 		return fmt.Errorf("federateAnnounce: error converting status to announce: %s", err)
 	}
@@ -532,12 +693,12 @@ func (p *processor) federateAnnounce(boostWrapperStatus *gtsmodel.Status, boosti
 		return fmt.Errorf("federateAnnounce: error parsing outboxURI %s: %s", boostingAccount.OutboxURI, err)
 	}
 
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, announce)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, announce)
 	return err
 }
 
-func (p *processor) federateAccountUpdate(updatedAccount *gtsmodel.Account, originAccount *gtsmodel.Account) error {
-	person, err := p.tc.AccountToAS(updatedAccount)
+func (p *processor) federateAccountUpdate(ctx context.Context, updatedAccount *gtsmodel.Account, originAccount *gtsmodel.Account) error {
+	person, err := p.tc.AccountToAS(ctx, updatedAccount)
 	if err != nil {
 		return fmt.Errorf("federateAccountUpdate: error converting account to person: %s", err)
 	}
@@ -552,14 +713,14 @@ func (p *processor) federateAccountUpdate(updatedAccount *gtsmodel.Account, orig
 		return fmt.Errorf("federateAnnounce: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
 	}
 
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, update)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, update)
 	return err
 }
 
-func (p *processor) federateBlock(block *gtsmodel.Block) error {
+func (p *processor) federateBlock(ctx context.Context, block *gtsmodel.Block) error {
 	if block.Account == nil {
 		a := &gtsmodel.Account{}
-		if err := p.db.GetByID(block.AccountID, a); err != nil {
+		if err := p.db.GetByID(ctx, block.AccountID, a); err != nil {
 			return fmt.Errorf("federateBlock: error getting block account from database: %s", err)
 		}
 		block.Account = a
@@ -567,7 +728,7 @@ func (p *processor) federateBlock(block *gtsmodel.Block) error {
 
 	if block.TargetAccount == nil {
 		a := &gtsmodel.Account{}
-		if err := p.db.GetByID(block.TargetAccountID, a); err != nil {
+		if err := p.db.GetByID(ctx, block.TargetAccountID, a); err != nil {
 			return fmt.Errorf("federateBlock: error getting block target account from database: %s", err)
 		}
 		block.TargetAccount = a
@@ -578,7 +739,7 @@ func (p *processor) federateBlock(block *gtsmodel.Block) error {
 		return nil
 	}
 
-	asBlock, err := p.tc.BlockToAS(block)
+	asBlock, err := p.tc.BlockToAS(ctx, block)
 	if err != nil {
 		return fmt.Errorf("federateBlock: error converting block to AS format: %s", err)
 	}
@@ -588,14 +749,56 @@ func (p *processor) federateBlock(block *gtsmodel.Block) error {
 		return fmt.Errorf("federateBlock: error parsing outboxURI %s: %s", block.Account.OutboxURI, err)
 	}
 
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, asBlock)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, asBlock)
 	return err
 }
 
-func (p *processor) federateUnblock(block *gtsmodel.Block) error {
+func (p *processor) federateReport(ctx context.Context, report *gtsmodel.Report, targetAccount *gtsmodel.Account) error {
+	// only reports about remote accounts can be forwarded
+	if targetAccount.Domain == "" {
+		return nil
+	}
+
+	// the report is sent by the instance account, so that the remote instance doesn't learn who made it
+	instanceAccount := &gtsmodel.Account{}
+	if err := p.db.GetLocalAccountByUsername(ctx, p.config.Host, instanceAccount); err != nil {
+		return fmt.Errorf("federateReport: error getting instance account %s: %s", p.config.Host, err)
+	}
+
+	asFlag, err := p.tc.ReportToASFlag(ctx, report, instanceAccount)
+	if err != nil {
+		return fmt.Errorf("federateReport: error converting report to AS format: %s", err)
+	}
+
+	data, err := streams.Serialize(asFlag)
+	if err != nil {
+		return fmt.Errorf("federateReport: error serializing flag: %s", err)
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("federateReport: error marshalling flag: %s", err)
+	}
+
+	inboxIRI, err := url.Parse(targetAccount.InboxURI)
+	if err != nil {
+		return fmt.Errorf("federateReport: error parsing inboxURI %s: %s", targetAccount.InboxURI, err)
+	}
+
+	// the instance account's username is the host, which isn't a valid outbox path,
+	// so deliver the flag straight to the target's inbox instead of going through the actor
+	t, err := p.federator.GetTransportForUser(ctx, "")
+	if err != nil {
+		return fmt.Errorf("federateReport: error getting transport for instance account: %s", err)
+	}
+
+	return t.Deliver(ctx, b, inboxIRI)
+}
+
+func (p *processor) federateUnblock(ctx context.Context, block *gtsmodel.Block) error {
 	if block.Account == nil {
 		a := &gtsmodel.Account{}
-		if err := p.db.GetByID(block.AccountID, a); err != nil {
+		if err := p.db.GetByID(ctx, block.AccountID, a); err != nil {
 			return fmt.Errorf("federateUnblock: error getting block account from database: %s", err)
 		}
 		block.Account = a
@@ -603,7 +806,7 @@ func (p *processor) federateUnblock(block *gtsmodel.Block) error {
 
 	if block.TargetAccount == nil {
 		a := &gtsmodel.Account{}
-		if err := p.db.GetByID(block.TargetAccountID, a); err != nil {
+		if err := p.db.GetByID(ctx, block.TargetAccountID, a); err != nil {
 			return fmt.Errorf("federateUnblock: error getting block target account from database: %s", err)
 		}
 		block.TargetAccount = a
@@ -614,7 +817,7 @@ func (p *processor) federateUnblock(block *gtsmodel.Block) error {
 		return nil
 	}
 
-	asBlock, err := p.tc.BlockToAS(block)
+	asBlock, err := p.tc.BlockToAS(ctx, block)
 	if err != nil {
 		return fmt.Errorf("federateUnblock: error converting block to AS format: %s", err)
 	}
@@ -642,6 +845,90 @@ func (p *processor) federateUnblock(block *gtsmodel.Block) error {
 	if err != nil {
 		return fmt.Errorf("federateUnblock: error parsing outboxURI %s: %s", block.Account.OutboxURI, err)
 	}
-	_, err = p.federator.FederatingActor().Send(context.Background(), outboxIRI, undo)
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, undo)
+	return err
+}
+
+func (p *processor) federatePollVote(ctx context.Context, vote *gtsmodel.PollVote, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+	// votes on local polls are counted straight away, so there's nothing to send
+	if targetAccount.Domain == "" {
+		return nil
+	}
+
+	asVote, err := p.tc.PollVoteToAS(ctx, vote)
+	if err != nil {
+		return fmt.Errorf("federatePollVote: error converting poll vote to as format: %s", err)
+	}
+
+	outboxIRI, err := url.Parse(originAccount.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federatePollVote: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
+	}
+
+	// the vote is a note, so the federating actor will wrap it in a create for us
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, asVote)
+	return err
+}
+
+func (p *processor) federatePollUpdate(ctx context.Context, status *gtsmodel.Status) error {
+	if status.GTSAuthorAccount == nil {
+		a := &gtsmodel.Account{}
+		if err := p.db.GetByID(ctx, status.AccountID, a); err != nil {
+			return fmt.Errorf("federatePollUpdate: error fetching status author account: %s", err)
+		}
+		status.GTSAuthorAccount = a
+	}
+
+	// do nothing if this isn't our poll
+	if status.GTSAuthorAccount.Domain != "" {
+		return nil
+	}
+
+	question, err := p.tc.PollStatusToAS(ctx, status)
+	if err != nil {
+		return fmt.Errorf("federatePollUpdate: error converting status to question: %s", err)
+	}
+
+	update, err := p.tc.WrapQuestionInUpdate(question, status.GTSAuthorAccount)
+	if err != nil {
+		return fmt.Errorf("federatePollUpdate: error wrapping question in update: %s", err)
+	}
+
+	outboxIRI, err := url.Parse(status.GTSAuthorAccount.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federatePollUpdate: error parsing outboxURI %s: %s", status.GTSAuthorAccount.OutboxURI, err)
+	}
+
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, update)
+	return err
+}
+
+func (p *processor) federateStatusPin(ctx context.Context, status *gtsmodel.Status, originAccount *gtsmodel.Account) error {
+	add, err := p.tc.WrapStatusInAdd(status, originAccount)
+	if err != nil {
+		return fmt.Errorf("federateStatusPin: error wrapping status in add: %s", err)
+	}
+
+	outboxIRI, err := url.Parse(originAccount.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federateStatusPin: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
+	}
+
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, add)
+	return err
+}
+
+func (p *processor) federateStatusUnpin(ctx context.Context, status *gtsmodel.Status, originAccount *gtsmodel.Account) error {
+	remove, err := p.tc.WrapStatusInRemove(status, originAccount)
+	if err != nil {
+		return fmt.Errorf("federateStatusUnpin: error wrapping status in remove: %s", err)
+	}
+
+	outboxIRI, err := url.Parse(originAccount.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federateStatusUnpin: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
+	}
+
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, remove)
 	return err
 }
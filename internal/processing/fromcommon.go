@@ -19,6 +19,7 @@
 package processing
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -28,7 +29,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/id"
 )
 
-func (p *processor) notifyStatus(status *gtsmodel.Status) error {
+func (p *processor) notifyStatus(ctx context.Context, status *gtsmodel.Status) error {
 	// if there are no mentions in this status then just bail
 	if len(status.Mentions) == 0 {
 		return nil
@@ -39,7 +40,7 @@ func (p *processor) notifyStatus(status *gtsmodel.Status) error {
 		menchies := []*gtsmodel.Mention{}
 		for _, m := range status.Mentions {
 			gtsm := &gtsmodel.Mention{}
-			if err := p.db.GetByID(m, gtsm); err != nil {
+			if err := p.db.GetByID(ctx, m, gtsm); err != nil {
 				return fmt.Errorf("notifyStatus: error getting mention with id %s from the db: %s", m, err)
 			}
 			menchies = append(menchies, gtsm)
@@ -52,7 +53,7 @@ func (p *processor) notifyStatus(status *gtsmodel.Status) error {
 		// make sure this is a local account, otherwise we don't need to create a notification for it
 		if m.GTSAccount == nil {
 			a := &gtsmodel.Account{}
-			if err := p.db.GetByID(m.TargetAccountID, a); err != nil {
+			if err := p.db.GetByID(ctx, m.TargetAccountID, a); err != nil {
 				// we don't have the account or there's been an error
 				return fmt.Errorf("notifyStatus: error getting account with id %s from the db: %s", m.TargetAccountID, err)
 			}
@@ -63,21 +64,23 @@ func (p *processor) notifyStatus(status *gtsmodel.Status) error {
 			continue
 		}
 
-		// make sure a notif doesn't already exist for this mention
-		err := p.db.GetWhere([]db.Where{
-			{Key: "notification_type", Value: gtsmodel.NotificationMention},
-			{Key: "target_account_id", Value: m.TargetAccountID},
-			{Key: "origin_account_id", Value: status.AccountID},
-			{Key: "status_id", Value: status.ID},
-		}, &gtsmodel.Notification{})
-		if err == nil {
-			// notification exists already so just continue
+		// don't notify an account that has muted the thread this status is part of
+		muted, err := p.db.StatusMutedBy(ctx, status, m.TargetAccountID)
+		if err != nil {
+			return fmt.Errorf("notifyStatus: error checking status mute for account %s: %s", m.TargetAccountID, err)
+		}
+		if muted {
 			continue
 		}
-		if _, ok := err.(db.ErrNoEntries); !ok {
-			// there's a real error in the db
+
+		// make sure a notif doesn't already exist for this mention
+		exists, err := p.notificationExists(ctx, gtsmodel.NotificationMention, m.TargetAccountID, status.AccountID, status.ID)
+		if err != nil {
 			return fmt.Errorf("notifyStatus: error checking existence of notification for mention with id %s : %s", m.ID, err)
 		}
+		if exists {
+			continue
+		}
 
 		// if we've reached this point we know the mention is for a local account, and the notification doesn't exist, so create it
 		notifID, err := id.NewULID()
@@ -93,17 +96,17 @@ func (p *processor) notifyStatus(status *gtsmodel.Status) error {
 			StatusID:         status.ID,
 		}
 
-		if err := p.db.Put(notif); err != nil {
+		if err := p.db.Put(ctx, notif); err != nil {
 			return fmt.Errorf("notifyStatus: error putting notification in database: %s", err)
 		}
 
 		// now stream the notification to the user
-		mastoNotif, err := p.tc.NotificationToMasto(notif)
+		mastoNotif, err := p.tc.NotificationToMasto(ctx, notif)
 		if err != nil {
 			return fmt.Errorf("notifyStatus: error converting notification to masto representation: %s", err)
 		}
 
-		if err := p.streamingProcessor.StreamNotificationToAccount(mastoNotif, m.GTSAccount); err != nil {
+		if err := p.streamingProcessor.StreamNotificationToAccount(ctx, mastoNotif, notif.GTSStatus, m.GTSAccount); err != nil {
 			return fmt.Errorf("notifyStatus: error streaming notification to account: %s", err)
 		}
 	}
@@ -111,12 +114,20 @@ func (p *processor) notifyStatus(status *gtsmodel.Status) error {
 	return nil
 }
 
-func (p *processor) notifyFollowRequest(followRequest *gtsmodel.FollowRequest, receivingAccount *gtsmodel.Account) error {
+func (p *processor) notifyFollowRequest(ctx context.Context, followRequest *gtsmodel.FollowRequest, receivingAccount *gtsmodel.Account) error {
 	// return if this isn't a local account
 	if receivingAccount.Domain != "" {
 		return nil
 	}
 
+	exists, err := p.notificationExists(ctx, gtsmodel.NotificationFollowRequest, followRequest.TargetAccountID, followRequest.AccountID, "")
+	if err != nil {
+		return fmt.Errorf("notifyFollowRequest: error checking existence of notification: %s", err)
+	}
+	if exists {
+		return nil
+	}
+
 	notifID, err := id.NewULID()
 	if err != nil {
 		return err
@@ -129,31 +140,31 @@ func (p *processor) notifyFollowRequest(followRequest *gtsmodel.FollowRequest, r
 		OriginAccountID:  followRequest.AccountID,
 	}
 
-	if err := p.db.Put(notif); err != nil {
+	if err := p.db.Put(ctx, notif); err != nil {
 		return fmt.Errorf("notifyFollowRequest: error putting notification in database: %s", err)
 	}
 
 	// now stream the notification to the user
-	mastoNotif, err := p.tc.NotificationToMasto(notif)
+	mastoNotif, err := p.tc.NotificationToMasto(ctx, notif)
 	if err != nil {
 		return fmt.Errorf("notifyStatus: error converting notification to masto representation: %s", err)
 	}
 
-	if err := p.streamingProcessor.StreamNotificationToAccount(mastoNotif, receivingAccount); err != nil {
+	if err := p.streamingProcessor.StreamNotificationToAccount(ctx, mastoNotif, notif.GTSStatus, receivingAccount); err != nil {
 		return fmt.Errorf("notifyStatus: error streaming notification to account: %s", err)
 	}
 
 	return nil
 }
 
-func (p *processor) notifyFollow(follow *gtsmodel.Follow, receivingAccount *gtsmodel.Account) error {
+func (p *processor) notifyFollow(ctx context.Context, follow *gtsmodel.Follow, receivingAccount *gtsmodel.Account) error {
 	// return if this isn't a local account
 	if receivingAccount.Domain != "" {
 		return nil
 	}
 
 	// first remove the follow request notification
-	if err := p.db.DeleteWhere([]db.Where{
+	if err := p.db.DeleteWhere(ctx, []db.Where{
 		{Key: "notification_type", Value: gtsmodel.NotificationFollowRequest},
 		{Key: "target_account_id", Value: follow.TargetAccountID},
 		{Key: "origin_account_id", Value: follow.AccountID},
@@ -161,6 +172,14 @@ func (p *processor) notifyFollow(follow *gtsmodel.Follow, receivingAccount *gtsm
 		return fmt.Errorf("notifyFollow: error removing old follow request notification from database: %s", err)
 	}
 
+	exists, err := p.notificationExists(ctx, gtsmodel.NotificationFollow, follow.TargetAccountID, follow.AccountID, "")
+	if err != nil {
+		return fmt.Errorf("notifyFollow: error checking existence of notification: %s", err)
+	}
+	if exists {
+		return nil
+	}
+
 	// now create the new follow notification
 	notifID, err := id.NewULID()
 	if err != nil {
@@ -173,29 +192,54 @@ func (p *processor) notifyFollow(follow *gtsmodel.Follow, receivingAccount *gtsm
 		TargetAccountID:  follow.TargetAccountID,
 		OriginAccountID:  follow.AccountID,
 	}
-	if err := p.db.Put(notif); err != nil {
+	if err := p.db.Put(ctx, notif); err != nil {
 		return fmt.Errorf("notifyFollow: error putting notification in database: %s", err)
 	}
 
 	// now stream the notification to the user
-	mastoNotif, err := p.tc.NotificationToMasto(notif)
+	mastoNotif, err := p.tc.NotificationToMasto(ctx, notif)
 	if err != nil {
 		return fmt.Errorf("notifyStatus: error converting notification to masto representation: %s", err)
 	}
 
-	if err := p.streamingProcessor.StreamNotificationToAccount(mastoNotif, receivingAccount); err != nil {
+	if err := p.streamingProcessor.StreamNotificationToAccount(ctx, mastoNotif, notif.GTSStatus, receivingAccount); err != nil {
 		return fmt.Errorf("notifyStatus: error streaming notification to account: %s", err)
 	}
 
 	return nil
 }
 
-func (p *processor) notifyFave(fave *gtsmodel.StatusFave, receivingAccount *gtsmodel.Account) error {
+func (p *processor) notifyFave(ctx context.Context, fave *gtsmodel.StatusFave, receivingAccount *gtsmodel.Account) error {
 	// return if this isn't a local account
 	if receivingAccount.Domain != "" {
 		return nil
 	}
 
+	if fave.GTSStatus == nil {
+		s := &gtsmodel.Status{}
+		if err := p.db.GetByID(ctx, fave.StatusID, s); err != nil {
+			return fmt.Errorf("notifyFave: error getting status with id %s: %s", fave.StatusID, err)
+		}
+		fave.GTSStatus = s
+	}
+
+	// return if the receiving account has muted the thread this status is part of
+	muted, err := p.db.StatusMutedBy(ctx, fave.GTSStatus, receivingAccount.ID)
+	if err != nil {
+		return fmt.Errorf("notifyFave: error checking status mute: %s", err)
+	}
+	if muted {
+		return nil
+	}
+
+	exists, err := p.notificationExists(ctx, gtsmodel.NotificationFave, fave.TargetAccountID, fave.AccountID, fave.StatusID)
+	if err != nil {
+		return fmt.Errorf("notifyFave: error checking existence of notification: %s", err)
+	}
+	if exists {
+		return nil
+	}
+
 	notifID, err := id.NewULID()
 	if err != nil {
 		return err
@@ -209,36 +253,36 @@ func (p *processor) notifyFave(fave *gtsmodel.StatusFave, receivingAccount *gtsm
 		StatusID:         fave.StatusID,
 	}
 
-	if err := p.db.Put(notif); err != nil {
+	if err := p.db.Put(ctx, notif); err != nil {
 		return fmt.Errorf("notifyFave: error putting notification in database: %s", err)
 	}
 
 	// now stream the notification to the user
-	mastoNotif, err := p.tc.NotificationToMasto(notif)
+	mastoNotif, err := p.tc.NotificationToMasto(ctx, notif)
 	if err != nil {
 		return fmt.Errorf("notifyStatus: error converting notification to masto representation: %s", err)
 	}
 
-	if err := p.streamingProcessor.StreamNotificationToAccount(mastoNotif, receivingAccount); err != nil {
+	if err := p.streamingProcessor.StreamNotificationToAccount(ctx, mastoNotif, notif.GTSStatus, receivingAccount); err != nil {
 		return fmt.Errorf("notifyStatus: error streaming notification to account: %s", err)
 	}
 
 	return nil
 }
 
-func (p *processor) notifyAnnounce(status *gtsmodel.Status) error {
+func (p *processor) notifyAnnounce(ctx context.Context, status *gtsmodel.Status) error {
 	if status.BoostOfID == "" {
 		// not a boost, nothing to do
 		return nil
 	}
 
 	boostedStatus := &gtsmodel.Status{}
-	if err := p.db.GetByID(status.BoostOfID, boostedStatus); err != nil {
+	if err := p.db.GetByID(ctx, status.BoostOfID, boostedStatus); err != nil {
 		return fmt.Errorf("notifyAnnounce: error getting status with id %s: %s", status.BoostOfID, err)
 	}
 
 	boostedAcct := &gtsmodel.Account{}
-	if err := p.db.GetByID(boostedStatus.AccountID, boostedAcct); err != nil {
+	if err := p.db.GetByID(ctx, boostedStatus.AccountID, boostedAcct); err != nil {
 		return fmt.Errorf("notifyAnnounce: error getting account with id %s: %s", boostedStatus.AccountID, err)
 	}
 
@@ -252,15 +296,21 @@ func (p *processor) notifyAnnounce(status *gtsmodel.Status) error {
 		return nil
 	}
 
+	muted, err := p.db.StatusMutedBy(ctx, boostedStatus, boostedAcct.ID)
+	if err != nil {
+		return fmt.Errorf("notifyAnnounce: error checking status mute: %s", err)
+	}
+	if muted {
+		// the boosted account has muted the thread, nothing to do
+		return nil
+	}
+
 	// make sure a notif doesn't already exist for this announce
-	err := p.db.GetWhere([]db.Where{
-		{Key: "notification_type", Value: gtsmodel.NotificationReblog},
-		{Key: "target_account_id", Value: boostedAcct.ID},
-		{Key: "origin_account_id", Value: status.AccountID},
-		{Key: "status_id", Value: status.ID},
-	}, &gtsmodel.Notification{})
-	if err == nil {
-		// notification exists already so just bail
+	exists, err := p.notificationExists(ctx, gtsmodel.NotificationReblog, boostedAcct.ID, status.AccountID, status.ID)
+	if err != nil {
+		return fmt.Errorf("notifyAnnounce: error checking existence of notification: %s", err)
+	}
+	if exists {
 		return nil
 	}
 
@@ -278,28 +328,174 @@ func (p *processor) notifyAnnounce(status *gtsmodel.Status) error {
 		StatusID:         status.ID,
 	}
 
-	if err := p.db.Put(notif); err != nil {
+	if err := p.db.Put(ctx, notif); err != nil {
 		return fmt.Errorf("notifyAnnounce: error putting notification in database: %s", err)
 	}
 
 	// now stream the notification to the user
-	mastoNotif, err := p.tc.NotificationToMasto(notif)
+	mastoNotif, err := p.tc.NotificationToMasto(ctx, notif)
 	if err != nil {
 		return fmt.Errorf("notifyStatus: error converting notification to masto representation: %s", err)
 	}
 
-	if err := p.streamingProcessor.StreamNotificationToAccount(mastoNotif, boostedAcct); err != nil {
+	if err := p.streamingProcessor.StreamNotificationToAccount(ctx, mastoNotif, notif.GTSStatus, boostedAcct); err != nil {
 		return fmt.Errorf("notifyStatus: error streaming notification to account: %s", err)
 	}
 
 	return nil
 }
 
-func (p *processor) timelineStatus(status *gtsmodel.Status) error {
+func (p *processor) notifyPoll(ctx context.Context, poll *gtsmodel.Poll, status *gtsmodel.Status) error {
+	votes := []*gtsmodel.PollVote{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "poll_id", Value: poll.ID}}, &votes); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return fmt.Errorf("notifyPoll: error getting votes in poll %s: %s", poll.ID, err)
+		}
+	}
+
+	// everyone who voted should be notified, as well as the author of the poll
+	targetAccountIDs := []string{poll.AccountID}
+	seen := map[string]bool{poll.AccountID: true}
+	for _, v := range votes {
+		if !seen[v.AccountID] {
+			seen[v.AccountID] = true
+			targetAccountIDs = append(targetAccountIDs, v.AccountID)
+		}
+	}
+
+	for _, targetAccountID := range targetAccountIDs {
+		targetAccount := &gtsmodel.Account{}
+		if err := p.db.GetByID(ctx, targetAccountID, targetAccount); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				continue
+			}
+			return fmt.Errorf("notifyPoll: error getting account with id %s: %s", targetAccountID, err)
+		}
+
+		if targetAccount.Domain != "" {
+			// remote account, nothing to do
+			continue
+		}
+
+		muted, err := p.db.StatusMutedBy(ctx, status, targetAccount.ID)
+		if err != nil {
+			return fmt.Errorf("notifyPoll: error checking status mute for account %s: %s", targetAccount.ID, err)
+		}
+		if muted {
+			continue
+		}
+
+		exists, err := p.notificationExists(ctx, gtsmodel.NotificationPoll, targetAccount.ID, status.AccountID, status.ID)
+		if err != nil {
+			return fmt.Errorf("notifyPoll: error checking existence of notification for account %s: %s", targetAccount.ID, err)
+		}
+		if exists {
+			continue
+		}
+
+		notifID, err := id.NewULID()
+		if err != nil {
+			return err
+		}
+
+		notif := &gtsmodel.Notification{
+			ID:               notifID,
+			NotificationType: gtsmodel.NotificationPoll,
+			TargetAccountID:  targetAccount.ID,
+			OriginAccountID:  status.AccountID,
+			StatusID:         status.ID,
+		}
+
+		if err := p.db.Put(ctx, notif); err != nil {
+			return fmt.Errorf("notifyPoll: error putting notification in database: %s", err)
+		}
+
+		// now stream the notification to the user
+		mastoNotif, err := p.tc.NotificationToMasto(ctx, notif)
+		if err != nil {
+			return fmt.Errorf("notifyPoll: error converting notification to masto representation: %s", err)
+		}
+
+		if err := p.streamingProcessor.StreamNotificationToAccount(ctx, mastoNotif, notif.GTSStatus, targetAccount); err != nil {
+			return fmt.Errorf("notifyPoll: error streaming notification to account: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// notificationExists returns true if a notification of the given type from the origin account to the target account
+// already exists, about the given status if statusID isn't empty. This lets the notify functions above be run more than
+// once for the same message, for example when a queued message is retried, without notifying anyone twice.
+func (p *processor) notificationExists(ctx context.Context, notificationType gtsmodel.NotificationType, targetAccountID string, originAccountID string, statusID string) (bool, error) {
+	where := []db.Where{
+		{Key: "notification_type", Value: notificationType},
+		{Key: "target_account_id", Value: targetAccountID},
+		{Key: "origin_account_id", Value: originAccountID},
+	}
+	if statusID != "" {
+		where = append(where, db.Where{Key: "status_id", Value: statusID})
+	}
+
+	err := p.db.GetWhere(ctx, where, &gtsmodel.Notification{})
+	if err == nil {
+		return true, nil
+	}
+	if _, ok := err.(db.ErrNoEntries); ok {
+		return false, nil
+	}
+	return false, err
+}
+
+// inheritThreadMutes mutes a new reply for every account that has muted the status it replies to,
+// so that muting a thread also covers replies that only come in after the mute was created.
+func (p *processor) inheritThreadMutes(ctx context.Context, status *gtsmodel.Status) error {
+	if status.InReplyToID == "" {
+		// not a reply, so it can't be part of a muted thread yet
+		return nil
+	}
+
+	mutes := []*gtsmodel.StatusMute{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: status.InReplyToID}}, &mutes); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil
+		}
+		return fmt.Errorf("inheritThreadMutes: error getting mutes of status %s: %s", status.InReplyToID, err)
+	}
+
+	for _, m := range mutes {
+		// the status may have been processed before, in which case it'll have inherited this mute already
+		muted, err := p.db.StatusMutedBy(ctx, status, m.AccountID)
+		if err != nil {
+			return fmt.Errorf("inheritThreadMutes: error checking status mute: %s", err)
+		}
+		if muted {
+			continue
+		}
+
+		muteID, err := id.NewULID()
+		if err != nil {
+			return err
+		}
+
+		if err := p.db.Put(ctx, &gtsmodel.StatusMute{
+			ID:              muteID,
+			AccountID:       m.AccountID,
+			TargetAccountID: status.AccountID,
+			StatusID:        status.ID,
+		}); err != nil {
+			return fmt.Errorf("inheritThreadMutes: error putting status mute in database: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *processor) timelineStatus(ctx context.Context, status *gtsmodel.Status) error {
 	// make sure the author account is pinned onto the status
 	if status.GTSAuthorAccount == nil {
 		a := &gtsmodel.Account{}
-		if err := p.db.GetByID(status.AccountID, a); err != nil {
+		if err := p.db.GetByID(ctx, status.AccountID, a); err != nil {
 			return fmt.Errorf("timelineStatus: error getting author account with id %s: %s", status.AccountID, err)
 		}
 		status.GTSAuthorAccount = a
@@ -307,7 +503,7 @@ func (p *processor) timelineStatus(status *gtsmodel.Status) error {
 
 	// get local followers of the account that posted the status
 	followers := []gtsmodel.Follow{}
-	if err := p.db.GetFollowersByAccountID(status.AccountID, &followers, true); err != nil {
+	if err := p.db.GetFollowersByAccountID(ctx, status.AccountID, &followers, true); err != nil {
 		return fmt.Errorf("timelineStatus: error getting followers for account id %s: %s", status.AccountID, err)
 	}
 
@@ -318,12 +514,44 @@ func (p *processor) timelineStatus(status *gtsmodel.Status) error {
 		})
 	}
 
+	// public statuses also go to the home timelines of local accounts that follow one of the tags used in them
+	tagFollowerIDs, err := p.tagFollowerIDs(ctx, status)
+	if err != nil {
+		return fmt.Errorf("timelineStatus: %s", err)
+	}
+	for _, accountID := range tagFollowerIDs {
+		alreadyTimelined := false
+		for _, f := range followers {
+			if f.AccountID == accountID {
+				alreadyTimelined = true
+				break
+			}
+		}
+		if !alreadyTimelined {
+			followers = append(followers, gtsmodel.Follow{
+				AccountID: accountID,
+			})
+		}
+	}
+
+	// get any lists that the account that posted the status is a member of
+	listEntries := []*gtsmodel.ListEntry{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: status.AccountID}}, &listEntries); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return fmt.Errorf("timelineStatus: error getting list entries for account id %s: %s", status.AccountID, err)
+		}
+	}
+
 	wg := sync.WaitGroup{}
-	wg.Add(len(followers))
-	errors := make(chan error, len(followers))
+	wg.Add(len(followers) + len(listEntries))
+	errors := make(chan error, len(followers)+len(listEntries))
 
 	for _, f := range followers {
-		go p.timelineStatusForAccount(status, f.AccountID, errors, &wg)
+		go p.timelineStatusForAccount(ctx, status, f.AccountID, errors, &wg)
+	}
+
+	for _, le := range listEntries {
+		go p.timelineStatusForList(ctx, status, le.ListID, errors, &wg)
 	}
 
 	// read any errors that come in from the async functions
@@ -346,22 +574,27 @@ func (p *processor) timelineStatus(status *gtsmodel.Status) error {
 		return fmt.Errorf("timelineStatus: one or more errors timelining statuses: %s", strings.Join(errs, ";"))
 	}
 
+	// stream the status to anyone watching one of its hashtags
+	if err := p.streamingProcessor.StreamStatusToHashtags(ctx, status); err != nil {
+		return fmt.Errorf("timelineStatus: error streaming status to hashtags: %s", err)
+	}
+
 	// no errors, nice
 	return nil
 }
 
-func (p *processor) timelineStatusForAccount(status *gtsmodel.Status, accountID string, errors chan error, wg *sync.WaitGroup) {
+func (p *processor) timelineStatusForAccount(ctx context.Context, status *gtsmodel.Status, accountID string, errors chan error, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	// get the timeline owner account
 	timelineAccount := &gtsmodel.Account{}
-	if err := p.db.GetByID(accountID, timelineAccount); err != nil {
+	if err := p.db.GetByID(ctx, accountID, timelineAccount); err != nil {
 		errors <- fmt.Errorf("timelineStatusForAccount: error getting account for timeline with id %s: %s", accountID, err)
 		return
 	}
 
 	// make sure the status is timelineable
-	timelineable, err := p.filter.StatusHometimelineable(status, timelineAccount)
+	timelineable, err := p.filter.StatusHometimelineable(ctx, status, timelineAccount)
 	if err != nil {
 		errors <- fmt.Errorf("timelineStatusForAccount: error getting timelineability for status for timeline with id %s: %s", accountID, err)
 		return
@@ -372,7 +605,7 @@ func (p *processor) timelineStatusForAccount(status *gtsmodel.Status, accountID
 	}
 
 	// stick the status in the timeline for the account and then immediately prepare it so they can see it right away
-	inserted, err := p.timelineManager.IngestAndPrepare(status, timelineAccount.ID)
+	inserted, err := p.timelineManager.IngestAndPrepare(ctx, status, timelineAccount.ID)
 	if err != nil {
 		errors <- fmt.Errorf("timelineStatusForAccount: error ingesting status %s: %s", status.ID, err)
 		return
@@ -380,30 +613,77 @@ func (p *processor) timelineStatusForAccount(status *gtsmodel.Status, accountID
 
 	// the status was inserted to stream it to the user
 	if inserted {
-		mastoStatus, err := p.tc.StatusToMasto(status, timelineAccount)
+		mastoStatus, err := p.tc.StatusToMasto(ctx, status, timelineAccount)
 		if err != nil {
 			errors <- fmt.Errorf("timelineStatusForAccount: error converting status %s to frontend representation: %s", status.ID, err)
 		} else {
-			if err := p.streamingProcessor.StreamStatusToAccount(mastoStatus, timelineAccount); err != nil {
+			if err := p.streamingProcessor.StreamStatusToAccount(ctx, mastoStatus, status, timelineAccount); err != nil {
 				errors <- fmt.Errorf("timelineStatusForAccount: error streaming status %s: %s", status.ID, err)
 			}
 		}
 	}
 
-	mastoStatus, err := p.tc.StatusToMasto(status, timelineAccount)
+	mastoStatus, err := p.tc.StatusToMasto(ctx, status, timelineAccount)
 	if err != nil {
 		errors <- fmt.Errorf("timelineStatusForAccount: error converting status %s to frontend representation: %s", status.ID, err)
 	} else {
-		if err := p.streamingProcessor.StreamStatusToAccount(mastoStatus, timelineAccount); err != nil {
+		if err := p.streamingProcessor.StreamStatusToAccount(ctx, mastoStatus, status, timelineAccount); err != nil {
 			errors <- fmt.Errorf("timelineStatusForAccount: error streaming status %s: %s", status.ID, err)
 		}
 	}
 }
 
-func (p *processor) deleteStatusFromTimelines(status *gtsmodel.Status) error {
+func (p *processor) timelineStatusForList(ctx context.Context, status *gtsmodel.Status, listID string, errors chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	// get the list and the account that owns it
+	list := &gtsmodel.List{}
+	if err := p.db.GetByID(ctx, listID, list); err != nil {
+		errors <- fmt.Errorf("timelineStatusForList: error getting list with id %s: %s", listID, err)
+		return
+	}
+
+	listAccount := &gtsmodel.Account{}
+	if err := p.db.GetByID(ctx, list.AccountID, listAccount); err != nil {
+		errors <- fmt.Errorf("timelineStatusForList: error getting account for list with id %s: %s", listID, err)
+		return
+	}
+
+	// make sure the status is timelineable
+	timelineable, err := p.filter.StatusListTimelineable(ctx, status, list, listAccount)
+	if err != nil {
+		errors <- fmt.Errorf("timelineStatusForList: error getting timelineability for status for list with id %s: %s", listID, err)
+		return
+	}
+
+	if !timelineable {
+		return
+	}
+
+	// stick the status in the timeline for the list and then immediately prepare it so it can be seen right away
+	inserted, err := p.timelineManager.IngestAndPrepareList(ctx, status, list.ID)
+	if err != nil {
+		errors <- fmt.Errorf("timelineStatusForList: error ingesting status %s: %s", status.ID, err)
+		return
+	}
+
+	// the status was inserted so stream it to the list
+	if inserted {
+		mastoStatus, err := p.tc.StatusToMasto(ctx, status, listAccount)
+		if err != nil {
+			errors <- fmt.Errorf("timelineStatusForList: error converting status %s to frontend representation: %s", status.ID, err)
+		} else {
+			if err := p.streamingProcessor.StreamStatusToList(ctx, mastoStatus, list.ID, listAccount); err != nil {
+				errors <- fmt.Errorf("timelineStatusForList: error streaming status %s: %s", status.ID, err)
+			}
+		}
+	}
+}
+
+func (p *processor) deleteStatusFromTimelines(ctx context.Context, status *gtsmodel.Status) error {
 	if err := p.timelineManager.WipeStatusFromAllTimelines(status.ID); err != nil {
 		return err
 	}
 
-	return p.streamingProcessor.StreamDelete(status.ID)
+	return p.streamingProcessor.StreamDelete(ctx, status.ID)
 }
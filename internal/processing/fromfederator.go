@@ -19,6 +19,7 @@
 package processing
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -28,7 +29,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/id"
 )
 
-func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) error {
+func (p *processor) processFromFederator(ctx context.Context, federatorMsg gtsmodel.FromFederator) error {
 	l := p.log.WithFields(logrus.Fields{
 		"func":         "processFromFederator",
 		"federatorMsg": fmt.Sprintf("%+v", federatorMsg),
@@ -41,6 +42,17 @@ func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) er
 		// CREATE
 		switch federatorMsg.APObjectType {
 		case gtsmodel.ActivityStreamsNote:
+			if vote, ok := federatorMsg.GTSModel.(*gtsmodel.PollVote); ok {
+				// CREATE A POLL VOTE
+				status, err := p.recountPoll(ctx, vote.PollID)
+				if err != nil {
+					return fmt.Errorf("error recounting poll %s: %s", vote.PollID, err)
+				}
+				return p.step(ctx, "federate", func() error {
+					return p.federatePollUpdate(ctx, status)
+				})
+			}
+
 			// CREATE A STATUS
 			incomingStatus, ok := federatorMsg.GTSModel.(*gtsmodel.Status)
 			if !ok {
@@ -48,18 +60,34 @@ func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) er
 			}
 
 			l.Trace("will now derefence incoming status")
-			if err := p.federator.DereferenceStatusFields(incomingStatus, federatorMsg.ReceivingAccount.Username); err != nil {
+			if err := p.federator.DereferenceStatusFields(ctx, incomingStatus, federatorMsg.ReceivingAccount.Username); err != nil {
 				return fmt.Errorf("error dereferencing status from federator: %s", err)
 			}
-			if err := p.db.UpdateByID(incomingStatus.ID, incomingStatus); err != nil {
+			if err := p.db.UpdateByID(ctx, incomingStatus.ID, incomingStatus); err != nil {
 				return fmt.Errorf("error updating dereferenced status in the db: %s", err)
 			}
 
-			if err := p.timelineStatus(incomingStatus); err != nil {
+			if err := p.step(ctx, "inherit thread mutes", func() error {
+				return p.inheritThreadMutes(ctx, incomingStatus)
+			}); err != nil {
+				return err
+			}
+
+			if err := p.step(ctx, "timeline", func() error {
+				return p.timelineStatus(ctx, incomingStatus)
+			}); err != nil {
 				return err
 			}
 
-			if err := p.notifyStatus(incomingStatus); err != nil {
+			if err := p.step(ctx, "notify", func() error {
+				return p.notifyStatus(ctx, incomingStatus)
+			}); err != nil {
+				return err
+			}
+
+			if err := p.step(ctx, "conversations", func() error {
+				return p.updateConversations(ctx, incomingStatus)
+			}); err != nil {
 				return err
 			}
 
@@ -71,10 +99,10 @@ func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) er
 			}
 
 			l.Trace("will now derefence incoming account")
-			if err := p.federator.DereferenceAccountFields(incomingAccount, "", false); err != nil {
+			if err := p.federator.DereferenceAccountFields(ctx, incomingAccount, "", false); err != nil {
 				return fmt.Errorf("error dereferencing account from federator: %s", err)
 			}
-			if err := p.db.UpdateByID(incomingAccount.ID, incomingAccount); err != nil {
+			if err := p.db.UpdateByID(ctx, incomingAccount.ID, incomingAccount); err != nil {
 				return fmt.Errorf("error updating dereferenced account in the db: %s", err)
 			}
 		case gtsmodel.ActivityStreamsLike:
@@ -84,7 +112,9 @@ func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) er
 				return errors.New("like was not parseable as *gtsmodel.StatusFave")
 			}
 
-			if err := p.notifyFave(incomingFave, federatorMsg.ReceivingAccount); err != nil {
+			if err := p.step(ctx, "notify", func() error {
+				return p.notifyFave(ctx, incomingFave, federatorMsg.ReceivingAccount)
+			}); err != nil {
 				return err
 			}
 		case gtsmodel.ActivityStreamsFollow:
@@ -94,7 +124,9 @@ func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) er
 				return errors.New("incomingFollowRequest was not parseable as *gtsmodel.FollowRequest")
 			}
 
-			if err := p.notifyFollowRequest(incomingFollowRequest, federatorMsg.ReceivingAccount); err != nil {
+			if err := p.step(ctx, "notify", func() error {
+				return p.notifyFollowRequest(ctx, incomingFollowRequest, federatorMsg.ReceivingAccount)
+			}); err != nil {
 				return err
 			}
 		case gtsmodel.ActivityStreamsAnnounce:
@@ -104,7 +136,7 @@ func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) er
 				return errors.New("announce was not parseable as *gtsmodel.Status")
 			}
 
-			if err := p.federator.DereferenceAnnounce(incomingAnnounce, federatorMsg.ReceivingAccount.Username); err != nil {
+			if err := p.federator.DereferenceAnnounce(ctx, incomingAnnounce, federatorMsg.ReceivingAccount.Username); err != nil {
 				return fmt.Errorf("error dereferencing announce from federator: %s", err)
 			}
 
@@ -114,17 +146,25 @@ func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) er
 			}
 			incomingAnnounce.ID = incomingAnnounceID
 
-			if err := p.db.Put(incomingAnnounce); err != nil {
+			if err := p.db.Put(ctx, incomingAnnounce); err != nil {
 				if _, ok := err.(db.ErrAlreadyExists); !ok {
 					return fmt.Errorf("error adding dereferenced announce to the db: %s", err)
 				}
+				// we put this announce during an earlier attempt, so carry on with the one we stored then
+				if err := p.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: incomingAnnounce.URI}}, incomingAnnounce); err != nil {
+					return fmt.Errorf("error getting existing announce from the db: %s", err)
+				}
 			}
 
-			if err := p.timelineStatus(incomingAnnounce); err != nil {
+			if err := p.step(ctx, "timeline", func() error {
+				return p.timelineStatus(ctx, incomingAnnounce)
+			}); err != nil {
 				return err
 			}
 
-			if err := p.notifyAnnounce(incomingAnnounce); err != nil {
+			if err := p.step(ctx, "notify", func() error {
+				return p.notifyAnnounce(ctx, incomingAnnounce)
+			}); err != nil {
 				return err
 			}
 		case gtsmodel.ActivityStreamsBlock:
@@ -135,10 +175,10 @@ func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) er
 			}
 
 			// remove any of the blocking account's statuses from the blocked account's timeline, and vice versa
-			if err := p.timelineManager.WipeStatusesFromAccountID(block.AccountID, block.TargetAccountID); err != nil {
+			if err := p.timelineManager.WipeStatusesFromAccountID(ctx, block.AccountID, block.TargetAccountID); err != nil {
 				return err
 			}
-			if err := p.timelineManager.WipeStatusesFromAccountID(block.TargetAccountID, block.AccountID); err != nil {
+			if err := p.timelineManager.WipeStatusesFromAccountID(ctx, block.TargetAccountID, block.AccountID); err != nil {
 				return err
 			}
 			// TODO: same with notifications
@@ -155,10 +195,10 @@ func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) er
 			}
 
 			l.Trace("will now derefence incoming account")
-			if err := p.federator.DereferenceAccountFields(incomingAccount, federatorMsg.ReceivingAccount.Username, true); err != nil {
+			if err := p.federator.DereferenceAccountFields(ctx, incomingAccount, federatorMsg.ReceivingAccount.Username, true); err != nil {
 				return fmt.Errorf("error dereferencing account from federator: %s", err)
 			}
-			if err := p.db.UpdateByID(incomingAccount.ID, incomingAccount); err != nil {
+			if err := p.db.UpdateByID(ctx, incomingAccount.ID, incomingAccount); err != nil {
 				return fmt.Errorf("error updating dereferenced account in the db: %s", err)
 			}
 		}
@@ -178,25 +218,53 @@ func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) er
 
 			// delete all attachments for this status
 			for _, a := range statusToDelete.Attachments {
-				if err := p.mediaProcessor.Delete(a); err != nil {
+				if err := p.step(ctx, "delete attachment "+a, func() error {
+					return p.mediaProcessor.Delete(ctx, a)
+				}); err != nil {
 					return err
 				}
 			}
 
 			// delete all mentions for this status
 			for _, m := range statusToDelete.Mentions {
-				if err := p.db.DeleteByID(m, &gtsmodel.Mention{}); err != nil {
+				if err := p.db.DeleteByID(ctx, m, &gtsmodel.Mention{}); err != nil {
 					return err
 				}
 			}
 
 			// delete all notifications for this status
-			if err := p.db.DeleteWhere([]db.Where{{Key: "status_id", Value: statusToDelete.ID}}, &[]*gtsmodel.Notification{}); err != nil {
+			if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "status_id", Value: statusToDelete.ID}}, &[]*gtsmodel.Notification{}); err != nil {
+				return err
+			}
+
+			// take this status out of any direct message conversations
+			if err := p.step(ctx, "delete from conversations", func() error {
+				return p.deleteStatusFromConversations(ctx, statusToDelete)
+			}); err != nil {
+				return err
+			}
+
+			// delete all bookmarks of this status
+			if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "status_id", Value: statusToDelete.ID}}, &[]*gtsmodel.StatusBookmark{}); err != nil {
+				return err
+			}
+
+			// delete all mutes of this status
+			if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "status_id", Value: statusToDelete.ID}}, &[]*gtsmodel.StatusMute{}); err != nil {
+				return err
+			}
+
+			// delete any poll attached to this status
+			if err := p.step(ctx, "delete poll", func() error {
+				return p.deletePoll(ctx, statusToDelete)
+			}); err != nil {
 				return err
 			}
 
 			// remove this status from any and all timelines
-			return p.deleteStatusFromTimelines(statusToDelete)
+			return p.step(ctx, "delete from timelines", func() error {
+				return p.deleteStatusFromTimelines(ctx, statusToDelete)
+			})
 		case gtsmodel.ActivityStreamsProfile:
 			// DELETE A PROFILE/ACCOUNT
 			// TODO: handle side effects of account deletion here: delete all objects, statuses, media etc associated with account
@@ -211,7 +279,9 @@ func (p *processor) processFromFederator(federatorMsg gtsmodel.FromFederator) er
 				return errors.New("follow was not parseable as *gtsmodel.Follow")
 			}
 
-			if err := p.notifyFollow(follow, federatorMsg.ReceivingAccount); err != nil {
+			if err := p.step(ctx, "notify", func() error {
+				return p.notifyFollow(ctx, follow, federatorMsg.ReceivingAccount)
+			}); err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,368 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+func (p *processor) ListsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.List, gtserror.WithCode) {
+	lists := []*gtsmodel.List{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: authed.Account.ID}}, &lists); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("ListsGet: error getting lists: %s", err))
+		}
+	}
+
+	return p.listsToMasto(lists)
+}
+
+func (p *processor) AccountListsGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) ([]*apimodel.List, gtserror.WithCode) {
+	entries := []*gtsmodel.ListEntry{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: targetAccountID}}, &entries); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("AccountListsGet: error getting list entries: %s", err))
+		}
+	}
+
+	// only return lists owned by the requester that the target account is in
+	lists := []*gtsmodel.List{}
+	for _, entry := range entries {
+		list := &gtsmodel.List{}
+		if err := p.db.GetByID(ctx, entry.ListID, list); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				continue
+			}
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("AccountListsGet: error getting list %s: %s", entry.ListID, err))
+		}
+		if list.AccountID == authed.Account.ID {
+			lists = append(lists, list)
+		}
+	}
+
+	return p.listsToMasto(lists)
+}
+
+func (p *processor) ListGet(ctx context.Context, authed *oauth.Auth, listID string) (*apimodel.List, gtserror.WithCode) {
+	list, errWithCode := p.getOwnList(ctx, authed, listID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.listToMasto(list)
+}
+
+func (p *processor) ListCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ListCreateRequest) (*apimodel.List, gtserror.WithCode) {
+	repliesPolicy, err := parseRepliesPolicy(form.RepliesPolicy)
+	if err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if form.Title == "" {
+		err := errors.New("list title must not be empty")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	listID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	list := &gtsmodel.List{
+		ID:            listID,
+		Title:         form.Title,
+		AccountID:     authed.Account.ID,
+		RepliesPolicy: repliesPolicy,
+	}
+
+	if err := p.db.Put(ctx, list); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ListCreate: error putting list in db: %s", err))
+	}
+
+	return p.listToMasto(list)
+}
+
+func (p *processor) ListUpdate(ctx context.Context, authed *oauth.Auth, listID string, form *apimodel.ListCreateRequest) (*apimodel.List, gtserror.WithCode) {
+	list, errWithCode := p.getOwnList(ctx, authed, listID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if form.Title != "" {
+		list.Title = form.Title
+	}
+
+	if form.RepliesPolicy != "" {
+		repliesPolicy, err := parseRepliesPolicy(form.RepliesPolicy)
+		if err != nil {
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		list.RepliesPolicy = repliesPolicy
+	}
+
+	if err := p.db.UpdateByID(ctx, list.ID, list); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ListUpdate: error updating list in db: %s", err))
+	}
+
+	// the replies policy might have changed what belongs in the timeline of the list
+	p.timelineManager.RemoveListTimeline(list.ID)
+
+	return p.listToMasto(list)
+}
+
+func (p *processor) ListDelete(ctx context.Context, authed *oauth.Auth, listID string) (*apimodel.List, gtserror.WithCode) {
+	list, errWithCode := p.getOwnList(ctx, authed, listID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "list_id", Value: list.ID}}, &[]*gtsmodel.ListEntry{}); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ListDelete: error deleting list entries from db: %s", err))
+	}
+
+	if err := p.db.DeleteByID(ctx, list.ID, &gtsmodel.List{}); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ListDelete: error deleting list from db: %s", err))
+	}
+
+	p.timelineManager.RemoveListTimeline(list.ID)
+
+	return p.listToMasto(list)
+}
+
+func (p *processor) ListAccountsGet(ctx context.Context, authed *oauth.Auth, listID string, maxID string, sinceID string, limit int) (*apimodel.ListAccountsResponse, gtserror.WithCode) {
+	list, errWithCode := p.getOwnList(ctx, authed, listID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	resp := &apimodel.ListAccountsResponse{
+		Accounts: []*apimodel.Account{},
+	}
+
+	entries, err := p.db.GetListEntries(ctx, list.ID, maxID, sinceID, limit)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			// there are just no entries
+			return resp, nil
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ListAccountsGet: error getting list entries: %s", err))
+	}
+
+	for _, entry := range entries {
+		account := &gtsmodel.Account{}
+		if err := p.db.GetByID(ctx, entry.AccountID, account); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				continue
+			}
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("ListAccountsGet: error getting account %s: %s", entry.AccountID, err))
+		}
+
+		apiAccount, err := p.tc.AccountToMastoPublic(ctx, account)
+		if err != nil {
+			continue
+		}
+		resp.Accounts = append(resp.Accounts, apiAccount)
+	}
+
+	// only page if a limit was given, otherwise all the accounts were returned in one go
+	if limit > 0 {
+		path := fmt.Sprintf("/api/v1/lists/%s/accounts", list.ID)
+		nextLink := &url.URL{
+			Scheme:   p.config.Protocol,
+			Host:     p.config.Host,
+			Path:     path,
+			RawQuery: fmt.Sprintf("limit=%d&max_id=%s", limit, entries[len(entries)-1].ID),
+		}
+		prevLink := &url.URL{
+			Scheme:   p.config.Protocol,
+			Host:     p.config.Host,
+			Path:     path,
+			RawQuery: fmt.Sprintf("limit=%d&since_id=%s", limit, entries[0].ID),
+		}
+		resp.LinkHeader = fmt.Sprintf("<%s>; rel=\"next\", <%s>; rel=\"prev\"", nextLink.String(), prevLink.String())
+	}
+
+	return resp, nil
+}
+
+func (p *processor) ListAccountsAdd(ctx context.Context, authed *oauth.Auth, listID string, form *apimodel.ListAccountsChangeRequest) (*apimodel.List, gtserror.WithCode) {
+	list, errWithCode := p.getOwnList(ctx, authed, listID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if len(form.AccountIDs) == 0 {
+		err := errors.New("no account ids given")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// check every account first, so that we don't add some of them and then bail halfway through
+	entries := []*gtsmodel.ListEntry{}
+	for _, accountID := range form.AccountIDs {
+		follow := &gtsmodel.Follow{}
+		if err := p.db.GetWhere(ctx, []db.Where{
+			{Key: "account_id", Value: authed.Account.ID},
+			{Key: "target_account_id", Value: accountID},
+		}, follow); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				err := fmt.Errorf("you must follow account %s to add it to a list", accountID)
+				return nil, gtserror.NewErrorBadRequest(err, err.Error())
+			}
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("ListAccountsAdd: error checking follow of account %s: %s", accountID, err))
+		}
+
+		existing := &gtsmodel.ListEntry{}
+		if err := p.db.GetWhere(ctx, []db.Where{
+			{Key: "list_id", Value: list.ID},
+			{Key: "account_id", Value: accountID},
+		}, existing); err == nil {
+			// already in the list, nothing to do
+			continue
+		} else if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("ListAccountsAdd: error checking list entry for account %s: %s", accountID, err))
+		}
+
+		entryID, err := id.NewULID()
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		entries = append(entries, &gtsmodel.ListEntry{
+			ID:        entryID,
+			ListID:    list.ID,
+			AccountID: accountID,
+			FollowID:  follow.ID,
+		})
+	}
+
+	for _, entry := range entries {
+		if err := p.db.Put(ctx, entry); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("ListAccountsAdd: error putting list entry in db: %s", err))
+		}
+	}
+
+	// the timeline of the list will be indexed again with the new members the next time it's used
+	p.timelineManager.RemoveListTimeline(list.ID)
+
+	return p.listToMasto(list)
+}
+
+func (p *processor) ListAccountsRemove(ctx context.Context, authed *oauth.Auth, listID string, form *apimodel.ListAccountsChangeRequest) (*apimodel.List, gtserror.WithCode) {
+	list, errWithCode := p.getOwnList(ctx, authed, listID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if len(form.AccountIDs) == 0 {
+		err := errors.New("no account ids given")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	for _, accountID := range form.AccountIDs {
+		if err := p.db.DeleteWhere(ctx, []db.Where{
+			{Key: "list_id", Value: list.ID},
+			{Key: "account_id", Value: accountID},
+		}, &[]*gtsmodel.ListEntry{}); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("ListAccountsRemove: error deleting list entry from db: %s", err))
+		}
+	}
+
+	p.timelineManager.RemoveListTimeline(list.ID)
+
+	return p.listToMasto(list)
+}
+
+func (p *processor) ListTimelineGet(ctx context.Context, authed *oauth.Auth, listID string, maxID string, sinceID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	list, errWithCode := p.getOwnList(ctx, authed, listID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	statuses, err := p.timelineManager.ListTimeline(ctx, list.ID, maxID, sinceID, minID, limit)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if len(statuses) == 0 {
+		return &apimodel.StatusTimelineResponse{
+			Statuses: []*apimodel.Status{},
+		}, nil
+	}
+
+	return p.packageStatusResponse(statuses, "api/v1/timelines/list/"+list.ID, statuses[len(statuses)-1].ID, statuses[0].ID, limit)
+}
+
+// getOwnList gets the list with the given ID, making sure that it belongs to the authed account.
+// Lists belonging to other accounts are treated as not found, so as not to give away that they exist.
+func (p *processor) getOwnList(ctx context.Context, authed *oauth.Auth, listID string) (*gtsmodel.List, gtserror.WithCode) {
+	list := &gtsmodel.List{}
+	if err := p.db.GetByID(ctx, listID, list); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("list %s not found", listID))
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting list %s: %s", listID, err))
+	}
+
+	if list.AccountID != authed.Account.ID {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("list %s does not belong to account %s", listID, authed.Account.ID))
+	}
+
+	return list, nil
+}
+
+func (p *processor) listToMasto(list *gtsmodel.List) (*apimodel.List, gtserror.WithCode) {
+	apiList, err := p.tc.ListToMasto(list)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting list to api representation: %s", err))
+	}
+	return apiList, nil
+}
+
+func (p *processor) listsToMasto(lists []*gtsmodel.List) ([]*apimodel.List, gtserror.WithCode) {
+	apiLists := []*apimodel.List{}
+	for _, list := range lists {
+		apiList, errWithCode := p.listToMasto(list)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		apiLists = append(apiLists, apiList)
+	}
+	return apiLists, nil
+}
+
+// parseRepliesPolicy parses the given replies policy from a list form, defaulting to followed if it's empty.
+func parseRepliesPolicy(repliesPolicy string) (gtsmodel.ListRepliesPolicy, error) {
+	switch gtsmodel.ListRepliesPolicy(repliesPolicy) {
+	case "":
+		return gtsmodel.ListRepliesPolicyFollowed, nil
+	case gtsmodel.ListRepliesPolicyFollowed, gtsmodel.ListRepliesPolicyList, gtsmodel.ListRepliesPolicyNone:
+		return gtsmodel.ListRepliesPolicy(repliesPolicy), nil
+	}
+	return "", fmt.Errorf("replies policy %s not recognised, must be one of %s, %s or %s", repliesPolicy, gtsmodel.ListRepliesPolicyFollowed, gtsmodel.ListRepliesPolicyList, gtsmodel.ListRepliesPolicyNone)
+}
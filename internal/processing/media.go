@@ -19,23 +19,89 @@
 package processing
 
 import (
+	"context"
+	"time"
+
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
-func (p *processor) MediaCreate(authed *oauth.Auth, form *apimodel.AttachmentRequest) (*apimodel.Attachment, error) {
-	return p.mediaProcessor.Create(authed.Account, form)
+// mediaPruneInterval is how often remote media that hasn't been accessed for a while is pruned from storage.
+const mediaPruneInterval = 24 * time.Hour
+
+// mediaCleanupInterval is how often unattached and orphaned media is cleaned up.
+const mediaCleanupInterval = 24 * time.Hour
+
+func (p *processor) MediaCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AttachmentRequest) (*apimodel.Attachment, error) {
+	return p.mediaProcessor.Create(ctx, authed.Account, form)
 }
 
-func (p *processor) MediaGet(authed *oauth.Auth, mediaAttachmentID string) (*apimodel.Attachment, gtserror.WithCode) {
-	return p.mediaProcessor.GetMedia(authed.Account, mediaAttachmentID)
+func (p *processor) MediaGet(ctx context.Context, authed *oauth.Auth, mediaAttachmentID string) (*apimodel.Attachment, gtserror.WithCode) {
+	return p.mediaProcessor.GetMedia(ctx, authed.Account, mediaAttachmentID)
 }
 
-func (p *processor) MediaUpdate(authed *oauth.Auth, mediaAttachmentID string, form *apimodel.AttachmentUpdateRequest) (*apimodel.Attachment, gtserror.WithCode) {
-	return p.mediaProcessor.Update(authed.Account, mediaAttachmentID, form)
+func (p *processor) MediaUpdate(ctx context.Context, authed *oauth.Auth, mediaAttachmentID string, form *apimodel.AttachmentUpdateRequest) (*apimodel.Attachment, gtserror.WithCode) {
+	return p.mediaProcessor.Update(ctx, authed.Account, mediaAttachmentID, form)
 }
 
-func (p *processor) FileGet(authed *oauth.Auth, form *apimodel.GetContentRequestForm) (*apimodel.Content, error) {
-	return p.mediaProcessor.GetFile(authed.Account, form)
+func (p *processor) FileGet(ctx context.Context, authed *oauth.Auth, form *apimodel.GetContentRequestForm) (*apimodel.Content, error) {
+	return p.mediaProcessor.GetFile(ctx, authed.Account, form)
+}
+
+// pruneRemoteMedia periodically removes remote media that hasn't been accessed for the configured number of days
+// from storage, until the processor is stopped.
+//
+// Pruned media is dereferenced again when it's requested, so this just stops remote media from piling up in storage.
+func (p *processor) pruneRemoteMedia(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(mediaPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		if days := p.config.MediaConfig.RemoteCacheDays; days > 0 {
+			olderThan := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+			pruned, err := p.mediaHandler.PruneRemoteMedia(ctx, olderThan)
+			if err != nil {
+				p.log.Errorf("pruneRemoteMedia: error pruning remote media: %s", err)
+			}
+			if pruned != 0 {
+				p.log.Infof("pruneRemoteMedia: pruned %d remote media attachments from storage", pruned)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// cleanupMedia periodically removes media that has been unattached or orphaned for longer than the configured
+// grace period, as well as files in storage that don't belong to any media, until the processor is stopped.
+func (p *processor) cleanupMedia(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(mediaCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		if hours := p.config.MediaConfig.CleanupGraceHours; hours > 0 {
+			result, err := p.mediaHandler.CleanupMedia(ctx, time.Now().Add(-time.Duration(hours)*time.Hour), false)
+			if err != nil {
+				p.log.Errorf("cleanupMedia: error cleaning up media: %s", err)
+			}
+			if result.Unattached+result.Orphaned+result.OrphanedFiles != 0 {
+				p.log.Infof("cleanupMedia: removed %d unattached attachments, %d orphaned attachments and %d orphaned files, freeing %d bytes", result.Unattached, result.Orphaned, result.OrphanedFiles, result.Bytes)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
 }
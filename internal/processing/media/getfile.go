@@ -19,8 +19,10 @@
 package media
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
@@ -28,7 +30,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/media"
 )
 
-func (p *processor) GetFile(account *gtsmodel.Account, form *apimodel.GetContentRequestForm) (*apimodel.Content, error) {
+func (p *processor) GetFile(ctx context.Context, account *gtsmodel.Account, form *apimodel.GetContentRequestForm) (*apimodel.Content, error) {
 	// parse the form fields
 	mediaSize, err := media.ParseMediaSize(form.MediaSize)
 	if err != nil {
@@ -48,7 +50,7 @@ func (p *processor) GetFile(account *gtsmodel.Account, form *apimodel.GetContent
 
 	// get the account that owns the media and make sure it's not suspended
 	acct := &gtsmodel.Account{}
-	if err := p.db.GetByID(form.AccountID, acct); err != nil {
+	if err := p.db.GetByID(ctx, form.AccountID, acct); err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("account with id %s could not be selected from the db: %s", form.AccountID, err))
 	}
 	if !acct.SuspendedAt.IsZero() {
@@ -57,7 +59,7 @@ func (p *processor) GetFile(account *gtsmodel.Account, form *apimodel.GetContent
 
 	// make sure the requesting account and the media account don't block each other
 	if account != nil {
-		blocked, err := p.db.Blocked(account.ID, form.AccountID)
+		blocked, err := p.db.Blocked(ctx, account.ID, form.AccountID)
 		if err != nil {
 			return nil, gtserror.NewErrorNotFound(fmt.Errorf("block status could not be established between accounts %s and %s: %s", form.AccountID, account.ID, err))
 		}
@@ -73,7 +75,7 @@ func (p *processor) GetFile(account *gtsmodel.Account, form *apimodel.GetContent
 	switch mediaType {
 	case media.Emoji:
 		e := &gtsmodel.Emoji{}
-		if err := p.db.GetByID(wantedMediaID, e); err != nil {
+		if err := p.db.GetByID(ctx, wantedMediaID, e); err != nil {
 			return nil, gtserror.NewErrorNotFound(fmt.Errorf("emoji %s could not be taken from the db: %s", wantedMediaID, err))
 		}
 		if e.Disabled {
@@ -91,12 +93,15 @@ func (p *processor) GetFile(account *gtsmodel.Account, form *apimodel.GetContent
 		}
 	case media.Attachment, media.Header, media.Avatar:
 		a := &gtsmodel.MediaAttachment{}
-		if err := p.db.GetByID(wantedMediaID, a); err != nil {
+		if err := p.db.GetByID(ctx, wantedMediaID, a); err != nil {
 			return nil, gtserror.NewErrorNotFound(fmt.Errorf("attachment %s could not be taken from the db: %s", wantedMediaID, err))
 		}
 		if a.AccountID != form.AccountID {
 			return nil, gtserror.NewErrorNotFound(fmt.Errorf("attachment %s is not owned by %s", wantedMediaID, form.AccountID))
 		}
+		if err := p.touchAttachment(ctx, a); err != nil {
+			return nil, gtserror.NewErrorNotFound(err)
+		}
 		switch mediaSize {
 		case media.Original:
 			content.ContentType = a.File.ContentType
@@ -109,12 +114,45 @@ func (p *processor) GetFile(account *gtsmodel.Account, form *apimodel.GetContent
 		}
 	}
 
-	bytes, err := p.storage.RetrieveFileFrom(storagePath)
+	info, err := p.storage.StatFile(storagePath)
 	if err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error retrieving from storage: %s", err))
 	}
 
-	content.ContentLength = int64(len(bytes))
-	content.Content = bytes
+	f, err := p.storage.OpenFile(storagePath)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error retrieving from storage: %s", err))
+	}
+
+	// stored media never changes for a given id and size, so that's all we need to identify it
+	content.ETag = fmt.Sprintf(`"%s-%s-%d"`, wantedMediaID, mediaSize, info.Size)
+	content.ContentUpdated = info.ModTime
+	content.ContentLength = info.Size
+	content.Content = f
 	return content, nil
 }
+
+// touchAttachment makes sure that the files of the given attachment are in storage, dereferencing
+// them again if they were pruned, and updates the time that the attachment was last accessed.
+func (p *processor) touchAttachment(ctx context.Context, a *gtsmodel.MediaAttachment) error {
+	if !a.Cached && a.RemoteURL != "" {
+		// use the instance account to fetch the media, since we don't know who's asking for it
+		t, err := p.federator.GetTransportForUser(ctx, "")
+		if err != nil {
+			return fmt.Errorf("error getting transport to recache attachment %s: %s", a.ID, err)
+		}
+		if err := p.mediaHandler.RecacheRemoteMedia(ctx, t, a); err != nil {
+			return fmt.Errorf("error recaching attachment %s: %s", a.ID, err)
+		}
+		return nil
+	}
+
+	if time.Since(a.LastAccessedAt) < media.LastAccessedInterval {
+		return nil
+	}
+	if err := p.db.UpdateOneByID(ctx, a.ID, "last_accessed_at", time.Now(), a); err != nil {
+		// serving the file is more important than keeping it cached for longer
+		p.log.Errorf("touchAttachment: error updating last accessed time of attachment %s: %s", a.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/blob"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+const testRemoteMediaURL = "http://fossbros-anonymous.io/attachments/original/13bbc3f8-2b5e-46ea-9531-40b4974d9912.jpeg"
+
+type MediaCacheTestSuite struct {
+	suite.Suite
+	db           db.DB
+	storage      blob.Storage
+	mediaHandler media.Handler
+	processor    processing.Processor
+	testAccounts map[string]*gtsmodel.Account
+	remoteImage  []byte
+	fetched      int32
+}
+
+// failingStorage fails to remove the file at failPath, and otherwise acts like the storage it wraps.
+type failingStorage struct {
+	blob.Storage
+	failPath string
+}
+
+func (s *failingStorage) RemoveFileAt(path string) error {
+	if path == s.failPath {
+		return errors.New("storage is having a bad day")
+	}
+	return s.Storage.RemoveFileAt(path)
+}
+
+func (suite *MediaCacheTestSuite) SetupTest() {
+	suite.db = testrig.NewTestDB()
+	testrig.StandardDBSetup(suite.db)
+	suite.storage = testrig.NewTestStorage()
+	suite.mediaHandler = testrig.NewTestMediaHandler(suite.db, suite.storage)
+	suite.testAccounts = testrig.NewTestAccounts()
+	suite.fetched = 0
+
+	b, err := ioutil.ReadFile("../../testrig/media/test-jpeg.jpg")
+	suite.Require().NoError(err)
+	suite.remoteImage = b
+
+	httpClient := testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() != testRemoteMediaURL {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+			}, nil
+		}
+		atomic.AddInt32(&suite.fetched, 1)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(suite.remoteImage)),
+		}, nil
+	})
+	federator := testrig.NewTestFederator(suite.db, testrig.NewTestTransportController(httpClient), suite.storage)
+	suite.processor = testrig.NewTestProcessor(suite.db, suite.storage, federator)
+}
+
+func (suite *MediaCacheTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+// remoteAttachment stores the remote test image as an attachment of remote_account_1, last accessed at the given time.
+func (suite *MediaCacheTestSuite) remoteAttachment(lastAccessedAt time.Time) *gtsmodel.MediaAttachment {
+	attachment, err := suite.mediaHandler.ProcessAttachment(suite.remoteImage, suite.testAccounts["remote_account_1"].ID, testRemoteMediaURL)
+	suite.Require().NoError(err)
+	suite.True(attachment.Cached)
+
+	attachment.LastAccessedAt = lastAccessedAt
+	suite.Require().NoError(suite.db.Put(context.Background(), attachment))
+	return attachment
+}
+
+func (suite *MediaCacheTestSuite) getAttachment(id string) *gtsmodel.MediaAttachment {
+	attachment := &gtsmodel.MediaAttachment{}
+	suite.Require().NoError(suite.db.GetByID(context.Background(), id, attachment))
+	return attachment
+}
+
+func (suite *MediaCacheTestSuite) TestPruneRemoteMedia() {
+	ctx := context.Background()
+	old := suite.remoteAttachment(time.Now().Add(-60 * 24 * time.Hour))
+	recent := suite.remoteAttachment(time.Now().Add(-1 * time.Hour))
+
+	pruned, err := suite.mediaHandler.PruneRemoteMedia(ctx, time.Now().Add(-30*24*time.Hour))
+	suite.NoError(err)
+	suite.Equal(1, pruned)
+
+	// the old attachment should be gone from storage but still in the db
+	dbOld := suite.getAttachment(old.ID)
+	suite.False(dbOld.Cached)
+	_, err = suite.storage.RetrieveFileFrom(old.File.Path)
+	suite.Error(err)
+	_, err = suite.storage.RetrieveFileFrom(old.Thumbnail.Path)
+	suite.Error(err)
+
+	// the recent one should be untouched
+	dbRecent := suite.getAttachment(recent.ID)
+	suite.True(dbRecent.Cached)
+	_, err = suite.storage.RetrieveFileFrom(recent.File.Path)
+	suite.NoError(err)
+
+	// local media is never pruned, however old it is
+	for _, a := range testrig.NewTestAttachments() {
+		suite.True(suite.getAttachment(a.ID).Cached)
+	}
+
+	// pruning again shouldn't find anything else to do
+	pruned, err = suite.mediaHandler.PruneRemoteMedia(ctx, time.Now().Add(-30*24*time.Hour))
+	suite.NoError(err)
+	suite.Zero(pruned)
+}
+
+func (suite *MediaCacheTestSuite) TestPruneRemoteMediaSkipsFailures() {
+	ctx := context.Background()
+	bad := suite.remoteAttachment(time.Now().Add(-60 * 24 * time.Hour))
+	good1 := suite.remoteAttachment(time.Now().Add(-60 * 24 * time.Hour))
+	good2 := suite.remoteAttachment(time.Now().Add(-60 * 24 * time.Hour))
+
+	mediaHandler := testrig.NewTestMediaHandler(suite.db, &failingStorage{Storage: suite.storage, failPath: bad.File.Path})
+
+	// the attachment that can't be removed shouldn't stop the others from being pruned
+	pruned, err := mediaHandler.PruneRemoteMedia(ctx, time.Now().Add(-30*24*time.Hour))
+	suite.NoError(err)
+	suite.Equal(2, pruned)
+	suite.True(suite.getAttachment(bad.ID).Cached)
+	suite.False(suite.getAttachment(good1.ID).Cached)
+	suite.False(suite.getAttachment(good2.ID).Cached)
+
+	// and it should be tried again next time
+	pruned, err = mediaHandler.PruneRemoteMedia(ctx, time.Now().Add(-30*24*time.Hour))
+	suite.NoError(err)
+	suite.Zero(pruned)
+	pruned, err = suite.mediaHandler.PruneRemoteMedia(ctx, time.Now().Add(-30*24*time.Hour))
+	suite.NoError(err)
+	suite.Equal(1, pruned)
+}
+
+func (suite *MediaCacheTestSuite) TestGetPrunedFileConcurrently() {
+	ctx := context.Background()
+	attachment := suite.remoteAttachment(time.Now().Add(-60 * 24 * time.Hour))
+
+	_, err := suite.mediaHandler.PruneRemoteMedia(ctx, time.Now().Add(-30*24*time.Hour))
+	suite.Require().NoError(err)
+
+	// lots of requests for the pruned file at once should only fetch it from the remote instance once
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			content, err := suite.processor.FileGet(ctx, &oauth.Auth{}, &apimodel.GetContentRequestForm{
+				AccountID: attachment.AccountID,
+				MediaType: string(media.Attachment),
+				MediaSize: string(media.Original),
+				FileName:  attachment.ID + ".jpeg",
+			})
+			if suite.NoError(err) {
+				content.Content.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	suite.EqualValues(1, atomic.LoadInt32(&suite.fetched))
+	suite.True(suite.getAttachment(attachment.ID).Cached)
+}
+
+func (suite *MediaCacheTestSuite) TestGetPrunedFile() {
+	ctx := context.Background()
+	attachment := suite.remoteAttachment(time.Now().Add(-60 * 24 * time.Hour))
+
+	original, err := suite.storage.RetrieveFileFrom(attachment.File.Path)
+	suite.Require().NoError(err)
+
+	_, err = suite.mediaHandler.PruneRemoteMedia(ctx, time.Now().Add(-30*24*time.Hour))
+	suite.Require().NoError(err)
+
+	content, err := suite.processor.FileGet(ctx, &oauth.Auth{}, &apimodel.GetContentRequestForm{
+		AccountID: attachment.AccountID,
+		MediaType: string(media.Attachment),
+		MediaSize: string(media.Original),
+		FileName:  attachment.ID + ".jpeg",
+	})
+	suite.Require().NoError(err)
+	defer content.Content.Close()
+	suite.EqualValues(1, atomic.LoadInt32(&suite.fetched))
+
+	// the file should have been fetched again and stored at the same path
+	served, err := ioutil.ReadAll(content.Content)
+	suite.NoError(err)
+	suite.Equal(original, served)
+	suite.Equal(attachment.File.ContentType, content.ContentType)
+
+	_, err = suite.storage.RetrieveFileFrom(attachment.Thumbnail.Path)
+	suite.NoError(err)
+
+	dbAttachment := suite.getAttachment(attachment.ID)
+	suite.True(dbAttachment.Cached)
+	suite.WithinDuration(time.Now(), dbAttachment.LastAccessedAt, time.Minute)
+	suite.Equal(attachment.File.Path, dbAttachment.File.Path)
+	suite.Equal(attachment.Thumbnail.Path, dbAttachment.Thumbnail.Path)
+
+	// getting it again should just serve it from storage
+	content, err = suite.processor.FileGet(ctx, &oauth.Auth{}, &apimodel.GetContentRequestForm{
+		AccountID: attachment.AccountID,
+		MediaType: string(media.Attachment),
+		MediaSize: string(media.Small),
+		FileName:  attachment.ID + ".jpeg",
+	})
+	suite.Require().NoError(err)
+	content.Content.Close()
+	suite.EqualValues(1, atomic.LoadInt32(&suite.fetched))
+}
+
+func TestMediaCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(MediaCacheTestSuite))
+}
@@ -0,0 +1,178 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type MuteTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func accountIDs(accounts []*apimodel.Account) []string {
+	ids := []string{}
+	for _, a := range accounts {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func (suite *MuteTestSuite) TestMuteTimelines() {
+	ctx := context.Background()
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+	targetAccount := suite.testAccounts["local_account_2"]
+	mutedStatusID := suite.testStatuses["local_account_2_status_2"].ID
+
+	// starting the processor indexes the home timelines
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+
+	resp, errWithCode := suite.processor.HomeTimelineGet(ctx, authed, "", "", "", 20, false)
+	suite.Nil(errWithCode)
+	suite.Contains(statusIDs(resp.Statuses), mutedStatusID)
+
+	relationship, errWithCode := suite.processor.AccountMuteCreate(ctx, authed, &apimodel.AccountMuteRequest{TargetAccountID: targetAccount.ID})
+	suite.Nil(errWithCode)
+	suite.True(relationship.Muting)
+	suite.True(relationship.MutingNotifications)
+
+	// the muted account's statuses should be gone from both the home and the public timeline
+	resp, errWithCode = suite.processor.HomeTimelineGet(ctx, authed, "", "", "", 20, false)
+	suite.Nil(errWithCode)
+	suite.NotContains(statusIDs(resp.Statuses), mutedStatusID)
+
+	resp, errWithCode = suite.processor.PublicTimelineGet(ctx, authed, "", "", "", 20, false)
+	suite.Nil(errWithCode)
+	suite.NotContains(statusIDs(resp.Statuses), mutedStatusID)
+
+	mutes, errWithCode := suite.processor.MutesGet(ctx, authed, "", "", 20)
+	suite.Nil(errWithCode)
+	suite.Equal([]string{targetAccount.ID}, accountIDs(mutes.Accounts))
+	suite.Contains(mutes.LinkHeader, "api/v1/mutes")
+
+	relationship, errWithCode = suite.processor.AccountMuteRemove(ctx, authed, targetAccount.ID)
+	suite.Nil(errWithCode)
+	suite.False(relationship.Muting)
+	suite.False(relationship.MutingNotifications)
+
+	resp, errWithCode = suite.processor.PublicTimelineGet(ctx, authed, "", "", "", 20, false)
+	suite.Nil(errWithCode)
+	suite.Contains(statusIDs(resp.Statuses), mutedStatusID)
+
+	mutes, errWithCode = suite.processor.MutesGet(ctx, authed, "", "", 20)
+	suite.Nil(errWithCode)
+	suite.Empty(mutes.Accounts)
+	suite.Empty(mutes.LinkHeader)
+}
+
+func (suite *MuteTestSuite) TestMuteNotifications() {
+	ctx := context.Background()
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+	targetAccount := suite.testAccounts["admin_account"]
+
+	notificationAccountIDs := func() []string {
+		notifs, errWithCode := suite.processor.NotificationsGet(ctx, authed, 20, "", "")
+		suite.Nil(errWithCode)
+		ids := []string{}
+		for _, n := range notifs {
+			ids = append(ids, n.Account.ID)
+		}
+		return ids
+	}
+	suite.Contains(notificationAccountIDs(), targetAccount.ID)
+
+	// muting without notifications should leave notifications alone
+	notifications := false
+	relationship, errWithCode := suite.processor.AccountMuteCreate(ctx, authed, &apimodel.AccountMuteRequest{TargetAccountID: targetAccount.ID, Notifications: &notifications})
+	suite.Nil(errWithCode)
+	suite.True(relationship.Muting)
+	suite.False(relationship.MutingNotifications)
+	suite.Contains(notificationAccountIDs(), targetAccount.ID)
+
+	// muting again updates the existing mute
+	notifications = true
+	relationship, errWithCode = suite.processor.AccountMuteCreate(ctx, authed, &apimodel.AccountMuteRequest{TargetAccountID: targetAccount.ID, Notifications: &notifications})
+	suite.Nil(errWithCode)
+	suite.True(relationship.Muting)
+	suite.True(relationship.MutingNotifications)
+	suite.NotContains(notificationAccountIDs(), targetAccount.ID)
+}
+
+func (suite *MuteTestSuite) TestMuteExpiry() {
+	ctx := context.Background()
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+	targetAccount := suite.testAccounts["local_account_2"]
+
+	relationship, errWithCode := suite.processor.AccountMuteCreate(ctx, authed, &apimodel.AccountMuteRequest{TargetAccountID: targetAccount.ID, Duration: 3600})
+	suite.Nil(errWithCode)
+	suite.True(relationship.Muting)
+
+	mute := &gtsmodel.Mute{}
+	suite.NoError(suite.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: authed.Account.ID}, {Key: "target_account_id", Value: targetAccount.ID}}, mute))
+	suite.WithinDuration(time.Now().Add(time.Hour), mute.ExpiresAt, time.Minute)
+
+	// pretend the mute ran out a while ago
+	mute.ExpiresAt = time.Now().Add(-time.Minute)
+	suite.NoError(suite.db.UpdateByID(ctx, mute.ID, mute))
+
+	// the mute shouldn't apply any more, even before it's cleaned up
+	relationship, errWithCode = suite.processor.AccountRelationshipGet(ctx, authed, targetAccount.ID)
+	suite.Nil(errWithCode)
+	suite.False(relationship.Muting)
+
+	mutes, errWithCode := suite.processor.MutesGet(ctx, authed, "", "", 20)
+	suite.Nil(errWithCode)
+	suite.Empty(mutes.Accounts)
+
+	// starting the processor cleans up expired mutes
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+
+	suite.Eventually(func() bool {
+		err := suite.db.GetByID(ctx, mute.ID, &gtsmodel.Mute{})
+		_, ok := err.(db.ErrNoEntries)
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (suite *MuteTestSuite) TestMuteSelf() {
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+
+	_, errWithCode := suite.processor.AccountMuteCreate(context.Background(), authed, &apimodel.AccountMuteRequest{TargetAccountID: authed.Account.ID})
+	suite.NotNil(errWithCode)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+}
+
+func TestMuteTestSuite(t *testing.T) {
+	suite.Run(t, new(MuteTestSuite))
+}
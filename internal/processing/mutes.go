@@ -0,0 +1,136 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// muteCleanupInterval is how often mutes that have expired are removed from the database.
+const muteCleanupInterval = 5 * time.Minute
+
+// muteCleanupBatch is the maximum number of expired mutes that will be removed in one go.
+const muteCleanupBatch = 100
+
+func (p *processor) MutesGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.MutesResponse, gtserror.WithCode) {
+	accounts, nextMaxID, prevMinID, err := p.db.GetMutesForAccount(ctx, authed.Account.ID, maxID, sinceID, limit)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			// there are just no entries
+			return &apimodel.MutesResponse{
+				Accounts: []*apimodel.Account{},
+			}, nil
+		}
+		// there's an actual error
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiAccounts := []*apimodel.Account{}
+	for _, a := range accounts {
+		apiAccount, err := p.tc.AccountToMastoPublic(ctx, a)
+		if err != nil {
+			continue
+		}
+		apiAccounts = append(apiAccounts, apiAccount)
+	}
+
+	return p.packageMutesResponse(apiAccounts, "/api/v1/mutes", nextMaxID, prevMinID, limit)
+}
+
+func (p *processor) packageMutesResponse(accounts []*apimodel.Account, path string, nextMaxID string, prevMinID string, limit int) (*apimodel.MutesResponse, gtserror.WithCode) {
+	resp := &apimodel.MutesResponse{
+		Accounts: accounts,
+	}
+
+	// prepare the next and previous links
+	if len(accounts) != 0 {
+		nextLink := &url.URL{
+			Scheme:   p.config.Protocol,
+			Host:     p.config.Host,
+			Path:     path,
+			RawQuery: fmt.Sprintf("limit=%d&max_id=%s", limit, nextMaxID),
+		}
+		next := fmt.Sprintf("<%s>; rel=\"next\"", nextLink.String())
+
+		prevLink := &url.URL{
+			Scheme:   p.config.Protocol,
+			Host:     p.config.Host,
+			Path:     path,
+			RawQuery: fmt.Sprintf("limit=%d&min_id=%s", limit, prevMinID),
+		}
+		prev := fmt.Sprintf("<%s>; rel=\"prev\"", prevLink.String())
+		resp.LinkHeader = fmt.Sprintf("%s, %s", next, prev)
+	}
+
+	return resp, nil
+}
+
+// cleanupMutes periodically removes mutes that have passed their expiry time, until the processor is stopped.
+//
+// Filters already ignore expired mutes, so this just stops them from piling up in the database.
+func (p *processor) cleanupMutes(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(muteCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		p.removeExpiredMutes(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// removeExpiredMutes removes all mutes that have passed their expiry time.
+func (p *processor) removeExpiredMutes(ctx context.Context) {
+	for {
+		mutes, err := p.db.GetExpiredMutes(ctx, muteCleanupBatch)
+		if err != nil {
+			if _, ok := err.(db.ErrNoEntries); !ok {
+				p.log.Errorf("removeExpiredMutes: error getting expired mutes from the db: %s", err)
+			}
+			return
+		}
+
+		for _, mute := range mutes {
+			if err := p.db.DeleteByID(ctx, mute.ID, &gtsmodel.Mute{}); err != nil {
+				// the mute would just be picked up again, so stop here rather than going round in circles
+				p.log.Errorf("removeExpiredMutes: error removing mute %s: %s", mute.ID, err)
+				return
+			}
+		}
+
+		if len(mutes) < muteCleanupBatch {
+			return
+		}
+	}
+}
@@ -19,22 +19,56 @@
 package processing
 
 import (
+	"context"
+
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
-func (p *processor) NotificationsGet(authed *oauth.Auth, limit int, maxID string, sinceID string) ([]*apimodel.Notification, gtserror.WithCode) {
+func (p *processor) NotificationsGet(ctx context.Context, authed *oauth.Auth, limit int, maxID string, sinceID string) ([]*apimodel.Notification, gtserror.WithCode) {
 	l := p.log.WithField("func", "NotificationsGet")
 
-	notifs, err := p.db.GetNotificationsForAccount(authed.Account.ID, limit, maxID, sinceID)
+	notifs, err := p.db.GetNotificationsForAccount(ctx, authed.Account.ID, limit, maxID, sinceID)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	filters, err := p.filter.KeywordFilters(ctx, authed.Account, gtsmodel.FilterContextNotifications)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
 	mastoNotifs := []*apimodel.Notification{}
 	for _, n := range notifs {
-		mastoNotif, err := p.tc.NotificationToMasto(n)
+		// don't return notifications caused by accounts that the account has muted notifications from
+		muted, err := p.filter.AccountMuted(ctx, n.OriginAccountID, authed.Account, true)
+		if err != nil {
+			l.Debugf("got an error checking mutes for a notification, will skip it: %s", err)
+			continue
+		}
+		if muted {
+			continue
+		}
+
+		if n.StatusID != "" {
+			// don't return notifications about statuses that the account has filtered out
+			s := &gtsmodel.Status{}
+			if err := p.db.GetByID(ctx, n.StatusID, s); err == nil {
+				n.GTSStatus = s
+				filtered, err := p.filter.StatusKeywordFiltered(ctx, s, filters)
+				if err != nil {
+					l.Debugf("got an error checking keyword filters for a notification, will skip it: %s", err)
+					continue
+				}
+				if filtered {
+					continue
+				}
+			}
+		}
+
+		mastoNotif, err := p.tc.NotificationToMasto(ctx, n)
 		if err != nil {
 			l.Debugf("got an error converting a notification to masto, will skip it: %s", err)
 			continue
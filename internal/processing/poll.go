@@ -0,0 +1,364 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+const (
+	// pollCloseInterval is how often polls that have passed their expiry time are looked for, so they can be closed.
+	pollCloseInterval = time.Minute
+	// pollCloseBatch is the maximum number of polls that will be closed in one go.
+	pollCloseBatch = 100
+)
+
+func (p *processor) PollGet(ctx context.Context, authed *oauth.Auth, pollID string) (*apimodel.Poll, gtserror.WithCode) {
+	poll, _, errWithCode := p.getVisiblePoll(ctx, authed, pollID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	mastoPoll, err := p.tc.PollToMasto(ctx, poll, authed.Account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("PollGet: error converting poll to masto: %s", err))
+	}
+
+	return mastoPoll, nil
+}
+
+func (p *processor) PollVote(ctx context.Context, authed *oauth.Auth, pollID string, choices []int) (*apimodel.Poll, gtserror.WithCode) {
+	poll, _, errWithCode := p.getVisiblePoll(ctx, authed, pollID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if poll.AccountID == authed.Account.ID {
+		err := errors.New("you can't vote in your own poll")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if poll.Expired(time.Now()) {
+		err := errors.New("this poll has already closed")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if err := validatePollChoices(poll, choices); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	existing := []*gtsmodel.PollVote{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "poll_id", Value: poll.ID}, {Key: "account_id", Value: authed.Account.ID}}, &existing); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("PollVote: error checking existing votes: %s", err))
+		}
+	}
+	if len(existing) != 0 {
+		err := errors.New("you have already voted in this poll")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	pollOwner := &gtsmodel.Account{}
+	if err := p.db.GetByID(ctx, poll.AccountID, pollOwner); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("PollVote: error getting poll owner: %s", err))
+	}
+
+	votes := []*gtsmodel.PollVote{}
+	for _, choice := range choices {
+		voteID, err := id.NewULID()
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		vote := &gtsmodel.PollVote{
+			ID:        voteID,
+			CreatedAt: time.Now(),
+			PollID:    poll.ID,
+			AccountID: authed.Account.ID,
+			Choice:    choice,
+			URI:       util.GenerateURIForPollVote(authed.Account.Username, p.config.Protocol, p.config.Host, voteID),
+		}
+
+		if err := p.db.Put(ctx, vote); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("PollVote: error putting vote in db: %s", err))
+		}
+		votes = append(votes, vote)
+	}
+
+	if pollOwner.Domain == "" {
+		// the poll is ours, so we can count the votes and let everyone know about the new tallies
+		status, err := p.recountPoll(ctx, poll.ID)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("PollVote: error recounting poll: %s", err))
+		}
+		poll = status.GTSPoll
+
+		p.fromClientAPI <- gtsmodel.FromClientAPI{
+			APObjectType:   gtsmodel.ActivityStreamsQuestion,
+			APActivityType: gtsmodel.ActivityStreamsUpdate,
+			GTSModel:       status,
+			OriginAccount:  pollOwner,
+		}
+	} else {
+		// the poll is remote, so the owner will do the counting, but we update our copy of the
+		// tallies in the meantime so the vote shows up straight away
+		for _, vote := range votes {
+			if vote.Choice < len(poll.Votes) {
+				poll.Votes[vote.Choice] = poll.Votes[vote.Choice] + 1
+			}
+		}
+		poll.VotersCount = poll.VotersCount + 1
+		poll.UpdatedAt = time.Now()
+		if err := p.db.UpdateByID(ctx, poll.ID, poll); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("PollVote: error updating poll: %s", err))
+		}
+
+		for _, vote := range votes {
+			p.fromClientAPI <- gtsmodel.FromClientAPI{
+				APObjectType:   gtsmodel.ActivityStreamsNote,
+				APActivityType: gtsmodel.ActivityStreamsCreate,
+				GTSModel:       vote,
+				OriginAccount:  authed.Account,
+				TargetAccount:  pollOwner,
+			}
+		}
+	}
+
+	mastoPoll, err := p.tc.PollToMasto(ctx, poll, authed.Account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("PollVote: error converting poll to masto: %s", err))
+	}
+
+	return mastoPoll, nil
+}
+
+// getVisiblePoll gets the poll with the given ID and the status it's attached to,
+// returning a not found error if the poll doesn't exist or its status isn't visible to the requester.
+func (p *processor) getVisiblePoll(ctx context.Context, authed *oauth.Auth, pollID string) (*gtsmodel.Poll, *gtsmodel.Status, gtserror.WithCode) {
+	poll := &gtsmodel.Poll{}
+	if err := p.db.GetByID(ctx, pollID, poll); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil, nil, gtserror.NewErrorNotFound(fmt.Errorf("poll %s not found", pollID))
+		}
+		return nil, nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting poll %s: %s", pollID, err))
+	}
+
+	status := &gtsmodel.Status{}
+	if err := p.db.GetByID(ctx, poll.StatusID, status); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil, nil, gtserror.NewErrorNotFound(fmt.Errorf("status %s of poll %s not found", poll.StatusID, pollID))
+		}
+		return nil, nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting status %s: %s", poll.StatusID, err))
+	}
+
+	visible, err := p.filter.StatusVisible(ctx, status, authed.Account)
+	if err != nil {
+		return nil, nil, gtserror.NewErrorInternalError(fmt.Errorf("error checking visibility of status %s: %s", status.ID, err))
+	}
+	if !visible {
+		return nil, nil, gtserror.NewErrorNotFound(fmt.Errorf("status %s of poll %s is not visible", status.ID, pollID))
+	}
+
+	status.GTSPoll = poll
+	return poll, status, nil
+}
+
+// validatePollChoices checks that the given choices are a valid vote in the given poll.
+func validatePollChoices(poll *gtsmodel.Poll, choices []int) error {
+	if len(choices) == 0 {
+		return errors.New("at least one choice must be given")
+	}
+
+	if len(choices) > 1 && !poll.Multiple {
+		return errors.New("only one choice can be given in this poll")
+	}
+
+	chosen := map[int]bool{}
+	for _, choice := range choices {
+		if choice < 0 || choice >= len(poll.Options) {
+			return fmt.Errorf("choice %d is not an option in this poll", choice)
+		}
+		if chosen[choice] {
+			return fmt.Errorf("choice %d was given more than once", choice)
+		}
+		chosen[choice] = true
+	}
+
+	return nil
+}
+
+// recountPoll updates the tallies of the poll with the given ID from the votes that are stored for it,
+// and returns the status the poll is attached to, with the updated poll set on it.
+func (p *processor) recountPoll(ctx context.Context, pollID string) (*gtsmodel.Status, error) {
+	poll := &gtsmodel.Poll{}
+	if err := p.db.GetByID(ctx, pollID, poll); err != nil {
+		return nil, fmt.Errorf("recountPoll: error getting poll: %s", err)
+	}
+
+	votes := []*gtsmodel.PollVote{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "poll_id", Value: poll.ID}}, &votes); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, fmt.Errorf("recountPoll: error getting votes: %s", err)
+		}
+	}
+
+	tallies := make([]int, len(poll.Options))
+	voters := map[string]bool{}
+	for _, vote := range votes {
+		if vote.Choice < 0 || vote.Choice >= len(tallies) {
+			continue
+		}
+		tallies[vote.Choice] = tallies[vote.Choice] + 1
+		voters[vote.AccountID] = true
+	}
+
+	poll.Votes = tallies
+	poll.VotersCount = len(voters)
+	poll.UpdatedAt = time.Now()
+	if err := p.db.UpdateByID(ctx, poll.ID, poll); err != nil {
+		return nil, fmt.Errorf("recountPoll: error updating poll: %s", err)
+	}
+
+	status := &gtsmodel.Status{}
+	if err := p.db.GetByID(ctx, poll.StatusID, status); err != nil {
+		return nil, fmt.Errorf("recountPoll: error getting status: %s", err)
+	}
+	status.GTSPoll = poll
+
+	return status, nil
+}
+
+// deletePoll deletes the poll attached to the given status, if there is one, along with all the votes in it.
+func (p *processor) deletePoll(ctx context.Context, status *gtsmodel.Status) error {
+	if status.ActivityStreamsType != gtsmodel.ActivityStreamsQuestion {
+		return nil
+	}
+
+	poll := &gtsmodel.Poll{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: status.ID}}, poll); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil
+		}
+		return fmt.Errorf("deletePoll: error getting poll of status %s: %s", status.ID, err)
+	}
+
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "poll_id", Value: poll.ID}}, &[]*gtsmodel.PollVote{}); err != nil {
+		return fmt.Errorf("deletePoll: error deleting votes in poll %s: %s", poll.ID, err)
+	}
+
+	if err := p.db.DeleteByID(ctx, poll.ID, &gtsmodel.Poll{}); err != nil {
+		return fmt.Errorf("deletePoll: error deleting poll %s: %s", poll.ID, err)
+	}
+
+	return nil
+}
+
+// closePolls periodically closes polls that have passed their expiry time, until the processor is stopped.
+//
+// Polls that expired while the processor wasn't running are closed straight away.
+func (p *processor) closePolls(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(pollCloseInterval)
+	defer ticker.Stop()
+
+	for {
+		p.closeExpiredPolls(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// closeExpiredPolls closes all polls that have passed their expiry time but haven't been closed yet.
+func (p *processor) closeExpiredPolls(ctx context.Context) {
+	sinceID := ""
+	for {
+		polls, err := p.db.GetExpiredPolls(ctx, sinceID, pollCloseBatch)
+		if err != nil {
+			if _, ok := err.(db.ErrNoEntries); !ok {
+				p.log.Errorf("closeExpiredPolls: error getting expired polls from the db: %s", err)
+			}
+			return
+		}
+
+		for _, poll := range polls {
+			if err := p.closePoll(ctx, poll); err != nil {
+				// a poll that was left open will be tried again next time, so just log and page past it
+				p.log.Errorf("closeExpiredPolls: error closing poll %s: %s", poll.ID, err)
+			}
+		}
+		sinceID = polls[len(polls)-1].ID
+
+		if len(polls) < pollCloseBatch {
+			return
+		}
+	}
+}
+
+// closePoll marks the given poll as closed, notifies local accounts that voted in it or created it,
+// and lets everyone know about the final tallies if it's a local poll.
+func (p *processor) closePoll(ctx context.Context, poll *gtsmodel.Poll) error {
+	poll.ClosedAt = time.Now()
+	poll.UpdatedAt = poll.ClosedAt
+	if err := p.db.UpdateByID(ctx, poll.ID, poll); err != nil {
+		return fmt.Errorf("closePoll: error updating poll: %s", err)
+	}
+
+	status := &gtsmodel.Status{}
+	if err := p.db.GetByID(ctx, poll.StatusID, status); err != nil {
+		return fmt.Errorf("closePoll: error getting status: %s", err)
+	}
+	status.GTSPoll = poll
+
+	if err := p.notifyPoll(ctx, poll, status); err != nil {
+		return err
+	}
+
+	pollOwner := &gtsmodel.Account{}
+	if err := p.db.GetByID(ctx, poll.AccountID, pollOwner); err != nil {
+		return fmt.Errorf("closePoll: error getting poll owner: %s", err)
+	}
+	status.GTSAuthorAccount = pollOwner
+
+	if pollOwner.Domain == "" {
+		p.fromClientAPI <- gtsmodel.FromClientAPI{
+			APObjectType:   gtsmodel.ActivityStreamsQuestion,
+			APActivityType: gtsmodel.ActivityStreamsUpdate,
+			GTSModel:       status,
+			OriginAccount:  pollOwner,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type PollTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+// createPoll creates a public status with a poll by local_account_1, returning the poll.
+func (suite *PollTestSuite) createPoll(multiple bool) *apimodel.Poll {
+	authed := &oauth.Auth{
+		Account:     suite.testAccounts["local_account_1"],
+		Application: suite.testApplications["application_1"],
+	}
+
+	status, err := suite.processor.StatusCreate(context.Background(), authed, &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status: "which is the best animal?",
+			Poll: &apimodel.PollRequest{
+				Options:   []string{"cats", "dogs", "turtles"},
+				ExpiresIn: 3600,
+				Multiple:  multiple,
+			},
+			Visibility: apimodel.VisibilityPublic,
+		},
+	})
+	suite.NoError(err)
+	suite.NotNil(status.Poll)
+	return status.Poll
+}
+
+func (suite *PollTestSuite) TestCreatePoll() {
+	poll := suite.createPoll(false)
+	suite.NotEmpty(poll.ID)
+	suite.False(poll.Expired)
+	suite.NotEmpty(poll.ExpiresAt)
+	suite.Len(poll.Options, 3)
+	suite.Equal("turtles", poll.Options[2].Title)
+	suite.Equal(0, poll.VotesCount)
+
+	dbPoll := &gtsmodel.Poll{}
+	suite.NoError(suite.db.GetByID(context.Background(), poll.ID, dbPoll))
+	dbStatus := &gtsmodel.Status{}
+	suite.NoError(suite.db.GetByID(context.Background(), dbPoll.StatusID, dbStatus))
+	suite.Equal(gtsmodel.ActivityStreamsQuestion, dbStatus.ActivityStreamsType)
+}
+
+func (suite *PollTestSuite) TestCreatePollInvalidOptions() {
+	authed := &oauth.Auth{
+		Account:     suite.testAccounts["local_account_1"],
+		Application: suite.testApplications["application_1"],
+	}
+
+	for _, options := range [][]string{
+		{"cats", "<p></p>"},
+		{"cats", "   "},
+		{"cats", "dogs", "cats"},
+		{"cats", "<b>cats</b>"},
+	} {
+		_, err := suite.processor.StatusCreate(context.Background(), authed, &apimodel.AdvancedStatusCreateForm{
+			StatusCreateRequest: apimodel.StatusCreateRequest{
+				Status: "which is the best animal?",
+				Poll: &apimodel.PollRequest{
+					Options:   options,
+					ExpiresIn: 3600,
+				},
+				Visibility: apimodel.VisibilityPublic,
+			},
+		})
+		errWithCode, ok := err.(gtserror.WithCode)
+		if suite.True(ok, "options %v", options) {
+			suite.Equal(400, errWithCode.Code())
+		}
+	}
+}
+
+func (suite *PollTestSuite) TestVote() {
+	ctx := context.Background()
+	poll := suite.createPoll(false)
+	voter := &oauth.Auth{Account: suite.testAccounts["local_account_2"]}
+
+	voted, errWithCode := suite.processor.PollVote(ctx, voter, poll.ID, []int{2})
+	suite.Nil(errWithCode)
+	suite.True(voted.Voted)
+	suite.Equal([]int{2}, voted.OwnVotes)
+	suite.Equal(1, voted.VotesCount)
+	suite.Equal(1, voted.Options[2].VotesCount)
+
+	// the vote should be visible to other accounts too
+	got, errWithCode := suite.processor.PollGet(ctx, &oauth.Auth{Account: suite.testAccounts["admin_account"]}, poll.ID)
+	suite.Nil(errWithCode)
+	suite.False(got.Voted)
+	suite.Equal(1, got.VotesCount)
+
+	// voting twice isn't allowed
+	_, errWithCode = suite.processor.PollVote(ctx, voter, poll.ID, []int{1})
+	suite.NotNil(errWithCode)
+	suite.Equal(400, errWithCode.Code())
+}
+
+func (suite *PollTestSuite) TestVoteInvalid() {
+	ctx := context.Background()
+	poll := suite.createPoll(false)
+	voter := &oauth.Auth{Account: suite.testAccounts["local_account_2"]}
+
+	for _, choices := range [][]int{{}, {3}, {-1}, {0, 1}} {
+		_, errWithCode := suite.processor.PollVote(ctx, voter, poll.ID, choices)
+		suite.NotNil(errWithCode, "choices %v", choices)
+		suite.Equal(400, errWithCode.Code())
+	}
+
+	// the author can't vote in their own poll
+	_, errWithCode := suite.processor.PollVote(ctx, &oauth.Auth{Account: suite.testAccounts["local_account_1"]}, poll.ID, []int{0})
+	suite.NotNil(errWithCode)
+	suite.Equal(400, errWithCode.Code())
+}
+
+func (suite *PollTestSuite) TestVoteMultiple() {
+	ctx := context.Background()
+	poll := suite.createPoll(true)
+
+	voted, errWithCode := suite.processor.PollVote(ctx, &oauth.Auth{Account: suite.testAccounts["local_account_2"]}, poll.ID, []int{0, 2})
+	suite.Nil(errWithCode)
+	suite.Equal([]int{0, 2}, voted.OwnVotes)
+	suite.Equal(2, voted.VotesCount)
+	suite.Equal(1, voted.VotersCount)
+
+	// the same choice can't be given twice
+	_, errWithCode = suite.processor.PollVote(ctx, &oauth.Auth{Account: suite.testAccounts["admin_account"]}, poll.ID, []int{1, 1})
+	suite.NotNil(errWithCode)
+	suite.Equal(400, errWithCode.Code())
+}
+
+func (suite *PollTestSuite) TestCloseExpiredPoll() {
+	ctx := context.Background()
+	poll := suite.createPoll(false)
+
+	_, errWithCode := suite.processor.PollVote(ctx, &oauth.Auth{Account: suite.testAccounts["local_account_2"]}, poll.ID, []int{0})
+	suite.Nil(errWithCode)
+
+	// pretend the poll expired a while ago
+	dbPoll := &gtsmodel.Poll{}
+	suite.NoError(suite.db.GetByID(ctx, poll.ID, dbPoll))
+	dbPoll.ExpiresAt = time.Now().Add(-time.Minute)
+	suite.NoError(suite.db.UpdateByID(ctx, dbPoll.ID, dbPoll))
+
+	// starting the processor closes polls that expired in the meantime
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+
+	// both the voter and the author should be notified once the poll is closed
+	for _, accountName := range []string{"local_account_1", "local_account_2"} {
+		targetAccountID := suite.testAccounts[accountName].ID
+		suite.Eventually(func() bool {
+			notifs := []*gtsmodel.Notification{}
+			if err := suite.db.GetWhere(ctx, []db.Where{
+				{Key: "notification_type", Value: gtsmodel.NotificationPoll},
+				{Key: "target_account_id", Value: targetAccountID},
+			}, &notifs); err != nil {
+				return false
+			}
+			return len(notifs) == 1
+		}, 5*time.Second, 10*time.Millisecond, accountName)
+	}
+
+	closed, errWithCode := suite.processor.PollGet(ctx, &oauth.Auth{Account: suite.testAccounts["local_account_2"]}, poll.ID)
+	suite.Nil(errWithCode)
+	suite.True(closed.Expired)
+
+	// votes aren't accepted any more
+	_, errWithCode = suite.processor.PollVote(ctx, &oauth.Auth{Account: suite.testAccounts["admin_account"]}, poll.ID, []int{1})
+	suite.NotNil(errWithCode)
+	suite.Equal(400, errWithCode.Code())
+}
+
+func TestPollTestSuite(t *testing.T) {
+	suite.Run(t, new(PollTestSuite))
+}
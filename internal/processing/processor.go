@@ -22,6 +22,7 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
@@ -32,6 +33,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/internal/metrics"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/account"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/admin"
@@ -51,8 +53,11 @@ import (
 // for clean distribution of messages without slowing down the client API and harming the user experience.
 type Processor interface {
 	// Start starts the Processor, reading from its channels and passing messages back and forth.
-	Start() error
-	// Stop stops the processor cleanly, finishing handling any remaining messages before closing down.
+	Start(ctx context.Context) error
+	// Stop stops the processor cleanly, queueing any messages that haven't been queued yet and
+	// waiting for messages that are being processed to finish before returning.
+	//
+	// Messages left in the queue will be processed the next time the processor is started.
 	Stop() error
 
 	/*
@@ -64,108 +69,218 @@ type Processor interface {
 	*/
 
 	// AccountCreate processes the given form for creating a new account, returning an oauth token for that account if successful.
-	AccountCreate(authed *oauth.Auth, form *apimodel.AccountCreateRequest) (*apimodel.Token, error)
+	AccountCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountCreateRequest) (*apimodel.Token, error)
 	// AccountGet processes the given request for account information.
-	AccountGet(authed *oauth.Auth, targetAccountID string) (*apimodel.Account, error)
+	AccountGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Account, error)
 	// AccountUpdate processes the update of an account with the given form
-	AccountUpdate(authed *oauth.Auth, form *apimodel.UpdateCredentialsRequest) (*apimodel.Account, error)
+	AccountUpdate(ctx context.Context, authed *oauth.Auth, form *apimodel.UpdateCredentialsRequest) (*apimodel.Account, error)
 	// AccountStatusesGet fetches a number of statuses (in time descending order) from the given account, filtered by visibility for
 	// the account given in authed.
-	AccountStatusesGet(authed *oauth.Auth, targetAccountID string, limit int, excludeReplies bool, maxID string, pinned bool, mediaOnly bool) ([]apimodel.Status, gtserror.WithCode)
+	AccountStatusesGet(ctx context.Context, authed *oauth.Auth, targetAccountID string, limit int, excludeReplies bool, maxID string, pinned bool, mediaOnly bool) ([]apimodel.Status, gtserror.WithCode)
 	// AccountFollowersGet fetches a list of the target account's followers.
-	AccountFollowersGet(authed *oauth.Auth, targetAccountID string) ([]apimodel.Account, gtserror.WithCode)
+	AccountFollowersGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) ([]apimodel.Account, gtserror.WithCode)
 	// AccountFollowingGet fetches a list of the accounts that target account is following.
-	AccountFollowingGet(authed *oauth.Auth, targetAccountID string) ([]apimodel.Account, gtserror.WithCode)
+	AccountFollowingGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) ([]apimodel.Account, gtserror.WithCode)
 	// AccountRelationshipGet returns a relationship model describing the relationship of the targetAccount to the Authed account.
-	AccountRelationshipGet(authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
+	AccountRelationshipGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
 	// AccountFollowCreate handles a follow request to an account, either remote or local.
-	AccountFollowCreate(authed *oauth.Auth, form *apimodel.AccountFollowRequest) (*apimodel.Relationship, gtserror.WithCode)
+	AccountFollowCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountFollowRequest) (*apimodel.Relationship, gtserror.WithCode)
 	// AccountFollowRemove handles the removal of a follow/follow request to an account, either remote or local.
-	AccountFollowRemove(authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
+	AccountFollowRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
 	// AccountBlockCreate handles the creation of a block from authed account to target account, either remote or local.
-	AccountBlockCreate(authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
+	AccountBlockCreate(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
 	// AccountBlockRemove handles the removal of a block from authed account to target account, either remote or local.
-	AccountBlockRemove(authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
+	AccountBlockRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
+	// AccountMuteCreate handles the creation or update of a mute from authed account to the target account of the form.
+	AccountMuteCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountMuteRequest) (*apimodel.Relationship, gtserror.WithCode)
+	// AccountMuteRemove handles the removal of a mute from authed account to target account.
+	AccountMuteRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
 
 	// AdminEmojiCreate handles the creation of a new instance emoji by an admin, using the given form.
-	AdminEmojiCreate(authed *oauth.Auth, form *apimodel.EmojiCreateRequest) (*apimodel.Emoji, error)
+	AdminEmojiCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.EmojiCreateRequest) (*apimodel.Emoji, error)
 	// AdminDomainBlockCreate handles the creation of a new domain block by an admin, using the given form.
-	AdminDomainBlockCreate(authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) (*apimodel.DomainBlock, gtserror.WithCode)
+	AdminDomainBlockCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) (*apimodel.DomainBlock, gtserror.WithCode)
 	// AdminDomainBlocksImport handles the import of multiple domain blocks by an admin, using the given form.
-	AdminDomainBlocksImport(authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) ([]*apimodel.DomainBlock, gtserror.WithCode)
+	AdminDomainBlocksImport(ctx context.Context, authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) ([]*apimodel.DomainBlock, gtserror.WithCode)
 	// AdminDomainBlocksGet returns a list of currently blocked domains.
-	AdminDomainBlocksGet(authed *oauth.Auth, export bool) ([]*apimodel.DomainBlock, gtserror.WithCode)
+	AdminDomainBlocksGet(ctx context.Context, authed *oauth.Auth, export bool) ([]*apimodel.DomainBlock, gtserror.WithCode)
 	// AdminDomainBlockGet returns one domain block, specified by ID.
-	AdminDomainBlockGet(authed *oauth.Auth, id string, export bool) (*apimodel.DomainBlock, gtserror.WithCode)
+	AdminDomainBlockGet(ctx context.Context, authed *oauth.Auth, id string, export bool) (*apimodel.DomainBlock, gtserror.WithCode)
 	// AdminDomainBlockDelete deletes one domain block, specified by ID, returning the deleted domain block.
-	AdminDomainBlockDelete(authed *oauth.Auth, id string) (*apimodel.DomainBlock, gtserror.WithCode)
+	AdminDomainBlockDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.DomainBlock, gtserror.WithCode)
+	// AdminDeliveriesGet returns a summary, per domain, of outgoing deliveries that are waiting to be retried or that have been given up on.
+	// If domain is set, only deliveries to that domain will be included.
+	AdminDeliveriesGet(ctx context.Context, authed *oauth.Auth, domain string) ([]*apimodel.AdminDeliveryInfo, gtserror.WithCode)
+	// AdminReportsGet returns unresolved reports, or resolved ones if resolved is true, optionally only those by or about the given accounts.
+	AdminReportsGet(ctx context.Context, authed *oauth.Auth, resolved bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*apimodel.AdminReportInfo, gtserror.WithCode)
+	// AdminReportGet returns one report, specified by ID.
+	AdminReportGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	// AdminReportAssign assigns the report with the given ID to the requesting admin.
+	AdminReportAssign(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	// AdminReportUnassign removes whichever admin was assigned to the report with the given ID.
+	AdminReportUnassign(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	// AdminReportNote sets the moderator notes on the report with the given ID.
+	AdminReportNote(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminReportNoteRequest) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	// AdminReportResolve resolves the report with the given ID, taking the action given in the form against the reported account or statuses.
+	AdminReportResolve(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminReportResolveRequest) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	// AdminReportReopen marks the report with the given ID as unresolved again. Any action that was taken is not undone.
+	AdminReportReopen(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
 
 	// AppCreate processes the creation of a new API application
-	AppCreate(authed *oauth.Auth, form *apimodel.ApplicationCreateRequest) (*apimodel.Application, error)
+	AppCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ApplicationCreateRequest) (*apimodel.Application, error)
 
 	// BlocksGet returns a list of accounts blocked by the requesting account.
-	BlocksGet(authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.BlocksResponse, gtserror.WithCode)
+	BlocksGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.BlocksResponse, gtserror.WithCode)
+
+	// ConversationsGet returns the direct message conversations of the requesting account, most recently active first.
+	ConversationsGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.ConversationsResponse, gtserror.WithCode)
+	// ConversationRead marks the conversation with the given ID as read by the requesting account.
+	ConversationRead(ctx context.Context, authed *oauth.Auth, conversationID string) (*apimodel.Conversation, gtserror.WithCode)
+	// ConversationDelete removes the conversation with the given ID from the requesting account's conversations.
+	ConversationDelete(ctx context.Context, authed *oauth.Auth, conversationID string) gtserror.WithCode
 
 	// FileGet handles the fetching of a media attachment file via the fileserver.
-	FileGet(authed *oauth.Auth, form *apimodel.GetContentRequestForm) (*apimodel.Content, error)
+	FileGet(ctx context.Context, authed *oauth.Auth, form *apimodel.GetContentRequestForm) (*apimodel.Content, error)
+
+	// FiltersGet returns all the keyword filters of the requesting account.
+	FiltersGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Filter, gtserror.WithCode)
+	// FilterGet returns the keyword filter with the given ID, if it's owned by the requesting account.
+	FilterGet(ctx context.Context, authed *oauth.Auth, filterID string) (*apimodel.Filter, gtserror.WithCode)
+	// FilterCreate creates a new keyword filter for the requesting account, using the given form.
+	FilterCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.FilterCreateRequest) (*apimodel.Filter, gtserror.WithCode)
+	// FilterUpdate replaces the settings of the given keyword filter with the ones in the given form.
+	FilterUpdate(ctx context.Context, authed *oauth.Auth, filterID string, form *apimodel.FilterCreateRequest) (*apimodel.Filter, gtserror.WithCode)
+	// FilterDelete deletes the given keyword filter, returning the deleted filter.
+	FilterDelete(ctx context.Context, authed *oauth.Auth, filterID string) (*apimodel.Filter, gtserror.WithCode)
 
 	// FollowRequestsGet handles the getting of the authed account's incoming follow requests
-	FollowRequestsGet(auth *oauth.Auth) ([]apimodel.Account, gtserror.WithCode)
+	FollowRequestsGet(ctx context.Context, auth *oauth.Auth) ([]apimodel.Account, gtserror.WithCode)
 	// FollowRequestAccept handles the acceptance of a follow request from the given account ID
-	FollowRequestAccept(auth *oauth.Auth, accountID string) (*apimodel.Relationship, gtserror.WithCode)
+	FollowRequestAccept(ctx context.Context, auth *oauth.Auth, accountID string) (*apimodel.Relationship, gtserror.WithCode)
 
 	// InstanceGet retrieves instance information for serving at api/v1/instance
-	InstanceGet(domain string) (*apimodel.Instance, gtserror.WithCode)
+	InstanceGet(ctx context.Context, domain string) (*apimodel.Instance, gtserror.WithCode)
 	// InstancePatch updates this instance according to the given form.
 	//
 	// It should already be ascertained that the requesting account is authenticated and an admin.
-	InstancePatch(form *apimodel.InstanceSettingsUpdateRequest) (*apimodel.Instance, gtserror.WithCode)
+	InstancePatch(ctx context.Context, form *apimodel.InstanceSettingsUpdateRequest) (*apimodel.Instance, gtserror.WithCode)
+
+	// ListsGet returns all the lists owned by the requesting account.
+	ListsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.List, gtserror.WithCode)
+	// ListGet returns the list with the given ID, if it's owned by the requesting account.
+	ListGet(ctx context.Context, authed *oauth.Auth, listID string) (*apimodel.List, gtserror.WithCode)
+	// ListCreate creates a new list for the requesting account, using the given form.
+	ListCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ListCreateRequest) (*apimodel.List, gtserror.WithCode)
+	// ListUpdate updates the title and/or replies policy of the given list.
+	ListUpdate(ctx context.Context, authed *oauth.Auth, listID string, form *apimodel.ListCreateRequest) (*apimodel.List, gtserror.WithCode)
+	// ListDelete deletes the given list and all its entries, returning the deleted list.
+	ListDelete(ctx context.Context, authed *oauth.Auth, listID string) (*apimodel.List, gtserror.WithCode)
+	// ListAccountsGet returns the accounts that are members of the given list, paged with maxID and sinceID.
+	ListAccountsGet(ctx context.Context, authed *oauth.Auth, listID string, maxID string, sinceID string, limit int) (*apimodel.ListAccountsResponse, gtserror.WithCode)
+	// ListAccountsAdd adds the given accounts to the given list. The requesting account must follow all of them.
+	ListAccountsAdd(ctx context.Context, authed *oauth.Auth, listID string, form *apimodel.ListAccountsChangeRequest) (*apimodel.List, gtserror.WithCode)
+	// ListAccountsRemove removes the given accounts from the given list.
+	ListAccountsRemove(ctx context.Context, authed *oauth.Auth, listID string, form *apimodel.ListAccountsChangeRequest) (*apimodel.List, gtserror.WithCode)
+	// AccountListsGet returns the lists owned by the requesting account that contain the target account.
+	AccountListsGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) ([]*apimodel.List, gtserror.WithCode)
 
 	// MediaCreate handles the creation of a media attachment, using the given form.
-	MediaCreate(authed *oauth.Auth, form *apimodel.AttachmentRequest) (*apimodel.Attachment, error)
+	MediaCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AttachmentRequest) (*apimodel.Attachment, error)
 	// MediaGet handles the GET of a media attachment with the given ID
-	MediaGet(authed *oauth.Auth, attachmentID string) (*apimodel.Attachment, gtserror.WithCode)
+	MediaGet(ctx context.Context, authed *oauth.Auth, attachmentID string) (*apimodel.Attachment, gtserror.WithCode)
 	// MediaUpdate handles the PUT of a media attachment with the given ID and form
-	MediaUpdate(authed *oauth.Auth, attachmentID string, form *apimodel.AttachmentUpdateRequest) (*apimodel.Attachment, gtserror.WithCode)
+	MediaUpdate(ctx context.Context, authed *oauth.Auth, attachmentID string, form *apimodel.AttachmentUpdateRequest) (*apimodel.Attachment, gtserror.WithCode)
+
+	// MutesGet returns a list of accounts muted by the requesting account.
+	MutesGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.MutesResponse, gtserror.WithCode)
 
 	// NotificationsGet
-	NotificationsGet(authed *oauth.Auth, limit int, maxID string, sinceID string) ([]*apimodel.Notification, gtserror.WithCode)
+	NotificationsGet(ctx context.Context, authed *oauth.Auth, limit int, maxID string, sinceID string) ([]*apimodel.Notification, gtserror.WithCode)
+
+	// PollGet returns the poll with the given ID, if the status it's attached to is visible to the requesting account.
+	PollGet(ctx context.Context, authed *oauth.Auth, pollID string) (*apimodel.Poll, gtserror.WithCode)
+	// PollVote casts the requesting account's vote in the given poll, for the options with the given indexes.
+	PollVote(ctx context.Context, authed *oauth.Auth, pollID string, choices []int) (*apimodel.Poll, gtserror.WithCode)
+
+	// ReportCreate reports an account, and optionally some of its statuses, to the moderators of this instance.
+	// If the account is remote, the report can also be forwarded to its instance.
+	ReportCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ReportCreateRequest) (*apimodel.Report, gtserror.WithCode)
+
+	// ScheduledStatusCreate schedules a status to be published at the time given in the form, instead of publishing it straight away.
+	ScheduledStatusCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdvancedStatusCreateForm) (*apimodel.ScheduledStatus, gtserror.WithCode)
+	// ScheduledStatusesGet returns the statuses that the requesting account has scheduled.
+	ScheduledStatusesGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.ScheduledStatusesResponse, gtserror.WithCode)
+	// ScheduledStatusGet returns the scheduled status with the given ID, if it belongs to the requesting account.
+	ScheduledStatusGet(ctx context.Context, authed *oauth.Auth, scheduledStatusID string) (*apimodel.ScheduledStatus, gtserror.WithCode)
+	// ScheduledStatusUpdate moves the scheduled status with the given ID to the time given in the form.
+	ScheduledStatusUpdate(ctx context.Context, authed *oauth.Auth, scheduledStatusID string, form *apimodel.ScheduledStatusUpdateRequest) (*apimodel.ScheduledStatus, gtserror.WithCode)
+	// ScheduledStatusDelete cancels the scheduled status with the given ID, so that it won't be published.
+	ScheduledStatusDelete(ctx context.Context, authed *oauth.Auth, scheduledStatusID string) gtserror.WithCode
 
 	// SearchGet performs a search with the given params, resolving/dereferencing remotely as desired
-	SearchGet(authed *oauth.Auth, searchQuery *apimodel.SearchQuery) (*apimodel.SearchResult, gtserror.WithCode)
+	SearchGet(ctx context.Context, authed *oauth.Auth, searchQuery *apimodel.SearchQuery) (*apimodel.SearchResult, gtserror.WithCode)
 
 	// StatusCreate processes the given form to create a new status, returning the api model representation of that status if it's OK.
-	StatusCreate(authed *oauth.Auth, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, error)
+	StatusCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, error)
 	// StatusDelete processes the delete of a given status, returning the deleted status if the delete goes through.
-	StatusDelete(authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
+	StatusDelete(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
 	// StatusFave processes the faving of a given status, returning the updated status if the fave goes through.
-	StatusFave(authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
+	StatusFave(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
+	// StatusBookmark processes the bookmarking of a given status, returning the updated status.
+	StatusBookmark(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	// StatusUnbookmark processes the removal of a bookmark from a given status, returning the updated status.
+	StatusUnbookmark(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	// StatusMute processes the muting of the thread that a given status is part of, returning the updated status.
+	StatusMute(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	// StatusUnmute processes the unmuting of the thread that a given status is part of, returning the updated status.
+	StatusUnmute(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	// StatusPin processes the pinning of a given status to the requesting account's profile, returning the updated status.
+	StatusPin(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	// StatusUnpin processes the unpinning of a given status from the requesting account's profile, returning the updated status.
+	StatusUnpin(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
 	// StatusBoost processes the boost/reblog of a given status, returning the newly-created boost if all is well.
-	StatusBoost(authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	StatusBoost(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
 	// StatusUnboost processes the unboost/unreblog of a given status, returning the status if all is well.
-	StatusUnboost(authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	StatusUnboost(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
 	// StatusBoostedBy returns a slice of accounts that have boosted the given status, filtered according to privacy settings.
-	StatusBoostedBy(authed *oauth.Auth, targetStatusID string) ([]*apimodel.Account, gtserror.WithCode)
+	StatusBoostedBy(ctx context.Context, authed *oauth.Auth, targetStatusID string) ([]*apimodel.Account, gtserror.WithCode)
 	// StatusFavedBy returns a slice of accounts that have liked the given status, filtered according to privacy settings.
-	StatusFavedBy(authed *oauth.Auth, targetStatusID string) ([]*apimodel.Account, error)
+	StatusFavedBy(ctx context.Context, authed *oauth.Auth, targetStatusID string) ([]*apimodel.Account, error)
 	// StatusGet gets the given status, taking account of privacy settings and blocks etc.
-	StatusGet(authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
+	StatusGet(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
 	// StatusUnfave processes the unfaving of a given status, returning the updated status if the fave goes through.
-	StatusUnfave(authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
+	StatusUnfave(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
 	// StatusGetContext returns the context (previous and following posts) from the given status ID
-	StatusGetContext(authed *oauth.Auth, targetStatusID string) (*apimodel.Context, gtserror.WithCode)
+	StatusGetContext(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Context, gtserror.WithCode)
+
+	// TagGet returns the hashtag with the given name, and whether the requesting account follows it.
+	TagGet(ctx context.Context, authed *oauth.Auth, tagName string) (*apimodel.Tag, gtserror.WithCode)
+	// TagFollow makes the requesting account follow the hashtag with the given name, so that public statuses using it show up in its home timeline.
+	TagFollow(ctx context.Context, authed *oauth.Auth, tagName string) (*apimodel.Tag, gtserror.WithCode)
+	// TagUnfollow makes the requesting account stop following the hashtag with the given name.
+	TagUnfollow(ctx context.Context, authed *oauth.Auth, tagName string) (*apimodel.Tag, gtserror.WithCode)
+	// FollowedTagsGet returns the hashtags followed by the requesting account.
+	FollowedTagsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Tag, gtserror.WithCode)
 
 	// HomeTimelineGet returns statuses from the home timeline, with the given filters/parameters.
-	HomeTimelineGet(authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
+	HomeTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
+	// ListTimelineGet returns statuses from the timeline of the given list, with the given filters/parameters.
+	ListTimelineGet(ctx context.Context, authed *oauth.Auth, listID string, maxID string, sinceID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
 	// PublicTimelineGet returns statuses from the public/local timeline, with the given filters/parameters.
-	PublicTimelineGet(authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
+	PublicTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
+	// TagTimelineGet returns public statuses using the given hashtag, or any of the tags in anyTagNames, which also use all of the tags
+	// in allTagNames and none of the tags in noneTagNames, with the given filters/parameters.
+	TagTimelineGet(ctx context.Context, authed *oauth.Auth, tagName string, anyTagNames []string, allTagNames []string, noneTagNames []string, maxID string, sinceID string, minID string, limit int, local bool, mediaOnly bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
 	// FavedTimelineGet returns faved statuses, with the given filters/parameters.
-	FavedTimelineGet(authed *oauth.Auth, maxID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
+	FavedTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
+	// BookmarkedTimelineGet returns bookmarked statuses, with the given filters/parameters.
+	BookmarkedTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
 
 	// AuthorizeStreamingRequest returns a gotosocial account in exchange for an access token, or an error if the given token is not valid.
-	AuthorizeStreamingRequest(accessToken string) (*gtsmodel.Account, error)
+	AuthorizeStreamingRequest(ctx context.Context, accessToken string) (*gtsmodel.Account, error)
 	// OpenStreamForAccount opens a new stream for the given account, with the given stream type.
-	OpenStreamForAccount(account *gtsmodel.Account, streamType string) (*gtsmodel.Stream, gtserror.WithCode)
+	// The listID is only used when the stream type is list.
+	OpenStreamForAccount(ctx context.Context, account *gtsmodel.Account, streamType string, listID string, tag string) (*gtsmodel.Stream, gtserror.WithCode)
 
 	/*
 		FEDERATION API-FACING PROCESSING FUNCTIONS
@@ -187,6 +302,10 @@ type Processor interface {
 	// authentication before returning a JSON serializable interface to the caller.
 	GetFediFollowing(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
 
+	// GetFediFeatured handles the getting of a fedi/activitypub representation of a user/account's pinned statuses, performing appropriate
+	// authentication before returning a JSON serializable interface to the caller.
+	GetFediFeatured(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
+
 	// GetFediStatus handles the getting of a fedi/activitypub representation of a particular status, performing appropriate
 	// authentication before returning a JSON serializable interface to the caller.
 	GetFediStatus(ctx context.Context, requestedUsername string, requestedStatusID string, requestURL *url.URL) (interface{}, gtserror.WithCode)
@@ -195,10 +314,10 @@ type Processor interface {
 	GetWebfingerAccount(ctx context.Context, requestedUsername string, requestURL *url.URL) (*apimodel.WellKnownResponse, gtserror.WithCode)
 
 	// GetNodeInfoRel returns a well known response giving the path to node info.
-	GetNodeInfoRel(request *http.Request) (*apimodel.WellKnownResponse, gtserror.WithCode)
+	GetNodeInfoRel(ctx context.Context, request *http.Request) (*apimodel.WellKnownResponse, gtserror.WithCode)
 
 	// GetNodeInfo returns a node info struct in response to a node info request.
-	GetNodeInfo(request *http.Request) (*apimodel.Nodeinfo, gtserror.WithCode)
+	GetNodeInfo(ctx context.Context, request *http.Request) (*apimodel.Nodeinfo, gtserror.WithCode)
 
 	// InboxPost handles POST requests to a user's inbox for new activitypub messages.
 	//
@@ -219,6 +338,10 @@ type processor struct {
 	fromFederator   chan gtsmodel.FromFederator
 	federator       federation.Federator
 	stop            chan interface{}
+	queueWake       chan struct{}
+	queueSlots      chan struct{}
+	wg              sync.WaitGroup
+	workers         sync.WaitGroup
 	log             *logrus.Logger
 	config          *config.Config
 	tc              typeutils.TypeConverter
@@ -250,13 +373,15 @@ func NewProcessor(config *config.Config, tc typeutils.TypeConverter, federator f
 	streamingProcessor := streaming.New(db, tc, oauthServer, config, log)
 	accountProcessor := account.New(db, tc, mediaHandler, oauthServer, fromClientAPI, federator, config, log)
 	adminProcessor := admin.New(db, tc, mediaHandler, fromClientAPI, config, log)
-	mediaProcessor := mediaProcessor.New(db, tc, mediaHandler, storage, config, log)
+	mediaProcessor := mediaProcessor.New(db, tc, mediaHandler, federator, storage, config, log)
 
-	return &processor{
+	p := &processor{
 		fromClientAPI:   fromClientAPI,
 		fromFederator:   fromFederator,
 		federator:       federator,
 		stop:            make(chan interface{}),
+		queueWake:       make(chan struct{}, 1),
+		queueSlots:      make(chan struct{}, queueWorkers),
 		log:             log,
 		config:          config,
 		tc:              tc,
@@ -273,39 +398,40 @@ func NewProcessor(config *config.Config, tc typeutils.TypeConverter, federator f
 		streamingProcessor: streamingProcessor,
 		mediaProcessor:     mediaProcessor,
 	}
+
+	if config.TracingConfig.Enabled {
+		return &tracingProcessor{Processor: p}
+	}
+	return p
 }
 
 // Start starts the Processor, reading from its channels and passing messages back and forth.
-func (p *processor) Start() error {
-	go func() {
-	DistLoop:
-		for {
-			select {
-			case clientMsg := <-p.fromClientAPI:
-				p.log.Tracef("received message FROM client API: %+v", clientMsg)
-				go func() {
-					if err := p.processFromClientAPI(clientMsg); err != nil {
-						p.log.Error(err)
-					}
-				}()
-			case federatorMsg := <-p.fromFederator:
-				p.log.Tracef("received message FROM federator: %+v", federatorMsg)
-				go func() {
-					if err := p.processFromFederator(federatorMsg); err != nil {
-						p.log.Error(err)
-					}
-				}()
-			case <-p.stop:
-				break DistLoop
-			}
-		}
-	}()
-	return p.initTimelines()
+//
+// Messages from the channels are first stored in the queue, and then picked up from there by a bounded pool of workers.
+// Failed messages are retried with a backoff, and messages left over from a previous run are picked up straight away.
+func (p *processor) Start(ctx context.Context) error {
+	metrics.SetQueueDepther(p)
+	metrics.SetTimelineSizer(p.timelineManager)
+
+	p.wg.Add(7)
+	go p.receive(ctx)
+	go p.dispatch(ctx)
+	go p.closePolls(ctx)
+	go p.cleanupMutes(ctx)
+	go p.publishScheduledStatuses(ctx)
+	go p.pruneRemoteMedia(ctx)
+	go p.cleanupMedia(ctx)
+
+	// there may be messages left in the queue from last time
+	p.wake()
+
+	return p.initTimelines(ctx)
 }
 
-// Stop stops the processor cleanly, finishing handling any remaining messages before closing down.
-// TODO: empty message buffer properly before stopping otherwise we'll lose federating messages.
+// Stop stops the processor cleanly, queueing any messages that haven't been queued yet and
+// waiting for messages that are being processed to finish before returning.
 func (p *processor) Stop() error {
 	close(p.stop)
+	p.wg.Wait()
 	return nil
 }
@@ -0,0 +1,316 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+const (
+	// queueWorkers is the maximum number of queued messages that will be processed at the same time.
+	queueWorkers = 8
+	// queueMaxAttempts is the number of times processing of a message will be attempted before it's given up on.
+	queueMaxAttempts = 5
+	// queueRetryBackoff is how long to wait before the first retry of a failed message; it doubles with every further attempt.
+	queueRetryBackoff = 30 * time.Second
+	// queueLease is how long a message that's being processed is hidden from the queue for. If processing is interrupted,
+	// for example because the instance crashed, then the message will be picked up again once the lease has run out.
+	queueLease = 10 * time.Minute
+	// queuePollInterval is how often the queue is checked for messages that are due to be retried.
+	queuePollInterval = 10 * time.Second
+)
+
+// queuedMessageKey is the context key under which the queued message that's being processed is stored.
+type queuedMessageKey struct{}
+
+// queueModelTypes are the types of GTSModel that can be carried by a queued message, keyed by their type name.
+var queueModelTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, m := range []interface{}{
+		&gtsmodel.Account{},
+		&gtsmodel.Block{},
+		&gtsmodel.DomainBlock{},
+		&gtsmodel.Follow{},
+		&gtsmodel.FollowRequest{},
+		&gtsmodel.PollVote{},
+		&gtsmodel.Report{},
+		&gtsmodel.Status{},
+		&gtsmodel.StatusFave{},
+	} {
+		t := reflect.TypeOf(m).Elem()
+		queueModelTypes[t.Name()] = t
+	}
+}
+
+// receive reads messages from the client API and federator channels and stores them in the queue, until the processor is stopped.
+//
+// When the processor is stopped, any messages that are still buffered in the channels are queued before receive returns,
+// so that they'll be processed when the processor is next started.
+func (p *processor) receive(ctx context.Context) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case clientMsg := <-p.fromClientAPI:
+			p.log.Tracef("received message FROM client API: %+v", clientMsg)
+			p.enqueue(ctx, gtsmodel.QueueOriginClientAPI, clientMsg.GTSModel, clientMsg)
+		case federatorMsg := <-p.fromFederator:
+			p.log.Tracef("received message FROM federator: %+v", federatorMsg)
+			p.enqueue(ctx, gtsmodel.QueueOriginFederator, federatorMsg.GTSModel, federatorMsg)
+		case <-p.stop:
+			for {
+				select {
+				case clientMsg := <-p.fromClientAPI:
+					p.enqueue(ctx, gtsmodel.QueueOriginClientAPI, clientMsg.GTSModel, clientMsg)
+				case federatorMsg := <-p.fromFederator:
+					p.enqueue(ctx, gtsmodel.QueueOriginFederator, federatorMsg.GTSModel, federatorMsg)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// enqueue stores the given FromClientAPI or FromFederator message in the queue, and wakes up the dispatcher.
+func (p *processor) enqueue(ctx context.Context, origin gtsmodel.QueueOrigin, model interface{}, msg interface{}) {
+	queued, err := newQueuedMessage(origin, model, msg)
+	if err != nil {
+		p.log.Errorf("enqueue: error encoding %s message: %s", origin, err)
+		return
+	}
+
+	if err := p.db.Put(ctx, queued); err != nil {
+		p.log.Errorf("enqueue: error putting %s message in the db: %s", origin, err)
+		return
+	}
+
+	p.wake()
+}
+
+// QueueDepth returns the number of messages waiting in the queue, for reporting as a metric.
+func (p *processor) QueueDepth() (int, error) {
+	return p.db.CountQueuedMessages(context.Background())
+}
+
+// wake lets the dispatcher know that there might be work for it to do.
+func (p *processor) wake() {
+	select {
+	case p.queueWake <- struct{}{}:
+	default:
+		// the dispatcher has already been woken up
+	}
+}
+
+// dispatch hands messages that are due from the queue to a bounded pool of workers, until the processor is stopped.
+//
+// When the processor is stopped, no new messages are picked up, but messages that are already being processed
+// are allowed to finish before dispatch returns.
+func (p *processor) dispatch(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(queuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.queueWake:
+		case <-ticker.C:
+		case <-p.stop:
+		}
+
+		select {
+		case <-p.stop:
+			p.workers.Wait()
+			return
+		default:
+		}
+
+		free := cap(p.queueSlots) - len(p.queueSlots)
+		if free == 0 {
+			// all workers are busy, we'll be woken up when one of them is done
+			continue
+		}
+
+		messages, err := p.db.GetDueQueuedMessages(ctx, free)
+		if err != nil {
+			if _, ok := err.(db.ErrNoEntries); !ok {
+				p.log.Errorf("dispatch: error getting queued messages from the db: %s", err)
+			}
+			continue
+		}
+
+		for _, m := range messages {
+			// hide the message from the queue while it's being worked on
+			m.NextAttemptAt = time.Now().Add(queueLease)
+			if err := p.db.UpdateByID(ctx, m.ID, m); err != nil {
+				p.log.Errorf("dispatch: error claiming queued message %s: %s", m.ID, err)
+				continue
+			}
+
+			p.queueSlots <- struct{}{}
+			p.workers.Add(1)
+			go func(m *gtsmodel.QueuedMessage) {
+				defer func() {
+					<-p.queueSlots
+					p.workers.Done()
+					p.wake()
+				}()
+				p.work(ctx, m)
+			}(m)
+		}
+	}
+}
+
+// work processes the given queued message, removing it from the queue if it's done with, or scheduling a retry if it failed.
+func (p *processor) work(ctx context.Context, m *gtsmodel.QueuedMessage) {
+	err := p.processQueuedMessage(context.WithValue(ctx, queuedMessageKey{}, m), m)
+	if err == nil {
+		if err := p.db.DeleteByID(ctx, m.ID, &gtsmodel.QueuedMessage{}); err != nil {
+			p.log.Errorf("work: error deleting processed message %s: %s", m.ID, err)
+		}
+		return
+	}
+
+	m.Attempts = m.Attempts + 1
+	m.LastError = err.Error()
+
+	if m.Attempts >= queueMaxAttempts {
+		p.log.Errorf("work: giving up on %s message %s after %d attempts: %s", m.Origin, m.ID, m.Attempts, err)
+		if err := p.db.DeleteByID(ctx, m.ID, &gtsmodel.QueuedMessage{}); err != nil {
+			p.log.Errorf("work: error deleting message %s: %s", m.ID, err)
+		}
+		return
+	}
+
+	backoff := queueRetryBackoff * time.Duration(1<<(m.Attempts-1))
+	p.log.Errorf("work: error processing %s message %s, retrying in %s: %s", m.Origin, m.ID, backoff, err)
+	m.NextAttemptAt = time.Now().Add(backoff)
+	if err := p.db.UpdateByID(ctx, m.ID, m); err != nil {
+		p.log.Errorf("work: error updating message %s: %s", m.ID, err)
+	}
+}
+
+// processQueuedMessage decodes the given queued message and passes it to processFromClientAPI or processFromFederator.
+//
+// A message which fails halfway through will be processed again from the start, but side effects that are run
+// with step and that already succeeded the first time around will be skipped.
+func (p *processor) processQueuedMessage(ctx context.Context, m *gtsmodel.QueuedMessage) error {
+	var model interface{}
+	if m.ModelType != "" {
+		t, ok := queueModelTypes[m.ModelType]
+		if !ok {
+			return fmt.Errorf("queued message has unknown model type %s", m.ModelType)
+		}
+		model = reflect.New(t).Interface()
+	}
+
+	switch m.Origin {
+	case gtsmodel.QueueOriginClientAPI:
+		// json will decode into the typed model that we put in the interface
+		clientMsg := gtsmodel.FromClientAPI{GTSModel: model}
+		if err := json.Unmarshal([]byte(m.Payload), &clientMsg); err != nil {
+			return fmt.Errorf("error decoding queued message: %s", err)
+		}
+		return p.processFromClientAPI(ctx, clientMsg)
+	case gtsmodel.QueueOriginFederator:
+		federatorMsg := gtsmodel.FromFederator{GTSModel: model}
+		if err := json.Unmarshal([]byte(m.Payload), &federatorMsg); err != nil {
+			return fmt.Errorf("error decoding queued message: %s", err)
+		}
+		return p.processFromFederator(ctx, federatorMsg)
+	}
+
+	return fmt.Errorf("queued message has unknown origin %s", m.Origin)
+}
+
+// step runs the side effect f of the queued message that's being processed, unless it already succeeded during an
+// earlier attempt at processing the message. Once f has succeeded, this is recorded on the message, so that retrying
+// the message because of a failure further along won't repeat it.
+//
+// The name of each step must be unique among the side effects of a message. If ctx doesn't carry a queued message,
+// f is just run.
+func (p *processor) step(ctx context.Context, name string, f func() error) error {
+	m, ok := ctx.Value(queuedMessageKey{}).(*gtsmodel.QueuedMessage)
+	if !ok {
+		return f()
+	}
+
+	for _, done := range m.CompletedSteps {
+		if done == name {
+			p.log.Tracef("step: skipping %s of message %s, which already succeeded", name, m.ID)
+			return nil
+		}
+	}
+
+	if err := f(); err != nil {
+		return err
+	}
+
+	m.CompletedSteps = append(m.CompletedSteps, name)
+	if err := p.db.UpdateByID(ctx, m.ID, m); err != nil {
+		// the step itself succeeded, so carry on; it'll only be repeated if the message has to be retried
+		p.log.Errorf("step: error recording %s of message %s: %s", name, m.ID, err)
+	}
+	return nil
+}
+
+// newQueuedMessage encodes the given FromClientAPI or FromFederator message, carrying the given model, as a queued message.
+func newQueuedMessage(origin gtsmodel.QueueOrigin, model interface{}, msg interface{}) (*gtsmodel.QueuedMessage, error) {
+	var modelType string
+	if model != nil {
+		t := reflect.TypeOf(model)
+		if t.Kind() != reflect.Ptr {
+			return nil, fmt.Errorf("model %T is not a pointer", model)
+		}
+		if _, ok := queueModelTypes[t.Elem().Name()]; !ok {
+			return nil, fmt.Errorf("model type %T can't be queued", model)
+		}
+		modelType = t.Elem().Name()
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, err := id.NewULID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &gtsmodel.QueuedMessage{
+		ID:            messageID,
+		CreatedAt:     now,
+		Origin:        origin,
+		ModelType:     modelType,
+		Payload:       string(payload),
+		NextAttemptAt: now,
+	}, nil
+}
@@ -0,0 +1,162 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type QueueTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+// TestStopLosesNothing makes sure that a message sent just before the processor is stopped
+// either gets processed, or is left in the queue to be processed next time.
+func (suite *QueueTestSuite) TestStopLosesNothing() {
+	ctx := context.Background()
+	suite.NoError(suite.processor.Start(ctx))
+
+	account := suite.testAccounts["local_account_2"]
+	status := suite.testStatuses["admin_account_status_1"]
+	_, err := suite.processor.StatusFave(ctx, &oauth.Auth{Account: account}, status.ID)
+	suite.NoError(err)
+
+	suite.NoError(suite.processor.Stop())
+
+	queued := []*gtsmodel.QueuedMessage{}
+	suite.NoError(suite.db.GetAll(ctx, &queued))
+
+	notifications := []*gtsmodel.Notification{}
+	suite.NoError(suite.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: status.ID}, {Key: "origin_account_id", Value: account.ID}}, &notifications))
+
+	if len(queued) == 0 {
+		// the fave was processed before the processor stopped, so the admin should have been notified
+		suite.Len(notifications, 1)
+	} else {
+		suite.Len(queued, 1)
+		suite.Equal(gtsmodel.QueueOriginClientAPI, queued[0].Origin)
+		suite.Equal("StatusFave", queued[0].ModelType)
+		suite.Empty(notifications)
+	}
+}
+
+// queueFave puts a message in the queue for local_account_2 faving admin_account_status_1. If completedSteps
+// isn't empty, it's as if processing had already been attempted and those steps had succeeded.
+func (suite *QueueTestSuite) queueFave(messageID string, completedSteps []string) {
+	account := suite.testAccounts["local_account_2"]
+	targetAccount := suite.testAccounts["admin_account"]
+	status := suite.testStatuses["admin_account_status_1"]
+
+	payload, err := json.Marshal(gtsmodel.FromClientAPI{
+		APObjectType:   gtsmodel.ActivityStreamsLike,
+		APActivityType: gtsmodel.ActivityStreamsCreate,
+		GTSModel: &gtsmodel.StatusFave{
+			ID:              "01FJ1T8SSGS2GW5T1N0SXZE83D",
+			AccountID:       account.ID,
+			TargetAccountID: targetAccount.ID,
+			StatusID:        status.ID,
+		},
+		OriginAccount: account,
+		TargetAccount: targetAccount,
+	})
+	suite.NoError(err)
+
+	suite.NoError(suite.db.Put(context.Background(), &gtsmodel.QueuedMessage{
+		ID:             messageID,
+		CreatedAt:      time.Now(),
+		Origin:         gtsmodel.QueueOriginClientAPI,
+		ModelType:      "StatusFave",
+		Payload:        string(payload),
+		Attempts:       len(completedSteps),
+		NextAttemptAt:  time.Now(),
+		CompletedSteps: completedSteps,
+	}))
+}
+
+// processQueue starts the processor and waits for the queue to be emptied, before stopping it again.
+func (suite *QueueTestSuite) processQueue() {
+	ctx := context.Background()
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+
+	for i := 0; i < 50; i++ {
+		queued := []*gtsmodel.QueuedMessage{}
+		if err := suite.db.GetAll(ctx, &queued); err != nil {
+			if _, ok := err.(db.ErrNoEntries); !ok {
+				suite.FailNow(err.Error())
+			}
+		}
+		if len(queued) == 0 {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	suite.FailNow("queue wasn't emptied")
+}
+
+func (suite *QueueTestSuite) faveNotifications() []*gtsmodel.Notification {
+	notifications := []*gtsmodel.Notification{}
+	if err := suite.db.GetWhere(context.Background(), []db.Where{
+		{Key: "notification_type", Value: gtsmodel.NotificationFave},
+		{Key: "status_id", Value: suite.testStatuses["admin_account_status_1"].ID},
+		{Key: "origin_account_id", Value: suite.testAccounts["local_account_2"].ID},
+	}, &notifications); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			suite.FailNow(err.Error())
+		}
+	}
+	return notifications
+}
+
+// TestRetrySkipsCompletedSteps makes sure that retrying a message doesn't repeat side effects that already succeeded.
+func (suite *QueueTestSuite) TestRetrySkipsCompletedSteps() {
+	suite.queueFave("01FJ1T9S4DHXJ3P9JH1Q0WE6CQ", []string{"notify"})
+	suite.processQueue()
+	suite.Empty(suite.faveNotifications())
+}
+
+// TestRetryNotifiesOnce makes sure that a notification isn't created twice if a message is retried without
+// the notify step having been recorded, for example because the instance crashed just after notifying.
+func (suite *QueueTestSuite) TestRetryNotifiesOnce() {
+	suite.NoError(suite.db.Put(context.Background(), &gtsmodel.Notification{
+		ID:               "01FJ1TA1N6SH9Q1ZHWY6A1CJ8P",
+		NotificationType: gtsmodel.NotificationFave,
+		TargetAccountID:  suite.testAccounts["admin_account"].ID,
+		OriginAccountID:  suite.testAccounts["local_account_2"].ID,
+		StatusID:         suite.testStatuses["admin_account_status_1"].ID,
+	}))
+
+	suite.queueFave("01FJ1T9S4DHXJ3P9JH1Q0WE6CQ", nil)
+	suite.processQueue()
+	suite.Len(suite.faveNotifications(), 1)
+}
+
+func TestQueueTestSuite(t *testing.T) {
+	suite.Run(t, new(QueueTestSuite))
+}
@@ -0,0 +1,130 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// reportCommentMaxChars is the maximum length of the comment on a report.
+const reportCommentMaxChars = 1000
+
+func (p *processor) ReportCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ReportCreateRequest) (*apimodel.Report, gtserror.WithCode) {
+	if len([]rune(form.Comment)) > reportCommentMaxChars {
+		err := fmt.Errorf("comment must be no more than %d characters", reportCommentMaxChars)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	statuses := []*gtsmodel.Status{}
+	for _, statusID := range form.StatusIDs {
+		s := &gtsmodel.Status{}
+		if err := p.db.GetByID(ctx, statusID, s); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				err := fmt.Errorf("status with id %s not found", statusID)
+				return nil, gtserror.NewErrorBadRequest(err, err.Error())
+			}
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("ReportCreate: error getting status %s: %s", statusID, err))
+		}
+		statuses = append(statuses, s)
+	}
+
+	// if no account was given, the author of the reported statuses is the one being reported
+	targetAccountID := form.AccountID
+	if targetAccountID == "" {
+		if len(statuses) == 0 {
+			err := errors.New("account_id or status_ids must be provided")
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		targetAccountID = statuses[0].AccountID
+	}
+
+	targetAccount := &gtsmodel.Account{}
+	if err := p.db.GetByID(ctx, targetAccountID, targetAccount); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			err := fmt.Errorf("account with id %s not found", targetAccountID)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ReportCreate: error getting account %s: %s", targetAccountID, err))
+	}
+
+	if targetAccount.ID == authed.Account.ID {
+		err := errors.New("you can't report yourself")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	statusIDs := []string{}
+	for _, s := range statuses {
+		if s.AccountID != targetAccount.ID {
+			err := fmt.Errorf("status with id %s was not posted by account %s", s.ID, targetAccount.ID)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		statusIDs = append(statusIDs, s.ID)
+	}
+
+	reportID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	report := &gtsmodel.Report{
+		ID:        reportID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		// forwarded reports are sent by the instance account, so the URI lives under that account
+		URI:             util.GenerateURIForFlag(p.config.Host, p.config.Protocol, p.config.Host, reportID),
+		AccountID:       authed.Account.ID,
+		TargetAccountID: targetAccount.ID,
+		StatusIDs:       statusIDs,
+		Comment:         form.Comment,
+		// reports about local accounts have nowhere to be forwarded to
+		Forwarded: form.Forward && targetAccount.Domain != "",
+	}
+
+	if err := p.db.Put(ctx, report); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ReportCreate: error putting report in db: %s", err))
+	}
+
+	if report.Forwarded {
+		p.fromClientAPI <- gtsmodel.FromClientAPI{
+			APObjectType:   gtsmodel.ActivityStreamsFlag,
+			APActivityType: gtsmodel.ActivityStreamsCreate,
+			GTSModel:       report,
+			OriginAccount:  authed.Account,
+			TargetAccount:  targetAccount,
+		}
+	}
+
+	mastoReport, err := p.tc.ReportToMasto(ctx, report)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ReportCreate: error converting report to api representation: %s", err))
+	}
+
+	return mastoReport, nil
+}
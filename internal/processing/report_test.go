@@ -0,0 +1,260 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type ReportTestSuite struct {
+	suite.Suite
+	db           db.DB
+	processor    processing.Processor
+	testAccounts map[string]*gtsmodel.Account
+	testStatuses map[string]*gtsmodel.Status
+
+	// bodies of requests posted to other instances
+	postedMu sync.Mutex
+	posted   map[string][]string
+}
+
+func (suite *ReportTestSuite) SetupTest() {
+	suite.db = testrig.NewTestDB()
+	testrig.StandardDBSetup(suite.db)
+	storage := testrig.NewTestStorage()
+	suite.posted = make(map[string][]string)
+	httpClient := testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPost && req.Body != nil {
+			b, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			suite.postedMu.Lock()
+			suite.posted[req.URL.String()] = append(suite.posted[req.URL.String()], string(b))
+			suite.postedMu.Unlock()
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+	federator := testrig.NewTestFederator(suite.db, testrig.NewTestTransportController(httpClient), storage)
+	suite.processor = testrig.NewTestProcessor(suite.db, storage, federator)
+	suite.testAccounts = testrig.NewTestAccounts()
+	suite.testStatuses = testrig.NewTestStatuses()
+}
+
+func (suite *ReportTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+// report makes local_account_1 report local_account_2 for one of its statuses.
+func (suite *ReportTestSuite) report() *apimodel.Report {
+	report, errWithCode := suite.processor.ReportCreate(context.Background(), &oauth.Auth{Account: suite.testAccounts["local_account_1"]}, &apimodel.ReportCreateRequest{
+		StatusIDs: []string{suite.testStatuses["local_account_2_status_1"].ID},
+		Comment:   "this is spam",
+		Forward:   true,
+	})
+	suite.Nil(errWithCode)
+	return report
+}
+
+func (suite *ReportTestSuite) TestReportCreate() {
+	report := suite.report()
+
+	// the reported account is taken from the status, and reports of local accounts can't be forwarded
+	suite.Equal(suite.testAccounts["local_account_2"].ID, report.TargetAccount.ID)
+	suite.Equal([]string{suite.testStatuses["local_account_2_status_1"].ID}, report.StatusIDs)
+	suite.Equal("this is spam", report.Comment)
+	suite.False(report.Forwarded)
+	suite.False(report.ActionTaken)
+	suite.Nil(report.ActionTakenAt)
+}
+
+func (suite *ReportTestSuite) TestReportCreateInvalid() {
+	ctx := context.Background()
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+
+	for _, form := range []*apimodel.ReportCreateRequest{
+		// nobody to report
+		{Comment: "hmm"},
+		// reporting yourself
+		{AccountID: suite.testAccounts["local_account_1"].ID},
+		// the status wasn't posted by the reported account
+		{AccountID: suite.testAccounts["local_account_2"].ID, StatusIDs: []string{suite.testStatuses["local_account_1_status_1"].ID}},
+		// comment is too long
+		{AccountID: suite.testAccounts["local_account_2"].ID, Comment: strings.Repeat("a", 1001)},
+	} {
+		_, errWithCode := suite.processor.ReportCreate(ctx, authed, form)
+		suite.Equal(http.StatusBadRequest, errWithCode.Code())
+	}
+}
+
+func (suite *ReportTestSuite) TestAdminReportModeration() {
+	ctx := context.Background()
+	admin := &oauth.Auth{Account: suite.testAccounts["admin_account"]}
+	report := suite.report()
+
+	reports, errWithCode := suite.processor.AdminReportsGet(ctx, admin, false, "", suite.testAccounts["local_account_2"].ID, "", "", 20)
+	suite.Nil(errWithCode)
+	suite.Len(reports, 1)
+	suite.Len(reports[0].Statuses, 1)
+	suite.Equal(suite.testAccounts["local_account_1"].ID, reports[0].Account.ID)
+
+	adminReport, errWithCode := suite.processor.AdminReportAssign(ctx, admin, report.ID)
+	suite.Nil(errWithCode)
+	suite.Equal(suite.testAccounts["admin_account"].ID, adminReport.AssignedAccount.ID)
+
+	adminReport, errWithCode = suite.processor.AdminReportNote(ctx, admin, report.ID, &apimodel.AdminReportNoteRequest{Note: "definitely spam"})
+	suite.Nil(errWithCode)
+	suite.Equal("definitely spam", adminReport.Note)
+
+	// an unknown action is rejected
+	_, errWithCode = suite.processor.AdminReportResolve(ctx, admin, report.ID, &apimodel.AdminReportResolveRequest{Action: "banish"})
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+
+	adminReport, errWithCode = suite.processor.AdminReportResolve(ctx, admin, report.ID, &apimodel.AdminReportResolveRequest{Action: "silence"})
+	suite.Nil(errWithCode)
+	suite.Equal("silence", adminReport.ActionTaken)
+	suite.NotNil(adminReport.ActionTakenAt)
+	suite.Equal(suite.testAccounts["admin_account"].ID, adminReport.ActionTakenByAccount.ID)
+
+	// the reported account should be silenced
+	silenced := &gtsmodel.Account{}
+	suite.NoError(suite.db.GetByID(ctx, suite.testAccounts["local_account_2"].ID, silenced))
+	suite.False(silenced.SilencedAt.IsZero())
+
+	// a report can only be resolved once
+	_, errWithCode = suite.processor.AdminReportResolve(ctx, admin, report.ID, &apimodel.AdminReportResolveRequest{})
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+
+	// the report should have moved from the unresolved to the resolved list
+	reports, errWithCode = suite.processor.AdminReportsGet(ctx, admin, false, "", "", "", "", 20)
+	suite.Nil(errWithCode)
+	suite.Empty(reports)
+	reports, errWithCode = suite.processor.AdminReportsGet(ctx, admin, true, "", "", "", "", 20)
+	suite.Nil(errWithCode)
+	suite.Len(reports, 1)
+
+	adminReport, errWithCode = suite.processor.AdminReportReopen(ctx, admin, report.ID)
+	suite.Nil(errWithCode)
+	suite.Empty(adminReport.ActionTaken)
+	suite.Nil(adminReport.ActionTakenAt)
+	suite.Nil(adminReport.ActionTakenByAccount)
+}
+
+func (suite *ReportTestSuite) TestAdminReportDeleteStatus() {
+	ctx := context.Background()
+	admin := &oauth.Auth{Account: suite.testAccounts["admin_account"]}
+	report := suite.report()
+
+	adminReport, errWithCode := suite.processor.AdminReportResolve(ctx, admin, report.ID, &apimodel.AdminReportResolveRequest{Action: "delete_status"})
+	suite.Nil(errWithCode)
+	suite.Equal("delete_status", adminReport.ActionTaken)
+
+	err := suite.db.GetByID(ctx, suite.testStatuses["local_account_2_status_1"].ID, &gtsmodel.Status{})
+	suite.IsType(db.ErrNoEntries{}, err)
+}
+
+// eventually waits for condition to become true while the processor works through its queue.
+func (suite *ReportTestSuite) eventually(condition func() bool) bool {
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if condition() {
+			return true
+		}
+	}
+	return false
+}
+
+func (suite *ReportTestSuite) TestReportForwardFederatesFlag() {
+	ctx := context.Background()
+	suite.NoError(suite.processor.Start(ctx))
+	defer suite.processor.Stop()
+
+	target := suite.testAccounts["remote_account_1"]
+	report, errWithCode := suite.processor.ReportCreate(ctx, &oauth.Auth{Account: suite.testAccounts["local_account_1"]}, &apimodel.ReportCreateRequest{
+		AccountID: target.ID,
+		Comment:   "this is spam",
+		Forward:   true,
+	})
+	suite.Nil(errWithCode)
+	suite.True(report.Forwarded)
+
+	// the report should go through the queue and be delivered to the remote instance as a Flag
+	delivered := suite.eventually(func() bool {
+		suite.postedMu.Lock()
+		defer suite.postedMu.Unlock()
+		for _, body := range suite.posted[target.InboxURI] {
+			if strings.Contains(body, `"Flag"`) && strings.Contains(body, target.URI) {
+				return true
+			}
+		}
+		return false
+	})
+	suite.True(delivered)
+
+	// the Flag is sent by the instance account, so it shouldn't give away who made the report
+	suite.postedMu.Lock()
+	defer suite.postedMu.Unlock()
+	for _, body := range suite.posted[target.InboxURI] {
+		suite.NotContains(body, suite.testAccounts["local_account_1"].URI)
+	}
+}
+
+func (suite *ReportTestSuite) TestAdminReportSuspend() {
+	ctx := context.Background()
+	suite.NoError(suite.processor.Start(ctx))
+	defer suite.processor.Stop()
+
+	admin := &oauth.Auth{Account: suite.testAccounts["admin_account"]}
+	report := suite.report()
+
+	adminReport, errWithCode := suite.processor.AdminReportResolve(ctx, admin, report.ID, &apimodel.AdminReportResolveRequest{Action: "suspend"})
+	suite.Nil(errWithCode)
+	suite.Equal("suspend", adminReport.ActionTaken)
+
+	// the suspension goes through the queue, and the reported account should end up suspended
+	suspended := suite.eventually(func() bool {
+		account := &gtsmodel.Account{}
+		if err := suite.db.GetByID(ctx, suite.testAccounts["local_account_2"].ID, account); err != nil {
+			return false
+		}
+		return !account.SuspendedAt.IsZero()
+	})
+	suite.True(suspended)
+}
+
+func TestReportTestSuite(t *testing.T) {
+	suite.Run(t, new(ReportTestSuite))
+}
@@ -0,0 +1,426 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// scheduledStatusMinLead is how far in the future a status must be scheduled.
+const scheduledStatusMinLead = 5 * time.Minute
+
+// scheduledStatusInterval is how often scheduled statuses that are due get published.
+const scheduledStatusInterval = 30 * time.Second
+
+// scheduledStatusBatch is the maximum number of scheduled statuses that will be published in one go.
+const scheduledStatusBatch = 100
+
+// scheduledStatusMaxAttempts is the number of times publishing a scheduled status will be tried before it's given up on.
+const scheduledStatusMaxAttempts = 5
+
+// scheduledStatusRetryBackoff is how long to wait before trying to publish a scheduled status again after the first failure;
+// it doubles with every further attempt.
+const scheduledStatusRetryBackoff = 1 * time.Minute
+
+func (p *processor) ScheduledStatusCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdvancedStatusCreateForm) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	scheduledAt, err := parseScheduledAt(form.ScheduledAt)
+	if err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	scheduledID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// check the media now rather than when the status is published, so that problems are reported to the client straight away
+	attachments := []*gtsmodel.MediaAttachment{}
+	for _, mediaID := range form.MediaIDs {
+		a := &gtsmodel.MediaAttachment{}
+		if err := p.db.GetByID(ctx, mediaID, a); err != nil {
+			err := fmt.Errorf("invalid media type or media not found for media id %s", mediaID)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		if a.AccountID != authed.Account.ID {
+			err := fmt.Errorf("media with id %s does not belong to account %s", mediaID, authed.Account.ID)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		if a.StatusID != "" || a.ScheduledStatusID != "" {
+			err := fmt.Errorf("media with id %s is already attached to a status", mediaID)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		attachments = append(attachments, a)
+	}
+
+	// the time is stored on the scheduled status itself, so it's not needed in the params
+	params := *form
+	params.ScheduledAt = ""
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ScheduledStatusCreate: error encoding params: %s", err))
+	}
+
+	scheduled := &gtsmodel.ScheduledStatus{
+		ID:               scheduledID,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+		AccountID:        authed.Account.ID,
+		ScheduledAt:      scheduledAt,
+		Params:           string(b),
+		MediaAttachments: form.MediaIDs,
+	}
+	if authed.Application != nil {
+		scheduled.ApplicationID = authed.Application.ID
+	}
+
+	if err := p.db.Put(ctx, scheduled); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ScheduledStatusCreate: error putting scheduled status in db: %s", err))
+	}
+
+	// attach the media to the scheduled status so that it can't be used anywhere else in the meantime
+	for _, a := range attachments {
+		a.ScheduledStatusID = scheduled.ID
+		a.UpdatedAt = time.Now()
+		if err := p.db.UpdateByID(ctx, a.ID, a); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("ScheduledStatusCreate: error attaching media %s: %s", a.ID, err))
+		}
+	}
+
+	return p.scheduledStatusToMasto(ctx, scheduled)
+}
+
+func (p *processor) ScheduledStatusesGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.ScheduledStatusesResponse, gtserror.WithCode) {
+	resp := &apimodel.ScheduledStatusesResponse{
+		ScheduledStatuses: []*apimodel.ScheduledStatus{},
+	}
+
+	scheduled, err := p.db.GetScheduledStatusesForAccount(ctx, authed.Account.ID, maxID, sinceID, limit)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			// there are just no entries
+			return resp, nil
+		}
+		// there's an actual error
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	for _, s := range scheduled {
+		apiScheduled, errWithCode := p.scheduledStatusToMasto(ctx, s)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		resp.ScheduledStatuses = append(resp.ScheduledStatuses, apiScheduled)
+	}
+
+	// prepare the next and previous links
+	nextLink := &url.URL{
+		Scheme:   p.config.Protocol,
+		Host:     p.config.Host,
+		Path:     "/api/v1/scheduled_statuses",
+		RawQuery: fmt.Sprintf("limit=%d&max_id=%s", limit, scheduled[len(scheduled)-1].ID),
+	}
+	next := fmt.Sprintf("<%s>; rel=\"next\"", nextLink.String())
+
+	prevLink := &url.URL{
+		Scheme:   p.config.Protocol,
+		Host:     p.config.Host,
+		Path:     "/api/v1/scheduled_statuses",
+		RawQuery: fmt.Sprintf("limit=%d&since_id=%s", limit, scheduled[0].ID),
+	}
+	prev := fmt.Sprintf("<%s>; rel=\"prev\"", prevLink.String())
+	resp.LinkHeader = fmt.Sprintf("%s, %s", next, prev)
+
+	return resp, nil
+}
+
+func (p *processor) ScheduledStatusGet(ctx context.Context, authed *oauth.Auth, scheduledStatusID string) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	scheduled, errWithCode := p.getOwnScheduledStatus(ctx, authed, scheduledStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.scheduledStatusToMasto(ctx, scheduled)
+}
+
+func (p *processor) ScheduledStatusUpdate(ctx context.Context, authed *oauth.Auth, scheduledStatusID string, form *apimodel.ScheduledStatusUpdateRequest) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	scheduled, errWithCode := p.getOwnScheduledStatus(ctx, authed, scheduledStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	scheduledAt, err := parseScheduledAt(form.ScheduledAt)
+	if err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// rescheduling gives a scheduled status that couldn't be published a fresh start
+	scheduled.ScheduledAt = scheduledAt
+	scheduled.Attempts = 0
+	scheduled.LastError = ""
+	scheduled.FailedAt = time.Time{}
+	scheduled.NextAttemptAt = time.Time{}
+	scheduled.UpdatedAt = time.Now()
+	if err := p.db.UpdateByID(ctx, scheduled.ID, scheduled); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("ScheduledStatusUpdate: error updating scheduled status in db: %s", err))
+	}
+
+	return p.scheduledStatusToMasto(ctx, scheduled)
+}
+
+func (p *processor) ScheduledStatusDelete(ctx context.Context, authed *oauth.Auth, scheduledStatusID string) gtserror.WithCode {
+	scheduled, errWithCode := p.getOwnScheduledStatus(ctx, authed, scheduledStatusID)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	if err := p.deleteScheduledStatus(ctx, scheduled); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("ScheduledStatusDelete: %s", err))
+	}
+
+	return nil
+}
+
+// getOwnScheduledStatus gets the scheduled status with the given ID, making sure that it belongs to the authed account.
+func (p *processor) getOwnScheduledStatus(ctx context.Context, authed *oauth.Auth, scheduledStatusID string) (*gtsmodel.ScheduledStatus, gtserror.WithCode) {
+	scheduled := &gtsmodel.ScheduledStatus{}
+	if err := p.db.GetByID(ctx, scheduledStatusID, scheduled); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("scheduled status %s not found", scheduledStatusID))
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting scheduled status %s: %s", scheduledStatusID, err))
+	}
+
+	if scheduled.AccountID != authed.Account.ID {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("scheduled status %s does not belong to account %s", scheduledStatusID, authed.Account.ID))
+	}
+
+	return scheduled, nil
+}
+
+func (p *processor) scheduledStatusToMasto(ctx context.Context, scheduled *gtsmodel.ScheduledStatus) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	apiScheduled, err := p.tc.ScheduledStatusToMasto(ctx, scheduled)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting scheduled status to api representation: %s", err))
+	}
+	return apiScheduled, nil
+}
+
+// deleteScheduledStatus removes the given scheduled status from the database, releasing its media
+// attachments so that they can be used again.
+func (p *processor) deleteScheduledStatus(ctx context.Context, scheduled *gtsmodel.ScheduledStatus) error {
+	if err := p.releaseScheduledMedia(ctx, scheduled); err != nil {
+		return err
+	}
+
+	if err := p.db.DeleteByID(ctx, scheduled.ID, &gtsmodel.ScheduledStatus{}); err != nil {
+		return fmt.Errorf("error deleting scheduled status %s from db: %s", scheduled.ID, err)
+	}
+
+	return nil
+}
+
+// releaseScheduledMedia detaches the media attachments of the given scheduled status from it.
+func (p *processor) releaseScheduledMedia(ctx context.Context, scheduled *gtsmodel.ScheduledStatus) error {
+	for _, aID := range scheduled.MediaAttachments {
+		a := &gtsmodel.MediaAttachment{}
+		if err := p.db.GetByID(ctx, aID, a); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				continue
+			}
+			return fmt.Errorf("error getting media %s from db: %s", aID, err)
+		}
+
+		if a.ScheduledStatusID != scheduled.ID {
+			continue
+		}
+
+		a.ScheduledStatusID = ""
+		a.UpdatedAt = time.Now()
+		if err := p.db.UpdateByID(ctx, a.ID, a); err != nil {
+			return fmt.Errorf("error releasing media %s: %s", a.ID, err)
+		}
+	}
+	return nil
+}
+
+// publishScheduledStatuses periodically publishes scheduled statuses that are due, until the processor is stopped.
+//
+// Scheduled statuses live in the database, so any that fell due while the server was down are published as soon as it's back.
+func (p *processor) publishScheduledStatuses(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(scheduledStatusInterval)
+	defer ticker.Stop()
+
+	for {
+		p.publishDueScheduledStatuses(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// publishDueScheduledStatuses publishes all scheduled statuses that are due.
+func (p *processor) publishDueScheduledStatuses(ctx context.Context) {
+	for {
+		scheduled, err := p.db.GetDueScheduledStatuses(ctx, scheduledStatusBatch)
+		if err != nil {
+			if _, ok := err.(db.ErrNoEntries); !ok {
+				p.log.Errorf("publishDueScheduledStatuses: error getting due scheduled statuses from the db: %s", err)
+			}
+			return
+		}
+
+		failed := 0
+		for _, s := range scheduled {
+			if err := p.publishDueScheduledStatus(ctx, s); err != nil {
+				p.log.Errorf("publishDueScheduledStatuses: %s", err)
+				failed++
+			}
+		}
+
+		// something that failed might still be due, so leave the rest until next time rather than going round in circles
+		if len(scheduled) < scheduledStatusBatch || failed > 0 {
+			return
+		}
+	}
+}
+
+// publishDueScheduledStatus publishes the given scheduled status, and removes it from the database once that's done.
+//
+// The ID that the new status will get is stored on the scheduled status before publishing it. That way, if the
+// scheduled status can't be removed after it was published, the status it was published as is found next time
+// instead of it being published twice. A failed attempt to publish is tried again later on.
+func (p *processor) publishDueScheduledStatus(ctx context.Context, scheduled *gtsmodel.ScheduledStatus) error {
+	if scheduled.StatusID != "" {
+		err := p.db.GetByID(ctx, scheduled.StatusID, &gtsmodel.Status{})
+		if err == nil {
+			// it's already been published, it just wasn't removed
+			if err := p.db.DeleteByID(ctx, scheduled.ID, &gtsmodel.ScheduledStatus{}); err != nil {
+				return fmt.Errorf("error deleting published scheduled status %s from db: %s", scheduled.ID, err)
+			}
+			return nil
+		}
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return fmt.Errorf("error checking whether scheduled status %s was published: %s", scheduled.ID, err)
+		}
+	}
+
+	statusID, err := id.NewULID()
+	if err != nil {
+		return err
+	}
+	scheduled.StatusID = statusID
+	scheduled.UpdatedAt = time.Now()
+	if err := p.db.UpdateByID(ctx, scheduled.ID, scheduled); err != nil {
+		return fmt.Errorf("error updating scheduled status %s before publishing it: %s", scheduled.ID, err)
+	}
+
+	if err := p.publishScheduledStatus(ctx, scheduled); err != nil {
+		p.retryScheduledStatus(ctx, scheduled, err)
+		return fmt.Errorf("error publishing scheduled status %s: %s", scheduled.ID, err)
+	}
+
+	if err := p.db.DeleteByID(ctx, scheduled.ID, &gtsmodel.ScheduledStatus{}); err != nil {
+		return fmt.Errorf("error deleting published scheduled status %s from db: %s", scheduled.ID, err)
+	}
+	return nil
+}
+
+// publishScheduledStatus creates a status from the given scheduled status, in the same way as if it had just been posted.
+func (p *processor) publishScheduledStatus(ctx context.Context, scheduled *gtsmodel.ScheduledStatus) error {
+	account := &gtsmodel.Account{}
+	if err := p.db.GetByID(ctx, scheduled.AccountID, account); err != nil {
+		return fmt.Errorf("error getting account %s: %s", scheduled.AccountID, err)
+	}
+
+	if !account.SuspendedAt.IsZero() {
+		return errors.New("account has been suspended")
+	}
+
+	// the application may have been removed in the meantime, in which case the status just won't have one
+	application := &gtsmodel.Application{}
+	if scheduled.ApplicationID != "" {
+		if err := p.db.GetByID(ctx, scheduled.ApplicationID, application); err != nil {
+			if _, ok := err.(db.ErrNoEntries); !ok {
+				return fmt.Errorf("error getting application %s: %s", scheduled.ApplicationID, err)
+			}
+		}
+	}
+
+	form := &apimodel.AdvancedStatusCreateForm{}
+	if err := json.Unmarshal([]byte(scheduled.Params), form); err != nil {
+		return fmt.Errorf("error parsing params: %s", err)
+	}
+
+	// the media stays attached to the scheduled status until the new status takes it over
+	if errWithCode := p.statusProcessor.CreateScheduled(ctx, account, application, form, scheduled.ID, scheduled.StatusID); errWithCode != nil {
+		return errWithCode
+	}
+
+	return nil
+}
+
+// retryScheduledStatus records that the given scheduled status failed to publish with the given error, so that publishing
+// it will be tried again after a while. Once it's failed too many times it's given up on, but it's kept along with its
+// media, so that the account can still see it and decide what to do with it.
+func (p *processor) retryScheduledStatus(ctx context.Context, scheduled *gtsmodel.ScheduledStatus, publishErr error) {
+	now := time.Now()
+	scheduled.Attempts = scheduled.Attempts + 1
+	scheduled.LastError = publishErr.Error()
+	scheduled.UpdatedAt = now
+	scheduled.NextAttemptAt = now.Add(scheduledStatusRetryBackoff * time.Duration(1<<(scheduled.Attempts-1)))
+	if scheduled.Attempts >= scheduledStatusMaxAttempts {
+		p.log.Infof("retryScheduledStatus: giving up on scheduled status %s after %d attempts", scheduled.ID, scheduled.Attempts)
+		scheduled.FailedAt = now
+	}
+
+	if err := p.db.UpdateByID(ctx, scheduled.ID, scheduled); err != nil {
+		p.log.Errorf("retryScheduledStatus: error updating scheduled status %s: %s", scheduled.ID, err)
+	}
+}
+
+// parseScheduledAt parses the given ISO 8601 time, making sure that it's far enough in the future.
+func parseScheduledAt(scheduledAt string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, scheduledAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("scheduled_at %s could not be parsed as an ISO 8601 datetime", scheduledAt)
+	}
+
+	if t.Before(time.Now().Add(scheduledStatusMinLead)) {
+		return time.Time{}, fmt.Errorf("scheduled_at must be at least %d minutes in the future", int(scheduledStatusMinLead.Minutes()))
+	}
+
+	return t, nil
+}
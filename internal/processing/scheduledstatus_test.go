@@ -0,0 +1,214 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type ScheduledStatusTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *ScheduledStatusTestSuite) authed() *oauth.Auth {
+	return &oauth.Auth{
+		Account:     suite.testAccounts["local_account_1"],
+		Application: suite.testApplications["application_1"],
+	}
+}
+
+// scheduleStatus schedules an unlisted, unfederated status with the unattached test media, so that
+// publishing it doesn't leave the processor retrying deliveries to the mock http client.
+func (suite *ScheduledStatusTestSuite) scheduleStatus(scheduledAt time.Time) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	federated := false
+	scheduled, errWithCode := suite.processor.ScheduledStatusCreate(context.Background(), suite.authed(), &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status:      "this is a status from the future",
+			MediaIDs:    []string{suite.testAttachments["local_account_1_unattached_1"].ID},
+			Visibility:  apimodel.VisibilityUnlisted,
+			ScheduledAt: scheduledAt.Format(time.RFC3339),
+		},
+		AdvancedVisibilityFlagsForm: apimodel.AdvancedVisibilityFlagsForm{
+			Federated: &federated,
+		},
+	})
+	return scheduled, errWithCode
+}
+
+func (suite *ScheduledStatusTestSuite) getAttachment() *gtsmodel.MediaAttachment {
+	a := &gtsmodel.MediaAttachment{}
+	suite.NoError(suite.db.GetByID(context.Background(), suite.testAttachments["local_account_1_unattached_1"].ID, a))
+	return a
+}
+
+func (suite *ScheduledStatusTestSuite) TestScheduleStatus() {
+	ctx := context.Background()
+	scheduledAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	scheduled, errWithCode := suite.scheduleStatus(scheduledAt)
+	suite.Nil(errWithCode)
+	suite.Equal("this is a status from the future", scheduled.Params.Text)
+	suite.Len(scheduled.MediaAttachments, 1)
+
+	// the media should be held by the scheduled status
+	suite.Equal(scheduled.ID, suite.getAttachment().ScheduledStatusID)
+
+	resp, errWithCode := suite.processor.ScheduledStatusesGet(ctx, suite.authed(), "", "", 20)
+	suite.Nil(errWithCode)
+	suite.Len(resp.ScheduledStatuses, 1)
+
+	// nobody else can see it
+	_, errWithCode = suite.processor.ScheduledStatusGet(ctx, &oauth.Auth{Account: suite.testAccounts["local_account_2"]}, scheduled.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+
+	// reschedule
+	later := scheduledAt.Add(time.Hour)
+	updated, errWithCode := suite.processor.ScheduledStatusUpdate(ctx, suite.authed(), scheduled.ID, &apimodel.ScheduledStatusUpdateRequest{
+		ScheduledAt: later.Format(time.RFC3339),
+	})
+	suite.Nil(errWithCode)
+	suite.Equal(later.UTC().Format(time.RFC3339), updated.ScheduledAt)
+
+	// cancel, which should release the media again
+	suite.Nil(suite.processor.ScheduledStatusDelete(ctx, suite.authed(), scheduled.ID))
+	suite.Empty(suite.getAttachment().ScheduledStatusID)
+	_, errWithCode = suite.processor.ScheduledStatusGet(ctx, suite.authed(), scheduled.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *ScheduledStatusTestSuite) TestScheduleStatusTooSoon() {
+	_, errWithCode := suite.scheduleStatus(time.Now().Add(time.Minute))
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+
+	// the media shouldn't have been touched
+	suite.Empty(suite.getAttachment().ScheduledStatusID)
+}
+
+func (suite *ScheduledStatusTestSuite) TestPublishScheduledStatus() {
+	ctx := context.Background()
+
+	scheduled, errWithCode := suite.scheduleStatus(time.Now().Add(time.Hour))
+	suite.Nil(errWithCode)
+
+	// pretend that the scheduled time has passed while the server was down
+	suite.NoError(suite.db.UpdateOneByID(ctx, scheduled.ID, "scheduled_at", time.Now().Add(-time.Minute), &gtsmodel.ScheduledStatus{}))
+
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+
+	// the media should end up attached to the newly published status, and the scheduled status should be gone
+	suite.Eventually(func() bool {
+		return suite.getAttachment().StatusID != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	suite.Eventually(func() bool {
+		err := suite.db.GetByID(ctx, scheduled.ID, &gtsmodel.ScheduledStatus{})
+		_, ok := err.(db.ErrNoEntries)
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	a := suite.getAttachment()
+	suite.Empty(a.ScheduledStatusID)
+	status := &gtsmodel.Status{}
+	suite.NoError(suite.db.GetByID(ctx, a.StatusID, status))
+	suite.Equal("this is a status from the future", status.Text)
+	suite.Equal(suite.testAccounts["local_account_1"].ID, status.AccountID)
+}
+
+func (suite *ScheduledStatusTestSuite) TestPublishScheduledStatusFailed() {
+	ctx := context.Background()
+
+	scheduled, errWithCode := suite.scheduleStatus(time.Now().Add(time.Hour))
+	suite.Nil(errWithCode)
+	suite.NoError(suite.db.UpdateOneByID(ctx, scheduled.ID, "scheduled_at", time.Now().Add(-time.Minute), &gtsmodel.ScheduledStatus{}))
+
+	// publishing can't work while the account is suspended
+	suite.NoError(suite.db.UpdateOneByID(ctx, suite.testAccounts["local_account_1"].ID, "suspended_at", time.Now(), &gtsmodel.Account{}))
+
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+
+	// the scheduled status should be kept around to try again, still holding on to its media
+	suite.Eventually(func() bool {
+		s := &gtsmodel.ScheduledStatus{}
+		if err := suite.db.GetByID(ctx, scheduled.ID, s); err != nil {
+			return false
+		}
+		return s.Attempts == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	s := &gtsmodel.ScheduledStatus{}
+	suite.NoError(suite.db.GetByID(ctx, scheduled.ID, s))
+	suite.Equal("account has been suspended", s.LastError)
+	suite.True(s.FailedAt.IsZero())
+
+	// it shouldn't be tried again straight away
+	suite.True(s.NextAttemptAt.After(time.Now()))
+	due, err := suite.db.GetDueScheduledStatuses(ctx, 0)
+	suite.Nil(due)
+	suite.IsType(db.ErrNoEntries{}, err)
+
+	a := suite.getAttachment()
+	suite.Equal(scheduled.ID, a.ScheduledStatusID)
+	suite.Empty(a.StatusID)
+}
+
+func (suite *ScheduledStatusTestSuite) TestPublishScheduledStatusAlreadyPublished() {
+	ctx := context.Background()
+
+	scheduled, errWithCode := suite.scheduleStatus(time.Now().Add(time.Hour))
+	suite.Nil(errWithCode)
+	suite.NoError(suite.db.UpdateOneByID(ctx, scheduled.ID, "scheduled_at", time.Now().Add(-time.Minute), &gtsmodel.ScheduledStatus{}))
+
+	// pretend that the scheduled status was published last time, but couldn't be removed afterwards
+	published := suite.testStatuses["local_account_1_status_1"]
+	suite.NoError(suite.db.UpdateOneByID(ctx, scheduled.ID, "status_id", published.ID, &gtsmodel.ScheduledStatus{}))
+
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+
+	// the scheduled status should just be removed, without publishing it again
+	suite.Eventually(func() bool {
+		err := suite.db.GetByID(ctx, scheduled.ID, &gtsmodel.ScheduledStatus{})
+		_, ok := err.(db.ErrNoEntries)
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err := suite.db.GetWhere(ctx, []db.Where{{Key: "text", Value: "this is a status from the future"}}, &gtsmodel.Status{})
+	suite.IsType(db.ErrNoEntries{}, err)
+}
+
+func TestScheduledStatusTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduledStatusTestSuite))
+}
@@ -19,6 +19,7 @@
 package processing
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -34,7 +35,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
-func (p *processor) SearchGet(authed *oauth.Auth, searchQuery *apimodel.SearchQuery) (*apimodel.SearchResult, gtserror.WithCode) {
+func (p *processor) SearchGet(ctx context.Context, authed *oauth.Auth, searchQuery *apimodel.SearchQuery) (*apimodel.SearchResult, gtserror.WithCode) {
 	l := p.log.WithFields(logrus.Fields{
 		"func":  "SearchGet",
 		"query": searchQuery.Query,
@@ -56,7 +57,7 @@ func (p *processor) SearchGet(authed *oauth.Auth, searchQuery *apimodel.SearchQu
 	// check if the query is something like @whatever_username@example.org -- this means it's a remote account
 	if !foundOne && util.IsMention(searchQuery.Query) {
 		l.Debug("search term is a mention, looking it up...")
-		foundAccount, err := p.searchAccountByMention(authed, searchQuery.Query, searchQuery.Resolve)
+		foundAccount, err := p.searchAccountByMention(ctx, authed, searchQuery.Query, searchQuery.Resolve)
 		if err == nil && foundAccount != nil {
 			foundAccounts = append(foundAccounts, foundAccount)
 			foundOne = true
@@ -67,14 +68,14 @@ func (p *processor) SearchGet(authed *oauth.Auth, searchQuery *apimodel.SearchQu
 	// check if the query is a URI and just do a lookup for that, straight up
 	if uri, err := url.Parse(query); err == nil && !foundOne {
 		// 1. check if it's a status
-		if foundStatus, err := p.searchStatusByURI(authed, uri, searchQuery.Resolve); err == nil && foundStatus != nil {
+		if foundStatus, err := p.searchStatusByURI(ctx, authed, uri, searchQuery.Resolve); err == nil && foundStatus != nil {
 			foundStatuses = append(foundStatuses, foundStatus)
 			foundOne = true
 			l.Debug("got a status by searching by URI")
 		}
 
 		// 2. check if it's an account
-		if foundAccount, err := p.searchAccountByURI(authed, uri, searchQuery.Resolve); err == nil && foundAccount != nil {
+		if foundAccount, err := p.searchAccountByURI(ctx, authed, uri, searchQuery.Resolve); err == nil && foundAccount != nil {
 			foundAccounts = append(foundAccounts, foundAccount)
 			foundOne = true
 			l.Debug("got an account by searching by URI")
@@ -92,9 +93,9 @@ func (p *processor) SearchGet(authed *oauth.Auth, searchQuery *apimodel.SearchQu
 	*/
 	for _, foundAccount := range foundAccounts {
 		// make sure there's no block in either direction between the account and the requester
-		if blocked, err := p.db.Blocked(authed.Account.ID, foundAccount.ID); err == nil && !blocked {
+		if blocked, err := p.db.Blocked(ctx, authed.Account.ID, foundAccount.ID); err == nil && !blocked {
 			// all good, convert it and add it to the results
-			if acctMasto, err := p.tc.AccountToMastoPublic(foundAccount); err == nil && acctMasto != nil {
+			if acctMasto, err := p.tc.AccountToMastoPublic(ctx, foundAccount); err == nil && acctMasto != nil {
 				results.Accounts = append(results.Accounts, *acctMasto)
 			}
 		}
@@ -102,15 +103,15 @@ func (p *processor) SearchGet(authed *oauth.Auth, searchQuery *apimodel.SearchQu
 
 	for _, foundStatus := range foundStatuses {
 		statusOwner := &gtsmodel.Account{}
-		if err := p.db.GetByID(foundStatus.AccountID, statusOwner); err != nil {
+		if err := p.db.GetByID(ctx, foundStatus.AccountID, statusOwner); err != nil {
 			continue
 		}
 
-		if visible, err := p.filter.StatusVisible(foundStatus, authed.Account); !visible || err != nil {
+		if visible, err := p.filter.StatusVisible(ctx, foundStatus, authed.Account); !visible || err != nil {
 			continue
 		}
 
-		statusMasto, err := p.tc.StatusToMasto(foundStatus, authed.Account)
+		statusMasto, err := p.tc.StatusToMasto(ctx, foundStatus, authed.Account)
 		if err != nil {
 			continue
 		}
@@ -121,20 +122,20 @@ func (p *processor) SearchGet(authed *oauth.Auth, searchQuery *apimodel.SearchQu
 	return results, nil
 }
 
-func (p *processor) searchStatusByURI(authed *oauth.Auth, uri *url.URL, resolve bool) (*gtsmodel.Status, error) {
+func (p *processor) searchStatusByURI(ctx context.Context, authed *oauth.Auth, uri *url.URL, resolve bool) (*gtsmodel.Status, error) {
 
 	maybeStatus := &gtsmodel.Status{}
-	if err := p.db.GetWhere([]db.Where{{Key: "uri", Value: uri.String(), CaseInsensitive: true}}, maybeStatus); err == nil {
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: uri.String(), CaseInsensitive: true}}, maybeStatus); err == nil {
 		// we have it and it's a status
 		return maybeStatus, nil
-	} else if err := p.db.GetWhere([]db.Where{{Key: "url", Value: uri.String(), CaseInsensitive: true}}, maybeStatus); err == nil {
+	} else if err := p.db.GetWhere(ctx, []db.Where{{Key: "url", Value: uri.String(), CaseInsensitive: true}}, maybeStatus); err == nil {
 		// we have it and it's a status
 		return maybeStatus, nil
 	}
 
 	// we don't have it locally so dereference it if we're allowed to
 	if resolve {
-		statusable, err := p.federator.DereferenceRemoteStatus(authed.Account.Username, uri)
+		statusable, err := p.federator.DereferenceRemoteStatus(ctx, authed.Account.Username, uri)
 		if err == nil {
 			// it IS a status!
 
@@ -152,7 +153,7 @@ func (p *processor) searchStatusByURI(authed *oauth.Auth, uri *url.URL, resolve
 			}
 
 			// make sure the status owner exists in the db by searching for it
-			_, err := p.searchAccountByURI(authed, statusOwnerURI, resolve)
+			_, err := p.searchAccountByURI(ctx, authed, statusOwnerURI, resolve)
 			if err != nil {
 				return nil, err
 			}
@@ -160,7 +161,7 @@ func (p *processor) searchStatusByURI(authed *oauth.Auth, uri *url.URL, resolve
 			// we have the status owner, we have the dereferenced status, so now we should finish dereferencing the status properly
 
 			// first turn it into a gtsmodel.Status
-			status, err := p.tc.ASStatusToStatus(statusable)
+			status, err := p.tc.ASStatusToStatus(ctx, statusable)
 			if err != nil {
 				return nil, gtserror.NewErrorInternalError(err)
 			}
@@ -171,17 +172,17 @@ func (p *processor) searchStatusByURI(authed *oauth.Auth, uri *url.URL, resolve
 			}
 			status.ID = statusID
 
-			if err := p.db.Put(status); err != nil {
+			if err := p.db.Put(ctx, status); err != nil {
 				return nil, fmt.Errorf("error putting status in the db: %s", err)
 			}
 
 			// properly dereference everything in the status (media attachments etc)
-			if err := p.federator.DereferenceStatusFields(status, authed.Account.Username); err != nil {
+			if err := p.federator.DereferenceStatusFields(ctx, status, authed.Account.Username); err != nil {
 				return nil, fmt.Errorf("error dereferencing status fields: %s", err)
 			}
 
 			// update with the nicely dereferenced status
-			if err := p.db.UpdateByID(status.ID, status); err != nil {
+			if err := p.db.UpdateByID(ctx, status.ID, status); err != nil {
 				return nil, fmt.Errorf("error updating status in the db: %s", err)
 			}
 
@@ -191,24 +192,24 @@ func (p *processor) searchStatusByURI(authed *oauth.Auth, uri *url.URL, resolve
 	return nil, nil
 }
 
-func (p *processor) searchAccountByURI(authed *oauth.Auth, uri *url.URL, resolve bool) (*gtsmodel.Account, error) {
+func (p *processor) searchAccountByURI(ctx context.Context, authed *oauth.Auth, uri *url.URL, resolve bool) (*gtsmodel.Account, error) {
 	maybeAccount := &gtsmodel.Account{}
-	if err := p.db.GetWhere([]db.Where{{Key: "uri", Value: uri.String(), CaseInsensitive: true}}, maybeAccount); err == nil {
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: uri.String(), CaseInsensitive: true}}, maybeAccount); err == nil {
 		// we have it and it's an account
 		return maybeAccount, nil
-	} else if err = p.db.GetWhere([]db.Where{{Key: "url", Value: uri.String(), CaseInsensitive: true}}, maybeAccount); err == nil {
+	} else if err = p.db.GetWhere(ctx, []db.Where{{Key: "url", Value: uri.String(), CaseInsensitive: true}}, maybeAccount); err == nil {
 		// we have it and it's an account
 		return maybeAccount, nil
 	}
 	if resolve {
 		// we don't have it locally so try and dereference it
-		accountable, err := p.federator.DereferenceRemoteAccount(authed.Account.Username, uri)
+		accountable, err := p.federator.DereferenceRemoteAccount(ctx, authed.Account.Username, uri)
 		if err != nil {
 			return nil, fmt.Errorf("searchAccountByURI: error dereferencing account with uri %s: %s", uri.String(), err)
 		}
 
 		// it IS an account!
-		account, err := p.tc.ASRepresentationToAccount(accountable, false)
+		account, err := p.tc.ASRepresentationToAccount(ctx, accountable, false)
 		if err != nil {
 			return nil, fmt.Errorf("searchAccountByURI: error dereferencing account with uri %s: %s", uri.String(), err)
 		}
@@ -219,11 +220,11 @@ func (p *processor) searchAccountByURI(authed *oauth.Auth, uri *url.URL, resolve
 		}
 		account.ID = accountID
 
-		if err := p.db.Put(account); err != nil {
+		if err := p.db.Put(ctx, account); err != nil {
 			return nil, fmt.Errorf("searchAccountByURI: error inserting account with uri %s: %s", uri.String(), err)
 		}
 
-		if err := p.federator.DereferenceAccountFields(account, authed.Account.Username, false); err != nil {
+		if err := p.federator.DereferenceAccountFields(ctx, account, authed.Account.Username, false); err != nil {
 			return nil, fmt.Errorf("searchAccountByURI: error further dereferencing account with uri %s: %s", uri.String(), err)
 		}
 
@@ -232,7 +233,7 @@ func (p *processor) searchAccountByURI(authed *oauth.Auth, uri *url.URL, resolve
 	return nil, nil
 }
 
-func (p *processor) searchAccountByMention(authed *oauth.Auth, mention string, resolve bool) (*gtsmodel.Account, error) {
+func (p *processor) searchAccountByMention(ctx context.Context, authed *oauth.Auth, mention string, resolve bool) (*gtsmodel.Account, error) {
 	// query is for a remote account
 	username, domain, err := util.ExtractMentionParts(mention)
 	if err != nil {
@@ -242,7 +243,7 @@ func (p *processor) searchAccountByMention(authed *oauth.Auth, mention string, r
 	// if it's a local account we can skip a whole bunch of stuff
 	maybeAcct := &gtsmodel.Account{}
 	if domain == p.config.Host {
-		if err = p.db.GetLocalAccountByUsername(username, maybeAcct); err != nil {
+		if err = p.db.GetLocalAccountByUsername(ctx, username, maybeAcct); err != nil {
 			return nil, fmt.Errorf("searchAccountByMention: error getting local account by username: %s", err)
 		}
 		return maybeAcct, nil
@@ -253,7 +254,7 @@ func (p *processor) searchAccountByMention(authed *oauth.Auth, mention string, r
 		{Key: "username", Value: username, CaseInsensitive: true},
 		{Key: "domain", Value: domain, CaseInsensitive: true},
 	}
-	err = p.db.GetWhere(where, maybeAcct)
+	err = p.db.GetWhere(ctx, where, maybeAcct)
 	if err == nil {
 		// we've got it stored locally already!
 		return maybeAcct, nil
@@ -269,21 +270,21 @@ func (p *processor) searchAccountByMention(authed *oauth.Auth, mention string, r
 		// we're allowed to resolve it so let's try
 
 		// first we need to webfinger the remote account to convert the username and domain into the activitypub URI for the account
-		acctURI, err := p.federator.FingerRemoteAccount(authed.Account.Username, username, domain)
+		acctURI, err := p.federator.FingerRemoteAccount(ctx, authed.Account.Username, username, domain)
 		if err != nil {
 			// something went wrong doing the webfinger lookup so we can't process the request
 			return nil, fmt.Errorf("searchAccountByMention: error fingering remote account with username %s and domain %s: %s", username, domain, err)
 		}
 
 		// dereference the account based on the URI we retrieved from the webfinger lookup
-		accountable, err := p.federator.DereferenceRemoteAccount(authed.Account.Username, acctURI)
+		accountable, err := p.federator.DereferenceRemoteAccount(ctx, authed.Account.Username, acctURI)
 		if err != nil {
 			// something went wrong doing the dereferencing so we can't process the request
 			return nil, fmt.Errorf("searchAccountByMention: error dereferencing remote account with uri %s: %s", acctURI.String(), err)
 		}
 
 		// convert the dereferenced account to the gts model of that account
-		foundAccount, err := p.tc.ASRepresentationToAccount(accountable, false)
+		foundAccount, err := p.tc.ASRepresentationToAccount(ctx, accountable, false)
 		if err != nil {
 			// something went wrong doing the conversion to a gtsmodel.Account so we can't process the request
 			return nil, fmt.Errorf("searchAccountByMention: error converting account with uri %s: %s", acctURI.String(), err)
@@ -296,12 +297,12 @@ func (p *processor) searchAccountByMention(authed *oauth.Auth, mention string, r
 		foundAccount.ID = foundAccountID
 
 		// put this new account in our database
-		if err := p.db.Put(foundAccount); err != nil {
+		if err := p.db.Put(ctx, foundAccount); err != nil {
 			return nil, fmt.Errorf("searchAccountByMention: error inserting account with uri %s: %s", acctURI.String(), err)
 		}
 
 		// properly dereference all the fields on the account immediately
-		if err := p.federator.DereferenceAccountFields(foundAccount, authed.Account.Username, true); err != nil {
+		if err := p.federator.DereferenceAccountFields(ctx, foundAccount, authed.Account.Username, true); err != nil {
 			return nil, fmt.Errorf("searchAccountByMention: error dereferencing fields on account with uri %s: %s", acctURI.String(), err)
 		}
 	}
@@ -0,0 +1,66 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+func (p *processor) Bookmark(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	l := p.log.WithField("func", "StatusBookmark")
+	l.Tracef("going to search for target status %s", targetStatusID)
+	targetStatus := &gtsmodel.Status{}
+	if err := p.db.GetByID(ctx, targetStatusID, targetStatus); err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching status %s: %s", targetStatusID, err))
+	}
+
+	l.Trace("going to see if status is visible")
+	visible, err := p.filter.StatusVisible(ctx, targetStatus, account)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error seeing if status %s is visible: %s", targetStatus.ID, err))
+	}
+
+	if !visible {
+		return nil, gtserror.NewErrorNotFound(errors.New("status is not visible"))
+	}
+
+	// first check if the status is already bookmarked, if so we don't need to do anything
+	err = p.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: targetStatus.ID}, {Key: "account_id", Value: account.ID}}, &gtsmodel.StatusBookmark{})
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching existing bookmark from database: %s", err))
+		}
+
+		thisBookmarkID, err := id.NewULID()
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		// we need to create a new bookmark in the database
+		gtsBookmark := &gtsmodel.StatusBookmark{
+			ID:              thisBookmarkID,
+			AccountID:       account.ID,
+			TargetAccountID: targetStatus.AccountID,
+			StatusID:        targetStatus.ID,
+		}
+
+		if err := p.db.Put(ctx, gtsBookmark); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting bookmark in database: %s", err))
+		}
+	}
+
+	// bookmarks are private, so unlike faves there's nothing to federate
+
+	mastoStatus, err := p.tc.StatusToMasto(ctx, targetStatus, account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", targetStatus.ID, err))
+	}
+
+	return mastoStatus, nil
+}
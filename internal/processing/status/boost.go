@@ -1,6 +1,7 @@
 package status
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -9,23 +10,23 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (p *processor) Boost(account *gtsmodel.Account, application *gtsmodel.Application, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+func (p *processor) Boost(ctx context.Context, account *gtsmodel.Account, application *gtsmodel.Application, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
 	l := p.log.WithField("func", "StatusBoost")
 
 	l.Tracef("going to search for target status %s", targetStatusID)
 	targetStatus := &gtsmodel.Status{}
-	if err := p.db.GetByID(targetStatusID, targetStatus); err != nil {
+	if err := p.db.GetByID(ctx, targetStatusID, targetStatus); err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching status %s: %s", targetStatusID, err))
 	}
 
 	l.Tracef("going to search for target account %s", targetStatus.AccountID)
 	targetAccount := &gtsmodel.Account{}
-	if err := p.db.GetByID(targetStatus.AccountID, targetAccount); err != nil {
+	if err := p.db.GetByID(ctx, targetStatus.AccountID, targetAccount); err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching target account %s: %s", targetStatus.AccountID, err))
 	}
 
 	l.Trace("going to see if status is visible")
-	visible, err := p.filter.StatusVisible(targetStatus, account)
+	visible, err := p.filter.StatusVisible(ctx, targetStatus, account)
 	if err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error seeing if status %s is visible: %s", targetStatus.ID, err))
 	}
@@ -50,7 +51,7 @@ func (p *processor) Boost(account *gtsmodel.Account, application *gtsmodel.Appli
 	boostWrapperStatus.GTSBoostedAccount = targetAccount
 
 	// put the boost in the database
-	if err := p.db.Put(boostWrapperStatus); err != nil {
+	if err := p.db.Put(ctx, boostWrapperStatus); err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
@@ -64,7 +65,7 @@ func (p *processor) Boost(account *gtsmodel.Account, application *gtsmodel.Appli
 	}
 
 	// return the frontend representation of the new status to the submitter
-	mastoStatus, err := p.tc.StatusToMasto(boostWrapperStatus, account)
+	mastoStatus, err := p.tc.StatusToMasto(ctx, boostWrapperStatus, account)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", targetStatus.ID, err))
 	}
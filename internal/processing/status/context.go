@@ -1,6 +1,7 @@
 package status
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -10,7 +11,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (p *processor) Context(account *gtsmodel.Account, targetStatusID string) (*apimodel.Context, gtserror.WithCode) {
+func (p *processor) Context(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Context, gtserror.WithCode) {
 
 	context := &apimodel.Context{
 		Ancestors:   []apimodel.Status{},
@@ -18,14 +19,14 @@ func (p *processor) Context(account *gtsmodel.Account, targetStatusID string) (*
 	}
 
 	targetStatus := &gtsmodel.Status{}
-	if err := p.db.GetByID(targetStatusID, targetStatus); err != nil {
+	if err := p.db.GetByID(ctx, targetStatusID, targetStatus); err != nil {
 		if _, ok := err.(db.ErrNoEntries); ok {
 			return nil, gtserror.NewErrorNotFound(err)
 		}
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	visible, err := p.filter.StatusVisible(targetStatus, account)
+	visible, err := p.filter.StatusVisible(ctx, targetStatus, account)
 	if err != nil {
 		return nil, gtserror.NewErrorNotFound(err)
 	}
@@ -33,14 +34,22 @@ func (p *processor) Context(account *gtsmodel.Account, targetStatusID string) (*
 		return nil, gtserror.NewErrorForbidden(fmt.Errorf("account with id %s does not have permission to view status %s", account.ID, targetStatusID))
 	}
 
-	parents, err := p.db.StatusParents(targetStatus)
+	filters, err := p.filter.KeywordFilters(ctx, account, gtsmodel.FilterContextThread)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	parents, err := p.db.StatusParents(ctx, targetStatus)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
 	for _, status := range parents {
-		if v, err := p.filter.StatusVisible(status, account); err == nil && v {
-			mastoStatus, err := p.tc.StatusToMasto(status, account)
+		if v, err := p.filter.StatusVisible(ctx, status, account); err == nil && v {
+			if filtered, err := p.filter.StatusKeywordFiltered(ctx, status, filters); err != nil || filtered {
+				continue
+			}
+			mastoStatus, err := p.tc.StatusToMasto(ctx, status, account)
 			if err == nil {
 				context.Ancestors = append(context.Ancestors, *mastoStatus)
 			}
@@ -51,14 +60,17 @@ func (p *processor) Context(account *gtsmodel.Account, targetStatusID string) (*
 		return context.Ancestors[i].ID < context.Ancestors[j].ID
 	})
 
-	children, err := p.db.StatusChildren(targetStatus)
+	children, err := p.db.StatusChildren(ctx, targetStatus)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
 	for _, status := range children {
-		if v, err := p.filter.StatusVisible(status, account); err == nil && v {
-			mastoStatus, err := p.tc.StatusToMasto(status, account)
+		if v, err := p.filter.StatusVisible(ctx, status, account); err == nil && v {
+			if filtered, err := p.filter.StatusKeywordFiltered(ctx, status, filters); err != nil || filtered {
+				continue
+			}
+			mastoStatus, err := p.tc.StatusToMasto(ctx, status, account)
 			if err == nil {
 				context.Descendants = append(context.Descendants, *mastoStatus)
 			}
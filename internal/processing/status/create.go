@@ -1,6 +1,7 @@
 package status
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -11,11 +12,38 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
-func (p *processor) Create(account *gtsmodel.Account, application *gtsmodel.Application, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, gtserror.WithCode) {
-	uris := util.GenerateURIsForAccount(account.Username, p.config.Protocol, p.config.Host)
-	thisStatusID, err := id.NewULID()
+func (p *processor) Create(ctx context.Context, account *gtsmodel.Account, application *gtsmodel.Application, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, gtserror.WithCode) {
+	newStatus, errWithCode := p.create(ctx, account, application, form, "", "")
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// return the frontend representation of the new status to the submitter
+	mastoStatus, err := p.tc.StatusToMasto(ctx, newStatus, account)
 	if err != nil {
-		return nil, gtserror.NewErrorInternalError(err)
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", newStatus.ID, err))
+	}
+
+	return mastoStatus, nil
+}
+
+func (p *processor) CreateScheduled(ctx context.Context, account *gtsmodel.Account, application *gtsmodel.Application, form *apimodel.AdvancedStatusCreateForm, scheduledStatusID string, statusID string) gtserror.WithCode {
+	_, errWithCode := p.create(ctx, account, application, form, scheduledStatusID, statusID)
+	return errWithCode
+}
+
+// create creates and stores a new status from the given form, and sends it off for async processing.
+// If scheduledStatusID is set, media attachments that are attached to that scheduled status can be used.
+// If statusID is set, the new status gets that ID, otherwise a new one is generated.
+func (p *processor) create(ctx context.Context, account *gtsmodel.Account, application *gtsmodel.Application, form *apimodel.AdvancedStatusCreateForm, scheduledStatusID string, statusID string) (*gtsmodel.Status, gtserror.WithCode) {
+	uris := util.GenerateURIsForAccount(account.Username, p.config.Protocol, p.config.Host)
+	thisStatusID := statusID
+	if thisStatusID == "" {
+		var err error
+		thisStatusID, err = id.NewULID()
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
 	}
 	thisStatusURI := fmt.Sprintf("%s/%s", uris.StatusesURI, thisStatusID)
 	thisStatusURL := fmt.Sprintf("%s/%s", uris.StatusesURL, thisStatusID)
@@ -38,15 +66,20 @@ func (p *processor) Create(account *gtsmodel.Account, application *gtsmodel.Appl
 	}
 
 	// check if replyToID is ok
-	if err := p.processReplyToID(form, account.ID, newStatus); err != nil {
+	if err := p.processReplyToID(ctx, form, account.ID, newStatus); err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
 	// check if mediaIDs are ok
-	if err := p.processMediaIDs(form, account.ID, newStatus); err != nil {
+	if err := p.processMediaIDs(ctx, form, account.ID, scheduledStatusID, newStatus); err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
+	// check if the poll is ok
+	if errWithCode := p.processPoll(form, account.ID, newStatus); errWithCode != nil {
+		return nil, errWithCode
+	}
+
 	// check if visibility settings are ok
 	if err := p.processVisibility(form, account.Privacy, newStatus); err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
@@ -58,32 +91,40 @@ func (p *processor) Create(account *gtsmodel.Account, application *gtsmodel.Appl
 	}
 
 	// handle mentions
-	if err := p.processMentions(form, account.ID, newStatus); err != nil {
+	if err := p.processMentions(ctx, form, account.ID, newStatus); err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	if err := p.processTags(form, account.ID, newStatus); err != nil {
+	if err := p.processTags(ctx, form, account.ID, newStatus); err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	if err := p.processEmojis(form, account.ID, newStatus); err != nil {
+	if err := p.processEmojis(ctx, form, account.ID, newStatus); err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	if err := p.processContent(form, account.ID, newStatus); err != nil {
+	if err := p.processContent(ctx, form, account.ID, newStatus); err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
 	// put the new status in the database, generating an ID for it in the process
-	if err := p.db.Put(newStatus); err != nil {
+	if err := p.db.Put(ctx, newStatus); err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
+	// put the poll in the database now that its status exists
+	if newStatus.GTSPoll != nil {
+		if err := p.db.Put(ctx, newStatus.GTSPoll); err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
 	// change the status ID of the media attachments to the new status
 	for _, a := range newStatus.GTSMediaAttachments {
 		a.StatusID = newStatus.ID
+		a.ScheduledStatusID = ""
 		a.UpdatedAt = time.Now()
-		if err := p.db.UpdateByID(a.ID, a); err != nil {
+		if err := p.db.UpdateByID(ctx, a.ID, a); err != nil {
 			return nil, gtserror.NewErrorInternalError(err)
 		}
 	}
@@ -96,11 +137,5 @@ func (p *processor) Create(account *gtsmodel.Account, application *gtsmodel.Appl
 		OriginAccount:  account,
 	}
 
-	// return the frontend representation of the new status to the submitter
-	mastoStatus, err := p.tc.StatusToMasto(newStatus, account)
-	if err != nil {
-		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", newStatus.ID, err))
-	}
-
-	return mastoStatus, nil
+	return newStatus, nil
 }
@@ -1,6 +1,7 @@
 package status
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -10,11 +11,11 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (p *processor) Delete(account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+func (p *processor) Delete(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
 	l := p.log.WithField("func", "StatusDelete")
 	l.Tracef("going to search for target status %s", targetStatusID)
 	targetStatus := &gtsmodel.Status{}
-	if err := p.db.GetByID(targetStatusID, targetStatus); err != nil {
+	if err := p.db.GetByID(ctx, targetStatusID, targetStatus); err != nil {
 		if _, ok := err.(db.ErrNoEntries); !ok {
 			return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching status %s: %s", targetStatusID, err))
 		}
@@ -29,17 +30,17 @@ func (p *processor) Delete(account *gtsmodel.Account, targetStatusID string) (*a
 	var boostOfStatus *gtsmodel.Status
 	if targetStatus.BoostOfID != "" {
 		boostOfStatus = &gtsmodel.Status{}
-		if err := p.db.GetByID(targetStatus.BoostOfID, boostOfStatus); err != nil {
+		if err := p.db.GetByID(ctx, targetStatus.BoostOfID, boostOfStatus); err != nil {
 			return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching boosted status %s: %s", targetStatus.BoostOfID, err))
 		}
 	}
 
-	mastoStatus, err := p.tc.StatusToMasto(targetStatus, account)
+	mastoStatus, err := p.tc.StatusToMasto(ctx, targetStatus, account)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", targetStatus.ID, err))
 	}
 
-	if err := p.db.DeleteByID(targetStatus.ID, &gtsmodel.Status{}); err != nil {
+	if err := p.db.DeleteByID(ctx, targetStatus.ID, &gtsmodel.Status{}); err != nil {
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error deleting status from the database: %s", err))
 	}
 
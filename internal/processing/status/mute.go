@@ -0,0 +1,92 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+func (p *processor) Mute(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	l := p.log.WithField("func", "StatusMute")
+	l.Tracef("going to search for target status %s", targetStatusID)
+	targetStatus := &gtsmodel.Status{}
+	if err := p.db.GetByID(ctx, targetStatusID, targetStatus); err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching status %s: %s", targetStatusID, err))
+	}
+
+	l.Trace("going to see if status is visible")
+	visible, err := p.filter.StatusVisible(ctx, targetStatus, account)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error seeing if status %s is visible: %s", targetStatus.ID, err))
+	}
+
+	if !visible {
+		return nil, gtserror.NewErrorNotFound(errors.New("status is not visible"))
+	}
+
+	thread, err := p.thread(ctx, targetStatus)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// mute every status in the thread that isn't muted already; replies that come in later
+	// inherit the mute from the status they reply to when they're processed
+	for _, s := range thread {
+		err := p.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: s.ID}, {Key: "account_id", Value: account.ID}}, &gtsmodel.StatusMute{})
+		if err == nil {
+			continue
+		}
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching existing status mute from database: %s", err))
+		}
+
+		thisMuteID, err := id.NewULID()
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		gtsMute := &gtsmodel.StatusMute{
+			ID:              thisMuteID,
+			AccountID:       account.ID,
+			TargetAccountID: s.AccountID,
+			StatusID:        s.ID,
+		}
+
+		if err := p.db.Put(ctx, gtsMute); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting status mute in database: %s", err))
+		}
+	}
+
+	// status mutes are private, so there's nothing to federate
+
+	mastoStatus, err := p.tc.StatusToMasto(ctx, targetStatus, account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", targetStatus.ID, err))
+	}
+
+	return mastoStatus, nil
+}
+
+// thread returns the given status along with all of its ancestors and descendants.
+func (p *processor) thread(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.Status, error) {
+	parents, err := p.db.StatusParents(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("error getting parents of status %s: %s", status.ID, err)
+	}
+
+	children, err := p.db.StatusChildren(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("error getting children of status %s: %s", status.ID, err)
+	}
+
+	thread := []*gtsmodel.Status{status}
+	thread = append(thread, parents...)
+	thread = append(thread, children...)
+	return thread, nil
+}
@@ -0,0 +1,69 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (p *processor) Pin(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	l := p.log.WithField("func", "StatusPin")
+	l.Tracef("going to search for target status %s", targetStatusID)
+	targetStatus := &gtsmodel.Status{}
+	if err := p.db.GetByID(ctx, targetStatusID, targetStatus); err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching status %s: %s", targetStatusID, err))
+	}
+
+	// only the owner of a status can pin it to their profile
+	if targetStatus.AccountID != account.ID {
+		return nil, gtserror.NewErrorNotFound(errors.New("status does not belong to account"))
+	}
+
+	if targetStatus.BoostOfID != "" {
+		return nil, gtserror.NewErrorBadRequest(errors.New("boosts cannot be pinned"), "boosts cannot be pinned")
+	}
+
+	// pins are shown publicly on the profile, so we don't allow statuses with a narrower audience than that
+	if targetStatus.Visibility != gtsmodel.VisibilityPublic && targetStatus.Visibility != gtsmodel.VisibilityUnlocked {
+		return nil, gtserror.NewErrorBadRequest(errors.New("only public or unlisted statuses can be pinned"), "only public or unlisted statuses can be pinned")
+	}
+
+	if !targetStatus.Pinned {
+		pinned, err := p.db.GetStatusesForAccount(ctx, account.ID, 0, false, "", true, false)
+		if err != nil {
+			if _, ok := err.(db.ErrNoEntries); !ok {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching pinned statuses from database: %s", err))
+			}
+		}
+
+		if len(pinned) >= p.config.StatusesConfig.MaxPinned {
+			err := fmt.Errorf("pin limit of %d statuses reached", p.config.StatusesConfig.MaxPinned)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+
+		if err := p.db.UpdateOneByID(ctx, targetStatus.ID, "pinned", true, &gtsmodel.Status{}); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error pinning status in database: %s", err))
+		}
+		targetStatus.Pinned = true
+
+		// send it back to the processor for async processing
+		p.fromClientAPI <- gtsmodel.FromClientAPI{
+			APObjectType:   gtsmodel.ActivityStreamsNote,
+			APActivityType: gtsmodel.ActivityStreamsAdd,
+			GTSModel:       targetStatus,
+			OriginAccount:  account,
+		}
+	}
+
+	mastoStatus, err := p.tc.StatusToMasto(ctx, targetStatus, account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", targetStatus.ID, err))
+	}
+
+	return mastoStatus, nil
+}
@@ -0,0 +1,46 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (p *processor) Unpin(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	l := p.log.WithField("func", "StatusUnpin")
+	l.Tracef("going to search for target status %s", targetStatusID)
+	targetStatus := &gtsmodel.Status{}
+	if err := p.db.GetByID(ctx, targetStatusID, targetStatus); err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching status %s: %s", targetStatusID, err))
+	}
+
+	if targetStatus.AccountID != account.ID {
+		return nil, gtserror.NewErrorNotFound(errors.New("status does not belong to account"))
+	}
+
+	if targetStatus.Pinned {
+		if err := p.db.UpdateOneByID(ctx, targetStatus.ID, "pinned", false, &gtsmodel.Status{}); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error unpinning status in database: %s", err))
+		}
+		targetStatus.Pinned = false
+
+		// send it back to the processor for async processing
+		p.fromClientAPI <- gtsmodel.FromClientAPI{
+			APObjectType:   gtsmodel.ActivityStreamsNote,
+			APActivityType: gtsmodel.ActivityStreamsRemove,
+			GTSModel:       targetStatus,
+			OriginAccount:  account,
+		}
+	}
+
+	mastoStatus, err := p.tc.StatusToMasto(ctx, targetStatus, account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", targetStatus.ID, err))
+	}
+
+	return mastoStatus, nil
+}
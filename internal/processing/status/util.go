@@ -1,12 +1,15 @@
 package status
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
@@ -87,7 +90,7 @@ func (p *processor) processVisibility(form *apimodel.AdvancedStatusCreateForm, a
 	return nil
 }
 
-func (p *processor) processReplyToID(form *apimodel.AdvancedStatusCreateForm, thisAccountID string, status *gtsmodel.Status) error {
+func (p *processor) processReplyToID(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, thisAccountID string, status *gtsmodel.Status) error {
 	if form.InReplyToID == "" {
 		return nil
 	}
@@ -102,7 +105,7 @@ func (p *processor) processReplyToID(form *apimodel.AdvancedStatusCreateForm, th
 	repliedStatus := &gtsmodel.Status{}
 	repliedAccount := &gtsmodel.Account{}
 	// check replied status exists + is replyable
-	if err := p.db.GetByID(form.InReplyToID, repliedStatus); err != nil {
+	if err := p.db.GetByID(ctx, form.InReplyToID, repliedStatus); err != nil {
 		if _, ok := err.(db.ErrNoEntries); ok {
 			return fmt.Errorf("status with id %s not replyable because it doesn't exist", form.InReplyToID)
 		}
@@ -116,14 +119,14 @@ func (p *processor) processReplyToID(form *apimodel.AdvancedStatusCreateForm, th
 	}
 
 	// check replied account is known to us
-	if err := p.db.GetByID(repliedStatus.AccountID, repliedAccount); err != nil {
+	if err := p.db.GetByID(ctx, repliedStatus.AccountID, repliedAccount); err != nil {
 		if _, ok := err.(db.ErrNoEntries); ok {
 			return fmt.Errorf("status with id %s not replyable because account id %s is not known", form.InReplyToID, repliedStatus.AccountID)
 		}
 		return fmt.Errorf("status with id %s not replyable: %s", form.InReplyToID, err)
 	}
 	// check if a block exists
-	if blocked, err := p.db.Blocked(thisAccountID, repliedAccount.ID); err != nil {
+	if blocked, err := p.db.Blocked(ctx, thisAccountID, repliedAccount.ID); err != nil {
 		if _, ok := err.(db.ErrNoEntries); !ok {
 			return fmt.Errorf("status with id %s not replyable: %s", form.InReplyToID, err)
 		}
@@ -136,7 +139,7 @@ func (p *processor) processReplyToID(form *apimodel.AdvancedStatusCreateForm, th
 	return nil
 }
 
-func (p *processor) processMediaIDs(form *apimodel.AdvancedStatusCreateForm, thisAccountID string, status *gtsmodel.Status) error {
+func (p *processor) processMediaIDs(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, thisAccountID string, scheduledStatusID string, status *gtsmodel.Status) error {
 	if form.MediaIDs == nil {
 		return nil
 	}
@@ -146,15 +149,15 @@ func (p *processor) processMediaIDs(form *apimodel.AdvancedStatusCreateForm, thi
 	for _, mediaID := range form.MediaIDs {
 		// check these attachments exist
 		a := &gtsmodel.MediaAttachment{}
-		if err := p.db.GetByID(mediaID, a); err != nil {
+		if err := p.db.GetByID(ctx, mediaID, a); err != nil {
 			return fmt.Errorf("invalid media type or media not found for media id %s", mediaID)
 		}
 		// check they belong to the requesting account id
 		if a.AccountID != thisAccountID {
 			return fmt.Errorf("media with id %s does not belong to account %s", mediaID, thisAccountID)
 		}
-		// check they're not already used in a status
-		if a.StatusID != "" || a.ScheduledStatusID != "" {
+		// check they're not already used in a status, unless it's the scheduled status that's being published
+		if a.StatusID != "" || (a.ScheduledStatusID != "" && a.ScheduledStatusID != scheduledStatusID) {
 			return fmt.Errorf("media with id %s is already attached to a status", mediaID)
 		}
 		gtsMediaAttachments = append(gtsMediaAttachments, a)
@@ -165,6 +168,49 @@ func (p *processor) processMediaIDs(form *apimodel.AdvancedStatusCreateForm, thi
 	return nil
 }
 
+// processPoll attaches a new poll to the given status if the form contains one, rejecting options that are empty or duplicated once their html is removed.
+func (p *processor) processPoll(form *apimodel.AdvancedStatusCreateForm, thisAccountID string, status *gtsmodel.Status) gtserror.WithCode {
+	if form.Poll == nil {
+		return nil
+	}
+
+	options := []string{}
+	seen := map[string]bool{}
+	for _, o := range form.Poll.Options {
+		option := strings.TrimSpace(util.RemoveHTML(o))
+		if option == "" {
+			return gtserror.NewErrorBadRequest(errors.New("poll option was empty"), "poll options must not be empty")
+		}
+		if seen[option] {
+			return gtserror.NewErrorBadRequest(fmt.Errorf("poll option %s was duplicated", option), "poll options must not be duplicated")
+		}
+		seen[option] = true
+		options = append(options, option)
+	}
+
+	pollID, err := id.NewULID()
+	if err != nil {
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	now := time.Now()
+	status.GTSPoll = &gtsmodel.Poll{
+		ID:         pollID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		StatusID:   status.ID,
+		AccountID:  thisAccountID,
+		Options:    options,
+		Votes:      make([]int, len(options)),
+		Multiple:   form.Poll.Multiple,
+		HideTotals: form.Poll.HideTotals,
+		ExpiresAt:  now.Add(time.Duration(form.Poll.ExpiresIn) * time.Second),
+	}
+	// a status with a poll is federated as a question rather than a note
+	status.ActivityStreamsType = gtsmodel.ActivityStreamsQuestion
+	return nil
+}
+
 func (p *processor) processLanguage(form *apimodel.AdvancedStatusCreateForm, accountDefaultLanguage string, status *gtsmodel.Status) error {
 	if form.Language != "" {
 		status.Language = form.Language
@@ -177,9 +223,9 @@ func (p *processor) processLanguage(form *apimodel.AdvancedStatusCreateForm, acc
 	return nil
 }
 
-func (p *processor) processMentions(form *apimodel.AdvancedStatusCreateForm, accountID string, status *gtsmodel.Status) error {
+func (p *processor) processMentions(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, accountID string, status *gtsmodel.Status) error {
 	menchies := []string{}
-	gtsMenchies, err := p.db.MentionStringsToMentions(util.DeriveMentionsFromStatus(form.Status), accountID, status.ID)
+	gtsMenchies, err := p.db.MentionStringsToMentions(ctx, util.DeriveMentionsFromStatus(form.Status), accountID, status.ID)
 	if err != nil {
 		return fmt.Errorf("error generating mentions from status: %s", err)
 	}
@@ -190,7 +236,7 @@ func (p *processor) processMentions(form *apimodel.AdvancedStatusCreateForm, acc
 		}
 		menchie.ID = menchieID
 
-		if err := p.db.Put(menchie); err != nil {
+		if err := p.db.Put(ctx, menchie); err != nil {
 			return fmt.Errorf("error putting mentions in db: %s", err)
 		}
 		menchies = append(menchies, menchie.ID)
@@ -202,14 +248,14 @@ func (p *processor) processMentions(form *apimodel.AdvancedStatusCreateForm, acc
 	return nil
 }
 
-func (p *processor) processTags(form *apimodel.AdvancedStatusCreateForm, accountID string, status *gtsmodel.Status) error {
+func (p *processor) processTags(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, accountID string, status *gtsmodel.Status) error {
 	tags := []string{}
-	gtsTags, err := p.db.TagStringsToTags(util.DeriveHashtagsFromStatus(form.Status), accountID, status.ID)
+	gtsTags, err := p.db.TagStringsToTags(ctx, util.DeriveHashtagsFromStatus(form.Status), accountID, status.ID)
 	if err != nil {
 		return fmt.Errorf("error generating hashtags from status: %s", err)
 	}
 	for _, tag := range gtsTags {
-		if err := p.db.Upsert(tag, "name"); err != nil {
+		if err := p.db.Upsert(ctx, tag, "name"); err != nil {
 			return fmt.Errorf("error putting tags in db: %s", err)
 		}
 		tags = append(tags, tag.ID)
@@ -221,9 +267,9 @@ func (p *processor) processTags(form *apimodel.AdvancedStatusCreateForm, account
 	return nil
 }
 
-func (p *processor) processEmojis(form *apimodel.AdvancedStatusCreateForm, accountID string, status *gtsmodel.Status) error {
+func (p *processor) processEmojis(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, accountID string, status *gtsmodel.Status) error {
 	emojis := []string{}
-	gtsEmojis, err := p.db.EmojiStringsToEmojis(util.DeriveEmojisFromStatus(form.Status), accountID, status.ID)
+	gtsEmojis, err := p.db.EmojiStringsToEmojis(ctx, util.DeriveEmojisFromStatus(form.Status), accountID, status.ID)
 	if err != nil {
 		return fmt.Errorf("error generating emojis from status: %s", err)
 	}
@@ -237,7 +283,7 @@ func (p *processor) processEmojis(form *apimodel.AdvancedStatusCreateForm, accou
 	return nil
 }
 
-func (p *processor) processContent(form *apimodel.AdvancedStatusCreateForm, accountID string, status *gtsmodel.Status) error {
+func (p *processor) processContent(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, accountID string, status *gtsmodel.Status) error {
 	if form.Status == "" {
 		status.Content = ""
 		return nil
@@ -249,7 +295,7 @@ func (p *processor) processContent(form *apimodel.AdvancedStatusCreateForm, acco
 	// format mentions nicely
 	for _, menchie := range status.GTSMentions {
 		targetAccount := &gtsmodel.Account{}
-		if err := p.db.GetByID(menchie.TargetAccountID, targetAccount); err == nil {
+		if err := p.db.GetByID(ctx, menchie.TargetAccountID, targetAccount); err == nil {
 			mentionContent := fmt.Sprintf(`<span class="h-card"><a href="%s" class="u-url mention">@<span>%s</span></a></span>`, targetAccount.URL, targetAccount.Username)
 			content = strings.ReplaceAll(content, menchie.NameString, mentionContent)
 		}
@@ -0,0 +1,135 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type StatusMuteTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+// waitForQueue waits until the processor has worked through all queued messages.
+func (suite *StatusMuteTestSuite) waitForQueue() {
+	suite.Eventually(func() bool {
+		queued := []*gtsmodel.QueuedMessage{}
+		err := suite.db.GetAll(context.Background(), &queued)
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return true
+		}
+		return err == nil && len(queued) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (suite *StatusMuteTestSuite) statusMuted(authed *oauth.Auth, statusID string) bool {
+	status, errWithCode := suite.processor.StatusGet(context.Background(), authed, statusID)
+	suite.Nil(errWithCode)
+	return status.Muted
+}
+
+func (suite *StatusMuteTestSuite) TestMuteThread() {
+	ctx := context.Background()
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+	root := suite.testStatuses["local_account_1_status_1"]
+
+	muted, errWithCode := suite.processor.StatusMute(ctx, authed, root.ID)
+	suite.Nil(errWithCode)
+	suite.True(muted.Muted)
+
+	// a reply that comes in after the mute mentions the muting account, and the muted status gets faved;
+	// the reply isn't federated, so that the processor isn't left retrying deliveries to the mock http client
+	federated := false
+	reply, err := suite.processor.StatusCreate(ctx, &oauth.Auth{
+		Account:     suite.testAccounts["local_account_2"],
+		Application: suite.testApplications["application_2"],
+	}, &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status:      "@the_mighty_zork what do you mean?",
+			InReplyToID: root.ID,
+			Visibility:  apimodel.VisibilityUnlisted,
+		},
+		AdvancedVisibilityFlagsForm: apimodel.AdvancedVisibilityFlagsForm{
+			Federated: &federated,
+		},
+	})
+	suite.NoError(err)
+	_, err = suite.processor.StatusFave(ctx, &oauth.Auth{Account: suite.testAccounts["local_account_2"]}, root.ID)
+	suite.NoError(err)
+
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+	suite.waitForQueue()
+
+	// the reply should be muted along with the rest of the thread, so there should be no notifications about either status
+	suite.True(suite.statusMuted(authed, reply.ID))
+	for _, statusID := range []string{root.ID, reply.ID} {
+		notifs := []*gtsmodel.Notification{}
+		if err := suite.db.GetWhere(ctx, []db.Where{
+			{Key: "target_account_id", Value: authed.Account.ID},
+			{Key: "origin_account_id", Value: suite.testAccounts["local_account_2"].ID},
+			{Key: "status_id", Value: statusID},
+		}, &notifs); err != nil {
+			suite.IsType(db.ErrNoEntries{}, err)
+		}
+		suite.Empty(notifs)
+	}
+
+	// unmuting the reply unmutes the whole thread
+	unmuted, errWithCode := suite.processor.StatusUnmute(ctx, authed, reply.ID)
+	suite.Nil(errWithCode)
+	suite.False(unmuted.Muted)
+	suite.False(suite.statusMuted(authed, root.ID))
+
+	// muting the reply mutes the statuses it replies to as well
+	_, errWithCode = suite.processor.StatusMute(ctx, authed, reply.ID)
+	suite.Nil(errWithCode)
+	suite.True(suite.statusMuted(authed, root.ID))
+}
+
+func (suite *StatusMuteTestSuite) TestMuteInvisibleStatus() {
+	ctx := context.Background()
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+
+	// local_account_2 blocks local_account_1, so their statuses can't be seen, let alone muted
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.Block{
+		ID:              "01FGRY7WYN8B5QHS0NGDKHE5G9",
+		AccountID:       suite.testAccounts["local_account_2"].ID,
+		TargetAccountID: authed.Account.ID,
+		URI:             "http://localhost:8080/users/1happyturtle/blocks/01FGRY7WYN8B5QHS0NGDKHE5G9",
+	}))
+
+	_, errWithCode := suite.processor.StatusMute(ctx, authed, suite.testStatuses["local_account_2_status_1"].ID)
+	suite.NotNil(errWithCode)
+	suite.Equal(404, errWithCode.Code())
+}
+
+func TestStatusMuteTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusMuteTestSuite))
+}
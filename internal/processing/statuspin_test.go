@@ -0,0 +1,130 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type StatusPinTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *StatusPinTestSuite) pinnedIDs(authed *oauth.Auth, accountID string) []string {
+	statuses, errWithCode := suite.processor.AccountStatusesGet(context.Background(), authed, accountID, 20, false, "", true, false)
+	suite.Nil(errWithCode)
+	ids := []string{}
+	for _, s := range statuses {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func (suite *StatusPinTestSuite) TestPinUnpin() {
+	ctx := context.Background()
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+	viewer := &oauth.Auth{Account: suite.testAccounts["local_account_2"]}
+	status := suite.testStatuses["local_account_1_status_1"]
+
+	pinned, errWithCode := suite.processor.StatusPin(ctx, authed, status.ID)
+	suite.Nil(errWithCode)
+	suite.True(pinned.Pinned)
+	suite.Equal([]string{status.ID}, suite.pinnedIDs(viewer, authed.Account.ID))
+
+	// pinning again is a no-op
+	_, errWithCode = suite.processor.StatusPin(ctx, authed, status.ID)
+	suite.Nil(errWithCode)
+	suite.Equal([]string{status.ID}, suite.pinnedIDs(viewer, authed.Account.ID))
+
+	unpinned, errWithCode := suite.processor.StatusUnpin(ctx, authed, status.ID)
+	suite.Nil(errWithCode)
+	suite.False(unpinned.Pinned)
+	suite.Empty(suite.pinnedIDs(viewer, authed.Account.ID))
+}
+
+func (suite *StatusPinTestSuite) TestPinNotAllowed() {
+	ctx := context.Background()
+	authed := &oauth.Auth{Account: suite.testAccounts["local_account_1"]}
+
+	// someone else's status
+	_, errWithCode := suite.processor.StatusPin(ctx, authed, suite.testStatuses["local_account_2_status_1"].ID)
+	suite.NotNil(errWithCode)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+
+	// a status that isn't public or unlisted
+	_, errWithCode = suite.processor.StatusPin(ctx, authed, suite.testStatuses["local_account_1_status_3"].ID)
+	suite.NotNil(errWithCode)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+
+	suite.Empty(suite.pinnedIDs(authed, authed.Account.ID))
+}
+
+func (suite *StatusPinTestSuite) TestPinLimit() {
+	ctx := context.Background()
+	authed := &oauth.Auth{
+		Account:     suite.testAccounts["local_account_1"],
+		Application: suite.testApplications["application_1"],
+	}
+
+	// the statuses aren't federated, so that the processor isn't left retrying deliveries to the mock http client
+	federated := false
+	statusIDs := []string{}
+	for i := 0; i < testrig.NewTestConfig().StatusesConfig.MaxPinned+1; i++ {
+		status, err := suite.processor.StatusCreate(ctx, authed, &apimodel.AdvancedStatusCreateForm{
+			StatusCreateRequest: apimodel.StatusCreateRequest{
+				Status:     fmt.Sprintf("pin me %d", i),
+				Visibility: apimodel.VisibilityUnlisted,
+			},
+			AdvancedVisibilityFlagsForm: apimodel.AdvancedVisibilityFlagsForm{
+				Federated: &federated,
+			},
+		})
+		suite.NoError(err)
+		statusIDs = append(statusIDs, status.ID)
+	}
+
+	for _, statusID := range statusIDs[:len(statusIDs)-1] {
+		_, errWithCode := suite.processor.StatusPin(ctx, authed, statusID)
+		suite.Nil(errWithCode)
+	}
+
+	// one more is too many
+	_, errWithCode := suite.processor.StatusPin(ctx, authed, statusIDs[len(statusIDs)-1])
+	suite.NotNil(errWithCode)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+	suite.Len(suite.pinnedIDs(authed, authed.Account.ID), len(statusIDs)-1)
+
+	// but after unpinning one there's room again
+	_, errWithCode = suite.processor.StatusUnpin(ctx, authed, statusIDs[0])
+	suite.Nil(errWithCode)
+	_, errWithCode = suite.processor.StatusPin(ctx, authed, statusIDs[len(statusIDs)-1])
+	suite.Nil(errWithCode)
+}
+
+func TestStatusPinTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusPinTestSuite))
+}
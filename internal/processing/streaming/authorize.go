@@ -7,8 +7,8 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (p *processor) AuthorizeStreamingRequest(accessToken string) (*gtsmodel.Account, error) {
-	ti, err := p.oauthServer.LoadAccessToken(context.Background(), accessToken)
+func (p *processor) AuthorizeStreamingRequest(ctx context.Context, accessToken string) (*gtsmodel.Account, error) {
+	ti, err := p.oauthServer.LoadAccessToken(ctx, accessToken)
 	if err != nil {
 		return nil, fmt.Errorf("AuthorizeStreamingRequest: error loading access token: %s", err)
 	}
@@ -20,12 +20,12 @@ func (p *processor) AuthorizeStreamingRequest(accessToken string) (*gtsmodel.Acc
 
 	// fetch user's and account for this user id
 	user := &gtsmodel.User{}
-	if err := p.db.GetByID(uid, user); err != nil || user == nil {
+	if err := p.db.GetByID(ctx, uid, user); err != nil || user == nil {
 		return nil, fmt.Errorf("AuthorizeStreamingRequest: no user found for validated uid %s", uid)
 	}
 
 	acct := &gtsmodel.Account{}
-	if err := p.db.GetByID(user.AccountID, acct); err != nil || acct == nil {
+	if err := p.db.GetByID(ctx, user.AccountID, acct); err != nil || acct == nil {
 		return nil, fmt.Errorf("AuthorizeStreamingRequest: no account retrieved for user with id %s", uid)
 	}
 
@@ -1,23 +1,59 @@
 package streaming
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
 )
 
-func (p *processor) OpenStreamForAccount(account *gtsmodel.Account, streamType string) (*gtsmodel.Stream, gtserror.WithCode) {
+func (p *processor) OpenStreamForAccount(ctx context.Context, account *gtsmodel.Account, streamType string, listID string, tag string) (*gtsmodel.Stream, gtserror.WithCode) {
 	l := p.log.WithFields(logrus.Fields{
 		"func":       "OpenStreamForAccount",
 		"account":    account.ID,
 		"streamType": streamType,
+		"listID":     listID,
+		"tag":        tag,
 	})
 	l.Debug("received open stream request")
 
+	if streamType == gtsmodel.StreamTypeList {
+		// make sure the list exists and belongs to the account before streaming it
+		if listID == "" {
+			err := errors.New("no list id provided for list stream")
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		list := &gtsmodel.List{}
+		if err := p.db.GetByID(ctx, listID, list); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				return nil, gtserror.NewErrorNotFound(fmt.Errorf("list %s not found", listID))
+			}
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting list %s: %s", listID, err))
+		}
+		if list.AccountID != account.ID {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("list %s does not belong to account %s", listID, account.ID))
+		}
+	} else {
+		listID = ""
+	}
+
+	if streamType == gtsmodel.StreamTypeHashtag {
+		// hashtags are stored without the hash and in lowercase, so match them like that
+		tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+		if tag == "" {
+			err := errors.New("no tag provided for hashtag stream")
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+	} else {
+		tag = ""
+	}
+
 	// each stream needs a unique ID so we know to close it
 	streamID, err := id.NewRandomULID()
 	if err != nil {
@@ -27,6 +63,8 @@ func (p *processor) OpenStreamForAccount(account *gtsmodel.Account, streamType s
 	thisStream := &gtsmodel.Stream{
 		ID:        streamID,
 		Type:      streamType,
+		List:      listID,
+		Tag:       tag,
 		Messages:  make(chan *gtsmodel.Message, 100),
 		Hangup:    make(chan interface{}, 1),
 		Connected: true,
@@ -1,13 +1,14 @@
 package streaming
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (p *processor) StreamDelete(statusID string) error {
+func (p *processor) StreamDelete(ctx context.Context, statusID string) error {
 	errs := []string{}
 
 	// we want to range through ALL streams for ALL accounts here to make sure it's very clear to everyone that the status has been deleted
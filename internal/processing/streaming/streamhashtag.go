@@ -0,0 +1,126 @@
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (p *processor) StreamStatusToHashtags(ctx context.Context, status *gtsmodel.Status) error {
+	l := p.log.WithFields(logrus.Fields{
+		"func":     "StreamStatusToHashtags",
+		"statusID": status.ID,
+	})
+
+	if status.Visibility != gtsmodel.VisibilityPublic {
+		return nil
+	}
+
+	// work out the names of the tags used in the status, since that's what hashtag streams are keyed on;
+	// tags are matched without regard to case, and hashtag streams store their tag in lowercase
+	tagNames := map[string]bool{}
+	if status.GTSTags != nil {
+		for _, t := range status.GTSTags {
+			tagNames[strings.ToLower(t.Name)] = true
+		}
+	} else {
+		for _, tID := range status.Tags {
+			t := &gtsmodel.Tag{}
+			if err := p.db.GetByID(ctx, tID, t); err != nil {
+				return fmt.Errorf("StreamStatusToHashtags: error getting tag %s: %s", tID, err)
+			}
+			tagNames[strings.ToLower(t.Name)] = true
+		}
+	}
+	if len(tagNames) == 0 {
+		return nil
+	}
+
+	// collect the accounts that have a stream open for one of the tags
+	accountIDs := []string{}
+	p.streamMap.Range(func(k interface{}, v interface{}) bool {
+		accountID, ok := k.(string)
+		if !ok {
+			return true
+		}
+		streamsForAccount, ok := v.(*gtsmodel.StreamsForAccount)
+		if !ok {
+			return true
+		}
+
+		streamsForAccount.Lock()
+		defer streamsForAccount.Unlock()
+		for _, stream := range streamsForAccount.Streams {
+			if stream.Type == gtsmodel.StreamTypeHashtag && tagNames[stream.Tag] {
+				accountIDs = append(accountIDs, accountID)
+				break
+			}
+		}
+		return true
+	})
+
+	for _, accountID := range accountIDs {
+		account := &gtsmodel.Account{}
+		if err := p.db.GetByID(ctx, accountID, account); err != nil {
+			return fmt.Errorf("StreamStatusToHashtags: error getting account %s: %s", accountID, err)
+		}
+
+		// hashtag streams are public timelines, so the status has to be fit for those
+		timelineable, err := p.filter.StatusPublictimelineable(ctx, status, account)
+		if err != nil {
+			return fmt.Errorf("StreamStatusToHashtags: error checking timelineability of status for account %s: %s", accountID, err)
+		}
+		if !timelineable {
+			continue
+		}
+
+		filtered, err := p.statusKeywordFiltered(ctx, status, account, gtsmodel.FilterContextPublic)
+		if err != nil {
+			return fmt.Errorf("StreamStatusToHashtags: error checking keyword filters of account %s: %s", accountID, err)
+		}
+		if filtered {
+			continue
+		}
+
+		apiStatus, err := p.tc.StatusToMasto(ctx, status, account)
+		if err != nil {
+			return fmt.Errorf("StreamStatusToHashtags: error converting status to api representation: %s", err)
+		}
+
+		statusBytes, err := json.Marshal(apiStatus)
+		if err != nil {
+			return fmt.Errorf("StreamStatusToHashtags: error marshalling status to json: %s", err)
+		}
+
+		v, ok := p.streamMap.Load(accountID)
+		if !ok {
+			// the streams were closed in the meantime
+			continue
+		}
+		streamsForAccount, ok := v.(*gtsmodel.StreamsForAccount)
+		if !ok {
+			continue
+		}
+
+		streamsForAccount.Lock()
+		for _, stream := range streamsForAccount.Streams {
+			stream.Lock()
+			if stream.Connected && stream.Type == gtsmodel.StreamTypeHashtag && tagNames[stream.Tag] {
+				l.Debugf("streaming status to stream id %s", stream.ID)
+				stream.Messages <- &gtsmodel.Message{
+					Stream:  []string{stream.Type, stream.Tag},
+					Event:   "update",
+					Payload: string(statusBytes),
+				}
+			}
+			stream.Unlock()
+		}
+		streamsForAccount.Unlock()
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package streaming
 
 import (
+	"context"
 	"sync"
 
 	"github.com/sirupsen/logrus"
@@ -17,15 +18,24 @@ import (
 // Processor wraps a bunch of functions for processing streaming.
 type Processor interface {
 	// AuthorizeStreamingRequest returns an oauth2 token info in response to an access token query from the streaming API
-	AuthorizeStreamingRequest(accessToken string) (*gtsmodel.Account, error)
+	AuthorizeStreamingRequest(ctx context.Context, accessToken string) (*gtsmodel.Account, error)
 	// OpenStreamForAccount returns a new Stream for the given account, which will contain a channel for passing messages back to the caller.
-	OpenStreamForAccount(account *gtsmodel.Account, streamType string) (*gtsmodel.Stream, gtserror.WithCode)
+	OpenStreamForAccount(ctx context.Context, account *gtsmodel.Account, streamType string, listID string, tag string) (*gtsmodel.Stream, gtserror.WithCode)
 	// StreamStatusToAccount streams the given status to any open, appropriate streams belonging to the given account.
-	StreamStatusToAccount(s *apimodel.Status, account *gtsmodel.Account) error
+	// The database representation of the status is used to check it against the keyword filters of the account.
+	StreamStatusToAccount(ctx context.Context, s *apimodel.Status, status *gtsmodel.Status, account *gtsmodel.Account) error
+	// StreamStatusToList streams the given status to any open list streams belonging to the given account for the given list.
+	StreamStatusToList(ctx context.Context, s *apimodel.Status, listID string, account *gtsmodel.Account) error
+	// StreamStatusToHashtags streams the given status to any open hashtag streams, belonging to any account, for the tags used in the status.
+	StreamStatusToHashtags(ctx context.Context, status *gtsmodel.Status) error
 	// StreamNotificationToAccount streams the given notification to any open, appropriate streams belonging to the given account.
-	StreamNotificationToAccount(n *apimodel.Notification, account *gtsmodel.Account) error
+	// If the notification is about a status, the database representation of that status should be passed in so it can be checked
+	// against the keyword filters of the account; otherwise status can be nil.
+	StreamNotificationToAccount(ctx context.Context, n *apimodel.Notification, status *gtsmodel.Status, account *gtsmodel.Account) error
+	// StreamConversationToAccount streams the given conversation to any open direct streams belonging to the given account.
+	StreamConversationToAccount(ctx context.Context, c *apimodel.Conversation, account *gtsmodel.Account) error
 	// StreamDelete streams the delete of the given statusID to *ALL* open streams.
-	StreamDelete(statusID string) error
+	StreamDelete(ctx context.Context, statusID string) error
 }
 
 type processor struct {
@@ -1,6 +1,7 @@
 package streaming
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,7 +11,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (p *processor) StreamNotificationToAccount(n *apimodel.Notification, account *gtsmodel.Account) error {
+func (p *processor) StreamNotificationToAccount(ctx context.Context, n *apimodel.Notification, status *gtsmodel.Status, account *gtsmodel.Account) error {
 	l := p.log.WithFields(logrus.Fields{
 		"func":    "StreamNotificationToAccount",
 		"account": account.ID,
@@ -26,6 +27,28 @@ func (p *processor) StreamNotificationToAccount(n *apimodel.Notification, accoun
 		return errors.New("stream map error")
 	}
 
+	if n.Account != nil {
+		muted, err := p.filter.AccountMuted(ctx, n.Account.ID, account, true)
+		if err != nil {
+			return err
+		}
+		if muted {
+			l.Debugf("not streaming notification %s because notifications from its account are muted", n.ID)
+			return nil
+		}
+	}
+
+	if status != nil {
+		filtered, err := p.statusKeywordFiltered(ctx, status, account, gtsmodel.FilterContextNotifications)
+		if err != nil {
+			return err
+		}
+		if filtered {
+			l.Debugf("not streaming notification %s because its status matches a keyword filter", n.ID)
+			return nil
+		}
+	}
+
 	notificationBytes, err := json.Marshal(n)
 	if err != nil {
 		return fmt.Errorf("error marshalling notification to json: %s", err)
@@ -36,7 +59,7 @@ func (p *processor) StreamNotificationToAccount(n *apimodel.Notification, accoun
 	for _, stream := range streamsForAccount.Streams {
 		stream.Lock()
 		defer stream.Unlock()
-		if stream.Connected {
+		if stream.Connected && stream.Type != gtsmodel.StreamTypeList && stream.Type != gtsmodel.StreamTypeDirect && stream.Type != gtsmodel.StreamTypeHashtag {
 			l.Debugf("streaming notification to stream id %s", stream.ID)
 			stream.Messages <- &gtsmodel.Message{
 				Stream:  []string{stream.Type},
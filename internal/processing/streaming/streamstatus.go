@@ -1,6 +1,7 @@
 package streaming
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,7 +11,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (p *processor) StreamStatusToAccount(s *apimodel.Status, account *gtsmodel.Account) error {
+func (p *processor) StreamStatusToAccount(ctx context.Context, s *apimodel.Status, status *gtsmodel.Status, account *gtsmodel.Account) error {
 	l := p.log.WithFields(logrus.Fields{
 		"func":    "StreamStatusForAccount",
 		"account": account.ID,
@@ -26,6 +27,15 @@ func (p *processor) StreamStatusToAccount(s *apimodel.Status, account *gtsmodel.
 		return errors.New("stream map error")
 	}
 
+	filtered, err := p.statusKeywordFiltered(ctx, status, account, gtsmodel.FilterContextHome)
+	if err != nil {
+		return err
+	}
+	if filtered {
+		l.Debugf("not streaming status %s because it matches a keyword filter", s.ID)
+		return nil
+	}
+
 	statusBytes, err := json.Marshal(s)
 	if err != nil {
 		return fmt.Errorf("error marshalling status to json: %s", err)
@@ -36,7 +46,7 @@ func (p *processor) StreamStatusToAccount(s *apimodel.Status, account *gtsmodel.
 	for _, stream := range streamsForAccount.Streams {
 		stream.Lock()
 		defer stream.Unlock()
-		if stream.Connected {
+		if stream.Connected && stream.Type != gtsmodel.StreamTypeList && stream.Type != gtsmodel.StreamTypeDirect && stream.Type != gtsmodel.StreamTypeHashtag {
 			l.Debugf("streaming status to stream id %s", stream.ID)
 			stream.Messages <- &gtsmodel.Message{
 				Stream:  []string{stream.Type},
@@ -48,3 +58,58 @@ func (p *processor) StreamStatusToAccount(s *apimodel.Status, account *gtsmodel.
 
 	return nil
 }
+
+func (p *processor) StreamStatusToList(ctx context.Context, s *apimodel.Status, listID string, account *gtsmodel.Account) error {
+	l := p.log.WithFields(logrus.Fields{
+		"func":    "StreamStatusToList",
+		"account": account.ID,
+		"list":    listID,
+	})
+	v, ok := p.streamMap.Load(account.ID)
+	if !ok {
+		// no open connections so nothing to stream
+		return nil
+	}
+
+	streamsForAccount, ok := v.(*gtsmodel.StreamsForAccount)
+	if !ok {
+		return errors.New("stream map error")
+	}
+
+	statusBytes, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("error marshalling status to json: %s", err)
+	}
+
+	streamsForAccount.Lock()
+	defer streamsForAccount.Unlock()
+	for _, stream := range streamsForAccount.Streams {
+		stream.Lock()
+		defer stream.Unlock()
+		if stream.Connected && stream.Type == gtsmodel.StreamTypeList && stream.List == listID {
+			l.Debugf("streaming status to stream id %s", stream.ID)
+			stream.Messages <- &gtsmodel.Message{
+				Stream:  []string{stream.Type, stream.List},
+				Event:   "update",
+				Payload: string(statusBytes),
+			}
+		}
+	}
+
+	return nil
+}
+
+// statusKeywordFiltered returns true if the given status matches any of the keyword filters of the given account in the given context.
+func (p *processor) statusKeywordFiltered(ctx context.Context, status *gtsmodel.Status, account *gtsmodel.Account, filterContext gtsmodel.FilterContext) (bool, error) {
+	filters, err := p.filter.KeywordFilters(ctx, account, filterContext)
+	if err != nil {
+		return false, fmt.Errorf("error getting keyword filters for account %s: %s", account.ID, err)
+	}
+
+	filtered, err := p.filter.StatusKeywordFiltered(ctx, status, filters)
+	if err != nil {
+		return false, fmt.Errorf("error checking keyword filters for status %s: %s", status.ID, err)
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,268 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+func (p *processor) TagTimelineGet(ctx context.Context, authed *oauth.Auth, tagName string, anyTagNames []string, allTagNames []string, noneTagNames []string, maxID string, sinceID string, minID string, limit int, local bool, mediaOnly bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	tag, errWithCode := p.getTag(ctx, tagName)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// tags that aren't in the database yet come back with a fresh ID that no status uses, so
+	// an unknown tag in all just means there are no results, and an unknown tag in any or none is ignored
+	anyTagIDs, err := p.tagIDs(ctx, anyTagNames)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	allTagIDs, err := p.tagIDs(ctx, allTagNames)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	noneTagIDs, err := p.tagIDs(ctx, noneTagNames)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	statuses, err := p.db.GetTagTimeline(ctx, append([]string{tag.ID}, anyTagIDs...), allTagIDs, noneTagIDs, maxID, sinceID, minID, limit, local, mediaOnly)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			// there are just no entries left
+			return &apimodel.StatusTimelineResponse{
+				Statuses: []*apimodel.Status{},
+			}, nil
+		}
+		// there's an actual error
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	s, err := p.filterPublicStatuses(ctx, authed, statuses)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if len(s) == 0 {
+		return &apimodel.StatusTimelineResponse{
+			Statuses: []*apimodel.Status{},
+		}, nil
+	}
+
+	return p.packageStatusResponse(s, "api/v1/timelines/tag/"+tag.Name, s[len(s)-1].ID, s[0].ID, limit)
+}
+
+func (p *processor) TagGet(ctx context.Context, authed *oauth.Auth, tagName string) (*apimodel.Tag, gtserror.WithCode) {
+	tag, errWithCode := p.getTag(ctx, tagName)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	following, err := p.followsTag(ctx, authed.Account.ID, tag.ID)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.tagToMasto(tag, following)
+}
+
+func (p *processor) TagFollow(ctx context.Context, authed *oauth.Auth, tagName string) (*apimodel.Tag, gtserror.WithCode) {
+	tag, errWithCode := p.getTag(ctx, tagName)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// the tag might not have been used by anyone yet, in which case it has to be created before it can be followed
+	if err := p.db.GetByID(ctx, tag.ID, &gtsmodel.Tag{}); err != nil {
+		if _, ok := err.(db.ErrNoEntries); !ok {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("TagFollow: error checking tag %s: %s", tag.ID, err))
+		}
+		if err := p.db.Put(ctx, tag); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("TagFollow: error putting tag in db: %s", err))
+		}
+	}
+
+	following, err := p.followsTag(ctx, authed.Account.ID, tag.ID)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if !following {
+		followedTagID, err := id.NewULID()
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		followedTag := &gtsmodel.FollowedTag{
+			ID:        followedTagID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			AccountID: authed.Account.ID,
+			TagID:     tag.ID,
+		}
+		if err := p.db.Put(ctx, followedTag); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("TagFollow: error putting followed tag in db: %s", err))
+		}
+	}
+
+	return p.tagToMasto(tag, true)
+}
+
+func (p *processor) TagUnfollow(ctx context.Context, authed *oauth.Auth, tagName string) (*apimodel.Tag, gtserror.WithCode) {
+	tag, errWithCode := p.getTag(ctx, tagName)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := p.db.DeleteWhere(ctx, []db.Where{
+		{Key: "account_id", Value: authed.Account.ID},
+		{Key: "tag_id", Value: tag.ID},
+	}, &[]*gtsmodel.FollowedTag{}); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("TagUnfollow: error deleting followed tag from db: %s", err))
+	}
+
+	return p.tagToMasto(tag, false)
+}
+
+func (p *processor) FollowedTagsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Tag, gtserror.WithCode) {
+	apiTags := []*apimodel.Tag{}
+
+	followedTags := []*gtsmodel.FollowedTag{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: authed.Account.ID}}, &followedTags); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return apiTags, nil
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("FollowedTagsGet: error getting followed tags: %s", err))
+	}
+
+	for _, ft := range followedTags {
+		tag := &gtsmodel.Tag{}
+		if err := p.db.GetByID(ctx, ft.TagID, tag); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				continue
+			}
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("FollowedTagsGet: error getting tag %s: %s", ft.TagID, err))
+		}
+
+		apiTag, errWithCode := p.tagToMasto(tag, true)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		apiTags = append(apiTags, apiTag)
+	}
+
+	return apiTags, nil
+}
+
+// getTag returns the tag with the given name, ignoring case and any leading hash. If the tag isn't in the database yet,
+// a new tag is returned that hasn't been put in the database.
+func (p *processor) getTag(ctx context.Context, tagName string) (*gtsmodel.Tag, gtserror.WithCode) {
+	tagName = strings.TrimPrefix(tagName, "#")
+	if names := util.DeriveHashtagsFromStatus("#" + tagName); len(names) != 1 || names[0] != tagName {
+		err := fmt.Errorf("%s is not a valid hashtag", tagName)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	tags, err := p.db.TagStringsToTags(ctx, []string{tagName}, "", "")
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if len(tags) == 0 {
+		// the tag exists but can't be used
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("tag %s is not useable", tagName))
+	}
+
+	return tags[0], nil
+}
+
+// tagIDs returns the IDs of the tags with the given names, skipping any invalid names.
+func (p *processor) tagIDs(ctx context.Context, tagNames []string) ([]string, error) {
+	ids := []string{}
+	for _, tagName := range tagNames {
+		tag, errWithCode := p.getTag(ctx, tagName)
+		if errWithCode != nil {
+			if errWithCode.Code() == http.StatusInternalServerError {
+				return nil, errWithCode
+			}
+			continue
+		}
+		ids = append(ids, tag.ID)
+	}
+	return ids, nil
+}
+
+// followsTag returns true if the given account follows the tag with the given ID.
+func (p *processor) followsTag(ctx context.Context, accountID string, tagID string) (bool, error) {
+	if err := p.db.GetWhere(ctx, []db.Where{
+		{Key: "account_id", Value: accountID},
+		{Key: "tag_id", Value: tagID},
+	}, &gtsmodel.FollowedTag{}); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return false, nil
+		}
+		return false, fmt.Errorf("error checking if account %s follows tag %s: %s", accountID, tagID, err)
+	}
+	return true, nil
+}
+
+func (p *processor) tagToMasto(tag *gtsmodel.Tag, following bool) (*apimodel.Tag, gtserror.WithCode) {
+	apiTag, err := p.tc.TagToMasto(tag)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting tag %s to api representation: %s", tag.ID, err))
+	}
+	apiTag.Following = &following
+	return &apiTag, nil
+}
+
+// tagFollowerIDs returns the IDs of the local accounts that follow any of the tags used in the given status.
+// Only public statuses are put in the timelines of tag followers, so for other statuses nothing is returned.
+func (p *processor) tagFollowerIDs(ctx context.Context, status *gtsmodel.Status) ([]string, error) {
+	accountIDs := []string{}
+	if status.Visibility != gtsmodel.VisibilityPublic || status.BoostOfID != "" {
+		return accountIDs, nil
+	}
+
+	for _, tagID := range status.Tags {
+		followedTags := []*gtsmodel.FollowedTag{}
+		if err := p.db.GetWhere(ctx, []db.Where{{Key: "tag_id", Value: tagID}}, &followedTags); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				continue
+			}
+			return nil, fmt.Errorf("error getting followers of tag %s: %s", tagID, err)
+		}
+		for _, ft := range followedTags {
+			accountIDs = append(accountIDs, ft.AccountID)
+		}
+	}
+
+	return accountIDs, nil
+}
@@ -0,0 +1,151 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type TagTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *TagTestSuite) authed(accountName string) *oauth.Auth {
+	return &oauth.Auth{
+		Account:     suite.testAccounts[accountName],
+		Application: suite.testApplications["application_1"],
+	}
+}
+
+// post creates a public status with the given text as local_account_1, without federating it.
+func (suite *TagTestSuite) post(text string) *apimodel.Status {
+	federated := false
+	status, err := suite.processor.StatusCreate(context.Background(), suite.authed("local_account_1"), &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status:     text,
+			Visibility: apimodel.VisibilityPublic,
+		},
+		AdvancedVisibilityFlagsForm: apimodel.AdvancedVisibilityFlagsForm{
+			Federated: &federated,
+		},
+	})
+	suite.NoError(err)
+	return status
+}
+
+func (suite *TagTestSuite) tagTimeline(tagName string, anyTagNames []string, allTagNames []string, noneTagNames []string, mediaOnly bool) []string {
+	resp, errWithCode := suite.processor.TagTimelineGet(context.Background(), suite.authed("local_account_2"), tagName, anyTagNames, allTagNames, noneTagNames, "", "", "", 20, false, mediaOnly)
+	suite.Nil(errWithCode)
+	return statusIDs(resp.Statuses)
+}
+
+func (suite *TagTestSuite) TestTagTimeline() {
+	welcomeStatusID := suite.testStatuses["admin_account_status_1"].ID
+	gardenStatus := suite.post("my #Garden is looking lovely today #welcome")
+	plantsStatus := suite.post("some new #plants for the #garden")
+
+	// tags are matched without regard to case
+	suite.Equal([]string{plantsStatus.ID, gardenStatus.ID}, suite.tagTimeline("GARDEN", nil, nil, nil, false))
+	suite.Equal([]string{gardenStatus.ID, welcomeStatusID}, suite.tagTimeline("#welcome", nil, nil, nil, false))
+
+	// any widens the timeline, all and none narrow it down
+	suite.Equal([]string{plantsStatus.ID, gardenStatus.ID, welcomeStatusID}, suite.tagTimeline("welcome", []string{"plants"}, nil, nil, false))
+	suite.Equal([]string{gardenStatus.ID}, suite.tagTimeline("garden", nil, []string{"welcome"}, nil, false))
+	suite.Equal([]string{plantsStatus.ID}, suite.tagTimeline("garden", nil, nil, []string{"welcome"}, false))
+	suite.Empty(suite.tagTimeline("garden", nil, []string{"nonexistent"}, nil, false))
+
+	// only the admin status has media attached
+	suite.Equal([]string{welcomeStatusID}, suite.tagTimeline("welcome", nil, nil, nil, true))
+
+	// a tag that hasn't been used yet just has no statuses, and an invalid one is rejected
+	suite.Empty(suite.tagTimeline("nonexistent", nil, nil, nil, false))
+	_, errWithCode := suite.processor.TagTimelineGet(context.Background(), suite.authed("local_account_2"), "not a tag", nil, nil, nil, "", "", "", 20, false, false)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+}
+
+func (suite *TagTestSuite) TestFollowTag() {
+	ctx := context.Background()
+	authed := suite.authed("local_account_2")
+
+	// following a tag that nobody has used yet creates it
+	tag, errWithCode := suite.processor.TagFollow(ctx, authed, "Plants")
+	suite.Nil(errWithCode)
+	suite.Equal("Plants", tag.Name)
+	suite.True(*tag.Following)
+
+	tag, errWithCode = suite.processor.TagGet(ctx, authed, "plants")
+	suite.Nil(errWithCode)
+	suite.True(*tag.Following)
+
+	followedTags, errWithCode := suite.processor.FollowedTagsGet(ctx, authed)
+	suite.Nil(errWithCode)
+	suite.Len(followedTags, 1)
+
+	suite.NoError(suite.processor.Start(ctx))
+	defer func() {
+		suite.NoError(suite.processor.Stop())
+	}()
+
+	// local_account_2 doesn't follow local_account_1, but gets their status through the followed tag
+	stream, errWithCode := suite.processor.OpenStreamForAccount(ctx, suite.testAccounts["local_account_2"], gtsmodel.StreamTypeHashtag, "", "#PLANTS")
+	suite.Nil(errWithCode)
+
+	status := suite.post("look at my new #plants")
+	suite.Eventually(func() bool {
+		resp, errWithCode := suite.processor.HomeTimelineGet(ctx, authed, "", "", "", 20, false)
+		if errWithCode != nil {
+			return false
+		}
+		for _, s := range resp.Statuses {
+			if s.ID == status.ID {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	select {
+	case msg := <-stream.Messages:
+		suite.Equal("update", msg.Event)
+		suite.Equal([]string{gtsmodel.StreamTypeHashtag, "plants"}, msg.Stream)
+		suite.Contains(msg.Payload, status.ID)
+	case <-time.After(5 * time.Second):
+		suite.FailNow("timed out waiting for streamed status")
+	}
+
+	tag, errWithCode = suite.processor.TagUnfollow(ctx, authed, "plants")
+	suite.Nil(errWithCode)
+	suite.False(*tag.Following)
+
+	followedTags, errWithCode = suite.processor.FollowedTagsGet(ctx, authed)
+	suite.Nil(errWithCode)
+	suite.Empty(followedTags)
+}
+
+func TestTagTestSuite(t *testing.T) {
+	suite.Run(t, new(TagTestSuite))
+}
@@ -19,6 +19,7 @@
 package processing
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"sync"
@@ -60,8 +61,8 @@ func (p *processor) packageStatusResponse(statuses []*apimodel.Status, path stri
 	return resp, nil
 }
 
-func (p *processor) HomeTimelineGet(authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
-	statuses, err := p.timelineManager.HomeTimeline(authed.Account.ID, maxID, sinceID, minID, limit, local)
+func (p *processor) HomeTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	statuses, err := p.timelineManager.HomeTimeline(ctx, authed.Account.ID, maxID, sinceID, minID, limit, local)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
@@ -75,8 +76,8 @@ func (p *processor) HomeTimelineGet(authed *oauth.Auth, maxID string, sinceID st
 	return p.packageStatusResponse(statuses, "api/v1/timelines/home", statuses[len(statuses)-1].ID, statuses[0].ID, limit)
 }
 
-func (p *processor) PublicTimelineGet(authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
-	statuses, err := p.db.GetPublicTimelineForAccount(authed.Account.ID, maxID, sinceID, minID, limit, local)
+func (p *processor) PublicTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	statuses, err := p.db.GetPublicTimelineForAccount(ctx, authed.Account.ID, maxID, sinceID, minID, limit, local)
 	if err != nil {
 		if _, ok := err.(db.ErrNoEntries); ok {
 			// there are just no entries left
@@ -88,7 +89,7 @@ func (p *processor) PublicTimelineGet(authed *oauth.Auth, maxID string, sinceID
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	s, err := p.filterPublicStatuses(authed, statuses)
+	s, err := p.filterPublicStatuses(ctx, authed, statuses)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
@@ -96,8 +97,8 @@ func (p *processor) PublicTimelineGet(authed *oauth.Auth, maxID string, sinceID
 	return p.packageStatusResponse(s, "api/v1/timelines/public", s[len(s)-1].ID, s[0].ID, limit)
 }
 
-func (p *processor) FavedTimelineGet(authed *oauth.Auth, maxID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
-	statuses, nextMaxID, prevMinID, err := p.db.GetFavedTimelineForAccount(authed.Account.ID, maxID, minID, limit)
+func (p *processor) FavedTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	statuses, nextMaxID, prevMinID, err := p.db.GetFavedTimelineForAccount(ctx, authed.Account.ID, maxID, minID, limit)
 	if err != nil {
 		if _, ok := err.(db.ErrNoEntries); ok {
 			// there are just no entries left
@@ -109,7 +110,7 @@ func (p *processor) FavedTimelineGet(authed *oauth.Auth, maxID string, minID str
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	s, err := p.filterFavedStatuses(authed, statuses)
+	s, err := p.filterVisibleStatuses(ctx, authed, statuses)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
@@ -117,13 +118,40 @@ func (p *processor) FavedTimelineGet(authed *oauth.Auth, maxID string, minID str
 	return p.packageStatusResponse(s, "api/v1/favourites", nextMaxID, prevMinID, limit)
 }
 
-func (p *processor) filterPublicStatuses(authed *oauth.Auth, statuses []*gtsmodel.Status) ([]*apimodel.Status, error) {
+func (p *processor) BookmarkedTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	statuses, nextMaxID, prevMinID, err := p.db.GetBookmarkedTimelineForAccount(ctx, authed.Account.ID, maxID, minID, limit)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			// there are just no entries left
+			return &apimodel.StatusTimelineResponse{
+				Statuses: []*apimodel.Status{},
+			}, nil
+		}
+		// there's an actual error
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// statuses might have become invisible to the requester since they were bookmarked
+	s, err := p.filterVisibleStatuses(ctx, authed, statuses)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.packageStatusResponse(s, "api/v1/bookmarks", nextMaxID, prevMinID, limit)
+}
+
+func (p *processor) filterPublicStatuses(ctx context.Context, authed *oauth.Auth, statuses []*gtsmodel.Status) ([]*apimodel.Status, error) {
 	l := p.log.WithField("func", "filterPublicStatuses")
 
+	filters, err := p.filter.KeywordFilters(ctx, authed.Account, gtsmodel.FilterContextPublic)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("filterPublicStatuses: error getting keyword filters: %s", err))
+	}
+
 	apiStatuses := []*apimodel.Status{}
 	for _, s := range statuses {
 		targetAccount := &gtsmodel.Account{}
-		if err := p.db.GetByID(s.AccountID, targetAccount); err != nil {
+		if err := p.db.GetByID(ctx, s.AccountID, targetAccount); err != nil {
 			if _, ok := err.(db.ErrNoEntries); ok {
 				l.Debugf("filterPublicStatuses: skipping status %s because account %s can't be found in the db", s.ID, s.AccountID)
 				continue
@@ -131,7 +159,7 @@ func (p *processor) filterPublicStatuses(authed *oauth.Auth, statuses []*gtsmode
 			return nil, gtserror.NewErrorInternalError(fmt.Errorf("filterPublicStatuses: error getting status author: %s", err))
 		}
 
-		timelineable, err := p.filter.StatusPublictimelineable(s, authed.Account)
+		timelineable, err := p.filter.StatusPublictimelineable(ctx, s, authed.Account)
 		if err != nil {
 			l.Debugf("filterPublicStatuses: skipping status %s because of an error checking status visibility: %s", s.ID, err)
 			continue
@@ -140,7 +168,16 @@ func (p *processor) filterPublicStatuses(authed *oauth.Auth, statuses []*gtsmode
 			continue
 		}
 
-		apiStatus, err := p.tc.StatusToMasto(s, authed.Account)
+		filtered, err := p.filter.StatusKeywordFiltered(ctx, s, filters)
+		if err != nil {
+			l.Debugf("filterPublicStatuses: skipping status %s because of an error checking keyword filters: %s", s.ID, err)
+			continue
+		}
+		if filtered {
+			continue
+		}
+
+		apiStatus, err := p.tc.StatusToMasto(ctx, s, authed.Account)
 		if err != nil {
 			l.Debugf("filterPublicStatuses: skipping status %s because it couldn't be converted to its mastodon representation: %s", s.ID, err)
 			continue
@@ -152,32 +189,33 @@ func (p *processor) filterPublicStatuses(authed *oauth.Auth, statuses []*gtsmode
 	return apiStatuses, nil
 }
 
-func (p *processor) filterFavedStatuses(authed *oauth.Auth, statuses []*gtsmodel.Status) ([]*apimodel.Status, error) {
-	l := p.log.WithField("func", "filterFavedStatuses")
+// filterVisibleStatuses converts the given statuses to their mastodon representation, skipping any that aren't visible to the requester.
+func (p *processor) filterVisibleStatuses(ctx context.Context, authed *oauth.Auth, statuses []*gtsmodel.Status) ([]*apimodel.Status, error) {
+	l := p.log.WithField("func", "filterVisibleStatuses")
 
 	apiStatuses := []*apimodel.Status{}
 	for _, s := range statuses {
 		targetAccount := &gtsmodel.Account{}
-		if err := p.db.GetByID(s.AccountID, targetAccount); err != nil {
+		if err := p.db.GetByID(ctx, s.AccountID, targetAccount); err != nil {
 			if _, ok := err.(db.ErrNoEntries); ok {
-				l.Debugf("filterFavedStatuses: skipping status %s because account %s can't be found in the db", s.ID, s.AccountID)
+				l.Debugf("filterVisibleStatuses: skipping status %s because account %s can't be found in the db", s.ID, s.AccountID)
 				continue
 			}
-			return nil, gtserror.NewErrorInternalError(fmt.Errorf("filterPublicStatuses: error getting status author: %s", err))
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("filterVisibleStatuses: error getting status author: %s", err))
 		}
 
-		timelineable, err := p.filter.StatusVisible(s, authed.Account)
+		timelineable, err := p.filter.StatusVisible(ctx, s, authed.Account)
 		if err != nil {
-			l.Debugf("filterFavedStatuses: skipping status %s because of an error checking status visibility: %s", s.ID, err)
+			l.Debugf("filterVisibleStatuses: skipping status %s because of an error checking status visibility: %s", s.ID, err)
 			continue
 		}
 		if !timelineable {
 			continue
 		}
 
-		apiStatus, err := p.tc.StatusToMasto(s, authed.Account)
+		apiStatus, err := p.tc.StatusToMasto(ctx, s, authed.Account)
 		if err != nil {
-			l.Debugf("filterFavedStatuses: skipping status %s because it couldn't be converted to its mastodon representation: %s", s.ID, err)
+			l.Debugf("filterVisibleStatuses: skipping status %s because it couldn't be converted to its mastodon representation: %s", s.ID, err)
 			continue
 		}
 
@@ -187,7 +225,7 @@ func (p *processor) filterFavedStatuses(authed *oauth.Auth, statuses []*gtsmodel
 	return apiStatuses, nil
 }
 
-func (p *processor) initTimelines() error {
+func (p *processor) initTimelines(ctx context.Context) error {
 	// get all local accounts (ie., domain = nil) that aren't suspended (suspended_at = nil)
 	localAccounts := []*gtsmodel.Account{}
 	where := []db.Where{
@@ -198,7 +236,7 @@ func (p *processor) initTimelines() error {
 			Key: "suspended_at", Value: nil,
 		},
 	}
-	if err := p.db.GetWhere(where, &localAccounts); err != nil {
+	if err := p.db.GetWhere(ctx, where, &localAccounts); err != nil {
 		if _, ok := err.(db.ErrNoEntries); ok {
 			return nil
 		}
@@ -212,7 +250,7 @@ func (p *processor) initTimelines() error {
 	for _, localAccount := range localAccounts {
 		// to save time we can populate the timelines asynchronously
 		// this will go heavy on the database, but since we're not actually serving yet it doesn't really matter
-		go p.initTimelineFor(localAccount, wg)
+		go p.initTimelineFor(ctx, localAccount, wg)
 	}
 
 	// wait for all timelines to be populated before we exit
@@ -220,7 +258,7 @@ func (p *processor) initTimelines() error {
 	return nil
 }
 
-func (p *processor) initTimelineFor(account *gtsmodel.Account, wg *sync.WaitGroup) {
+func (p *processor) initTimelineFor(ctx context.Context, account *gtsmodel.Account, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	l := p.log.WithFields(logrus.Fields{
@@ -230,57 +268,57 @@ func (p *processor) initTimelineFor(account *gtsmodel.Account, wg *sync.WaitGrou
 
 	desiredIndexLength := p.timelineManager.GetDesiredIndexLength()
 
-	statuses, err := p.db.GetHomeTimelineForAccount(account.ID, "", "", "", desiredIndexLength, false)
+	statuses, err := p.db.GetHomeTimelineForAccount(ctx, account.ID, "", "", "", desiredIndexLength, false)
 	if err != nil {
 		if _, ok := err.(db.ErrNoEntries); !ok {
 			l.Error(fmt.Errorf("initTimelineFor: error getting statuses: %s", err))
 		}
 		return
 	}
-	p.indexAndIngest(statuses, account, desiredIndexLength)
+	p.indexAndIngest(ctx, statuses, account, desiredIndexLength)
 
-	lengthNow := p.timelineManager.GetIndexedLength(account.ID)
+	lengthNow := p.timelineManager.GetIndexedLength(ctx, account.ID)
 	if lengthNow < desiredIndexLength {
 		// try and get more posts from the last ID onwards
-		rearmostStatusID, err := p.timelineManager.GetOldestIndexedID(account.ID)
+		rearmostStatusID, err := p.timelineManager.GetOldestIndexedID(ctx, account.ID)
 		if err != nil {
 			l.Error(fmt.Errorf("initTimelineFor: error getting id of rearmost status: %s", err))
 			return
 		}
 
 		if rearmostStatusID != "" {
-			moreStatuses, err := p.db.GetHomeTimelineForAccount(account.ID, rearmostStatusID, "", "", desiredIndexLength/2, false)
+			moreStatuses, err := p.db.GetHomeTimelineForAccount(ctx, account.ID, rearmostStatusID, "", "", desiredIndexLength/2, false)
 			if err != nil {
 				l.Error(fmt.Errorf("initTimelineFor: error getting more statuses: %s", err))
 				return
 			}
-			p.indexAndIngest(moreStatuses, account, desiredIndexLength)
+			p.indexAndIngest(ctx, moreStatuses, account, desiredIndexLength)
 		}
 	}
 
 	l.Debugf("prepared timeline of length %d for account %s", lengthNow, account.ID)
 }
 
-func (p *processor) indexAndIngest(statuses []*gtsmodel.Status, timelineAccount *gtsmodel.Account, desiredIndexLength int) {
+func (p *processor) indexAndIngest(ctx context.Context, statuses []*gtsmodel.Status, timelineAccount *gtsmodel.Account, desiredIndexLength int) {
 	l := p.log.WithFields(logrus.Fields{
 		"func":      "indexAndIngest",
 		"accountID": timelineAccount.ID,
 	})
 
 	for _, s := range statuses {
-		timelineable, err := p.filter.StatusHometimelineable(s, timelineAccount)
+		timelineable, err := p.filter.StatusHometimelineable(ctx, s, timelineAccount)
 		if err != nil {
 			l.Error(fmt.Errorf("initTimelineFor: error checking home timelineability of status %s: %s", s.ID, err))
 			continue
 		}
 		if timelineable {
-			if _, err := p.timelineManager.Ingest(s, timelineAccount.ID); err != nil {
+			if _, err := p.timelineManager.Ingest(ctx, s, timelineAccount.ID); err != nil {
 				l.Error(fmt.Errorf("initTimelineFor: error ingesting status %s: %s", s.ID, err))
 				continue
 			}
 
 			// check if we have enough posts now and return if we do
-			if p.timelineManager.GetIndexedLength(timelineAccount.ID) >= desiredIndexLength {
+			if p.timelineManager.GetIndexedLength(ctx, timelineAccount.ID) >= desiredIndexLength {
 				return
 			}
 		}
@@ -0,0 +1,752 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/tracing"
+)
+
+// tracingProcessor wraps a Processor, recording a span around each of its client API and federation API functions.
+//
+// Start and Stop are passed straight through to the wrapped Processor.
+type tracingProcessor struct {
+	Processor
+}
+
+func (p *tracingProcessor) AccountCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountCreateRequest) (*apimodel.Token, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountCreate")
+	result, err := p.Processor.AccountCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Account, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountGet")
+	result, err := p.Processor.AccountGet(ctx, authed, targetAccountID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountUpdate(ctx context.Context, authed *oauth.Auth, form *apimodel.UpdateCredentialsRequest) (*apimodel.Account, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountUpdate")
+	result, err := p.Processor.AccountUpdate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountStatusesGet(ctx context.Context, authed *oauth.Auth, targetAccountID string, limit int, excludeReplies bool, maxID string, pinned bool, mediaOnly bool) ([]apimodel.Status, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountStatusesGet")
+	result, err := p.Processor.AccountStatusesGet(ctx, authed, targetAccountID, limit, excludeReplies, maxID, pinned, mediaOnly)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountFollowersGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) ([]apimodel.Account, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountFollowersGet")
+	result, err := p.Processor.AccountFollowersGet(ctx, authed, targetAccountID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountFollowingGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) ([]apimodel.Account, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountFollowingGet")
+	result, err := p.Processor.AccountFollowingGet(ctx, authed, targetAccountID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountRelationshipGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountRelationshipGet")
+	result, err := p.Processor.AccountRelationshipGet(ctx, authed, targetAccountID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountFollowCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountFollowRequest) (*apimodel.Relationship, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountFollowCreate")
+	result, err := p.Processor.AccountFollowCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountFollowRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountFollowRemove")
+	result, err := p.Processor.AccountFollowRemove(ctx, authed, targetAccountID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountBlockCreate(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountBlockCreate")
+	result, err := p.Processor.AccountBlockCreate(ctx, authed, targetAccountID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountBlockRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountBlockRemove")
+	result, err := p.Processor.AccountBlockRemove(ctx, authed, targetAccountID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountMuteCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountMuteRequest) (*apimodel.Relationship, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountMuteCreate")
+	result, err := p.Processor.AccountMuteCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountMuteRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountMuteRemove")
+	result, err := p.Processor.AccountMuteRemove(ctx, authed, targetAccountID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminEmojiCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.EmojiCreateRequest) (*apimodel.Emoji, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminEmojiCreate")
+	result, err := p.Processor.AdminEmojiCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminDomainBlockCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) (*apimodel.DomainBlock, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminDomainBlockCreate")
+	result, err := p.Processor.AdminDomainBlockCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminDomainBlocksImport(ctx context.Context, authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) ([]*apimodel.DomainBlock, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminDomainBlocksImport")
+	result, err := p.Processor.AdminDomainBlocksImport(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminDomainBlocksGet(ctx context.Context, authed *oauth.Auth, export bool) ([]*apimodel.DomainBlock, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminDomainBlocksGet")
+	result, err := p.Processor.AdminDomainBlocksGet(ctx, authed, export)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminDomainBlockGet(ctx context.Context, authed *oauth.Auth, id string, export bool) (*apimodel.DomainBlock, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminDomainBlockGet")
+	result, err := p.Processor.AdminDomainBlockGet(ctx, authed, id, export)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminDomainBlockDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.DomainBlock, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminDomainBlockDelete")
+	result, err := p.Processor.AdminDomainBlockDelete(ctx, authed, id)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminDeliveriesGet(ctx context.Context, authed *oauth.Auth, domain string) ([]*apimodel.AdminDeliveryInfo, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminDeliveriesGet")
+	result, err := p.Processor.AdminDeliveriesGet(ctx, authed, domain)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminReportsGet(ctx context.Context, authed *oauth.Auth, resolved bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*apimodel.AdminReportInfo, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminReportsGet")
+	result, err := p.Processor.AdminReportsGet(ctx, authed, resolved, accountID, targetAccountID, maxID, sinceID, limit)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminReportGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminReportGet")
+	result, err := p.Processor.AdminReportGet(ctx, authed, id)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminReportAssign(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminReportAssign")
+	result, err := p.Processor.AdminReportAssign(ctx, authed, id)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminReportUnassign(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminReportUnassign")
+	result, err := p.Processor.AdminReportUnassign(ctx, authed, id)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminReportNote(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminReportNoteRequest) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminReportNote")
+	result, err := p.Processor.AdminReportNote(ctx, authed, id, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminReportResolve(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminReportResolveRequest) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminReportResolve")
+	result, err := p.Processor.AdminReportResolve(ctx, authed, id, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AdminReportReopen(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AdminReportReopen")
+	result, err := p.Processor.AdminReportReopen(ctx, authed, id)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AppCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ApplicationCreateRequest) (*apimodel.Application, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AppCreate")
+	result, err := p.Processor.AppCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) BlocksGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.BlocksResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.BlocksGet")
+	result, err := p.Processor.BlocksGet(ctx, authed, maxID, sinceID, limit)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ConversationsGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.ConversationsResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ConversationsGet")
+	result, err := p.Processor.ConversationsGet(ctx, authed, maxID, sinceID, limit)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ConversationRead(ctx context.Context, authed *oauth.Auth, conversationID string) (*apimodel.Conversation, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ConversationRead")
+	result, err := p.Processor.ConversationRead(ctx, authed, conversationID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ConversationDelete(ctx context.Context, authed *oauth.Auth, conversationID string) gtserror.WithCode {
+	ctx, span := tracing.StartSpan(ctx, "processor.ConversationDelete")
+	err := p.Processor.ConversationDelete(ctx, authed, conversationID)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+func (p *tracingProcessor) FileGet(ctx context.Context, authed *oauth.Auth, form *apimodel.GetContentRequestForm) (*apimodel.Content, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.FileGet")
+	result, err := p.Processor.FileGet(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) FiltersGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Filter, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.FiltersGet")
+	result, err := p.Processor.FiltersGet(ctx, authed)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) FilterGet(ctx context.Context, authed *oauth.Auth, filterID string) (*apimodel.Filter, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.FilterGet")
+	result, err := p.Processor.FilterGet(ctx, authed, filterID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) FilterCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.FilterCreateRequest) (*apimodel.Filter, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.FilterCreate")
+	result, err := p.Processor.FilterCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) FilterUpdate(ctx context.Context, authed *oauth.Auth, filterID string, form *apimodel.FilterCreateRequest) (*apimodel.Filter, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.FilterUpdate")
+	result, err := p.Processor.FilterUpdate(ctx, authed, filterID, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) FilterDelete(ctx context.Context, authed *oauth.Auth, filterID string) (*apimodel.Filter, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.FilterDelete")
+	result, err := p.Processor.FilterDelete(ctx, authed, filterID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) FollowRequestsGet(ctx context.Context, auth *oauth.Auth) ([]apimodel.Account, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.FollowRequestsGet")
+	result, err := p.Processor.FollowRequestsGet(ctx, auth)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) FollowRequestAccept(ctx context.Context, auth *oauth.Auth, accountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.FollowRequestAccept")
+	result, err := p.Processor.FollowRequestAccept(ctx, auth, accountID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) InstanceGet(ctx context.Context, domain string) (*apimodel.Instance, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.InstanceGet")
+	result, err := p.Processor.InstanceGet(ctx, domain)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) InstancePatch(ctx context.Context, form *apimodel.InstanceSettingsUpdateRequest) (*apimodel.Instance, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.InstancePatch")
+	result, err := p.Processor.InstancePatch(ctx, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ListsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.List, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ListsGet")
+	result, err := p.Processor.ListsGet(ctx, authed)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ListGet(ctx context.Context, authed *oauth.Auth, listID string) (*apimodel.List, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ListGet")
+	result, err := p.Processor.ListGet(ctx, authed, listID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ListCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ListCreateRequest) (*apimodel.List, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ListCreate")
+	result, err := p.Processor.ListCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ListUpdate(ctx context.Context, authed *oauth.Auth, listID string, form *apimodel.ListCreateRequest) (*apimodel.List, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ListUpdate")
+	result, err := p.Processor.ListUpdate(ctx, authed, listID, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ListDelete(ctx context.Context, authed *oauth.Auth, listID string) (*apimodel.List, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ListDelete")
+	result, err := p.Processor.ListDelete(ctx, authed, listID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ListAccountsGet(ctx context.Context, authed *oauth.Auth, listID string, maxID string, sinceID string, limit int) (*apimodel.ListAccountsResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ListAccountsGet")
+	result, err := p.Processor.ListAccountsGet(ctx, authed, listID, maxID, sinceID, limit)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ListAccountsAdd(ctx context.Context, authed *oauth.Auth, listID string, form *apimodel.ListAccountsChangeRequest) (*apimodel.List, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ListAccountsAdd")
+	result, err := p.Processor.ListAccountsAdd(ctx, authed, listID, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ListAccountsRemove(ctx context.Context, authed *oauth.Auth, listID string, form *apimodel.ListAccountsChangeRequest) (*apimodel.List, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ListAccountsRemove")
+	result, err := p.Processor.ListAccountsRemove(ctx, authed, listID, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AccountListsGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) ([]*apimodel.List, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AccountListsGet")
+	result, err := p.Processor.AccountListsGet(ctx, authed, targetAccountID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) MediaCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AttachmentRequest) (*apimodel.Attachment, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.MediaCreate")
+	result, err := p.Processor.MediaCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) MediaGet(ctx context.Context, authed *oauth.Auth, attachmentID string) (*apimodel.Attachment, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.MediaGet")
+	result, err := p.Processor.MediaGet(ctx, authed, attachmentID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) MediaUpdate(ctx context.Context, authed *oauth.Auth, attachmentID string, form *apimodel.AttachmentUpdateRequest) (*apimodel.Attachment, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.MediaUpdate")
+	result, err := p.Processor.MediaUpdate(ctx, authed, attachmentID, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) MutesGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.MutesResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.MutesGet")
+	result, err := p.Processor.MutesGet(ctx, authed, maxID, sinceID, limit)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) NotificationsGet(ctx context.Context, authed *oauth.Auth, limit int, maxID string, sinceID string) ([]*apimodel.Notification, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.NotificationsGet")
+	result, err := p.Processor.NotificationsGet(ctx, authed, limit, maxID, sinceID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) PollGet(ctx context.Context, authed *oauth.Auth, pollID string) (*apimodel.Poll, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.PollGet")
+	result, err := p.Processor.PollGet(ctx, authed, pollID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) PollVote(ctx context.Context, authed *oauth.Auth, pollID string, choices []int) (*apimodel.Poll, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.PollVote")
+	result, err := p.Processor.PollVote(ctx, authed, pollID, choices)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ReportCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ReportCreateRequest) (*apimodel.Report, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ReportCreate")
+	result, err := p.Processor.ReportCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ScheduledStatusCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdvancedStatusCreateForm) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ScheduledStatusCreate")
+	result, err := p.Processor.ScheduledStatusCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ScheduledStatusesGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.ScheduledStatusesResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ScheduledStatusesGet")
+	result, err := p.Processor.ScheduledStatusesGet(ctx, authed, maxID, sinceID, limit)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ScheduledStatusGet(ctx context.Context, authed *oauth.Auth, scheduledStatusID string) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ScheduledStatusGet")
+	result, err := p.Processor.ScheduledStatusGet(ctx, authed, scheduledStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ScheduledStatusUpdate(ctx context.Context, authed *oauth.Auth, scheduledStatusID string, form *apimodel.ScheduledStatusUpdateRequest) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ScheduledStatusUpdate")
+	result, err := p.Processor.ScheduledStatusUpdate(ctx, authed, scheduledStatusID, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ScheduledStatusDelete(ctx context.Context, authed *oauth.Auth, scheduledStatusID string) gtserror.WithCode {
+	ctx, span := tracing.StartSpan(ctx, "processor.ScheduledStatusDelete")
+	err := p.Processor.ScheduledStatusDelete(ctx, authed, scheduledStatusID)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+func (p *tracingProcessor) SearchGet(ctx context.Context, authed *oauth.Auth, searchQuery *apimodel.SearchQuery) (*apimodel.SearchResult, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.SearchGet")
+	result, err := p.Processor.SearchGet(ctx, authed, searchQuery)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusCreate")
+	result, err := p.Processor.StatusCreate(ctx, authed, form)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusDelete(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusDelete")
+	result, err := p.Processor.StatusDelete(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusFave(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusFave")
+	result, err := p.Processor.StatusFave(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusBookmark(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusBookmark")
+	result, err := p.Processor.StatusBookmark(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusUnbookmark(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusUnbookmark")
+	result, err := p.Processor.StatusUnbookmark(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusMute(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusMute")
+	result, err := p.Processor.StatusMute(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusUnmute(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusUnmute")
+	result, err := p.Processor.StatusUnmute(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusPin(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusPin")
+	result, err := p.Processor.StatusPin(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusUnpin(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusUnpin")
+	result, err := p.Processor.StatusUnpin(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusBoost(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusBoost")
+	result, err := p.Processor.StatusBoost(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusUnboost(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusUnboost")
+	result, err := p.Processor.StatusUnboost(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusBoostedBy(ctx context.Context, authed *oauth.Auth, targetStatusID string) ([]*apimodel.Account, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusBoostedBy")
+	result, err := p.Processor.StatusBoostedBy(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusFavedBy(ctx context.Context, authed *oauth.Auth, targetStatusID string) ([]*apimodel.Account, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusFavedBy")
+	result, err := p.Processor.StatusFavedBy(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusGet(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusGet")
+	result, err := p.Processor.StatusGet(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusUnfave(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusUnfave")
+	result, err := p.Processor.StatusUnfave(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) StatusGetContext(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Context, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.StatusGetContext")
+	result, err := p.Processor.StatusGetContext(ctx, authed, targetStatusID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) TagGet(ctx context.Context, authed *oauth.Auth, tagName string) (*apimodel.Tag, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.TagGet")
+	result, err := p.Processor.TagGet(ctx, authed, tagName)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) TagFollow(ctx context.Context, authed *oauth.Auth, tagName string) (*apimodel.Tag, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.TagFollow")
+	result, err := p.Processor.TagFollow(ctx, authed, tagName)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) TagUnfollow(ctx context.Context, authed *oauth.Auth, tagName string) (*apimodel.Tag, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.TagUnfollow")
+	result, err := p.Processor.TagUnfollow(ctx, authed, tagName)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) FollowedTagsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Tag, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.FollowedTagsGet")
+	result, err := p.Processor.FollowedTagsGet(ctx, authed)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) HomeTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.HomeTimelineGet")
+	result, err := p.Processor.HomeTimelineGet(ctx, authed, maxID, sinceID, minID, limit, local)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) ListTimelineGet(ctx context.Context, authed *oauth.Auth, listID string, maxID string, sinceID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.ListTimelineGet")
+	result, err := p.Processor.ListTimelineGet(ctx, authed, listID, maxID, sinceID, minID, limit)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) PublicTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.PublicTimelineGet")
+	result, err := p.Processor.PublicTimelineGet(ctx, authed, maxID, sinceID, minID, limit, local)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) TagTimelineGet(ctx context.Context, authed *oauth.Auth, tagName string, anyTagNames []string, allTagNames []string, noneTagNames []string, maxID string, sinceID string, minID string, limit int, local bool, mediaOnly bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.TagTimelineGet")
+	result, err := p.Processor.TagTimelineGet(ctx, authed, tagName, anyTagNames, allTagNames, noneTagNames, maxID, sinceID, minID, limit, local, mediaOnly)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) FavedTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.FavedTimelineGet")
+	result, err := p.Processor.FavedTimelineGet(ctx, authed, maxID, minID, limit)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) BookmarkedTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.BookmarkedTimelineGet")
+	result, err := p.Processor.BookmarkedTimelineGet(ctx, authed, maxID, minID, limit)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) AuthorizeStreamingRequest(ctx context.Context, accessToken string) (*gtsmodel.Account, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.AuthorizeStreamingRequest")
+	result, err := p.Processor.AuthorizeStreamingRequest(ctx, accessToken)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) OpenStreamForAccount(ctx context.Context, account *gtsmodel.Account, streamType string, listID string, tag string) (*gtsmodel.Stream, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.OpenStreamForAccount")
+	result, err := p.Processor.OpenStreamForAccount(ctx, account, streamType, listID, tag)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) GetFediUser(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.GetFediUser")
+	result, err := p.Processor.GetFediUser(ctx, requestedUsername, requestURL)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) GetFediFollowers(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.GetFediFollowers")
+	result, err := p.Processor.GetFediFollowers(ctx, requestedUsername, requestURL)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) GetFediFollowing(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.GetFediFollowing")
+	result, err := p.Processor.GetFediFollowing(ctx, requestedUsername, requestURL)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) GetFediFeatured(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.GetFediFeatured")
+	result, err := p.Processor.GetFediFeatured(ctx, requestedUsername, requestURL)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) GetFediStatus(ctx context.Context, requestedUsername string, requestedStatusID string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.GetFediStatus")
+	result, err := p.Processor.GetFediStatus(ctx, requestedUsername, requestedStatusID, requestURL)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) GetWebfingerAccount(ctx context.Context, requestedUsername string, requestURL *url.URL) (*apimodel.WellKnownResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.GetWebfingerAccount")
+	result, err := p.Processor.GetWebfingerAccount(ctx, requestedUsername, requestURL)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) GetNodeInfoRel(ctx context.Context, request *http.Request) (*apimodel.WellKnownResponse, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.GetNodeInfoRel")
+	result, err := p.Processor.GetNodeInfoRel(ctx, request)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) GetNodeInfo(ctx context.Context, request *http.Request) (*apimodel.Nodeinfo, gtserror.WithCode) {
+	ctx, span := tracing.StartSpan(ctx, "processor.GetNodeInfo")
+	result, err := p.Processor.GetNodeInfo(ctx, request)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (p *tracingProcessor) InboxPost(ctx context.Context, w http.ResponseWriter, r *http.Request) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "processor.InboxPost")
+	result, err := p.Processor.InboxPost(ctx, w, r)
+	tracing.EndSpan(span, err)
+	return result, err
+}
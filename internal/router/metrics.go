@@ -0,0 +1,69 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package router
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/metrics"
+)
+
+// metricsPath is the path at which prometheus metrics are served.
+const metricsPath = "/metrics"
+
+// useMetrics records metrics about every request handled by the engine, and serves them at metricsPath,
+// if metrics are enabled in the config.
+func useMetrics(cfg *config.Config, engine *gin.Engine) error {
+	if !cfg.MetricsConfig.Enabled {
+		return nil
+	}
+
+	engine.Use(metricsMiddleware)
+
+	handlers := []gin.HandlerFunc{}
+	if cfg.MetricsConfig.AuthEnabled {
+		if cfg.MetricsConfig.AuthUsername == "" || cfg.MetricsConfig.AuthPassword == "" {
+			return errors.New("metrics auth is enabled, but metrics auth username or password was not set")
+		}
+		handlers = append(handlers, gin.BasicAuth(gin.Accounts{
+			cfg.MetricsConfig.AuthUsername: cfg.MetricsConfig.AuthPassword,
+		}))
+	}
+	handlers = append(handlers, gin.WrapH(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
+
+	engine.GET(metricsPath, handlers...)
+	return nil
+}
+
+// metricsMiddleware records the count and duration of requests, by the route that handled them.
+func metricsMiddleware(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+
+	route := c.FullPath()
+	if route == "" {
+		// don't record the actual path for 404s, or we could end up with a huge number of different routes
+		route = "unmatched"
+	}
+	metrics.ObserveHTTPRequest(route, c.Request.Method, c.Writer.Status(), time.Since(start))
+}
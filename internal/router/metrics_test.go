@@ -0,0 +1,81 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+)
+
+type MetricsTestSuite struct {
+	suite.Suite
+	config *config.Config
+	engine *gin.Engine
+}
+
+func (suite *MetricsTestSuite) SetupTest() {
+	suite.config = config.TestDefault()
+	suite.config.MetricsConfig.Enabled = true
+	suite.config.MetricsConfig.AuthEnabled = true
+	suite.config.MetricsConfig.AuthUsername = "prometheus"
+	suite.config.MetricsConfig.AuthPassword = "hunter2"
+
+	gin.SetMode(gin.TestMode)
+	suite.engine = gin.New()
+	suite.NoError(useMetrics(suite.config, suite.engine))
+	suite.engine.GET("/api/v1/statuses/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+}
+
+func (suite *MetricsTestSuite) TestMetricsRequireAuth() {
+	recorder := httptest.NewRecorder()
+	suite.engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+}
+
+func (suite *MetricsTestSuite) TestMetricsRecordRoute() {
+	recorder := httptest.NewRecorder()
+	suite.engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/statuses/01F8MH75CBF9JFX4ZAD54N0W0R", nil))
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, metricsPath, nil)
+	request.SetBasicAuth("prometheus", "hunter2")
+	suite.engine.ServeHTTP(recorder, request)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	// the route pattern should be recorded, not the actual path
+	suite.Contains(recorder.Body.String(), `gotosocial_http_requests_total{method="GET",route="/api/v1/statuses/:id",status="200"}`)
+	suite.NotContains(recorder.Body.String(), "01F8MH75CBF9JFX4ZAD54N0W0R")
+}
+
+func (suite *MetricsTestSuite) TestMetricsAuthMissingCredentials() {
+	suite.config.MetricsConfig.AuthPassword = ""
+	suite.Error(useMetrics(suite.config, gin.New()))
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
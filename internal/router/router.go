@@ -109,7 +109,7 @@ func (r *router) Stop(ctx context.Context) error {
 //
 // The given DB is only used in the New function for parsing config values, and is not otherwise
 // pinned to the router.
-func New(cfg *config.Config, db db.DB, logger *logrus.Logger) (Router, error) {
+func New(ctx context.Context, cfg *config.Config, db db.DB, logger *logrus.Logger) (Router, error) {
 
 	// gin has different log modes; for convenience, we match the gin log mode to
 	// whatever log mode has been set for logrus
@@ -128,6 +128,14 @@ func New(cfg *config.Config, db db.DB, logger *logrus.Logger) (Router, error) {
 	engine := gin.Default()
 	engine.MaxMultipartMemory = 8 << 20 // 8 MiB
 
+	// trace requests, if enabled
+	useTracing(cfg, engine)
+
+	// record request metrics and serve them, if enabled
+	if err := useMetrics(cfg, engine); err != nil {
+		return nil, err
+	}
+
 	// enable cors on the engine
 	if err := useCors(cfg, engine); err != nil {
 		return nil, err
@@ -142,7 +150,7 @@ func New(cfg *config.Config, db db.DB, logger *logrus.Logger) (Router, error) {
 	}
 
 	// enable session store middleware on the engine
-	if err := useSession(cfg, db, engine); err != nil {
+	if err := useSession(ctx, cfg, db, engine); err != nil {
 		return nil, err
 	}
 
@@ -20,6 +20,7 @@ package timeline
 
 import (
 	"container/list"
+	"context"
 	"errors"
 	"fmt"
 
@@ -27,7 +28,7 @@ import (
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 )
 
-func (t *timeline) Get(amount int, maxID string, sinceID string, minID string) ([]*apimodel.Status, error) {
+func (t *timeline) Get(ctx context.Context, amount int, maxID string, sinceID string, minID string) ([]*apimodel.Status, error) {
 	l := t.log.WithFields(logrus.Fields{
 		"func":      "Get",
 		"accountID": t.accountID,
@@ -43,12 +44,12 @@ func (t *timeline) Get(amount int, maxID string, sinceID string, minID string) (
 
 	// no params are defined to just fetch from the top
 	if maxID == "" && sinceID == "" && minID == "" {
-		statuses, err = t.GetXFromTop(amount)
+		statuses, err = t.GetXFromTop(ctx, amount)
 		// aysnchronously prepare the next predicted query so it's ready when the user asks for it
 		if len(statuses) != 0 {
 			nextMaxID := statuses[len(statuses)-1].ID
 			go func() {
-				if err := t.prepareNextQuery(amount, nextMaxID, "", ""); err != nil {
+				if err := t.prepareNextQuery(context.Background(), amount, nextMaxID, "", ""); err != nil {
 					l.Errorf("error preparing next query: %s", err)
 				}
 			}()
@@ -57,12 +58,12 @@ func (t *timeline) Get(amount int, maxID string, sinceID string, minID string) (
 
 	// maxID is defined but sinceID isn't so take from behind
 	if maxID != "" && sinceID == "" {
-		statuses, err = t.GetXBehindID(amount, maxID)
+		statuses, err = t.GetXBehindID(ctx, amount, maxID)
 		// aysnchronously prepare the next predicted query so it's ready when the user asks for it
 		if len(statuses) != 0 {
 			nextMaxID := statuses[len(statuses)-1].ID
 			go func() {
-				if err := t.prepareNextQuery(amount, nextMaxID, "", ""); err != nil {
+				if err := t.prepareNextQuery(context.Background(), amount, nextMaxID, "", ""); err != nil {
 					l.Errorf("error preparing next query: %s", err)
 				}
 			}()
@@ -71,24 +72,24 @@ func (t *timeline) Get(amount int, maxID string, sinceID string, minID string) (
 
 	// maxID is defined and sinceID || minID are as well, so take a slice between them
 	if maxID != "" && sinceID != "" {
-		statuses, err = t.GetXBetweenID(amount, maxID, minID)
+		statuses, err = t.GetXBetweenID(ctx, amount, maxID, minID)
 	}
 	if maxID != "" && minID != "" {
-		statuses, err = t.GetXBetweenID(amount, maxID, minID)
+		statuses, err = t.GetXBetweenID(ctx, amount, maxID, minID)
 	}
 
 	// maxID isn't defined, but sinceID || minID are, so take x before
 	if maxID == "" && sinceID != "" {
-		statuses, err = t.GetXBeforeID(amount, sinceID, true)
+		statuses, err = t.GetXBeforeID(ctx, amount, sinceID, true)
 	}
 	if maxID == "" && minID != "" {
-		statuses, err = t.GetXBeforeID(amount, minID, true)
+		statuses, err = t.GetXBeforeID(ctx, amount, minID, true)
 	}
 
 	return statuses, err
 }
 
-func (t *timeline) GetXFromTop(amount int) ([]*apimodel.Status, error) {
+func (t *timeline) GetXFromTop(ctx context.Context, amount int) ([]*apimodel.Status, error) {
 	// make a slice of statuses with the length we need to return
 	statuses := make([]*apimodel.Status, 0, amount)
 
@@ -98,7 +99,7 @@ func (t *timeline) GetXFromTop(amount int) ([]*apimodel.Status, error) {
 
 	// make sure we have enough posts prepared to return
 	if t.preparedPosts.data.Len() < amount {
-		if err := t.PrepareFromTop(amount); err != nil {
+		if err := t.PrepareFromTop(ctx, amount); err != nil {
 			return nil, err
 		}
 	}
@@ -120,7 +121,7 @@ func (t *timeline) GetXFromTop(amount int) ([]*apimodel.Status, error) {
 	return statuses, nil
 }
 
-func (t *timeline) GetXBehindID(amount int, behindID string) ([]*apimodel.Status, error) {
+func (t *timeline) GetXBehindID(ctx context.Context, amount int, behindID string) ([]*apimodel.Status, error) {
 	// make a slice of statuses with the length we need to return
 	statuses := make([]*apimodel.Status, 0, amount)
 
@@ -148,10 +149,10 @@ findMarkLoop:
 
 	// we didn't find it, so we need to make sure it's indexed and prepared and then try again
 	if behindIDMark == nil {
-		if err := t.IndexBehind(behindID, amount); err != nil {
+		if err := t.IndexBehind(ctx, behindID, amount); err != nil {
 			return nil, fmt.Errorf("GetXBehindID: error indexing behind and including ID %s", behindID)
 		}
-		if err := t.PrepareBehind(behindID, amount); err != nil {
+		if err := t.PrepareBehind(ctx, behindID, amount); err != nil {
 			return nil, fmt.Errorf("GetXBehindID: error preparing behind and including ID %s", behindID)
 		}
 		oldestID, err := t.OldestPreparedPostID()
@@ -163,12 +164,12 @@ findMarkLoop:
 			// this means we should just return the empty statuses slice since we don't have any more posts to offer
 			return statuses, nil
 		}
-		return t.GetXBehindID(amount, behindID)
+		return t.GetXBehindID(ctx, amount, behindID)
 	}
 
 	// make sure we have enough posts prepared behind it to return what we're being asked for
 	if t.preparedPosts.data.Len() < amount+position {
-		if err := t.PrepareBehind(behindID, amount); err != nil {
+		if err := t.PrepareBehind(ctx, behindID, amount); err != nil {
 			return nil, err
 		}
 	}
@@ -193,7 +194,7 @@ serveloop:
 	return statuses, nil
 }
 
-func (t *timeline) GetXBeforeID(amount int, beforeID string, startFromTop bool) ([]*apimodel.Status, error) {
+func (t *timeline) GetXBeforeID(ctx context.Context, amount int, beforeID string, startFromTop bool) ([]*apimodel.Status, error) {
 	// make a slice of statuses with the length we need to return
 	statuses := make([]*apimodel.Status, 0, amount)
 
@@ -218,13 +219,13 @@ findMarkLoop:
 
 	// we didn't find it, so we need to make sure it's indexed and prepared and then try again
 	if beforeIDMark == nil {
-		if err := t.IndexBefore(beforeID, true, amount); err != nil {
+		if err := t.IndexBefore(ctx, beforeID, true, amount); err != nil {
 			return nil, fmt.Errorf("GetXBeforeID: error indexing before and including ID %s", beforeID)
 		}
-		if err := t.PrepareBefore(beforeID, true, amount); err != nil {
+		if err := t.PrepareBefore(ctx, beforeID, true, amount); err != nil {
 			return nil, fmt.Errorf("GetXBeforeID: error preparing before and including ID %s", beforeID)
 		}
-		return t.GetXBeforeID(amount, beforeID, startFromTop)
+		return t.GetXBeforeID(ctx, amount, beforeID, startFromTop)
 	}
 
 	var served int
@@ -270,7 +271,7 @@ findMarkLoop:
 	return statuses, nil
 }
 
-func (t *timeline) GetXBetweenID(amount int, behindID string, beforeID string) ([]*apimodel.Status, error) {
+func (t *timeline) GetXBetweenID(ctx context.Context, amount int, behindID string, beforeID string) ([]*apimodel.Status, error) {
 	// make a slice of statuses with the length we need to return
 	statuses := make([]*apimodel.Status, 0, amount)
 
@@ -302,7 +303,7 @@ findMarkLoop:
 
 	// make sure we have enough posts prepared behind it to return what we're being asked for
 	if t.preparedPosts.data.Len() < amount+position {
-		if err := t.PrepareBehind(behindID, amount); err != nil {
+		if err := t.PrepareBehind(ctx, behindID, amount); err != nil {
 			return nil, err
 		}
 	}
@@ -19,6 +19,7 @@
 package timeline
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -27,13 +28,13 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (t *timeline) IndexBefore(statusID string, include bool, amount int) error {
+func (t *timeline) IndexBefore(ctx context.Context, statusID string, include bool, amount int) error {
 	filtered := []*gtsmodel.Status{}
 	offsetStatus := statusID
 
 	if include {
 		s := &gtsmodel.Status{}
-		if err := t.db.GetByID(statusID, s); err != nil {
+		if err := t.db.GetByID(ctx, statusID, s); err != nil {
 			return fmt.Errorf("IndexBefore: error getting initial status with id %s: %s", statusID, err)
 		}
 		filtered = append(filtered, s)
@@ -41,7 +42,7 @@ func (t *timeline) IndexBefore(statusID string, include bool, amount int) error
 
 grabloop:
 	for len(filtered) < amount {
-		statuses, err := t.db.GetHomeTimelineForAccount(t.accountID, "", offsetStatus, "", amount, false)
+		statuses, err := t.grab(ctx, "", offsetStatus, amount)
 		if err != nil {
 			if _, ok := err.(db.ErrNoEntries); ok {
 				break grabloop // we just don't have enough statuses left in the db so index what we've got and then bail
@@ -50,7 +51,7 @@ grabloop:
 		}
 
 		for _, s := range statuses {
-			timelineable, err := t.filter.StatusHometimelineable(s, t.account)
+			timelineable, err := t.timelineable(ctx, s)
 			if err != nil {
 				continue
 			}
@@ -70,13 +71,13 @@ grabloop:
 	return nil
 }
 
-func (t *timeline) IndexBehind(statusID string, amount int) error {
+func (t *timeline) IndexBehind(ctx context.Context, statusID string, amount int) error {
 	filtered := []*gtsmodel.Status{}
 	offsetStatus := statusID
 
 grabloop:
 	for len(filtered) < amount {
-		statuses, err := t.db.GetHomeTimelineForAccount(t.accountID, offsetStatus, "", "", amount, false)
+		statuses, err := t.grab(ctx, offsetStatus, "", amount)
 		if err != nil {
 			if _, ok := err.(db.ErrNoEntries); ok {
 				break grabloop // we just don't have enough statuses left in the db so index what we've got and then bail
@@ -85,7 +86,7 @@ grabloop:
 		}
 
 		for _, s := range statuses {
-			timelineable, err := t.filter.StatusHometimelineable(s, t.account)
+			timelineable, err := t.timelineable(ctx, s)
 			if err != nil {
 				continue
 			}
@@ -105,6 +106,22 @@ grabloop:
 	return nil
 }
 
+// grab gets statuses that might belong in this timeline from the database, newest first.
+func (t *timeline) grab(ctx context.Context, maxID string, sinceID string, amount int) ([]*gtsmodel.Status, error) {
+	if t.list != nil {
+		return t.db.GetListTimeline(ctx, t.list.ID, maxID, sinceID, "", amount)
+	}
+	return t.db.GetHomeTimelineForAccount(ctx, t.accountID, maxID, sinceID, "", amount, false)
+}
+
+// timelineable checks whether the given status, grabbed from the database, should actually be shown in this timeline.
+func (t *timeline) timelineable(ctx context.Context, s *gtsmodel.Status) (bool, error) {
+	if t.list != nil {
+		return t.filter.StatusListTimelineable(ctx, s, t.list, t.account)
+	}
+	return t.filter.StatusHometimelineable(ctx, s, t.account)
+}
+
 func (t *timeline) IndexOneByID(statusID string) error {
 	return nil
 }
@@ -123,7 +140,7 @@ func (t *timeline) IndexOne(statusCreatedAt time.Time, statusID string, boostOfI
 	return t.postIndex.insertIndexed(postIndexEntry)
 }
 
-func (t *timeline) IndexAndPrepareOne(statusCreatedAt time.Time, statusID string, boostOfID string, accountID string, boostOfAccountID string) (bool, error) {
+func (t *timeline) IndexAndPrepareOne(ctx context.Context, statusCreatedAt time.Time, statusID string, boostOfID string, accountID string, boostOfAccountID string) (bool, error) {
 	t.Lock()
 	defer t.Unlock()
 
@@ -140,9 +157,16 @@ func (t *timeline) IndexAndPrepareOne(statusCreatedAt time.Time, statusID string
 	}
 
 	if inserted {
-		if err := t.prepare(statusID); err != nil {
+		filters, err := t.keywordFilters(ctx)
+		if err != nil {
+			return inserted, fmt.Errorf("IndexAndPrepareOne: error getting keyword filters: %s", err)
+		}
+		prepared, err := t.prepare(ctx, statusID, filters)
+		if err != nil {
 			return inserted, fmt.Errorf("IndexAndPrepareOne: error preparing: %s", err)
 		}
+		// a filtered status stays indexed, but it shouldn't be shown to the timeline owner right now
+		inserted = prepared
 	}
 
 	return inserted, nil
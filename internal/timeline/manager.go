@@ -19,6 +19,7 @@
 package timeline
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -54,7 +55,7 @@ type Manager interface {
 	//
 	// The returned bool indicates whether the status was actually put in the timeline. This could be false in cases where
 	// the status is a boost, but a boost of the original post or the post itself already exists recently in the timeline.
-	Ingest(status *gtsmodel.Status, timelineAccountID string) (bool, error)
+	Ingest(ctx context.Context, status *gtsmodel.Status, timelineAccountID string) (bool, error)
 	// IngestAndPrepare takes one status and indexes it into the timeline for the given account ID, and then immediately prepares it for serving.
 	// This is useful in cases where we know the status will need to be shown at the top of a user's timeline immediately (eg., a new status is created).
 	//
@@ -62,30 +63,54 @@ type Manager interface {
 	//
 	// The returned bool indicates whether the status was actually put in the timeline. This could be false in cases where
 	// the status is a boost, but a boost of the original post or the post itself already exists recently in the timeline.
-	IngestAndPrepare(status *gtsmodel.Status, timelineAccountID string) (bool, error)
+	IngestAndPrepare(ctx context.Context, status *gtsmodel.Status, timelineAccountID string) (bool, error)
 	// HomeTimeline returns limit n amount of entries from the home timeline of the given account ID, in descending chronological order.
 	// If maxID is provided, it will return entries from that maxID onwards, inclusive.
-	HomeTimeline(accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*apimodel.Status, error)
+	HomeTimeline(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*apimodel.Status, error)
 	// GetIndexedLength returns the amount of posts/statuses that have been *indexed* for the given account ID.
-	GetIndexedLength(timelineAccountID string) int
+	GetIndexedLength(ctx context.Context, timelineAccountID string) int
+	// GetTotalIndexedLength returns the amount of posts/statuses that have been *indexed* across all timelines.
+	GetTotalIndexedLength() int
+	// GetTotalPreparedLength returns the amount of posts/statuses that have been *prepared* across all timelines.
+	GetTotalPreparedLength() int
 	// GetDesiredIndexLength returns the amount of posts that we, ideally, index for each user.
 	GetDesiredIndexLength() int
 	// GetOldestIndexedID returns the status ID for the oldest post that we have indexed for the given account.
-	GetOldestIndexedID(timelineAccountID string) (string, error)
+	GetOldestIndexedID(ctx context.Context, timelineAccountID string) (string, error)
 	// PrepareXFromTop prepares limit n amount of posts, based on their indexed representations, from the top of the index.
-	PrepareXFromTop(timelineAccountID string, limit int) error
+	PrepareXFromTop(ctx context.Context, timelineAccountID string, limit int) error
 	// Remove removes one status from the timeline of the given timelineAccountID
-	Remove(statusID string, timelineAccountID string) (int, error)
+	Remove(ctx context.Context, statusID string, timelineAccountID string) (int, error)
 	// WipeStatusFromAllTimelines removes one status from the index and prepared posts of all timelines
 	WipeStatusFromAllTimelines(statusID string) error
 	// WipeStatusesFromAccountID removes all statuses by the given accountID from the timelineAccountID's timelines.
-	WipeStatusesFromAccountID(accountID string, timelineAccountID string) error
+	WipeStatusesFromAccountID(ctx context.Context, accountID string, timelineAccountID string) error
+	// ResetTimelines drops the prepared posts of the home timeline and list timelines of the given account ID, so that they'll
+	// be prepared again the next time they're served. This should be called when the account's keyword filters change.
+	ResetTimelines(timelineAccountID string) error
+
+	// IngestAndPrepareList takes one status and indexes it into the timeline for the given list ID, and then immediately prepares it for serving.
+	//
+	// It should already be established before calling this function that the status/post actually belongs in the list timeline!
+	//
+	// The returned bool indicates whether the status was actually put in the timeline.
+	IngestAndPrepareList(ctx context.Context, status *gtsmodel.Status, listID string) (bool, error)
+	// ListTimeline returns limit n amount of entries from the timeline of the given list ID, in descending chronological order.
+	//
+	// If the list timeline hasn't been used yet, it will be indexed from the database first.
+	ListTimeline(ctx context.Context, listID string, maxID string, sinceID string, minID string, limit int) ([]*apimodel.Status, error)
+	// RemoveListTimeline drops the timeline of the given list ID, if there is one.
+	//
+	// This should be called when the list is deleted, or its members change, in which case the timeline will be indexed
+	// afresh from the database the next time it's used.
+	RemoveListTimeline(listID string)
 }
 
 // NewManager returns a new timeline manager with the given database, typeconverter, config, and log.
 func NewManager(db db.DB, tc typeutils.TypeConverter, config *config.Config, log *logrus.Logger) Manager {
 	return &manager{
 		accountTimelines: sync.Map{},
+		listTimelines:    sync.Map{},
 		db:               db,
 		tc:               tc,
 		config:           config,
@@ -95,20 +120,21 @@ func NewManager(db db.DB, tc typeutils.TypeConverter, config *config.Config, log
 
 type manager struct {
 	accountTimelines sync.Map
+	listTimelines    sync.Map
 	db               db.DB
 	tc               typeutils.TypeConverter
 	config           *config.Config
 	log              *logrus.Logger
 }
 
-func (m *manager) Ingest(status *gtsmodel.Status, timelineAccountID string) (bool, error) {
+func (m *manager) Ingest(ctx context.Context, status *gtsmodel.Status, timelineAccountID string) (bool, error) {
 	l := m.log.WithFields(logrus.Fields{
 		"func":              "Ingest",
 		"timelineAccountID": timelineAccountID,
 		"statusID":          status.ID,
 	})
 
-	t, err := m.getOrCreateTimeline(timelineAccountID)
+	t, err := m.getOrCreateTimeline(ctx, timelineAccountID)
 	if err != nil {
 		return false, err
 	}
@@ -117,30 +143,30 @@ func (m *manager) Ingest(status *gtsmodel.Status, timelineAccountID string) (boo
 	return t.IndexOne(status.CreatedAt, status.ID, status.BoostOfID, status.AccountID, status.BoostOfAccountID)
 }
 
-func (m *manager) IngestAndPrepare(status *gtsmodel.Status, timelineAccountID string) (bool, error) {
+func (m *manager) IngestAndPrepare(ctx context.Context, status *gtsmodel.Status, timelineAccountID string) (bool, error) {
 	l := m.log.WithFields(logrus.Fields{
 		"func":              "IngestAndPrepare",
 		"timelineAccountID": timelineAccountID,
 		"statusID":          status.ID,
 	})
 
-	t, err := m.getOrCreateTimeline(timelineAccountID)
+	t, err := m.getOrCreateTimeline(ctx, timelineAccountID)
 	if err != nil {
 		return false, err
 	}
 
 	l.Trace("ingesting status")
-	return t.IndexAndPrepareOne(status.CreatedAt, status.ID, status.BoostOfID, status.AccountID, status.BoostOfAccountID)
+	return t.IndexAndPrepareOne(ctx, status.CreatedAt, status.ID, status.BoostOfID, status.AccountID, status.BoostOfAccountID)
 }
 
-func (m *manager) Remove(statusID string, timelineAccountID string) (int, error) {
+func (m *manager) Remove(ctx context.Context, statusID string, timelineAccountID string) (int, error) {
 	l := m.log.WithFields(logrus.Fields{
 		"func":              "Remove",
 		"timelineAccountID": timelineAccountID,
 		"statusID":          statusID,
 	})
 
-	t, err := m.getOrCreateTimeline(timelineAccountID)
+	t, err := m.getOrCreateTimeline(ctx, timelineAccountID)
 	if err != nil {
 		return 0, err
 	}
@@ -149,26 +175,26 @@ func (m *manager) Remove(statusID string, timelineAccountID string) (int, error)
 	return t.Remove(statusID)
 }
 
-func (m *manager) HomeTimeline(timelineAccountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*apimodel.Status, error) {
+func (m *manager) HomeTimeline(ctx context.Context, timelineAccountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*apimodel.Status, error) {
 	l := m.log.WithFields(logrus.Fields{
 		"func":              "HomeTimelineGet",
 		"timelineAccountID": timelineAccountID,
 	})
 
-	t, err := m.getOrCreateTimeline(timelineAccountID)
+	t, err := m.getOrCreateTimeline(ctx, timelineAccountID)
 	if err != nil {
 		return nil, err
 	}
 
-	statuses, err := t.Get(limit, maxID, sinceID, minID)
+	statuses, err := t.Get(ctx, limit, maxID, sinceID, minID)
 	if err != nil {
 		l.Errorf("error getting statuses: %s", err)
 	}
 	return statuses, nil
 }
 
-func (m *manager) GetIndexedLength(timelineAccountID string) int {
-	t, err := m.getOrCreateTimeline(timelineAccountID)
+func (m *manager) GetIndexedLength(ctx context.Context, timelineAccountID string) int {
+	t, err := m.getOrCreateTimeline(ctx, timelineAccountID)
 	if err != nil {
 		return 0
 	}
@@ -176,12 +202,28 @@ func (m *manager) GetIndexedLength(timelineAccountID string) int {
 	return t.PostIndexLength()
 }
 
+func (m *manager) GetTotalIndexedLength() int {
+	var total int
+	m.rangeTimelines(func(t Timeline) {
+		total = total + t.PostIndexLength()
+	})
+	return total
+}
+
+func (m *manager) GetTotalPreparedLength() int {
+	var total int
+	m.rangeTimelines(func(t Timeline) {
+		total = total + t.PreparedPostsLength()
+	})
+	return total
+}
+
 func (m *manager) GetDesiredIndexLength() int {
 	return desiredPostIndexLength
 }
 
-func (m *manager) GetOldestIndexedID(timelineAccountID string) (string, error) {
-	t, err := m.getOrCreateTimeline(timelineAccountID)
+func (m *manager) GetOldestIndexedID(ctx context.Context, timelineAccountID string) (string, error) {
+	t, err := m.getOrCreateTimeline(ctx, timelineAccountID)
 	if err != nil {
 		return "", err
 	}
@@ -189,28 +231,21 @@ func (m *manager) GetOldestIndexedID(timelineAccountID string) (string, error) {
 	return t.OldestIndexedPostID()
 }
 
-func (m *manager) PrepareXFromTop(timelineAccountID string, limit int) error {
-	t, err := m.getOrCreateTimeline(timelineAccountID)
+func (m *manager) PrepareXFromTop(ctx context.Context, timelineAccountID string, limit int) error {
+	t, err := m.getOrCreateTimeline(ctx, timelineAccountID)
 	if err != nil {
 		return err
 	}
 
-	return t.PrepareFromTop(limit)
+	return t.PrepareFromTop(ctx, limit)
 }
 
 func (m *manager) WipeStatusFromAllTimelines(statusID string) error {
 	errors := []string{}
-	m.accountTimelines.Range(func(k interface{}, i interface{}) bool {
-		t, ok := i.(Timeline)
-		if !ok {
-			panic("couldn't parse entry as Timeline, this should never happen so panic")
-		}
-
+	m.rangeTimelines(func(t Timeline) {
 		if _, err := t.Remove(statusID); err != nil {
 			errors = append(errors, err.Error())
 		}
-
-		return true
 	})
 
 	var err error
@@ -221,22 +256,120 @@ func (m *manager) WipeStatusFromAllTimelines(statusID string) error {
 	return err
 }
 
-func (m *manager) WipeStatusesFromAccountID(accountID string, timelineAccountID string) error {
-	t, err := m.getOrCreateTimeline(timelineAccountID)
+func (m *manager) WipeStatusesFromAccountID(ctx context.Context, accountID string, timelineAccountID string) error {
+	t, err := m.getOrCreateTimeline(ctx, timelineAccountID)
 	if err != nil {
 		return err
 	}
 
-	_, err = t.RemoveAllBy(accountID)
-	return err
+	if _, err := t.RemoveAllBy(accountID); err != nil {
+		return err
+	}
+
+	// the statuses shouldn't show up in any of the lists of the timeline account either
+	errors := []string{}
+	m.listTimelines.Range(func(k interface{}, i interface{}) bool {
+		lt, ok := i.(*timeline)
+		if !ok {
+			panic("couldn't parse entry as timeline, this should never happen so panic")
+		}
+
+		if lt.accountID == timelineAccountID {
+			if _, err := lt.RemoveAllBy(accountID); err != nil {
+				errors = append(errors, err.Error())
+			}
+		}
+
+		return true
+	})
+
+	if len(errors) > 0 {
+		return fmt.Errorf("one or more errors removing statuses by %s from list timelines: %s", accountID, strings.Join(errors, ";"))
+	}
+
+	return nil
+}
+
+func (m *manager) ResetTimelines(timelineAccountID string) error {
+	errors := []string{}
+	m.rangeTimelines(func(t Timeline) {
+		tl, ok := t.(*timeline)
+		if !ok {
+			panic("couldn't parse entry as timeline, this should never happen so panic")
+		}
+
+		if tl.accountID == timelineAccountID {
+			if err := tl.Reset(); err != nil {
+				errors = append(errors, err.Error())
+			}
+		}
+	})
+
+	if len(errors) > 0 {
+		return fmt.Errorf("one or more errors resetting timelines of %s: %s", timelineAccountID, strings.Join(errors, ";"))
+	}
+
+	return nil
+}
+
+func (m *manager) IngestAndPrepareList(ctx context.Context, status *gtsmodel.Status, listID string) (bool, error) {
+	l := m.log.WithFields(logrus.Fields{
+		"func":     "IngestAndPrepareList",
+		"listID":   listID,
+		"statusID": status.ID,
+	})
+
+	t, err := m.getOrCreateListTimeline(ctx, listID)
+	if err != nil {
+		return false, err
+	}
+
+	l.Trace("ingesting status")
+	return t.IndexAndPrepareOne(ctx, status.CreatedAt, status.ID, status.BoostOfID, status.AccountID, status.BoostOfAccountID)
+}
+
+func (m *manager) ListTimeline(ctx context.Context, listID string, maxID string, sinceID string, minID string, limit int) ([]*apimodel.Status, error) {
+	l := m.log.WithFields(logrus.Fields{
+		"func":   "ListTimeline",
+		"listID": listID,
+	})
+
+	t, err := m.getOrCreateListTimeline(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := t.Get(ctx, limit, maxID, sinceID, minID)
+	if err != nil {
+		l.Errorf("error getting statuses: %s", err)
+	}
+	return statuses, nil
+}
+
+func (m *manager) RemoveListTimeline(listID string) {
+	m.listTimelines.Delete(listID)
+}
+
+// rangeTimelines calls f for every home and list timeline held by the manager.
+func (m *manager) rangeTimelines(f func(t Timeline)) {
+	each := func(k interface{}, i interface{}) bool {
+		t, ok := i.(Timeline)
+		if !ok {
+			panic("couldn't parse entry as Timeline, this should never happen so panic")
+		}
+		f(t)
+		return true
+	}
+	m.accountTimelines.Range(each)
+	m.listTimelines.Range(each)
 }
 
-func (m *manager) getOrCreateTimeline(timelineAccountID string) (Timeline, error) {
+func (m *manager) getOrCreateTimeline(ctx context.Context, timelineAccountID string) (Timeline, error) {
 	var t Timeline
 	i, ok := m.accountTimelines.Load(timelineAccountID)
 	if !ok {
 		var err error
-		t, err = NewTimeline(timelineAccountID, m.db, m.tc, m.log)
+		t, err = NewTimeline(ctx, timelineAccountID, m.db, m.tc, m.log)
 		if err != nil {
 			return nil, err
 		}
@@ -250,3 +383,31 @@ func (m *manager) getOrCreateTimeline(timelineAccountID string) (Timeline, error
 
 	return t, nil
 }
+
+func (m *manager) getOrCreateListTimeline(ctx context.Context, listID string) (Timeline, error) {
+	if i, ok := m.listTimelines.Load(listID); ok {
+		t, ok := i.(Timeline)
+		if !ok {
+			panic("couldn't parse entry as Timeline, this should never happen so panic")
+		}
+		return t, nil
+	}
+
+	t, err := NewListTimeline(ctx, listID, m.db, m.tc, m.log)
+	if err != nil {
+		return nil, err
+	}
+
+	i, loaded := m.listTimelines.LoadOrStore(listID, t)
+	if loaded {
+		// someone else got here first, so use their timeline instead
+		return i.(Timeline), nil
+	}
+
+	// list timelines aren't populated on startup like home timelines are, so do it now
+	if err := t.IndexBehind(ctx, "", desiredPostIndexLength); err != nil {
+		m.log.WithField("listID", listID).Errorf("getOrCreateListTimeline: error indexing list timeline: %s", err)
+	}
+
+	return t, nil
+}
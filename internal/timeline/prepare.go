@@ -20,33 +20,39 @@ package timeline
 
 import (
 	"container/list"
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func (t *timeline) prepareNextQuery(amount int, maxID string, sinceID string, minID string) error {
+func (t *timeline) prepareNextQuery(ctx context.Context, amount int, maxID string, sinceID string, minID string) error {
 	var err error
 
 	// maxID is defined but sinceID isn't so take from behind
 	if maxID != "" && sinceID == "" {
-		err = t.PrepareBehind(maxID, amount)
+		err = t.PrepareBehind(ctx, maxID, amount)
 	}
 
 	// maxID isn't defined, but sinceID || minID are, so take x before
 	if maxID == "" && sinceID != "" {
-		err = t.PrepareBefore(sinceID, false, amount)
+		err = t.PrepareBefore(ctx, sinceID, false, amount)
 	}
 	if maxID == "" && minID != "" {
-		err = t.PrepareBefore(minID, false, amount)
+		err = t.PrepareBefore(ctx, minID, false, amount)
 	}
 
 	return err
 }
 
-func (t *timeline) PrepareBehind(statusID string, amount int) error {
+func (t *timeline) PrepareBehind(ctx context.Context, statusID string, amount int) error {
+	ctx, span := tracing.StartSpan(ctx, "timeline.PrepareBehind", attribute.String("account_id", t.accountID), attribute.Int("amount", amount))
+	defer span.End()
+
 	t.Lock()
 	defer t.Unlock()
 
@@ -61,6 +67,11 @@ func (t *timeline) PrepareBehind(statusID string, amount int) error {
 		return nil
 	}
 
+	filters, err := t.keywordFilters(ctx)
+	if err != nil {
+		return fmt.Errorf("PrepareBehind: error getting keyword filters: %s", err)
+	}
+
 	var prepared int
 	var preparing bool
 prepareloop:
@@ -78,7 +89,8 @@ prepareloop:
 		}
 
 		if preparing {
-			if err := t.prepare(entry.statusID); err != nil {
+			ok, err := t.prepare(ctx, entry.statusID, filters)
+			if err != nil {
 				// there's been an error
 				if _, ok := err.(db.ErrNoEntries); !ok {
 					// it's a real error
@@ -87,6 +99,10 @@ prepareloop:
 				// the status just doesn't exist (anymore) so continue to the next one
 				continue
 			}
+			if !ok {
+				// the status was filtered out so it doesn't count towards the amount
+				continue
+			}
 			if prepared == amount {
 				// we're done
 				break prepareloop
@@ -98,7 +114,10 @@ prepareloop:
 	return nil
 }
 
-func (t *timeline) PrepareBefore(statusID string, include bool, amount int) error {
+func (t *timeline) PrepareBefore(ctx context.Context, statusID string, include bool, amount int) error {
+	ctx, span := tracing.StartSpan(ctx, "timeline.PrepareBefore", attribute.String("account_id", t.accountID), attribute.Int("amount", amount))
+	defer span.End()
+
 	t.Lock()
 	defer t.Unlock()
 
@@ -113,6 +132,11 @@ func (t *timeline) PrepareBefore(statusID string, include bool, amount int) erro
 		return nil
 	}
 
+	filters, err := t.keywordFilters(ctx)
+	if err != nil {
+		return fmt.Errorf("PrepareBefore: error getting keyword filters: %s", err)
+	}
+
 	var prepared int
 	var preparing bool
 prepareloop:
@@ -133,7 +157,8 @@ prepareloop:
 		}
 
 		if preparing {
-			if err := t.prepare(entry.statusID); err != nil {
+			ok, err := t.prepare(ctx, entry.statusID, filters)
+			if err != nil {
 				// there's been an error
 				if _, ok := err.(db.ErrNoEntries); !ok {
 					// it's a real error
@@ -142,6 +167,10 @@ prepareloop:
 				// the status just doesn't exist (anymore) so continue to the next one
 				continue
 			}
+			if !ok {
+				// the status was filtered out so it doesn't count towards the amount
+				continue
+			}
 			if prepared == amount {
 				// we're done
 				break prepareloop
@@ -153,7 +182,10 @@ prepareloop:
 	return nil
 }
 
-func (t *timeline) PrepareFromTop(amount int) error {
+func (t *timeline) PrepareFromTop(ctx context.Context, amount int) error {
+	ctx, span := tracing.StartSpan(ctx, "timeline.PrepareFromTop", attribute.String("account_id", t.accountID), attribute.Int("amount", amount))
+	defer span.End()
+
 	t.Lock()
 	defer t.Unlock()
 
@@ -168,6 +200,11 @@ func (t *timeline) PrepareFromTop(amount int) error {
 		return nil
 	}
 
+	filters, err := t.keywordFilters(ctx)
+	if err != nil {
+		return fmt.Errorf("PrepareFromTop: error getting keyword filters: %s", err)
+	}
+
 	var prepared int
 prepareloop:
 	for e := t.postIndex.data.Front(); e != nil; e = e.Next() {
@@ -180,7 +217,8 @@ prepareloop:
 			return errors.New("PrepareFromTop: could not parse e as a postIndexEntry")
 		}
 
-		if err := t.prepare(entry.statusID); err != nil {
+		ok, err := t.prepare(ctx, entry.statusID, filters)
+		if err != nil {
 			// there's been an error
 			if _, ok := err.(db.ErrNoEntries); !ok {
 				// it's a real error
@@ -189,6 +227,10 @@ prepareloop:
 			// the status just doesn't exist (anymore) so continue to the next one
 			continue
 		}
+		if !ok {
+			// the status was filtered out so it doesn't count towards the amount
+			continue
+		}
 
 		prepared = prepared + 1
 		if prepared == amount {
@@ -200,27 +242,30 @@ prepareloop:
 	return nil
 }
 
-func (t *timeline) prepare(statusID string) error {
+// prepare converts the status with the given ID into its api representation, and puts it in the prepared posts.
+//
+// The returned bool will be false if the status wasn't prepared because it matches one of the given keyword filters of the timeline owner.
+func (t *timeline) prepare(ctx context.Context, statusID string, filters []*gtsmodel.Filter) (bool, error) {
 
 	// start by getting the status out of the database according to its indexed ID
 	gtsStatus := &gtsmodel.Status{}
-	if err := t.db.GetByID(statusID, gtsStatus); err != nil {
-		return err
+	if err := t.db.GetByID(ctx, statusID, gtsStatus); err != nil {
+		return false, err
 	}
 
-	// if the account pointer hasn't been set on this timeline already, set it lazily here
-	if t.account == nil {
-		timelineOwnerAccount := &gtsmodel.Account{}
-		if err := t.db.GetByID(t.accountID, timelineOwnerAccount); err != nil {
-			return err
-		}
-		t.account = timelineOwnerAccount
+	// make sure the timeline owner hasn't filtered this status out
+	filtered, err := t.filter.StatusKeywordFiltered(ctx, gtsStatus, filters)
+	if err != nil {
+		return false, err
+	}
+	if filtered {
+		return false, nil
 	}
 
 	// serialize the status (or, at least, convert it to a form that's ready to be serialized)
-	apiModelStatus, err := t.tc.StatusToMasto(gtsStatus, t.account)
+	apiModelStatus, err := t.tc.StatusToMasto(ctx, gtsStatus, t.account)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// shove it in prepared posts as a prepared posts entry
@@ -232,7 +277,22 @@ func (t *timeline) prepare(statusID string) error {
 		prepared:         apiModelStatus,
 	}
 
-	return t.preparedPosts.insertPrepared(preparedPostsEntry)
+	return true, t.preparedPosts.insertPrepared(preparedPostsEntry)
+}
+
+// keywordFilters returns the home timeline keyword filters of the timeline owner, so that they only have to be loaded once per batch of statuses to prepare.
+//
+// It also lazily sets the account pointer on this timeline, if that hasn't been done already.
+func (t *timeline) keywordFilters(ctx context.Context) ([]*gtsmodel.Filter, error) {
+	if t.account == nil {
+		timelineOwnerAccount := &gtsmodel.Account{}
+		if err := t.db.GetByID(ctx, t.accountID, timelineOwnerAccount); err != nil {
+			return nil, err
+		}
+		t.account = timelineOwnerAccount
+	}
+
+	return t.filter.KeywordFilters(ctx, t.account, gtsmodel.FilterContextHome)
 }
 
 func (t *timeline) OldestPreparedPostID() (string, error) {
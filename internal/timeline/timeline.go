@@ -19,6 +19,7 @@
 package timeline
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -38,24 +39,24 @@ type Timeline interface {
 		RETRIEVAL FUNCTIONS
 	*/
 
-	Get(amount int, maxID string, sinceID string, minID string) ([]*apimodel.Status, error)
+	Get(ctx context.Context, amount int, maxID string, sinceID string, minID string) ([]*apimodel.Status, error)
 	// GetXFromTop returns x amount of posts from the top of the timeline, from newest to oldest.
-	GetXFromTop(amount int) ([]*apimodel.Status, error)
+	GetXFromTop(ctx context.Context, amount int) ([]*apimodel.Status, error)
 	// GetXBehindID returns x amount of posts from the given id onwards, from newest to oldest.
 	// This will NOT include the status with the given ID.
 	//
 	// This corresponds to an api call to /timelines/home?max_id=WHATEVER
-	GetXBehindID(amount int, fromID string) ([]*apimodel.Status, error)
+	GetXBehindID(ctx context.Context, amount int, fromID string) ([]*apimodel.Status, error)
 	// GetXBeforeID returns x amount of posts up to the given id, from newest to oldest.
 	// This will NOT include the status with the given ID.
 	//
 	// This corresponds to an api call to /timelines/home?since_id=WHATEVER
-	GetXBeforeID(amount int, sinceID string, startFromTop bool) ([]*apimodel.Status, error)
+	GetXBeforeID(ctx context.Context, amount int, sinceID string, startFromTop bool) ([]*apimodel.Status, error)
 	// GetXBetweenID returns x amount of posts from the given maxID, up to the given id, from newest to oldest.
 	// This will NOT include the status with the given IDs.
 	//
 	// This corresponds to an api call to /timelines/home?since_id=WHATEVER&max_id=WHATEVER_ELSE
-	GetXBetweenID(amount int, maxID string, sinceID string) ([]*apimodel.Status, error)
+	GetXBetweenID(ctx context.Context, amount int, maxID string, sinceID string) ([]*apimodel.Status, error)
 
 	/*
 		INDEXING FUNCTIONS
@@ -66,6 +67,9 @@ type Timeline interface {
 	// The returned bool indicates whether or not the status was actually inserted into the timeline. This will be false
 	// if the status is a boost and the original post or another boost of it already exists < boostReinsertionDepth back in the timeline.
 	IndexOne(statusCreatedAt time.Time, statusID string, boostOfID string, accountID string, boostOfAccountID string) (bool, error)
+	// IndexBehind fetches amount posts that are older than the given status ID from the database, and indexes them.
+	// If statusID is empty, posts will be indexed from the newest one onwards.
+	IndexBehind(ctx context.Context, statusID string, amount int) error
 
 	// OldestIndexedPostID returns the id of the rearmost (ie., the oldest) indexed post, or an error if something goes wrong.
 	// If nothing goes wrong but there's no oldest post, an empty string will be returned so make sure to check for this.
@@ -76,16 +80,17 @@ type Timeline interface {
 	*/
 
 	// PrepareXFromTop instructs the timeline to prepare x amount of posts from the top of the timeline.
-	PrepareFromTop(amount int) error
+	PrepareFromTop(ctx context.Context, amount int) error
 	// PrepareBehind instructs the timeline to prepare the next amount of entries for serialization, from position onwards.
 	// If include is true, then the given status ID will also be prepared, otherwise only entries behind it will be prepared.
-	PrepareBehind(statusID string, amount int) error
+	PrepareBehind(ctx context.Context, statusID string, amount int) error
 	// IndexOne puts a status into the timeline at the appropriate place according to its 'createdAt' property,
 	// and then immediately prepares it.
 	//
 	// The returned bool indicates whether or not the status was actually inserted into the timeline. This will be false
-	// if the status is a boost and the original post or another boost of it already exists < boostReinsertionDepth back in the timeline.
-	IndexAndPrepareOne(statusCreatedAt time.Time, statusID string, boostOfID string, accountID string, boostOfAccountID string) (bool, error)
+	// if the status is a boost and the original post or another boost of it already exists < boostReinsertionDepth back in the timeline,
+	// or if the status matches one of the keyword filters of the timeline owner.
+	IndexAndPrepareOne(ctx context.Context, statusCreatedAt time.Time, statusID string, boostOfID string, accountID string, boostOfAccountID string) (bool, error)
 	// OldestPreparedPostID returns the id of the rearmost (ie., the oldest) prepared post, or an error if something goes wrong.
 	// If nothing goes wrong but there's no oldest post, an empty string will be returned so make sure to check for this.
 	OldestPreparedPostID() (string, error)
@@ -96,6 +101,8 @@ type Timeline interface {
 
 	// ActualPostIndexLength returns the actual length of the post index at this point in time.
 	PostIndexLength() int
+	// PreparedPostsLength returns the number of posts that are currently prepared for serving.
+	PreparedPostsLength() int
 
 	/*
 		UTILITY FUNCTIONS
@@ -121,6 +128,7 @@ type timeline struct {
 	preparedPosts *preparedPosts
 	accountID     string
 	account       *gtsmodel.Account
+	list          *gtsmodel.List // only set if this is the timeline of a list rather than a home timeline
 	db            db.DB
 	filter        visibility.Filter
 	tc            typeutils.TypeConverter
@@ -129,9 +137,9 @@ type timeline struct {
 }
 
 // NewTimeline returns a new Timeline for the given account ID
-func NewTimeline(accountID string, db db.DB, typeConverter typeutils.TypeConverter, log *logrus.Logger) (Timeline, error) {
+func NewTimeline(ctx context.Context, accountID string, db db.DB, typeConverter typeutils.TypeConverter, log *logrus.Logger) (Timeline, error) {
 	timelineOwnerAccount := &gtsmodel.Account{}
-	if err := db.GetByID(accountID, timelineOwnerAccount); err != nil {
+	if err := db.GetByID(ctx, accountID, timelineOwnerAccount); err != nil {
 		return nil, err
 	}
 
@@ -147,7 +155,37 @@ func NewTimeline(accountID string, db db.DB, typeConverter typeutils.TypeConvert
 	}, nil
 }
 
+// NewListTimeline returns a new Timeline for the list with the given ID, owned by the account that owns the list.
+func NewListTimeline(ctx context.Context, listID string, db db.DB, typeConverter typeutils.TypeConverter, log *logrus.Logger) (Timeline, error) {
+	list := &gtsmodel.List{}
+	if err := db.GetByID(ctx, listID, list); err != nil {
+		return nil, err
+	}
+
+	timelineOwnerAccount := &gtsmodel.Account{}
+	if err := db.GetByID(ctx, list.AccountID, timelineOwnerAccount); err != nil {
+		return nil, err
+	}
+
+	return &timeline{
+		postIndex:     &postIndex{},
+		preparedPosts: &preparedPosts{},
+		accountID:     list.AccountID,
+		account:       timelineOwnerAccount,
+		list:          list,
+		db:            db,
+		filter:        visibility.NewFilter(db, log),
+		tc:            typeConverter,
+		log:           log,
+	}, nil
+}
+
 func (t *timeline) Reset() error {
+	t.Lock()
+	defer t.Unlock()
+
+	// drop all prepared posts, they'll be prepared again from the index the next time they're needed
+	t.preparedPosts.data = nil
 	return nil
 }
 
@@ -158,3 +196,11 @@ func (t *timeline) PostIndexLength() int {
 
 	return t.postIndex.data.Len()
 }
+
+func (t *timeline) PreparedPostsLength() int {
+	if t.preparedPosts == nil || t.preparedPosts.data == nil {
+		return 0
+	}
+
+	return t.preparedPosts.data.Len()
+}
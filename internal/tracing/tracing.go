@@ -0,0 +1,140 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package tracing records OpenTelemetry traces of what gotosocial is doing, and exports them either
+// to an OTLP collector over http, or to a local file for testing.
+//
+// When tracing is not enabled, the global otel tracer provider is a no-op, so calls to StartSpan are cheap.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ExporterOTLP sends traces to an OpenTelemetry collector using OTLP over http.
+	ExporterOTLP = "otlp"
+	// ExporterFile writes traces as json to a local file.
+	ExporterFile = "file"
+
+	tracerName = "github.com/superseriousbusiness/gotosocial"
+)
+
+// Initialize sets up the global otel tracer provider according to the given config, and returns
+// a function that should be called on shutdown to flush any remaining spans.
+//
+// If tracing is not enabled, nothing is set up, and the returned function does nothing.
+func Initialize(ctx context.Context, c *config.Config) (func(context.Context) error, error) {
+	if !c.TracingConfig.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var file *os.File
+	switch c.TracingConfig.Exporter {
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(c.TracingConfig.Endpoint)}
+		if c.TracingConfig.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		e, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("error creating otlp trace exporter: %s", err)
+		}
+		exporter = e
+	case ExporterFile:
+		f, err := os.OpenFile(c.TracingConfig.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("error opening trace file %s: %s", c.TracingConfig.FilePath, err)
+		}
+		e, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("error creating file trace exporter: %s", err)
+		}
+		exporter = e
+		file = f
+	default:
+		return nil, fmt.Errorf("tracing exporter %s not recognised, must be one of %s or %s", c.TracingConfig.Exporter, ExporterOTLP, ExporterFile)
+	}
+
+	r := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(c.ApplicationName),
+		semconv.ServiceVersionKey.String(c.SoftwareVersion),
+	)
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(r),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if file != nil {
+			if closeErr := file.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
+
+// StartSpan starts a new span with the given name and attributes, as a child of any span already in ctx.
+// The returned context contains the new span, and should be passed on to anything called within the span.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends the given span, recording err on it first if it's not nil.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// HTTPClient returns an http client for making outgoing requests. If tracing is enabled, the client
+// records a span for every request it makes, and propagates the trace context to the remote server.
+func HTTPClient(c *config.Config) *http.Client {
+	if !c.TracingConfig.Enabled {
+		return http.DefaultClient
+	}
+	return &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+}
@@ -0,0 +1,93 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TracingTestSuite struct {
+	suite.Suite
+	config *config.Config
+}
+
+func (suite *TracingTestSuite) SetupTest() {
+	suite.config = config.TestDefault()
+	suite.config.TracingConfig.Enabled = true
+	suite.config.TracingConfig.Exporter = tracing.ExporterFile
+	suite.config.TracingConfig.FilePath = filepath.Join(suite.T().TempDir(), "traces.json")
+}
+
+func (suite *TracingTestSuite) TearDownTest() {
+	// put back the no-op tracer provider so other tests aren't affected
+	otel.SetTracerProvider(trace.NewNoopTracerProvider())
+}
+
+func (suite *TracingTestSuite) TestFileExporter() {
+	ctx := context.Background()
+	shutdown, err := tracing.Initialize(ctx, suite.config)
+	suite.NoError(err)
+
+	parentCtx, parent := tracing.StartSpan(ctx, "processor.StatusGet", attribute.String("status_id", "01F8MH75CBF9JFX4ZAD54N0W0R"))
+	_, child := tracing.StartSpan(parentCtx, "db.select")
+	tracing.EndSpan(child, errors.New("no entries"))
+	tracing.EndSpan(parent, nil)
+
+	// spans are only guaranteed to be written once the provider has been shut down
+	suite.NoError(shutdown(ctx))
+
+	b, err := os.ReadFile(suite.config.TracingConfig.FilePath)
+	suite.NoError(err)
+	suite.Contains(string(b), "processor.StatusGet")
+	suite.Contains(string(b), "01F8MH75CBF9JFX4ZAD54N0W0R")
+	suite.Contains(string(b), "db.select")
+	suite.Contains(string(b), "no entries")
+	suite.Contains(string(b), parent.SpanContext().TraceID().String())
+}
+
+func (suite *TracingTestSuite) TestDisabled() {
+	suite.config.TracingConfig.Enabled = false
+	shutdown, err := tracing.Initialize(context.Background(), suite.config)
+	suite.NoError(err)
+	suite.NoError(shutdown(context.Background()))
+
+	_, err = os.Stat(suite.config.TracingConfig.FilePath)
+	suite.True(os.IsNotExist(err))
+}
+
+func (suite *TracingTestSuite) TestUnknownExporter() {
+	suite.config.TracingConfig.Exporter = "jaeger"
+	_, err := tracing.Initialize(context.Background(), suite.config)
+	suite.Error(err)
+}
+
+func TestTracingTestSuite(t *testing.T) {
+	suite.Run(t, new(TracingTestSuite))
+}
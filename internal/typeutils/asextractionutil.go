@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/go-fed/activity/pub"
+	"github.com/go-fed/activity/streams/vocab"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
@@ -568,3 +569,89 @@ func extractObject(i withObject) (*url.URL, error) {
 	}
 	return nil, errors.New("no iri found for object prop")
 }
+
+func extractPoll(i Pollable) (*gtsmodel.Poll, error) {
+	poll := &gtsmodel.Poll{
+		Options: []string{},
+		Votes:   []int{},
+	}
+
+	// a question has its options in oneOf if only one can be chosen, or in anyOf if several can be
+	if oneOfProp := i.GetActivityStreamsOneOf(); oneOfProp != nil {
+		for iter := oneOfProp.Begin(); iter != oneOfProp.End(); iter = iter.Next() {
+			if !iter.IsActivityStreamsNote() {
+				continue
+			}
+			title, votes, err := extractPollOption(iter.GetActivityStreamsNote())
+			if err != nil {
+				return nil, err
+			}
+			poll.Options = append(poll.Options, title)
+			poll.Votes = append(poll.Votes, votes)
+		}
+	}
+
+	if anyOfProp := i.GetActivityStreamsAnyOf(); anyOfProp != nil && len(poll.Options) == 0 {
+		for iter := anyOfProp.Begin(); iter != anyOfProp.End(); iter = iter.Next() {
+			if !iter.IsActivityStreamsNote() {
+				continue
+			}
+			title, votes, err := extractPollOption(iter.GetActivityStreamsNote())
+			if err != nil {
+				return nil, err
+			}
+			poll.Options = append(poll.Options, title)
+			poll.Votes = append(poll.Votes, votes)
+		}
+		poll.Multiple = true
+	}
+
+	if len(poll.Options) == 0 {
+		return nil, errors.New("no poll options found")
+	}
+
+	if endTimeProp := i.GetActivityStreamsEndTime(); endTimeProp != nil && endTimeProp.IsXMLSchemaDateTime() {
+		poll.ExpiresAt = endTimeProp.Get()
+	}
+
+	if closedProp := i.GetActivityStreamsClosed(); closedProp != nil {
+		for iter := closedProp.Begin(); iter != closedProp.End(); iter = iter.Next() {
+			if iter.IsXMLSchemaDateTime() {
+				poll.ClosedAt = iter.GetXMLSchemaDateTime()
+				break
+			}
+			if iter.IsXMLSchemaBoolean() && iter.GetXMLSchemaBoolean() {
+				poll.ClosedAt = time.Now()
+				break
+			}
+		}
+	}
+
+	if votersCountProp := i.GetTootVotersCount(); votersCountProp != nil && votersCountProp.IsXMLSchemaNonNegativeInteger() {
+		poll.VotersCount = votersCountProp.Get()
+	} else if !poll.Multiple {
+		// everyone votes exactly once in a single choice poll
+		for _, v := range poll.Votes {
+			poll.VotersCount = poll.VotersCount + v
+		}
+	}
+
+	return poll, nil
+}
+
+func extractPollOption(note vocab.ActivityStreamsNote) (string, int, error) {
+	title, err := extractName(note)
+	if err != nil {
+		return "", 0, fmt.Errorf("error extracting poll option title: %s", err)
+	}
+
+	// the number of votes is the total number of items in the replies collection of the option
+	var votes int
+	if repliesProp := note.GetActivityStreamsReplies(); repliesProp != nil && repliesProp.IsActivityStreamsCollection() {
+		if totalItemsProp := repliesProp.GetActivityStreamsCollection().GetActivityStreamsTotalItems(); totalItemsProp != nil && totalItemsProp.IsXMLSchemaNonNegativeInteger() {
+			votes = totalItemsProp.Get()
+		}
+	}
+
+	return title, votes, nil
+}
@@ -62,6 +62,25 @@ type Statusable interface {
 	withReplies
 }
 
+// Pollable represents the minimum activitypub interface for representing a 'poll'.
+// This interface is fulfilled by: Question
+type Pollable interface {
+	withOneOf
+	withAnyOf
+	withEndTime
+	withClosed
+	withVotersCount
+}
+
+// Votable represents the minimum activitypub interface for representing a vote in a 'poll'.
+// This interface is fulfilled by: Note
+type Votable interface {
+	withJSONLDId
+	withName
+	withInReplyTo
+	withAttributedTo
+}
+
 // Attachmentable represents the minimum activitypub interface for representing a 'mediaAttachment'.
 // This interface is fulfilled by: Audio, Document, Image, Video
 type Attachmentable interface {
@@ -132,6 +151,16 @@ type Announceable interface {
 	withCC
 }
 
+// Flaggable represents the minimum interface for an activitystreams 'flag' activity.
+type Flaggable interface {
+	withJSONLDId
+	withTypeName
+
+	withActor
+	withObject
+	withContent
+}
+
 type withJSONLDId interface {
 	GetJSONLDId() vocab.JSONLDIdProperty
 }
@@ -236,6 +265,26 @@ type withReplies interface {
 	GetActivityStreamsReplies() vocab.ActivityStreamsRepliesProperty
 }
 
+type withOneOf interface {
+	GetActivityStreamsOneOf() vocab.ActivityStreamsOneOfProperty
+}
+
+type withAnyOf interface {
+	GetActivityStreamsAnyOf() vocab.ActivityStreamsAnyOfProperty
+}
+
+type withEndTime interface {
+	GetActivityStreamsEndTime() vocab.ActivityStreamsEndTimeProperty
+}
+
+type withClosed interface {
+	GetActivityStreamsClosed() vocab.ActivityStreamsClosedProperty
+}
+
+type withVotersCount interface {
+	GetTootVotersCount() vocab.TootVotersCountProperty
+}
+
 type withMediaType interface {
 	GetActivityStreamsMediaType() vocab.ActivityStreamsMediaTypeProperty
 }
@@ -19,16 +19,18 @@
 package typeutils
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (c *converter) ASRepresentationToAccount(accountable Accountable, update bool) (*gtsmodel.Account, error) {
+func (c *converter) ASRepresentationToAccount(ctx context.Context, accountable Accountable, update bool) (*gtsmodel.Account, error) {
 	// first check if we actually already know this account
 	uriProp := accountable.GetJSONLDId()
 	if uriProp == nil || !uriProp.IsIRI() {
@@ -38,7 +40,7 @@ func (c *converter) ASRepresentationToAccount(accountable Accountable, update bo
 
 	acct := &gtsmodel.Account{}
 	if !update {
-		err := c.db.GetWhere([]db.Where{{Key: "uri", Value: uri.String()}}, acct)
+		err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: uri.String()}}, acct)
 		if err == nil {
 			// we already know this account so we can skip generating it
 			return acct, nil
@@ -165,7 +167,7 @@ func (c *converter) ASRepresentationToAccount(accountable Accountable, update bo
 	return acct, nil
 }
 
-func (c *converter) ASStatusToStatus(statusable Statusable) (*gtsmodel.Status, error) {
+func (c *converter) ASStatusToStatus(ctx context.Context, statusable Statusable) (*gtsmodel.Status, error) {
 	status := &gtsmodel.Status{}
 
 	// uri at which this status is reachable
@@ -225,7 +227,7 @@ func (c *converter) ASStatusToStatus(statusable Statusable) (*gtsmodel.Status, e
 	status.AccountURI = attributedTo.String()
 
 	statusOwner := &gtsmodel.Account{}
-	if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: attributedTo.String(), CaseInsensitive: true}}, statusOwner); err != nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: attributedTo.String(), CaseInsensitive: true}}, statusOwner); err != nil {
 		return nil, fmt.Errorf("couldn't get status owner from db: %s", err)
 	}
 	status.AccountID = statusOwner.ID
@@ -241,7 +243,7 @@ func (c *converter) ASStatusToStatus(statusable Statusable) (*gtsmodel.Status, e
 
 		// now we can check if we have the replied-to status in our db already
 		inReplyToStatus := &gtsmodel.Status{}
-		if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: inReplyToURI.String()}}, inReplyToStatus); err == nil {
+		if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: inReplyToURI.String()}}, inReplyToStatus); err == nil {
 			// we have the status in our database already
 			// so we can set these fields here and then...
 			status.InReplyToID = inReplyToStatus.ID
@@ -250,7 +252,7 @@ func (c *converter) ASStatusToStatus(statusable Statusable) (*gtsmodel.Status, e
 
 			// ... check if we've seen the account already
 			inReplyToAccount := &gtsmodel.Account{}
-			if err := c.db.GetByID(inReplyToStatus.AccountID, inReplyToAccount); err == nil {
+			if err := c.db.GetByID(ctx, inReplyToStatus.AccountID, inReplyToAccount); err == nil {
 				status.GTSReplyToAccount = inReplyToAccount
 			}
 		}
@@ -312,10 +314,85 @@ func (c *converter) ASStatusToStatus(statusable Statusable) (*gtsmodel.Status, e
 	// ActivityStreamsType
 	status.ActivityStreamsType = statusable.GetTypeName()
 
+	// poll to create later on, if this status is a question
+	if pollable, ok := statusable.(Pollable); ok && status.ActivityStreamsType == gtsmodel.ActivityStreamsQuestion {
+		poll, err := c.ASPollToPoll(ctx, pollable)
+		if err != nil {
+			return nil, fmt.Errorf("error extracting poll: %s", err)
+		}
+		poll.AccountID = statusOwner.ID
+		status.GTSPoll = poll
+	}
+
 	return status, nil
 }
 
-func (c *converter) ASFollowToFollowRequest(followable Followable) (*gtsmodel.FollowRequest, error) {
+func (c *converter) ASPollToPoll(ctx context.Context, pollable Pollable) (*gtsmodel.Poll, error) {
+	return extractPoll(pollable)
+}
+
+func (c *converter) ASVoteToPollVote(ctx context.Context, votable Votable) (*gtsmodel.PollVote, *gtsmodel.Poll, error) {
+	idProp := votable.GetJSONLDId()
+	if idProp == nil || !idProp.IsIRI() {
+		return nil, nil, errors.New("no id property set on vote, or was not an iri")
+	}
+	uri := idProp.GetIRI().String()
+
+	choiceName, err := extractName(votable)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error extracting name of vote: %s", err)
+	}
+
+	inReplyToURI, err := extractInReplyToURI(votable)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error extracting inReplyTo of vote: %s", err)
+	}
+
+	// votes can only be made in polls that we own
+	status := &gtsmodel.Status{}
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: inReplyToURI.String()}}, status); err != nil {
+		return nil, nil, fmt.Errorf("error getting status %s from the db: %s", inReplyToURI.String(), err)
+	}
+	if !status.Local || status.ActivityStreamsType != gtsmodel.ActivityStreamsQuestion {
+		return nil, nil, fmt.Errorf("status %s is not a local poll", inReplyToURI.String())
+	}
+
+	poll := &gtsmodel.Poll{}
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: status.ID}}, poll); err != nil {
+		return nil, nil, fmt.Errorf("error getting poll of status %s from the db: %s", status.ID, err)
+	}
+
+	choice := -1
+	for i, option := range poll.Options {
+		if option == choiceName {
+			choice = i
+			break
+		}
+	}
+	if choice == -1 {
+		return nil, nil, fmt.Errorf("poll %s has no option %s", poll.ID, choiceName)
+	}
+
+	attributedTo, err := extractAttributedTo(votable)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error extracting attributedTo of vote: %s", err)
+	}
+
+	voter := &gtsmodel.Account{}
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: attributedTo.String()}}, voter); err != nil {
+		return nil, nil, fmt.Errorf("error getting voter account %s from the db: %s", attributedTo.String(), err)
+	}
+
+	return &gtsmodel.PollVote{
+		CreatedAt: time.Now(),
+		PollID:    poll.ID,
+		AccountID: voter.ID,
+		Choice:    choice,
+		URI:       uri,
+	}, poll, nil
+}
+
+func (c *converter) ASFollowToFollowRequest(ctx context.Context, followable Followable) (*gtsmodel.FollowRequest, error) {
 
 	idProp := followable.GetJSONLDId()
 	if idProp == nil || !idProp.IsIRI() {
@@ -328,7 +405,7 @@ func (c *converter) ASFollowToFollowRequest(followable Followable) (*gtsmodel.Fo
 		return nil, errors.New("error extracting actor property from follow")
 	}
 	originAccount := &gtsmodel.Account{}
-	if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: origin.String()}}, originAccount); err != nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: origin.String()}}, originAccount); err != nil {
 		return nil, fmt.Errorf("error extracting account with uri %s from the database: %s", origin.String(), err)
 	}
 
@@ -337,7 +414,7 @@ func (c *converter) ASFollowToFollowRequest(followable Followable) (*gtsmodel.Fo
 		return nil, errors.New("error extracting object property from follow")
 	}
 	targetAccount := &gtsmodel.Account{}
-	if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: target.String()}}, targetAccount); err != nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: target.String()}}, targetAccount); err != nil {
 		return nil, fmt.Errorf("error extracting account with uri %s from the database: %s", origin.String(), err)
 	}
 
@@ -350,7 +427,7 @@ func (c *converter) ASFollowToFollowRequest(followable Followable) (*gtsmodel.Fo
 	return followRequest, nil
 }
 
-func (c *converter) ASFollowToFollow(followable Followable) (*gtsmodel.Follow, error) {
+func (c *converter) ASFollowToFollow(ctx context.Context, followable Followable) (*gtsmodel.Follow, error) {
 	idProp := followable.GetJSONLDId()
 	if idProp == nil || !idProp.IsIRI() {
 		return nil, errors.New("no id property set on follow, or was not an iri")
@@ -362,7 +439,7 @@ func (c *converter) ASFollowToFollow(followable Followable) (*gtsmodel.Follow, e
 		return nil, errors.New("error extracting actor property from follow")
 	}
 	originAccount := &gtsmodel.Account{}
-	if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: origin.String()}}, originAccount); err != nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: origin.String()}}, originAccount); err != nil {
 		return nil, fmt.Errorf("error extracting account with uri %s from the database: %s", origin.String(), err)
 	}
 
@@ -371,7 +448,7 @@ func (c *converter) ASFollowToFollow(followable Followable) (*gtsmodel.Follow, e
 		return nil, errors.New("error extracting object property from follow")
 	}
 	targetAccount := &gtsmodel.Account{}
-	if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: target.String()}}, targetAccount); err != nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: target.String()}}, targetAccount); err != nil {
 		return nil, fmt.Errorf("error extracting account with uri %s from the database: %s", origin.String(), err)
 	}
 
@@ -384,7 +461,7 @@ func (c *converter) ASFollowToFollow(followable Followable) (*gtsmodel.Follow, e
 	return follow, nil
 }
 
-func (c *converter) ASLikeToFave(likeable Likeable) (*gtsmodel.StatusFave, error) {
+func (c *converter) ASLikeToFave(ctx context.Context, likeable Likeable) (*gtsmodel.StatusFave, error) {
 	idProp := likeable.GetJSONLDId()
 	if idProp == nil || !idProp.IsIRI() {
 		return nil, errors.New("no id property set on like, or was not an iri")
@@ -396,7 +473,7 @@ func (c *converter) ASLikeToFave(likeable Likeable) (*gtsmodel.StatusFave, error
 		return nil, errors.New("error extracting actor property from like")
 	}
 	originAccount := &gtsmodel.Account{}
-	if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: origin.String()}}, originAccount); err != nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: origin.String()}}, originAccount); err != nil {
 		return nil, fmt.Errorf("error extracting account with uri %s from the database: %s", origin.String(), err)
 	}
 
@@ -406,12 +483,12 @@ func (c *converter) ASLikeToFave(likeable Likeable) (*gtsmodel.StatusFave, error
 	}
 
 	targetStatus := &gtsmodel.Status{}
-	if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: target.String()}}, targetStatus); err != nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: target.String()}}, targetStatus); err != nil {
 		return nil, fmt.Errorf("error extracting status with uri %s from the database: %s", target.String(), err)
 	}
 
 	targetAccount := &gtsmodel.Account{}
-	if err := c.db.GetByID(targetStatus.AccountID, targetAccount); err != nil {
+	if err := c.db.GetByID(ctx, targetStatus.AccountID, targetAccount); err != nil {
 		return nil, fmt.Errorf("error extracting account with id %s from the database: %s", targetStatus.AccountID, err)
 	}
 
@@ -426,7 +503,7 @@ func (c *converter) ASLikeToFave(likeable Likeable) (*gtsmodel.StatusFave, error
 	}, nil
 }
 
-func (c *converter) ASBlockToBlock(blockable Blockable) (*gtsmodel.Block, error) {
+func (c *converter) ASBlockToBlock(ctx context.Context, blockable Blockable) (*gtsmodel.Block, error) {
 	idProp := blockable.GetJSONLDId()
 	if idProp == nil || !idProp.IsIRI() {
 		return nil, errors.New("ASBlockToBlock: no id property set on block, or was not an iri")
@@ -438,7 +515,7 @@ func (c *converter) ASBlockToBlock(blockable Blockable) (*gtsmodel.Block, error)
 		return nil, errors.New("ASBlockToBlock: error extracting actor property from block")
 	}
 	originAccount := &gtsmodel.Account{}
-	if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: origin.String()}}, originAccount); err != nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: origin.String()}}, originAccount); err != nil {
 		return nil, fmt.Errorf("ASBlockToBlock: error extracting account with uri %s from the database: %s", origin.String(), err)
 	}
 
@@ -448,7 +525,7 @@ func (c *converter) ASBlockToBlock(blockable Blockable) (*gtsmodel.Block, error)
 	}
 
 	targetAccount := &gtsmodel.Account{}
-	if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: target.String(), CaseInsensitive: true}}, targetAccount); err != nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: target.String(), CaseInsensitive: true}}, targetAccount); err != nil {
 		return nil, fmt.Errorf("ASBlockToBlock: error extracting account with uri %s from the database: %s", target.String(), err)
 	}
 
@@ -461,7 +538,7 @@ func (c *converter) ASBlockToBlock(blockable Blockable) (*gtsmodel.Block, error)
 	}, nil
 }
 
-func (c *converter) ASAnnounceToStatus(announceable Announceable) (*gtsmodel.Status, bool, error) {
+func (c *converter) ASAnnounceToStatus(ctx context.Context, announceable Announceable) (*gtsmodel.Status, bool, error) {
 	status := &gtsmodel.Status{}
 	isNew := true
 
@@ -472,7 +549,7 @@ func (c *converter) ASAnnounceToStatus(announceable Announceable) (*gtsmodel.Sta
 	}
 	uri := idProp.GetIRI().String()
 
-	if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: uri}}, status); err == nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: uri}}, status); err == nil {
 		// we already have it, great, just return it as-is :)
 		isNew = false
 		return status, isNew, nil
@@ -507,7 +584,7 @@ func (c *converter) ASAnnounceToStatus(announceable Announceable) (*gtsmodel.Sta
 	// get the boosting account based on the URI
 	// this should have been dereferenced already before we hit this point so we can confidently error out if we don't have it
 	boostingAccount := &gtsmodel.Account{}
-	if err := c.db.GetWhere([]db.Where{{Key: "uri", Value: actor.String()}}, boostingAccount); err != nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: actor.String()}}, boostingAccount); err != nil {
 		return nil, isNew, fmt.Errorf("ASAnnounceToStatus: error in db fetching account with uri %s: %s", actor.String(), err)
 	}
 	status.AccountID = boostingAccount.ID
@@ -555,6 +632,75 @@ func (c *converter) ASAnnounceToStatus(announceable Announceable) (*gtsmodel.Sta
 	return status, isNew, nil
 }
 
+func (c *converter) ASFlagToReport(ctx context.Context, flaggable Flaggable) (*gtsmodel.Report, error) {
+	idProp := flaggable.GetJSONLDId()
+	if idProp == nil || !idProp.IsIRI() {
+		return nil, errors.New("ASFlagToReport: no id property set on flag, or was not an iri")
+	}
+	uri := idProp.GetIRI().String()
+
+	origin, err := extractActor(flaggable)
+	if err != nil {
+		return nil, errors.New("ASFlagToReport: error extracting actor property from flag")
+	}
+	originAccount := &gtsmodel.Account{}
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: origin.String()}}, originAccount); err != nil {
+		return nil, fmt.Errorf("ASFlagToReport: error extracting account with uri %s from the database: %s", origin.String(), err)
+	}
+
+	// the object of a flag is the reported account, along with any of its statuses that are being reported, in no particular order
+	objectProp := flaggable.GetActivityStreamsObject()
+	if objectProp == nil {
+		return nil, errors.New("ASFlagToReport: object property was nil")
+	}
+	var targetAccount *gtsmodel.Account
+	statuses := []*gtsmodel.Status{}
+	for iter := objectProp.Begin(); iter != objectProp.End(); iter = iter.Next() {
+		if !iter.IsIRI() || iter.GetIRI() == nil {
+			continue
+		}
+		objectURI := iter.GetIRI().String()
+
+		s := &gtsmodel.Status{}
+		if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: objectURI}}, s); err == nil {
+			statuses = append(statuses, s)
+			continue
+		}
+
+		a := &gtsmodel.Account{}
+		if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: objectURI, CaseInsensitive: true}}, a); err == nil {
+			targetAccount = a
+		}
+	}
+
+	if targetAccount == nil {
+		return nil, errors.New("ASFlagToReport: flag didn't contain an account that we know about")
+	}
+
+	if targetAccount.Domain != "" {
+		return nil, fmt.Errorf("ASFlagToReport: reported account %s is not a local account", targetAccount.URI)
+	}
+
+	// only keep statuses that were actually posted by the reported account
+	statusIDs := []string{}
+	for _, s := range statuses {
+		if s.AccountID == targetAccount.ID {
+			statusIDs = append(statusIDs, s.ID)
+		}
+	}
+
+	// the comment is optional
+	comment, _ := extractContent(flaggable)
+
+	return &gtsmodel.Report{
+		URI:             uri,
+		AccountID:       originAccount.ID,
+		TargetAccountID: targetAccount.ID,
+		StatusIDs:       statusIDs,
+		Comment:         comment,
+	}, nil
+}
+
 func isPublic(tos []*url.URL) bool {
 	for _, entry := range tos {
 		if strings.EqualFold(entry.String(), "https://www.w3.org/ns/activitystreams#Public") {
@@ -338,7 +338,7 @@ func (suite *ASToInternalTestSuite) SetupSuite() {
 	suite.log = testrig.NewTestLog()
 	suite.accounts = testrig.NewTestAccounts()
 	suite.people = testrig.NewTestFediPeople()
-	suite.typeconverter = typeutils.NewConverter(suite.config, suite.db)
+	suite.typeconverter = typeutils.NewConverter(suite.config, suite.db, testrig.NewTestStorage())
 }
 
 func (suite *ASToInternalTestSuite) SetupTest() {
@@ -349,7 +349,7 @@ func (suite *ASToInternalTestSuite) TestParsePerson() {
 
 	testPerson := suite.people["new_person_1"]
 
-	acct, err := suite.typeconverter.ASRepresentationToAccount(testPerson, false)
+	acct, err := suite.typeconverter.ASRepresentationToAccount(context.Background(), testPerson, false)
 	assert.NoError(suite.T(), err)
 
 	fmt.Printf("%+v", acct)
@@ -367,7 +367,7 @@ func (suite *ASToInternalTestSuite) TestParseGargron() {
 	rep, ok := t.(typeutils.Accountable)
 	assert.True(suite.T(), ok)
 
-	acct, err := suite.typeconverter.ASRepresentationToAccount(rep, false)
+	acct, err := suite.typeconverter.ASRepresentationToAccount(context.Background(), rep, false)
 	assert.NoError(suite.T(), err)
 
 	fmt.Printf("%+v", acct)
@@ -394,7 +394,7 @@ func (suite *ASToInternalTestSuite) TestParseStatus() {
 	rep, ok := first.GetType().(typeutils.Statusable)
 	assert.True(suite.T(), ok)
 
-	status, err := suite.typeconverter.ASStatusToStatus(rep)
+	status, err := suite.typeconverter.ASStatusToStatus(context.Background(), rep)
 	assert.NoError(suite.T(), err)
 
 	assert.Len(suite.T(), status.GTSEmojis, 3)
@@ -421,7 +421,7 @@ func (suite *ASToInternalTestSuite) TestParseStatusWithMention() {
 	rep, ok := first.GetType().(typeutils.Statusable)
 	assert.True(suite.T(), ok)
 
-	status, err := suite.typeconverter.ASStatusToStatus(rep)
+	status, err := suite.typeconverter.ASStatusToStatus(context.Background(), rep)
 	assert.NoError(suite.T(), err)
 
 	fmt.Printf("%+v", status)
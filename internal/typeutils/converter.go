@@ -19,8 +19,11 @@
 package typeutils
 
 import (
+	"context"
+
 	"github.com/go-fed/activity/streams/vocab"
 	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/blob"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
@@ -43,11 +46,11 @@ type TypeConverter interface {
 	// AccountToMastoSensitive takes a db model account as a param, and returns a populated mastotype account, or an error
 	// if something goes wrong. The returned account should be ready to serialize on an API level, and may have sensitive fields,
 	// so serve it only to an authorized user who should have permission to see it.
-	AccountToMastoSensitive(account *gtsmodel.Account) (*model.Account, error)
+	AccountToMastoSensitive(ctx context.Context, account *gtsmodel.Account) (*model.Account, error)
 	// AccountToMastoPublic takes a db model account as a param, and returns a populated mastotype account, or an error
 	// if something goes wrong. The returned account should be ready to serialize on an API level, and may NOT have sensitive fields.
 	// In other words, this is the public record that the server has of an account.
-	AccountToMastoPublic(account *gtsmodel.Account) (*model.Account, error)
+	AccountToMastoPublic(ctx context.Context, account *gtsmodel.Account) (*model.Account, error)
 	// AccountToMastoBlocked takes a db model account as a param, and returns a mastotype account, or an error if
 	// something goes wrong. The returned account will be a bare minimum representation of the account. This function should be used
 	// when someone wants to view an account they've blocked.
@@ -61,9 +64,9 @@ type TypeConverter interface {
 	// fields sanitized so that it can be served to non-authorized accounts without revealing any private information.
 	AppToMastoPublic(application *gtsmodel.Application) (*model.Application, error)
 	// AttachmentToMasto converts a gts model media attacahment into its mastodon representation for serialization on the API.
-	AttachmentToMasto(attachment *gtsmodel.MediaAttachment) (model.Attachment, error)
+	AttachmentToMasto(ctx context.Context, attachment *gtsmodel.MediaAttachment) (model.Attachment, error)
 	// MentionToMasto converts a gts model mention into its mastodon (frontend) representation for serialization on the API.
-	MentionToMasto(m *gtsmodel.Mention) (model.Mention, error)
+	MentionToMasto(ctx context.Context, m *gtsmodel.Mention) (model.Mention, error)
 	// EmojiToMasto converts a gts model emoji into its mastodon (frontend) representation for serialization on the API.
 	EmojiToMasto(e *gtsmodel.Emoji) (model.Emoji, error)
 	// TagToMasto converts a gts model tag into its mastodon (frontend) representation for serialization on the API.
@@ -71,17 +74,33 @@ type TypeConverter interface {
 	// StatusToMasto converts a gts model status into its mastodon (frontend) representation for serialization on the API.
 	//
 	// Requesting account can be nil.
-	StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmodel.Account) (*model.Status, error)
+	StatusToMasto(ctx context.Context, s *gtsmodel.Status, requestingAccount *gtsmodel.Account) (*model.Status, error)
 	// VisToMasto converts a gts visibility into its mastodon equivalent
 	VisToMasto(m gtsmodel.Visibility) model.Visibility
 	// InstanceToMasto converts a gts instance into its mastodon equivalent for serving at /api/v1/instance
-	InstanceToMasto(i *gtsmodel.Instance) (*model.Instance, error)
+	InstanceToMasto(ctx context.Context, i *gtsmodel.Instance) (*model.Instance, error)
 	// RelationshipToMasto converts a gts relationship into its mastodon equivalent for serving in various places
 	RelationshipToMasto(r *gtsmodel.Relationship) (*model.Relationship, error)
 	// NotificationToMasto converts a gts notification into a mastodon notification
-	NotificationToMasto(n *gtsmodel.Notification) (*model.Notification, error)
+	NotificationToMasto(ctx context.Context, n *gtsmodel.Notification) (*model.Notification, error)
 	// DomainBlockTomasto converts a gts model domin block into a mastodon domain block, for serving at /api/v1/admin/domain_blocks
 	DomainBlockToMasto(b *gtsmodel.DomainBlock, export bool) (*model.DomainBlock, error)
+	// ListToMasto converts a gts model list into its mastodon representation, for serving at /api/v1/lists
+	ListToMasto(l *gtsmodel.List) (*model.List, error)
+	// FilterToMasto converts a gts model filter into its mastodon representation, for serving at /api/v1/filters
+	FilterToMasto(f *gtsmodel.Filter) (*model.Filter, error)
+	// PollToMasto converts a gts model poll into its mastodon representation, for serving at /api/v1/polls and in statuses.
+	//
+	// Requesting account can be nil.
+	PollToMasto(ctx context.Context, p *gtsmodel.Poll, requestingAccount *gtsmodel.Account) (*model.Poll, error)
+	// ScheduledStatusToMasto converts a gts model scheduled status into its mastodon representation, for serving at /api/v1/scheduled_statuses.
+	ScheduledStatusToMasto(ctx context.Context, s *gtsmodel.ScheduledStatus) (*model.ScheduledStatus, error)
+	// ReportToMasto converts a gts model report into its mastodon representation, for serving to the account that made the report.
+	ReportToMasto(ctx context.Context, r *gtsmodel.Report) (*model.Report, error)
+	// ReportToAdminMasto converts a gts model report into the admin view of a report, for serving at /api/v1/admin/reports.
+	ReportToAdminMasto(ctx context.Context, r *gtsmodel.Report, requestingAccount *gtsmodel.Account) (*model.AdminReportInfo, error)
+	// ConversationToMasto converts a gts model conversation into its mastodon representation, for serving at /api/v1/conversations.
+	ConversationToMasto(ctx context.Context, c *gtsmodel.Conversation, requestingAccount *gtsmodel.Account) (*model.Conversation, error)
 
 	/*
 		FRONTEND (mastodon) MODEL TO INTERNAL (gts) MODEL
@@ -99,17 +118,26 @@ type TypeConverter interface {
 	// If update is false, and the account is already known in the database, then the existing account entry will be returned.
 	// If update is true, then even if the account is already known, all fields in the accountable will be parsed and a new *gtsmodel.Account
 	// will be generated. This is useful when one needs to force refresh of an account, eg., during an Update of a Profile.
-	ASRepresentationToAccount(accountable Accountable, update bool) (*gtsmodel.Account, error)
+	ASRepresentationToAccount(ctx context.Context, accountable Accountable, update bool) (*gtsmodel.Account, error)
 	// ASStatus converts a remote activitystreams 'status' representation into a gts model status.
-	ASStatusToStatus(statusable Statusable) (*gtsmodel.Status, error)
+	ASStatusToStatus(ctx context.Context, statusable Statusable) (*gtsmodel.Status, error)
+	// ASPollToPoll converts the options, tallies and end time of a remote activitystreams 'question' into a gts model poll.
+	//
+	// The returned poll won't have its ID, StatusID or AccountID set.
+	ASPollToPoll(ctx context.Context, pollable Pollable) (*gtsmodel.Poll, error)
+	// ASVoteToPollVote converts a remote activitystreams vote, which is a note with the title of the chosen option as its name
+	// and the poll as its inReplyTo, into a gts model poll vote. The local poll that the vote was made in is returned alongside it.
+	//
+	// The returned vote won't have its ID set.
+	ASVoteToPollVote(ctx context.Context, votable Votable) (*gtsmodel.PollVote, *gtsmodel.Poll, error)
 	// ASFollowToFollowRequest converts a remote activitystreams `follow` representation into gts model follow request.
-	ASFollowToFollowRequest(followable Followable) (*gtsmodel.FollowRequest, error)
+	ASFollowToFollowRequest(ctx context.Context, followable Followable) (*gtsmodel.FollowRequest, error)
 	// ASFollowToFollowRequest converts a remote activitystreams `follow` representation into gts model follow.
-	ASFollowToFollow(followable Followable) (*gtsmodel.Follow, error)
+	ASFollowToFollow(ctx context.Context, followable Followable) (*gtsmodel.Follow, error)
 	// ASLikeToFave converts a remote activitystreams 'like' representation into a gts model status fave.
-	ASLikeToFave(likeable Likeable) (*gtsmodel.StatusFave, error)
+	ASLikeToFave(ctx context.Context, likeable Likeable) (*gtsmodel.StatusFave, error)
 	// ASBlockToBlock converts a remote activity streams 'block' representation into a gts model block.
-	ASBlockToBlock(blockable Blockable) (*gtsmodel.Block, error)
+	ASBlockToBlock(ctx context.Context, blockable Blockable) (*gtsmodel.Block, error)
 	// ASAnnounceToStatus converts an activitystreams 'announce' into a status.
 	//
 	// The returned bool indicates whether this status is new (true) or not new (false).
@@ -122,14 +150,18 @@ type TypeConverter interface {
 	// This is useful when multiple users on an instance might receive the same boost, and we only want to process the boost once.
 	//
 	// NOTE -- this is different from one status being boosted multiple times! In this case, new boosts should indeed be created.
-	ASAnnounceToStatus(announceable Announceable) (status *gtsmodel.Status, new bool, err error)
+	ASAnnounceToStatus(ctx context.Context, announceable Announceable) (status *gtsmodel.Status, new bool, err error)
+	// ASFlagToReport converts a remote activitystreams 'flag' into a gts model report of a local account.
+	//
+	// The returned report won't have its ID set.
+	ASFlagToReport(ctx context.Context, flaggable Flaggable) (*gtsmodel.Report, error)
 
 	/*
 		INTERNAL (gts) MODEL TO ACTIVITYSTREAMS MODEL
 	*/
 
 	// AccountToAS converts a gts model account into an activity streams person, suitable for federation
-	AccountToAS(a *gtsmodel.Account) (vocab.ActivityStreamsPerson, error)
+	AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab.ActivityStreamsPerson, error)
 	// AccountToASMinimal converts a gts model account into an activity streams person, suitable for federation.
 	//
 	// The returned account will just have the Type, Username, PublicKey, and ID properties set. This is
@@ -137,19 +169,29 @@ type TypeConverter interface {
 	// we don't trust them (yet).
 	AccountToASMinimal(a *gtsmodel.Account) (vocab.ActivityStreamsPerson, error)
 	// StatusToAS converts a gts model status into an activity streams note, suitable for federation
-	StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, error)
+	StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsNote, error)
+	// PollStatusToAS converts a gts model status with a poll into an activity streams question, suitable for federation
+	PollStatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsQuestion, error)
 	// FollowToASFollow converts a gts model Follow into an activity streams Follow, suitable for federation
 	FollowToAS(f *gtsmodel.Follow, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (vocab.ActivityStreamsFollow, error)
 	// MentionToAS converts a gts model mention into an activity streams Mention, suitable for federation
-	MentionToAS(m *gtsmodel.Mention) (vocab.ActivityStreamsMention, error)
+	MentionToAS(ctx context.Context, m *gtsmodel.Mention) (vocab.ActivityStreamsMention, error)
 	// AttachmentToAS converts a gts model media attachment into an activity streams Attachment, suitable for federation
 	AttachmentToAS(a *gtsmodel.MediaAttachment) (vocab.ActivityStreamsDocument, error)
 	// FaveToAS converts a gts model status fave into an activityStreams LIKE, suitable for federation.
-	FaveToAS(f *gtsmodel.StatusFave) (vocab.ActivityStreamsLike, error)
+	FaveToAS(ctx context.Context, f *gtsmodel.StatusFave) (vocab.ActivityStreamsLike, error)
 	// BoostToAS converts a gts model boost into an activityStreams ANNOUNCE, suitable for federation
-	BoostToAS(boostWrapperStatus *gtsmodel.Status, boostingAccount *gtsmodel.Account, boostedAccount *gtsmodel.Account) (vocab.ActivityStreamsAnnounce, error)
+	BoostToAS(ctx context.Context, boostWrapperStatus *gtsmodel.Status, boostingAccount *gtsmodel.Account, boostedAccount *gtsmodel.Account) (vocab.ActivityStreamsAnnounce, error)
 	// BlockToAS converts a gts model block into an activityStreams BLOCK, suitable for federation.
-	BlockToAS(block *gtsmodel.Block) (vocab.ActivityStreamsBlock, error)
+	BlockToAS(ctx context.Context, block *gtsmodel.Block) (vocab.ActivityStreamsBlock, error)
+	// PollVoteToAS converts a gts model poll vote into an activityStreams NOTE that replies to the poll, suitable for federation.
+	PollVoteToAS(ctx context.Context, vote *gtsmodel.PollVote) (vocab.ActivityStreamsNote, error)
+	// StatusesToASFeaturedCollection converts the pinned statuses of the given account into an activityStreams ORDEREDCOLLECTION, suitable for serving at the account's featured URI.
+	StatusesToASFeaturedCollection(ctx context.Context, a *gtsmodel.Account, statuses []*gtsmodel.Status) (vocab.ActivityStreamsOrderedCollection, error)
+	// ReportToASFlag converts a gts model report into an activityStreams FLAG, suitable for forwarding to the instance of the reported account.
+	//
+	// The flag is sent from the given instance account rather than the account that made the report, so that the reporter stays anonymous.
+	ReportToASFlag(ctx context.Context, r *gtsmodel.Report, instanceAccount *gtsmodel.Account) (vocab.ActivityStreamsFlag, error)
 
 	/*
 		INTERNAL (gts) MODEL TO INTERNAL MODEL
@@ -166,17 +208,30 @@ type TypeConverter interface {
 
 	// WrapPersonInUpdate
 	WrapPersonInUpdate(person vocab.ActivityStreamsPerson, originAccount *gtsmodel.Account) (vocab.ActivityStreamsUpdate, error)
+	// WrapQuestionInCreate wraps a question, ie., a status with a poll, in a create, so that it can be federated as a new status.
+	WrapQuestionInCreate(question vocab.ActivityStreamsQuestion, originAccount *gtsmodel.Account) (vocab.ActivityStreamsCreate, error)
+	// WrapQuestionInUpdate wraps a question, ie., a status with a poll, in an update, so that changes to its tallies can be federated.
+	WrapQuestionInUpdate(question vocab.ActivityStreamsQuestion, originAccount *gtsmodel.Account) (vocab.ActivityStreamsUpdate, error)
+	// WrapStatusInAdd wraps a reference to a status in an add targeting the featured collection of originAccount, so that a new pin can be federated.
+	WrapStatusInAdd(status *gtsmodel.Status, originAccount *gtsmodel.Account) (vocab.ActivityStreamsAdd, error)
+	// WrapStatusInRemove wraps a reference to a status in a remove targeting the featured collection of originAccount, so that an unpin can be federated.
+	WrapStatusInRemove(status *gtsmodel.Status, originAccount *gtsmodel.Account) (vocab.ActivityStreamsRemove, error)
 }
 
 type converter struct {
-	config *config.Config
-	db     db.DB
+	config  *config.Config
+	db      db.DB
+	storage blob.Storage
 }
 
-// NewConverter returns a new Converter
-func NewConverter(config *config.Config, db db.DB) TypeConverter {
+// NewConverter returns a new Converter.
+//
+// The given storage is used to find out whether media should be served to clients directly from the storage backend.
+// It may be nil, in which case media urls always point to the gotosocial fileserver.
+func NewConverter(config *config.Config, db db.DB, storage blob.Storage) TypeConverter {
 	return &converter{
-		config: config,
-		db:     db,
+		config:  config,
+		db:      db,
+		storage: storage,
 	}
 }
@@ -19,6 +19,7 @@
 package typeutils
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -32,7 +33,7 @@ import (
 
 // Converts a gts model account into an Activity Streams person type, following
 // the spec laid out for mastodon here: https://docs.joinmastodon.org/spec/activitypub/
-func (c *converter) AccountToAS(a *gtsmodel.Account) (vocab.ActivityStreamsPerson, error) {
+func (c *converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab.ActivityStreamsPerson, error) {
 	person := streams.NewActivityStreamsPerson()
 
 	// id should be the activitypub URI of this user
@@ -203,7 +204,7 @@ func (c *converter) AccountToAS(a *gtsmodel.Account) (vocab.ActivityStreamsPerso
 	// Used as profile avatar.
 	if a.AvatarMediaAttachmentID != "" {
 		avatar := &gtsmodel.MediaAttachment{}
-		if err := c.db.GetByID(a.AvatarMediaAttachmentID, avatar); err != nil {
+		if err := c.db.GetByID(ctx, a.AvatarMediaAttachmentID, avatar); err != nil {
 			return nil, err
 		}
 
@@ -231,7 +232,7 @@ func (c *converter) AccountToAS(a *gtsmodel.Account) (vocab.ActivityStreamsPerso
 	// Used as profile header.
 	if a.HeaderMediaAttachmentID != "" {
 		header := &gtsmodel.MediaAttachment{}
-		if err := c.db.GetByID(a.HeaderMediaAttachmentID, header); err != nil {
+		if err := c.db.GetByID(ctx, a.HeaderMediaAttachmentID, header); err != nil {
 			return nil, err
 		}
 
@@ -324,26 +325,119 @@ func (c *converter) AccountToASMinimal(a *gtsmodel.Account) (vocab.ActivityStrea
 	return person, nil
 }
 
-func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, error) {
+// asStatus is implemented by the activity streams types that a status can be represented as, such as Note and Question.
+type asStatus interface {
+	SetJSONLDId(i vocab.JSONLDIdProperty)
+	SetActivityStreamsSummary(i vocab.ActivityStreamsSummaryProperty)
+	SetActivityStreamsInReplyTo(i vocab.ActivityStreamsInReplyToProperty)
+	SetActivityStreamsPublished(i vocab.ActivityStreamsPublishedProperty)
+	SetActivityStreamsUrl(i vocab.ActivityStreamsUrlProperty)
+	SetActivityStreamsAttributedTo(i vocab.ActivityStreamsAttributedToProperty)
+	SetActivityStreamsTag(i vocab.ActivityStreamsTagProperty)
+	SetActivityStreamsTo(i vocab.ActivityStreamsToProperty)
+	SetActivityStreamsCc(i vocab.ActivityStreamsCcProperty)
+	SetActivityStreamsContent(i vocab.ActivityStreamsContentProperty)
+	SetActivityStreamsAttachment(i vocab.ActivityStreamsAttachmentProperty)
+}
+
+func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsNote, error) {
+	status := streams.NewActivityStreamsNote()
+	if err := c.statusToAS(ctx, s, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (c *converter) PollStatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsQuestion, error) {
+	question := streams.NewActivityStreamsQuestion()
+	if err := c.statusToAS(ctx, s, question); err != nil {
+		return nil, err
+	}
+
+	// check if the poll is already attached to the status and attach it if not
+	if s.GTSPoll == nil {
+		p := &gtsmodel.Poll{}
+		if err := c.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: s.ID}}, p); err != nil {
+			return nil, fmt.Errorf("PollStatusToAS: error retrieving poll from db: %s", err)
+		}
+		s.GTSPoll = p
+	}
+	poll := s.GTSPoll
+
+	// oneOf or anyOf -- the options of the poll, each with the number of votes it got as the total of its replies
+	oneOfProp := streams.NewActivityStreamsOneOfProperty()
+	anyOfProp := streams.NewActivityStreamsAnyOfProperty()
+	for i, title := range poll.Options {
+		option := streams.NewActivityStreamsNote()
+
+		nameProp := streams.NewActivityStreamsNameProperty()
+		nameProp.AppendXMLSchemaString(title)
+		option.SetActivityStreamsName(nameProp)
+
+		var votes int
+		if i < len(poll.Votes) {
+			votes = poll.Votes[i]
+		}
+		totalItemsProp := streams.NewActivityStreamsTotalItemsProperty()
+		totalItemsProp.Set(votes)
+		replies := streams.NewActivityStreamsCollection()
+		replies.SetActivityStreamsTotalItems(totalItemsProp)
+		repliesProp := streams.NewActivityStreamsRepliesProperty()
+		repliesProp.SetActivityStreamsCollection(replies)
+		option.SetActivityStreamsReplies(repliesProp)
+
+		if poll.Multiple {
+			anyOfProp.AppendActivityStreamsNote(option)
+		} else {
+			oneOfProp.AppendActivityStreamsNote(option)
+		}
+	}
+	if poll.Multiple {
+		question.SetActivityStreamsAnyOf(anyOfProp)
+	} else {
+		question.SetActivityStreamsOneOf(oneOfProp)
+	}
+
+	// endTime
+	if !poll.ExpiresAt.IsZero() {
+		endTimeProp := streams.NewActivityStreamsEndTimeProperty()
+		endTimeProp.Set(poll.ExpiresAt)
+		question.SetActivityStreamsEndTime(endTimeProp)
+	}
+
+	// closed
+	if !poll.ClosedAt.IsZero() {
+		closedProp := streams.NewActivityStreamsClosedProperty()
+		closedProp.AppendXMLSchemaDateTime(poll.ClosedAt)
+		question.SetActivityStreamsClosed(closedProp)
+	}
+
+	// votersCount
+	votersCountProp := streams.NewTootVotersCountProperty()
+	votersCountProp.Set(poll.VotersCount)
+	question.SetTootVotersCount(votersCountProp)
+
+	return question, nil
+}
+
+// statusToAS sets the properties that are common to all activity streams representations of the given status.
+func (c *converter) statusToAS(ctx context.Context, s *gtsmodel.Status, status asStatus) error {
 	// ensure prerequisites here before we get stuck in
 
 	// check if author account is already attached to status and attach it if not
 	// if we can't retrieve this, bail here already because we can't attribute the status to anyone
 	if s.GTSAuthorAccount == nil {
 		a := &gtsmodel.Account{}
-		if err := c.db.GetByID(s.AccountID, a); err != nil {
-			return nil, fmt.Errorf("StatusToAS: error retrieving author account from db: %s", err)
+		if err := c.db.GetByID(ctx, s.AccountID, a); err != nil {
+			return fmt.Errorf("StatusToAS: error retrieving author account from db: %s", err)
 		}
 		s.GTSAuthorAccount = a
 	}
 
-	// create the Note!
-	status := streams.NewActivityStreamsNote()
-
 	// id
 	statusURI, err := url.Parse(s.URI)
 	if err != nil {
-		return nil, fmt.Errorf("StatusToAS: error parsing url %s: %s", s.URI, err)
+		return fmt.Errorf("StatusToAS: error parsing url %s: %s", s.URI, err)
 	}
 	statusIDProp := streams.NewJSONLDIdProperty()
 	statusIDProp.SetIRI(statusURI)
@@ -362,14 +456,14 @@ func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, e
 		// fetch the replied status if we don't have it on hand already
 		if s.GTSReplyToStatus == nil {
 			rs := &gtsmodel.Status{}
-			if err := c.db.GetByID(s.InReplyToID, rs); err != nil {
-				return nil, fmt.Errorf("StatusToAS: error retrieving replied-to status from db: %s", err)
+			if err := c.db.GetByID(ctx, s.InReplyToID, rs); err != nil {
+				return fmt.Errorf("StatusToAS: error retrieving replied-to status from db: %s", err)
 			}
 			s.GTSReplyToStatus = rs
 		}
 		rURI, err := url.Parse(s.GTSReplyToStatus.URI)
 		if err != nil {
-			return nil, fmt.Errorf("StatusToAS: error parsing url %s: %s", s.GTSReplyToStatus.URI, err)
+			return fmt.Errorf("StatusToAS: error parsing url %s: %s", s.GTSReplyToStatus.URI, err)
 		}
 
 		inReplyToProp := streams.NewActivityStreamsInReplyToProperty()
@@ -386,7 +480,7 @@ func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, e
 	if s.URL != "" {
 		sURL, err := url.Parse(s.URL)
 		if err != nil {
-			return nil, fmt.Errorf("StatusToAS: error parsing url %s: %s", s.URL, err)
+			return fmt.Errorf("StatusToAS: error parsing url %s: %s", s.URL, err)
 		}
 
 		urlProp := streams.NewActivityStreamsUrlProperty()
@@ -397,7 +491,7 @@ func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, e
 	// attributedTo
 	authorAccountURI, err := url.Parse(s.GTSAuthorAccount.URI)
 	if err != nil {
-		return nil, fmt.Errorf("StatusToAS: error parsing url %s: %s", s.GTSAuthorAccount.URI, err)
+		return fmt.Errorf("StatusToAS: error parsing url %s: %s", s.GTSAuthorAccount.URI, err)
 	}
 	attributedToProp := streams.NewActivityStreamsAttributedToProperty()
 	attributedToProp.AppendIRI(authorAccountURI)
@@ -408,9 +502,9 @@ func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, e
 
 	// tag -- mentions
 	for _, m := range s.GTSMentions {
-		asMention, err := c.MentionToAS(m)
+		asMention, err := c.MentionToAS(ctx, m)
 		if err != nil {
-			return nil, fmt.Errorf("StatusToAS: error converting mention to AS mention: %s", err)
+			return fmt.Errorf("StatusToAS: error converting mention to AS mention: %s", err)
 		}
 		tagProp.AppendActivityStreamsMention(asMention)
 	}
@@ -426,12 +520,12 @@ func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, e
 	// parse out some URIs we need here
 	authorFollowersURI, err := url.Parse(s.GTSAuthorAccount.FollowersURI)
 	if err != nil {
-		return nil, fmt.Errorf("StatusToAS: error parsing url %s: %s", s.GTSAuthorAccount.FollowersURI, err)
+		return fmt.Errorf("StatusToAS: error parsing url %s: %s", s.GTSAuthorAccount.FollowersURI, err)
 	}
 
 	publicURI, err := url.Parse(asPublicURI)
 	if err != nil {
-		return nil, fmt.Errorf("StatusToAS: error parsing url %s: %s", asPublicURI, err)
+		return fmt.Errorf("StatusToAS: error parsing url %s: %s", asPublicURI, err)
 	}
 
 	// to and cc
@@ -443,7 +537,7 @@ func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, e
 		for _, m := range s.GTSMentions {
 			iri, err := url.Parse(m.GTSAccount.URI)
 			if err != nil {
-				return nil, fmt.Errorf("StatusToAS: error parsing uri %s: %s", m.GTSAccount.URI, err)
+				return fmt.Errorf("StatusToAS: error parsing uri %s: %s", m.GTSAccount.URI, err)
 			}
 			toProp.AppendIRI(iri)
 		}
@@ -455,7 +549,7 @@ func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, e
 		for _, m := range s.GTSMentions {
 			iri, err := url.Parse(m.GTSAccount.URI)
 			if err != nil {
-				return nil, fmt.Errorf("StatusToAS: error parsing uri %s: %s", m.GTSAccount.URI, err)
+				return fmt.Errorf("StatusToAS: error parsing uri %s: %s", m.GTSAccount.URI, err)
 			}
 			ccProp.AppendIRI(iri)
 		}
@@ -466,7 +560,7 @@ func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, e
 		for _, m := range s.GTSMentions {
 			iri, err := url.Parse(m.GTSAccount.URI)
 			if err != nil {
-				return nil, fmt.Errorf("StatusToAS: error parsing uri %s: %s", m.GTSAccount.URI, err)
+				return fmt.Errorf("StatusToAS: error parsing uri %s: %s", m.GTSAccount.URI, err)
 			}
 			ccProp.AppendIRI(iri)
 		}
@@ -477,7 +571,7 @@ func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, e
 		for _, m := range s.GTSMentions {
 			iri, err := url.Parse(m.GTSAccount.URI)
 			if err != nil {
-				return nil, fmt.Errorf("StatusToAS: error parsing uri %s: %s", m.GTSAccount.URI, err)
+				return fmt.Errorf("StatusToAS: error parsing uri %s: %s", m.GTSAccount.URI, err)
 			}
 			ccProp.AppendIRI(iri)
 		}
@@ -498,7 +592,7 @@ func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, e
 	for _, a := range s.GTSMediaAttachments {
 		doc, err := c.AttachmentToAS(a)
 		if err != nil {
-			return nil, fmt.Errorf("StatusToAS: error converting attachment: %s", err)
+			return fmt.Errorf("StatusToAS: error converting attachment: %s", err)
 		}
 		attachmentProp.AppendActivityStreamsDocument(doc)
 	}
@@ -507,7 +601,7 @@ func (c *converter) StatusToAS(s *gtsmodel.Status) (vocab.ActivityStreamsNote, e
 	// replies
 	// TODO
 
-	return status, nil
+	return nil
 }
 
 func (c *converter) FollowToAS(f *gtsmodel.Follow, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (vocab.ActivityStreamsFollow, error) {
@@ -556,10 +650,10 @@ func (c *converter) FollowToAS(f *gtsmodel.Follow, originAccount *gtsmodel.Accou
 	return follow, nil
 }
 
-func (c *converter) MentionToAS(m *gtsmodel.Mention) (vocab.ActivityStreamsMention, error) {
+func (c *converter) MentionToAS(ctx context.Context, m *gtsmodel.Mention) (vocab.ActivityStreamsMention, error) {
 	if m.GTSAccount == nil {
 		a := &gtsmodel.Account{}
-		if err := c.db.GetWhere([]db.Where{{Key: "target_account_id", Value: m.TargetAccountID}}, a); err != nil {
+		if err := c.db.GetWhere(ctx, []db.Where{{Key: "target_account_id", Value: m.TargetAccountID}}, a); err != nil {
 			return nil, fmt.Errorf("MentionToAS: error getting target account from db: %s", err)
 		}
 		m.GTSAccount = a
@@ -638,11 +732,11 @@ func (c *converter) AttachmentToAS(a *gtsmodel.MediaAttachment) (vocab.ActivityS
 	"type": "Like"
 	}
 */
-func (c *converter) FaveToAS(f *gtsmodel.StatusFave) (vocab.ActivityStreamsLike, error) {
+func (c *converter) FaveToAS(ctx context.Context, f *gtsmodel.StatusFave) (vocab.ActivityStreamsLike, error) {
 	// check if targetStatus is already pinned to this fave, and fetch it if not
 	if f.GTSStatus == nil {
 		s := &gtsmodel.Status{}
-		if err := c.db.GetByID(f.StatusID, s); err != nil {
+		if err := c.db.GetByID(ctx, f.StatusID, s); err != nil {
 			return nil, fmt.Errorf("FaveToAS: error fetching target status from database: %s", err)
 		}
 		f.GTSStatus = s
@@ -651,7 +745,7 @@ func (c *converter) FaveToAS(f *gtsmodel.StatusFave) (vocab.ActivityStreamsLike,
 	// check if the targetAccount is already pinned to this fave, and fetch it if not
 	if f.GTSTargetAccount == nil {
 		a := &gtsmodel.Account{}
-		if err := c.db.GetByID(f.TargetAccountID, a); err != nil {
+		if err := c.db.GetByID(ctx, f.TargetAccountID, a); err != nil {
 			return nil, fmt.Errorf("FaveToAS: error fetching target account from database: %s", err)
 		}
 		f.GTSTargetAccount = a
@@ -660,7 +754,7 @@ func (c *converter) FaveToAS(f *gtsmodel.StatusFave) (vocab.ActivityStreamsLike,
 	// check if the faving account is already pinned to this fave, and fetch it if not
 	if f.GTSFavingAccount == nil {
 		a := &gtsmodel.Account{}
-		if err := c.db.GetByID(f.AccountID, a); err != nil {
+		if err := c.db.GetByID(ctx, f.AccountID, a); err != nil {
 			return nil, fmt.Errorf("FaveToAS: error fetching faving account from database: %s", err)
 		}
 		f.GTSFavingAccount = a
@@ -708,11 +802,11 @@ func (c *converter) FaveToAS(f *gtsmodel.StatusFave) (vocab.ActivityStreamsLike,
 	return like, nil
 }
 
-func (c *converter) BoostToAS(boostWrapperStatus *gtsmodel.Status, boostingAccount *gtsmodel.Account, boostedAccount *gtsmodel.Account) (vocab.ActivityStreamsAnnounce, error) {
+func (c *converter) BoostToAS(ctx context.Context, boostWrapperStatus *gtsmodel.Status, boostingAccount *gtsmodel.Account, boostedAccount *gtsmodel.Account) (vocab.ActivityStreamsAnnounce, error) {
 	// the boosted status is probably pinned to the boostWrapperStatus but double check to make sure
 	if boostWrapperStatus.GTSBoostedStatus == nil {
 		b := &gtsmodel.Status{}
-		if err := c.db.GetByID(boostWrapperStatus.BoostOfID, b); err != nil {
+		if err := c.db.GetByID(ctx, boostWrapperStatus.BoostOfID, b); err != nil {
 			return nil, fmt.Errorf("BoostToAS: error getting status with ID %s from the db: %s", boostWrapperStatus.BoostOfID, err)
 		}
 		boostWrapperStatus.GTSBoostedStatus = b
@@ -792,10 +886,10 @@ func (c *converter) BoostToAS(boostWrapperStatus *gtsmodel.Status, boostingAccou
 		"type":"Block"
 	}
 */
-func (c *converter) BlockToAS(b *gtsmodel.Block) (vocab.ActivityStreamsBlock, error) {
+func (c *converter) BlockToAS(ctx context.Context, b *gtsmodel.Block) (vocab.ActivityStreamsBlock, error) {
 	if b.Account == nil {
 		a := &gtsmodel.Account{}
-		if err := c.db.GetByID(b.AccountID, a); err != nil {
+		if err := c.db.GetByID(ctx, b.AccountID, a); err != nil {
 			return nil, fmt.Errorf("BlockToAS: error getting block account from database: %s", err)
 		}
 		b.Account = a
@@ -803,7 +897,7 @@ func (c *converter) BlockToAS(b *gtsmodel.Block) (vocab.ActivityStreamsBlock, er
 
 	if b.TargetAccount == nil {
 		a := &gtsmodel.Account{}
-		if err := c.db.GetByID(b.TargetAccountID, a); err != nil {
+		if err := c.db.GetByID(ctx, b.TargetAccountID, a); err != nil {
 			return nil, fmt.Errorf("BlockToAS: error getting block target account from database: %s", err)
 		}
 		b.TargetAccount = a
@@ -850,3 +944,207 @@ func (c *converter) BlockToAS(b *gtsmodel.Block) (vocab.ActivityStreamsBlock, er
 
 	return block, nil
 }
+
+/*
+	We want to end up with something like this:
+
+	{
+	"@context": "https://www.w3.org/ns/activitystreams",
+	"attributedTo": "https://example.org/users/some_voter",
+	"id": "https://example.org/users/some_voter#votes/01FF8K1S8V7KJ4F9M5CJ3G5TNB",
+	"inReplyTo": "https://example.net/users/poll_owner/statuses/01FF8K1MYKPH7DJYBM5EFCX8WT",
+	"name": "the title of the chosen option",
+	"to": "https://example.net/users/poll_owner",
+	"type": "Note"
+	}
+*/
+func (c *converter) PollVoteToAS(ctx context.Context, vote *gtsmodel.PollVote) (vocab.ActivityStreamsNote, error) {
+	poll := &gtsmodel.Poll{}
+	if err := c.db.GetByID(ctx, vote.PollID, poll); err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error getting poll from db: %s", err)
+	}
+	if vote.Choice < 0 || vote.Choice >= len(poll.Options) {
+		return nil, fmt.Errorf("PollVoteToAS: poll %s has no option %d", poll.ID, vote.Choice)
+	}
+
+	status := &gtsmodel.Status{}
+	if err := c.db.GetByID(ctx, poll.StatusID, status); err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error getting status from db: %s", err)
+	}
+
+	voter := &gtsmodel.Account{}
+	if err := c.db.GetByID(ctx, vote.AccountID, voter); err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error getting voter account from db: %s", err)
+	}
+
+	pollOwner := &gtsmodel.Account{}
+	if err := c.db.GetByID(ctx, poll.AccountID, pollOwner); err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error getting poll owner account from db: %s", err)
+	}
+
+	note := streams.NewActivityStreamsNote()
+
+	// set the id
+	idIRI, err := url.Parse(vote.URI)
+	if err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error parsing uri %s: %s", vote.URI, err)
+	}
+	idProp := streams.NewJSONLDIdProperty()
+	idProp.SetIRI(idIRI)
+	note.SetJSONLDId(idProp)
+
+	// the name is the title of the chosen option
+	nameProp := streams.NewActivityStreamsNameProperty()
+	nameProp.AppendXMLSchemaString(poll.Options[vote.Choice])
+	note.SetActivityStreamsName(nameProp)
+
+	// the vote replies to the poll
+	inReplyToIRI, err := url.Parse(status.URI)
+	if err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error parsing uri %s: %s", status.URI, err)
+	}
+	inReplyToProp := streams.NewActivityStreamsInReplyToProperty()
+	inReplyToProp.AppendIRI(inReplyToIRI)
+	note.SetActivityStreamsInReplyTo(inReplyToProp)
+
+	// the vote is attributed to the voter
+	attributedToIRI, err := url.Parse(voter.URI)
+	if err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error parsing uri %s: %s", voter.URI, err)
+	}
+	attributedToProp := streams.NewActivityStreamsAttributedToProperty()
+	attributedToProp.AppendIRI(attributedToIRI)
+	note.SetActivityStreamsAttributedTo(attributedToProp)
+
+	// the vote is only sent to the owner of the poll
+	toIRI, err := url.Parse(pollOwner.URI)
+	if err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error parsing uri %s: %s", pollOwner.URI, err)
+	}
+	toProp := streams.NewActivityStreamsToProperty()
+	toProp.AppendIRI(toIRI)
+	note.SetActivityStreamsTo(toProp)
+
+	return note, nil
+}
+
+/*
+	we want to end up with something like this:
+
+	{
+	"@context": "https://www.w3.org/ns/activitystreams",
+	"id": "https://example.org/users/some_user/collections/featured",
+	"orderedItems": [
+		{
+			"id": "https://example.org/users/some_user/statuses/01FCTA44PW9H1TB328S9AQXKDS",
+			"type": "Note",
+			...
+		}
+	],
+	"totalItems": 1,
+	"type": "OrderedCollection"
+	}
+*/
+func (c *converter) StatusesToASFeaturedCollection(ctx context.Context, a *gtsmodel.Account, statuses []*gtsmodel.Status) (vocab.ActivityStreamsOrderedCollection, error) {
+	collection := streams.NewActivityStreamsOrderedCollection()
+
+	// set the id
+	idIRI, err := url.Parse(a.FeaturedCollectionURI)
+	if err != nil {
+		return nil, fmt.Errorf("StatusesToASFeaturedCollection: error parsing uri %s: %s", a.FeaturedCollectionURI, err)
+	}
+	idProp := streams.NewJSONLDIdProperty()
+	idProp.SetIRI(idIRI)
+	collection.SetJSONLDId(idProp)
+
+	// the pinned statuses themselves are included in full, so that remote instances don't have to fetch each one
+	itemsProp := streams.NewActivityStreamsOrderedItemsProperty()
+	for _, s := range statuses {
+		if s.ActivityStreamsType == gtsmodel.ActivityStreamsQuestion {
+			question, err := c.PollStatusToAS(ctx, s)
+			if err != nil {
+				return nil, fmt.Errorf("StatusesToASFeaturedCollection: error converting status %s: %s", s.ID, err)
+			}
+			itemsProp.AppendActivityStreamsQuestion(question)
+			continue
+		}
+
+		note, err := c.StatusToAS(ctx, s)
+		if err != nil {
+			return nil, fmt.Errorf("StatusesToASFeaturedCollection: error converting status %s: %s", s.ID, err)
+		}
+		itemsProp.AppendActivityStreamsNote(note)
+	}
+	collection.SetActivityStreamsOrderedItems(itemsProp)
+
+	totalItemsProp := streams.NewActivityStreamsTotalItemsProperty()
+	totalItemsProp.Set(len(statuses))
+	collection.SetActivityStreamsTotalItems(totalItemsProp)
+
+	return collection, nil
+}
+
+func (c *converter) ReportToASFlag(ctx context.Context, r *gtsmodel.Report, instanceAccount *gtsmodel.Account) (vocab.ActivityStreamsFlag, error) {
+	targetAccount := &gtsmodel.Account{}
+	if err := c.db.GetByID(ctx, r.TargetAccountID, targetAccount); err != nil {
+		return nil, fmt.Errorf("ReportToASFlag: error getting report target account from database: %s", err)
+	}
+
+	flag := streams.NewActivityStreamsFlag()
+
+	// set the ID property to the report's URI
+	idProp := streams.NewJSONLDIdProperty()
+	idIRI, err := url.Parse(r.URI)
+	if err != nil {
+		return nil, fmt.Errorf("ReportToASFlag: error parsing uri %s: %s", r.URI, err)
+	}
+	idProp.Set(idIRI)
+	flag.SetJSONLDId(idProp)
+
+	// set the actor property to the instance account's URI
+	actorProp := streams.NewActivityStreamsActorProperty()
+	actorIRI, err := url.Parse(instanceAccount.URI)
+	if err != nil {
+		return nil, fmt.Errorf("ReportToASFlag: error parsing uri %s: %s", instanceAccount.URI, err)
+	}
+	actorProp.AppendIRI(actorIRI)
+	flag.SetActivityStreamsActor(actorProp)
+
+	// set the object property to the target account's URI, followed by the URIs of any reported statuses
+	objectProp := streams.NewActivityStreamsObjectProperty()
+	targetIRI, err := url.Parse(targetAccount.URI)
+	if err != nil {
+		return nil, fmt.Errorf("ReportToASFlag: error parsing uri %s: %s", targetAccount.URI, err)
+	}
+	objectProp.AppendIRI(targetIRI)
+	for _, sID := range r.StatusIDs {
+		s := &gtsmodel.Status{}
+		if err := c.db.GetByID(ctx, sID, s); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				// status has been deleted in the meantime
+				continue
+			}
+			return nil, fmt.Errorf("ReportToASFlag: error getting status %s from database: %s", sID, err)
+		}
+		statusIRI, err := url.Parse(s.URI)
+		if err != nil {
+			return nil, fmt.Errorf("ReportToASFlag: error parsing uri %s: %s", s.URI, err)
+		}
+		objectProp.AppendIRI(statusIRI)
+	}
+	flag.SetActivityStreamsObject(objectProp)
+
+	// set the content property to the comment of the report
+	if r.Comment != "" {
+		contentProp := streams.NewActivityStreamsContentProperty()
+		contentProp.AppendXMLSchemaString(r.Comment)
+		flag.SetActivityStreamsContent(contentProp)
+	}
+
+	// set the TO property to the target account's IRI, so that the flag gets delivered to its instance
+	toProp := streams.NewActivityStreamsToProperty()
+	toProp.AppendIRI(targetIRI)
+	flag.SetActivityStreamsTo(toProp)
+
+	return flag, nil
+}
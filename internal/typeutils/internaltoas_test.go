@@ -19,6 +19,7 @@
 package typeutils_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -27,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/testrig"
 )
@@ -43,7 +45,7 @@ func (suite *InternalToASTestSuite) SetupSuite() {
 	suite.log = testrig.NewTestLog()
 	suite.accounts = testrig.NewTestAccounts()
 	suite.people = testrig.NewTestFediPeople()
-	suite.typeconverter = typeutils.NewConverter(suite.config, suite.db)
+	suite.typeconverter = typeutils.NewConverter(suite.config, suite.db, testrig.NewTestStorage())
 }
 
 func (suite *InternalToASTestSuite) SetupTest() {
@@ -58,7 +60,7 @@ func (suite *InternalToASTestSuite) TearDownTest() {
 func (suite *InternalToASTestSuite) TestAccountToAS() {
 	testAccount := suite.accounts["local_account_1"] // take zork for this test
 
-	asPerson, err := suite.typeconverter.AccountToAS(testAccount)
+	asPerson, err := suite.typeconverter.AccountToAS(context.Background(), testAccount)
 	assert.NoError(suite.T(), err)
 
 	ser, err := streams.Serialize(asPerson)
@@ -71,6 +73,61 @@ func (suite *InternalToASTestSuite) TestAccountToAS() {
 	// TODO: write assertions here, rn we're just eyeballing the output
 }
 
+func (suite *InternalToASTestSuite) TestStatusesToASFeaturedCollection() {
+	testAccount := suite.accounts["local_account_1"]
+	testStatus := testrig.NewTestStatuses()["local_account_1_status_1"]
+
+	collection, err := suite.typeconverter.StatusesToASFeaturedCollection(context.Background(), testAccount, []*gtsmodel.Status{testStatus})
+	suite.NoError(err)
+
+	ser, err := streams.Serialize(collection)
+	suite.NoError(err)
+
+	suite.Equal("OrderedCollection", ser["type"])
+	suite.Equal(testAccount.FeaturedCollectionURI, ser["id"])
+	suite.EqualValues(1, ser["totalItems"])
+
+	// a single item is serialized as an object rather than an array
+	item, ok := ser["orderedItems"].(map[string]interface{})
+	suite.True(ok)
+	suite.Equal("Note", item["type"])
+	suite.Equal(testStatus.URI, item["id"])
+}
+
+func (suite *InternalToASTestSuite) TestReportToASFlag() {
+	ctx := context.Background()
+	reportingAccount := suite.accounts["remote_account_1"]
+	targetAccount := suite.accounts["local_account_1"]
+	testStatus := testrig.NewTestStatuses()["local_account_1_status_1"]
+
+	report := &gtsmodel.Report{
+		URI:             "http://fossbros-anonymous.io/users/foss_satan/flags/01FGZ1QJ3W4VWDWH6H5Y0YFK7Z",
+		AccountID:       reportingAccount.ID,
+		TargetAccountID: targetAccount.ID,
+		StatusIDs:       []string{testStatus.ID},
+		Comment:         "this is spam",
+	}
+
+	flag, err := suite.typeconverter.ReportToASFlag(ctx, report, reportingAccount)
+	suite.NoError(err)
+
+	ser, err := streams.Serialize(flag)
+	suite.NoError(err)
+	suite.Equal("Flag", ser["type"])
+	suite.Equal(reportingAccount.URI, ser["actor"])
+	suite.Equal([]interface{}{targetAccount.URI, testStatus.URI}, ser["object"])
+	suite.Equal("this is spam", ser["content"])
+
+	// converting the flag back should give the same report
+	parsed, err := suite.typeconverter.ASFlagToReport(ctx, flag)
+	suite.NoError(err)
+	suite.Equal(report.URI, parsed.URI)
+	suite.Equal(reportingAccount.ID, parsed.AccountID)
+	suite.Equal(targetAccount.ID, parsed.TargetAccountID)
+	suite.Equal([]string{testStatus.ID}, parsed.StatusIDs)
+	suite.Equal("this is spam", parsed.Comment)
+}
+
 func TestInternalToASTestSuite(t *testing.T) {
 	suite.Run(t, new(InternalToASTestSuite))
 }
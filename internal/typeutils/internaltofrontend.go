@@ -19,7 +19,10 @@
 package typeutils
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -28,9 +31,9 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (c *converter) AccountToMastoSensitive(a *gtsmodel.Account) (*model.Account, error) {
+func (c *converter) AccountToMastoSensitive(ctx context.Context, a *gtsmodel.Account) (*model.Account, error) {
 	// we can build this sensitive account easily by first getting the public account....
-	mastoAccount, err := c.AccountToMastoPublic(a)
+	mastoAccount, err := c.AccountToMastoPublic(ctx, a)
 	if err != nil {
 		return nil, err
 	}
@@ -39,7 +42,7 @@ func (c *converter) AccountToMastoSensitive(a *gtsmodel.Account) (*model.Account
 
 	// check pending follow requests aimed at this account
 	fr := []gtsmodel.FollowRequest{}
-	if err := c.db.GetFollowRequestsForAccountID(a.ID, &fr); err != nil {
+	if err := c.db.GetFollowRequestsForAccountID(ctx, a.ID, &fr); err != nil {
 		if _, ok := err.(db.ErrNoEntries); !ok {
 			return nil, fmt.Errorf("error getting follow requests: %s", err)
 		}
@@ -61,10 +64,10 @@ func (c *converter) AccountToMastoSensitive(a *gtsmodel.Account) (*model.Account
 	return mastoAccount, nil
 }
 
-func (c *converter) AccountToMastoPublic(a *gtsmodel.Account) (*model.Account, error) {
+func (c *converter) AccountToMastoPublic(ctx context.Context, a *gtsmodel.Account) (*model.Account, error) {
 	// count followers
 	followers := []gtsmodel.Follow{}
-	if err := c.db.GetFollowersByAccountID(a.ID, &followers, false); err != nil {
+	if err := c.db.GetFollowersByAccountID(ctx, a.ID, &followers, false); err != nil {
 		if _, ok := err.(db.ErrNoEntries); !ok {
 			return nil, fmt.Errorf("error getting followers: %s", err)
 		}
@@ -76,7 +79,7 @@ func (c *converter) AccountToMastoPublic(a *gtsmodel.Account) (*model.Account, e
 
 	// count following
 	following := []gtsmodel.Follow{}
-	if err := c.db.GetFollowingByAccountID(a.ID, &following); err != nil {
+	if err := c.db.GetFollowingByAccountID(ctx, a.ID, &following); err != nil {
 		if _, ok := err.(db.ErrNoEntries); !ok {
 			return nil, fmt.Errorf("error getting following: %s", err)
 		}
@@ -87,7 +90,7 @@ func (c *converter) AccountToMastoPublic(a *gtsmodel.Account) (*model.Account, e
 	}
 
 	// count statuses
-	statusesCount, err := c.db.CountStatusesByAccountID(a.ID)
+	statusesCount, err := c.db.CountStatusesByAccountID(ctx, a.ID)
 	if err != nil {
 		if _, ok := err.(db.ErrNoEntries); !ok {
 			return nil, fmt.Errorf("error getting last statuses: %s", err)
@@ -96,7 +99,7 @@ func (c *converter) AccountToMastoPublic(a *gtsmodel.Account) (*model.Account, e
 
 	// check when the last status was
 	lastStatus := &gtsmodel.Status{}
-	if err := c.db.GetLastStatusForAccountID(a.ID, lastStatus); err != nil {
+	if err := c.db.GetLastStatusForAccountID(ctx, a.ID, lastStatus); err != nil {
 		if _, ok := err.(db.ErrNoEntries); !ok {
 			return nil, fmt.Errorf("error getting last status: %s", err)
 		}
@@ -108,22 +111,34 @@ func (c *converter) AccountToMastoPublic(a *gtsmodel.Account) (*model.Account, e
 
 	// build the avatar and header URLs
 	avi := &gtsmodel.MediaAttachment{}
-	if err := c.db.GetAvatarForAccountID(avi, a.ID); err != nil {
+	if err := c.db.GetAvatarForAccountID(ctx, avi, a.ID); err != nil {
 		if _, ok := err.(db.ErrNoEntries); !ok {
 			return nil, fmt.Errorf("error getting avatar: %s", err)
 		}
 	}
-	aviURL := avi.URL
-	aviURLStatic := avi.Thumbnail.URL
+	aviURL, err := c.attachmentURL(ctx, avi, avi.File.Path, avi.URL)
+	if err != nil {
+		return nil, err
+	}
+	aviURLStatic, err := c.attachmentURL(ctx, avi, avi.Thumbnail.Path, avi.Thumbnail.URL)
+	if err != nil {
+		return nil, err
+	}
 
 	header := &gtsmodel.MediaAttachment{}
-	if err := c.db.GetHeaderForAccountID(header, a.ID); err != nil {
+	if err := c.db.GetHeaderForAccountID(ctx, header, a.ID); err != nil {
 		if _, ok := err.(db.ErrNoEntries); !ok {
 			return nil, fmt.Errorf("error getting header: %s", err)
 		}
 	}
-	headerURL := header.URL
-	headerURLStatic := header.Thumbnail.URL
+	headerURL, err := c.attachmentURL(ctx, header, header.File.Path, header.URL)
+	if err != nil {
+		return nil, err
+	}
+	headerURLStatic, err := c.attachmentURL(ctx, header, header.Thumbnail.Path, header.Thumbnail.URL)
+	if err != nil {
+		return nil, err
+	}
 
 	// get the fields set on this account
 	fields := []model.Field{}
@@ -225,40 +240,88 @@ func (c *converter) AppToMastoPublic(a *gtsmodel.Application) (*model.Applicatio
 	}, nil
 }
 
-func (c *converter) AttachmentToMasto(a *gtsmodel.MediaAttachment) (model.Attachment, error) {
+func (c *converter) AttachmentToMasto(ctx context.Context, a *gtsmodel.MediaAttachment) (model.Attachment, error) {
+	url, err := c.attachmentURL(ctx, a, a.File.Path, a.URL)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	previewURL, err := c.attachmentURL(ctx, a, a.Thumbnail.Path, a.Thumbnail.URL)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	meta := model.MediaMeta{
+		Original: model.MediaDimensions{
+			Width:  a.FileMeta.Original.Width,
+			Height: a.FileMeta.Original.Height,
+			Size:   fmt.Sprintf("%dx%d", a.FileMeta.Original.Width, a.FileMeta.Original.Height),
+			Aspect: float32(a.FileMeta.Original.Aspect),
+		},
+		Small: model.MediaDimensions{
+			Width:  a.FileMeta.Small.Width,
+			Height: a.FileMeta.Small.Height,
+			Size:   fmt.Sprintf("%dx%d", a.FileMeta.Small.Width, a.FileMeta.Small.Height),
+			Aspect: float32(a.FileMeta.Small.Aspect),
+		},
+		Focus: model.MediaFocus{
+			X: a.FileMeta.Focus.X,
+			Y: a.FileMeta.Focus.Y,
+		},
+	}
+
+	// video and audio have some extra info about the length and quality of the media
+	if a.FileMeta.Original.Duration != nil {
+		duration := *a.FileMeta.Original.Duration
+		meta.Duration = duration
+		meta.Length = mediaLength(duration)
+		meta.Original.Duration = duration
+	}
+	if a.FileMeta.Original.Framerate != nil {
+		framerate := *a.FileMeta.Original.Framerate
+		meta.FPS = uint16(math.Round(float64(framerate)))
+		meta.Original.FrameRate = mediaFrameRate(framerate)
+	}
+	if a.FileMeta.Original.Bitrate != nil {
+		meta.Original.Bitrate = int(*a.FileMeta.Original.Bitrate)
+	}
+	if a.Type == gtsmodel.FileTypeVideo {
+		meta.Width = meta.Original.Width
+		meta.Height = meta.Original.Height
+		meta.Size = meta.Original.Size
+		meta.Aspect = meta.Original.Aspect
+	}
+
 	return model.Attachment{
 		ID:               a.ID,
 		Type:             strings.ToLower(string(a.Type)),
-		URL:              a.URL,
-		PreviewURL:       a.Thumbnail.URL,
+		URL:              url,
+		PreviewURL:       previewURL,
 		RemoteURL:        a.RemoteURL,
 		PreviewRemoteURL: a.Thumbnail.RemoteURL,
-		Meta: model.MediaMeta{
-			Original: model.MediaDimensions{
-				Width:  a.FileMeta.Original.Width,
-				Height: a.FileMeta.Original.Height,
-				Size:   fmt.Sprintf("%dx%d", a.FileMeta.Original.Width, a.FileMeta.Original.Height),
-				Aspect: float32(a.FileMeta.Original.Aspect),
-			},
-			Small: model.MediaDimensions{
-				Width:  a.FileMeta.Small.Width,
-				Height: a.FileMeta.Small.Height,
-				Size:   fmt.Sprintf("%dx%d", a.FileMeta.Small.Width, a.FileMeta.Small.Height),
-				Aspect: float32(a.FileMeta.Small.Aspect),
-			},
-			Focus: model.MediaFocus{
-				X: a.FileMeta.Focus.X,
-				Y: a.FileMeta.Focus.Y,
-			},
-		},
-		Description: a.Description,
-		Blurhash:    a.Blurhash,
+		Meta:             meta,
+		Description:      a.Description,
+		Blurhash:         a.Blurhash,
 	}, nil
 }
 
-func (c *converter) MentionToMasto(m *gtsmodel.Mention) (model.Mention, error) {
+// mediaLength formats a duration in seconds as a length like 0:01:23.45, the way mastodon does.
+func mediaLength(duration float32) string {
+	hundredths := int(math.Round(float64(duration) * 100))
+	return fmt.Sprintf("%d:%02d:%02d.%02d", hundredths/360000, hundredths/6000%60, hundredths/100%60, hundredths%100)
+}
+
+// mediaFrameRate formats a framerate as a fraction like 30/1 or 29970/1000, the way mastodon does.
+func mediaFrameRate(framerate float32) string {
+	if framerate == float32(math.Round(float64(framerate))) {
+		return fmt.Sprintf("%d/1", int(framerate))
+	}
+	return fmt.Sprintf("%d/1000", int(math.Round(float64(framerate)*1000)))
+}
+
+func (c *converter) MentionToMasto(ctx context.Context, m *gtsmodel.Mention) (model.Mention, error) {
 	target := &gtsmodel.Account{}
-	if err := c.db.GetByID(m.TargetAccountID, target); err != nil {
+	if err := c.db.GetByID(ctx, m.TargetAccountID, target); err != nil {
 		return model.Mention{}, err
 	}
 
@@ -283,10 +346,20 @@ func (c *converter) MentionToMasto(m *gtsmodel.Mention) (model.Mention, error) {
 }
 
 func (c *converter) EmojiToMasto(e *gtsmodel.Emoji) (model.Emoji, error) {
+	url, err := c.servedURL(e.ImagePath, e.ImageURL)
+	if err != nil {
+		return model.Emoji{}, err
+	}
+
+	staticURL, err := c.servedURL(e.ImageStaticPath, e.ImageStaticURL)
+	if err != nil {
+		return model.Emoji{}, err
+	}
+
 	return model.Emoji{
 		Shortcode:       e.Shortcode,
-		URL:             e.ImageURL,
-		StaticURL:       e.ImageStaticURL,
+		URL:             url,
+		StaticURL:       staticURL,
 		VisibleInPicker: e.VisibleInPicker,
 		Category:        e.CategoryID,
 	}, nil
@@ -301,18 +374,18 @@ func (c *converter) TagToMasto(t *gtsmodel.Tag) (model.Tag, error) {
 	}, nil
 }
 
-func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmodel.Account) (*model.Status, error) {
-	repliesCount, err := c.db.GetReplyCountForStatus(s)
+func (c *converter) StatusToMasto(ctx context.Context, s *gtsmodel.Status, requestingAccount *gtsmodel.Account) (*model.Status, error) {
+	repliesCount, err := c.db.GetReplyCountForStatus(ctx, s)
 	if err != nil {
 		return nil, fmt.Errorf("error counting replies: %s", err)
 	}
 
-	reblogsCount, err := c.db.GetReblogCountForStatus(s)
+	reblogsCount, err := c.db.GetReblogCountForStatus(ctx, s)
 	if err != nil {
 		return nil, fmt.Errorf("error counting reblogs: %s", err)
 	}
 
-	favesCount, err := c.db.GetFaveCountForStatus(s)
+	favesCount, err := c.db.GetFaveCountForStatus(ctx, s)
 	if err != nil {
 		return nil, fmt.Errorf("error counting faves: %s", err)
 	}
@@ -323,7 +396,7 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 		if s.GTSBoostedStatus == nil {
 			// it's not set so fetch it from the db
 			bs := &gtsmodel.Status{}
-			if err := c.db.GetByID(s.BoostOfID, bs); err != nil {
+			if err := c.db.GetByID(ctx, s.BoostOfID, bs); err != nil {
 				return nil, fmt.Errorf("error getting boosted status with id %s: %s", s.BoostOfID, err)
 			}
 			s.GTSBoostedStatus = bs
@@ -333,14 +406,14 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 		if s.GTSBoostedAccount == nil {
 			// it's not set so fetch it from the db
 			ba := &gtsmodel.Account{}
-			if err := c.db.GetByID(s.GTSBoostedStatus.AccountID, ba); err != nil {
+			if err := c.db.GetByID(ctx, s.GTSBoostedStatus.AccountID, ba); err != nil {
 				return nil, fmt.Errorf("error getting boosted account %s from status with id %s: %s", s.GTSBoostedStatus.AccountID, s.BoostOfID, err)
 			}
 			s.GTSBoostedAccount = ba
 			s.GTSBoostedStatus.GTSAuthorAccount = ba
 		}
 
-		mastoRebloggedStatus, err = c.StatusToMasto(s.GTSBoostedStatus, requestingAccount)
+		mastoRebloggedStatus, err = c.StatusToMasto(ctx, s.GTSBoostedStatus, requestingAccount)
 		if err != nil {
 			return nil, fmt.Errorf("error converting boosted status to mastotype: %s", err)
 		}
@@ -349,7 +422,7 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 	var mastoApplication *model.Application
 	if s.CreatedWithApplicationID != "" {
 		gtsApplication := &gtsmodel.Application{}
-		if err := c.db.GetByID(s.CreatedWithApplicationID, gtsApplication); err != nil {
+		if err := c.db.GetByID(ctx, s.CreatedWithApplicationID, gtsApplication); err != nil {
 			return nil, fmt.Errorf("error fetching application used to create status: %s", err)
 		}
 		mastoApplication, err = c.AppToMastoPublic(gtsApplication)
@@ -360,13 +433,13 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 
 	if s.GTSAuthorAccount == nil {
 		a := &gtsmodel.Account{}
-		if err := c.db.GetByID(s.AccountID, a); err != nil {
+		if err := c.db.GetByID(ctx, s.AccountID, a); err != nil {
 			return nil, fmt.Errorf("error getting status author: %s", err)
 		}
 		s.GTSAuthorAccount = a
 	}
 
-	mastoAuthorAccount, err := c.AccountToMastoPublic(s.GTSAuthorAccount)
+	mastoAuthorAccount, err := c.AccountToMastoPublic(ctx, s.GTSAuthorAccount)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing account of status author: %s", err)
 	}
@@ -376,7 +449,7 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 	// if so, we can directly convert the gts attachments into masto ones
 	if s.GTSMediaAttachments != nil {
 		for _, gtsAttachment := range s.GTSMediaAttachments {
-			mastoAttachment, err := c.AttachmentToMasto(gtsAttachment)
+			mastoAttachment, err := c.AttachmentToMasto(ctx, gtsAttachment)
 			if err != nil {
 				return nil, fmt.Errorf("error converting attachment with id %s: %s", gtsAttachment.ID, err)
 			}
@@ -387,10 +460,10 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 	} else {
 		for _, a := range s.Attachments {
 			gtsAttachment := &gtsmodel.MediaAttachment{}
-			if err := c.db.GetByID(a, gtsAttachment); err != nil {
+			if err := c.db.GetByID(ctx, a, gtsAttachment); err != nil {
 				return nil, fmt.Errorf("error getting attachment with id %s: %s", a, err)
 			}
-			mastoAttachment, err := c.AttachmentToMasto(gtsAttachment)
+			mastoAttachment, err := c.AttachmentToMasto(ctx, gtsAttachment)
 			if err != nil {
 				return nil, fmt.Errorf("error converting attachment with id %s: %s", a, err)
 			}
@@ -403,7 +476,7 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 	// if so, we can directly convert the gts mentions into masto ones
 	if s.GTSMentions != nil {
 		for _, gtsMention := range s.GTSMentions {
-			mastoMention, err := c.MentionToMasto(gtsMention)
+			mastoMention, err := c.MentionToMasto(ctx, gtsMention)
 			if err != nil {
 				return nil, fmt.Errorf("error converting mention with id %s: %s", gtsMention.ID, err)
 			}
@@ -414,10 +487,10 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 	} else {
 		for _, m := range s.Mentions {
 			gtsMention := &gtsmodel.Mention{}
-			if err := c.db.GetByID(m, gtsMention); err != nil {
+			if err := c.db.GetByID(ctx, m, gtsMention); err != nil {
 				return nil, fmt.Errorf("error getting mention with id %s: %s", m, err)
 			}
-			mastoMention, err := c.MentionToMasto(gtsMention)
+			mastoMention, err := c.MentionToMasto(ctx, gtsMention)
 			if err != nil {
 				return nil, fmt.Errorf("error converting mention with id %s: %s", gtsMention.ID, err)
 			}
@@ -441,7 +514,7 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 	} else {
 		for _, t := range s.Tags {
 			gtsTag := &gtsmodel.Tag{}
-			if err := c.db.GetByID(t, gtsTag); err != nil {
+			if err := c.db.GetByID(ctx, t, gtsTag); err != nil {
 				return nil, fmt.Errorf("error getting tag with id %s: %s", t, err)
 			}
 			mastoTag, err := c.TagToMasto(gtsTag)
@@ -468,7 +541,7 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 	} else {
 		for _, e := range s.Emojis {
 			gtsEmoji := &gtsmodel.Emoji{}
-			if err := c.db.GetByID(e, gtsEmoji); err != nil {
+			if err := c.db.GetByID(ctx, e, gtsEmoji); err != nil {
 				return nil, fmt.Errorf("error getting emoji with id %s: %s", e, err)
 			}
 			mastoEmoji, err := c.EmojiToMasto(gtsEmoji)
@@ -480,10 +553,31 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 	}
 
 	var mastoCard *model.Card
+
 	var mastoPoll *model.Poll
+	if s.ActivityStreamsType == gtsmodel.ActivityStreamsQuestion {
+		// the poll might have been set on this struct already so check first before doing db calls
+		if s.GTSPoll == nil {
+			p := &gtsmodel.Poll{}
+			if err := c.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: s.ID}}, p); err != nil {
+				if _, ok := err.(db.ErrNoEntries); !ok {
+					return nil, fmt.Errorf("error getting poll for status %s: %s", s.ID, err)
+				}
+				p = nil
+			}
+			s.GTSPoll = p
+		}
+
+		if s.GTSPoll != nil {
+			mastoPoll, err = c.PollToMasto(ctx, s.GTSPoll, requestingAccount)
+			if err != nil {
+				return nil, fmt.Errorf("error converting poll of status %s: %s", s.ID, err)
+			}
+		}
+	}
 
 	statusInteractions := &statusInteractions{}
-	si, err := c.interactionsWithStatusForAccount(s, requestingAccount)
+	si, err := c.interactionsWithStatusForAccount(ctx, s, requestingAccount)
 	if err == nil {
 		statusInteractions = si
 	}
@@ -516,7 +610,7 @@ func (c *converter) StatusToMasto(s *gtsmodel.Status, requestingAccount *gtsmode
 		Tags:               mastoTags,
 		Emojis:             mastoEmojis,
 		Card:               mastoCard, // TODO: implement cards
-		Poll:               mastoPoll, // TODO: implement polls
+		Poll:               mastoPoll,
 		Text:               s.Text,
 	}, nil
 }
@@ -536,7 +630,7 @@ func (c *converter) VisToMasto(m gtsmodel.Visibility) model.Visibility {
 	return ""
 }
 
-func (c *converter) InstanceToMasto(i *gtsmodel.Instance) (*model.Instance, error) {
+func (c *converter) InstanceToMasto(ctx context.Context, i *gtsmodel.Instance) (*model.Instance, error) {
 	mi := &model.Instance{
 		URI:              i.URI,
 		Title:            i.Title,
@@ -554,17 +648,17 @@ func (c *converter) InstanceToMasto(i *gtsmodel.Instance) (*model.Instance, erro
 		statusCountKey := "status_count"
 		domainCountKey := "domain_count"
 
-		userCount, err := c.db.GetUserCountForInstance(c.config.Host)
+		userCount, err := c.db.GetUserCountForInstance(ctx, c.config.Host)
 		if err == nil {
 			mi.Stats[userCountKey] = userCount
 		}
 
-		statusCount, err := c.db.GetStatusCountForInstance(c.config.Host)
+		statusCount, err := c.db.GetStatusCountForInstance(ctx, c.config.Host)
 		if err == nil {
 			mi.Stats[statusCountKey] = statusCount
 		}
 
-		domainCount, err := c.db.GetDomainCountForInstance(c.config.Host)
+		domainCount, err := c.db.GetDomainCountForInstance(ctx, c.config.Host)
 		if err == nil {
 			mi.Stats[domainCountKey] = domainCount
 		}
@@ -581,10 +675,10 @@ func (c *converter) InstanceToMasto(i *gtsmodel.Instance) (*model.Instance, erro
 
 	// get the instance account if it exists and just skip if it doesn't
 	ia := &gtsmodel.Account{}
-	if err := c.db.GetWhere([]db.Where{{Key: "username", Value: i.Domain}}, ia); err == nil {
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "username", Value: i.Domain}}, ia); err == nil {
 		// instance account exists, get the header for the account if it exists
 		attachment := &gtsmodel.MediaAttachment{}
-		if err := c.db.GetHeaderForAccountID(attachment, ia.ID); err == nil {
+		if err := c.db.GetHeaderForAccountID(ctx, attachment, ia.ID); err == nil {
 			// header exists, set it on the api model
 			mi.Thumbnail = attachment.URL
 		}
@@ -593,8 +687,8 @@ func (c *converter) InstanceToMasto(i *gtsmodel.Instance) (*model.Instance, erro
 	// contact account is optional but let's try to get it
 	if i.ContactAccountID != "" {
 		ia := &gtsmodel.Account{}
-		if err := c.db.GetByID(i.ContactAccountID, ia); err == nil {
-			ma, err := c.AccountToMastoPublic(ia)
+		if err := c.db.GetByID(ctx, i.ContactAccountID, ia); err == nil {
+			ma, err := c.AccountToMastoPublic(ctx, ia)
 			if err == nil {
 				mi.ContactAccount = ma
 			}
@@ -622,11 +716,11 @@ func (c *converter) RelationshipToMasto(r *gtsmodel.Relationship) (*model.Relati
 	}, nil
 }
 
-func (c *converter) NotificationToMasto(n *gtsmodel.Notification) (*model.Notification, error) {
+func (c *converter) NotificationToMasto(ctx context.Context, n *gtsmodel.Notification) (*model.Notification, error) {
 
 	if n.GTSTargetAccount == nil {
 		tAccount := &gtsmodel.Account{}
-		if err := c.db.GetByID(n.TargetAccountID, tAccount); err != nil {
+		if err := c.db.GetByID(ctx, n.TargetAccountID, tAccount); err != nil {
 			return nil, fmt.Errorf("NotificationToMasto: error getting target account with id %s from the db: %s", n.TargetAccountID, err)
 		}
 		n.GTSTargetAccount = tAccount
@@ -634,12 +728,12 @@ func (c *converter) NotificationToMasto(n *gtsmodel.Notification) (*model.Notifi
 
 	if n.GTSOriginAccount == nil {
 		ogAccount := &gtsmodel.Account{}
-		if err := c.db.GetByID(n.OriginAccountID, ogAccount); err != nil {
+		if err := c.db.GetByID(ctx, n.OriginAccountID, ogAccount); err != nil {
 			return nil, fmt.Errorf("NotificationToMasto: error getting origin account with id %s from the db: %s", n.OriginAccountID, err)
 		}
 		n.GTSOriginAccount = ogAccount
 	}
-	mastoAccount, err := c.AccountToMastoPublic(n.GTSOriginAccount)
+	mastoAccount, err := c.AccountToMastoPublic(ctx, n.GTSOriginAccount)
 	if err != nil {
 		return nil, fmt.Errorf("NotificationToMasto: error converting account to masto: %s", err)
 	}
@@ -648,7 +742,7 @@ func (c *converter) NotificationToMasto(n *gtsmodel.Notification) (*model.Notifi
 	if n.StatusID != "" {
 		if n.GTSStatus == nil {
 			status := &gtsmodel.Status{}
-			if err := c.db.GetByID(n.StatusID, status); err != nil {
+			if err := c.db.GetByID(ctx, n.StatusID, status); err != nil {
 				return nil, fmt.Errorf("NotificationToMasto: error getting status with id %s from the db: %s", n.StatusID, err)
 			}
 			n.GTSStatus = status
@@ -663,7 +757,7 @@ func (c *converter) NotificationToMasto(n *gtsmodel.Notification) (*model.Notifi
 		}
 
 		var err error
-		mastoStatus, err = c.StatusToMasto(n.GTSStatus, nil)
+		mastoStatus, err = c.StatusToMasto(ctx, n.GTSStatus, nil)
 		if err != nil {
 			return nil, fmt.Errorf("NotificationToMasto: error converting status to masto: %s", err)
 		}
@@ -697,3 +791,297 @@ func (c *converter) DomainBlockToMasto(b *gtsmodel.DomainBlock, export bool) (*m
 
 	return domainBlock, nil
 }
+
+func (c *converter) ListToMasto(l *gtsmodel.List) (*model.List, error) {
+	return &model.List{
+		ID:            l.ID,
+		Title:         l.Title,
+		RepliesPolicy: string(l.RepliesPolicy),
+	}, nil
+}
+
+func (c *converter) FilterToMasto(f *gtsmodel.Filter) (*model.Filter, error) {
+	context := []string{}
+	if f.ContextHome {
+		context = append(context, string(gtsmodel.FilterContextHome))
+	}
+	if f.ContextNotifications {
+		context = append(context, string(gtsmodel.FilterContextNotifications))
+	}
+	if f.ContextPublic {
+		context = append(context, string(gtsmodel.FilterContextPublic))
+	}
+	if f.ContextThread {
+		context = append(context, string(gtsmodel.FilterContextThread))
+	}
+
+	var expiresAt string
+	if !f.ExpiresAt.IsZero() {
+		expiresAt = f.ExpiresAt.Format(time.RFC3339)
+	}
+
+	return &model.Filter{
+		ID:           f.ID,
+		Phrase:       f.Phrase,
+		Context:      context,
+		WholeWord:    f.WholeWord,
+		ExpiresAt:    expiresAt,
+		Irreversible: f.Irreversible,
+	}, nil
+}
+
+func (c *converter) PollToMasto(ctx context.Context, p *gtsmodel.Poll, requestingAccount *gtsmodel.Account) (*model.Poll, error) {
+	expired := p.Expired(time.Now())
+
+	var expiresAt string
+	if !p.ExpiresAt.IsZero() {
+		expiresAt = p.ExpiresAt.Format(time.RFC3339)
+	}
+
+	var voted bool
+	ownVotes := []int{}
+	if requestingAccount != nil {
+		votes := []*gtsmodel.PollVote{}
+		if err := c.db.GetWhere(ctx, []db.Where{{Key: "poll_id", Value: p.ID}, {Key: "account_id", Value: requestingAccount.ID}}, &votes); err != nil {
+			if _, ok := err.(db.ErrNoEntries); !ok {
+				return nil, fmt.Errorf("error getting votes of account %s in poll %s: %s", requestingAccount.ID, p.ID, err)
+			}
+		}
+		for _, v := range votes {
+			ownVotes = append(ownVotes, v.Choice)
+		}
+		// the author of a poll can't vote in it, but counts as having voted so that clients show the results
+		voted = len(ownVotes) != 0 || requestingAccount.ID == p.AccountID
+	}
+
+	// tallies are only shown before the poll has closed if the author hasn't hidden them
+	showTotals := !p.HideTotals || expired || (requestingAccount != nil && requestingAccount.ID == p.AccountID)
+
+	var votesCount int
+	options := []model.PollOptions{}
+	for i, title := range p.Options {
+		var count int
+		if i < len(p.Votes) {
+			count = p.Votes[i]
+		}
+		votesCount = votesCount + count
+
+		option := model.PollOptions{
+			Title: title,
+		}
+		if showTotals {
+			option.VotesCount = count
+		}
+		options = append(options, option)
+	}
+
+	var votersCount int
+	if p.Multiple {
+		votersCount = p.VotersCount
+	}
+
+	return &model.Poll{
+		ID:          p.ID,
+		ExpiresAt:   expiresAt,
+		Expired:     expired,
+		Multiple:    p.Multiple,
+		VotesCount:  votesCount,
+		VotersCount: votersCount,
+		Voted:       voted,
+		OwnVotes:    ownVotes,
+		Options:     options,
+		Emojis:      []model.Emoji{},
+	}, nil
+}
+
+func (c *converter) ScheduledStatusToMasto(ctx context.Context, s *gtsmodel.ScheduledStatus) (*model.ScheduledStatus, error) {
+	form := &model.AdvancedStatusCreateForm{}
+	if err := json.Unmarshal([]byte(s.Params), form); err != nil {
+		return nil, fmt.Errorf("error parsing params of scheduled status %s: %s", s.ID, err)
+	}
+
+	scheduledAt := s.ScheduledAt.Format(time.RFC3339)
+
+	mastoAttachments := []model.Attachment{}
+	for _, aID := range s.MediaAttachments {
+		gtsAttachment := &gtsmodel.MediaAttachment{}
+		if err := c.db.GetByID(ctx, aID, gtsAttachment); err != nil {
+			return nil, fmt.Errorf("error getting attachment %s from the db: %s", aID, err)
+		}
+		mastoAttachment, err := c.AttachmentToMasto(ctx, gtsAttachment)
+		if err != nil {
+			return nil, fmt.Errorf("error converting attachment with id %s: %s", aID, err)
+		}
+		mastoAttachments = append(mastoAttachments, mastoAttachment)
+	}
+
+	return &model.ScheduledStatus{
+		ID:          s.ID,
+		ScheduledAt: scheduledAt,
+		Params: &model.StatusParams{
+			Text:          form.Status,
+			InReplyToID:   form.InReplyToID,
+			MediaIDs:      form.MediaIDs,
+			Sensitive:     form.Sensitive,
+			SpoilerText:   form.SpoilerText,
+			Visibility:    string(form.Visibility),
+			ScheduledAt:   scheduledAt,
+			ApplicationID: s.ApplicationID,
+			Poll:          form.Poll,
+			Language:      form.Language,
+		},
+		MediaAttachments: mastoAttachments,
+	}, nil
+}
+
+func (c *converter) ReportToMasto(ctx context.Context, r *gtsmodel.Report) (*model.Report, error) {
+	targetAccount := &gtsmodel.Account{}
+	if err := c.db.GetByID(ctx, r.TargetAccountID, targetAccount); err != nil {
+		return nil, fmt.Errorf("error getting target account %s from the db: %s", r.TargetAccountID, err)
+	}
+
+	mastoTargetAccount, err := c.AccountToMastoPublic(ctx, targetAccount)
+	if err != nil {
+		return nil, fmt.Errorf("error converting target account %s: %s", targetAccount.ID, err)
+	}
+
+	var actionTakenAt *string
+	if !r.ActionTakenAt.IsZero() {
+		t := r.ActionTakenAt.Format(time.RFC3339)
+		actionTakenAt = &t
+	}
+
+	statusIDs := r.StatusIDs
+	if statusIDs == nil {
+		statusIDs = []string{}
+	}
+
+	return &model.Report{
+		ID:            r.ID,
+		ActionTaken:   !r.ActionTakenAt.IsZero(),
+		ActionTakenAt: actionTakenAt,
+		Comment:       r.Comment,
+		Forwarded:     r.Forwarded,
+		CreatedAt:     r.CreatedAt.Format(time.RFC3339),
+		StatusIDs:     statusIDs,
+		TargetAccount: mastoTargetAccount,
+	}, nil
+}
+
+func (c *converter) ReportToAdminMasto(ctx context.Context, r *gtsmodel.Report, requestingAccount *gtsmodel.Account) (*model.AdminReportInfo, error) {
+	account, err := c.reportAccountToMasto(ctx, r.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	targetAccount, err := c.reportAccountToMasto(ctx, r.TargetAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignedAccount, err := c.reportAccountToMasto(ctx, r.AssignedAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	actionTakenByAccount, err := c.reportAccountToMasto(ctx, r.ActionTakenByAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	var actionTakenAt *string
+	if !r.ActionTakenAt.IsZero() {
+		t := r.ActionTakenAt.Format(time.RFC3339)
+		actionTakenAt = &t
+	}
+
+	// statuses may have been deleted since the report was made, in which case they're just left out
+	mastoStatuses := []model.Status{}
+	for _, sID := range r.StatusIDs {
+		s := &gtsmodel.Status{}
+		if err := c.db.GetByID(ctx, sID, s); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				continue
+			}
+			return nil, fmt.Errorf("error getting status %s from the db: %s", sID, err)
+		}
+		mastoStatus, err := c.StatusToMasto(ctx, s, requestingAccount)
+		if err != nil {
+			return nil, fmt.Errorf("error converting status %s: %s", sID, err)
+		}
+		mastoStatuses = append(mastoStatuses, *mastoStatus)
+	}
+
+	return &model.AdminReportInfo{
+		ID:                   r.ID,
+		ActionTaken:          string(r.ActionTaken),
+		ActionTakenAt:        actionTakenAt,
+		Comment:              r.Comment,
+		Forwarded:            r.Forwarded,
+		Note:                 r.Note,
+		CreatedAt:            r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:            r.UpdatedAt.Format(time.RFC3339),
+		Account:              account,
+		TargetAccount:        targetAccount,
+		AssignedAccount:      assignedAccount,
+		ActionTakenByAccount: actionTakenByAccount,
+		Statuses:             mastoStatuses,
+	}, nil
+}
+
+// reportAccountToMasto converts the account with the given ID for inclusion in the admin view of a report.
+// If the ID is empty, or the account no longer exists, nil is returned.
+func (c *converter) reportAccountToMasto(ctx context.Context, accountID string) (*model.Account, error) {
+	if accountID == "" {
+		return nil, nil
+	}
+
+	a := &gtsmodel.Account{}
+	if err := c.db.GetByID(ctx, accountID, a); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting account %s from the db: %s", accountID, err)
+	}
+
+	mastoAccount, err := c.AccountToMastoPublic(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("error converting account %s: %s", accountID, err)
+	}
+	return mastoAccount, nil
+}
+
+func (c *converter) ConversationToMasto(ctx context.Context, conversation *gtsmodel.Conversation, requestingAccount *gtsmodel.Account) (*model.Conversation, error) {
+	// participants may have been deleted since the conversation was last updated, in which case they're just left out
+	mastoAccounts := []model.Account{}
+	for _, aID := range conversation.ParticipantAccountIDs {
+		a := &gtsmodel.Account{}
+		if err := c.db.GetByID(ctx, aID, a); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				continue
+			}
+			return nil, fmt.Errorf("error getting account %s from the db: %s", aID, err)
+		}
+		mastoAccount, err := c.AccountToMastoPublic(ctx, a)
+		if err != nil {
+			return nil, fmt.Errorf("error converting account %s: %s", aID, err)
+		}
+		mastoAccounts = append(mastoAccounts, *mastoAccount)
+	}
+
+	lastStatus := &gtsmodel.Status{}
+	if err := c.db.GetByID(ctx, conversation.LastStatusID, lastStatus); err != nil {
+		return nil, fmt.Errorf("error getting last status %s from the db: %s", conversation.LastStatusID, err)
+	}
+	mastoLastStatus, err := c.StatusToMasto(ctx, lastStatus, requestingAccount)
+	if err != nil {
+		return nil, fmt.Errorf("error converting last status %s: %s", lastStatus.ID, err)
+	}
+
+	return &model.Conversation{
+		ID:         conversation.ID,
+		Accounts:   mastoAccounts,
+		Unread:     !conversation.Read,
+		LastStatus: mastoLastStatus,
+	}, nil
+}
@@ -0,0 +1,95 @@
+/*
+   GoToSocial
+   Copyright (C) 2021 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package typeutils_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/blob"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+// directStorage serves every file straight from the bucket, like s3 storage in public or presigned mode.
+type directStorage struct {
+	blob.Storage
+}
+
+func (s *directStorage) ServeURL(path string) (*url.URL, error) {
+	return url.Parse("https://bucket.example.org/" + path)
+}
+
+type InternalToFrontendTestSuite struct {
+	ConverterStandardTestSuite
+}
+
+func (suite *InternalToFrontendTestSuite) SetupSuite() {
+	suite.config = testrig.NewTestConfig()
+	suite.db = testrig.NewTestDB()
+	suite.log = testrig.NewTestLog()
+	suite.accounts = testrig.NewTestAccounts()
+	suite.typeconverter = typeutils.NewConverter(suite.config, suite.db, &directStorage{Storage: testrig.NewTestStorage()})
+}
+
+func (suite *InternalToFrontendTestSuite) SetupTest() {
+	testrig.StandardDBSetup(suite.db)
+}
+
+func (suite *InternalToFrontendTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+func (suite *InternalToFrontendTestSuite) TestAttachmentServedDirectly() {
+	ctx := context.Background()
+	attachment := testrig.NewTestAttachments()["admin_account_status_1_attachment_1"]
+	attachment.LastAccessedAt = time.Now().Add(-48 * time.Hour)
+	suite.NoError(suite.db.UpdateByID(ctx, attachment.ID, attachment))
+
+	mastoAttachment, err := suite.typeconverter.AttachmentToMasto(ctx, attachment)
+	suite.NoError(err)
+	suite.Equal("https://bucket.example.org/"+attachment.File.Path, mastoAttachment.URL)
+	suite.Equal("https://bucket.example.org/"+attachment.Thumbnail.Path, mastoAttachment.PreviewURL)
+
+	// the fileserver never sees requests for the file, so serving it should count as an access
+	updated := &gtsmodel.MediaAttachment{}
+	suite.NoError(suite.db.GetByID(ctx, attachment.ID, updated))
+	suite.WithinDuration(time.Now(), updated.LastAccessedAt, time.Minute)
+}
+
+func (suite *InternalToFrontendTestSuite) TestUncachedAttachmentServedByFileserver() {
+	ctx := context.Background()
+	attachment := testrig.NewTestAttachments()["admin_account_status_1_attachment_1"]
+	attachment.Cached = false
+	suite.NoError(suite.db.UpdateByID(ctx, attachment.ID, attachment))
+
+	// the file isn't in the bucket anymore, so it has to go through the fileserver to be fetched again
+	mastoAttachment, err := suite.typeconverter.AttachmentToMasto(ctx, attachment)
+	suite.NoError(err)
+	suite.Equal(attachment.URL, mastoAttachment.URL)
+	suite.Equal(attachment.Thumbnail.URL, mastoAttachment.PreviewURL)
+}
+
+func TestInternalToFrontendTestSuite(t *testing.T) {
+	suite.Run(t, new(InternalToFrontendTestSuite))
+}
@@ -1,34 +1,37 @@
 package typeutils
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
 )
 
-func (c *converter) interactionsWithStatusForAccount(s *gtsmodel.Status, requestingAccount *gtsmodel.Account) (*statusInteractions, error) {
+func (c *converter) interactionsWithStatusForAccount(ctx context.Context, s *gtsmodel.Status, requestingAccount *gtsmodel.Account) (*statusInteractions, error) {
 	si := &statusInteractions{}
 
 	if requestingAccount != nil {
-		faved, err := c.db.StatusFavedBy(s, requestingAccount.ID)
+		faved, err := c.db.StatusFavedBy(ctx, s, requestingAccount.ID)
 		if err != nil {
 			return nil, fmt.Errorf("error checking if requesting account has faved status: %s", err)
 		}
 		si.Faved = faved
 
-		reblogged, err := c.db.StatusRebloggedBy(s, requestingAccount.ID)
+		reblogged, err := c.db.StatusRebloggedBy(ctx, s, requestingAccount.ID)
 		if err != nil {
 			return nil, fmt.Errorf("error checking if requesting account has reblogged status: %s", err)
 		}
 		si.Reblogged = reblogged
 
-		muted, err := c.db.StatusMutedBy(s, requestingAccount.ID)
+		muted, err := c.db.StatusMutedBy(ctx, s, requestingAccount.ID)
 		if err != nil {
 			return nil, fmt.Errorf("error checking if requesting account has muted status: %s", err)
 		}
 		si.Muted = muted
 
-		bookmarked, err := c.db.StatusBookmarkedBy(s, requestingAccount.ID)
+		bookmarked, err := c.db.StatusBookmarkedBy(ctx, s, requestingAccount.ID)
 		if err != nil {
 			return nil, fmt.Errorf("error checking if requesting account has bookmarked status: %s", err)
 		}
@@ -44,3 +47,42 @@ type statusInteractions struct {
 	Bookmarked bool
 	Reblogged  bool
 }
+
+// servedURL returns the url that clients should use to fetch the stored file at path.
+// This is the given fileserver url, unless the storage backend serves files to clients directly.
+func (c *converter) servedURL(path string, fileserverURL string) (string, error) {
+	if c.storage == nil || path == "" {
+		return fileserverURL, nil
+	}
+
+	u, err := c.storage.ServeURL(path)
+	if err != nil {
+		return "", fmt.Errorf("error getting url for %s: %s", path, err)
+	}
+	if u == nil {
+		return fileserverURL, nil
+	}
+	return u.String(), nil
+}
+
+// attachmentURL returns the url that clients should use to fetch the stored file at path of the given attachment.
+// Remote media that has been pruned from storage is always served through the fileserver, so that it can be fetched again.
+// When storage serves the file directly, the fileserver never sees the request, so the last accessed time is updated here instead.
+func (c *converter) attachmentURL(ctx context.Context, a *gtsmodel.MediaAttachment, path string, fileserverURL string) (string, error) {
+	if !a.Cached {
+		return fileserverURL, nil
+	}
+
+	u, err := c.servedURL(path, fileserverURL)
+	if err != nil {
+		return "", err
+	}
+
+	if u != fileserverURL && time.Since(a.LastAccessedAt) >= media.LastAccessedInterval {
+		a.LastAccessedAt = time.Now()
+		if err := c.db.UpdateOneByID(ctx, a.ID, "last_accessed_at", a.LastAccessedAt, a); err != nil {
+			return "", fmt.Errorf("error updating last accessed time of attachment %s: %s", a.ID, err)
+		}
+	}
+	return u, nil
+}
@@ -65,3 +65,173 @@ func (c *converter) WrapPersonInUpdate(person vocab.ActivityStreamsPerson, origi
 
 	return update, nil
 }
+
+func (c *converter) WrapQuestionInCreate(question vocab.ActivityStreamsQuestion, originAccount *gtsmodel.Account) (vocab.ActivityStreamsCreate, error) {
+	// a question is an activity in its own right, so it won't be wrapped in a create automatically when it's sent
+	create := streams.NewActivityStreamsCreate()
+
+	// set the actor
+	actorURI, err := url.Parse(originAccount.URI)
+	if err != nil {
+		return nil, fmt.Errorf("WrapQuestionInCreate: error parsing url %s: %s", originAccount.URI, err)
+	}
+	actorProp := streams.NewActivityStreamsActorProperty()
+	actorProp.AppendIRI(actorURI)
+	create.SetActivityStreamsActor(actorProp)
+
+	// set the ID, based on the ID of the question
+	questionIDProp := question.GetJSONLDId()
+	if questionIDProp == nil || !questionIDProp.IsIRI() {
+		return nil, fmt.Errorf("WrapQuestionInCreate: question had no id")
+	}
+	idString := questionIDProp.GetIRI().String() + "/activity"
+	idURI, err := url.Parse(idString)
+	if err != nil {
+		return nil, fmt.Errorf("WrapQuestionInCreate: error parsing url %s: %s", idString, err)
+	}
+	idProp := streams.NewJSONLDIdProperty()
+	idProp.SetIRI(idURI)
+	create.SetJSONLDId(idProp)
+
+	// set the question as the object here
+	objectProp := streams.NewActivityStreamsObjectProperty()
+	objectProp.AppendActivityStreamsQuestion(question)
+	create.SetActivityStreamsObject(objectProp)
+
+	// published, to and cc should be the same as on the question
+	create.SetActivityStreamsPublished(question.GetActivityStreamsPublished())
+	create.SetActivityStreamsTo(question.GetActivityStreamsTo())
+	create.SetActivityStreamsCc(question.GetActivityStreamsCc())
+
+	return create, nil
+}
+
+func (c *converter) WrapQuestionInUpdate(question vocab.ActivityStreamsQuestion, originAccount *gtsmodel.Account) (vocab.ActivityStreamsUpdate, error) {
+	update := streams.NewActivityStreamsUpdate()
+
+	// set the actor
+	actorURI, err := url.Parse(originAccount.URI)
+	if err != nil {
+		return nil, fmt.Errorf("WrapQuestionInUpdate: error parsing url %s: %s", originAccount.URI, err)
+	}
+	actorProp := streams.NewActivityStreamsActorProperty()
+	actorProp.AppendIRI(actorURI)
+	update.SetActivityStreamsActor(actorProp)
+
+	// set the ID
+	newID, err := id.NewRandomULID()
+	if err != nil {
+		return nil, err
+	}
+
+	idString := util.GenerateURIForUpdate(originAccount.Username, c.config.Protocol, c.config.Host, newID)
+	idURI, err := url.Parse(idString)
+	if err != nil {
+		return nil, fmt.Errorf("WrapQuestionInUpdate: error parsing url %s: %s", idString, err)
+	}
+	idProp := streams.NewJSONLDIdProperty()
+	idProp.SetIRI(idURI)
+	update.SetJSONLDId(idProp)
+
+	// set the question as the object here
+	objectProp := streams.NewActivityStreamsObjectProperty()
+	objectProp.AppendActivityStreamsQuestion(question)
+	update.SetActivityStreamsObject(objectProp)
+
+	// to and cc should be the same as on the question
+	update.SetActivityStreamsTo(question.GetActivityStreamsTo())
+	update.SetActivityStreamsCc(question.GetActivityStreamsCc())
+
+	return update, nil
+}
+
+func (c *converter) WrapStatusInAdd(status *gtsmodel.Status, originAccount *gtsmodel.Account) (vocab.ActivityStreamsAdd, error) {
+	add := streams.NewActivityStreamsAdd()
+	if err := c.wrapStatusForFeatured(status, originAccount, add); err != nil {
+		return nil, fmt.Errorf("WrapStatusInAdd: %s", err)
+	}
+	return add, nil
+}
+
+func (c *converter) WrapStatusInRemove(status *gtsmodel.Status, originAccount *gtsmodel.Account) (vocab.ActivityStreamsRemove, error) {
+	remove := streams.NewActivityStreamsRemove()
+	if err := c.wrapStatusForFeatured(status, originAccount, remove); err != nil {
+		return nil, fmt.Errorf("WrapStatusInRemove: %s", err)
+	}
+	return remove, nil
+}
+
+// featuredActivity is satisfied by both Add and Remove, which share all the properties we need to set.
+type featuredActivity interface {
+	SetActivityStreamsActor(vocab.ActivityStreamsActorProperty)
+	SetJSONLDId(vocab.JSONLDIdProperty)
+	SetActivityStreamsObject(vocab.ActivityStreamsObjectProperty)
+	SetActivityStreamsTarget(vocab.ActivityStreamsTargetProperty)
+	SetActivityStreamsTo(vocab.ActivityStreamsToProperty)
+	SetActivityStreamsBcc(vocab.ActivityStreamsBccProperty)
+}
+
+// wrapStatusForFeatured sets the given activity up to add the status to, or remove it from, the featured collection of originAccount.
+func (c *converter) wrapStatusForFeatured(status *gtsmodel.Status, originAccount *gtsmodel.Account, activity featuredActivity) error {
+	// set the actor
+	actorURI, err := url.Parse(originAccount.URI)
+	if err != nil {
+		return fmt.Errorf("error parsing url %s: %s", originAccount.URI, err)
+	}
+	actorProp := streams.NewActivityStreamsActorProperty()
+	actorProp.AppendIRI(actorURI)
+	activity.SetActivityStreamsActor(actorProp)
+
+	// set the ID
+	newID, err := id.NewRandomULID()
+	if err != nil {
+		return err
+	}
+
+	idString := util.GenerateURIForUpdate(originAccount.Username, c.config.Protocol, c.config.Host, newID)
+	idURI, err := url.Parse(idString)
+	if err != nil {
+		return fmt.Errorf("error parsing url %s: %s", idString, err)
+	}
+	idProp := streams.NewJSONLDIdProperty()
+	idProp.SetIRI(idURI)
+	activity.SetJSONLDId(idProp)
+
+	// the object is just a reference to the status
+	statusURI, err := url.Parse(status.URI)
+	if err != nil {
+		return fmt.Errorf("error parsing url %s: %s", status.URI, err)
+	}
+	objectProp := streams.NewActivityStreamsObjectProperty()
+	objectProp.AppendIRI(statusURI)
+	activity.SetActivityStreamsObject(objectProp)
+
+	// the target is the featured collection of the account
+	featuredURI, err := url.Parse(originAccount.FeaturedCollectionURI)
+	if err != nil {
+		return fmt.Errorf("error parsing url %s: %s", originAccount.FeaturedCollectionURI, err)
+	}
+	targetProp := streams.NewActivityStreamsTargetProperty()
+	targetProp.AppendIRI(featuredURI)
+	activity.SetActivityStreamsTarget(targetProp)
+
+	// to should be public
+	toURI, err := url.Parse(asPublicURI)
+	if err != nil {
+		return fmt.Errorf("error parsing url %s: %s", asPublicURI, err)
+	}
+	toProp := streams.NewActivityStreamsToProperty()
+	toProp.AppendIRI(toURI)
+	activity.SetActivityStreamsTo(toProp)
+
+	// bcc followers
+	followersURI, err := url.Parse(originAccount.FollowersURI)
+	if err != nil {
+		return fmt.Errorf("error parsing url %s: %s", originAccount.FollowersURI, err)
+	}
+	bccProp := streams.NewActivityStreamsBccProperty()
+	bccProp.AppendIRI(followersURI)
+	activity.SetActivityStreamsBcc(bccProp)
+
+	return nil
+}
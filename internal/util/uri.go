@@ -52,6 +52,10 @@ const (
 	UpdatePath = "updates"
 	// BlocksPath is used to generate the URI for a block
 	BlocksPath = "blocks"
+	// VotesPath is used to generate the URI for a vote in a poll
+	VotesPath = "votes"
+	// FlagsPath is used to generate the URI for a flag, ie., a report forwarded to another instance
+	FlagsPath = "flags"
 )
 
 // APContextKey is a type used specifically for settings values on contexts within go-fed AP request chains
@@ -132,6 +136,18 @@ func GenerateURIForBlock(username string, protocol string, host string, thisBloc
 	return fmt.Sprintf("%s://%s/%s/%s/%s/%s", protocol, host, UsersPath, username, BlocksPath, thisBlockID)
 }
 
+// GenerateURIForFlag returns the AP URI for a new flag activity -- something like:
+// https://example.org/users/whatever_user/flags/01F7XTH1QGBAPMGF49WJZ91XGC
+func GenerateURIForFlag(username string, protocol string, host string, thisReportID string) string {
+	return fmt.Sprintf("%s://%s/%s/%s/%s/%s", protocol, host, UsersPath, username, FlagsPath, thisReportID)
+}
+
+// GenerateURIForPollVote returns the AP URI for a new vote in a poll -- something like:
+// https://example.org/users/whatever_user#votes/01F7XTH1QGBAPMGF49WJZ91XGC
+func GenerateURIForPollVote(username string, protocol string, host string, thisVoteID string) string {
+	return fmt.Sprintf("%s://%s/%s/%s#%s/%s", protocol, host, UsersPath, username, VotesPath, thisVoteID)
+}
+
 // GenerateURIsForAccount throws together a bunch of URIs for the given username, with the given protocol and host.
 func GenerateURIsForAccount(username string, protocol string, host string) *UserURIs {
 	// The below URLs are used for serving web requests
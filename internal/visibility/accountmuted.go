@@ -0,0 +1,29 @@
+package visibility
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (f *filter) AccountMuted(ctx context.Context, targetAccountID string, requestingAccount *gtsmodel.Account, notifications bool) (bool, error) {
+	if requestingAccount == nil || targetAccountID == "" || targetAccountID == requestingAccount.ID {
+		// only accounts can mute, and they can't mute themselves
+		return false, nil
+	}
+
+	mute, err := f.db.GetMute(ctx, requestingAccount.ID, targetAccountID)
+	if err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return false, nil
+		}
+		return false, fmt.Errorf("AccountMuted: error getting mute of account %s by account %s: %s", targetAccountID, requestingAccount.ID, err)
+	}
+
+	if notifications {
+		return mute.Notifications, nil
+	}
+	return true, nil
+}
@@ -1,6 +1,8 @@
 package visibility
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
@@ -11,17 +13,43 @@ type Filter interface {
 	// StatusVisible returns true if targetStatus is visible to requestingAccount, based on the
 	// privacy settings of the status, and any blocks/mutes that might exist between the two accounts
 	// or account domains, and other relevant accounts mentioned in or replied to by the status.
-	StatusVisible(targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) (bool, error)
+	StatusVisible(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) (bool, error)
 
 	// StatusHometimelineable returns true if targetStatus should be in the home timeline of the requesting account.
 	//
 	// This function will call StatusVisible internally, so it's not necessary to call it beforehand.
-	StatusHometimelineable(targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) (bool, error)
+	StatusHometimelineable(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) (bool, error)
 
 	// StatusPublictimelineable returns true if targetStatus should be in the public timeline of the requesting account.
 	//
 	// This function will call StatusVisible internally, so it's not necessary to call it beforehand.
-	StatusPublictimelineable(targetStatus *gtsmodel.Status, timelineOwnerAccount *gtsmodel.Account) (bool, error)
+	StatusPublictimelineable(ctx context.Context, targetStatus *gtsmodel.Status, timelineOwnerAccount *gtsmodel.Account) (bool, error)
+
+	// StatusListTimelineable returns true if targetStatus should be in the timeline of the given list, which belongs to timelineOwnerAccount.
+	//
+	// This function will call StatusHometimelineable internally, so it's not necessary to call it beforehand.
+	StatusListTimelineable(ctx context.Context, targetStatus *gtsmodel.Status, list *gtsmodel.List, timelineOwnerAccount *gtsmodel.Account) (bool, error)
+
+	// KeywordFilters returns the unexpired filters that requestingAccount has set up for the given filter context.
+	//
+	// Load these once per request or timeline page, and then pass them to StatusKeywordFiltered for each status.
+	KeywordFilters(ctx context.Context, requestingAccount *gtsmodel.Account, filterContext gtsmodel.FilterContext) ([]*gtsmodel.Filter, error)
+
+	// StatusKeywordFiltered returns true if targetStatus matches any of the given filters, as returned by KeywordFilters,
+	// in which case it shouldn't be shown to the account the filters belong to.
+	//
+	// This function doesn't check visibility at all, so StatusVisible or one of the timelineable functions should still be called.
+	StatusKeywordFiltered(ctx context.Context, targetStatus *gtsmodel.Status, filters []*gtsmodel.Filter) (bool, error)
+
+	// StatusMuted returns true if requestingAccount has an unexpired mute of the author of targetStatus,
+	// of the account that targetStatus replies to, or of the author of the status that targetStatus boosts.
+	//
+	// The timelineable functions call this internally, so it's only needed where statuses are shown without them.
+	StatusMuted(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) (bool, error)
+
+	// AccountMuted returns true if requestingAccount has an unexpired mute of the account with targetAccountID.
+	// If notifications is true, only mutes that also cover notifications from the target account are taken into account.
+	AccountMuted(ctx context.Context, targetAccountID string, requestingAccount *gtsmodel.Account, notifications bool) (bool, error)
 }
 
 type filter struct {
@@ -1,13 +1,14 @@
 package visibility
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (f *filter) StatusHometimelineable(targetStatus *gtsmodel.Status, timelineOwnerAccount *gtsmodel.Account) (bool, error) {
+func (f *filter) StatusHometimelineable(ctx context.Context, targetStatus *gtsmodel.Status, timelineOwnerAccount *gtsmodel.Account) (bool, error) {
 	l := f.log.WithFields(logrus.Fields{
 		"func":     "StatusHometimelineable",
 		"statusID": targetStatus.ID,
@@ -18,7 +19,7 @@ func (f *filter) StatusHometimelineable(targetStatus *gtsmodel.Status, timelineO
 		return true, nil
 	}
 
-	v, err := f.StatusVisible(targetStatus, timelineOwnerAccount)
+	v, err := f.StatusVisible(ctx, targetStatus, timelineOwnerAccount)
 	if err != nil {
 		return false, fmt.Errorf("StatusHometimelineable: error checking visibility of status with id %s: %s", targetStatus.ID, err)
 	}
@@ -28,6 +29,16 @@ func (f *filter) StatusHometimelineable(targetStatus *gtsmodel.Status, timelineO
 		return false, nil
 	}
 
+	muted, err := f.StatusMuted(ctx, targetStatus, timelineOwnerAccount)
+	if err != nil {
+		return false, fmt.Errorf("StatusHometimelineable: error checking mutes for status with id %s: %s", targetStatus.ID, err)
+	}
+
+	if muted {
+		l.Debug("status is not hometimelineable because the requester has muted an account involved in it")
+		return false, nil
+	}
+
 	// Don't timeline a status whose parent hasn't been dereferenced yet or can't be dereferenced.
 	// If we have the reply to URI but don't have an ID for the replied-to account or the replied-to status in our database, we haven't dereferenced it yet.
 	if targetStatus.InReplyToURI != "" && (targetStatus.InReplyToID == "" || targetStatus.InReplyToAccountID == "") {
@@ -39,7 +50,7 @@ func (f *filter) StatusHometimelineable(targetStatus *gtsmodel.Status, timelineO
 		// pin the reply to status on to this status if it hasn't been done already
 		if targetStatus.GTSReplyToStatus == nil {
 			rs := &gtsmodel.Status{}
-			if err := f.db.GetByID(targetStatus.InReplyToID, rs); err != nil {
+			if err := f.db.GetByID(ctx, targetStatus.InReplyToID, rs); err != nil {
 				return false, fmt.Errorf("StatusHometimelineable: error getting replied to status with id %s: %s", targetStatus.InReplyToID, err)
 			}
 			targetStatus.GTSReplyToStatus = rs
@@ -48,7 +59,7 @@ func (f *filter) StatusHometimelineable(targetStatus *gtsmodel.Status, timelineO
 		// pin the reply to account on to this status if it hasn't been done already
 		if targetStatus.GTSReplyToAccount == nil {
 			ra := &gtsmodel.Account{}
-			if err := f.db.GetByID(targetStatus.InReplyToAccountID, ra); err != nil {
+			if err := f.db.GetByID(ctx, targetStatus.InReplyToAccountID, ra); err != nil {
 				return false, fmt.Errorf("StatusHometimelineable: error getting replied to account with id %s: %s", targetStatus.InReplyToAccountID, err)
 			}
 			targetStatus.GTSReplyToAccount = ra
@@ -60,7 +71,7 @@ func (f *filter) StatusHometimelineable(targetStatus *gtsmodel.Status, timelineO
 		}
 
 		// the replied-to account != timelineOwnerAccount, so make sure the timelineOwnerAccount follows the replied-to account
-		follows, err := f.db.Follows(timelineOwnerAccount, targetStatus.GTSReplyToAccount)
+		follows, err := f.db.Follows(ctx, timelineOwnerAccount, targetStatus.GTSReplyToAccount)
 		if err != nil {
 			return false, fmt.Errorf("StatusHometimelineable: error checking follow from account %s to account %s: %s", timelineOwnerAccount.ID, targetStatus.InReplyToAccountID, err)
 		}
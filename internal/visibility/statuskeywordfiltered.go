@@ -0,0 +1,112 @@
+package visibility
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+func (f *filter) KeywordFilters(ctx context.Context, requestingAccount *gtsmodel.Account, filterContext gtsmodel.FilterContext) ([]*gtsmodel.Filter, error) {
+	if requestingAccount == nil {
+		// only accounts can have filters
+		return nil, nil
+	}
+
+	filters := []*gtsmodel.Filter{}
+	if err := f.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: requestingAccount.ID}}, &filters); err != nil {
+		if _, ok := err.(db.ErrNoEntries); ok {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("KeywordFilters: error getting filters for account %s: %s", requestingAccount.ID, err)
+	}
+
+	now := time.Now()
+	applicable := []*gtsmodel.Filter{}
+	for _, filter := range filters {
+		if filter.AppliesTo(filterContext, now) {
+			applicable = append(applicable, filter)
+		}
+	}
+
+	return applicable, nil
+}
+
+func (f *filter) StatusKeywordFiltered(ctx context.Context, targetStatus *gtsmodel.Status, filters []*gtsmodel.Filter) (bool, error) {
+	if len(filters) == 0 {
+		return false, nil
+	}
+
+	// a boost has no content of its own, so check the content of the boosted status instead
+	if targetStatus.BoostOfID != "" {
+		if targetStatus.GTSBoostedStatus == nil {
+			boostedStatus := &gtsmodel.Status{}
+			if err := f.db.GetByID(ctx, targetStatus.BoostOfID, boostedStatus); err != nil {
+				return false, fmt.Errorf("StatusKeywordFiltered: error getting boosted status %s: %s", targetStatus.BoostOfID, err)
+			}
+			targetStatus.GTSBoostedStatus = boostedStatus
+		}
+		targetStatus = targetStatus.GTSBoostedStatus
+	}
+
+	text := strings.ToLower(targetStatus.ContentWarning + "\n" + html.UnescapeString(htmlTag.ReplaceAllString(targetStatus.Content, " ")))
+	for _, filter := range filters {
+		if phraseMatches(text, strings.ToLower(filter.Phrase), filter.WholeWord) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// phraseMatches returns true if text contains phrase. Both should already be lowercased.
+//
+// If wholeWord is true, then a match only counts if it isn't part of a longer word, ie., if the phrase
+// starts with a word character then the character before the match mustn't be one, and if the phrase
+// ends with a word character then the character after the match mustn't be one either.
+func phraseMatches(text string, phrase string, wholeWord bool) bool {
+	if phrase == "" {
+		return false
+	}
+
+	if !wholeWord {
+		return strings.Contains(text, phrase)
+	}
+
+	first, _ := utf8.DecodeRuneInString(phrase)
+	last, _ := utf8.DecodeLastRuneInString(phrase)
+
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], phrase)
+		if i == -1 {
+			return false
+		}
+		start := offset + i
+		end := start + len(phrase)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (!isWordRune(first) || start == 0 || !isWordRune(before)) &&
+			(!isWordRune(last) || end == len(text) || !isWordRune(after)) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_'
+}
@@ -0,0 +1,59 @@
+package visibility
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (f *filter) StatusListTimelineable(ctx context.Context, targetStatus *gtsmodel.Status, list *gtsmodel.List, timelineOwnerAccount *gtsmodel.Account) (bool, error) {
+	l := f.log.WithFields(logrus.Fields{
+		"func":     "StatusListTimelineable",
+		"statusID": targetStatus.ID,
+		"listID":   list.ID,
+	})
+
+	// anything that couldn't go in the home timeline of the list owner can't go in one of their lists either
+	timelineable, err := f.StatusHometimelineable(ctx, targetStatus, timelineOwnerAccount)
+	if err != nil {
+		return false, fmt.Errorf("StatusListTimelineable: error checking home timelineability of status with id %s: %s", targetStatus.ID, err)
+	}
+
+	if !timelineable {
+		return false, nil
+	}
+
+	// if it's not a reply, or it's a reply by a list member to themself, there's nothing else to check
+	if targetStatus.InReplyToAccountID == "" || targetStatus.InReplyToAccountID == targetStatus.AccountID {
+		return true, nil
+	}
+
+	switch list.RepliesPolicy {
+	case gtsmodel.ListRepliesPolicyNone:
+		l.Debug("status is not list timelineable because the list doesn't show replies")
+		return false, nil
+	case gtsmodel.ListRepliesPolicyList:
+		// replies to the list owner are fine, as are replies to other members of the list
+		if targetStatus.InReplyToAccountID == list.AccountID {
+			return true, nil
+		}
+
+		entry := &gtsmodel.ListEntry{}
+		if err := f.db.GetWhere(ctx, []db.Where{
+			{Key: "list_id", Value: list.ID},
+			{Key: "account_id", Value: targetStatus.InReplyToAccountID},
+		}, entry); err != nil {
+			if _, ok := err.(db.ErrNoEntries); ok {
+				l.Debug("status is not list timelineable because it replies to an account outside the list")
+				return false, nil
+			}
+			return false, fmt.Errorf("StatusListTimelineable: error checking list membership of account %s: %s", targetStatus.InReplyToAccountID, err)
+		}
+	}
+
+	// the followed policy is already covered by home timelineability
+	return true, nil
+}
@@ -0,0 +1,27 @@
+package visibility
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (f *filter) StatusMuted(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) (bool, error) {
+	if requestingAccount == nil {
+		// only accounts can mute
+		return false, nil
+	}
+
+	for _, accountID := range []string{targetStatus.AccountID, targetStatus.InReplyToAccountID, targetStatus.BoostOfAccountID} {
+		muted, err := f.AccountMuted(ctx, accountID, requestingAccount, false)
+		if err != nil {
+			return false, fmt.Errorf("StatusMuted: error checking mutes for status %s: %s", targetStatus.ID, err)
+		}
+		if muted {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
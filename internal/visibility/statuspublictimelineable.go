@@ -1,13 +1,14 @@
 package visibility
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (f *filter) StatusPublictimelineable(targetStatus *gtsmodel.Status, timelineOwnerAccount *gtsmodel.Account) (bool, error) {
+func (f *filter) StatusPublictimelineable(ctx context.Context, targetStatus *gtsmodel.Status, timelineOwnerAccount *gtsmodel.Account) (bool, error) {
 	l := f.log.WithFields(logrus.Fields{
 		"func":     "StatusPublictimelineable",
 		"statusID": targetStatus.ID,
@@ -23,7 +24,21 @@ func (f *filter) StatusPublictimelineable(targetStatus *gtsmodel.Status, timelin
 		return true, nil
 	}
 
-	v, err := f.StatusVisible(targetStatus, timelineOwnerAccount)
+	// statuses of silenced accounts are kept off the public timeline
+	if targetStatus.GTSAuthorAccount == nil {
+		a := &gtsmodel.Account{}
+		if err := f.db.GetByID(ctx, targetStatus.AccountID, a); err != nil {
+			return false, fmt.Errorf("StatusPublictimelineable: error getting author of status with id %s: %s", targetStatus.ID, err)
+		}
+		targetStatus.GTSAuthorAccount = a
+	}
+
+	if !targetStatus.GTSAuthorAccount.SilencedAt.IsZero() {
+		l.Debug("status is not publicTimelineable because its author has been silenced")
+		return false, nil
+	}
+
+	v, err := f.StatusVisible(ctx, targetStatus, timelineOwnerAccount)
 	if err != nil {
 		return false, fmt.Errorf("StatusPublictimelineable: error checking visibility of status with id %s: %s", targetStatus.ID, err)
 	}
@@ -33,5 +48,15 @@ func (f *filter) StatusPublictimelineable(targetStatus *gtsmodel.Status, timelin
 		return false, nil
 	}
 
+	muted, err := f.StatusMuted(ctx, targetStatus, timelineOwnerAccount)
+	if err != nil {
+		return false, fmt.Errorf("StatusPublictimelineable: error checking mutes for status with id %s: %s", targetStatus.ID, err)
+	}
+
+	if muted {
+		l.Debug("status is not publicTimelineable because the requester has muted an account involved in it")
+		return false, nil
+	}
+
 	return true, nil
 }
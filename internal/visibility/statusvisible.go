@@ -1,6 +1,7 @@
 package visibility
 
 import (
+	"context"
 	"errors"
 
 	"fmt"
@@ -10,19 +11,19 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) (bool, error) {
+func (f *filter) StatusVisible(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) (bool, error) {
 	l := f.log.WithFields(logrus.Fields{
 		"func":     "StatusVisible",
 		"statusID": targetStatus.ID,
 	})
 
-	relevantAccounts, err := f.pullRelevantAccountsFromStatus(targetStatus)
+	relevantAccounts, err := f.pullRelevantAccountsFromStatus(ctx, targetStatus)
 	if err != nil {
 		l.Debugf("error pulling relevant accounts for status %s: %s", targetStatus.ID, err)
 		return false, fmt.Errorf("error pulling relevant accounts for status %s: %s", targetStatus.ID, err)
 	}
 
-	domainBlocked, err := f.domainBlockedRelevant(relevantAccounts)
+	domainBlocked, err := f.domainBlockedRelevant(ctx, relevantAccounts)
 	if err != nil {
 		l.Debugf("error checking domain block: %s", err)
 		return false, fmt.Errorf("error checking domain block: %s", err)
@@ -43,7 +44,7 @@ func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount
 	// note: we only do this for local users
 	if targetAccount.Domain == "" {
 		targetUser := &gtsmodel.User{}
-		if err := f.db.GetWhere([]db.Where{{Key: "account_id", Value: targetAccount.ID}}, targetUser); err != nil {
+		if err := f.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: targetAccount.ID}}, targetUser); err != nil {
 			l.Debug("target user could not be selected")
 			if _, ok := err.(db.ErrNoEntries); ok {
 				return false, nil
@@ -73,7 +74,7 @@ func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount
 	// note: we only do this for local users
 	if requestingAccount.Domain == "" {
 		requestingUser := &gtsmodel.User{}
-		if err := f.db.GetWhere([]db.Where{{Key: "account_id", Value: requestingAccount.ID}}, requestingUser); err != nil {
+		if err := f.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: requestingAccount.ID}}, requestingUser); err != nil {
 			// if the requesting account is local but doesn't have a corresponding user in the db this is a problem
 			l.Debug("requesting user could not be selected")
 			if _, ok := err.(db.ErrNoEntries); ok {
@@ -102,7 +103,7 @@ func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount
 
 	// At this point we have a populated targetAccount, targetStatus, and requestingAccount, so we can check for blocks and whathaveyou
 	// First check if a block exists directly between the target account (which authored the status) and the requesting account.
-	if blocked, err := f.db.Blocked(targetAccount.ID, requestingAccount.ID); err != nil {
+	if blocked, err := f.db.Blocked(ctx, targetAccount.ID, requestingAccount.ID); err != nil {
 		l.Debugf("something went wrong figuring out if the accounts have a block: %s", err)
 		return false, err
 	} else if blocked {
@@ -113,7 +114,7 @@ func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount
 
 	// status replies to account id
 	if relevantAccounts.ReplyToAccount != nil && relevantAccounts.ReplyToAccount.ID != requestingAccount.ID {
-		if blocked, err := f.db.Blocked(relevantAccounts.ReplyToAccount.ID, requestingAccount.ID); err != nil {
+		if blocked, err := f.db.Blocked(ctx, relevantAccounts.ReplyToAccount.ID, requestingAccount.ID); err != nil {
 			return false, err
 		} else if blocked {
 			l.Trace("a block exists between requesting account and reply to account")
@@ -122,7 +123,7 @@ func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount
 
 		// check reply to ID
 		if targetStatus.InReplyToID != "" && (targetStatus.Visibility == gtsmodel.VisibilityFollowersOnly || targetStatus.Visibility == gtsmodel.VisibilityDirect) {
-			followsRepliedAccount, err := f.db.Follows(requestingAccount, relevantAccounts.ReplyToAccount)
+			followsRepliedAccount, err := f.db.Follows(ctx, requestingAccount, relevantAccounts.ReplyToAccount)
 			if err != nil {
 				return false, err
 			}
@@ -135,7 +136,7 @@ func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount
 
 	// status boosts accounts id
 	if relevantAccounts.BoostedStatusAuthor != nil {
-		if blocked, err := f.db.Blocked(relevantAccounts.BoostedStatusAuthor.ID, requestingAccount.ID); err != nil {
+		if blocked, err := f.db.Blocked(ctx, relevantAccounts.BoostedStatusAuthor.ID, requestingAccount.ID); err != nil {
 			return false, err
 		} else if blocked {
 			l.Trace("a block exists between requesting account and boosted account")
@@ -145,7 +146,7 @@ func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount
 
 	// status boosts a reply to account id
 	if relevantAccounts.BoostedReplyToAccount != nil {
-		if blocked, err := f.db.Blocked(relevantAccounts.BoostedReplyToAccount.ID, requestingAccount.ID); err != nil {
+		if blocked, err := f.db.Blocked(ctx, relevantAccounts.BoostedReplyToAccount.ID, requestingAccount.ID); err != nil {
 			return false, err
 		} else if blocked {
 			l.Trace("a block exists between requesting account and boosted reply to account")
@@ -155,7 +156,7 @@ func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount
 
 	// status mentions accounts
 	for _, a := range relevantAccounts.MentionedAccounts {
-		if blocked, err := f.db.Blocked(a.ID, requestingAccount.ID); err != nil {
+		if blocked, err := f.db.Blocked(ctx, a.ID, requestingAccount.ID); err != nil {
 			return false, err
 		} else if blocked {
 			l.Trace("a block exists between requesting account and a mentioned account")
@@ -165,7 +166,7 @@ func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount
 
 	// boost mentions accounts
 	for _, a := range relevantAccounts.BoostedMentionedAccounts {
-		if blocked, err := f.db.Blocked(a.ID, requestingAccount.ID); err != nil {
+		if blocked, err := f.db.Blocked(ctx, a.ID, requestingAccount.ID); err != nil {
 			return false, err
 		} else if blocked {
 			l.Trace("a block exists between requesting account and a boosted mentioned account")
@@ -188,7 +189,7 @@ func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount
 		return true, nil
 	case gtsmodel.VisibilityFollowersOnly:
 		// check one-way follow
-		follows, err := f.db.Follows(requestingAccount, targetAccount)
+		follows, err := f.db.Follows(ctx, requestingAccount, targetAccount)
 		if err != nil {
 			return false, err
 		}
@@ -199,7 +200,7 @@ func (f *filter) StatusVisible(targetStatus *gtsmodel.Status, requestingAccount
 		return true, nil
 	case gtsmodel.VisibilityMutualsOnly:
 		// check mutual follow
-		mutuals, err := f.db.Mutuals(requestingAccount, targetAccount)
+		mutuals, err := f.db.Mutuals(ctx, requestingAccount, targetAccount)
 		if err != nil {
 			return false, err
 		}
@@ -1,13 +1,14 @@
 package visibility
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (f *filter) pullRelevantAccountsFromStatus(targetStatus *gtsmodel.Status) (*relevantAccounts, error) {
+func (f *filter) pullRelevantAccountsFromStatus(ctx context.Context, targetStatus *gtsmodel.Status) (*relevantAccounts, error) {
 	accounts := &relevantAccounts{
 		MentionedAccounts:        []*gtsmodel.Account{},
 		BoostedMentionedAccounts: []*gtsmodel.Account{},
@@ -16,7 +17,7 @@ func (f *filter) pullRelevantAccountsFromStatus(targetStatus *gtsmodel.Status) (
 	// get the author account
 	if targetStatus.GTSAuthorAccount == nil {
 		statusAuthor := &gtsmodel.Account{}
-		if err := f.db.GetByID(targetStatus.AccountID, statusAuthor); err != nil {
+		if err := f.db.GetByID(ctx, targetStatus.AccountID, statusAuthor); err != nil {
 			return accounts, fmt.Errorf("PullRelevantAccountsFromStatus: error getting statusAuthor with id %s: %s", targetStatus.AccountID, err)
 		}
 		targetStatus.GTSAuthorAccount = statusAuthor
@@ -26,7 +27,7 @@ func (f *filter) pullRelevantAccountsFromStatus(targetStatus *gtsmodel.Status) (
 	// get the replied to account from the status and add it to the pile
 	if targetStatus.InReplyToAccountID != "" {
 		repliedToAccount := &gtsmodel.Account{}
-		if err := f.db.GetByID(targetStatus.InReplyToAccountID, repliedToAccount); err != nil {
+		if err := f.db.GetByID(ctx, targetStatus.InReplyToAccountID, repliedToAccount); err != nil {
 			return accounts, fmt.Errorf("PullRelevantAccountsFromStatus: error getting repliedToAcount with id %s: %s", targetStatus.InReplyToAccountID, err)
 		}
 		accounts.ReplyToAccount = repliedToAccount
@@ -36,12 +37,12 @@ func (f *filter) pullRelevantAccountsFromStatus(targetStatus *gtsmodel.Status) (
 	for _, mentionID := range targetStatus.Mentions {
 
 		mention := &gtsmodel.Mention{}
-		if err := f.db.GetByID(mentionID, mention); err != nil {
+		if err := f.db.GetByID(ctx, mentionID, mention); err != nil {
 			return accounts, fmt.Errorf("PullRelevantAccountsFromStatus: error getting mention with id %s: %s", mentionID, err)
 		}
 
 		mentionedAccount := &gtsmodel.Account{}
-		if err := f.db.GetByID(mention.TargetAccountID, mentionedAccount); err != nil {
+		if err := f.db.GetByID(ctx, mention.TargetAccountID, mentionedAccount); err != nil {
 			return accounts, fmt.Errorf("PullRelevantAccountsFromStatus: error getting mentioned account: %s", err)
 		}
 		accounts.MentionedAccounts = append(accounts.MentionedAccounts, mentionedAccount)
@@ -51,11 +52,11 @@ func (f *filter) pullRelevantAccountsFromStatus(targetStatus *gtsmodel.Status) (
 	if targetStatus.BoostOfID != "" {
 		// retrieve the boosted status first
 		boostedStatus := &gtsmodel.Status{}
-		if err := f.db.GetByID(targetStatus.BoostOfID, boostedStatus); err != nil {
+		if err := f.db.GetByID(ctx, targetStatus.BoostOfID, boostedStatus); err != nil {
 			return accounts, fmt.Errorf("PullRelevantAccountsFromStatus: error getting boostedStatus with id %s: %s", targetStatus.BoostOfID, err)
 		}
 		boostedAccount := &gtsmodel.Account{}
-		if err := f.db.GetByID(boostedStatus.AccountID, boostedAccount); err != nil {
+		if err := f.db.GetByID(ctx, boostedStatus.AccountID, boostedAccount); err != nil {
 			return accounts, fmt.Errorf("PullRelevantAccountsFromStatus: error getting boostedAccount with id %s: %s", boostedStatus.AccountID, err)
 		}
 		accounts.BoostedStatusAuthor = boostedAccount
@@ -63,7 +64,7 @@ func (f *filter) pullRelevantAccountsFromStatus(targetStatus *gtsmodel.Status) (
 		// the boosted status might be a reply to another account so we should get that too
 		if boostedStatus.InReplyToAccountID != "" {
 			boostedStatusRepliedToAccount := &gtsmodel.Account{}
-			if err := f.db.GetByID(boostedStatus.InReplyToAccountID, boostedStatusRepliedToAccount); err != nil {
+			if err := f.db.GetByID(ctx, boostedStatus.InReplyToAccountID, boostedStatusRepliedToAccount); err != nil {
 				return accounts, fmt.Errorf("PullRelevantAccountsFromStatus: error getting boostedStatusRepliedToAccount with id %s: %s", boostedStatus.InReplyToAccountID, err)
 			}
 			accounts.BoostedReplyToAccount = boostedStatusRepliedToAccount
@@ -72,12 +73,12 @@ func (f *filter) pullRelevantAccountsFromStatus(targetStatus *gtsmodel.Status) (
 		// now get all accounts with IDs that are mentioned in the status
 		for _, mentionID := range boostedStatus.Mentions {
 			mention := &gtsmodel.Mention{}
-			if err := f.db.GetByID(mentionID, mention); err != nil {
+			if err := f.db.GetByID(ctx, mentionID, mention); err != nil {
 				return accounts, fmt.Errorf("PullRelevantAccountsFromStatus: error getting boosted mention with id %s: %s", mentionID, err)
 			}
 
 			mentionedAccount := &gtsmodel.Account{}
-			if err := f.db.GetByID(mention.TargetAccountID, mentionedAccount); err != nil {
+			if err := f.db.GetByID(ctx, mention.TargetAccountID, mentionedAccount); err != nil {
 				return accounts, fmt.Errorf("PullRelevantAccountsFromStatus: error getting boosted mentioned account: %s", err)
 			}
 			accounts.BoostedMentionedAccounts = append(accounts.BoostedMentionedAccounts, mentionedAccount)
@@ -104,9 +105,9 @@ type relevantAccounts struct {
 }
 
 // blockedDomain checks whether the given domain is blocked by us or not
-func (f *filter) blockedDomain(host string) (bool, error) {
+func (f *filter) blockedDomain(ctx context.Context, host string) (bool, error) {
 	b := &gtsmodel.DomainBlock{}
-	err := f.db.GetWhere([]db.Where{{Key: "domain", Value: host, CaseInsensitive: true}}, b)
+	err := f.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: host, CaseInsensitive: true}}, b)
 	if err == nil {
 		// block exists
 		return true, nil
@@ -126,9 +127,9 @@ func (f *filter) blockedDomain(host string) (bool, error) {
 //
 // Will return true+nil if there's a block, false+nil if there's no block, or
 // an error if something goes wrong.
-func (f *filter) domainBlockedRelevant(r *relevantAccounts) (bool, error) {
+func (f *filter) domainBlockedRelevant(ctx context.Context, r *relevantAccounts) (bool, error) {
 	if r.StatusAuthor != nil {
-		b, err := f.blockedDomain(r.StatusAuthor.Domain)
+		b, err := f.blockedDomain(ctx, r.StatusAuthor.Domain)
 		if err != nil {
 			return false, err
 		}
@@ -138,7 +139,7 @@ func (f *filter) domainBlockedRelevant(r *relevantAccounts) (bool, error) {
 	}
 
 	if r.ReplyToAccount != nil {
-		b, err := f.blockedDomain(r.ReplyToAccount.Domain)
+		b, err := f.blockedDomain(ctx, r.ReplyToAccount.Domain)
 		if err != nil {
 			return false, err
 		}
@@ -148,7 +149,7 @@ func (f *filter) domainBlockedRelevant(r *relevantAccounts) (bool, error) {
 	}
 
 	for _, a := range r.MentionedAccounts {
-		b, err := f.blockedDomain(a.Domain)
+		b, err := f.blockedDomain(ctx, a.Domain)
 		if err != nil {
 			return false, err
 		}
@@ -158,7 +159,7 @@ func (f *filter) domainBlockedRelevant(r *relevantAccounts) (bool, error) {
 	}
 
 	if r.BoostedStatusAuthor != nil {
-		b, err := f.blockedDomain(r.BoostedStatusAuthor.Domain)
+		b, err := f.blockedDomain(ctx, r.BoostedStatusAuthor.Domain)
 		if err != nil {
 			return false, err
 		}
@@ -168,7 +169,7 @@ func (f *filter) domainBlockedRelevant(r *relevantAccounts) (bool, error) {
 	}
 
 	if r.BoostedReplyToAccount != nil {
-		b, err := f.blockedDomain(r.BoostedReplyToAccount.Domain)
+		b, err := f.blockedDomain(ctx, r.BoostedReplyToAccount.Domain)
 		if err != nil {
 			return false, err
 		}
@@ -178,7 +179,7 @@ func (f *filter) domainBlockedRelevant(r *relevantAccounts) (bool, error) {
 	}
 
 	for _, a := range r.BoostedMentionedAccounts {
-		b, err := f.blockedDomain(a.Domain)
+		b, err := f.blockedDomain(ctx, a.Domain)
 		if err != nil {
 			return false, err
 		}
@@ -20,10 +20,11 @@ package testrig
 
 import (
 	"context"
+	"os"
 
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
-	"github.com/superseriousbusiness/gotosocial/internal/db/pg"
+	"github.com/superseriousbusiness/gotosocial/internal/db/dbconn"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
@@ -48,16 +49,41 @@ var testModels []interface{} = []interface{}{
 	&gtsmodel.Instance{},
 	&gtsmodel.Notification{},
 	&gtsmodel.RouterSession{},
+	&gtsmodel.QueuedMessage{},
+	&gtsmodel.Delivery{},
+	&gtsmodel.List{},
+	&gtsmodel.ListEntry{},
+	&gtsmodel.Filter{},
+	&gtsmodel.Poll{},
+	&gtsmodel.PollVote{},
+	&gtsmodel.Mute{},
+	&gtsmodel.ScheduledStatus{},
+	&gtsmodel.Report{},
+	&gtsmodel.Conversation{},
+	&gtsmodel.ConversationStatus{},
+	&gtsmodel.FollowedTag{},
 	&oauth.Token{},
 	&oauth.Client{},
 }
 
-// NewTestDB returns a new initialized, empty database for testing
+// NewTestDB returns a new initialized, empty database for testing.
+//
+// By default this will be a postgres database, but a different database type and address can be
+// set with the GTS_DB_TYPE and GTS_DB_ADDRESS environment variables, eg., GTS_DB_TYPE=sqlite GTS_DB_ADDRESS=:memory:
 func NewTestDB() db.DB {
 	config := NewTestConfig()
+
+	if alternateType := os.Getenv("GTS_DB_TYPE"); alternateType != "" {
+		config.DBConfig.Type = alternateType
+	}
+
+	if alternateAddress := os.Getenv("GTS_DB_ADDRESS"); alternateAddress != "" {
+		config.DBConfig.Address = alternateAddress
+	}
+
 	l := logrus.New()
 	l.SetLevel(logrus.TraceLevel)
-	testDB, err := pg.NewPostgresService(context.Background(), config, l)
+	testDB, err := dbconn.NewService(context.Background(), config, l)
 	if err != nil {
 		panic(err)
 	}
@@ -67,88 +93,106 @@ func NewTestDB() db.DB {
 // StandardDBSetup populates a given db with all the necessary tables/models for perfoming tests.
 func StandardDBSetup(db db.DB) {
 	for _, m := range testModels {
-		if err := db.CreateTable(m); err != nil {
+		if err := db.CreateTable(context.Background(), m); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestTokens() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestClients() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestApplications() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestUsers() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestAccounts() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestAttachments() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestStatuses() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestEmojis() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestTags() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestFaves() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestFollows() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
+			panic(err)
+		}
+	}
+
+	for _, v := range NewTestLists() {
+		if err := db.Put(context.Background(), v); err != nil {
+			panic(err)
+		}
+	}
+
+	for _, v := range NewTestListEntries() {
+		if err := db.Put(context.Background(), v); err != nil {
+			panic(err)
+		}
+	}
+
+	for _, v := range NewTestFilters() {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
 	for _, v := range NewTestNotifications() {
-		if err := db.Put(v); err != nil {
+		if err := db.Put(context.Background(), v); err != nil {
 			panic(err)
 		}
 	}
 
-	if err := db.CreateInstanceAccount(); err != nil {
+	if err := db.CreateInstanceAccount(context.Background()); err != nil {
 		panic(err)
 	}
 
-	if err := db.CreateInstanceInstance(); err != nil {
+	if err := db.CreateInstanceInstance(context.Background()); err != nil {
 		panic(err)
 	}
 }
@@ -156,7 +200,7 @@ func StandardDBSetup(db db.DB) {
 // StandardDBTeardown drops all the standard testing tables/models from the database to ensure it's clean for the next test.
 func StandardDBTeardown(db db.DB) {
 	for _, m := range testModels {
-		if err := db.DropTable(m); err != nil {
+		if err := db.DropTable(context.Background(), m); err != nil {
 			panic(err)
 		}
 	}
@@ -262,8 +262,18 @@ func NewTestUsers() map[string]*gtsmodel.User {
 func NewTestAccounts() map[string]*gtsmodel.Account {
 	accounts := map[string]*gtsmodel.Account{
 		"instance_account": {
-			ID:       "01F8MH261H1KSV3GW3016GZRY3",
-			Username: "localhost:8080",
+			ID:                    "01F8MH261H1KSV3GW3016GZRY3",
+			Username:              "localhost:8080",
+			DisplayName:           "localhost:8080",
+			URI:                   "http://localhost:8080/users/localhost:8080",
+			URL:                   "http://localhost:8080/@localhost:8080",
+			InboxURI:              "http://localhost:8080/users/localhost:8080/inbox",
+			OutboxURI:             "http://localhost:8080/users/localhost:8080/outbox",
+			FollowersURI:          "http://localhost:8080/users/localhost:8080/followers",
+			FollowingURI:          "http://localhost:8080/users/localhost:8080/following",
+			FeaturedCollectionURI: "http://localhost:8080/users/localhost:8080/collections/featured",
+			ActorType:             gtsmodel.ActivityStreamsPerson,
+			PublicKeyURI:          "http://localhost:8080/users/localhost:8080#main-key",
 		},
 		"unconfirmed_account": {
 			ID:                      "01F8MH0BBE4FHXPH513MBVFHB0",
@@ -516,8 +526,10 @@ func NewTestAttachments() map[string]*gtsmodel.MediaAttachment {
 				URL:         "http://localhost:8080/fileserver/01F8MH17FWEB39HZJ76B6VXSKF/attachment/small/01F8MH6NEM8D7527KZAECTCR76.jpeg",
 				RemoteURL:   "",
 			},
-			Avatar: false,
-			Header: false,
+			Avatar:         false,
+			Header:         false,
+			Cached:         true,
+			LastAccessedAt: time.Now(),
 		},
 		"local_account_1_status_4_attachment_1": {
 			ID:        "01F8MH7TDVANYKWVE8VVKFPJTJ",
@@ -564,8 +576,10 @@ func NewTestAttachments() map[string]*gtsmodel.MediaAttachment {
 				URL:         "http://localhost:8080/fileserver/01F8MH1H7YV1Z7D2C8K2730QBF/attachment/small/01F8MH7TDVANYKWVE8VVKFPJTJ.jpeg",
 				RemoteURL:   "",
 			},
-			Avatar: false,
-			Header: false,
+			Avatar:         false,
+			Header:         false,
+			Cached:         true,
+			LastAccessedAt: time.Now(),
 		},
 		"local_account_1_unattached_1": {
 			ID:        "01F8MH8RMYQ6MSNY3JM2XT1CQ5",
@@ -612,8 +626,10 @@ func NewTestAttachments() map[string]*gtsmodel.MediaAttachment {
 				URL:         "http://localhost:8080/fileserver/01F8MH1H7YV1Z7D2C8K2730QBF/attachment/small/01F8MH8RMYQ6MSNY3JM2XT1CQ5.jpeg",
 				RemoteURL:   "",
 			},
-			Avatar: false,
-			Header: false,
+			Avatar:         false,
+			Header:         false,
+			Cached:         true,
+			LastAccessedAt: time.Now(),
 		},
 		"local_account_1_avatar": {
 			ID:        "01F8MH58A357CV5K7R7TJMSH6S",
@@ -660,8 +676,10 @@ func NewTestAttachments() map[string]*gtsmodel.MediaAttachment {
 				URL:         "http://localhost:8080/fileserver/01F8MH1H7YV1Z7D2C8K2730QBF/avatar/small/01F8MH58A357CV5K7R7TJMSH6S.jpeg",
 				RemoteURL:   "",
 			},
-			Avatar: true,
-			Header: false,
+			Avatar:         true,
+			Header:         false,
+			Cached:         true,
+			LastAccessedAt: time.Now(),
 		},
 		"local_account_1_header": {
 			ID:        "01PFPMWK2FF0D9WMHEJHR07C3Q",
@@ -708,8 +726,10 @@ func NewTestAttachments() map[string]*gtsmodel.MediaAttachment {
 				URL:         "http://localhost:8080/fileserver/01F8MH1H7YV1Z7D2C8K2730QBF/header/small/01PFPMWK2FF0D9WMHEJHR07C3Q.jpeg",
 				RemoteURL:   "",
 			},
-			Avatar: false,
-			Header: true,
+			Avatar:         false,
+			Header:         true,
+			Cached:         true,
+			LastAccessedAt: time.Now(),
 		},
 	}
 }
@@ -1115,6 +1135,49 @@ func NewTestFollows() map[string]*gtsmodel.Follow {
 	}
 }
 
+// NewTestLists returns a map of gts model lists, keyed by a description of the list.
+func NewTestLists() map[string]*gtsmodel.List {
+	return map[string]*gtsmodel.List{
+		"local_account_1_list_1": {
+			ID:            "01FF3ZEK8TPQ1TG5XWGGYWKF3W",
+			CreatedAt:     time.Now().Add(-45 * time.Minute),
+			UpdatedAt:     time.Now().Add(-45 * time.Minute),
+			Title:         "Cool Ass Posters From This Instance",
+			AccountID:     "01F8MH1H7YV1Z7D2C8K2730QBF",
+			RepliesPolicy: gtsmodel.ListRepliesPolicyFollowed,
+		},
+	}
+}
+
+// NewTestListEntries returns a map of gts model list entries, keyed by a description of the entry.
+func NewTestListEntries() map[string]*gtsmodel.ListEntry {
+	return map[string]*gtsmodel.ListEntry{
+		"local_account_1_list_1_entry_1": {
+			ID:        "01FF3ZGBZDDJGB3KQ9W6GQ5ZQ3",
+			CreatedAt: time.Now().Add(-44 * time.Minute),
+			ListID:    "01FF3ZEK8TPQ1TG5XWGGYWKF3W",
+			AccountID: "01F8MH5NBDF2MV7CTC4Q5128HF",
+			FollowID:  "01F8PYDCE8XE23GRE5DPZJDZDP",
+		},
+	}
+}
+
+// NewTestFilters returns a map of gts model keyword filters, keyed by a description of the filter.
+func NewTestFilters() map[string]*gtsmodel.Filter {
+	return map[string]*gtsmodel.Filter{
+		"local_account_1_filter_1": {
+			ID:            "01FF5M0ZB7K5DSQ6AZF7VTN8BE",
+			CreatedAt:     time.Now().Add(-30 * time.Minute),
+			UpdatedAt:     time.Now().Add(-30 * time.Minute),
+			AccountID:     "01F8MH1H7YV1Z7D2C8K2730QBF",
+			Phrase:        "turtles",
+			ContextHome:   true,
+			ContextPublic: true,
+			WholeWord:     true,
+		},
+	}
+}
+
 // ActivityWithSignature wraps a pub.Activity along with its signature headers, for testing.
 type ActivityWithSignature struct {
 	Activity        pub.Activity